@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"orderstreamrest/internal/config"
 	"orderstreamrest/internal/middleware"
 	"orderstreamrest/internal/routes"
+	"orderstreamrest/internal/rpc"
+	"orderstreamrest/internal/service/jobs"
+	"orderstreamrest/internal/service/notifications"
 	"orderstreamrest/internal/utils"
 	"os"
 
@@ -26,8 +30,7 @@ import (
 // @license.name  Apache 2.0
 // @license.url   http://www.apache.org/licenses/LICENSE-2.0.html
 
-// @host      localhost:8080
-// @BasePath  /
+// @BasePath  /api/v1
 
 // @securityDefinitions.BearerAuth
 // @in header
@@ -50,8 +53,6 @@ func main() {
 		_ = godotenv.Load("******")
 	}
 
-	fmt.Printf("Environment: %s\n", os.Getenv("ENVIRONMENT_APP"))
-
 	// Inicializar configuração
 	cfg, err := config.NewConfig()
 	if err != nil {
@@ -59,10 +60,8 @@ func main() {
 	}
 	defer cfg.CloseAll()
 
-	cfg.Logger.Info(fmt.Sprintf(
-		"Starting VisionData API | execution_id=%s | version=1.0.0",
-		os.Getenv("ENVIRONMENT_APP"),
-	))
+	// Configuração efetiva da instância, sem segredos, para comparar ambientes
+	cfg.LogStartupReport()
 
 	// Setup do servidor
 	engine := middleware.SetupServer(cfg)
@@ -70,6 +69,18 @@ func main() {
 	// Inicializar rotas
 	routes.InitiateRoutes(engine, cfg)
 
+	// Notificar observadores de tickets alterados
+	go notifications.StartTicketWatcher(context.Background(), cfg)
+
+	// Executar as rotinas agendadas (ex.: anonimização de usuários removidos)
+	go jobs.Start(context.Background(), cfg)
+
+	// Workers da fila de tarefas assíncronas (ex.: reindexação, relatórios)
+	go jobs.StartWorkers(context.Background(), cfg)
+
+	// Servidor gRPC para os serviços internos, ao lado do HTTP
+	go rpc.Start(context.Background(), cfg)
+
 	// Iniciar servidor
 	startServer(engine, cfg)
 }
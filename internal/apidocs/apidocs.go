@@ -0,0 +1,79 @@
+// Package apidocs ajusta em tempo de execução a especificação Swagger gerada pelo swag init (pacote docs).
+//
+// O host, o basePath e os esquemas anotados em cmd/api/main.go valem para o ambiente local; em homologação e
+// produção a especificação publicada em /swagger precisa apontar para o endereço público da API, que só é
+// conhecido na implantação.
+package apidocs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/swaggo/swag"
+)
+
+// Settings são os campos da especificação que dependem do ambiente
+type Settings struct {
+	// Host é o host (e porta) público da API; vazio faz o Swagger UI usar o host que serviu a página
+	Host string
+	// BasePath é o prefixo das rotas documentadas
+	BasePath string
+	// Schemes são os esquemas aceitos (http, https); vazio usa o esquema da página
+	Schemes []string
+}
+
+// LoadSettings lê SWAGGER_HOST, SWAGGER_BASE_PATH e SWAGGER_SCHEMES. Sem SWAGGER_BASE_PATH vale
+// defaultBasePath, o prefixo da versão atual da API.
+func LoadSettings(defaultBasePath string) (Settings, error) {
+	settings := Settings{
+		Host:     strings.TrimSpace(os.Getenv("SWAGGER_HOST")),
+		BasePath: strings.TrimSpace(os.Getenv("SWAGGER_BASE_PATH")),
+	}
+
+	// O host não leva esquema nem caminho: https://api.exemplo.com/ vira api.exemplo.com
+	if strings.Contains(settings.Host, "://") {
+		_, settings.Host, _ = strings.Cut(settings.Host, "://")
+	}
+	settings.Host = strings.TrimRight(settings.Host, "/")
+	if strings.Contains(settings.Host, "/") {
+		return Settings{}, fmt.Errorf("SWAGGER_HOST must be a host and optional port, got %q", os.Getenv("SWAGGER_HOST"))
+	}
+
+	if settings.BasePath == "" {
+		settings.BasePath = defaultBasePath
+	}
+	if !strings.HasPrefix(settings.BasePath, "/") {
+		settings.BasePath = "/" + settings.BasePath
+	}
+	if len(settings.BasePath) > 1 {
+		settings.BasePath = strings.TrimRight(settings.BasePath, "/")
+	}
+
+	for _, scheme := range strings.Split(os.Getenv("SWAGGER_SCHEMES"), ",") {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		if scheme == "" {
+			continue
+		}
+		if scheme != "http" && scheme != "https" {
+			return Settings{}, fmt.Errorf("SWAGGER_SCHEMES accepts http and https, got %q", scheme)
+		}
+		settings.Schemes = append(settings.Schemes, scheme)
+	}
+
+	return settings, nil
+}
+
+// Configure aplica as configurações à especificação registrada pelo pacote docs. Retorna false quando a
+// especificação não foi gerada (build sem swag init), caso em que /swagger não tem o que servir.
+func Configure(settings Settings) bool {
+	spec, ok := swag.GetSwagger(swag.Name).(*swag.Spec)
+	if !ok {
+		return false
+	}
+
+	spec.Host = settings.Host
+	spec.BasePath = settings.BasePath
+	spec.Schemes = settings.Schemes
+	return true
+}
@@ -0,0 +1,195 @@
+package apidocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggo/swag"
+)
+
+func TestLoadSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expected      Settings
+		expectedError string
+	}{
+		{
+			name:     "defaults keep the page host and the versioned prefix",
+			expected: Settings{BasePath: "/api/v1"},
+		},
+		{
+			name: "public address of the environment",
+			env: map[string]string{
+				"SWAGGER_HOST":      "https://api.visiondata.com.br/",
+				"SWAGGER_BASE_PATH": "gateway/api/v1/",
+				"SWAGGER_SCHEMES":   "HTTPS, http",
+			},
+			expected: Settings{Host: "api.visiondata.com.br", BasePath: "/gateway/api/v1", Schemes: []string{"https", "http"}},
+		},
+		{
+			name:     "root base path",
+			env:      map[string]string{"SWAGGER_HOST": "localhost:8080", "SWAGGER_BASE_PATH": "/"},
+			expected: Settings{Host: "localhost:8080", BasePath: "/"},
+		},
+		{
+			name:          "host with a path",
+			env:           map[string]string{"SWAGGER_HOST": "api.visiondata.com.br/v1"},
+			expectedError: `SWAGGER_HOST must be a host and optional port, got "api.visiondata.com.br/v1"`,
+		},
+		{
+			name:          "unknown scheme",
+			env:           map[string]string{"SWAGGER_SCHEMES": "https,ws"},
+			expectedError: `SWAGGER_SCHEMES accepts http and https, got "ws"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"SWAGGER_HOST", "SWAGGER_BASE_PATH", "SWAGGER_SCHEMES"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			settings, err := LoadSettings("/api/v1")
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, settings)
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	assert.False(t, Configure(Settings{Host: "api.visiondata.com.br"}), "no spec is registered without swag init")
+
+	spec := &swag.Spec{
+		Host:             "localhost:8080",
+		BasePath:         "/",
+		InfoInstanceName: swag.Name,
+		SwaggerTemplate:  `{"swagger": "2.0", "host": "{{.Host}}", "basePath": "{{.BasePath}}", "schemes": {{ marshal .Schemes }}}`,
+		LeftDelim:        "{{",
+		RightDelim:       "}}",
+	}
+	swag.Register(swag.Name, spec)
+
+	require.True(t, Configure(Settings{Host: "api.visiondata.com.br", BasePath: "/api/v1", Schemes: []string{"https"}}))
+
+	doc, err := swag.ReadDoc()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"swagger": "2.0", "host": "api.visiondata.com.br", "basePath": "/api/v1", "schemes": ["https"]}`, doc)
+}
+
+// schema é o trecho da especificação usado pelas verificações das anotações
+type schema struct {
+	Ref        string            `json:"$ref"`
+	Type       interface{}       `json:"type"`
+	Example    interface{}       `json:"example"`
+	Properties map[string]schema `json:"properties"`
+	AllOf      []schema          `json:"allOf"`
+	Items      *schema           `json:"items"`
+}
+
+type operation struct {
+	Summary    string   `json:"summary"`
+	Tags       []string `json:"tags"`
+	Parameters []struct {
+		Name   string  `json:"name"`
+		In     string  `json:"in"`
+		Schema *schema `json:"schema"`
+	} `json:"parameters"`
+	Responses map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"responses"`
+}
+
+// TestSpecAnnotations gera a especificação a partir das anotações, como o swag init da CI, e verifica que
+// cada rota documenta o corpo e a resposta com tipos e que os DTOs trazem exemplos
+func TestSpecAnnotations(t *testing.T) {
+	parser := swag.New(swag.SetDebugger(silentLogger{}))
+	parser.ParseInternal = true
+	require.NoError(t, parser.ParseAPI("../..", "cmd/api/main.go", 100), "swag init would fail on these annotations")
+
+	raw, err := json.Marshal(parser.GetSwagger())
+	require.NoError(t, err)
+
+	var doc struct {
+		BasePath    string                                `json:"basePath"`
+		Paths       map[string]map[string]json.RawMessage `json:"paths"`
+		Definitions map[string]schema                     `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &doc))
+	assert.Equal(t, "/api/v1", doc.BasePath)
+	require.NotEmpty(t, doc.Paths)
+
+	var problems []string
+	for path, item := range doc.Paths {
+		for method, data := range item {
+			if method == "parameters" {
+				continue
+			}
+			var op operation
+			require.NoError(t, json.Unmarshal(data, &op))
+			route := strings.ToUpper(method) + " " + path
+
+			if op.Summary == "" || len(op.Tags) == 0 {
+				problems = append(problems, route+": missing @Summary or @Tags")
+			}
+			for _, param := range op.Parameters {
+				if param.In == "body" && (param.Schema == nil || untyped(*param.Schema)) {
+					problems = append(problems, fmt.Sprintf("%s: body parameter %s has no type", route, param.Name))
+				}
+			}
+
+			success := false
+			for code, response := range op.Responses {
+				if !strings.HasPrefix(code, "2") {
+					continue
+				}
+				success = true
+				// Respostas paginadas sempre têm dados: o envelope precisa de {data=...}
+				if response.Schema != nil && response.Schema.Ref == "#/definitions/dto.PaginatedResponse" {
+					problems = append(problems, route+": paginated response without a typed data field")
+				}
+			}
+			if !success {
+				problems = append(problems, route+": missing @Success")
+			}
+		}
+	}
+
+	for name, definition := range doc.Definitions {
+		for property, field := range definition.Properties {
+			if primitive(field) && field.Example == nil {
+				problems = append(problems, fmt.Sprintf("%s.%s: missing example", name, property))
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	assert.Empty(t, problems)
+}
+
+// untyped indica um schema sem tipo nem referência, gerado para interface{}
+func untyped(s schema) bool {
+	return s.Ref == "" && s.Type == nil && len(s.AllOf) == 0
+}
+
+// primitive indica os campos de texto, número e booleano, que devem ter exemplo
+func primitive(s schema) bool {
+	switch s.Type {
+	case "string", "integer", "number", "boolean":
+		return s.Ref == ""
+	}
+	return false
+}
+
+type silentLogger struct{}
+
+func (silentLogger) Printf(string, ...interface{}) {}
@@ -0,0 +1,102 @@
+// Package apperror define os tipos de erro que repositórios e serviços retornam para que a
+// camada HTTP escolha o status sem comparar mensagens
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Tipos de erro. Use errors.Is(err, apperror.ErrNotFound) para testar o tipo de qualquer erro
+// criado por New ou Wrap, inclusive quando embrulhado com fmt.Errorf("...: %w", err).
+var (
+	// ErrNotFound indica que o registro não existe ou está fora do escopo de quem pediu
+	ErrNotFound = errors.New("not found")
+	// ErrConflict indica que o registro já existe ou viola uma restrição de unicidade
+	ErrConflict = errors.New("conflict")
+	// ErrValidation indica que os dados enviados são inválidos
+	ErrValidation = errors.New("validation failed")
+	// ErrForbidden indica que quem pediu não tem acesso ao registro
+	ErrForbidden = errors.New("forbidden")
+	// ErrPreconditionFailed indica que o registro mudou desde a versão informada pelo cliente
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnavailable indica que uma dependência está indisponível e a operação pode ser repetida
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// statusByKind é o status HTTP de cada tipo de erro
+var statusByKind = []struct {
+	kind   error
+	status int
+}{
+	{ErrNotFound, http.StatusNotFound},
+	{ErrConflict, http.StatusConflict},
+	{ErrValidation, http.StatusBadRequest},
+	{ErrForbidden, http.StatusForbidden},
+	{ErrPreconditionFailed, http.StatusPreconditionFailed},
+	{ErrUnavailable, http.StatusServiceUnavailable},
+}
+
+// Error é um erro de um tipo conhecido, com a mensagem exibida ao cliente e a causa opcional
+type Error struct {
+	Kind    error
+	Message string
+	Cause   error
+	// RetryAfter é a espera sugerida ao cliente antes de repetir; zero quando não se aplica
+	RetryAfter time.Duration
+}
+
+// New cria um erro do tipo informado. Declarado como variável de pacote, serve de sentinela
+// específica (ex.: ErrUserNotFound) sem perder o tipo genérico.
+func New(kind error, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap cria um erro do tipo informado preservando a causa original
+func Wrap(kind error, message string, cause error) *Error {
+	return &Error{Kind: kind, Message: message, Cause: cause}
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap expõe o tipo e a causa para errors.Is e errors.As
+func (e *Error) Unwrap() []error {
+	if e.Cause != nil {
+		return []error{e.Kind, e.Cause}
+	}
+	return []error{e.Kind}
+}
+
+// HTTPStatus retorna o status HTTP do erro; erros sem tipo conhecido são 500
+func HTTPStatus(err error) int {
+	for _, entry := range statusByKind {
+		if errors.Is(err, entry.kind) {
+			return entry.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// Message retorna a mensagem do primeiro Error da cadeia, ou fallback quando não há
+func Message(err error, fallback string) string {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return fallback
+}
+
+// RetryAfter retorna a espera sugerida pelo primeiro Error da cadeia, ou zero quando não há
+func RetryAfter(err error) time.Duration {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.RetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,38 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	errUserNotFound := New(ErrNotFound, "user not found")
+	cause := errors.New("duplicate key")
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{name: "Sentinel", err: errUserNotFound, status: http.StatusNotFound, message: "user not found"},
+		{name: "Wrapped sentinel", err: fmt.Errorf("loading user: %w", errUserNotFound), status: http.StatusNotFound, message: "user not found"},
+		{name: "Wrap keeps cause", err: Wrap(ErrConflict, "email already in use", cause), status: http.StatusConflict, message: "email already in use"},
+		{name: "Validation", err: New(ErrValidation, "invalid email"), status: http.StatusBadRequest, message: "invalid email"},
+		{name: "Untyped error", err: errors.New("connection reset"), status: http.StatusInternalServerError, message: "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, HTTPStatus(tt.err))
+			assert.Equal(t, tt.message, Message(tt.err, "fallback"))
+		})
+	}
+
+	assert.ErrorIs(t, fmt.Errorf("x: %w", errUserNotFound), errUserNotFound)
+	assert.ErrorIs(t, Wrap(ErrConflict, "email already in use", cause), cause)
+}
@@ -3,10 +3,16 @@ package config
 import (
 	"context"
 	"errors"
+	"fmt"
+	"orderstreamrest/internal/repositories/cache"
 	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/mongo"
 	"orderstreamrest/internal/repositories/redis"
 	"orderstreamrest/internal/repositories/sqlserver"
+	"orderstreamrest/internal/telemetry"
 	"orderstreamrest/pkg/logger"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,14 +24,37 @@ type App struct {
 	ES        *elsearch.Client
 	Logger    *logger.ElasticsearchLogger
 	SqlServer *sqlserver.Internal
+	Metrics   *cache.MetricsRepository
+	// Mongo arquiva o payload bruto dos eventos de tickets; nil quando MONGO_URI não está configurado
+	Mongo *mongo.MongoInternal
+	// Readiness acompanha as etapas de inicialização exigidas por /healthcheck/ready
+	Readiness *Readiness
+	// ExecutionID identifica a execução da instância nos logs; StartedAt é quando ela subiu
+	ExecutionID string
+	StartedAt   time.Time
+
+	// shutdownTracing descarrega os spans pendentes do exportador OpenTelemetry
+	shutdownTracing func(context.Context) error
 }
 
+// defaultLogRetentionDays é a retenção padrão dos índices diários de logs
+const defaultLogRetentionDays = 30
+
+// startupRetryInterval é o intervalo entre as novas tentativas de uma etapa de inicialização que falhou
+const startupRetryInterval = 15 * time.Second
+
 // NewConfig - a function that returns a new Config struct
 func NewConfig() (*App, error) {
 
 	cfg := new(App)
+	cfg.Readiness = NewReadiness(ReadinessIndices, ReadinessMigrations)
+
+	cfg.ExecutionID = uuid.New().String()[0:5]
+	cfg.StartedAt = time.Now().UTC()
 
-	executionID := uuid.New().String()[0:5]
+	// O provider de tracing precisa existir antes dos clientes, que capturam o provider global ao serem criados
+	shutdownTracing, tracingErr := telemetry.Setup(context.Background(), "datavision-api", AppVersion)
+	cfg.shutdownTracing = shutdownTracing
 
 	err := cfg.newClientRedis()
 	if err != nil {
@@ -37,24 +66,30 @@ func NewConfig() (*App, error) {
 		return cfg, err
 	}
 
-	loggerConfig := logger.Config{
+	// Cria os índices antes do logger, para que o índice de logs não seja criado sem mapping
+	indices, indicesErr := cfg.ensureIndices()
 
-		Service:         "datavision-api",
-		Version:         "1.0.0",
-		Environment:     "homol", // or "development", "staging"
-		IndexName:       "datavision-api-logs",
-		FlushInterval:   5 * time.Second,
-		BatchSize:       1,
-		BufferSize:      1000,
-		LogLevel:        logger.LevelInfo,
-		EnableCaller:    true,
-		EnableBody:      true, // Set to true if you want to log request/response bodies
-		MaxBodySize:     1024,
-		SensitiveFields: []string{"password", "token", "secret"},
-		ExecutionID:     executionID,
+	// Nível, lotes e corpos das requisições seguem o ambiente (ENVIRONMENT_APP) e as variáveis LOG_*
+	cfg.Logger = logger.NewLogger(cfg.ES.ES, loggerConfig(cfg.ExecutionID))
+	cfg.ES.SetQueryLogger(cfg.Logger)
+
+	if tracingErr != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to configure OpenTelemetry tracing: %v", tracingErr))
 	}
 
-	cfg.Logger = logger.NewLogger(cfg.ES.ES, loggerConfig)
+	if indicesErr != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to bootstrap Elasticsearch indices: %v", indicesErr))
+		go cfg.retryStartupStep(ReadinessIndices, func() error {
+			indices, err := cfg.ensureIndices()
+			if err == nil {
+				cfg.logIndices(indices)
+			}
+			return err
+		})
+	} else {
+		cfg.logIndices(indices)
+		cfg.Readiness.Done(ReadinessIndices)
+	}
 
 	sqlServer, err := sqlserver.NewSQLServerInternal()
 	if err != nil {
@@ -62,10 +97,77 @@ func NewConfig() (*App, error) {
 	}
 
 	cfg.SqlServer = sqlServer
+	cfg.Metrics = cache.NewMetricsRepository(sqlServer, cfg.Redis)
+
+	// O arquivo de eventos é opcional: sem ele os tickets continuam sendo gravados, apenas sem o histórico bruto
+	if mongo.Enabled() {
+		if err := cfg.newClientMongo(); err != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Ticket events archive disabled: %v", err))
+		}
+	}
+
+	if err := cfg.migrate(); err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to run SQL Server migrations: %v", err))
+		go cfg.retryStartupStep(ReadinessMigrations, cfg.migrate)
+	} else {
+		cfg.Readiness.Done(ReadinessMigrations)
+	}
 
 	return cfg, nil
 }
 
+// migrate executa as migrações de dados do SQL Server
+func (cfg *App) migrate() error {
+	// Normaliza tipos de usuário gravados com sinônimos (ex.: SUPPORT -> AGENT)
+	updated, err := cfg.SqlServer.NormalizeUserTypes(context.Background())
+	if err != nil {
+		return fmt.Errorf("normalizing user types: %w", err)
+	}
+	if updated > 0 {
+		cfg.Logger.Info(fmt.Sprintf("Normalized user type of %d users", updated))
+	}
+
+	// As alterações de esquema são aplicadas pelos scripts de migrations/, fora da API
+	hasCompany, err := cfg.SqlServer.HasUserCompany(context.Background())
+	if err != nil {
+		return err
+	}
+	if !hasCompany {
+		cfg.Logger.Warn("dbo.tb_users has no CompanyId column; user companies are not saved until migrations/0003_users_company.sql runs")
+	}
+	return nil
+}
+
+// logIndices registra os índices criados e os que estão com o mapping desatualizado
+func (cfg *App) logIndices(indices []elsearch.IndexStatus) {
+	for _, index := range indices {
+		switch {
+		case index.Created:
+			cfg.Logger.Info(fmt.Sprintf("Created Elasticsearch index %s (mapping version %d)", index.Alias, index.CurrentVersion))
+		case index.CurrentVersion < index.ExpectedVersion:
+			cfg.Logger.Warn(fmt.Sprintf("Elasticsearch index %s is at mapping version %d, expected %d; run POST /admin/elasticsearch/reindex/%s", index.Alias, index.CurrentVersion, index.ExpectedVersion, index.Alias))
+		}
+	}
+}
+
+// retryStartupStep repete uma etapa de inicialização que falhou até ela concluir, mantendo a
+// instância fora do balanceamento (/healthcheck/ready) enquanto isso
+func (cfg *App) retryStartupStep(step string, run func() error) {
+	ticker := time.NewTicker(startupRetryInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := run(); err != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Startup step %s failed again: %v", step, err))
+			continue
+		}
+
+		cfg.Logger.Info(fmt.Sprintf("Startup step %s completed", step))
+		cfg.Readiness.Done(step)
+		return
+	}
+}
+
 // CloseAll - a function that closes all connections
 func (cfg *App) CloseAll() {
 	if cfg.Redis != nil {
@@ -76,10 +178,20 @@ func (cfg *App) CloseAll() {
 		_ = cfg.ES.ES.Indices.Flush.WithContext(context.Background())
 	}
 
+	if cfg.Mongo != nil {
+		_ = cfg.Mongo.Close(context.Background())
+	}
+
 	if cfg.Logger != nil {
 		_ = cfg.Logger.Close()
 	}
 
+	if cfg.shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = cfg.shutdownTracing(ctx)
+	}
+
 }
 
 // newClientRedis is a function that returns a new Redis client
@@ -95,6 +207,17 @@ func (cfg *App) newClientRedis() error {
 	return nil
 }
 
+// newClientMongo is a function that returns a new MongoDB client
+func (cfg *App) newClientMongo() error {
+	m, err := mongo.NewMongoInternal()
+	if err != nil {
+		return errors.New("creating mongo client: " + err.Error())
+	}
+
+	cfg.Mongo = m
+	return nil
+}
+
 func (cfg *App) newClientES() error {
 	es, err := elsearch.NewClient(&elsearch.Config{
 		MaxRetries:         3,
@@ -102,7 +225,7 @@ func (cfg *App) newClientES() error {
 		Timeout:            5 * time.Second,
 		EnableLogging:      true,
 		InsecureSkipVerify: true,
-		IndexName:          "support_tickets",
+		IndexName:          elsearch.TicketsIndex,
 	})
 	if err != nil {
 		return errors.New("creating elastic client: " + err.Error())
@@ -111,3 +234,47 @@ func (cfg *App) newClientES() error {
 	cfg.ES = es
 	return nil
 }
+
+// ensureIndices cria os índices gerenciados do Elasticsearch, exceto quando
+// ELASTICSEARCH_BOOTSTRAP_INDICES=false
+func (cfg *App) ensureIndices() ([]elsearch.IndexStatus, error) {
+	if os.Getenv("ELASTICSEARCH_BOOTSTRAP_INDICES") == "false" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indices, err := cfg.ES.EnsureIndices(ctx)
+	if err != nil {
+		return indices, err
+	}
+
+	if dailyLogIndex() {
+		if err := cfg.ES.EnsureLogsLifecycle(ctx, logRetentionDays()); err != nil {
+			return indices, err
+		}
+	}
+
+	return indices, nil
+}
+
+// dailyLogIndex indica se os logs são gravados em um índice por dia (LOG_INDEX_ROTATION, padrão daily)
+func dailyLogIndex() bool {
+	return os.Getenv("LOG_INDEX_ROTATION") != "none"
+}
+
+// logRetentionDays é a retenção dos índices diários de logs (LOG_RETENTION_DAYS, padrão 30);
+// 0 mantém os índices indefinidamente
+func logRetentionDays() int {
+	value := os.Getenv("LOG_RETENTION_DAYS")
+	if value == "" {
+		return defaultLogRetentionDays
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return defaultLogRetentionDays
+	}
+	return days
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"log"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/pkg/logger"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultLogEnvironment é o ambiente informado nos logs quando ENVIRONMENT_APP não está definido
+const defaultLogEnvironment = "homol"
+
+// logProfile são os padrões do logger de um ambiente, que as variáveis LOG_* podem sobrescrever
+type logProfile struct {
+	level         logger.LogLevel
+	batchSize     int
+	flushInterval time.Duration
+	enableBody    bool
+}
+
+// logProfiles são os padrões por ambiente. Em produção os logs vão em lotes e sem os corpos das
+// requisições; nos demais ambientes cada entrada é enviada na hora, para aparecer logo no Kibana.
+var logProfiles = map[string]logProfile{
+	"dev":   {level: logger.LevelDebug, batchSize: 1, flushInterval: time.Second, enableBody: true},
+	"homol": {level: logger.LevelInfo, batchSize: 1, flushInterval: 5 * time.Second, enableBody: true},
+	"prod":  {level: logger.LevelInfo, batchSize: 100, flushInterval: 5 * time.Second, enableBody: false},
+}
+
+// logEnvironmentAliases mapeia os outros nomes usados em ENVIRONMENT_APP para os perfis
+var logEnvironmentAliases = map[string]string{
+	"development": "dev",
+	"local":       "dev",
+	"staging":     "homol",
+	"production":  "prod",
+}
+
+// loggerConfig monta a configuração do logger a partir do ambiente (ENVIRONMENT_APP) e das
+// variáveis LOG_LEVEL, LOG_BATCH_SIZE, LOG_FLUSH_INTERVAL_MS, LOG_BUFFER_SIZE, LOG_ENABLE_BODY,
+// LOG_MAX_BODY_SIZE, LOG_OVERFLOW_* e LOG_RETRY_*. Valores inválidos são ignorados com um aviso.
+func loggerConfig(executionID string) logger.Config {
+	environment := strings.ToLower(strings.TrimSpace(os.Getenv("ENVIRONMENT_APP")))
+	if environment == "" {
+		environment = defaultLogEnvironment
+	}
+
+	profileName := environment
+	if alias, ok := logEnvironmentAliases[profileName]; ok {
+		profileName = alias
+	}
+	profile, ok := logProfiles[profileName]
+	if !ok {
+		profile = logProfiles[defaultLogEnvironment]
+	}
+
+	level := profile.level
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		parsed, err := logger.ParseLevel(value)
+		if err != nil {
+			log.Printf("Invalid LOG_LEVEL, using %s: %v", level, err)
+		} else {
+			level = parsed
+		}
+	}
+
+	overflow := logger.OverflowDrop
+	if value := os.Getenv("LOG_OVERFLOW_STRATEGY"); value != "" {
+		parsed, err := logger.ParseOverflowStrategy(value)
+		if err != nil {
+			log.Printf("Invalid LOG_OVERFLOW_STRATEGY, using %s: %v", overflow, err)
+		} else {
+			overflow = parsed
+		}
+	}
+
+	// LOG_RETRY_MAX=0 desliga as novas tentativas; no logger isso é um valor negativo
+	maxRetries := logEnvInt("LOG_RETRY_MAX", 3, 0)
+	if maxRetries == 0 {
+		maxRetries = -1
+	}
+
+	return logger.Config{
+		Service:         "datavision-api",
+		Version:         AppVersion,
+		Environment:     environment,
+		IndexName:       elsearch.LogsIndex,
+		DailyIndex:      dailyLogIndex(),
+		FlushInterval:   time.Duration(logEnvInt("LOG_FLUSH_INTERVAL_MS", int(profile.flushInterval/time.Millisecond), 1)) * time.Millisecond,
+		BatchSize:       logEnvInt("LOG_BATCH_SIZE", profile.batchSize, 1),
+		BufferSize:      logEnvInt("LOG_BUFFER_SIZE", 1000, 1),
+		LogLevel:        level,
+		EnableCaller:    true,
+		EnableBody:      logEnvBool("LOG_ENABLE_BODY", profile.enableBody),
+		MaxBodySize:     logEnvInt("LOG_MAX_BODY_SIZE", 2048, 1),
+		SensitiveFields: []string{"password", "token", "secret"},
+		ExecutionID:     executionID,
+		Overflow:        overflow,
+		OverflowTimeout: time.Duration(logEnvInt("LOG_OVERFLOW_TIMEOUT_MS", 100, 1)) * time.Millisecond,
+		SampleRate:      logEnvInt("LOG_SAMPLE_RATE", 10, 1),
+		MaxRetries:      maxRetries,
+		RetryBackoff:    time.Duration(logEnvInt("LOG_RETRY_BACKOFF_MS", 500, 1)) * time.Millisecond,
+	}
+}
+
+// logEnvInt lê uma variável inteira do logger, que precisa ser ao menos minValue
+func logEnvInt(name string, defaultValue, minValue int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < minValue {
+		log.Printf("Invalid %s %q, using %d", name, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// logEnvBool lê uma variável booleana do logger
+func logEnvBool(name string, defaultValue bool) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using %t", name, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"orderstreamrest/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		environment   string
+		level         logger.LogLevel
+		batchSize     int
+		flushInterval time.Duration
+		enableBody    bool
+	}{
+		{
+			name:          "defaults to homol",
+			environment:   "homol",
+			level:         logger.LevelInfo,
+			batchSize:     1,
+			flushInterval: 5 * time.Second,
+			enableBody:    true,
+		},
+		{
+			name:          "production profile",
+			env:           map[string]string{"ENVIRONMENT_APP": "prod"},
+			environment:   "prod",
+			level:         logger.LevelInfo,
+			batchSize:     100,
+			flushInterval: 5 * time.Second,
+		},
+		{
+			name:          "alias of the development profile",
+			env:           map[string]string{"ENVIRONMENT_APP": "Local"},
+			environment:   "local",
+			level:         logger.LevelDebug,
+			batchSize:     1,
+			flushInterval: time.Second,
+			enableBody:    true,
+		},
+		{
+			name: "variables override the profile",
+			env: map[string]string{
+				"ENVIRONMENT_APP":       "prod",
+				"LOG_LEVEL":             "warning",
+				"LOG_BATCH_SIZE":        "50",
+				"LOG_FLUSH_INTERVAL_MS": "250",
+				"LOG_ENABLE_BODY":       "true",
+			},
+			environment:   "prod",
+			level:         logger.LevelWarn,
+			batchSize:     50,
+			flushInterval: 250 * time.Millisecond,
+			enableBody:    true,
+		},
+		{
+			name: "invalid values keep the profile",
+			env: map[string]string{
+				"ENVIRONMENT_APP": "prod",
+				"LOG_LEVEL":       "verbose",
+				"LOG_BATCH_SIZE":  "0",
+				"LOG_ENABLE_BODY": "sometimes",
+			},
+			environment:   "prod",
+			level:         logger.LevelInfo,
+			batchSize:     100,
+			flushInterval: 5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"ENVIRONMENT_APP", "LOG_LEVEL", "LOG_BATCH_SIZE", "LOG_FLUSH_INTERVAL_MS", "LOG_ENABLE_BODY"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			got := loggerConfig("3f2a1")
+			assert.Equal(t, tt.environment, got.Environment)
+			assert.Equal(t, tt.level, got.LogLevel)
+			assert.Equal(t, tt.batchSize, got.BatchSize)
+			assert.Equal(t, tt.flushInterval, got.FlushInterval)
+			assert.Equal(t, tt.enableBody, got.EnableBody)
+			assert.Equal(t, "3f2a1", got.ExecutionID)
+		})
+	}
+}
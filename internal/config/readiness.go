@@ -0,0 +1,62 @@
+package config
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	// ReadinessIndices é a criação dos índices gerenciados do Elasticsearch
+	ReadinessIndices = "elasticsearch_indices"
+	// ReadinessMigrations são as migrações de dados do SQL Server executadas na inicialização
+	ReadinessMigrations = "sqlserver_migrations"
+)
+
+// Readiness acompanha as etapas de inicialização que precisam terminar antes de a instância receber tráfego
+type Readiness struct {
+	mu    sync.RWMutex
+	steps map[string]bool
+}
+
+// NewReadiness cria o acompanhamento com todas as etapas pendentes
+func NewReadiness(steps ...string) *Readiness {
+	r := &Readiness{steps: make(map[string]bool, len(steps))}
+	for _, step := range steps {
+		r.steps[step] = false
+	}
+	return r
+}
+
+// Done marca a etapa como concluída
+func (r *Readiness) Done(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps[step] = true
+}
+
+// Steps retorna a situação de cada etapa
+func (r *Readiness) Steps() map[string]bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	steps := make(map[string]bool, len(r.steps))
+	for step, done := range r.steps {
+		steps[step] = done
+	}
+	return steps
+}
+
+// Pending retorna as etapas ainda não concluídas, em ordem alfabética
+func (r *Readiness) Pending() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pending := make([]string, 0, len(r.steps))
+	for step, done := range r.steps {
+		if !done {
+			pending = append(pending, step)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
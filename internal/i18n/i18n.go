@@ -0,0 +1,112 @@
+// Package i18n traduz as mensagens das respostas da API para o idioma pedido pelo cliente
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Idiomas suportados, no formato do header Content-Language
+const (
+	English      = "en"
+	PortugueseBR = "pt-BR"
+
+	// Default é usado quando o cliente não pede nenhum idioma suportado
+	Default = English
+)
+
+// ContextKey guarda no contexto do gin o idioma negociado para a requisição
+const ContextKey = "language"
+
+// catalogs são as traduções de cada idioma, indexadas pela mensagem original em inglês
+var catalogs = map[string]map[string]string{
+	PortugueseBR: ptBR,
+}
+
+// Translate retorna a mensagem no idioma lang. Mensagens sem tradução (ex.: montadas com dados da
+// requisição) e o inglês são devolvidos como vieram.
+func Translate(lang, message string) string {
+	if translated, ok := catalogs[lang][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// FromContext retorna o idioma negociado pelo middleware ou, quando ele não rodou, negocia pelo header
+func FromContext(c *gin.Context) string {
+	if value, exists := c.Get(ContextKey); exists {
+		if lang, ok := value.(string); ok {
+			return lang
+		}
+	}
+	if c.Request == nil {
+		return Default
+	}
+	return Negotiate(c.GetHeader("Accept-Language"))
+}
+
+// Negotiate escolhe o idioma suportado de maior peso (q) no header Accept-Language.
+// Qualquer variante do português (pt, pt-PT) usa o catálogo pt-BR.
+func Negotiate(header string) string {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{tag: tag, quality: quality})
+	}
+
+	// Em caso de empate vale a ordem do header
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	for _, item := range candidates {
+		if lang, ok := supported(item.tag); ok {
+			return lang
+		}
+	}
+	return Default
+}
+
+// supported converte a tag do header (ex.: pt-br, en-US, *) no idioma suportado correspondente
+func supported(tag string) (string, bool) {
+	primary, _, _ := strings.Cut(tag, "-")
+	switch primary {
+	case "pt":
+		return PortugueseBR, true
+	case "en":
+		return English, true
+	case "*":
+		return Default, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,164 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "no header", header: "", expected: English},
+		{name: "english", header: "en-US,en;q=0.9", expected: English},
+		{name: "brazilian portuguese", header: "pt-BR,pt;q=0.9,en;q=0.8", expected: PortugueseBR},
+		{name: "other portuguese variants", header: "pt-PT", expected: PortugueseBR},
+		{name: "case insensitive", header: "PT-br", expected: PortugueseBR},
+		{name: "highest quality wins", header: "en;q=0.5, pt-BR;q=0.8", expected: PortugueseBR},
+		{name: "ties keep the header order", header: "en, pt-BR", expected: English},
+		{name: "unsupported languages are skipped", header: "fr-FR, de;q=0.9, pt;q=0.1", expected: PortugueseBR},
+		{name: "only unsupported languages", header: "fr-FR, de", expected: Default},
+		{name: "q=0 refuses the language", header: "pt-BR;q=0, en;q=0.1", expected: English},
+		{name: "wildcard", header: "*", expected: Default},
+		{name: "malformed quality", header: "pt-BR;q=abc, en;q=0.2", expected: English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Negotiate(tt.header))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "Usuário não encontrado", Translate(PortugueseBR, "User not found"))
+	assert.Equal(t, "User not found", Translate(English, "User not found"))
+	assert.Equal(t, "Invalid start date 2025-13-01", Translate(PortugueseBR, "Invalid start date 2025-13-01"))
+	assert.Equal(t, "User not found", Translate("fr", "User not found"))
+}
+
+func TestFromContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept-Language", "pt-BR")
+	assert.Equal(t, PortugueseBR, FromContext(c))
+
+	c.Set(ContextKey, English)
+	assert.Equal(t, English, FromContext(c))
+}
+
+// messageArgs é a posição do argumento com a mensagem da resposta em cada função que a recebe
+var messageArgs = map[string]int{
+	"NewErrorResponse":           3,
+	"NewSuccessResponse":         2,
+	"NewPaginatedResponse":       3,
+	"NewAuthErrorResponse":       1,
+	"NewValidationErrorResponse": 1,
+	"NewRateLimitErrorResponse":  3,
+	"RespondError":               2,
+	"AbortWithRetry":             3,
+	"BindJSON":                   2,
+	"BindQuery":                  2,
+}
+
+// apperrorArgs é a posição da mensagem nos construtores do pacote apperror, que vira a mensagem da resposta
+var apperrorArgs = map[string]int{
+	"New":  1,
+	"Wrap": 1,
+}
+
+// TestCatalogCoversMessages falha quando uma mensagem fixa de um handler, middleware ou repositório
+// não tem tradução em todos os catálogos
+func TestCatalogCoversMessages(t *testing.T) {
+	fset := token.NewFileSet()
+	missing := map[string]map[string]bool{}
+
+	for _, root := range []string{"../service", "../middleware", "../repositories"} {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				position, ok := messagePosition(call)
+				if !ok || position >= len(call.Args) {
+					return true
+				}
+				literal, ok := call.Args[position].(*ast.BasicLit)
+				if !ok || literal.Kind != token.STRING {
+					return true
+				}
+				message, err := strconv.Unquote(literal.Value)
+				if err != nil {
+					return true
+				}
+
+				for lang, catalog := range catalogs {
+					if _, ok := catalog[message]; !ok {
+						if missing[lang] == nil {
+							missing[lang] = map[string]bool{}
+						}
+						missing[lang][message] = true
+					}
+				}
+				return true
+			})
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	for lang, messages := range missing {
+		untranslated := make([]string, 0, len(messages))
+		for message := range messages {
+			untranslated = append(untranslated, message)
+		}
+		sort.Strings(untranslated)
+		assert.Empty(t, untranslated, "messages without a %s translation", lang)
+	}
+}
+
+// messagePosition retorna a posição da mensagem quando call é uma das funções que respondem ao cliente
+func messagePosition(call *ast.CallExpr) (int, bool) {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		position, ok := messageArgs[fn.Name]
+		return position, ok
+	case *ast.SelectorExpr:
+		if pkg, ok := fn.X.(*ast.Ident); ok && pkg.Name == "apperror" {
+			position, ok := apperrorArgs[fn.Sel.Name]
+			return position, ok
+		}
+		position, ok := messageArgs[fn.Sel.Name]
+		return position, ok
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,284 @@
+package i18n
+
+// ptBR traduz as mensagens das respostas para português. As chaves são as mensagens em inglês usadas
+// no código; mensagens novas precisam ser adicionadas aqui (TestCatalogCoversMessages falha sem elas).
+var ptBR = map[string]string{
+	// Status HTTP, usados quando o erro não traz mensagem própria
+	"Bad Request":              "Requisição inválida",
+	"Unauthorized":             "Não autorizado",
+	"Forbidden":                "Acesso negado",
+	"Not Found":                "Não encontrado",
+	"Conflict":                 "Conflito",
+	"Precondition Failed":      "Pré-condição falhou",
+	"Unprocessable Entity":     "Entidade não processável",
+	"Too Many Requests":        "Requisições demais",
+	"Internal Server Error":    "Erro interno do servidor",
+	"Service Unavailable":      "Serviço indisponível",
+	"Gateway Timeout":          "Tempo de resposta do gateway esgotado",
+	"Request Entity Too Large": "Requisição grande demais",
+
+	// Autenticação e limites de requisição
+	"Invalid token": "Token inválido",
+	"Invalid token format. Use: Bearer <token>":                      "Formato do token inválido. Use: Bearer <token>",
+	"Token has been revoked":                                         "O token foi revogado",
+	"User not authenticated":                                         "Usuário não autenticado",
+	"Authenticated user not found":                                   "Usuário autenticado não encontrado",
+	"User does not have permission to access this resource":          "O usuário não tem permissão para acessar este recurso",
+	"Rate limiter unavailable":                                       "Limitador de requisições indisponível",
+	"Rate limit exceeded":                                            "Limite de requisições excedido",
+	"Daily quota exceeded":                                           "Cota diária excedida",
+	"Concurrent request limit exceeded":                              "Limite de requisições simultâneas excedido",
+	"Too many exports in progress, try again later":                  "Há exportações demais em andamento, tente novamente mais tarde",
+	"A request with this Idempotency-Key is still being processed":   "Uma requisição com esta Idempotency-Key ainda está em processamento",
+	"Idempotency-Key must have 1 to 255 printable ASCII characters":  "A Idempotency-Key deve ter de 1 a 255 caracteres ASCII imprimíveis",
+	"Idempotency-Key was already used with a different request body": "A Idempotency-Key já foi usada com outro corpo de requisição",
+	"Invalid If-Match header":                                        "Header If-Match inválido",
+	"Invalid request body":                                           "Corpo da requisição inválido",
+	"Page out of range":                                              "Página fora do intervalo",
+
+	// Login, sessões e autenticação em dois fatores
+	"Login successful":            "Login realizado com sucesso",
+	"Invalid credentials":         "Credenciais inválidas",
+	"User type is not recognized": "O tipo do usuário não é reconhecido",
+	"User account is inactive":    "A conta do usuário está inativa",
+	"User uses Microsoft authentication. Please use Microsoft login":               "O usuário usa autenticação Microsoft. Entre com a conta Microsoft",
+	"Failed to generate authentication token":                                      "Falha ao gerar o token de autenticação",
+	"Two-factor authentication required":                                           "Autenticação em dois fatores obrigatória",
+	"Two-factor authentication enabled":                                            "Autenticação em dois fatores ativada",
+	"Two-factor authentication is already enabled":                                 "A autenticação em dois fatores já está ativada",
+	"Two-factor authentication is only available for password accounts":            "A autenticação em dois fatores só está disponível para contas com senha",
+	"Scan the QR code and confirm with a code to enable two-factor authentication": "Escaneie o QR code e confirme com um código para ativar a autenticação em dois fatores",
+	"Invalid two-factor code":                                                      "Código de dois fatores inválido",
+	"Invalid verification code":                                                    "Código de verificação inválido",
+	"Invalid or expired challenge":                                                 "Desafio inválido ou expirado",
+	"Failed to retrieve challenge":                                                 "Falha ao buscar o desafio",
+	"Failed to check two-factor authentication":                                    "Falha ao verificar a autenticação em dois fatores",
+	"Failed to check two-factor code":                                              "Falha ao verificar o código de dois fatores",
+	"Too many invalid two-factor codes, try again later":                           "Muitos códigos de dois fatores inválidos, tente novamente mais tarde",
+	"Failed to check recovery code":                                                "Falha ao verificar o código de recuperação",
+	"Failed to generate secret":                                                    "Falha ao gerar o segredo",
+	"Failed to generate recovery codes":                                            "Falha ao gerar os códigos de recuperação",
+	"Failed to start two-factor authentication":                                    "Falha ao iniciar a autenticação em dois fatores",
+	"Failed to enable two-factor authentication":                                   "Falha ao ativar a autenticação em dois fatores",
+	"Failed to retrieve two-factor settings":                                       "Falha ao buscar as configurações de dois fatores",
+	"Failed to save two-factor settings":                                           "Falha ao salvar as configurações de dois fatores",
+	"two-factor authentication not configured":                                     "autenticação em dois fatores não configurada",
+	"Sessions retrieved successfully":                                              "Sessões obtidas com sucesso",
+	"Session revoked successfully":                                                 "Sessão revogada com sucesso",
+	"Session not found":                                                            "Sessão não encontrada",
+	"Failed to retrieve session":                                                   "Falha ao buscar a sessão",
+	"Failed to retrieve sessions":                                                  "Falha ao buscar as sessões",
+	"Failed to revoke session":                                                     "Falha ao revogar a sessão",
+
+	// Usuários e perfil
+	"User created successfully":                                     "Usuário criado com sucesso",
+	"User retrieved successfully":                                   "Usuário obtido com sucesso",
+	"User updated successfully":                                     "Usuário atualizado com sucesso",
+	"User deleted successfully":                                     "Usuário removido com sucesso",
+	"User activated successfully":                                   "Usuário ativado com sucesso",
+	"User deactivated successfully":                                 "Usuário desativado com sucesso",
+	"Users retrieved successfully":                                  "Usuários obtidos com sucesso",
+	"Users import finished":                                         "Importação de usuários concluída",
+	"Profile retrieved successfully":                                "Perfil obtido com sucesso",
+	"Profile updated successfully":                                  "Perfil atualizado com sucesso",
+	"Password changed successfully":                                 "Senha alterada com sucesso",
+	"User not found":                                                "Usuário não encontrado",
+	"user not found":                                                "usuário não encontrado",
+	"Invalid user ID":                                               "ID de usuário inválido",
+	"Email already exists":                                          "O email já está cadastrado",
+	"Email already in use":                                          "O email já está em uso",
+	"email already in use":                                          "o email já está em uso",
+	"Current password is incorrect":                                 "A senha atual está incorreta",
+	"currentPassword is required to change the email":               "currentPassword é obrigatório para alterar o email",
+	"Either password or microsoftId must be provided":               "Informe password ou microsoftId",
+	"The email of Microsoft accounts cannot be changed":             "O email de contas Microsoft não pode ser alterado",
+	"User does not have a password (uses Microsoft authentication)": "O usuário não tem senha (usa autenticação Microsoft)",
+	"User cannot deactivate themselves":                             "O usuário não pode desativar a si mesmo",
+	"User cannot delete themselves":                                 "O usuário não pode remover a si mesmo",
+	"No users to import":                                            "Nenhum usuário para importar",
+	"Invalid import file":                                           "Arquivo de importação inválido",
+	"Invalid onlyActive filter":                                     "Filtro onlyActive inválido",
+	"Failed to create user":                                         "Falha ao criar o usuário",
+	"Failed to retrieve user":                                       "Falha ao buscar o usuário",
+	"Failed to retrieve users":                                      "Falha ao buscar os usuários",
+	"Failed to update user":                                         "Falha ao atualizar o usuário",
+	"Failed to update user status":                                  "Falha ao atualizar o status do usuário",
+	"Failed to delete user":                                         "Falha ao remover o usuário",
+	"Failed to update profile":                                      "Falha ao atualizar o perfil",
+	"Failed to update password":                                     "Falha ao atualizar a senha",
+	"Failed to hash password":                                       "Falha ao gerar o hash da senha",
+	"Failed to check password history":                              "Falha ao verificar o histórico de senhas",
+	"Failed to export users":                                        "Falha ao exportar os usuários",
+	"Failed to process linked records":                              "Falha ao processar os registros vinculados",
+	"Dry run completed, no records were changed":                    "Simulação concluída, nenhum registro foi alterado",
+	"Auth logs retrieved successfully":                              "Logs de autenticação obtidos com sucesso",
+	"Failed to retrieve auth logs":                                  "Falha ao buscar os logs de autenticação",
+	"Failed to count auth logs":                                     "Falha ao contar os logs de autenticação",
+
+	// Tickets
+	"Ticket created successfully":                "Ticket criado com sucesso",
+	"Ticket retrieved successfully":              "Ticket obtido com sucesso",
+	"Ticket updated successfully":                "Ticket atualizado com sucesso",
+	"Ticket watched successfully":                "Ticket acompanhado com sucesso",
+	"Ticket unwatched successfully":              "Ticket deixou de ser acompanhado com sucesso",
+	"Tickets retrieved successfully":             "Tickets obtidos com sucesso",
+	"Tickets imported":                           "Tickets importados",
+	"Ticket attachments retrieved successfully":  "Anexos do ticket obtidos com sucesso",
+	"Ticket audit trail retrieved successfully":  "Histórico do ticket obtido com sucesso",
+	"Ticket audit trails retrieved successfully": "Históricos dos tickets obtidos com sucesso",
+	"Ticket events retrieved successfully":       "Eventos do ticket obtidos com sucesso",
+	"Invalid ticket":                             "Ticket inválido",
+	"Invalid tickets payload":                    "Lista de tickets inválida",
+	"Error while fetching ticket":                "Erro ao buscar o ticket",
+	"Error while fetching tickets":               "Erro ao buscar os tickets",
+	"Invalid ticket IDs":                         "IDs de ticket inválidos",
+	"Error while fetching ticket attachments":    "Erro ao buscar os anexos do ticket",
+	"Error while fetching ticket audit trail":    "Erro ao buscar o histórico do ticket",
+	"Error while searching ticket audit trails":  "Erro ao pesquisar os históricos dos tickets",
+	"Error while searching tickets":              "Erro ao pesquisar os tickets",
+	"Error while importing tickets":              "Erro ao importar os tickets",
+	"Error while watching ticket":                "Erro ao acompanhar o ticket",
+	"Error while unwatching ticket":              "Erro ao deixar de acompanhar o ticket",
+	"Failed to export tickets":                   "Falha ao exportar os tickets",
+	"Failed to retrieve ticket events":           "Falha ao buscar os eventos do ticket",
+	"Ticket events archive is not configured":    "O arquivo de eventos de tickets não está configurado",
+	"ticket already exists":                      "o ticket já existe",
+	"ticket not found":                           "ticket não encontrado",
+	"ticket was modified by another request":     "o ticket foi alterado por outra requisição",
+	"ticket is outside of the caller scope":      "o ticket está fora do escopo do usuário",
+	"invalid document version":                   "versão do documento inválida",
+	"invalid search cursor":                      "cursor de busca inválido",
+	"page is beyond the search result window, continue with pagination.next_cursor":                                                     "a página está além da janela de resultados da busca, continue com pagination.next_cursor",
+	"invalid attachment_type, expected a category (image, video, audio, document, archive) or a mime type such as image/png or image/*": "attachment_type inválido, informe uma categoria (image, video, audio, document, archive) ou um mime type como image/png ou image/*",
+
+	// Buscas salvas e sinônimos
+	"Search results retrieved successfully":           "Resultados da busca obtidos com sucesso",
+	"Invalid search query":                            "Busca inválida",
+	"Saved search created successfully":               "Busca salva criada com sucesso",
+	"Saved search updated successfully":               "Busca salva atualizada com sucesso",
+	"Saved search deleted successfully":               "Busca salva removida com sucesso",
+	"Saved search executed successfully":              "Busca salva executada com sucesso",
+	"Saved searches retrieved successfully":           "Buscas salvas obtidas com sucesso",
+	"Invalid saved search ID":                         "ID de busca salva inválido",
+	"Failed to create saved search":                   "Falha ao criar a busca salva",
+	"Failed to retrieve saved search":                 "Falha ao buscar a busca salva",
+	"Failed to retrieve saved searches":               "Falha ao buscar as buscas salvas",
+	"Failed to update saved search":                   "Falha ao atualizar a busca salva",
+	"Failed to delete saved search":                   "Falha ao remover a busca salva",
+	"saved search not found":                          "busca salva não encontrada",
+	"a saved search with this name already exists":    "já existe uma busca salva com este nome",
+	"Synonyms retrieved successfully":                 "Sinônimos obtidos com sucesso",
+	"Synonyms updated successfully":                   "Sinônimos atualizados com sucesso",
+	"The synonyms file is required in the file field": "O arquivo de sinônimos é obrigatório no campo file",
+	"Synonyms file too large":                         "Arquivo de sinônimos grande demais",
+	"Invalid synonyms file":                           "Arquivo de sinônimos inválido",
+	"Failed to read synonyms file":                    "Falha ao ler o arquivo de sinônimos",
+	"Failed to read the active synonyms":              "Falha ao ler os sinônimos ativos",
+	"Failed to update synonyms":                       "Falha ao atualizar os sinônimos",
+	"No synonym set was uploaded":                     "Nenhum conjunto de sinônimos foi enviado",
+	"Tags retrieved successfully":                     "Tags obtidas com sucesso",
+	"Tags merged successfully":                        "Tags unificadas com sucesso",
+	"Failed to list tags":                             "Falha ao listar as tags",
+	"Failed to merge tags":                            "Falha ao unificar as tags",
+	"Invalid tag merge":                               "Unificação de tags inválida",
+	"Quotas retrieved successfully":                   "Cotas obtidas com sucesso",
+	"IP rules retrieved successfully":                 "Regras de IP obtidas com sucesso",
+	"IP rules updated successfully":                   "Regras de IP atualizadas com sucesso",
+	"Failed to retrieve IP rules":                     "Falha ao buscar as regras de IP",
+	"Failed to update IP rules":                       "Falha ao atualizar as regras de IP",
+	"Invalid IP rules":                                "Regras de IP inválidas",
+	"Access denied from this network":                 "Acesso negado a partir desta rede",
+	"Quota updated successfully":                      "Cota atualizada com sucesso",
+	"Failed to retrieve quotas":                       "Falha ao buscar as cotas",
+	"Failed to update quota":                          "Falha ao atualizar a cota",
+	"Invalid quota settings":                          "Ajuste de cota inválido",
+
+	// Métricas
+	"Tickets metrics retrieved successfully":               "Métricas de tickets obtidas com sucesso",
+	"Tickets by month retrieved successfully":              "Tickets por mês obtidos com sucesso",
+	"Tickets by status and month retrieved successfully":   "Tickets por status e mês obtidos com sucesso",
+	"Tickets by priority and month retrieved successfully": "Tickets por prioridade e mês obtidos com sucesso",
+	"Tickets breakdown retrieved successfully":             "Distribuição de tickets obtida com sucesso",
+	"Tickets histogram retrieved successfully":             "Histograma de tickets obtido com sucesso",
+	"Tickets trend retrieved successfully":                 "Tendência de tickets obtida com sucesso",
+	"Top tickets retrieved successfully":                   "Principais tickets obtidos com sucesso",
+	"Mean time by priority retrieved successfully":         "Tempo médio por prioridade obtido com sucesso",
+	"Resolution time percentiles retrieved successfully":   "Percentis do tempo de resolução obtidos com sucesso",
+	"Agents workload retrieved successfully":               "Carga de trabalho dos agentes obtida com sucesso",
+	"Users activity retrieved successfully":                "Atividade dos usuários obtida com sucesso",
+	"Failed to retrieve total tickets":                     "Falha ao buscar o total de tickets",
+	"Failed to retrieve tickets by month":                  "Falha ao buscar os tickets por mês",
+	"Failed to retrieve tickets by status and month":       "Falha ao buscar os tickets por status e mês",
+	"Failed to retrieve tickets by priority and month":     "Falha ao buscar os tickets por prioridade e mês",
+	"Failed to retrieve tickets breakdown":                 "Falha ao buscar a distribuição de tickets",
+	"Failed to stream tickets breakdown":                   "Falha ao enviar a distribuição de tickets",
+	"Failed to retrieve tickets histogram":                 "Falha ao buscar o histograma de tickets",
+	"Failed to retrieve tickets trend":                     "Falha ao buscar a tendência de tickets",
+	"Failed to retrieve top tickets":                       "Falha ao buscar os principais tickets",
+	"Failed to retrieve mean time by priority":             "Falha ao buscar o tempo médio por prioridade",
+	"Failed to retrieve resolution time percentiles":       "Falha ao buscar os percentis do tempo de resolução",
+	"Failed to retrieve agents workload":                   "Falha ao buscar a carga de trabalho dos agentes",
+	"Failed to retrieve users activity":                    "Falha ao buscar a atividade dos usuários",
+	"Invalid metrics filter":                               "Filtro de métricas inválido",
+	"Invalid date filter":                                  "Filtro de data inválido",
+	"Invalid dimension":                                    "Dimensão inválida",
+	"Invalid interval":                                     "Intervalo inválido",
+	"Invalid granularity":                                  "Granularidade inválida",
+	"Invalid window":                                       "Janela inválida",
+	"unknown metrics dimension":                            "dimensão de métricas desconhecida",
+	"unknown trend granularity, expected week or month":    "granularidade de tendência desconhecida, use week ou month",
+
+	// Administração, auditoria, rotinas e alertas
+	"Admin summary retrieved successfully":      "Resumo administrativo obtido com sucesso",
+	"Configuration retrieved successfully":      "Configuração obtida com sucesso",
+	"Deprecated routes retrieved successfully":  "Rotas descontinuadas obtidas com sucesso",
+	"Worker pools retrieved successfully":       "Pools de workers obtidos com sucesso",
+	"Logs retrieved successfully":               "Logs obtidos com sucesso",
+	"Log level retrieved successfully":          "Nível dos logs obtido com sucesso",
+	"Log level updated successfully":            "Nível dos logs alterado com sucesso",
+	"Metrics cache invalidated successfully":    "Cache de métricas invalidado com sucesso",
+	"Metrics cache refreshed successfully":      "Cache de métricas atualizado com sucesso",
+	"Index reindexed successfully":              "Índice reindexado com sucesso",
+	"Index not managed by the application":      "Índice não gerenciado pela aplicação",
+	"index is not managed by the application":   "o índice não é gerenciado pela aplicação",
+	"Audit logs retrieved successfully":         "Trilha de auditoria obtida com sucesso",
+	"Alerts retrieved successfully":             "Alertas obtidos com sucesso",
+	"Notifications retrieved successfully":      "Notificações obtidas com sucesso",
+	"Jobs retrieved successfully":               "Rotinas obtidas com sucesso",
+	"Job retrieved successfully":                "Rotina obtida com sucesso",
+	"Job updated successfully":                  "Rotina atualizada com sucesso",
+	"Job runs retrieved successfully":           "Execuções da rotina obtidas com sucesso",
+	"Job finished successfully":                 "Rotina concluída com sucesso",
+	"Job failed":                                "A rotina falhou",
+	"Job not found":                             "Rotina não encontrada",
+	"Invalid job settings":                      "Configuração da rotina inválida",
+	"Invalid logs filter":                       "Filtro de logs inválido",
+	"Invalid log level":                         "Nível de log inválido",
+	"Invalid audit filter":                      "Filtro de auditoria inválido",
+	"Invalid alert filter":                      "Filtro de alertas inválido",
+	"Invalid export format":                     "Formato de exportação inválido",
+	"Invalid GraphQL request":                   "Requisição GraphQL inválida",
+	"Failed to retrieve job":                    "Falha ao buscar a rotina",
+	"Failed to retrieve jobs":                   "Falha ao buscar as rotinas",
+	"Failed to retrieve job runs":               "Falha ao buscar as execuções da rotina",
+	"Failed to update job":                      "Falha ao atualizar a rotina",
+	"Failed to search logs":                     "Falha ao pesquisar os logs",
+	"Failed to retrieve audit logs":             "Falha ao buscar a trilha de auditoria",
+	"Failed to retrieve alerts":                 "Falha ao buscar os alertas",
+	"Failed to retrieve notifications":          "Falha ao buscar as notificações",
+	"Failed to retrieve deprecated route usage": "Falha ao buscar o uso das rotas descontinuadas",
+	"Failed to invalidate metrics cache":        "Falha ao invalidar o cache de métricas",
+	"Failed to refresh metrics cache":           "Falha ao atualizar o cache de métricas",
+	"Failed to reindex index":                   "Falha ao reindexar o índice",
+	"Failed to export data":                     "Falha ao exportar os dados",
+	"job is already running":                    "a rotina já está em execução",
+	"job queue is unavailable":                  "a fila de rotinas está indisponível",
+	"queued job not found":                      "rotina enfileirada não encontrada",
+	"unknown job type":                          "tipo de rotina desconhecido",
+	"unsupported export format":                 "formato de exportação não suportado",
+
+	// Dependências
+	"elasticsearch is unavailable": "o elasticsearch está indisponível",
+	"elasticsearch request failed": "a requisição ao elasticsearch falhou",
+	"sql server query timed out":   "a consulta ao sql server excedeu o tempo limite",
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"orderstreamrest/internal/config"
+	redisInternal "orderstreamrest/internal/repositories/redis"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// revokedTokensKeyPrefix prefixes the Redis key holding when a user's tokens were revoked
+	revokedTokensKeyPrefix = "auth:revoked:user:"
+	// blacklistTimeout bounds the Redis lookup made on every authenticated request
+	blacklistTimeout = 200 * time.Millisecond
+)
+
+// tokenBlacklist is the Redis client used to revoke tokens; nil disables the check (e.g. in tests)
+var tokenBlacklist *redisInternal.RedisInternal
+
+// setupTokenBlacklist enables the token blacklist checked by Auth
+func setupTokenBlacklist(cfg *config.App) {
+	tokenBlacklist = cfg.Redis
+}
+
+// RevokeUserTokens invalidates every token issued to the user up to now. The revocation
+// only needs to outlive the tokens, so the key expires after the token lifetime.
+func RevokeUserTokens(ctx context.Context, userID int) error {
+	if tokenBlacklist == nil {
+		return nil
+	}
+	if err := tokenBlacklist.Set(ctx, revokedTokensKey(userID), time.Now().Unix(), TokenLifetime()).Err(); err != nil {
+		return err
+	}
+	return revokeUserSessions(ctx, userID)
+}
+
+// RestoreUserTokens lifts the revocation so tokens issued from now on are accepted right away
+func RestoreUserTokens(ctx context.Context, userID int) error {
+	if tokenBlacklist == nil {
+		return nil
+	}
+	return tokenBlacklist.Del(ctx, revokedTokensKey(userID)).Err()
+}
+
+// isTokenRevoked reports whether the token was issued before its user's tokens were revoked.
+// Redis failures let the token through: the token is still signed and expires within its lifetime.
+func isTokenRevoked(ctx context.Context, claims jwt.MapClaims) bool {
+	if tokenBlacklist == nil {
+		return false
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, blacklistTimeout)
+	defer cancel()
+
+	value, err := tokenBlacklist.Get(ctx, revokedTokensKey(int(userID))).Result()
+	if errors.Is(err, redis.Nil) {
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to check token blacklist: %v", err)
+		return false
+	}
+
+	revokedAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	return issuedBefore(claims, revokedAt)
+}
+
+// issuedBefore reports whether the token was issued at or before revokedAt (Unix seconds).
+// Tokens without iat predate the claim and are treated as revoked.
+func issuedBefore(claims jwt.MapClaims, revokedAt int64) bool {
+	issuedAt, ok := claims["iat"].(float64)
+	if !ok {
+		return true
+	}
+	return int64(issuedAt) <= revokedAt
+}
+
+func revokedTokensKey(userID int) string {
+	return revokedTokensKeyPrefix + strconv.Itoa(userID)
+}
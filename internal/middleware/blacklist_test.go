@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"orderstreamrest/internal/utils"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssuedBefore(t *testing.T) {
+	const revokedAt = int64(1_700_000_000)
+
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		expected bool
+	}{
+		{name: "issued before revocation", claims: jwt.MapClaims{"iat": float64(revokedAt - 60)}, expected: true},
+		{name: "issued in the revocation second", claims: jwt.MapClaims{"iat": float64(revokedAt)}, expected: true},
+		{name: "issued after revocation", claims: jwt.MapClaims{"iat": float64(revokedAt + 1)}, expected: false},
+		{name: "token without iat", claims: jwt.MapClaims{"user_id": float64(7)}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, issuedBefore(tt.claims, revokedAt))
+		})
+	}
+}
+
+func TestGenerateJWTSetsIssuedAt(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token, err := GenerateJWT(7, "admin@example.com", utils.RoleAdmin, 0, "")
+	assert.NoError(t, err)
+
+	claims, err := DecodeTokenJWT(token)
+	assert.NoError(t, err)
+	assert.Contains(t, claims, "iat")
+}
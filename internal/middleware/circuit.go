@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker deixa de chamar uma dependência depois de failureThreshold falhas seguidas.
+// Após o cooldown uma única chamada de teste é liberada (meio-aberto): sucesso fecha o circuito,
+// falha o reabre por mais um cooldown.
+type circuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	cooldown         time.Duration
+	failures         int
+	openedAt         time.Time
+	probing          bool
+	now              func() time.Time
+}
+
+// newCircuitBreaker cria um circuito fechado
+func newCircuitBreaker(failureThreshold int, cooldown time.Duration) *circuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+	}
+}
+
+// Allow informa se a dependência pode ser chamada agora
+func (cb *circuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.failures < cb.failureThreshold {
+		return true
+	}
+	if cb.probing || cb.now().Sub(cb.openedAt) < cb.cooldown {
+		return false
+	}
+
+	cb.probing = true
+	return true
+}
+
+// Success registra uma chamada bem-sucedida e fecha o circuito. Retorna true se o circuito estava aberto.
+func (cb *circuitBreaker) Success() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	wasOpen := cb.failures >= cb.failureThreshold
+	cb.failures = 0
+	cb.probing = false
+	return wasOpen
+}
+
+// Failure registra uma chamada com falha e abre o circuito ao atingir o limite.
+// Retorna true quando esta falha abriu o circuito.
+func (cb *circuitBreaker) Failure() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	if cb.failures < cb.failureThreshold {
+		return false
+	}
+
+	opened := cb.failures == cb.failureThreshold
+	cb.openedAt = cb.now()
+	cb.probing = false
+	return opened
+}
+
+// RetryAfter retorna quanto falta para o circuito liberar uma chamada de teste
+func (cb *circuitBreaker) RetryAfter() time.Duration {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.failures < cb.failureThreshold {
+		return 0
+	}
+	if remaining := cb.cooldown - cb.now().Sub(cb.openedAt); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
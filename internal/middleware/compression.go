@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultCompressionMinBytes is the smallest body worth compressing; smaller ones go out as they are
+	defaultCompressionMinBytes = 1024
+
+	// noCompressionKey marks requests whose response must not be compressed (see NoCompression)
+	noCompressionKey = "compression.disabled"
+)
+
+// gzipWriters reuses the compressors, which allocate a few hundred KB each
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		writer, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return writer
+	},
+}
+
+// setupCompression gzips JSON responses unless COMPRESSION_ENABLED=false
+func setupCompression(engine *gin.Engine) {
+	if enabled, err := strconv.ParseBool(os.Getenv("COMPRESSION_ENABLED")); err == nil && !enabled {
+		log.Println("Response compression disabled")
+		return
+	}
+	engine.Use(Compression(int(getEnvAsInt64("COMPRESSION_MIN_BYTES", defaultCompressionMinBytes))))
+}
+
+// NoCompression opts a route out of compression. Used by the streaming exports, whose clients
+// read rows as they are flushed.
+func NoCompression() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(noCompressionKey, true)
+		c.Next()
+	}
+}
+
+// Compression gzips JSON responses of at least minBytes for clients that send Accept-Encoding: gzip.
+// The body is held until minBytes are written, so small responses go out unchanged. Responses
+// that are flushed before that (streams) or that are not JSON are never compressed.
+func Compression(minBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, context: c, minBytes: minBytes}
+		c.Writer = writer
+		defer func() {
+			writer.close()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// compressWriter decides on the first minBytes of the body whether the response is compressed
+type compressWriter struct {
+	gin.ResponseWriter
+	context  *gin.Context
+	minBytes int
+
+	buffer  []byte
+	size    int
+	decided bool
+	gzip    *gzip.Writer
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		if !w.compressible() {
+			w.passthrough()
+		} else {
+			w.buffer = append(w.buffer, data...)
+			w.size += len(data)
+			if len(w.buffer) >= w.minBytes {
+				if err := w.startGzip(); err != nil {
+					return 0, err
+				}
+			}
+			return len(data), nil
+		}
+	}
+
+	w.size += len(data)
+	if w.gzip != nil {
+		return w.gzip.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers of a response without body (e.g. 304), which is never compressed
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.passthrough()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush marks a stream: what was held goes out uncompressed, unless compression already started
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.passthrough()
+	}
+	if w.gzip != nil {
+		_ = w.gzip.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Written reports the body held in the buffer as written, so error handlers do not answer twice
+func (w *compressWriter) Written() bool {
+	return w.size > 0 || w.ResponseWriter.Written()
+}
+
+// Size is the uncompressed size written by the handler
+func (w *compressWriter) Size() int {
+	if w.size > 0 {
+		return w.size
+	}
+	return w.ResponseWriter.Size()
+}
+
+// compressible checks the response as it stands when the handler starts writing the body
+func (w *compressWriter) compressible() bool {
+	if w.context.GetBool(noCompressionKey) {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	switch w.Status() {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	return compressibleType(header.Get("Content-Type"))
+}
+
+// startGzip sends the headers of a compressed response and compresses what was held
+func (w *compressWriter) startGzip() error {
+	w.decided = true
+
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+
+	w.gzip = gzipWriters.Get().(*gzip.Writer)
+	w.gzip.Reset(w.ResponseWriter)
+
+	held := w.buffer
+	w.buffer = nil
+	_, err := w.gzip.Write(held)
+	return err
+}
+
+// passthrough sends the response uncompressed, starting with what was held
+func (w *compressWriter) passthrough() {
+	w.decided = true
+	if len(w.buffer) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buffer)
+		w.buffer = nil
+	}
+}
+
+// close ends the gzip stream, or sends a body that stayed below minBytes as it is
+func (w *compressWriter) close() {
+	if w.gzip != nil {
+		_ = w.gzip.Close()
+		w.gzip.Reset(nil)
+		gzipWriters.Put(w.gzip)
+		w.gzip = nil
+		return
+	}
+	if !w.decided {
+		w.passthrough()
+	}
+}
+
+// compressibleType accepts JSON (application/json and +json types). NDJSON, CSV and event
+// streams are left out: they are streamed and their clients read them as they arrive.
+func compressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// acceptsGzip checks Accept-Encoding for gzip (or *) with a non-zero quality
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		quality := strings.TrimSpace(params)
+		if value, ok := strings.CutPrefix(quality, "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err == nil && q == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	large := strings.Repeat("ticket ", 500)
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		expectGzip     bool
+	}{
+		{name: "Large JSON is compressed", path: "/json/large", acceptEncoding: "gzip, deflate, br", expectGzip: true},
+		{name: "Client without gzip", path: "/json/large", acceptEncoding: "br"},
+		{name: "Client refuses gzip", path: "/json/large", acceptEncoding: "gzip;q=0, br"},
+		{name: "Small JSON", path: "/json/small", acceptEncoding: "gzip"},
+		{name: "Not JSON", path: "/csv", acceptEncoding: "gzip"},
+		{name: "Route opted out", path: "/json/opted-out", acceptEncoding: "gzip"},
+		{name: "Flushed stream", path: "/json/stream", acceptEncoding: "gzip"},
+	}
+
+	engine := gin.New()
+	engine.Use(Compression(defaultCompressionMinBytes))
+	engine.GET("/json/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": large})
+	})
+	engine.GET("/json/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": "ticket"})
+	})
+	engine.GET("/csv", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/csv", []byte(large))
+	})
+	engine.GET("/json/opted-out", NoCompression(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": large})
+	})
+	engine.GET("/json/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		_, _ = c.Writer.WriteString(`{"data":"`)
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString(large + `"}`)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+			body := w.Body.Bytes()
+			if tt.expectGzip {
+				assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+				assert.Empty(t, w.Header().Get("Content-Length"))
+
+				reader, err := gzip.NewReader(w.Body)
+				require.NoError(t, err)
+				body, err = io.ReadAll(reader)
+				require.NoError(t, err)
+				assert.Less(t, w.Body.Len(), len(body))
+			} else {
+				assert.Empty(t, w.Header().Get("Content-Encoding"))
+			}
+
+			assert.Contains(t, string(body), "ticket")
+		})
+	}
+}
+
+func TestCompressionKeepsErrorHandling(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(Compression(defaultCompressionMinBytes))
+	engine.Use(errorHandler())
+	engine.GET("/written", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": "ok"})
+		_ = c.Error(assert.AnError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/written", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	// O corpo ainda retido no buffer conta como escrito, então o errorHandler não responde de novo
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":"ok"}`, w.Body.String())
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("deflate, GZIP;q=0.5"))
+	assert.True(t, acceptsGzip("*"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("br, deflate"))
+	assert.False(t, acceptsGzip("gzip;q=0"))
+}
@@ -9,8 +9,8 @@ func setupCors(engine *gin.Engine) {
 	engine.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization", "Accept-Timezone", IdempotencyKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", idempotencyReplayedHeader, quotaLimitHeader, quotaRemainingHeader, quotaResetHeader},
 		AllowCredentials: true,
 	}))
 }
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes a deprecated route and its retirement plan
+type Deprecation struct {
+	Method       string    // HTTP method
+	Path         string    // Gin route path (e.g. /tickets/:id)
+	Replacement  string    // Successor route, sent in the Link header
+	DeprecatedAt time.Time // When the route was deprecated
+	SunsetAt     time.Time // When the route stops answering (zero means no date yet)
+}
+
+// DeprecationRegistry holds the deprecated routes indexed by method and path
+type DeprecationRegistry struct {
+	mu     sync.RWMutex
+	routes map[string]Deprecation
+}
+
+// Deprecations is the registry used by the deprecation middleware
+var Deprecations = NewDeprecationRegistry()
+
+// NewDeprecationRegistry creates an empty deprecation registry
+func NewDeprecationRegistry() *DeprecationRegistry {
+	return &DeprecationRegistry{
+		routes: make(map[string]Deprecation),
+	}
+}
+
+// Register adds or replaces a deprecated route
+func (r *DeprecationRegistry) Register(d Deprecation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[d.Method+" "+d.Path] = d
+}
+
+// Lookup returns the deprecation registered for a route, if any
+func (r *DeprecationRegistry) Lookup(method, path string) (Deprecation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.routes[method+" "+path]
+	return d, ok
+}
+
+// List returns all deprecated routes ordered by sunset date
+func (r *DeprecationRegistry) List() []Deprecation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Deprecation, 0, len(r.routes))
+	for _, d := range r.routes {
+		list = append(list, d)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SunsetAt.Equal(list[j].SunsetAt) {
+			return list[i].Path < list[j].Path
+		}
+		return list[i].SunsetAt.Before(list[j].SunsetAt)
+	})
+
+	return list
+}
+
+// DeprecationUsageKey returns the Redis hash that counts calls per client to a deprecated route
+func DeprecationUsageKey(d Deprecation) string {
+	return "deprecation:usage:" + d.Method + ":" + d.Path
+}
+
+// setupDeprecations adds the deprecation middleware to the engine
+func setupDeprecations(engine *gin.Engine, cfg *config.App) {
+	engine.Use(DeprecationMiddleware(cfg))
+}
+
+// DeprecationMiddleware emits Deprecation/Sunset/Link headers for registered routes,
+// records which clients still call them and answers 410 Gone after the sunset date
+func DeprecationMiddleware(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d, ok := Deprecations.Lookup(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		c.Header("Deprecation", fmt.Sprintf("@%d", d.DeprecatedAt.Unix()))
+		if !d.SunsetAt.IsZero() {
+			c.Header("Sunset", d.SunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if d.Replacement != "" {
+			c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", d.Replacement))
+		}
+
+		if !d.SunsetAt.IsZero() && time.Now().After(d.SunsetAt) {
+			c.AbortWithStatusJSON(http.StatusGone, dto.NewErrorResponse(
+				c,
+				http.StatusGone,
+				"Gone",
+				"This endpoint was retired, use "+d.Replacement,
+				map[string]interface{}{
+					"sunset":      d.SunsetAt.UTC(),
+					"replacement": d.Replacement,
+				},
+			))
+			recordDeprecatedCall(c, cfg, d)
+			return
+		}
+
+		c.Next()
+
+		// Identifica o cliente depois do Auth, que roda dentro da cadeia
+		recordDeprecatedCall(c, cfg, d)
+	}
+}
+
+// recordDeprecatedCall counts the call in Redis and logs it
+func recordDeprecatedCall(c *gin.Context, cfg *config.App, d Deprecation) {
+	client := "ip:" + c.ClientIP()
+	if userID, ok := GetCurrentUserID(c); ok {
+		client = "user:" + strconv.Itoa(userID)
+	}
+
+	if err := cfg.Redis.HIncrBy(c.Request.Context(), DeprecationUsageKey(d), client, 1).Err(); err != nil {
+		cfg.Logger.Error("Failed to record deprecated route usage", err)
+	}
+
+	cfg.Logger.Warn("Deprecated route called", map[string]interface{}{
+		"method":      d.Method,
+		"path":        d.Path,
+		"client":      client,
+		"replacement": d.Replacement,
+		"sunset":      d.SunsetAt,
+		"request_id":  GetRequestID(c),
+	})
+}
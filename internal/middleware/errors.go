@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupErrors registra a conversão dos erros dos handlers em respostas padronizadas
+func setupErrors(engine *gin.Engine) {
+	engine.Use(errorHandler())
+}
+
+// RespondError interrompe a requisição com err, que o errorHandler converte na resposta de erro:
+// o status vem do tipo do erro (apperror) e message é usada quando o erro não traz mensagem própria
+func RespondError(c *gin.Context, err error, message string) {
+	_ = c.Error(err).SetMeta(message)
+	c.Abort()
+}
+
+// errorHandler responde com o último erro registrado por RespondError quando o handler não
+// escreveu a resposta. Erros sem tipo conhecido viram 500.
+func errorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+
+		last := c.Errors.Last()
+		status := apperror.HTTPStatus(last.Err)
+
+		fallback, _ := last.Meta.(string)
+		if fallback == "" {
+			fallback = http.StatusText(status)
+		}
+		message := apperror.Message(last.Err, fallback)
+
+		// Dependência indisponível com espera conhecida (circuito aberto): resposta de retry padronizada
+		if retryAfter := apperror.RetryAfter(last.Err); retryAfter > 0 && status == http.StatusServiceUnavailable {
+			AbortWithRetry(c, status, dto.ReasonDependencyUnavailable, message, retryAfter, 0)
+			return
+		}
+
+		var details interface{}
+		if detail := last.Err.Error(); detail != message {
+			details = detail
+		}
+
+		c.JSON(status, dto.NewErrorResponse(c, status, http.StatusText(status), message, details))
+	}
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	errUserNotFound := apperror.New(apperror.ErrNotFound, "user not found")
+
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedMessage string
+		expectedDetails interface{}
+	}{
+		{
+			name:            "Typed error",
+			err:             errUserNotFound,
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "user not found",
+		},
+		{
+			name:            "Wrapped typed error keeps the context in details",
+			err:             fmt.Errorf("%w: 42", errUserNotFound),
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "user not found",
+			expectedDetails: "user not found: 42",
+		},
+		{
+			name:            "Untyped error uses the handler message",
+			err:             errors.New("connection reset"),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Failed to load user",
+			expectedDetails: "connection reset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(RequestIDMiddleware(""), errorHandler())
+			engine.GET("/users/42", func(c *gin.Context) {
+				RespondError(c, tt.err, "Failed to load user")
+			})
+
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+			var body dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedStatus, body.Code)
+			assert.Equal(t, http.StatusText(tt.expectedStatus), body.Error)
+			assert.Equal(t, tt.expectedMessage, body.Message)
+			assert.Equal(t, tt.expectedDetails, body.Details)
+			assert.NotEmpty(t, body.RequestID)
+		})
+	}
+}
+
+func TestErrorHandlerKeepsWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(errorHandler())
+	engine.GET("/", func(c *gin.Context) {
+		_ = c.Error(errors.New("logged only"))
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, nil, "ok"))
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestErrorHandlerRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(RequestIDMiddleware(""), errorHandler())
+	engine.GET("/metrics", func(c *gin.Context) {
+		RespondError(c, &apperror.Error{
+			Kind:       apperror.ErrUnavailable,
+			Message:    "sql_metrics is temporarily unavailable",
+			RetryAfter: 2500 * time.Millisecond,
+		}, "Failed to retrieve metrics")
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	var body dto.RateLimitErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "3", rec.Header().Get("Retry-After"))
+	assert.Equal(t, dto.ReasonDependencyUnavailable, body.Reason)
+	assert.Equal(t, "sql_metrics is temporarily unavailable", body.Message)
+}
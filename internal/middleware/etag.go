@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// volatileResponseFields change on every call without changing the content, so they are left out of the ETag
+var volatileResponseFields = []string{"timestamp", "request_id"}
+
+// etagWriter holds the response body until the handler returns, so the ETag can be computed from it
+type etagWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	streaming bool
+}
+
+func (w *etagWriter) Write(data []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush switches to streaming: what was held is sent and the response goes out without an ETag
+func (w *etagWriter) Flush() {
+	if !w.streaming {
+		w.streaming = true
+		if w.body.Len() > 0 {
+			_, _ = w.ResponseWriter.Write(w.body.Bytes())
+			w.body.Reset()
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// ETag tags successful GET responses with a weak ETag computed from the body and answers
+// 304 Not Modified when the client's If-None-Match already has it, so dashboards that poll
+// the same data only download it when it changes. Responses that are streamed (flushed by
+// the handler) or not 200 are sent unchanged.
+func ETag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		writer := &etagWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.streaming {
+			return
+		}
+
+		body := writer.body.Bytes()
+		if writer.Status() != http.StatusOK || len(body) == 0 {
+			_, _ = c.Writer.Write(body)
+			return
+		}
+
+		etag := responseETag(body, c.Writer.Header().Get("Content-Type"))
+		c.Header("ETag", etag)
+		// Authenticated data: only the client may keep it, and it must revalidate before reuse
+		c.Header("Cache-Control", "private, no-cache")
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Length")
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+
+		_, _ = c.Writer.Write(body)
+	}
+}
+
+// responseETag hashes the body; for JSON objects the volatile envelope fields are removed first
+func responseETag(body []byte, contentType string) string {
+	content := body
+	if strings.HasPrefix(contentType, "application/json") {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(body, &object); err == nil {
+			for _, field := range volatileResponseFields {
+				delete(object, field)
+			}
+			// Map keys are marshaled in order, so the same content always gives the same bytes
+			if stable, err := json.Marshal(object); err == nil {
+				content = stable
+			}
+		}
+	}
+
+	sum := sha256.Sum256(content)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches applies the weak comparison of If-None-Match, which may list several ETags or be *
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
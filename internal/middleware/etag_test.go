@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	total := 10
+	engine := gin.New()
+	engine.Use(RequestIDMiddleware(""))
+	engine.GET("/metrics", ETag(), func(c *gin.Context) {
+		// timestamp e request_id mudam a cada chamada sem mudar o conteúdo
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, gin.H{"total": total}, "ok"))
+	})
+	engine.GET("/missing", ETag(), func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "missing", nil))
+	})
+	engine.GET("/stream", ETag(), func(c *gin.Context) {
+		c.String(http.StatusOK, "first\n")
+		c.Writer.Flush()
+		c.String(http.StatusOK, "second\n")
+	})
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/metrics", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Contains(t, first.Body.String(), `"total":10`)
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+
+	t.Run("Same content answers 304", func(t *testing.T) {
+		rec := get("/metrics", etag)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("Any of several ETags matches", func(t *testing.T) {
+		rec := get("/metrics", `"other", `+etag)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	t.Run("Changed content answers 200 with a new ETag", func(t *testing.T) {
+		total = 11
+		defer func() { total = 10 }()
+
+		rec := get("/metrics", etag)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+		assert.Contains(t, rec.Body.String(), `"total":11`)
+	})
+
+	t.Run("Errors are not tagged", func(t *testing.T) {
+		rec := get("/missing", "*")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Header().Get("ETag"))
+		assert.Contains(t, rec.Body.String(), "missing")
+	})
+
+	t.Run("Streamed responses pass through", func(t *testing.T) {
+		rec := get("/stream", "*")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("ETag"))
+		assert.Equal(t, "first\nsecond\n", rec.Body.String())
+	})
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AbortWithRetry interrompe a requisição com uma resposta 429 ou 503 padronizada: header Retry-After
+// em segundos, motivo legível por máquina e indicação de que a requisição pode ser repetida.
+// Todo middleware que rejeita requisições por carga ou indisponibilidade deve responder por aqui.
+// limit é omitido da resposta e dos headers quando zero.
+func AbortWithRetry(c *gin.Context, status int, reason, message string, retryAfter time.Duration, limit int) {
+	response := dto.NewRateLimitErrorResponse(c, status, reason, message, retryAfter, limit, 0)
+
+	if response.RetryAfterSeconds > 0 {
+		c.Writer.Header().Set("Retry-After", strconv.Itoa(response.RetryAfterSeconds))
+	}
+	if limit > 0 {
+		c.Writer.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Writer.Header().Set("X-RateLimit-Remaining", "0")
+		c.Writer.Header().Set("X-RateLimit-Reset", response.ResetTime.Format(time.RFC3339))
+	}
+
+	c.AbortWithStatusJSON(status, response)
+}
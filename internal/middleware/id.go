@@ -5,14 +5,24 @@ import (
 	"github.com/google/uuid"
 )
 
-// setupIds -
+const (
+	// requestIDHeader is the header that carries the request ID in both directions
+	requestIDHeader = "X-Request-ID"
+	// requestIDKey is the gin context key read by dto responses and the request log
+	requestIDKey = "request_id"
+	// maxRequestIDLength bounds the client-supplied request ID stored in logs
+	maxRequestIDLength = 128
+)
+
+// setupIds assigns the request ID before any other middleware, so that every response,
+// including rate limit and overload rejections, carries the same ID as the request log
 func setupIds(engine *gin.Engine) {
 	engine.Use(RequestIDMiddleware(""))
 }
 
 // GetRequestID retrieves the request ID from Gin context
 func GetRequestID(c *gin.Context) string {
-	if requestID, exists := c.Get("request_id"); exists {
+	if requestID, exists := c.Get(requestIDKey); exists {
 		if id, ok := requestID.(string); ok {
 			return id
 		}
@@ -22,17 +32,45 @@ func GetRequestID(c *gin.Context) string {
 
 // RequestIDMiddleware adds request ID to context if not present
 func RequestIDMiddleware(headerName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ensureRequestID(c, headerName)
+		c.Next()
+	}
+}
+
+// ensureRequestID returns the request ID already assigned to the request or assigns one: the
+// client's header when it is a safe token, otherwise a new UUID. The ID is echoed in the response.
+func ensureRequestID(c *gin.Context, headerName string) string {
+	if requestID := GetRequestID(c); requestID != "" {
+		return requestID
+	}
 	if headerName == "" {
-		headerName = "X-Request-ID"
+		headerName = requestIDHeader
 	}
 
-	return func(c *gin.Context) {
-		requestID := c.GetHeader(headerName)
-		if requestID == "" {
-			requestID = uuid.New().String()
-			c.Header(headerName, requestID)
+	requestID := c.GetHeader(headerName)
+	if !validRequestID(requestID) {
+		requestID = uuid.New().String()
+	}
+
+	c.Set(requestIDKey, requestID)
+	c.Header(headerName, requestID)
+	return requestID
+}
+
+// validRequestID accepts IDs made of letters, digits and - _ . : so that a client cannot
+// inject arbitrary content into response headers and logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
 		}
-		c.Set("request_id", requestID)
-		c.Next()
 	}
+	return true
 }
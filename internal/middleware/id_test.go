@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDIsSharedByResponseAndHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{name: "Client ID is kept", header: "req-42.retry:1", keep: true},
+		{name: "Missing ID is generated", header: ""},
+		{name: "Unsafe ID is replaced", header: "id\r\nX-Injected: 1"},
+		{name: "Too long ID is replaced", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen []string
+			engine := gin.New()
+			engine.Use(RequestIDMiddleware(""))
+			// A second middleware asking for the ID must not generate another one
+			engine.Use(func(c *gin.Context) {
+				seen = append(seen, ensureRequestID(c, ""))
+				c.Next()
+			})
+			engine.GET("/", func(c *gin.Context) {
+				seen = append(seen, GetRequestID(c))
+				c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid", nil))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(requestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			var body dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+			id := rec.Header().Get(requestIDHeader)
+			require.NotEmpty(t, id)
+			assert.Equal(t, []string{id, id}, seen)
+			assert.Equal(t, id, body.RequestID)
+			if tt.keep {
+				assert.Equal(t, tt.header, id)
+			} else {
+				assert.NotEqual(t, tt.header, id)
+			}
+		})
+	}
+}
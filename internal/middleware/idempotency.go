@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	redisInternal "orderstreamrest/internal/repositories/redis"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client generated idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyReplayedHeader marks responses replayed from an earlier request with the same key
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	// idempotencyKeyPrefix prefixes the Redis keys holding the stored responses
+	idempotencyKeyPrefix = "idempotency:"
+	// maxIdempotencyKeyLength bounds the header so clients cannot make us hash arbitrary payloads
+	maxIdempotencyKeyLength = 255
+	// defaultIdempotencyTTL is how long a response is replayed when IDEMPOTENCY_TTL_HOURS is not set
+	defaultIdempotencyTTL = 24 * time.Hour
+	// idempotencyLockTTL bounds how long a request holds its key; a crashed instance releases it after this
+	idempotencyLockTTL = 2 * time.Minute
+	// idempotencyTimeout bounds each Redis call made by the middleware
+	idempotencyTimeout = 500 * time.Millisecond
+
+	idempotencyProcessing = "processing"
+	idempotencyCompleted  = "completed"
+)
+
+// idempotencyReplayedHeaders are the response headers stored and sent again on a replay
+var idempotencyReplayedHeaders = []string{"Content-Type", "Location"}
+
+// idempotencyStore is the Redis client holding the idempotency keys; nil disables the middleware (e.g. in tests)
+var idempotencyStore *redisInternal.RedisInternal
+
+// setupIdempotency enables the Idempotency-Key handling of the Idempotent middleware
+func setupIdempotency(cfg *config.App) {
+	idempotencyStore = cfg.Redis
+}
+
+// idempotencyRecord is what is stored under an idempotency key: the fingerprint of the request
+// that used it and, once that request finished, its response
+type idempotencyRecord struct {
+	State       string            `json:"state"`
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// idempotencyWriter copies the response body as it is written, so it can be stored for replays
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// IdempotencyTTL returns how long a response is kept for replays (IDEMPOTENCY_TTL_HOURS, default 24)
+func IdempotencyTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_HOURS"))
+	if err != nil || hours < 1 {
+		return defaultIdempotencyTTL
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// Idempotent makes a mutating endpoint safe to retry. When the request carries an Idempotency-Key
+// header, the first response for that key is stored and replayed to every retry with the same key
+// and body, so a client that lost the response of a create can retry without creating twice.
+// Reusing a key with a different body answers 422, and a retry that arrives while the first request
+// is still running answers 409. Keys are scoped by user (or client IP) and route; server errors are
+// not stored, so the request can be retried with the same key. Requests without the header, or
+// made while Redis is unavailable, run as usual.
+func Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || idempotencyStore == nil {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Invalid idempotency key",
+				"Idempotency-Key must have 1 to 255 printable ASCII characters", nil))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error(), nil))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		storeKey := idempotencyStoreKey(idempotencyScope(c), c.Request.Method, UnversionedPath(c.FullPath()), key)
+		fingerprint := requestFingerprint(body)
+
+		acquired, err := acquireIdempotencyKey(c.Request.Context(), storeKey, fingerprint)
+		if err != nil {
+			log.Printf("Failed to acquire idempotency key, running request without it: %v", err)
+			c.Next()
+			return
+		}
+
+		if !acquired {
+			record, err := loadIdempotencyRecord(c.Request.Context(), storeKey)
+			if err != nil {
+				log.Printf("Failed to load idempotency key, running request without it: %v", err)
+				c.Next()
+				return
+			}
+			respondIdempotencyRecord(c, record, fingerprint)
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// The client may be gone by now, but the outcome must still be recorded for its retry
+		ctx, cancel := context.WithTimeout(context.Background(), idempotencyTimeout)
+		defer cancel()
+
+		if !storableIdempotentStatus(writer.Status()) {
+			if err := idempotencyStore.Del(ctx, storeKey).Err(); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+
+		record := idempotencyRecord{
+			State:       idempotencyCompleted,
+			Fingerprint: fingerprint,
+			Status:      writer.Status(),
+			Headers:     make(map[string]string),
+			Body:        writer.body.Bytes(),
+		}
+		for _, name := range idempotencyReplayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				record.Headers[name] = value
+			}
+		}
+
+		data, err := json.Marshal(record)
+		if err == nil {
+			err = idempotencyStore.Set(ctx, storeKey, data, IdempotencyTTL()).Err()
+		}
+		if err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// respondIdempotencyRecord answers a request whose key was already used
+func respondIdempotencyRecord(c *gin.Context, record idempotencyRecord, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.NewErrorResponse(c, http.StatusUnprocessableEntity, "Idempotency key reused",
+			"Idempotency-Key was already used with a different request body", nil))
+	case record.State != idempotencyCompleted:
+		c.Header("Retry-After", "1")
+		c.AbortWithStatusJSON(http.StatusConflict, dto.NewErrorResponse(c, http.StatusConflict, "Request in progress",
+			"A request with this Idempotency-Key is still being processed", nil))
+	default:
+		for name, value := range record.Headers {
+			c.Header(name, value)
+		}
+		c.Header(idempotencyReplayedHeader, "true")
+		c.Status(record.Status)
+		_, _ = c.Writer.Write(record.Body)
+		c.Abort()
+	}
+}
+
+// acquireIdempotencyKey claims the key for this request; false means it was already used
+func acquireIdempotencyKey(ctx context.Context, storeKey, fingerprint string) (bool, error) {
+	data, err := json.Marshal(idempotencyRecord{State: idempotencyProcessing, Fingerprint: fingerprint})
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, idempotencyTimeout)
+	defer cancel()
+	return idempotencyStore.SetNX(ctx, storeKey, data, idempotencyLockTTL).Result()
+}
+
+// loadIdempotencyRecord reads what is stored under the key
+func loadIdempotencyRecord(ctx context.Context, storeKey string) (idempotencyRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, idempotencyTimeout)
+	defer cancel()
+
+	var record idempotencyRecord
+	data, err := idempotencyStore.Get(ctx, storeKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// Expired between the SetNX and the Get
+		return record, errors.New("idempotency key expired")
+	}
+	if err != nil {
+		return record, err
+	}
+	err = json.Unmarshal(data, &record)
+	return record, err
+}
+
+// validIdempotencyKey reports whether the header value is an acceptable key
+func validIdempotencyKey(key string) bool {
+	if len(key) == 0 || len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotencyScope keeps keys of different users apart: the authenticated user, or the client IP
+// on public routes
+func idempotencyScope(c *gin.Context) string {
+	if userID, ok := GetCurrentUserID(c); ok {
+		return "user:" + strconv.Itoa(userID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// idempotencyStoreKey builds the Redis key. The path is unversioned so a retry through
+// /api/v1 and through the unversioned alias share the key.
+func idempotencyStoreKey(scope, method, path, key string) string {
+	sum := sha256.Sum256([]byte(scope + "|" + method + "|" + path + "|" + key))
+	return idempotencyKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// requestFingerprint identifies the request body sent with a key
+func requestFingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// storableIdempotentStatus reports whether a response is final for its key. Server errors and
+// rate limiting are transient, so the key is released and the retry runs the request again.
+func storableIdempotentStatus(status int) bool {
+	return status < http.StatusInternalServerError && status != http.StatusTooManyRequests
+}
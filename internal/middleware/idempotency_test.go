@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{name: "uuid", key: "8e03978e-40d5-43e8-bc93-6894a57f9324", want: true},
+		{name: "printable symbols", key: "order:42/retry #1", want: true},
+		{name: "empty", key: "", want: false},
+		{name: "too long", key: strings.Repeat("a", maxIdempotencyKeyLength+1), want: false},
+		{name: "max length", key: strings.Repeat("a", maxIdempotencyKeyLength), want: true},
+		{name: "control character", key: "abc\n", want: false},
+		{name: "non ascii", key: "chave-é", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validIdempotencyKey(tt.key))
+		})
+	}
+}
+
+func TestIdempotencyStoreKey(t *testing.T) {
+	base := idempotencyStoreKey("user:1", http.MethodPost, "/tickets", "abc")
+
+	assert.True(t, strings.HasPrefix(base, idempotencyKeyPrefix))
+	assert.Equal(t, base, idempotencyStoreKey("user:1", http.MethodPost, "/tickets", "abc"))
+	assert.NotEqual(t, base, idempotencyStoreKey("user:2", http.MethodPost, "/tickets", "abc"), "keys are scoped by user")
+	assert.NotEqual(t, base, idempotencyStoreKey("user:1", http.MethodPost, "/users", "abc"), "keys are scoped by route")
+	assert.NotEqual(t, base, idempotencyStoreKey("user:1", http.MethodPost, "/tickets", "abd"))
+}
+
+func TestStorableIdempotentStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{status: http.StatusCreated, want: true},
+		{status: http.StatusBadRequest, want: true},
+		{status: http.StatusConflict, want: true},
+		{status: http.StatusTooManyRequests, want: false},
+		{status: http.StatusInternalServerError, want: false},
+		{status: http.StatusServiceUnavailable, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.want, storableIdempotentStatus(tt.status))
+		})
+	}
+}
+
+func TestRespondIdempotencyRecord(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fingerprint := requestFingerprint([]byte(`{"name":"a"}`))
+	completed := idempotencyRecord{
+		State:       idempotencyCompleted,
+		Fingerprint: fingerprint,
+		Status:      http.StatusCreated,
+		Headers:     map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:        []byte(`{"id":1}`),
+	}
+
+	tests := []struct {
+		name         string
+		record       idempotencyRecord
+		fingerprint  string
+		wantStatus   int
+		wantReplayed bool
+	}{
+		{name: "replays completed response", record: completed, fingerprint: fingerprint, wantStatus: http.StatusCreated, wantReplayed: true},
+		{name: "different body", record: completed, fingerprint: requestFingerprint([]byte(`{"name":"b"}`)), wantStatus: http.StatusUnprocessableEntity},
+		{name: "still processing", record: idempotencyRecord{State: idempotencyProcessing, Fingerprint: fingerprint}, fingerprint: fingerprint, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/tickets", nil)
+
+			respondIdempotencyRecord(c, tt.record, tt.fingerprint)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.True(t, c.IsAborted())
+			if tt.wantReplayed {
+				assert.Equal(t, "true", rec.Header().Get(idempotencyReplayedHeader))
+				assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+				assert.Equal(t, `{"id":1}`, rec.Body.String())
+			} else {
+				assert.Empty(t, rec.Header().Get(idempotencyReplayedHeader))
+			}
+		})
+	}
+}
+
+func TestIdempotentWithoutStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls := 0
+	engine := gin.New()
+	engine.POST("/tickets", Idempotent(), func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusCreated)
+	})
+
+	// Without Redis (or without the header) every request runs the handler
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/tickets", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "same-key")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	}
+	assert.Equal(t, 2, calls)
+}
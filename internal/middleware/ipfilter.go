@@ -0,0 +1,294 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	redisInternal "orderstreamrest/internal/repositories/redis"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// ipRulesKey guarda as regras definidas pelos administradores, compartilhadas entre as instâncias
+	ipRulesKey = "ipfilter:rules"
+	// ipRulesRefresh é por quanto tempo cada instância usa as regras em memória antes de relê-las do Redis
+	ipRulesRefresh = 30 * time.Second
+	// ipRulesTimeout limita a leitura das regras no Redis
+	ipRulesTimeout = 500 * time.Millisecond
+
+	ipRulesSourceConfig = "config"
+	ipRulesSourceAdmin  = "admin"
+)
+
+// IPFilters guarda as regras de IP do middleware RestrictIPs; nil desativa o filtro (ex.: nos testes)
+var IPFilters *IPFilter
+
+// IPFilter decide quais redes alcançam as rotas sensíveis. As regras partem de IP_ALLOWLIST e
+// IP_DENYLIST e podem ser substituídas pelos administradores; nesse caso ficam no Redis e valem para
+// todas as instâncias. Cada instância mantém as regras em memória e as relê a cada ipRulesRefresh, em
+// segundo plano, para que nenhuma requisição espere pelo Redis.
+type IPFilter struct {
+	redis    *redisInternal.RedisInternal
+	defaults dto.IPRules
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	current  ipRuleSet
+	loadedAt time.Time
+	// version muda a cada troca das regras, para que uma releitura lenta não desfaça uma alteração mais nova
+	version    uint64
+	refreshing atomic.Bool
+}
+
+// ipRuleSet são as regras com as redes já interpretadas
+type ipRuleSet struct {
+	rules dto.IPRules
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// NewIPFilter cria o filtro com as regras da configuração
+func NewIPFilter(redisClient *redisInternal.RedisInternal, defaults dto.IPRules) *IPFilter {
+	defaults.Source = ipRulesSourceConfig
+	return &IPFilter{
+		redis:    redisClient,
+		defaults: defaults,
+		now:      time.Now,
+		current:  newIPRuleSet(defaults),
+	}
+}
+
+// LoadIPRules lê as redes aceitas e recusadas de IP_ALLOWLIST e IP_DENYLIST; entradas inválidas são ignoradas
+func LoadIPRules() dto.IPRules {
+	allow, deny := splitList(os.Getenv("IP_ALLOWLIST")), splitList(os.Getenv("IP_DENYLIST"))
+	if invalid := InvalidNetworks(allow, deny); len(invalid) > 0 {
+		log.Printf("IP filter: ignoring invalid IP_ALLOWLIST/IP_DENYLIST entries %v", invalid)
+	}
+	return dto.IPRules{Allow: validNetworks(allow), Deny: validNetworks(deny)}
+}
+
+// setupIPFilter habilita o filtro de IP do middleware RestrictIPs, já com as regras dos administradores
+// guardadas no Redis, para que a instância não comece aceitando as redes da configuração
+func setupIPFilter(cfg *config.App) {
+	filter := NewIPFilter(cfg.Redis, LoadIPRules())
+	if filter.redis != nil {
+		filter.refresh()
+	}
+	IPFilters = filter
+}
+
+// RestrictIPs recusa com 403 as requisições de redes recusadas ou fora das redes aceitas. Deve vir antes
+// de Auth, para que a rede seja conferida antes do token. O IP é o de ClientIP(): sem TRUSTED_PROXIES é o
+// endereço da conexão e X-Forwarded-For é ignorado (ver setupTrustedProxies), então o header não burla as
+// listas. Atrás de um balanceador, TRUSTED_PROXIES é obrigatório, senão todas as requisições têm o IP dele.
+func RestrictIPs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := IPFilters
+		if filter == nil {
+			c.Next()
+			return
+		}
+
+		ip := c.ClientIP()
+		if !filter.ruleSet().allows(ip) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.NewErrorResponse(
+				c,
+				http.StatusForbidden,
+				"Forbidden",
+				"Access denied from this network",
+				map[string]interface{}{"ip": ip},
+			))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Rules retorna as regras em vigor, lidas do Redis
+func (f *IPFilter) Rules(ctx context.Context) (dto.IPRules, error) {
+	set, err := f.load(ctx)
+	if err != nil {
+		return dto.IPRules{}, err
+	}
+	return set.rules, nil
+}
+
+// Defaults retorna as regras da configuração
+func (f *IPFilter) Defaults() dto.IPRules {
+	return f.defaults
+}
+
+// SetRules substitui as regras em todas as instâncias. As redes devem ter passado por InvalidNetworks.
+func (f *IPFilter) SetRules(ctx context.Context, allow, deny []string, userID int) (dto.IPRules, error) {
+	if f.redis == nil {
+		return dto.IPRules{}, errors.New("redis is not configured")
+	}
+
+	updatedAt := f.now().UTC()
+	rules := dto.IPRules{
+		Allow:     nonNilList(allow),
+		Deny:      nonNilList(deny),
+		Source:    ipRulesSourceAdmin,
+		UpdatedAt: &updatedAt,
+		UpdatedBy: &userID,
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return dto.IPRules{}, err
+	}
+	if err := f.redis.Set(ctx, ipRulesKey, data, 0).Err(); err != nil {
+		return dto.IPRules{}, err
+	}
+
+	f.store(newIPRuleSet(rules))
+	return rules, nil
+}
+
+// ResetRules descarta as regras dos administradores e volta às da configuração
+func (f *IPFilter) ResetRules(ctx context.Context) (dto.IPRules, error) {
+	if f.redis == nil {
+		return dto.IPRules{}, errors.New("redis is not configured")
+	}
+	if err := f.redis.Del(ctx, ipRulesKey).Err(); err != nil {
+		return dto.IPRules{}, err
+	}
+
+	f.store(newIPRuleSet(f.defaults))
+	return f.defaults, nil
+}
+
+// ruleSet retorna as regras em memória. Quando passam de ipRulesRefresh, uma única releitura do Redis é
+// disparada em segundo plano e as regras atuais continuam valendo até ela terminar.
+func (f *IPFilter) ruleSet() ipRuleSet {
+	f.mu.RLock()
+	current, stale := f.current, f.now().Sub(f.loadedAt) >= ipRulesRefresh
+	f.mu.RUnlock()
+
+	if stale && f.redis != nil && f.refreshing.CompareAndSwap(false, true) {
+		go f.refresh()
+	}
+	return current
+}
+
+// refresh relê as regras do Redis. Se o Redis falhar as regras atuais continuam valendo até a próxima
+// tentativa, depois de ipRulesRefresh.
+func (f *IPFilter) refresh() {
+	defer f.refreshing.Store(false)
+
+	f.mu.RLock()
+	version := f.version
+	f.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), ipRulesTimeout)
+	defer cancel()
+	set, err := f.load(ctx)
+	f.apply(version, set, err)
+}
+
+// apply guarda as regras relidas do Redis, a menos que elas tenham sido trocadas depois de version
+func (f *IPFilter) apply(version uint64, set ipRuleSet, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loadedAt = f.now()
+	if err != nil {
+		log.Printf("IP filter: Redis unavailable (%v), keeping the current rules", err)
+		return
+	}
+	if f.version == version {
+		f.current = set
+		f.version++
+	}
+}
+
+// load lê as regras dos administradores no Redis; sem elas valem as da configuração
+func (f *IPFilter) load(ctx context.Context) (ipRuleSet, error) {
+	if f.redis == nil {
+		return newIPRuleSet(f.defaults), nil
+	}
+
+	data, err := f.redis.Get(ctx, ipRulesKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return newIPRuleSet(f.defaults), nil
+	}
+	if err != nil {
+		return ipRuleSet{}, err
+	}
+
+	var rules dto.IPRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return ipRuleSet{}, fmt.Errorf("invalid IP rules in %s: %w", ipRulesKey, err)
+	}
+	return newIPRuleSet(rules), nil
+}
+
+// store troca as regras em memória da instância
+func (f *IPFilter) store(set ipRuleSet) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current = set
+	f.loadedAt = f.now()
+	f.version++
+}
+
+// IPAllowed informa se o IP passaria pelas listas, para conferir uma alteração antes de aplicá-la
+func IPAllowed(allow, deny []string, ip string) bool {
+	return newIPRuleSet(dto.IPRules{Allow: allow, Deny: deny}).allows(ip)
+}
+
+// InvalidNetworks retorna as entradas das listas que não são IPs nem CIDRs
+func InvalidNetworks(lists ...[]string) []string {
+	var invalid []string
+	for _, list := range lists {
+		_, bad := parseNetworks(list)
+		invalid = append(invalid, bad...)
+	}
+	return invalid
+}
+
+// newIPRuleSet interpreta as redes das regras; entradas inválidas são ignoradas
+func newIPRuleSet(rules dto.IPRules) ipRuleSet {
+	rules.Allow, rules.Deny = nonNilList(rules.Allow), nonNilList(rules.Deny)
+	allow, _ := parseNetworks(rules.Allow)
+	deny, _ := parseNetworks(rules.Deny)
+	return ipRuleSet{rules: rules, allow: allow, deny: deny}
+}
+
+// allows aplica as regras: Deny sempre recusa e, com Allow preenchido, só as suas redes passam
+func (s ipRuleSet) allows(ip string) bool {
+	if containsIP(s.deny, ip) {
+		return false
+	}
+	return len(s.rules.Allow) == 0 || containsIP(s.allow, ip)
+}
+
+// validNetworks remove as entradas que não são IPs nem CIDRs
+func validNetworks(items []string) []string {
+	valid := []string{}
+	for _, item := range items {
+		if _, invalid := parseNetworks([]string{item}); len(invalid) == 0 {
+			valid = append(valid, item)
+		}
+	}
+	return valid
+}
+
+// nonNilList troca a lista nula por vazia, para que a resposta traga [] e não null
+func nonNilList(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"orderstreamrest/internal/models/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		allow    []string
+		deny     []string
+		ip       string
+		expected bool
+	}{
+		{name: "No rules allow everyone", ip: "203.0.113.9", expected: true},
+		{name: "Inside the allowlist", allow: []string{"10.8.0.0/16"}, ip: "10.8.2.31", expected: true},
+		{name: "Outside the allowlist", allow: []string{"10.8.0.0/16"}, ip: "203.0.113.9", expected: false},
+		{name: "Single IP in the allowlist", allow: []string{"192.168.1.20"}, ip: "192.168.1.20", expected: true},
+		{name: "Denylist wins over the allowlist", allow: []string{"10.8.0.0/16"}, deny: []string{"10.8.4.17"}, ip: "10.8.4.17", expected: false},
+		{name: "Denylist alone", deny: []string{"198.51.100.0/24"}, ip: "198.51.100.7", expected: false},
+		{name: "IPv6 allowlist", allow: []string{"2001:db8::/32"}, ip: "2001:db8::1", expected: true},
+		{name: "Unparseable IP with an allowlist", allow: []string{"10.8.0.0/16"}, ip: "unknown", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IPAllowed(tt.allow, tt.deny, tt.ip))
+		})
+	}
+}
+
+func TestInvalidNetworks(t *testing.T) {
+	assert.Empty(t, InvalidNetworks([]string{"10.0.0.0/8", "192.168.1.20"}, []string{"2001:db8::/32"}))
+	assert.Equal(t, []string{"10.0.0.0/33", "vpn"}, InvalidNetworks([]string{"10.0.0.0/33"}, []string{"vpn"}))
+}
+
+func TestLoadIPRules(t *testing.T) {
+	t.Setenv("IP_ALLOWLIST", "10.8.0.0/16, vpn,192.168.1.20")
+	t.Setenv("IP_DENYLIST", "")
+
+	assert.Equal(t, dto.IPRules{Allow: []string{"10.8.0.0/16", "192.168.1.20"}, Deny: []string{}}, LoadIPRules())
+}
+
+func TestRestrictIPs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	previous := IPFilters
+	t.Cleanup(func() { IPFilters = previous })
+
+	tests := []struct {
+		name           string
+		filter         *IPFilter
+		remoteAddr     string
+		forwarded      string
+		expectedStatus int
+	}{
+		{name: "Filter not configured", filter: nil, remoteAddr: "203.0.113.9:4000", expectedStatus: http.StatusOK},
+		{name: "Allowed network", filter: NewIPFilter(nil, dto.IPRules{Allow: []string{"10.8.0.0/16"}}), remoteAddr: "10.8.2.31:4000", expectedStatus: http.StatusOK},
+		{name: "Network outside the allowlist", filter: NewIPFilter(nil, dto.IPRules{Allow: []string{"10.8.0.0/16"}}), remoteAddr: "203.0.113.9:4000", expectedStatus: http.StatusForbidden},
+		{name: "Denied IP", filter: NewIPFilter(nil, dto.IPRules{Deny: []string{"203.0.113.9"}}), remoteAddr: "203.0.113.9:4000", expectedStatus: http.StatusForbidden},
+		{name: "Forged X-Forwarded-For without trusted proxies", filter: NewIPFilter(nil, dto.IPRules{Allow: []string{"10.8.0.0/16"}}), remoteAddr: "203.0.113.9:4000", forwarded: "10.8.2.31", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			IPFilters = tt.filter
+			router := gin.New()
+			require.NoError(t, trustProxies(router, nil))
+			router.GET("/admin/summary", RestrictIPs(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/admin/summary", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestIPFilterApply(t *testing.T) {
+	adminRules := newIPRuleSet(dto.IPRules{Allow: []string{"192.168.0.0/16"}})
+
+	tests := []struct {
+		name       string
+		changed    bool
+		err        error
+		expectedIP string
+	}{
+		{name: "Refreshed rules replace the current ones", expectedIP: "192.168.1.20"},
+		{name: "Rules changed during the refresh are kept", changed: true, expectedIP: "10.8.2.31"},
+		{name: "Redis failure keeps the current rules", err: errors.New("timeout"), expectedIP: "10.8.2.31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewIPFilter(nil, dto.IPRules{Allow: []string{"10.0.0.0/8"}})
+			version := filter.version
+			if tt.changed {
+				filter.store(newIPRuleSet(dto.IPRules{Allow: []string{"10.8.0.0/16"}}))
+			}
+
+			filter.apply(version, adminRules, tt.err)
+
+			assert.True(t, filter.ruleSet().allows(tt.expectedIP))
+			assert.False(t, filter.loadedAt.IsZero())
+		})
+	}
+}
@@ -1,45 +1,91 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"orderstreamrest/internal/models/dto"
-	"os"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/utils"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 )
 
-// GenerateJWT generates a JWT token for a given user ID, email, and role
-func GenerateJWT(userID int64, email string, role int64) (string, error) {
-	jwtKey := os.Getenv("JWT_SECRET")
+// companyScopedRoles are the user types that only see tickets and metrics of their own company
+var companyScopedRoles = map[utils.Role]bool{
+	utils.RoleManager: true,
+}
+
+// GenerateJWT generates a JWT token for a given user ID, email, role, company and session.
+// A companyID of 0 means the user is not bound to a company and an empty sessionID means the
+// token is not tracked as a session; both claims are then omitted.
+func GenerateJWT(userID int64, email string, role utils.Role, companyID int64, sessionID string) (string, error) {
+	if !role.Valid() {
+		return "", fmt.Errorf("unknown role %q", role)
+	}
+
+	settings, err := loadTokenSettings()
+	if err != nil {
+		return "", err
+	}
+	if settings.signKey == nil {
+		return "", errors.New("RS256 signing requires JWT_PRIVATE_KEY_FILE")
+	}
+
+	now := time.Now()
 	claims := jwt.MapClaims{
 
 		"user_id": userID,
 		"email":   email,
-		"role":    role,
-		"exp":     time.Now().Add(1 * time.Hour).Unix(),
+		"role":    role.Code(),
+		"iss":     settings.issuer,
+		"aud":     settings.audience,
+		"jti":     uuid.NewString(),
+		"iat":     now.Unix(),
+		"exp":     now.Add(settings.lifetime).Unix(),
+	}
+	if companyID > 0 {
+		claims["company_id"] = companyID
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtKey))
+	token := jwt.NewWithClaims(settings.method, claims)
+	token.Header["kid"] = settings.keyID
+	return token.SignedString(settings.signKey)
 }
 
-// VerifyToken verifies a JWT token and returns the token if valid
+// VerifyToken verifies a JWT token and returns the token if valid.
+// Only the configured signing method is accepted, the key is picked by the kid header from the
+// keyring, and the iss and aud claims must match.
 func VerifyToken(token string) (*jwt.Token, error) {
+	settings, err := loadTokenSettings()
+	if err != nil {
+		return nil, err
+	}
 
 	tokenVerify, err := jwt.Parse(token, func(newToken *jwt.Token) (any, error) {
-		if _, isValid := newToken.Method.(*jwt.SigningMethodHMAC); !isValid {
+		if newToken.Method.Alg() != settings.method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", newToken.Header["alg"])
 		}
-		return []byte(os.Getenv("JWT_SECRET")), nil
+		kid, _ := newToken.Header["kid"].(string)
+		return settings.verificationKey(kid)
 	})
 	if err != nil {
 		err = errors.New("failed to verify token: " + err.Error())
 		return nil, err
 	}
+
+	claims, ok := tokenVerify.Claims.(jwt.MapClaims)
+	if !ok || !claims.VerifyIssuer(settings.issuer, true) || !claims.VerifyAudience(settings.audience, true) {
+		return nil, errors.New("failed to verify token: invalid issuer or audience")
+	}
 	return tokenVerify, nil
 }
 
@@ -62,6 +108,9 @@ func DecodeTokenJWT(token string) (jwt.MapClaims, error) {
 	return nil, fmt.Errorf("invalid token")
 }
 
+// ErrTokenRevoked is returned for valid tokens of revoked sessions or deactivated users
+var ErrTokenRevoked = errors.New("token has been revoked")
+
 // Auth is a middleware function that checks for a valid JWT token in the Authorization header
 func Auth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -81,14 +130,163 @@ func Auth() gin.HandlerFunc {
 
 		token = parts[1]
 
-		claims, err := DecodeTokenJWT(token)
+		claims, ctx, err := AuthenticateToken(c.Request.Context(), token)
 		if err != nil {
-			authError := dto.NewAuthErrorResponse(c, "Invalid token")
+			message := "Invalid token"
+			if errors.Is(err, ErrTokenRevoked) {
+				message = "Token has been revoked"
+			}
+			authError := dto.NewAuthErrorResponse(c, message)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, authError)
 			return
 		}
 
 		c.Set("currentUser", claims)
+		c.Request = c.Request.WithContext(ctx)
+
 		c.Next()
 	}
 }
+
+// AuthenticateToken validates a JWT the same way as Auth and returns its claims and a context
+// carrying the user's search scope. Transports other than Gin (e.g. gRPC) authenticate through it.
+func AuthenticateToken(ctx context.Context, token string) (jwt.MapClaims, context.Context, error) {
+	claims, err := DecodeTokenJWT(token)
+	if err != nil {
+		return nil, ctx, err
+	}
+
+	// Tokens of deactivated or deleted users are rejected before they expire
+	if isTokenRevoked(ctx, claims) || !isSessionActive(ctx, claims) {
+		return nil, ctx, ErrTokenRevoked
+	}
+
+	// Escopo de busca no Elasticsearch (e das métricas) do usuário autenticado
+	return claims, elsearch.WithScope(ctx, searchScope(claims)), nil
+}
+
+// RequireRoles is a middleware function that only allows users whose role is one of roles.
+// It must be used after Auth, and panics on an unknown role so a typo fails at startup.
+func RequireRoles(roles ...utils.Role) gin.HandlerFunc {
+	allowed := make(map[utils.Role]bool, len(roles))
+	for _, role := range roles {
+		if !role.Valid() {
+			panic(fmt.Sprintf("RequireRoles: unknown role %q", role))
+		}
+		allowed[role] = true
+	}
+
+	return func(c *gin.Context) {
+		if !allowed[GetCurrentRole(c)] {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.NewErrorResponse(
+				c,
+				http.StatusForbidden,
+				"Forbidden",
+				"User does not have permission to access this resource",
+				nil,
+			))
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetCurrentUserID returns the ID of the authenticated user
+func GetCurrentUserID(c *gin.Context) (int, bool) {
+	claims, ok := c.Get("currentUser")
+	if !ok {
+		return 0, false
+	}
+
+	mapClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, false
+	}
+
+	userID, ok := mapClaims["user_id"].(float64)
+	if !ok {
+		return 0, false
+	}
+
+	return int(userID), true
+}
+
+// GetCurrentCompanyID returns the company the authenticated user is bound to
+func GetCurrentCompanyID(c *gin.Context) (int64, bool) {
+	claims, ok := c.Get("currentUser")
+	if !ok {
+		return 0, false
+	}
+
+	mapClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, false
+	}
+
+	return companyClaim(mapClaims)
+}
+
+// companyClaim reads the company_id claim
+func companyClaim(claims jwt.MapClaims) (int64, bool) {
+	companyID, ok := claims["company_id"].(float64)
+	if !ok || companyID <= 0 {
+		return 0, false
+	}
+	return int64(companyID), true
+}
+
+// searchScope returns the tenant scope of the token: company-scoped user types only see their own
+// company, and see nothing when the token carries no company; every other user type is unrestricted
+func searchScope(claims jwt.MapClaims) elsearch.Scope {
+	if !companyScopedRoles[roleClaim(claims)] {
+		return elsearch.UnrestrictedScope()
+	}
+
+	companyID, ok := companyClaim(claims)
+	if !ok {
+		return elsearch.CompanyScope()
+	}
+	return elsearch.CompanyScope(strconv.FormatInt(companyID, 10))
+}
+
+// GetCurrentRole returns the role of the authenticated user, or an empty role
+func GetCurrentRole(c *gin.Context) utils.Role {
+	claims, ok := c.Get("currentUser")
+	if !ok {
+		return ""
+	}
+
+	mapClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+
+	return roleClaim(mapClaims)
+}
+
+// roleClaim reads the role claim, or returns an empty role when it is missing or unknown
+func roleClaim(claims jwt.MapClaims) utils.Role {
+	// Claims numéricos são decodificados como float64
+	code, ok := claims["role"].(float64)
+	if !ok {
+		return ""
+	}
+
+	role, _ := utils.RoleFromCode(int64(code))
+	return role
+}
+
+// bearerClaims returns the claims of a valid Bearer token in the request, without rejecting it.
+// Used by middlewares that run before Auth.
+func bearerClaims(c *gin.Context) (jwt.MapClaims, bool) {
+	parts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return nil, false
+	}
+
+	claims, err := DecodeTokenJWT(parts[1])
+	if err != nil {
+		return nil, false
+	}
+	return claims, true
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/utils"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateJWTCompanyClaim(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token, err := GenerateJWT(4, "manager@example.com", utils.RoleManager, 12, "")
+	require.NoError(t, err)
+	claims, err := DecodeTokenJWT(token)
+	require.NoError(t, err)
+	companyID, ok := companyClaim(claims)
+	assert.True(t, ok)
+	assert.Equal(t, int64(12), companyID)
+
+	token, err = GenerateJWT(7, "admin@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+	claims, err = DecodeTokenJWT(token)
+	require.NoError(t, err)
+	assert.NotContains(t, claims, "company_id")
+}
+
+func TestSearchScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		expected elsearch.Scope
+	}{
+		{
+			name:     "Admin sees every company",
+			claims:   jwt.MapClaims{"role": float64(1), "company_id": float64(12)},
+			expected: elsearch.UnrestrictedScope(),
+		},
+		{
+			name:     "Manager sees only their company",
+			claims:   jwt.MapClaims{"role": float64(2), "company_id": float64(12)},
+			expected: elsearch.CompanyScope("12"),
+		},
+		{
+			name:     "Manager without company sees nothing",
+			claims:   jwt.MapClaims{"role": float64(2)},
+			expected: elsearch.CompanyScope(),
+		},
+		{
+			name:     "Agent is not company scoped",
+			claims:   jwt.MapClaims{"role": float64(3), "company_id": float64(12)},
+			expected: elsearch.UnrestrictedScope(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, searchScope(tt.claims))
+		})
+	}
+}
+
+func TestGenerateJWTRole(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token, err := GenerateJWT(9, "agent@example.com", utils.RoleAgent, 0, "")
+	require.NoError(t, err)
+	claims, err := DecodeTokenJWT(token)
+	require.NoError(t, err)
+	assert.Equal(t, utils.RoleAgent, roleClaim(claims))
+
+	_, err = GenerateJWT(9, "agent@example.com", utils.Role("SUPPORT"), 0, "")
+	assert.Error(t, err)
+}
+
+func TestRoleClaim(t *testing.T) {
+	assert.Equal(t, utils.RoleViewer, roleClaim(jwt.MapClaims{"role": float64(4)}))
+	assert.Empty(t, roleClaim(jwt.MapClaims{"role": float64(99)}))
+	assert.Empty(t, roleClaim(jwt.MapClaims{"role": "ADMIN"}))
+	assert.Empty(t, roleClaim(jwt.MapClaims{}))
+}
+
+func TestRequireRolesRejectsUnknownRoles(t *testing.T) {
+	assert.Panics(t, func() { RequireRoles(utils.RoleAdmin, utils.Role("OWNER")) })
+	assert.NotPanics(t, func() { RequireRoles(utils.RoleAdmin, utils.RoleManager) })
+}
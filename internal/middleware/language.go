@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"orderstreamrest/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupLanguage negocia o idioma das mensagens antes dos middlewares que podem responder com erro
+func setupLanguage(engine *gin.Engine) {
+	engine.Use(Language())
+}
+
+// Language escolhe o idioma das mensagens (en ou pt-BR) pelo header Accept-Language, guarda-o no
+// contexto para os construtores das respostas e o informa no header Content-Language
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Set(i18n.ContextKey, lang)
+
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
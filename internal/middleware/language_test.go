@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name             string
+		acceptLanguage   string
+		expectedLanguage string
+		expectedMessage  string
+	}{
+		{name: "Default is English", expectedLanguage: "en", expectedMessage: "User not found"},
+		{name: "Portuguese", acceptLanguage: "pt-BR,pt;q=0.9,en;q=0.8", expectedLanguage: "pt-BR", expectedMessage: "Usuário não encontrado"},
+		{name: "Preferred English", acceptLanguage: "en-US,pt-BR;q=0.5", expectedLanguage: "en", expectedMessage: "User not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(Language())
+			engine.GET("/users/:id", func(c *gin.Context) {
+				c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "User not found", nil))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedLanguage, w.Header().Get("Content-Language"))
+			assert.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+
+			var response dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedMessage, response.Message)
+		})
+	}
+}
@@ -4,20 +4,22 @@ import (
 	"bytes"
 	"io"
 	"orderstreamrest/pkg/logger"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 // setupLogger -
 func setupLogger(engine *gin.Engine, logger *logger.ElasticsearchLogger) {
+	// Bodies follow LOG_ENABLE_BODY and LOG_MAX_BODY_SIZE (off by default in production)
+	logBodies, maxBodySize := logger.BodyLogging()
 
 	middlewareConfig := MiddlewareConfig{
-		LogRequestBody:  true,
-		LogResponseBody: true,
-		MaxBodySize:     2048,
+		LogRequestBody:  logBodies,
+		LogResponseBody: logBodies,
+		MaxBodySize:     maxBodySize,
 		ExcludedHeaders: []string{
 			"authorization",
 			"cookie",
@@ -26,13 +28,33 @@ func setupLogger(engine *gin.Engine, logger *logger.ElasticsearchLogger) {
 		SkipPaths: []string{
 			"/health",
 			"/metrics",
+			"/prometheus",
+			"/ping",
+			"/healthcheck/live",
+			"/healthcheck/ready",
+			"/healthcheck/startup",
 		},
 		ErrorsOnly:      false,
 		RequestIDHeader: "X-Request-ID",
+		UserExtractor:   currentUserContext,
+		TraceExtractor:  finishTrace,
 	}
 	engine.Use(LoggerMiddleware(logger, middlewareConfig))
 }
 
+// currentUserContext identifies the authenticated user in the request log, so that
+// GET /admin/logs can filter by user; the e-mail is left out of the logs on purpose
+func currentUserContext(c *gin.Context) *logger.UserContext {
+	userID, ok := GetCurrentUserID(c)
+	if !ok {
+		return nil
+	}
+	return &logger.UserContext{
+		ID:   strconv.Itoa(userID),
+		Role: GetCurrentRole(c).String(),
+	}
+}
+
 // MiddlewareConfig configures the logging middleware
 type MiddlewareConfig struct {
 	// Whether to log request bodies
@@ -120,15 +142,8 @@ func LoggerMiddleware(esLogger *logger.ElasticsearchLogger, config ...Middleware
 
 		start := time.Now()
 
-		// Generate or extract request ID
-		requestID := c.GetHeader(cfg.RequestIDHeader)
-		if requestID == "" {
-			requestID = uuid.New().String()
-			c.Header(cfg.RequestIDHeader, requestID)
-		}
-
-		// Store request ID in context for use in handlers
-		c.Set("request_id", requestID)
+		// Reuse the request ID assigned by RequestIDMiddleware, so the log and the response share it
+		requestID := ensureRequestID(c, cfg.RequestIDHeader)
 
 		// Read request body if configured
 		var requestBody string
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryLimiterPruneEvery define a cada quantas verificações as janelas expiradas são removidas
+const memoryLimiterPruneEvery = 1000
+
+// memoryWindow é o contador de uma chave dentro da janela atual
+type memoryWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// memoryLimiter é um limitador de janela fixa local, usado enquanto o Redis está indisponível.
+// Os contadores valem só para esta instância da API.
+type memoryLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+	checks  int
+	now     func() time.Time
+}
+
+// newMemoryLimiter cria um limitador local vazio
+func newMemoryLimiter() *memoryLimiter {
+	return &memoryLimiter{
+		windows: make(map[string]*memoryWindow),
+		now:     time.Now,
+	}
+}
+
+// Allow conta a requisição no limite e informa se ela pode seguir e, se não, quanto esperar
+func (m *memoryLimiter) Allow(limit rateLimit) (bool, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	m.checks++
+	if m.checks%memoryLimiterPruneEvery == 0 {
+		m.prune(now)
+	}
+
+	window, ok := m.windows[limit.key]
+	if !ok || !now.Before(window.resetAt) {
+		m.windows[limit.key] = &memoryWindow{count: 1, resetAt: now.Add(limit.window)}
+		return true, 0
+	}
+
+	if window.count >= limit.maxRequests {
+		return false, window.resetAt.Sub(now)
+	}
+
+	window.count++
+	return true, 0
+}
+
+// prune remove as janelas expiradas
+func (m *memoryLimiter) prune(now time.Time) {
+	for key, window := range m.windows {
+		if !now.Before(window.resetAt) {
+			delete(m.windows, key)
+		}
+	}
+}
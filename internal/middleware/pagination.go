@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/url"
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page is the page requested by the client, already clamped
+type Page struct {
+	Number int
+	Size   int
+}
+
+// Offset is how many records come before the page
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// ParsePage reads the page and pageSize query parameters (page_size is accepted as well).
+// A missing or invalid page is 1, a missing or invalid size is defaultSize and sizes above maxSize are cut to maxSize.
+func ParsePage(c *gin.Context, defaultSize, maxSize int) Page {
+	number, err := strconv.Atoi(c.Query("page"))
+	if err != nil || number < 1 {
+		number = 1
+	}
+
+	value, ok := c.GetQuery("pageSize")
+	if !ok {
+		value = c.Query("page_size")
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 1 {
+		size = defaultSize
+	}
+	if size > maxSize {
+		size = maxSize
+	}
+
+	return Page{Number: number, Size: size}
+}
+
+// NewPagination describes the page within total records, with the links to the neighbouring pages
+func NewPagination(c *gin.Context, page Page, total int64) dto.Pagination {
+	pagination := dto.Pagination{
+		CurrentPage:  page.Number,
+		PerPage:      page.Size,
+		TotalPages:   int((total + int64(page.Size) - 1) / int64(page.Size)),
+		TotalRecords: total,
+		HasNext:      int64(page.Offset()+page.Size) < total,
+		HasPrev:      page.Number > 1,
+	}
+	pagination.Links = PaginationLinks(c, pagination)
+	return pagination
+}
+
+// PaginationLinks builds the self, next and prev links of the pagination from the request URL,
+// keeping every other query parameter so the filters carry over
+func PaginationLinks(c *gin.Context, pagination dto.Pagination) *dto.PaginationLinks {
+	return pageLinks(c, pagination)
+}
+
+// CursorPaginationLinks builds the links of a list that also pages with a cursor parameter: self keeps
+// the requested cursor, next follows pagination.NextCursor when there is one and the page links drop the cursor
+func CursorPaginationLinks(c *gin.Context, pagination dto.Pagination, param string) *dto.PaginationLinks {
+	links := pageLinks(c, pagination, param)
+	if cursor := c.Query(param); cursor != "" {
+		links.Self = cursorLink(c, param, cursor)
+	}
+	if pagination.NextCursor != "" {
+		links.Next = cursorLink(c, param, pagination.NextCursor)
+	}
+	return links
+}
+
+// pageLinks builds the page number links, removing the drop parameters from them
+func pageLinks(c *gin.Context, pagination dto.Pagination, drop ...string) *dto.PaginationLinks {
+	link := func(number int) string {
+		return requestLink(c, func(query url.Values) {
+			for _, param := range drop {
+				query.Del(param)
+			}
+			query.Set("page", strconv.Itoa(number))
+		})
+	}
+
+	links := &dto.PaginationLinks{Self: link(pagination.CurrentPage)}
+	if pagination.HasNext {
+		links.Next = link(pagination.CurrentPage + 1)
+	}
+	if pagination.HasPrev {
+		// A page past the end points back to the last one instead of to another empty page
+		prev := pagination.CurrentPage - 1
+		if pagination.TotalPages > 0 && prev > pagination.TotalPages {
+			prev = pagination.TotalPages
+		}
+		links.Prev = link(prev)
+	}
+	return links
+}
+
+// cursorLink is the request URL opening the page of the cursor
+func cursorLink(c *gin.Context, param, cursor string) string {
+	return requestLink(c, func(query url.Values) {
+		query.Del("page")
+		query.Set(param, cursor)
+	})
+}
+
+// requestLink is the request URL with the query parameters changed by set
+func requestLink(c *gin.Context, set func(query url.Values)) string {
+	query := c.Request.URL.Query()
+	set(query)
+	return (&url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}).String()
+}
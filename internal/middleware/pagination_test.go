@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected Page
+	}{
+		{name: "defaults", query: "", expected: Page{Number: 1, Size: 20}},
+		{name: "explicit values", query: "page=3&pageSize=50", expected: Page{Number: 3, Size: 50}},
+		{name: "snake case size", query: "page=2&page_size=30", expected: Page{Number: 2, Size: 30}},
+		{name: "size above max is clamped", query: "pageSize=1000", expected: Page{Number: 1, Size: 100}},
+		{name: "invalid values fall back", query: "page=-2&pageSize=abc", expected: Page{Number: 1, Size: 20}},
+		{name: "zero size falls back", query: "pageSize=0", expected: Page{Number: 1, Size: 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/v1/users?"+tt.query, nil)
+
+			assert.Equal(t, tt.expected, ParsePage(c, 20, 100))
+		})
+	}
+}
+
+func TestNewPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		query      string
+		total      int64
+		totalPages int
+		next       string
+		prev       string
+	}{
+		{name: "first page", query: "page=1&pageSize=10&onlyActive=true", total: 25, totalPages: 3, next: "/api/v1/users?onlyActive=true&page=2&pageSize=10"},
+		{name: "middle page", query: "page=2&pageSize=10", total: 25, totalPages: 3, next: "/api/v1/users?page=3&pageSize=10", prev: "/api/v1/users?page=1&pageSize=10"},
+		{name: "last page", query: "page=3&pageSize=10", total: 25, totalPages: 3, prev: "/api/v1/users?page=2&pageSize=10"},
+		{name: "past the end points back to the last page", query: "page=9&pageSize=10", total: 25, totalPages: 3, prev: "/api/v1/users?page=3&pageSize=10"},
+		{name: "empty result", query: "", total: 0, totalPages: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/v1/users?"+tt.query, nil)
+
+			pagination := NewPagination(c, ParsePage(c, 10, 100), tt.total)
+
+			assert.Equal(t, tt.totalPages, pagination.TotalPages)
+			assert.Equal(t, tt.total, pagination.TotalRecords)
+			assert.Equal(t, tt.next != "", pagination.HasNext)
+			assert.Equal(t, tt.prev != "", pagination.HasPrev)
+			require.NotNil(t, pagination.Links)
+			assert.Equal(t, tt.next, pagination.Links.Next)
+			assert.Equal(t, tt.prev, pagination.Links.Prev)
+			assert.Contains(t, pagination.Links.Self, "/api/v1/users?")
+		})
+	}
+}
+
+func TestCursorPaginationLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		query      string
+		pagination dto.Pagination
+		self       string
+		next       string
+		prev       string
+	}{
+		{
+			name:       "page request offers the next cursor",
+			query:      "q=boleto&page=1&page_size=50",
+			pagination: dto.Pagination{CurrentPage: 1, TotalPages: 3, HasNext: true, NextCursor: "abc"},
+			self:       "/api/v1/tickets/query?page=1&page_size=50&q=boleto",
+			next:       "/api/v1/tickets/query?cursor=abc&page_size=50&q=boleto",
+		},
+		{
+			name:       "cursor request keeps its cursor in self",
+			query:      "q=boleto&cursor=abc",
+			pagination: dto.Pagination{CurrentPage: 2, TotalPages: 3, HasNext: true, HasPrev: true, NextCursor: "def"},
+			self:       "/api/v1/tickets/query?cursor=abc&q=boleto",
+			next:       "/api/v1/tickets/query?cursor=def&q=boleto",
+			prev:       "/api/v1/tickets/query?page=1&q=boleto",
+		},
+		{
+			name:       "last page has no next link",
+			query:      "q=boleto&cursor=def",
+			pagination: dto.Pagination{CurrentPage: 3, TotalPages: 3, HasPrev: true},
+			self:       "/api/v1/tickets/query?cursor=def&q=boleto",
+			prev:       "/api/v1/tickets/query?page=2&q=boleto",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/v1/tickets/query?"+tt.query, nil)
+
+			links := CursorPaginationLinks(c, tt.pagination, "cursor")
+
+			assert.Equal(t, tt.self, links.Self)
+			assert.Equal(t, tt.next, links.Next)
+			assert.Equal(t, tt.prev, links.Prev)
+		})
+	}
+}
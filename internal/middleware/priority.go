@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"orderstreamrest/internal/models/dto"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	defaultMaxExportRequests  = 2
+	defaultExportQueueTimeout = 30 * time.Second
+
+	// interactiveSlotKey guarda no contexto a função que libera a vaga do semáforo global
+	interactiveSlotKey = "interactive_slot_release"
+	// exportJobKey guarda no contexto o job em execução no pool de baixa prioridade
+	exportJobKey = "export_job"
+)
+
+// ExportPool is the low-priority pool used by exports and reports
+var ExportPool *WorkerPool
+
+// WorkerPool limits how many low-priority requests run at the same time
+type WorkerPool struct {
+	name         string
+	max          int64
+	queueTimeout time.Duration
+	sema         *semaphore.Weighted
+	queued       atomic.Int64
+
+	mu   sync.Mutex
+	jobs map[string]*PoolJob
+}
+
+// PoolJob is a request running inside a worker pool
+type PoolJob struct {
+	RequestID string
+	Path      string
+	StartedAt time.Time
+	processed atomic.Int64
+	total     atomic.Int64
+}
+
+// NewWorkerPool creates a worker pool with its own concurrency cap
+func NewWorkerPool(name string, max int64, queueTimeout time.Duration) *WorkerPool {
+	return &WorkerPool{
+		name:         name,
+		max:          max,
+		queueTimeout: queueTimeout,
+		sema:         semaphore.NewWeighted(max),
+		jobs:         make(map[string]*PoolJob),
+	}
+}
+
+// setupWorkerPools creates the low-priority pools from the environment
+func setupWorkerPools() {
+	max := getEnvAsInt64("MAX_REQUEST_COUNT_EXPORT", defaultMaxExportRequests)
+	queueTimeout := time.Duration(getEnvAsInt64("EXPORT_QUEUE_TIMEOUT_SECONDS", int64(defaultExportQueueTimeout/time.Second))) * time.Second
+
+	ExportPool = NewWorkerPool("export", max, queueTimeout)
+}
+
+// Middleware moves the request from the interactive semaphore to the pool, waiting in queue up to the pool timeout
+func (p *WorkerPool) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Libera a vaga global para não competir com as requisições interativas
+		if release, ok := c.Get(interactiveSlotKey); ok {
+			if fn, ok := release.(func()); ok {
+				fn()
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), p.queueTimeout)
+		p.queued.Add(1)
+		err := p.sema.Acquire(ctx, 1)
+		p.queued.Add(-1)
+		cancel()
+
+		if err != nil {
+			AbortWithRetry(
+				c,
+				http.StatusServiceUnavailable,
+				dto.ReasonQueueTimeout,
+				"Too many exports in progress, try again later",
+				p.queueTimeout,
+				0,
+			)
+			return
+		}
+		defer p.sema.Release(1)
+
+		job := &PoolJob{
+			RequestID: GetRequestID(c),
+			Path:      c.FullPath(),
+			StartedAt: time.Now().UTC(),
+		}
+
+		p.mu.Lock()
+		p.jobs[job.RequestID] = job
+		p.mu.Unlock()
+
+		defer func() {
+			p.mu.Lock()
+			delete(p.jobs, job.RequestID)
+			p.mu.Unlock()
+		}()
+
+		c.Set(exportJobKey, job)
+		c.Next()
+	}
+}
+
+// Stats returns the current usage of the pool and the progress of its jobs
+func (p *WorkerPool) Stats() dto.WorkerPoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := dto.WorkerPoolStats{
+		Name:    p.name,
+		Max:     p.max,
+		Running: int64(len(p.jobs)),
+		Queued:  p.queued.Load(),
+		Jobs:    make([]dto.WorkerPoolJob, 0, len(p.jobs)),
+	}
+
+	for _, job := range p.jobs {
+		stats.Jobs = append(stats.Jobs, dto.WorkerPoolJob{
+			RequestID: job.RequestID,
+			Path:      job.Path,
+			StartedAt: job.StartedAt,
+			Processed: job.processed.Load(),
+			Total:     job.total.Load(),
+		})
+	}
+
+	sort.Slice(stats.Jobs, func(i, j int) bool {
+		return stats.Jobs[i].StartedAt.Before(stats.Jobs[j].StartedAt)
+	})
+
+	return stats
+}
+
+// ReportProgress updates the progress of the pool job running the request.
+// Total may be zero when it is not known in advance.
+func ReportProgress(c *gin.Context, processed, total int64) {
+	value, ok := c.Get(exportJobKey)
+	if !ok {
+		return
+	}
+
+	if job, ok := value.(*PoolJob); ok {
+		job.processed.Store(processed)
+		job.total.Store(total)
+	}
+}
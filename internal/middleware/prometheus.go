@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/pkg/logger"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute agrupa as requisições sem rota, para não criar uma série por URL
+const unmatchedRoute = "unmatched"
+
+var (
+	metricsRegistry = prometheus.NewRegistry()
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route and status code.",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "route", "status"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		requestDuration,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	metricsRegistry.MustRegister(elsearch.QueryCollectors()...)
+	metricsRegistry.MustRegister(logger.Collectors()...)
+}
+
+// PrometheusHandler expõe as métricas do servidor. O formato OpenMetrics é necessário para
+// que os exemplars (trace_id) cheguem ao Prometheus.
+func PrometheusHandler() gin.HandlerFunc {
+	handler := promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+	return gin.WrapH(handler)
+}
+
+// observeRequest registra a latência da requisição. Quando o trace foi amostrado, o trace_id
+// é anexado como exemplar ao bucket, ligando um pico de latência a uma requisição concreta.
+func observeRequest(c *gin.Context, duration time.Duration, trace *logger.TraceContext) {
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	observer := requestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+
+	if trace != nil && trace.Sampled {
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+			exemplarObserver.ObserveWithExemplar(duration.Seconds(), prometheus.Labels{"trace_id": trace.TraceID})
+			return
+		}
+	}
+
+	observer.Observe(duration.Seconds())
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"orderstreamrest/internal/config"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupTrustedProxies configures which proxies Gin trusts to set X-Forwarded-For and X-Real-IP, from
+// TRUSTED_PROXIES (comma-separated IPs or CIDRs). ClientIP(), and so the per-IP rate limit, the rate limit
+// exemptions and the IP filter, only reads those headers when the request comes from one of them.
+// Unset, no proxy is trusted and the client IP is the socket address.
+func setupTrustedProxies(engine *gin.Engine, cfg *config.App) {
+	proxies := splitList(os.Getenv("TRUSTED_PROXIES"))
+	if len(proxies) == 0 {
+		cfg.Logger.Warn("TRUSTED_PROXIES is not set: X-Forwarded-For is ignored and the client IP is the socket address")
+	}
+
+	if err := trustProxies(engine, proxies); err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Invalid TRUSTED_PROXIES, forwarded headers are ignored: %v", err))
+	}
+}
+
+// trustProxies makes Gin trust the forwarded headers only from the given proxies. An empty list, or an
+// invalid one, trusts no proxy: Gin's own default trusts every client, which would let anyone pick its IP.
+func trustProxies(engine *gin.Engine, proxies []string) error {
+	if len(proxies) == 0 {
+		return engine.SetTrustedProxies(nil)
+	}
+
+	if err := engine.SetTrustedProxies(proxies); err != nil {
+		// A typo must not leave the forwarded headers open to every client
+		_ = engine.SetTrustedProxies(nil)
+		return err
+	}
+	return nil
+}
+
+// splitList splits a comma-separated setting, dropping blank items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseNetworks parses IPs and CIDRs; a single IP becomes a /32 (or /128) network.
+// Invalid items are returned apart so the caller can report them.
+func parseNetworks(items []string) (networks []*net.IPNet, invalid []string) {
+	for _, item := range items {
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				invalid = append(invalid, item)
+				continue
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			invalid = append(invalid, item)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks, invalid
+}
+
+// containsIP reports whether ip belongs to one of the networks
+func containsIP(networks []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
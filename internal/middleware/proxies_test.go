@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		proxies     []string
+		remoteAddr  string
+		forwarded   string
+		expectedIP  string
+		expectError bool
+	}{
+		{
+			name:       "Forged X-Forwarded-For is ignored by default",
+			remoteAddr: "203.0.113.9:4000",
+			forwarded:  "10.0.0.5",
+			expectedIP: "203.0.113.9",
+		},
+		{
+			name:       "Trusted proxy forwards the client IP",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:4000",
+			forwarded:  "198.51.100.7",
+			expectedIP: "198.51.100.7",
+		},
+		{
+			name:       "Forwarded header from outside the trusted proxies is ignored",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.9:4000",
+			forwarded:  "10.0.0.5",
+			expectedIP: "203.0.113.9",
+		},
+		{
+			name:        "Invalid proxies trust nobody",
+			proxies:     []string{"load-balancer"},
+			remoteAddr:  "203.0.113.9:4000",
+			forwarded:   "10.0.0.5",
+			expectedIP:  "203.0.113.9",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			err := trustProxies(engine, tt.proxies)
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var clientIP string
+			engine.GET("/ping", func(c *gin.Context) {
+				clientIP = c.ClientIP()
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+			engine.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expectedIP, clientIP)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	redisInternal "orderstreamrest/internal/repositories/redis"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultExportQuota     = 50
+	defaultBulkImportQuota = 20
+	defaultReindexQuota    = 5
+
+	// quotaKeyPrefix fica fora de rateLimitKeyPrefix: os contadores e as cotas próprias sobrevivem aos restarts
+	quotaKeyPrefix = "quota:"
+	// quotaTimeout limita cada chamada ao Redis feita pelo middleware de cotas
+	quotaTimeout = 500 * time.Millisecond
+
+	quotaLimitHeader     = "X-Quota-Limit"
+	quotaRemainingHeader = "X-Quota-Remaining"
+	quotaResetHeader     = "X-Quota-Reset"
+)
+
+// QuotaAction é uma operação cara com cota diária por usuário
+type QuotaAction string
+
+const (
+	// QuotaExport conta as exportações de tickets e usuários
+	QuotaExport QuotaAction = "export"
+	// QuotaBulkImport conta as importações em lote de tickets e usuários
+	QuotaBulkImport QuotaAction = "bulk_import"
+	// QuotaReindex conta as migrações de índices do Elasticsearch
+	QuotaReindex QuotaAction = "reindex"
+)
+
+// QuotaActions lista as operações com cota na ordem em que aparecem no endpoint administrativo
+var QuotaActions = []QuotaAction{QuotaExport, QuotaBulkImport, QuotaReindex}
+
+// Valid indica se a operação tem cota
+func (a QuotaAction) Valid() bool {
+	for _, action := range QuotaActions {
+		if action == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Quotas guarda as cotas diárias por usuário; nil desativa o middleware Quota (ex.: nos testes)
+var Quotas *QuotaManager
+
+// QuotaManager conta no Redis o uso diário das operações caras de cada usuário. O dia é o dia UTC:
+// os contadores levam a data na chave e expiram à meia-noite UTC. Um administrador pode dar a um
+// usuário uma cota própria, guardada no hash quota:limits:<id>.
+type QuotaManager struct {
+	redis    *redisInternal.RedisInternal
+	defaults map[QuotaAction]int
+	now      func() time.Time
+}
+
+// quotaStatus é o uso de uma cota depois de contar a requisição
+type quotaStatus struct {
+	limit    int
+	used     int64
+	exceeded bool
+	resetAt  time.Time
+}
+
+// NewQuotaManager cria o contador de cotas com os limites padrão de cada operação (0 desativa a cota)
+func NewQuotaManager(redisClient *redisInternal.RedisInternal, defaults map[QuotaAction]int) *QuotaManager {
+	return &QuotaManager{
+		redis:    redisClient,
+		defaults: defaults,
+		now:      time.Now,
+	}
+}
+
+// LoadQuotaLimits lê os limites diários padrão das variáveis de ambiente; valores negativos desativam a cota
+func LoadQuotaLimits() map[QuotaAction]int {
+	limits := map[QuotaAction]int{
+		QuotaExport:     int(getEnvAsInt64("QUOTA_EXPORTS_PER_DAY", defaultExportQuota)),
+		QuotaBulkImport: int(getEnvAsInt64("QUOTA_BULK_IMPORTS_PER_DAY", defaultBulkImportQuota)),
+		QuotaReindex:    int(getEnvAsInt64("QUOTA_REINDEX_PER_DAY", defaultReindexQuota)),
+	}
+	for action, limit := range limits {
+		if limit < 0 {
+			limits[action] = 0
+		}
+	}
+	return limits
+}
+
+// setupQuotas habilita as cotas diárias do middleware Quota
+func setupQuotas(cfg *config.App) {
+	Quotas = NewQuotaManager(cfg.Redis, LoadQuotaLimits())
+}
+
+// Quota conta a requisição na cota diária do usuário autenticado para a operação e responde 429 quando
+// ela acabou. Deve vir depois de Auth e antes dos pools de baixa prioridade, para que a requisição recusada
+// não espere na fila. A cota é flexível: sem usuário ou com o Redis indisponível a requisição passa sem ser
+// contada. Requisições que o handler recusa com 4xx não consomem a cota.
+func Quota(action QuotaAction) gin.HandlerFunc {
+	if !action.Valid() {
+		panic(fmt.Sprintf("Quota: unknown action %q", action))
+	}
+
+	return func(c *gin.Context) {
+		quotas := Quotas
+		userID, ok := GetCurrentUserID(c)
+		if quotas == nil || quotas.redis == nil || !ok {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), quotaTimeout)
+		status, err := quotas.consume(ctx, userID, action)
+		cancel()
+		if err != nil {
+			log.Printf("Quota: Redis unavailable (%v), not counting %s for user %d", err, action, userID)
+			c.Next()
+			return
+		}
+
+		setQuotaHeaders(c, status)
+		if status.exceeded {
+			AbortWithRetry(
+				c,
+				http.StatusTooManyRequests,
+				dto.ReasonQuotaExceeded,
+				"Daily quota exceeded",
+				status.resetAt.Sub(quotas.now()),
+				0,
+			)
+			return
+		}
+
+		c.Next()
+
+		if code := c.Writer.Status(); code >= http.StatusBadRequest && code < http.StatusInternalServerError {
+			ctx, cancel := context.WithTimeout(context.Background(), quotaTimeout)
+			defer cancel()
+			if err := quotas.redis.Decr(ctx, quotas.usageKey(userID, action, status.resetAt)).Err(); err != nil {
+				log.Printf("Quota: failed to give back %s to user %d: %v", action, userID, err)
+			}
+		}
+	}
+}
+
+// setQuotaHeaders informa a cota, quanto resta e quando ela volta; operações sem cota não têm headers
+func setQuotaHeaders(c *gin.Context, status quotaStatus) {
+	if status.limit == 0 {
+		return
+	}
+
+	remaining := int64(status.limit) - status.used
+	if remaining < 0 {
+		remaining = 0
+	}
+	c.Header(quotaLimitHeader, strconv.Itoa(status.limit))
+	c.Header(quotaRemainingHeader, strconv.FormatInt(remaining, 10))
+	c.Header(quotaResetHeader, status.resetAt.Format(time.RFC3339))
+}
+
+// consume incrementa o contador do dia e, se a cota já acabou, desfaz o incremento
+func (q *QuotaManager) consume(ctx context.Context, userID int, action QuotaAction) (quotaStatus, error) {
+	limit, err := q.limit(ctx, userID, action)
+	if err != nil {
+		return quotaStatus{}, err
+	}
+
+	status := quotaStatus{limit: limit, resetAt: quotaResetAt(q.now())}
+	key := q.usageKey(userID, action, status.resetAt)
+
+	status.used, err = q.redis.Incr(ctx, key).Result()
+	if err != nil {
+		return quotaStatus{}, err
+	}
+	if status.used == 1 {
+		// A chave já leva a data; a expiração só evita acumular contadores de dias passados
+		if err := q.redis.Expire(ctx, key, status.resetAt.Sub(q.now())+time.Minute).Err(); err != nil {
+			return quotaStatus{}, err
+		}
+	}
+
+	if limit > 0 && status.used > int64(limit) {
+		status.exceeded = true
+		status.used--
+		if err := q.redis.Decr(ctx, key).Err(); err != nil {
+			return quotaStatus{}, err
+		}
+	}
+	return status, nil
+}
+
+// limit retorna a cota do usuário na operação: a cota própria, se houver, ou o limite padrão
+func (q *QuotaManager) limit(ctx context.Context, userID int, action QuotaAction) (int, error) {
+	overrides, err := q.redis.HGetAll(ctx, q.limitsKey(userID)).Result()
+	if err != nil {
+		return 0, err
+	}
+	limit, _ := effectiveQuota(q.defaults[action], overrides[string(action)])
+	return limit, nil
+}
+
+// Usage retorna as cotas do usuário e o consumo do dia em cada operação
+func (q *QuotaManager) Usage(ctx context.Context, userID int) ([]dto.QuotaUsage, error) {
+	overrides, err := q.redis.HGetAll(ctx, q.limitsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	resetAt := quotaResetAt(q.now())
+	usage := make([]dto.QuotaUsage, 0, len(QuotaActions))
+	for _, action := range QuotaActions {
+		used, err := q.redis.Get(ctx, q.usageKey(userID, action, resetAt)).Int64()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+
+		limit, custom := effectiveQuota(q.defaults[action], overrides[string(action)])
+		item := dto.QuotaUsage{
+			Action:       string(action),
+			Limit:        limit,
+			DefaultLimit: q.defaults[action],
+			Custom:       custom,
+			Used:         used,
+			ResetAt:      resetAt,
+		}
+		if limit > 0 {
+			remaining := int64(limit) - used
+			if remaining < 0 {
+				remaining = 0
+			}
+			item.Remaining = &remaining
+		}
+		usage = append(usage, item)
+	}
+	return usage, nil
+}
+
+// SetLimit dá ao usuário uma cota própria na operação; nil volta ao limite padrão
+func (q *QuotaManager) SetLimit(ctx context.Context, userID int, action QuotaAction, limit *int) error {
+	if limit == nil {
+		return q.redis.HDel(ctx, q.limitsKey(userID), string(action)).Err()
+	}
+	return q.redis.HSet(ctx, q.limitsKey(userID), string(action), *limit).Err()
+}
+
+// ResetUsage zera o consumo do dia do usuário na operação
+func (q *QuotaManager) ResetUsage(ctx context.Context, userID int, action QuotaAction) error {
+	return q.redis.Del(ctx, q.usageKey(userID, action, quotaResetAt(q.now()))).Err()
+}
+
+// usageKey é o contador do usuário na operação no dia que termina em resetAt
+func (q *QuotaManager) usageKey(userID int, action QuotaAction, resetAt time.Time) string {
+	day := resetAt.AddDate(0, 0, -1).Format(time.DateOnly)
+	return fmt.Sprintf("%susage:%s:%d:%s", quotaKeyPrefix, action, userID, day)
+}
+
+// limitsKey é o hash com as cotas próprias do usuário
+func (q *QuotaManager) limitsKey(userID int) string {
+	return fmt.Sprintf("%slimits:%d", quotaKeyPrefix, userID)
+}
+
+// quotaResetAt retorna a próxima meia-noite UTC, quando os contadores do dia deixam de valer
+func quotaResetAt(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
+
+// effectiveQuota aplica a cota própria guardada no Redis sobre o limite padrão; valores inválidos são ignorados
+func effectiveQuota(defaultLimit int, override string) (int, bool) {
+	if override == "" {
+		return defaultLimit, false
+	}
+	limit, err := strconv.Atoi(override)
+	if err != nil || limit < 0 {
+		return defaultLimit, false
+	}
+	return limit, true
+}
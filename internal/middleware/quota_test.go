@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaResetAt(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "Middle of the day",
+			now:      time.Date(2025, time.October, 16, 15, 4, 5, 0, time.UTC),
+			expected: time.Date(2025, time.October, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Exactly midnight starts a new day",
+			now:      time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, time.October, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Last day of the year",
+			now:      time.Date(2025, time.December, 31, 23, 59, 59, 0, time.UTC),
+			expected: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Local evening is already the next UTC day",
+			now:      time.Date(2025, time.October, 16, 22, 0, 0, 0, saoPaulo),
+			expected: time.Date(2025, time.October, 18, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, quotaResetAt(tt.now))
+		})
+	}
+}
+
+func TestQuotaUsageKey(t *testing.T) {
+	q := NewQuotaManager(nil, nil)
+	resetAt := quotaResetAt(time.Date(2025, time.October, 16, 15, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "quota:usage:export:42:2025-10-16", q.usageKey(42, QuotaExport, resetAt))
+	assert.Equal(t, "quota:limits:42", q.limitsKey(42))
+}
+
+func TestEffectiveQuota(t *testing.T) {
+	tests := []struct {
+		name           string
+		override       string
+		expectedLimit  int
+		expectedCustom bool
+	}{
+		{name: "No override uses the default", override: "", expectedLimit: 50},
+		{name: "Override raises the quota", override: "200", expectedLimit: 200, expectedCustom: true},
+		{name: "Zero override removes the quota", override: "0", expectedLimit: 0, expectedCustom: true},
+		{name: "Invalid override is ignored", override: "many", expectedLimit: 50},
+		{name: "Negative override is ignored", override: "-1", expectedLimit: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, custom := effectiveQuota(50, tt.override)
+			assert.Equal(t, tt.expectedLimit, limit)
+			assert.Equal(t, tt.expectedCustom, custom)
+		})
+	}
+}
+
+func TestLoadQuotaLimits(t *testing.T) {
+	t.Setenv("QUOTA_EXPORTS_PER_DAY", "")
+	t.Setenv("QUOTA_BULK_IMPORTS_PER_DAY", "0")
+	t.Setenv("QUOTA_REINDEX_PER_DAY", "-3")
+
+	assert.Equal(t, map[QuotaAction]int{
+		QuotaExport:     defaultExportQuota,
+		QuotaBulkImport: 0,
+		QuotaReindex:    0,
+	}, LoadQuotaLimits())
+}
+
+func TestSetQuotaHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resetAt := time.Date(2025, time.October, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		status            quotaStatus
+		expectedLimit     string
+		expectedRemaining string
+		expectedReset     string
+	}{
+		{
+			name:              "Quota with room left",
+			status:            quotaStatus{limit: 50, used: 12, resetAt: resetAt},
+			expectedLimit:     "50",
+			expectedRemaining: "38",
+			expectedReset:     "2025-10-17T00:00:00Z",
+		},
+		{
+			name:              "Exhausted quota",
+			status:            quotaStatus{limit: 5, used: 5, exceeded: true, resetAt: resetAt},
+			expectedLimit:     "5",
+			expectedRemaining: "0",
+			expectedReset:     "2025-10-17T00:00:00Z",
+		},
+		{
+			name:   "Action without quota has no headers",
+			status: quotaStatus{limit: 0, used: 300, resetAt: resetAt},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			setQuotaHeaders(c, tt.status)
+
+			assert.Equal(t, tt.expectedLimit, w.Header().Get(quotaLimitHeader))
+			assert.Equal(t, tt.expectedRemaining, w.Header().Get(quotaRemainingHeader))
+			assert.Equal(t, tt.expectedReset, w.Header().Get(quotaResetHeader))
+		})
+	}
+}
+
+func TestQuota_PassesWithoutStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	previous := Quotas
+	t.Cleanup(func() { Quotas = previous })
+
+	tests := []struct {
+		name   string
+		quotas *QuotaManager
+		user   bool
+	}{
+		{name: "Quotas not configured", quotas: nil, user: true},
+		{name: "Redis not connected", quotas: NewQuotaManager(nil, map[QuotaAction]int{QuotaExport: 1}), user: true},
+		{name: "Anonymous request", quotas: NewQuotaManager(nil, map[QuotaAction]int{QuotaExport: 1})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Quotas = tt.quotas
+			router := gin.New()
+			router.GET("/tickets/export", func(c *gin.Context) {
+				if tt.user {
+					c.Set("currentUser", jwt.MapClaims{"user_id": float64(7)})
+				}
+				c.Next()
+			}, Quota(QuotaExport), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tickets/export", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get(quotaLimitHeader))
+		})
+	}
+}
+
+func TestQuota_UnknownActionPanics(t *testing.T) {
+	assert.Panics(t, func() { Quota("download") })
+}
@@ -16,12 +16,24 @@ func SetupServer(rd *config.App) (engine *gin.Engine) {
 
 	gin.SetMode(gin.ReleaseMode)
 	engine = gin.New()
+	setupTrustedProxies(engine, rd)
 
+	setupValidators()
+	setupIds(engine)
+	setupLanguage(engine)
+	setupTracing(engine)
 	setupSemaphore(engine)
+	setupWorkerPools()
 	setupCors(engine)
 	setupRedisDB(engine, rd)
+	setupTokenBlacklist(rd)
+	setupIdempotency(rd)
+	setupQuotas(rd)
+	setupIPFilter(rd)
 	setupLogger(engine, rd.Logger)
-	setupIds(engine)
+	setupDeprecations(engine, rd)
+	setupCompression(engine)
+	setupErrors(engine)
 
 	certFile, keyFile := utils.GetCertFiles()
 	if certFile != "" && keyFile != "" {
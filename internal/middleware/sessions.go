@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// sessionKeyPrefix prefixes the Redis key holding one session
+	sessionKeyPrefix = "auth:session:"
+	// userSessionsKeyPrefix prefixes the Redis set with the session IDs of a user
+	userSessionsKeyPrefix = "auth:sessions:user:"
+	// sessionTouchInterval is how stale LastSeenAt may get before Auth rewrites it
+	sessionTouchInterval = 1 * time.Minute
+)
+
+// Session is a login tracked in Redis. Each token carries the session ID in the sid claim
+// and is rejected by Auth once its session is revoked.
+type Session struct {
+	Id         string    `json:"id"`
+	UserId     int       `json:"userId"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	IssuedAt   time.Time `json:"issuedAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// StartSession registers a new session for the user, to be embedded in the token by GenerateJWT.
+// It returns an error when sessions are unavailable; the caller may then issue a token without one.
+func StartSession(ctx context.Context, userID int, ipAddress, userAgent string) (*Session, error) {
+	if tokenBlacklist == nil {
+		return nil, errors.New("session store not configured")
+	}
+
+	now := time.Now()
+	session := &Session{
+		Id:         uuid.NewString(),
+		UserId:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		IssuedAt:   now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(TokenLifetime()),
+	}
+
+	if err := saveSession(ctx, session, TokenLifetime()); err != nil {
+		return nil, err
+	}
+
+	userKey := userSessionsKey(userID)
+	if err := tokenBlacklist.SAdd(ctx, userKey, session.Id).Err(); err != nil {
+		return nil, fmt.Errorf("indexing session: %w", err)
+	}
+	// The index lives as long as the newest session
+	if err := tokenBlacklist.Expire(ctx, userKey, TokenLifetime()).Err(); err != nil {
+		return nil, fmt.Errorf("indexing session: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetSession returns the session, or nil when it does not exist, expired or was revoked
+func GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	if tokenBlacklist == nil {
+		return nil, nil
+	}
+
+	value, err := tokenBlacklist.Get(ctx, sessionKey(sessionID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, fmt.Errorf("decoding session: %w", err)
+	}
+	return &session, nil
+}
+
+// ListSessions returns the active sessions of the user, newest first
+func ListSessions(ctx context.Context, userID int) ([]Session, error) {
+	if tokenBlacklist == nil {
+		return []Session{}, nil
+	}
+
+	userKey := userSessionsKey(userID)
+	ids, err := tokenBlacklist.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := GetSession(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			// Sessão expirada: remove do índice
+			tokenBlacklist.SRem(ctx, userKey, id)
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].IssuedAt.After(sessions[j].IssuedAt)
+	})
+	return sessions, nil
+}
+
+// RevokeSession ends the session; its token is rejected from the next request on
+func RevokeSession(ctx context.Context, session *Session) error {
+	if tokenBlacklist == nil {
+		return nil
+	}
+
+	if err := tokenBlacklist.Del(ctx, sessionKey(session.Id)).Err(); err != nil {
+		return err
+	}
+	return tokenBlacklist.SRem(ctx, userSessionsKey(session.UserId), session.Id).Err()
+}
+
+// revokeUserSessions ends every session of the user
+func revokeUserSessions(ctx context.Context, userID int) error {
+	userKey := userSessionsKey(userID)
+	ids, err := tokenBlacklist.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, sessionKey(id))
+	}
+	keys = append(keys, userKey)
+	return tokenBlacklist.Del(ctx, keys...).Err()
+}
+
+// GetCurrentSessionID returns the session of the token used in the request
+func GetCurrentSessionID(c *gin.Context) (string, bool) {
+	claims, ok := c.Get("currentUser")
+	if !ok {
+		return "", false
+	}
+
+	mapClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return "", false
+	}
+
+	sessionID, ok := mapClaims["sid"].(string)
+	return sessionID, ok && sessionID != ""
+}
+
+// isSessionActive reports whether the session of the token still exists, refreshing its
+// LastSeenAt. Tokens without a session and Redis failures are let through, as in isTokenRevoked.
+func isSessionActive(ctx context.Context, claims jwt.MapClaims) bool {
+	sessionID, ok := claims["sid"].(string)
+	if !ok || sessionID == "" || tokenBlacklist == nil {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, blacklistTimeout)
+	defer cancel()
+
+	session, err := GetSession(ctx, sessionID)
+	if err != nil {
+		log.Printf("Failed to check session: %v", err)
+		return true
+	}
+	if session == nil {
+		return false
+	}
+
+	if now := time.Now(); now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		session.LastSeenAt = now
+		if err := saveSession(ctx, session, redis.KeepTTL); err != nil {
+			log.Printf("Failed to update session last seen: %v", err)
+		}
+	}
+	return true
+}
+
+func saveSession(ctx context.Context, session *Session, expiration time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("encoding session: %w", err)
+	}
+	if err := tokenBlacklist.Set(ctx, sessionKey(session.Id), data, expiration).Err(); err != nil {
+		return fmt.Errorf("storing session: %w", err)
+	}
+	return nil
+}
+
+func sessionKey(sessionID string) string {
+	return sessionKeyPrefix + sessionID
+}
+
+func userSessionsKey(userID int) string {
+	return fmt.Sprintf("%s%d", userSessionsKeyPrefix, userID)
+}
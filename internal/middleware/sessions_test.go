@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"orderstreamrest/internal/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCurrentSessionID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	tests := []struct {
+		name      string
+		sessionID string
+		ok        bool
+	}{
+		{name: "token with session", sessionID: "3f2b8c1e-4d5a-4b6c-9e7f-1a2b3c4d5e6f", ok: true},
+		{name: "token without session", sessionID: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := GenerateJWT(7, "admin@example.com", utils.RoleAdmin, 0, tt.sessionID)
+			require.NoError(t, err)
+			claims, err := DecodeTokenJWT(token)
+			require.NoError(t, err)
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set("currentUser", claims)
+
+			sessionID, ok := GetCurrentSessionID(c)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.sessionID, sessionID)
+			assert.True(t, isSessionActive(c, claims), "sessions are not checked without a store")
+		})
+	}
+}
@@ -2,13 +2,19 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
 	"orderstreamrest/internal/config"
 	"orderstreamrest/internal/models/dto"
 	redisInternal "orderstreamrest/internal/repositories/redis"
+	"orderstreamrest/internal/utils"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,36 +23,158 @@ import (
 )
 
 const (
-	defaultMaxRequests = 1500
-	rateLimitWindow    = 60 * time.Second
+	defaultMaxRequests      = 1500
+	defaultMaxUserRequests  = 1500
+	defaultMaxAdminRequests = 5000
+	defaultMaxLoginRequests = 10
+	rateLimitWindow         = 60 * time.Second
+	rateLimitKeyPrefix      = "ratelimit:"
+
+	defaultRateLimitRedisTimeout    = 200 * time.Millisecond
+	defaultRateLimitBreakerFailures = 5
+	defaultRateLimitBreakerCooldown = 30 * time.Second
+
+	// semaphoreRetryAfter é a espera sugerida quando o limite de requisições simultâneas é atingido
+	semaphoreRetryAfter = 60 * time.Second
 )
 
-// RateLimiter encapsula a lógica de rate limiting
-type RateLimiter struct {
-	redis       *redisInternal.RedisInternal
+// RateLimitFailureMode define o que o rate limiter faz quando o Redis está indisponível
+type RateLimitFailureMode string
+
+const (
+	// RateLimitFailMemory passa a contar as requisições em memória, por instância (padrão)
+	RateLimitFailMemory RateLimitFailureMode = "memory"
+	// RateLimitFailOpen deixa todas as requisições passarem sem limite
+	RateLimitFailOpen RateLimitFailureMode = "open"
+	// RateLimitFailClosed recusa as requisições com 503
+	RateLimitFailClosed RateLimitFailureMode = "closed"
+)
+
+// rateLimitExemptPaths são as rotas das probes do Kubernetes e do Docker, que não podem ser barradas pelo limite do IP do kubelet
+var rateLimitExemptPaths = map[string]bool{
+	"/ping":                true,
+	"/healthcheck/live":    true,
+	"/healthcheck/ready":   true,
+	"/healthcheck/startup": true,
+}
+
+// errRateLimiterUnavailable indica que o Redis está indisponível e o modo é fail-closed
+var errRateLimiterUnavailable = errors.New("rate limiter unavailable")
+
+// RouteRateLimit é um limite adicional de uma rota, contado por usuário (ou IP, se anônimo)
+type RouteRateLimit struct {
+	Method      string // Método HTTP; vazio vale para todos
+	Path        string // Rota do Gin (ex.: /auth/login)
+	MaxRequests int
+	Window      time.Duration
+}
+
+// RateLimitConfig define os limites do rate limiter. Todo cliente tem um limite geral:
+// por usuário quando envia um JWT válido (com cota maior para ADMIN) ou por IP quando anônimo.
+// As rotas em Routes têm ainda um limite próprio.
+type RateLimitConfig struct {
+	IPRequests    int
+	UserRequests  int
+	AdminRequests int
+	Window        time.Duration
+	Routes        []RouteRateLimit
+
+	// FailureMode, RedisTimeout e o circuit breaker controlam o comportamento quando o Redis falha:
+	// depois de BreakerFailures erros seguidos o Redis deixa de ser consultado por BreakerCooldown
+	FailureMode     RateLimitFailureMode
+	RedisTimeout    time.Duration
+	BreakerFailures int
+	BreakerCooldown time.Duration
+
+	// ExemptNetworks e ExemptUsers não passam pelo rate limiting (probes, ETL interno, contas de serviço)
+	ExemptNetworks []*net.IPNet
+	ExemptUsers    map[int64]bool
+}
+
+// LoadRateLimitConfig lê os limites das variáveis de ambiente
+func LoadRateLimitConfig() RateLimitConfig {
+	exemptNetworks, invalid := parseNetworks(splitList(os.Getenv("RATE_LIMIT_EXEMPT_IPS")))
+	if len(invalid) > 0 {
+		log.Printf("Rate limiter: ignoring invalid RATE_LIMIT_EXEMPT_IPS entries %v", invalid)
+	}
+
+	return RateLimitConfig{
+		IPRequests:    int(getEnvAsInt64("MAX_REQUEST_COUNT_BY_IP", defaultMaxRequests)),
+		UserRequests:  int(getEnvAsInt64("MAX_REQUEST_COUNT_BY_USER", defaultMaxUserRequests)),
+		AdminRequests: int(getEnvAsInt64("MAX_REQUEST_COUNT_ADMIN", defaultMaxAdminRequests)),
+		Window:        rateLimitWindow,
+		Routes: []RouteRateLimit{
+			// Limite apertado para dificultar tentativas de senha
+			{Method: http.MethodPost, Path: "/auth/login", MaxRequests: int(getEnvAsInt64("MAX_REQUEST_COUNT_LOGIN", defaultMaxLoginRequests)), Window: rateLimitWindow},
+			{Method: http.MethodPost, Path: "/auth/2fa/login", MaxRequests: int(getEnvAsInt64("MAX_REQUEST_COUNT_LOGIN", defaultMaxLoginRequests)), Window: rateLimitWindow},
+		},
+		FailureMode:     parseRateLimitFailureMode(os.Getenv("RATE_LIMIT_FAILURE_MODE")),
+		RedisTimeout:    time.Duration(getEnvAsInt64("RATE_LIMIT_REDIS_TIMEOUT_MS", defaultRateLimitRedisTimeout.Milliseconds())) * time.Millisecond,
+		BreakerFailures: int(getEnvAsInt64("RATE_LIMIT_BREAKER_FAILURES", defaultRateLimitBreakerFailures)),
+		BreakerCooldown: time.Duration(getEnvAsInt64("RATE_LIMIT_BREAKER_COOLDOWN_SECONDS", int64(defaultRateLimitBreakerCooldown.Seconds()))) * time.Second,
+		ExemptNetworks:  exemptNetworks,
+		ExemptUsers:     parseExemptUsers(os.Getenv("RATE_LIMIT_EXEMPT_USERS")),
+	}
+}
+
+// parseExemptUsers lê os IDs de usuário separados por vírgula; IDs inválidos são ignorados
+func parseExemptUsers(value string) map[int64]bool {
+	users := make(map[int64]bool)
+	for _, item := range splitList(value) {
+		id, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			log.Printf("Rate limiter: ignoring invalid RATE_LIMIT_EXEMPT_USERS entry %q", item)
+			continue
+		}
+		users[id] = true
+	}
+	return users
+}
+
+// parseRateLimitFailureMode lê o modo de falha; valores desconhecidos usam o limitador em memória
+func parseRateLimitFailureMode(value string) RateLimitFailureMode {
+	switch mode := RateLimitFailureMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case RateLimitFailOpen, RateLimitFailClosed:
+		return mode
+	default:
+		return RateLimitFailMemory
+	}
+}
+
+// rateLimit é um limite aplicado a uma requisição, já com a chave do contador
+type rateLimit struct {
+	key         string
 	maxRequests int
 	window      time.Duration
 }
 
+// RateLimiter encapsula a lógica de rate limiting
+type RateLimiter struct {
+	redis    *redisInternal.RedisInternal
+	config   RateLimitConfig
+	breaker  *circuitBreaker
+	fallback *memoryLimiter
+}
+
 // NewRateLimiter cria uma nova instância do rate limiter
-func NewRateLimiter(redisClient *redisInternal.RedisInternal, maxRequests int, window time.Duration) *RateLimiter {
+func NewRateLimiter(redisClient *redisInternal.RedisInternal, config RateLimitConfig) *RateLimiter {
 	return &RateLimiter{
-		redis:       redisClient,
-		maxRequests: maxRequests,
-		window:      window,
+		redis:    redisClient,
+		config:   config,
+		breaker:  newCircuitBreaker(config.BreakerFailures, config.BreakerCooldown),
+		fallback: newMemoryLimiter(),
 	}
 }
 
 // setupRedisDB configura o middleware de rate limiting
 func setupRedisDB(engine *gin.Engine, cfg *config.App) {
-	// Limpa o Redis (opcional - considere remover em produção)
-	cfg.Redis.FlushAll(context.Background())
-
-	// Obtém a configuração do limite máximo
-	maxRequests := int(getEnvAsInt64("MAX_REQUEST_COUNT_BY_IP", defaultMaxRequests))
+	// Limpa apenas os contadores de rate limiting, preservando os demais dados do Redis
+	if err := clearRateLimitKeys(context.Background(), cfg.Redis); err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to clear rate limit keys: %v", err))
+	}
 
-	// Cria o rate limiter
-	rateLimiter := NewRateLimiter(cfg.Redis, maxRequests, rateLimitWindow)
+	// Cria o rate limiter com os limites configurados
+	rateLimiter := NewRateLimiter(cfg.Redis, LoadRateLimitConfig())
 
 	// Adiciona o middleware
 	engine.Use(rateLimiter.Middleware())
@@ -56,37 +184,160 @@ func setupRedisDB(engine *gin.Engine, cfg *config.App) {
 func (rl *RateLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 
-		// Permite requisições para qualquer rota que contenha "swagger" sem rate limiting
-		if strings.Contains(c.FullPath(), "swagger") {
+		if rl.exempt(c) {
 			c.Next()
 			return
 		}
 
-		ip := c.ClientIP()
+		for _, limit := range rl.limitsFor(c) {
+			allowed, retryAfter, err := rl.check(c.Request.Context(), limit)
+			if err != nil {
+				rl.handleUnavailable(c)
+				return
+			}
+
+			if !allowed {
+				rl.handleRateLimitExceeded(c, retryAfter, limit.maxRequests)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// exempt indica se a requisição não passa pelo rate limiting: rotas do swagger, probes, IPs da lista
+// de exceção (o IP vem de ClientIP, que só considera X-Forwarded-For dos proxies confiáveis) e
+// usuários da lista de exceção com um JWT válido
+func (rl *RateLimiter) exempt(c *gin.Context) bool {
+	if strings.Contains(c.FullPath(), "swagger") || rateLimitExemptPaths[UnversionedPath(c.FullPath())] {
+		return true
+	}
+
+	if containsIP(rl.config.ExemptNetworks, c.ClientIP()) {
+		return true
+	}
+
+	if len(rl.config.ExemptUsers) > 0 {
+		if claims, ok := bearerClaims(c); ok {
+			if userID, ok := claims["user_id"].(float64); ok && rl.config.ExemptUsers[int64(userID)] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// limitsFor retorna os limites que a requisição precisa respeitar: o geral do cliente e o da rota, se houver
+func (rl *RateLimiter) limitsFor(c *gin.Context) []rateLimit {
+	identity, maxRequests := rl.identify(c)
+
+	limits := []rateLimit{{
+		key:         rateLimitKeyPrefix + identity,
+		maxRequests: maxRequests,
+		window:      rl.config.Window,
+	}}
 
-		allowed, retryAfter, err := rl.checkRateLimit(c.Request.Context(), ip)
+	// /api/v1/auth/login e a rota legada /auth/login dividem o mesmo contador
+	path := UnversionedPath(c.FullPath())
+	for _, route := range rl.config.Routes {
+		if route.Path != path || (route.Method != "" && route.Method != c.Request.Method) {
+			continue
+		}
+		limits = append(limits, rateLimit{
+			key:         rateLimitKeyPrefix + "route:" + route.Method + ":" + route.Path + ":" + identity,
+			maxRequests: route.MaxRequests,
+			window:      route.Window,
+		})
+	}
+
+	return limits
+}
+
+// identify retorna quem está fazendo a requisição e o seu limite geral.
+// O rate limiter roda antes de Auth, então lê o JWT por conta própria; um token inválido conta como anônimo.
+func (rl *RateLimiter) identify(c *gin.Context) (string, int) {
+	claims, ok := bearerClaims(c)
+	if ok {
+		if userID, ok := claims["user_id"].(float64); ok {
+			maxRequests := rl.config.UserRequests
+			if roleClaim(claims) == utils.RoleAdmin {
+				maxRequests = rl.config.AdminRequests
+			}
+			return "user:" + strconv.FormatInt(int64(userID), 10), maxRequests
+		}
+	}
+
+	return "ip:" + c.ClientIP(), rl.config.IPRequests
+}
+
+// clearRateLimitKeys remove os contadores de rate limiting de execuções anteriores
+func clearRateLimitKeys(ctx context.Context, rd *redisInternal.RedisInternal) error {
+	var cursor uint64
+	for {
+		keys, next, err := rd.Scan(ctx, cursor, rateLimitKeyPrefix+"*", 500).Result()
 		if err != nil {
-			rl.handleError(c, err)
-			return
+			return err
 		}
 
-		if !allowed {
-			rl.handleRateLimitExceeded(c, retryAfter)
-			return
+		if len(keys) > 0 {
+			if err := rd.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
 		}
 
-		c.Next()
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// check consulta o limite no Redis e, se o Redis falhar ou o circuito estiver aberto, aplica o modo de falha
+func (rl *RateLimiter) check(ctx context.Context, limit rateLimit) (bool, time.Duration, error) {
+	if rl.redis != nil && rl.breaker.Allow() {
+		redisCtx := ctx
+		if rl.config.RedisTimeout > 0 {
+			var cancel context.CancelFunc
+			redisCtx, cancel = context.WithTimeout(ctx, rl.config.RedisTimeout)
+			defer cancel()
+		}
+
+		allowed, retryAfter, err := rl.checkRateLimit(redisCtx, limit)
+		if err == nil {
+			if rl.breaker.Success() {
+				log.Printf("Rate limiter: Redis is back, leaving %s mode", rl.config.FailureMode)
+			}
+			return allowed, retryAfter, nil
+		}
+
+		if rl.breaker.Failure() {
+			log.Printf("Rate limiter: Redis unavailable (%v), using %s mode for %s", err, rl.config.FailureMode, rl.config.BreakerCooldown)
+		}
+	}
+
+	switch rl.config.FailureMode {
+	case RateLimitFailOpen:
+		return true, 0, nil
+	case RateLimitFailClosed:
+		return false, 0, errRateLimiterUnavailable
+	default:
+		allowed, retryAfter := rl.fallback.Allow(limit)
+		return allowed, retryAfter, nil
 	}
 }
 
-// checkRateLimit verifica se o IP pode fazer a requisição
-func (rl *RateLimiter) checkRateLimit(ctx context.Context, ip string) (allowed bool, retryAfter time.Duration, err error) {
+// checkRateLimit verifica se o contador do limite ainda comporta a requisição
+func (rl *RateLimiter) checkRateLimit(ctx context.Context, limit rateLimit) (allowed bool, retryAfter time.Duration, err error) {
+	key := limit.key
+
 	// Tenta obter o contador atual
-	val, err := rl.redis.Get(ctx, ip).Result()
+	val, err := rl.redis.Get(ctx, key).Result()
 
-	// Primeira requisição do IP
+	// Primeira requisição do cliente na janela
 	if err == redis.Nil {
-		err = rl.redis.Set(ctx, ip, 1, rl.window).Err()
+		err = rl.redis.Set(ctx, key, 1, limit.window).Err()
 		if err != nil {
 			return false, 0, err
 		}
@@ -105,8 +356,8 @@ func (rl *RateLimiter) checkRateLimit(ctx context.Context, ip string) (allowed b
 	}
 
 	// Verifica se excedeu o limite
-	if requestCount >= rl.maxRequests {
-		ttl, err := rl.redis.TTL(ctx, ip).Result()
+	if requestCount >= limit.maxRequests {
+		ttl, err := rl.redis.TTL(ctx, key).Result()
 		if err != nil {
 			return false, 0, err
 		}
@@ -114,7 +365,7 @@ func (rl *RateLimiter) checkRateLimit(ctx context.Context, ip string) (allowed b
 	}
 
 	// Incrementa o contador
-	err = rl.redis.Incr(ctx, ip).Err()
+	err = rl.redis.Incr(ctx, key).Err()
 	if err != nil {
 		return false, 0, err
 	}
@@ -122,63 +373,58 @@ func (rl *RateLimiter) checkRateLimit(ctx context.Context, ip string) (allowed b
 	return true, 0, nil
 }
 
-// handleError trata erros internos
-func (rl *RateLimiter) handleError(c *gin.Context, err error) {
-	errorResponse := dto.NewErrorResponse(
+// handleUnavailable recusa a requisição enquanto o Redis está indisponível no modo fail-closed
+func (rl *RateLimiter) handleUnavailable(c *gin.Context) {
+	retryAfter := rl.breaker.RetryAfter()
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+
+	AbortWithRetry(
 		c,
-		http.StatusInternalServerError,
-		"internal_server_error",
-		"Internal server error",
-		map[string]interface{}{
-			"original_error": err.Error(),
-		},
+		http.StatusServiceUnavailable,
+		dto.ReasonRateLimiterUnavailable,
+		"Rate limiter unavailable",
+		retryAfter,
+		0,
 	)
-
-	c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse)
 }
 
 // handleRateLimitExceeded trata quando o limite é excedido
-func (rl *RateLimiter) handleRateLimitExceeded(c *gin.Context, retryAfter time.Duration) {
-	// Adicionar headers de rate limiting
-	c.Writer.Header().Set("Retry-After", retryAfter.String())
-	c.Writer.Header().Set("X-RateLimit-Limit", "100") // ajuste conforme sua configuração
-	c.Writer.Header().Set("X-RateLimit-Remaining", "0")
-	c.Writer.Header().Set("X-RateLimit-Reset", time.Now().Add(retryAfter).Format(time.RFC3339))
-
-	errorResponse := dto.NewRateLimitErrorResponse(
+func (rl *RateLimiter) handleRateLimitExceeded(c *gin.Context, retryAfter time.Duration, maxRequests int) {
+	AbortWithRetry(
 		c,
-		retryAfter.String(),
-		100, // limite por minuto - ajuste conforme sua configuração
-		0,   // requests restantes
-		time.Now().Add(retryAfter),
+		http.StatusTooManyRequests,
+		dto.ReasonRateLimited,
+		"Rate limit exceeded",
+		retryAfter,
+		maxRequests,
 	)
-
-	c.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse)
 }
+
 func setupSemaphore(engine *gin.Engine) {
 	max := getEnvAsInt64("MAX_REQUEST_COUNT_GLOBAL", int64(10))
 	sema := semaphore.NewWeighted(max)
 
 	engine.Use(func(c *gin.Context) {
 		if err := sema.Acquire(c.Request.Context(), 1); err != nil {
-			errorResponse := dto.NewRateLimitErrorResponse(
+			AbortWithRetry(
 				c,
-				"60s", // retry after 60 seconds
+				http.StatusTooManyRequests,
+				dto.ReasonConcurrencyLimit,
+				"Concurrent request limit exceeded",
+				semaphoreRetryAfter,
 				int(max),
-				0,
-				time.Now().Add(time.Minute),
 			)
-
-			// Adicionar headers de rate limiting
-			c.Writer.Header().Set("Retry-After", "60")
-			c.Writer.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", max))
-			c.Writer.Header().Set("X-RateLimit-Remaining", "0")
-			c.Writer.Header().Set("X-RateLimit-Reset", time.Now().Add(time.Minute).Format(time.RFC3339))
-
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse)
 			return
 		}
-		defer sema.Release(1)
+
+		// Requisições de baixa prioridade liberam a vaga antes de terminar
+		var once sync.Once
+		release := func() { once.Do(func() { sema.Release(1) }) }
+		c.Set(interactiveSlotKey, release)
+
+		defer release()
 		c.Next()
 	})
 }
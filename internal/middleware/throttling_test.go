@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/utils"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterLimitsFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	adminToken, err := GenerateJWT(7, "admin@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+	agentToken, err := GenerateJWT(9, "agent@example.com", utils.RoleAgent, 0, "")
+	require.NoError(t, err)
+
+	rl := NewRateLimiter(nil, RateLimitConfig{
+		IPRequests:    100,
+		UserRequests:  200,
+		AdminRequests: 500,
+		Window:        rateLimitWindow,
+		Routes: []RouteRateLimit{
+			{Method: http.MethodPost, Path: "/auth/login", MaxRequests: 10, Window: rateLimitWindow},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		expected []rateLimit
+	}{
+		{
+			name:     "Anonymous request is limited by IP",
+			method:   http.MethodGet,
+			path:     "/tickets/query",
+			expected: []rateLimit{{key: "ratelimit:ip:192.0.2.1", maxRequests: 100, window: rateLimitWindow}},
+		},
+		{
+			name:     "Invalid token is treated as anonymous",
+			method:   http.MethodGet,
+			path:     "/tickets/query",
+			token:    "not-a-jwt",
+			expected: []rateLimit{{key: "ratelimit:ip:192.0.2.1", maxRequests: 100, window: rateLimitWindow}},
+		},
+		{
+			name:     "Authenticated user is limited by user ID",
+			method:   http.MethodGet,
+			path:     "/tickets/query",
+			token:    agentToken,
+			expected: []rateLimit{{key: "ratelimit:user:9", maxRequests: 200, window: rateLimitWindow}},
+		},
+		{
+			name:     "Admin has a higher quota",
+			method:   http.MethodGet,
+			path:     "/tickets/query",
+			token:    adminToken,
+			expected: []rateLimit{{key: "ratelimit:user:7", maxRequests: 500, window: rateLimitWindow}},
+		},
+		{
+			name:   "Login has its own limit",
+			method: http.MethodPost,
+			path:   "/auth/login",
+			expected: []rateLimit{
+				{key: "ratelimit:ip:192.0.2.1", maxRequests: 100, window: rateLimitWindow},
+				{key: "ratelimit:route:POST:/auth/login:ip:192.0.2.1", maxRequests: 10, window: rateLimitWindow},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limits []rateLimit
+			engine := gin.New()
+			engine.Handle(tt.method, tt.path, func(c *gin.Context) {
+				limits = rl.limitsFor(c)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			engine.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expected, limits)
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	cb := newCircuitBreaker(2, 30*time.Second)
+	cb.now = func() time.Time { return now }
+
+	assert.True(t, cb.Allow())
+	assert.False(t, cb.Failure())
+	assert.True(t, cb.Allow(), "one failure keeps the circuit closed")
+	assert.True(t, cb.Failure(), "second failure opens the circuit")
+	assert.False(t, cb.Allow())
+	assert.Equal(t, 30*time.Second, cb.RetryAfter())
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, cb.Allow(), "half-open after the cooldown")
+	assert.False(t, cb.Allow(), "only one probe at a time")
+
+	cb.Failure()
+	assert.False(t, cb.Allow(), "failed probe reopens the circuit")
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, cb.Allow())
+	assert.True(t, cb.Success(), "successful probe closes the circuit")
+	assert.True(t, cb.Allow())
+	assert.Zero(t, cb.RetryAfter())
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	now := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	m := newMemoryLimiter()
+	m.now = func() time.Time { return now }
+
+	limit := rateLimit{key: "ratelimit:ip:192.0.2.1", maxRequests: 2, window: time.Minute}
+
+	allowed, _ := m.Allow(limit)
+	assert.True(t, allowed)
+	allowed, _ = m.Allow(limit)
+	assert.True(t, allowed)
+
+	now = now.Add(20 * time.Second)
+	allowed, retryAfter := m.Allow(limit)
+	assert.False(t, allowed)
+	assert.Equal(t, 40*time.Second, retryAfter)
+
+	now = now.Add(40 * time.Second)
+	allowed, _ = m.Allow(limit)
+	assert.True(t, allowed, "a new window starts after the reset")
+}
+
+func TestRateLimiterFailureModes(t *testing.T) {
+	limit := rateLimit{key: "ratelimit:ip:192.0.2.1", maxRequests: 1, window: time.Minute}
+
+	tests := []struct {
+		mode          RateLimitFailureMode
+		secondAllowed bool
+		wantErr       bool
+	}{
+		{mode: RateLimitFailMemory, secondAllowed: false},
+		{mode: RateLimitFailOpen, secondAllowed: true},
+		{mode: RateLimitFailClosed, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			// Sem Redis o limiter se comporta como durante uma indisponibilidade
+			rl := NewRateLimiter(nil, RateLimitConfig{FailureMode: tt.mode, BreakerFailures: 1, BreakerCooldown: time.Second})
+
+			_, _, err := rl.check(context.Background(), limit)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errRateLimiterUnavailable)
+				return
+			}
+			require.NoError(t, err)
+
+			allowed, _, err := rl.check(context.Background(), limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.secondAllowed, allowed)
+		})
+	}
+}
+
+func TestParseRateLimitFailureMode(t *testing.T) {
+	assert.Equal(t, RateLimitFailOpen, parseRateLimitFailureMode(" OPEN "))
+	assert.Equal(t, RateLimitFailClosed, parseRateLimitFailureMode("closed"))
+	assert.Equal(t, RateLimitFailMemory, parseRateLimitFailureMode(""))
+	assert.Equal(t, RateLimitFailMemory, parseRateLimitFailureMode("unknown"))
+}
+
+func TestRateLimiterExempt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	etlToken, err := GenerateJWT(42, "etl@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+	agentToken, err := GenerateJWT(9, "agent@example.com", utils.RoleAgent, 0, "")
+	require.NoError(t, err)
+
+	networks, invalid := parseNetworks([]string{"10.0.0.0/8", "192.0.2.50"})
+	require.Empty(t, invalid)
+	rl := NewRateLimiter(nil, RateLimitConfig{ExemptNetworks: networks, ExemptUsers: parseExemptUsers("42")})
+
+	tests := []struct {
+		name           string
+		path           string
+		remoteAddr     string
+		forwardedFor   string
+		token          string
+		trustedProxies []string
+		expected       bool
+	}{
+		{name: "Probe route", path: "/healthcheck/live", remoteAddr: "192.0.2.1:1234", expected: true},
+		{name: "Exempt network", path: "/tickets/query", remoteAddr: "10.1.2.3:1234", expected: true},
+		{name: "Exempt single IP", path: "/tickets/query", remoteAddr: "192.0.2.50:1234", expected: true},
+		{name: "Other IP", path: "/tickets/query", remoteAddr: "192.0.2.1:1234", expected: false},
+		{name: "Exempt service account", path: "/tickets/query", remoteAddr: "192.0.2.1:1234", token: etlToken, expected: true},
+		{name: "Other user", path: "/tickets/query", remoteAddr: "192.0.2.1:1234", token: agentToken, expected: false},
+		{
+			name: "Forwarded IP from a trusted proxy", path: "/tickets/query", remoteAddr: "172.16.0.1:1234", forwardedFor: "10.1.2.3",
+			trustedProxies: []string{"172.16.0.0/12"}, expected: true,
+		},
+		{
+			name: "Forwarded IP from an untrusted client", path: "/tickets/query", remoteAddr: "192.0.2.1:1234", forwardedFor: "10.1.2.3",
+			trustedProxies: []string{"172.16.0.0/12"}, expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var exempt bool
+			engine := gin.New()
+			require.NoError(t, engine.SetTrustedProxies(tt.trustedProxies))
+			engine.GET(tt.path, func(c *gin.Context) {
+				exempt = rl.exempt(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			engine.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expected, exempt)
+		})
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks, invalid := parseNetworks(splitList(" 10.0.0.0/8, 192.0.2.7 ,, 2001:db8::1, not-an-ip, 10.0.0.0/99"))
+
+	assert.Equal(t, []string{"not-an-ip", "10.0.0.0/99"}, invalid)
+	require.Len(t, networks, 3)
+	assert.True(t, containsIP(networks, "10.200.0.1"))
+	assert.True(t, containsIP(networks, "192.0.2.7"))
+	assert.False(t, containsIP(networks, "192.0.2.8"))
+	assert.True(t, containsIP(networks, "2001:db8::1"))
+	assert.False(t, containsIP(networks, "2001:db8::2"))
+	assert.False(t, containsIP(networks, "garbage"))
+}
+
+func TestParseExemptUsers(t *testing.T) {
+	assert.Equal(t, map[int64]bool{1: true, 42: true}, parseExemptUsers("1, 42,abc"))
+	assert.Empty(t, parseExemptUsers(""))
+}
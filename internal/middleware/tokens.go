@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// defaultTokenLifetime is how long a JWT stays valid when JWT_TTL_MINUTES is not set
+	defaultTokenLifetime = 1 * time.Hour
+	// defaultTokenIssuer is the iss claim when JWT_ISSUER is not set
+	defaultTokenIssuer = "visiondata-api"
+	// defaultTokenAudience is the aud claim when JWT_AUDIENCE is not set
+	defaultTokenAudience = "visiondata"
+	// defaultKeyID is the kid stamped on new tokens when JWT_KID is not set
+	defaultKeyID = "current"
+
+	signingHS256 = "HS256"
+	signingRS256 = "RS256"
+)
+
+// tokenSettings describes how tokens are signed and which claims they must carry
+type tokenSettings struct {
+	lifetime  time.Duration
+	issuer    string
+	audience  string
+	method    jwt.SigningMethod
+	keyID     string
+	signKey   interface{}
+	verifyKey interface{}
+	// previousKeys are the verification keys of rotated-out kids, still accepted until their tokens expire
+	previousKeys map[string]interface{}
+}
+
+// rsaKeys caches the parsed key files by path; rotating a key requires a restart
+var rsaKeys sync.Map
+
+// TokenLifetime returns how long an issued token stays valid (JWT_TTL_MINUTES, default 60)
+func TokenLifetime() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("JWT_TTL_MINUTES"))
+	if err != nil || minutes < 1 {
+		return defaultTokenLifetime
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// loadTokenSettings reads the token settings from the environment.
+// JWT_SIGNING_METHOD=HS256 (default) signs with JWT_SECRET. RS256 signs with the PEM key in
+// JWT_PRIVATE_KEY_FILE and verifies with JWT_PUBLIC_KEY_FILE (or the private key's public half),
+// so other services can verify tokens holding only the public key.
+// New tokens carry the kid in JWT_KID; JWT_PREVIOUS_KEYS keeps the keys of earlier kids valid
+// during a rotation.
+func loadTokenSettings() (tokenSettings, error) {
+	settings := tokenSettings{
+		lifetime: TokenLifetime(),
+		issuer:   envOrDefault("JWT_ISSUER", defaultTokenIssuer),
+		audience: envOrDefault("JWT_AUDIENCE", defaultTokenAudience),
+	}
+
+	switch method := strings.ToUpper(envOrDefault("JWT_SIGNING_METHOD", signingHS256)); method {
+	case signingHS256:
+		secret := []byte(os.Getenv("JWT_SECRET"))
+		settings.method = jwt.SigningMethodHS256
+		settings.signKey = secret
+		settings.verifyKey = secret
+	case signingRS256:
+		settings.method = jwt.SigningMethodRS256
+
+		if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+			privateKey, err := loadRSAPrivateKey(path)
+			if err != nil {
+				return settings, err
+			}
+			settings.signKey = privateKey
+			settings.verifyKey = &privateKey.PublicKey
+		}
+		if path := os.Getenv("JWT_PUBLIC_KEY_FILE"); path != "" {
+			publicKey, err := loadRSAPublicKey(path)
+			if err != nil {
+				return settings, err
+			}
+			settings.verifyKey = publicKey
+		}
+		if settings.verifyKey == nil {
+			return settings, errors.New("RS256 signing requires JWT_PRIVATE_KEY_FILE or JWT_PUBLIC_KEY_FILE")
+		}
+	default:
+		return settings, fmt.Errorf("unsupported JWT_SIGNING_METHOD %q, expected HS256 or RS256", method)
+	}
+
+	previousKeys, err := loadPreviousKeys(settings.method, os.Getenv("JWT_PREVIOUS_KEYS"))
+	if err != nil {
+		return settings, err
+	}
+	settings.keyID = envOrDefault("JWT_KID", defaultKeyID)
+	settings.previousKeys = previousKeys
+
+	return settings, nil
+}
+
+// loadPreviousKeys parses JWT_PREVIOUS_KEYS, a comma separated list of kid=key pairs where the key
+// is the old secret (HS256) or the path of the old public key (RS256)
+func loadPreviousKeys(method jwt.SigningMethod, value string) (map[string]interface{}, error) {
+	keys := make(map[string]interface{})
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, key, ok := strings.Cut(entry, "=")
+		kid, key = strings.TrimSpace(kid), strings.TrimSpace(key)
+		if !ok || kid == "" || key == "" {
+			// The entry may hold a secret, so only its position is reported
+			return nil, fmt.Errorf("invalid JWT_PREVIOUS_KEYS entry #%d, expected kid=key", i+1)
+		}
+
+		if method == jwt.SigningMethodRS256 {
+			publicKey, err := loadRSAPublicKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("previous key %s: %w", kid, err)
+			}
+			keys[kid] = publicKey
+		} else {
+			keys[kid] = []byte(key)
+		}
+	}
+	return keys, nil
+}
+
+// verificationKey returns the key that verifies a token signed with the given kid. Tokens without
+// kid were issued before the keyring and are checked against the current key.
+func (s tokenSettings) verificationKey(kid string) (interface{}, error) {
+	if kid == "" || kid == s.keyID {
+		return s.verifyKey, nil
+	}
+	if key, ok := s.previousKeys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// loadRSAPrivateKey reads and caches a PEM encoded RSA private key
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	if key, ok := rsaKeys.Load(path); ok {
+		if privateKey, ok := key.(*rsa.PrivateKey); ok {
+			return privateKey, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT private key: %w", err)
+	}
+
+	rsaKeys.Store(path, privateKey)
+	return privateKey, nil
+}
+
+// loadRSAPublicKey reads and caches a PEM encoded RSA public key
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	if key, ok := rsaKeys.Load(path); ok {
+		if publicKey, ok := key.(*rsa.PublicKey); ok {
+			return publicKey, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+	}
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT public key: %w", err)
+	}
+
+	rsaKeys.Store(path, publicKey)
+	return publicKey, nil
+}
+
+// envOrDefault returns the trimmed environment variable, or fallback when it is empty
+func envOrDefault(name, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+		return value
+	}
+	return fallback
+}
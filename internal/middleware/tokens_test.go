@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"orderstreamrest/internal/utils"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenLifetime(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "Default", value: "", expected: time.Hour},
+		{name: "Configured", value: "15", expected: 15 * time.Minute},
+		{name: "Invalid falls back to the default", value: "0", expected: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_TTL_MINUTES", tt.value)
+			assert.Equal(t, tt.expected, TokenLifetime())
+		})
+	}
+}
+
+func TestGenerateJWTStandardClaims(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("JWT_TTL_MINUTES", "30")
+
+	token, err := GenerateJWT(4, "agent@example.com", utils.RoleAgent, 0, "")
+	require.NoError(t, err)
+	claims, err := DecodeTokenJWT(token)
+	require.NoError(t, err)
+
+	assert.Equal(t, defaultTokenIssuer, claims["iss"])
+	assert.Equal(t, defaultTokenAudience, claims["aud"])
+	assert.NotEmpty(t, claims["jti"])
+	assert.Equal(t, float64(30*60), claims["exp"].(float64)-claims["iat"].(float64))
+
+	other, err := GenerateJWT(4, "agent@example.com", utils.RoleAgent, 0, "")
+	require.NoError(t, err)
+	otherClaims, err := DecodeTokenJWT(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, claims["jti"], otherClaims["jti"])
+}
+
+func TestVerifyTokenIssuerAndAudience(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	t.Setenv("JWT_ISSUER", "other-service")
+	foreignIssuer, err := GenerateJWT(1, "admin@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("JWT_AUDIENCE", "other-audience")
+	foreignAudience, err := GenerateJWT(1, "admin@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+
+	t.Setenv("JWT_AUDIENCE", "")
+	_, err = DecodeTokenJWT(foreignIssuer)
+	assert.Error(t, err)
+	_, err = DecodeTokenJWT(foreignAudience)
+	assert.Error(t, err)
+}
+
+func TestRS256Tokens(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "jwt.key")
+	publicPath := filepath.Join(dir, "jwt.pub")
+	require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}), 0o600))
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o644))
+
+	t.Setenv("JWT_SECRET", "test-secret")
+	hmacToken, err := GenerateJWT(1, "admin@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+
+	t.Setenv("JWT_SIGNING_METHOD", "RS256")
+	t.Setenv("JWT_PRIVATE_KEY_FILE", privatePath)
+	token, err := GenerateJWT(1, "admin@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+
+	// A service holding only the public key verifies tokens but cannot issue them
+	t.Setenv("JWT_PRIVATE_KEY_FILE", "")
+	t.Setenv("JWT_PUBLIC_KEY_FILE", publicPath)
+	claims, err := DecodeTokenJWT(token)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), claims["user_id"])
+
+	_, err = GenerateJWT(1, "admin@example.com", utils.RoleAdmin, 0, "")
+	assert.Error(t, err)
+
+	// HS256 tokens are rejected in RS256 mode
+	_, err = DecodeTokenJWT(hmacToken)
+	assert.Error(t, err)
+}
+
+func TestKeyRotation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "old-secret")
+	t.Setenv("JWT_KID", "2025-01")
+	oldToken, err := GenerateJWT(1, "admin@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+
+	// Rotation: new secret and kid, the old one stays accepted
+	t.Setenv("JWT_SECRET", "new-secret")
+	t.Setenv("JWT_KID", "2025-02")
+	t.Setenv("JWT_PREVIOUS_KEYS", "2025-01=old-secret")
+	newToken, err := GenerateJWT(1, "admin@example.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+
+	parsed, err := VerifyToken(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02", parsed.Header["kid"])
+
+	_, err = DecodeTokenJWT(oldToken)
+	assert.NoError(t, err)
+
+	// Once the old kid is dropped its tokens are rejected
+	t.Setenv("JWT_PREVIOUS_KEYS", "")
+	_, err = DecodeTokenJWT(oldToken)
+	assert.Error(t, err)
+	_, err = DecodeTokenJWT(newToken)
+	assert.NoError(t, err)
+}
+
+func TestLoadPreviousKeys(t *testing.T) {
+	keys, err := loadPreviousKeys(jwt.SigningMethodHS256, " a=secret-a , b=c2VjcmV0LWI= ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []byte("secret-a"), "b": []byte("c2VjcmV0LWI=")}, keys)
+
+	_, err = loadPreviousKeys(jwt.SigningMethodHS256, "only-a-secret")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "only-a-secret")
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand/v2"
+	"net/http"
+	"orderstreamrest/internal/telemetry"
+	"orderstreamrest/pkg/logger"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceParentHeader = "traceparent"
+
+	// traceKey guarda no contexto o *logger.TraceContext da requisição
+	traceKey = "trace"
+	// traceStartKey guarda no contexto o início da requisição, usado na amostragem por latência
+	traceStartKey = "trace_start"
+
+	defaultTraceSampleRatio = 0.1
+	defaultTraceSlowAfter   = time.Second
+)
+
+// traceSampling decide quais requisições têm o trace guardado. Requisições com erro 5xx ou mais
+// lentas que slowAfter são sempre amostradas; as demais (caminho feliz) na proporção ratio.
+type traceSampling struct {
+	ratio     float64
+	slowAfter time.Duration
+}
+
+var sampling = traceSampling{ratio: defaultTraceSampleRatio, slowAfter: defaultTraceSlowAfter}
+
+// setupTracing propaga ou cria o trace W3C (traceparent) de cada requisição e registra a latência
+// no Prometheus, com o trace como exemplar quando amostrado. Deve ser o primeiro middleware,
+// para que as requisições rejeitadas pelos limites também sejam medidas.
+func setupTracing(engine *gin.Engine) {
+	sampling = traceSampling{
+		ratio:     getEnvAsFloat("TRACE_SAMPLE_RATIO", defaultTraceSampleRatio),
+		slowAfter: time.Duration(getEnvAsInt64("TRACE_SLOW_REQUEST_MS", defaultTraceSlowAfter.Milliseconds())) * time.Millisecond,
+	}
+
+	engine.Use(tracingMiddleware())
+}
+
+// tracingMiddleware guarda o trace no contexto, abre o span OpenTelemetry da requisição com os mesmos
+// identificadores e mede a requisição ao final. As chamadas ao SQL Server, Elasticsearch e Redis feitas
+// com c.Request.Context() viram spans filhos.
+func tracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		trace := newTrace(c.GetHeader(traceParentHeader), sampling.ratio)
+		c.Set(traceKey, trace)
+		c.Set(traceStartKey, start)
+		c.Header(traceParentHeader, formatTraceParent(trace))
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx = telemetry.WithRequestTrace(ctx, telemetry.RequestTrace{
+			TraceID: trace.TraceID,
+			SpanID:  trace.SpanID,
+			Sampled: trace.Sampled,
+		})
+		ctx, span := telemetry.Tracer().Start(ctx, c.Request.Method,
+			oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+			oteltrace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.ClientAddress(c.ClientIP()),
+			),
+		)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		endRequestSpan(c, span)
+		observeRequest(c, time.Since(start), finishTrace(c))
+	}
+}
+
+// endRequestSpan completa o span da requisição com a rota e o status da resposta
+func endRequestSpan(c *gin.Context, span oteltrace.Span) {
+	status := c.Writer.Status()
+	if route := c.FullPath(); route != "" {
+		span.SetName(c.Request.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= 500 {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	for _, err := range c.Errors {
+		span.RecordError(err.Err)
+	}
+	span.End()
+}
+
+// GetTrace retorna o trace da requisição, ou nil quando o tracing não está ativo
+func GetTrace(c *gin.Context) *logger.TraceContext {
+	value, ok := c.Get(traceKey)
+	if !ok {
+		return nil
+	}
+	trace, _ := value.(*logger.TraceContext)
+	return trace
+}
+
+// finishTrace aplica a amostragem de cauda (erros e requisições lentas) e retorna o trace.
+// Pode ser chamada mais de uma vez; uma vez amostrado, o trace continua amostrado.
+func finishTrace(c *gin.Context) *logger.TraceContext {
+	trace := GetTrace(c)
+	if trace == nil || trace.Sampled {
+		return trace
+	}
+
+	if c.Writer.Status() >= 500 {
+		trace.Sampled = true
+		return trace
+	}
+
+	if start, ok := c.Get(traceStartKey); ok {
+		if startedAt, ok := start.(time.Time); ok && time.Since(startedAt) >= sampling.slowAfter {
+			trace.Sampled = true
+		}
+	}
+
+	return trace
+}
+
+// newTrace continua o trace do header traceparent ou inicia um novo. A decisão de amostragem
+// de quem chamou é respeitada; sem ela, a requisição é amostrada na proporção ratio.
+func newTrace(traceParent string, ratio float64) *logger.TraceContext {
+	trace := &logger.TraceContext{SpanID: randomHex(8)}
+
+	if traceID, parentID, sampled, ok := parseTraceParent(traceParent); ok {
+		trace.TraceID = traceID
+		trace.ParentID = parentID
+		trace.Sampled = sampled
+		return trace
+	}
+
+	trace.TraceID = randomHex(16)
+	trace.Sampled = mathrand.Float64() < ratio
+	return trace
+}
+
+// parseTraceParent interpreta um header traceparent versão 00 (version-traceid-parentid-flags)
+func parseTraceParent(value string) (traceID, parentID string, sampled, ok bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return "", "", false, false
+	}
+
+	traceID, parentID, flags := parts[1], parts[2], parts[3]
+	if !isHex(traceID, 32) || !isHex(parentID, 16) || !isHex(flags, 2) {
+		return "", "", false, false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(parentID, "0") == "" {
+		return "", "", false, false
+	}
+
+	flagBits, _ := strconv.ParseUint(flags, 16, 8)
+	return traceID, parentID, flagBits&0x01 == 1, true
+}
+
+// formatTraceParent monta o header traceparent da resposta, com o span desta requisição
+func formatTraceParent(trace *logger.TraceContext) string {
+	flags := "00"
+	if trace.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", trace.TraceID, trace.SpanID, flags)
+}
+
+// isHex verifica se value tem o tamanho informado e apenas dígitos hexadecimais minúsculos
+func isHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// randomHex gera um identificador aleatório com n bytes em hexadecimal
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand não falha em plataformas suportadas; mantém um id válido mesmo assim
+		return strings.Repeat("0", 2*n-1) + "1"
+	}
+	return hex.EncodeToString(buf)
+}
+
+func getEnvAsFloat(name string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
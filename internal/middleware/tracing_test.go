@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		sampled bool
+		ok      bool
+	}{
+		{name: "Sampled parent", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sampled: true, ok: true},
+		{name: "Not sampled parent", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", ok: true},
+		{name: "Unknown version", header: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "Uppercase hex", header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "All zero trace id", header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "Missing header", header: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceID, parentID, sampled, ok := parseTraceParent(tt.header)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.sampled, sampled)
+			if tt.ok {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+				assert.Equal(t, "00f067aa0ba902b7", parentID)
+			}
+		})
+	}
+}
+
+func TestNewTrace(t *testing.T) {
+	trace := newTrace("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", 0)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", trace.ParentID)
+	assert.True(t, trace.Sampled)
+	assert.Len(t, trace.SpanID, 16)
+
+	fresh := newTrace("", 0)
+	assert.Len(t, fresh.TraceID, 32)
+	assert.Empty(t, fresh.ParentID)
+	assert.False(t, fresh.Sampled)
+
+	_, _, _, ok := parseTraceParent(formatTraceParent(fresh))
+	assert.True(t, ok)
+}
+
+func TestTracingSamplesErrorsAndRecordsExemplar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sampling = traceSampling{ratio: 0, slowAfter: time.Hour}
+
+	engine := gin.New()
+	engine.Use(tracingMiddleware())
+	engine.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	engine.GET("/fail", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	traceIDs := map[string]string{}
+	for _, path := range []string{"/ok", "/fail"} {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+		traceID, _, _, ok := parseTraceParent(recorder.Header().Get(traceParentHeader))
+		require.True(t, ok)
+		traceIDs[path] = traceID
+	}
+
+	families, err := metricsRegistry.Gather()
+	require.NoError(t, err)
+
+	exemplars := map[string]string{}
+	for _, family := range families {
+		if family.GetName() != "http_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			route := ""
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "route" {
+					route = label.GetValue()
+				}
+			}
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				if exemplar := bucket.GetExemplar(); exemplar != nil {
+					exemplars[route] = exemplar.GetLabel()[0].GetValue()
+				}
+			}
+		}
+	}
+
+	// O caminho feliz não foi amostrado; o erro sempre é
+	assert.NotContains(t, exemplars, "/ok")
+	assert.Equal(t, traceIDs["/fail"], exemplars["/fail"])
+}
@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/i18n"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/utils"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// validationMessages são as mensagens por regra de validação; %s recebe o parâmetro da regra.
+// Regras de tamanho têm uma mensagem para textos, outra para números e outra para listas.
+var validationMessages = map[string]map[string]string{
+	i18n.English: {
+		"required":    "is required",
+		"email":       "must be a valid email address",
+		"numeric":     "must contain only digits",
+		"min":         "must have at least %s characters",
+		"min_number":  "must be at least %s",
+		"min_items":   "must have at least %s items",
+		"max":         "must have at most %s characters",
+		"max_number":  "must be at most %s",
+		"max_items":   "must have at most %s items",
+		"len":         "must have exactly %s characters",
+		"len_number":  "must be %s",
+		"len_items":   "must have exactly %s items",
+		"oneof":       "must be one of: %s",
+		"type":        "must be of type %s",
+		"default":     "is invalid (%s)",
+		"description": "Request validation failed",
+
+		"password_length":   "must have at least %s characters",
+		"password_classes":  "must combine at least %s of: lowercase letters, uppercase letters, digits and symbols",
+		"password_common":   "is too common",
+		"password_personal": "must not contain the user's name or email",
+		"password_reused":   "must differ from the last %s passwords",
+	},
+	i18n.PortugueseBR: {
+		"required":    "é obrigatório",
+		"email":       "deve ser um email válido",
+		"numeric":     "deve conter apenas números",
+		"min":         "deve ter pelo menos %s caracteres",
+		"min_number":  "deve ser no mínimo %s",
+		"min_items":   "deve ter pelo menos %s itens",
+		"max":         "deve ter no máximo %s caracteres",
+		"max_number":  "deve ser no máximo %s",
+		"max_items":   "deve ter no máximo %s itens",
+		"len":         "deve ter exatamente %s caracteres",
+		"len_number":  "deve ser %s",
+		"len_items":   "deve ter exatamente %s itens",
+		"oneof":       "deve ser um de: %s",
+		"type":        "deve ser do tipo %s",
+		"default":     "é inválido (%s)",
+		"description": "Falha na validação da requisição",
+
+		"password_length":   "deve ter pelo menos %s caracteres",
+		"password_classes":  "deve combinar pelo menos %s entre: letras minúsculas, letras maiúsculas, números e símbolos",
+		"password_common":   "é muito comum",
+		"password_personal": "não pode conter o nome ou o email do usuário",
+		"password_reused":   "deve ser diferente das últimas %s senhas",
+	},
+}
+
+// BindJSON faz o bind do corpo da requisição e, quando falha, responde com os erros por campo
+// (422) ou, para um corpo ilegível, com 400 e message. Retorna false quando já respondeu.
+func BindJSON(c *gin.Context, obj interface{}, message string) bool {
+	return respondBindError(c, c.ShouldBindJSON(obj), message)
+}
+
+// BindQuery faz o bind da query string, com as mesmas respostas de BindJSON
+func BindQuery(c *gin.Context, obj interface{}, message string) bool {
+	return respondBindError(c, c.ShouldBindQuery(obj), message)
+}
+
+// ValidateStruct valida obj com as mesmas regras do bind, para itens que não chegam
+// um por requisição (ex.: linhas de uma importação). Retorna os erros por campo
+// no idioma da requisição, ou nil quando obj é válido.
+func ValidateStruct(c *gin.Context, obj interface{}) []dto.ValidationError {
+	err := binding.Validator.ValidateStruct(obj)
+	if err == nil {
+		return nil
+	}
+
+	fields, ok := translateBindError(err, requestLanguage(c))
+	if !ok {
+		return []dto.ValidationError{{Message: err.Error()}}
+	}
+	return fields
+}
+
+// RuleViolation é uma regra de negócio violada por um campo, validada fora do bind
+// (ex.: a política de senhas). Rule é a chave da mensagem e Param o seu parâmetro.
+type RuleViolation struct {
+	Rule  string
+	Param string
+}
+
+// RuleErrors converte as regras violadas por field em erros por campo no idioma da requisição
+func RuleErrors(c *gin.Context, field string, violations []RuleViolation) []dto.ValidationError {
+	messages := validationMessages[requestLanguage(c)]
+
+	fields := make([]dto.ValidationError, 0, len(violations))
+	for _, v := range violations {
+		template, ok := messages[v.Rule]
+		if !ok {
+			template = fmt.Sprintf(messages["default"], v.Rule)
+		} else if strings.Contains(template, "%s") {
+			template = fmt.Sprintf(template, v.Param)
+		}
+		fields = append(fields, dto.ValidationError{Field: field, Message: template})
+	}
+	return fields
+}
+
+// RespondRuleErrors responde 422 com as regras violadas por field, no mesmo formato dos erros do bind
+func RespondRuleErrors(c *gin.Context, field string, violations []RuleViolation) {
+	lang := requestLanguage(c)
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.NewValidationErrorResponse(c, validationMessages[lang]["description"], RuleErrors(c, field, violations)))
+}
+
+func respondBindError(c *gin.Context, err error, message string) bool {
+	if err == nil {
+		return true
+	}
+
+	lang := requestLanguage(c)
+	fields, ok := translateBindError(err, lang)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", message, err.Error()))
+		return false
+	}
+
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.NewValidationErrorResponse(c, validationMessages[lang]["description"], fields))
+	return false
+}
+
+// translateBindError converte os erros do validator e de tipo do JSON em mensagens por campo;
+// retorna false para erros que não se referem a um campo (ex.: JSON malformado)
+func translateBindError(err error, lang string) ([]dto.ValidationError, bool) {
+	messages := validationMessages[lang]
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]dto.ValidationError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, dto.ValidationError{
+				Field:   fieldPath(fe),
+				Message: fieldMessage(fe, messages),
+			})
+		}
+		return fields, true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []dto.ValidationError{{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf(messages["type"], typeErr.Type.Kind()),
+		}}, true
+	}
+
+	return nil, false
+}
+
+// fieldMessage escolhe a mensagem da regra que falhou conforme o tipo do campo
+func fieldMessage(fe validator.FieldError, messages map[string]string) string {
+	tag := fe.Tag()
+	param := fe.Param()
+
+	switch tag {
+	case "min", "max", "len":
+		switch fe.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Array:
+			tag += "_items"
+		case reflect.String:
+		default:
+			tag += "_number"
+		}
+	case "enum":
+		if e, ok := utils.LookupEnum(param); ok {
+			param = strings.Join(e.Values(), ", ")
+		}
+		tag = "oneof"
+	case "oneof":
+		param = strings.Join(strings.Fields(param), ", ")
+	}
+
+	template, ok := messages[tag]
+	if !ok {
+		return fmt.Sprintf(messages["default"], fe.Tag())
+	}
+	if strings.Contains(template, "%s") {
+		return fmt.Sprintf(template, param)
+	}
+	return template
+}
+
+// fieldPath retorna o caminho do campo com os nomes do JSON, sem o nome da struct raiz
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+// requestLanguage retorna o idioma negociado para a requisição (ver Language)
+func requestLanguage(c *gin.Context) string {
+	return i18n.FromContext(c)
+}
+
+// jsonFieldName usa o nome do campo no JSON (ou na query) nas mensagens de validação
+func jsonFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupValidators()
+
+	tests := []struct {
+		name           string
+		body           string
+		language       string
+		expectedStatus int
+		expectedErrors []dto.ValidationError
+	}{
+		{
+			name:           "Success - Valid body",
+			body:           `{"name":"João Silva","email":"joao@example.com","userType":"suporte"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Error - Field errors in English",
+			body:           `{"name":"Jo","email":"joao","userType":"OWNER","password":"123"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []dto.ValidationError{
+				{Field: "name", Message: "must have at least 3 characters"},
+				{Field: "email", Message: "must be a valid email address"},
+				{Field: "password", Message: "must have at least 8 characters"},
+				{Field: "userType", Message: "must be one of: ADMIN, MANAGER, AGENT, VIEWER"},
+			},
+		},
+		{
+			name:           "Error - Field errors in Portuguese",
+			body:           `{"email":"joao@example.com","userType":"AGENT","companyId":0}`,
+			language:       "pt-BR,pt;q=0.9",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []dto.ValidationError{
+				{Field: "name", Message: "é obrigatório"},
+				{Field: "companyId", Message: "deve ser no mínimo 1"},
+			},
+		},
+		{
+			name:           "Error - Wrong JSON type",
+			body:           `{"name":123}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []dto.ValidationError{
+				{Field: "name", Message: "must be of type string"},
+			},
+		},
+		{
+			name:           "Error - Malformed JSON",
+			body:           `{"name":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.POST("/users", func(c *gin.Context) {
+				var req dto.CreateUserRequest
+				if !BindJSON(c, &req, "Invalid request body") {
+					return
+				}
+				c.JSON(http.StatusOK, dto.NewSuccessResponse(c, req.UserType, "ok"))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.language != "" {
+				req.Header.Set("Accept-Language", tt.language)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusUnprocessableEntity {
+				return
+			}
+
+			var body dto.ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, http.StatusUnprocessableEntity, body.Code)
+			assert.Equal(t, tt.expectedErrors, body.Errors)
+		})
+	}
+}
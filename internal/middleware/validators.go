@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"log"
+	"orderstreamrest/internal/utils"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// setupValidators registers the custom binding validators used by the DTOs
+func setupValidators() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		log.Println("Binding validator engine is not go-playground/validator, custom validators not registered")
+		return
+	}
+
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	if err := utils.RegisterEnumValidator(v); err != nil {
+		log.Printf("Failed to register enum validator: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"orderstreamrest/internal/models/dto"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionHeader carries the requested version in requests and the served version in responses
+const APIVersionHeader = "API-Version"
+
+const (
+	// APIVersion1 is the first versioned API, served under /api/v1
+	APIVersion1 = "v1"
+	// DefaultAPIVersion is served to unversioned routes that do not ask for a version
+	DefaultAPIVersion = APIVersion1
+
+	apiPathPrefix = "/api/"
+	apiVersionKey = "api_version"
+)
+
+// SupportedAPIVersions lists the versions the API serves, oldest first
+var SupportedAPIVersions = []string{APIVersion1}
+
+// vendorMediaType matches version requests sent in Accept, e.g. application/vnd.visiondata.v1+json
+var vendorMediaType = regexp.MustCompile(`application/vnd\.visiondata\.(v\d+)\+json`)
+
+// APIVersionPrefix returns the route prefix of a version (e.g. /api/v1)
+func APIVersionPrefix(version string) string {
+	return apiPathPrefix + version
+}
+
+// UnversionedPath strips the /api/vN prefix from a route path, so /api/v1/auth/login becomes /auth/login
+func UnversionedPath(path string) string {
+	for _, version := range SupportedAPIVersions {
+		prefix := APIVersionPrefix(version)
+		if path == prefix {
+			return "/"
+		}
+		if strings.HasPrefix(path, prefix+"/") {
+			return strings.TrimPrefix(path, prefix)
+		}
+	}
+	return path
+}
+
+// PinAPIVersion serves a versioned route group: the version comes from the path, whatever the headers ask
+func PinAPIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setAPIVersion(c, version)
+		c.Next()
+	}
+}
+
+// NegotiateAPIVersion serves the legacy unversioned routes. The version comes from the API-Version
+// header or a vendor media type in Accept, falling back to DefaultAPIVersion; unknown versions get 406
+func NegotiateAPIVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := requestedAPIVersion(c.GetHeader(APIVersionHeader), c.GetHeader("Accept"))
+		if version == "" {
+			version = DefaultAPIVersion
+		}
+
+		if !isSupportedAPIVersion(version) {
+			c.AbortWithStatusJSON(http.StatusNotAcceptable, dto.NewErrorResponse(
+				c,
+				http.StatusNotAcceptable,
+				"Not Acceptable",
+				"Unsupported API version "+version,
+				map[string]interface{}{
+					"supported": SupportedAPIVersions,
+				},
+			))
+			return
+		}
+
+		setAPIVersion(c, version)
+		c.Next()
+	}
+}
+
+// GetAPIVersion returns the API version serving the request
+func GetAPIVersion(c *gin.Context) string {
+	if version := c.GetString(apiVersionKey); version != "" {
+		return version
+	}
+	return DefaultAPIVersion
+}
+
+// requestedAPIVersion reads the version asked by the client; the header wins over Accept
+func requestedAPIVersion(header, accept string) string {
+	if version := strings.ToLower(strings.TrimSpace(header)); version != "" {
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		return version
+	}
+
+	if match := vendorMediaType.FindStringSubmatch(strings.ToLower(accept)); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+func isSupportedAPIVersion(version string) bool {
+	for _, supported := range SupportedAPIVersions {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
+
+func setAPIVersion(c *gin.Context, version string) {
+	c.Set(apiVersionKey, version)
+	c.Header(APIVersionHeader, version)
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnversionedPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/api/v1/auth/login", want: "/auth/login"},
+		{path: "/api/v1/tickets/:id", want: "/tickets/:id"},
+		{path: "/api/v1", want: "/"},
+		{path: "/auth/login", want: "/auth/login"},
+		{path: "/api/v10/tickets", want: "/api/v10/tickets"},
+		{path: "/api/v9/tickets", want: "/api/v9/tickets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, UnversionedPath(tt.path))
+		})
+	}
+}
+
+func TestRequestedAPIVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		accept string
+		want   string
+	}{
+		{name: "Header", header: "v1", want: "v1"},
+		{name: "Header without prefix", header: "1", want: "v1"},
+		{name: "Vendor media type", accept: "application/vnd.visiondata.v2+json", want: "v2"},
+		{name: "Header wins over Accept", header: "v1", accept: "application/vnd.visiondata.v2+json", want: "v1"},
+		{name: "Plain JSON asks nothing", accept: "application/json", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, requestedAPIVersion(tt.header, tt.accept))
+		})
+	}
+}
+
+func TestAPIVersionMiddlewares(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	handler := func(c *gin.Context) {
+		c.String(http.StatusOK, GetAPIVersion(c))
+	}
+	engine.GET(APIVersionPrefix(APIVersion1)+"/ping", PinAPIVersion(APIVersion1), handler)
+	engine.GET("/ping", NegotiateAPIVersion(), handler)
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "Versioned path", path: "/api/v1/ping", wantStatus: http.StatusOK, wantBody: "v1"},
+		{name: "Versioned path ignores header", path: "/api/v1/ping", header: "v2", wantStatus: http.StatusOK, wantBody: "v1"},
+		{name: "Legacy path defaults", path: "/ping", wantStatus: http.StatusOK, wantBody: "v1"},
+		{name: "Legacy path with supported header", path: "/ping", header: "1", wantStatus: http.StatusOK, wantBody: "v1"},
+		{name: "Legacy path with unknown version", path: "/ping", header: "v2", wantStatus: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(APIVersionHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+				assert.Equal(t, tt.wantBody, rec.Header().Get(APIVersionHeader))
+			}
+		})
+	}
+}
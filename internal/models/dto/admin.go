@@ -0,0 +1,294 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeprecationClientUsage representa as chamadas de um cliente a uma rota depreciada
+type DeprecationClientUsage struct {
+	Client string `json:"client" example:"user:42"`
+	Calls  int64  `json:"calls" example:"17"`
+}
+
+// DeprecationResponse representa uma rota depreciada e quem ainda a utiliza
+type DeprecationResponse struct {
+	Method       string                   `json:"method" example:"GET"`
+	Path         string                   `json:"path" example:"/metrics/tickets"`
+	Replacement  string                   `json:"replacement,omitempty" example:"/api/v1/metrics/tickets"`
+	DeprecatedAt time.Time                `json:"deprecatedAt" example:"2025-10-01T00:00:00Z"`
+	SunsetAt     *time.Time               `json:"sunsetAt,omitempty" example:"2026-01-01T00:00:00Z"`
+	TotalCalls   int64                    `json:"totalCalls" example:"42"`
+	Clients      []DeprecationClientUsage `json:"clients"`
+}
+
+// WorkerPoolJob representa uma requisição em execução em um pool de baixa prioridade
+type WorkerPoolJob struct {
+	RequestID string    `json:"requestId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Path      string    `json:"path" example:"/tickets/export"`
+	StartedAt time.Time `json:"startedAt" example:"2025-10-16T10:30:00Z"`
+	Processed int64     `json:"processed" example:"1500"`
+	Total     int64     `json:"total,omitempty" example:"10000"`
+}
+
+// WorkerPoolStats representa o uso de um pool de baixa prioridade
+type WorkerPoolStats struct {
+	Name    string          `json:"name" example:"export"`
+	Max     int64           `json:"max" example:"2"`
+	Running int64           `json:"running" example:"1"`
+	Queued  int64           `json:"queued" example:"3"`
+	Jobs    []WorkerPoolJob `json:"jobs"`
+}
+
+// MetricsCacheResponse representa o resultado de uma operação no cache de métricas
+type MetricsCacheResponse struct {
+	DeletedKeys int64  `json:"deletedKeys" example:"12"`
+	Refreshed   bool   `json:"refreshed" example:"true"`
+	TTL         string `json:"ttl" example:"5m0s"`
+}
+
+// LogLevelRequest altera o nível mínimo dos logs da instância
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required" example:"DEBUG"`
+}
+
+// LogLevelResponse representa o nível mínimo dos logs de uma instância
+type LogLevelResponse struct {
+	Level           string `json:"level" example:"DEBUG"`
+	ConfiguredLevel string `json:"configuredLevel" example:"INFO"`
+	PreviousLevel   string `json:"previousLevel,omitempty" example:"INFO"`
+	ExecutionId     string `json:"executionId" example:"3f2a1"`
+}
+
+// ReindexResponse representa a migração de um índice do Elasticsearch para a versão atual do mapping
+type ReindexResponse struct {
+	Alias     string   `json:"alias" example:"support_tickets"`
+	From      []string `json:"from" example:"support_tickets_v1"`
+	To        string   `json:"to" example:"support_tickets_v2"`
+	Documents int64    `json:"documents" example:"125000"`
+	Took      string   `json:"took" example:"1m12s"`
+}
+
+// SynonymSetResponse representa o conjunto de sinônimos ativo no índice de tickets
+type SynonymSetResponse struct {
+	Id          string    `json:"id" example:"3f2a9c1b7d4e"`
+	Source      string    `json:"source" example:"th_pt_BR.dat"`
+	Format      string    `json:"format" example:"thesaurus" enums:"solr,thesaurus"`
+	Rules       int       `json:"rules" example:"18250"`
+	Checksum    string    `json:"checksum" example:"3f2a9c1b7d4e5f60a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718"`
+	ActivatedBy *int      `json:"activatedBy,omitempty" example:"1"`
+	ActivatedAt time.Time `json:"activatedAt" example:"2025-10-16T10:30:00Z"`
+	// Skipped são as regras descartadas pelos filtros da conversão, informado apenas no envio
+	Skipped *int `json:"skipped,omitempty" example:"312"`
+	// Preview são as primeiras regras do conjunto
+	Preview []string `json:"preview" example:"carro, automóvel, veículo"`
+}
+
+// AuditLogResponse representa uma entrada da trilha de auditoria, com o estado do registro antes e depois da ação
+type AuditLogResponse struct {
+	Id         int             `json:"id" example:"1"`
+	ActorId    *int            `json:"actorId,omitempty" example:"1"`
+	Action     string          `json:"action" example:"UPDATE" enums:"CREATE,UPDATE,DELETE,IMPORT,EXPORT"`
+	EntityType string          `json:"entityType" example:"USER"`
+	EntityId   string          `json:"entityId" example:"42"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	IPAddress  *string         `json:"ipAddress,omitempty" example:"192.168.1.100"`
+	CreatedAt  time.Time       `json:"createdAt" example:"2025-10-16T10:30:00Z"`
+}
+
+// TicketVolumeAlertResponse representa um dia em que o volume de tickets ficou acima da linha de base
+type TicketVolumeAlertResponse struct {
+	Id             int        `json:"id" example:"1"`
+	Day            string     `json:"day" example:"2025-10-16"`
+	Tickets        int64      `json:"tickets" example:"412"`
+	BaselineMean   float64    `json:"baselineMean" example:"180.5"`
+	BaselineStdDev float64    `json:"baselineStdDev" example:"22.3"`
+	BaselineDays   int        `json:"baselineDays" example:"28"`
+	ZScore         float64    `json:"zScore" example:"10.38"`
+	Severity       string     `json:"severity" example:"CRITICAL" enums:"WARNING,CRITICAL"`
+	CreatedAt      time.Time  `json:"createdAt" example:"2025-10-16T10:30:00Z"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty" example:"2025-10-16T11:30:00Z"`
+}
+
+// ScheduledJobResponse representa uma rotina em segundo plano e a sua configuração
+type ScheduledJobResponse struct {
+	Name            string   `json:"name" example:"deleted_users_anonymization"`
+	Description     string   `json:"description" example:"Anonymizes auth logs of deleted users"`
+	Enabled         bool     `json:"enabled" example:"true"`
+	IntervalMinutes int      `json:"intervalMinutes" example:"1440"`
+	RetentionDays   int      `json:"retentionDays" example:"30"`
+	Policy          string   `json:"policy,omitempty" example:"ANONYMIZE"`
+	Policies        []string `json:"policies,omitempty" example:"ANONYMIZE,DELETE"`
+	// RunsOnStartup indica que a rotina também é executada quando uma instância inicia
+	RunsOnStartup bool       `json:"runsOnStartup" example:"false"`
+	LastRunAt     *time.Time `json:"lastRunAt,omitempty" example:"2025-10-16T03:00:00Z"`
+	NextRunAt     *time.Time `json:"nextRunAt,omitempty" example:"2025-10-17T03:00:00Z"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty" example:"2025-10-16T10:30:00Z"`
+	UpdatedBy     *int       `json:"updatedBy,omitempty" example:"1"`
+}
+
+// UpdateScheduledJobRequest altera a configuração de uma rotina; campos ausentes são mantidos
+type UpdateScheduledJobRequest struct {
+	Enabled         *bool   `json:"enabled,omitempty" example:"true"`
+	IntervalMinutes *int    `json:"intervalMinutes,omitempty" example:"1440"`
+	RetentionDays   *int    `json:"retentionDays,omitempty" example:"30"`
+	Policy          *string `json:"policy,omitempty" example:"DELETE"`
+}
+
+// JobRunResponse representa uma execução de rotina em segundo plano
+type JobRunResponse struct {
+	Id           int        `json:"id" example:"1"`
+	JobName      string     `json:"jobName" example:"deleted_users_anonymization"`
+	Source       string     `json:"source" example:"SCHEDULE" enums:"SCHEDULE,MANUAL,STARTUP"`
+	TriggeredBy  *int       `json:"triggeredBy,omitempty" example:"1"`
+	StartedAt    time.Time  `json:"startedAt" example:"2025-10-16T03:00:00Z"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty" example:"2025-10-16T03:00:02Z"`
+	Success      bool       `json:"success" example:"true"`
+	Affected     int64      `json:"affected" example:"120"`
+	ErrorMessage *string    `json:"errorMessage,omitempty" example:"context deadline exceeded"`
+}
+
+// QueuedJobResponse representa uma tarefa da fila de processamento assíncrono e o seu resultado
+type QueuedJobResponse struct {
+	Id           string          `json:"id" example:"9b2f6c1e-4d3a-4f1b-8e2a-6c5d4b3a2f10"`
+	Type         string          `json:"type" example:"search_reindex"`
+	Status       string          `json:"status" example:"SUCCEEDED" enums:"QUEUED,RUNNING,SUCCEEDED,FAILED"`
+	Attempts     int             `json:"attempts" example:"1"`
+	MaxAttempts  int             `json:"maxAttempts" example:"3"`
+	Payload      json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+	Result       json.RawMessage `json:"result,omitempty" swaggertype:"object"`
+	ErrorMessage *string         `json:"errorMessage,omitempty" example:"context deadline exceeded"`
+	RequestedBy  *int            `json:"requestedBy,omitempty" example:"1"`
+	CreatedAt    time.Time       `json:"createdAt" example:"2025-10-16T10:30:00Z"`
+	StartedAt    *time.Time      `json:"startedAt,omitempty" example:"2025-10-16T10:30:01Z"`
+	FinishedAt   *time.Time      `json:"finishedAt,omitempty" example:"2025-10-16T10:30:05Z"`
+}
+
+// LogEntryResponse representa uma entrada do log da aplicação, com o contexto HTTP quando gerada por uma requisição
+type LogEntryResponse struct {
+	Id         string                 `json:"id" example:"5f0c6a1e-8f3b-4c2a-9a57-0d2f3e1b7c44"`
+	Timestamp  time.Time              `json:"timestamp" example:"2025-10-16T10:30:00Z"`
+	Level      string                 `json:"level" example:"ERROR" enums:"DEBUG,INFO,WARN,ERROR,FATAL"`
+	Message    string                 `json:"message" example:"HTTP Server Error"`
+	Hostname   string                 `json:"hostname,omitempty" example:"api-7d9f8"`
+	RequestId  string                 `json:"requestId,omitempty" example:"9b2d4c1e-3f6a-4b8c-8e21-7a5d0c9f1b34"`
+	Method     string                 `json:"method,omitempty" example:"GET"`
+	Path       string                 `json:"path,omitempty" example:"/tickets/123"`
+	StatusCode int                    `json:"statusCode,omitempty" example:"500"`
+	DurationMs float64                `json:"durationMs,omitempty" example:"12.5"`
+	UserId     string                 `json:"userId,omitempty" example:"42"`
+	TraceId    string                 `json:"traceId,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	Error      string                 `json:"error,omitempty" example:"connection refused"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+}
+
+// UsersSummary representa a quantidade de usuários ativos, por tipo, e inativos
+type UsersSummary struct {
+	Active       int64            `json:"active" example:"42"`
+	Inactive     int64            `json:"inactive" example:"3"`
+	ActiveByRole map[string]int64 `json:"activeByRole"`
+}
+
+// TicketsSummary representa o total de tickets do DW e os abertos nos últimos 30 dias
+type TicketsSummary struct {
+	Total      int64 `json:"total" example:"125000"`
+	Last30Days int64 `json:"last30Days" example:"3200"`
+}
+
+// ErrorRateSummary representa as requisições registradas nos logs na janela e quantas terminaram em
+// erro. ServerErrorPercent é a porcentagem das requisições que responderam 5xx.
+type ErrorRateSummary struct {
+	WindowMinutes      int     `json:"windowMinutes" example:"60"`
+	Requests           int64   `json:"requests" example:"5400"`
+	ClientErrors       int64   `json:"clientErrors" example:"120"`
+	ServerErrors       int64   `json:"serverErrors" example:"3"`
+	ServerErrorPercent float64 `json:"serverErrorPercent" example:"0.06"`
+}
+
+// AdminSummaryResponse reúne os indicadores da página inicial do administrador. Uma seção cuja fonte
+// não respondeu vem nula e é listada em unavailable.
+type AdminSummaryResponse struct {
+	Users       *UsersSummary     `json:"users"`
+	Tickets     *TicketsSummary   `json:"tickets"`
+	Errors      *ErrorRateSummary `json:"errors"`
+	Unavailable []string          `json:"unavailable,omitempty" example:"errors"`
+}
+
+// TagResponse representa uma tag canônica do catálogo, com o total de tickets no DW e as grafias unificadas nela.
+// TagKey é 0 para tags que só existem como destino de aliases.
+type TagResponse struct {
+	TagKey  int64    `json:"tagKey" example:"12"`
+	Name    string   `json:"name" example:"Boleto"`
+	Tickets int64    `json:"tickets" example:"3400"`
+	Aliases []string `json:"aliases" example:"boletos"`
+}
+
+// MergeTagsRequest representa a unificação das tags de From na tag To
+type MergeTagsRequest struct {
+	From []string `json:"from" binding:"required,min=1,max=100" example:"boletos,BOLETO"`
+	To   string   `json:"to" binding:"required" example:"Boleto"`
+}
+
+// MergeTagsResponse representa o resultado da unificação de tags. RewriteJobId é a tarefa que reescreve
+// os tickets no Elasticsearch; vem vazio quando a fila está indisponível e a rotina diária faz a reescrita.
+type MergeTagsResponse struct {
+	Tag          string   `json:"tag" example:"Boleto"`
+	TagKey       int64    `json:"tagKey" example:"12"`
+	Renamed      bool     `json:"renamed" example:"false"`
+	MergedTags   int      `json:"mergedTags" example:"2"`
+	MovedTickets int64    `json:"movedTickets" example:"180"`
+	Aliases      []string `json:"aliases" example:"boletos"`
+	RewriteJobId *string  `json:"rewriteJobId,omitempty" example:"9b2f6c1e-4d3a-4f1b-8e2a-6c5d4b3a2f10"`
+}
+
+// QuotaUsage representa a cota diária de um usuário em uma operação cara e o consumo do dia (UTC).
+// Limit 0 indica operação sem cota; nesse caso Remaining vem vazio.
+type QuotaUsage struct {
+	Action       string    `json:"action" example:"export"`
+	Limit        int       `json:"limit" example:"100"`
+	DefaultLimit int       `json:"defaultLimit" example:"50"`
+	Custom       bool      `json:"custom" example:"true"`
+	Used         int64     `json:"used" example:"12"`
+	Remaining    *int64    `json:"remaining,omitempty" example:"88"`
+	ResetAt      time.Time `json:"resetAt" example:"2025-10-17T00:00:00Z"`
+}
+
+// UserQuotasResponse representa as cotas diárias de um usuário
+type UserQuotasResponse struct {
+	UserId int          `json:"userId" example:"42"`
+	Quotas []QuotaUsage `json:"quotas"`
+}
+
+// UpdateQuotaRequest ajusta a cota diária de um usuário em uma operação. Campos omitidos não mudam:
+// Limit define uma cota própria (0 libera a operação), UseDefault volta ao limite padrão e
+// ResetUsage zera o consumo do dia.
+type UpdateQuotaRequest struct {
+	Action     string `json:"action" binding:"required" example:"export"`
+	Limit      *int   `json:"limit" binding:"omitempty,min=0" example:"100"`
+	UseDefault bool   `json:"useDefault" example:"false"`
+	ResetUsage bool   `json:"resetUsage" example:"false"`
+}
+
+// IPRules representa as redes (IPs ou CIDRs) aceitas e recusadas nas rotas sensíveis. Allow vazio aceita
+// qualquer rede; Deny vale mesmo para as redes de Allow. Source indica se as listas vêm da configuração
+// (IP_ALLOWLIST e IP_DENYLIST) ou de uma alteração feita pelos administradores.
+type IPRules struct {
+	Allow     []string   `json:"allow" example:"10.8.0.0/16"`
+	Deny      []string   `json:"deny" example:"10.8.4.17"`
+	Source    string     `json:"source" example:"admin" enums:"config,admin"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" example:"2025-10-16T14:30:00Z"`
+	UpdatedBy *int       `json:"updatedBy,omitempty" example:"1"`
+}
+
+// IPRulesResponse representa as regras de IP em vigor e o IP com que a requisição chegou
+type IPRulesResponse struct {
+	IPRules
+	ClientIP string `json:"clientIp" example:"10.8.2.31"`
+}
+
+// UpdateIPRulesRequest substitui as listas de redes aceitas e recusadas
+type UpdateIPRulesRequest struct {
+	Allow []string `json:"allow" binding:"omitempty,max=200,dive,required" example:"10.8.0.0/16"`
+	Deny  []string `json:"deny" binding:"omitempty,max=200,dive,required" example:"10.8.4.17"`
+}
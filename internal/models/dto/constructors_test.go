@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envelopeTypes são as respostas que só podem ser montadas pelos construtores deste pacote: os envelopes,
+// que preenchem request_id e o timestamp em UTC, e o usuário, convertido em um único lugar
+var envelopeTypes = map[string]string{
+	"BaseResponse":      "NewSuccessResponse, NewErrorResponse or NewPaginatedResponse",
+	"SuccessResponse":   "NewSuccessResponse",
+	"ErrorResponse":     "NewErrorResponse",
+	"PaginatedResponse": "NewPaginatedResponse",
+	"UserResponse":      "NewUserResponse",
+}
+
+// TestEnvelopesUseConstructors falha quando um handler, middleware ou repositório monta um envelope de
+// resposta como literal em vez de chamar o construtor
+func TestEnvelopesUseConstructors(t *testing.T) {
+	fset := token.NewFileSet()
+	var violations []string
+
+	for _, root := range []string{"../../service", "../../middleware", "../../repositories"} {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+
+			ast.Inspect(file, func(node ast.Node) bool {
+				literal, ok := node.(*ast.CompositeLit)
+				if !ok {
+					return true
+				}
+				selector, ok := literal.Type.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				pkg, ok := selector.X.(*ast.Ident)
+				if !ok || pkg.Name != "dto" {
+					return true
+				}
+				if constructor, ok := envelopeTypes[selector.Sel.Name]; ok {
+					violations = append(violations, fset.Position(literal.Pos()).String()+": dto."+selector.Sel.Name+" literal, use dto."+constructor)
+				}
+				return true
+			})
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Empty(t, violations)
+}
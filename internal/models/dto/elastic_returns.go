@@ -23,6 +23,35 @@ type ESResponse struct {
 			ID     string          `json:"_id"`
 			Score  float64         `json:"_score"`
 			Source json.RawMessage `json:"_source"`
+			Sort   []interface{}   `json:"sort,omitempty"`
+			// SeqNo e PrimaryTerm só vêm preenchidos quando a busca pede seq_no_primary_term
+			SeqNo       *int64 `json:"_seq_no,omitempty"`
+			PrimaryTerm *int64 `json:"_primary_term,omitempty"`
+			// InnerHits só vem preenchido quando uma query nested pede inner_hits
+			InnerHits map[string]ESInnerHits `json:"inner_hits,omitempty"`
 		} `json:"hits"`
 	} `json:"hits"`
+	Aggregations map[string]ESTermsAggregation `json:"aggregations,omitempty"`
+}
+
+// ESInnerHits são os objetos nested que atenderam à query de um hit
+type ESInnerHits struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source json.RawMessage `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// ESTermsAggregation é o resultado de uma agregação terms (ou date_histogram, que também traz
+// key_as_string no formato pedido)
+type ESTermsAggregation struct {
+	Buckets []struct {
+		Key         interface{} `json:"key"`
+		KeyAsString string      `json:"key_as_string,omitempty"`
+		DocCount    int64       `json:"doc_count"`
+	} `json:"buckets"`
 }
@@ -2,6 +2,8 @@
 package dto
 
 import (
+	"net/http"
+	"orderstreamrest/internal/i18n"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,25 +11,25 @@ import (
 
 // BaseResponse contém campos comuns a todas as respostas
 type BaseResponse struct {
-	Success   bool      `json:"success"`
-	Timestamp time.Time `json:"timestamp"`
-	RequestID string    `json:"request_id,omitempty"`
+	Success   bool      `json:"success" example:"true"`
+	Timestamp time.Time `json:"timestamp" example:"2025-10-16T10:30:00Z"`
+	RequestID string    `json:"request_id,omitempty" example:"c0a8012e-4f1b-4e2a-9b3d-6f5e4d3c2b1a"`
 }
 
 // SuccessResponse representa uma resposta de sucesso
 type SuccessResponse struct {
 	BaseResponse
 	Data    interface{} `json:"data,omitempty"`
-	Message string      `json:"message,omitempty"`
+	Message string      `json:"message,omitempty" example:"Ticket retrieved successfully"`
 }
 
 // ErrorResponse representa uma resposta de erro
 type ErrorResponse struct {
 	BaseResponse
-	Error   string      `json:"error"`
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Details interface{} `json:"details,omitempty"`
+	Error   string      `json:"error" example:"Bad Request"`
+	Code    int         `json:"code" example:"400"`
+	Message string      `json:"message" example:"Invalid request body"`
+	Details interface{} `json:"details,omitempty" swaggertype:"string" example:"title is required"`
 }
 
 // PaginatedResponse representa uma resposta paginada
@@ -35,7 +37,17 @@ type PaginatedResponse struct {
 	BaseResponse
 	Data       interface{} `json:"data"`
 	Pagination Pagination  `json:"pagination"`
-	Message    string      `json:"message,omitempty"`
+	Facets     Facets      `json:"facets,omitempty"`
+	Message    string      `json:"message,omitempty" example:"Users retrieved successfully"`
+}
+
+// Facets agrupa, por campo (status, priority, category, channel), a contagem de resultados de cada valor
+type Facets map[string][]FacetValue
+
+// FacetValue é a quantidade de resultados de um valor do campo
+type FacetValue struct {
+	Value string `json:"value" example:"ABERTO"`
+	Count int64  `json:"count" example:"42"`
 }
 
 // Pagination contém informações de paginação
@@ -46,6 +58,17 @@ type Pagination struct {
 	TotalRecords int64 `json:"total_records" example:"50"`
 	HasNext      bool  `json:"has_next" example:"true"`
 	HasPrev      bool  `json:"has_prev" example:"false"`
+	// NextCursor abre a próxima página sem o limite de profundidade da paginação por número (só na busca de tickets)
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJwIjozLCJzIjo1MH0"`
+	// Links são os endereços das páginas vizinhas, com os mesmos filtros da requisição
+	Links *PaginationLinks `json:"links,omitempty"`
+}
+
+// PaginationLinks contém os endereços da página atual e das vizinhas; Next e Prev ficam vazios nas pontas
+type PaginationLinks struct {
+	Self string `json:"self" example:"/api/v1/users?page=2&pageSize=10"`
+	Next string `json:"next,omitempty" example:"/api/v1/users?page=3&pageSize=10"`
+	Prev string `json:"prev,omitempty" example:"/api/v1/users?page=1&pageSize=10"`
 }
 
 // Parâmetros de busca
@@ -53,6 +76,15 @@ type SearchParams struct {
 	Query    string `form:"q"`
 	Page     int    `form:"page"`
 	PageSize int    `form:"page_size"`
+	// Mode escolhe a query de busca para comparar relevância: analyzed (padrão) ou legacy
+	Mode string `form:"mode" binding:"omitempty,oneof=analyzed legacy"`
+	// Cursor continua a busca a partir de pagination.next_cursor; quando informado, page e page_size são ignorados
+	Cursor string `form:"cursor" binding:"omitempty,max=8192"`
+	// HasAttachments filtra os tickets com (true) ou sem (false) anexos
+	HasAttachments *bool `form:"has_attachments"`
+	// AttachmentType filtra os tickets com ao menos um anexo da categoria (image, video, audio, document,
+	// archive) ou do mime type (image/png, image/*)
+	AttachmentType string `form:"attachment_type" binding:"omitempty,max=100"`
 }
 
 // HealthResponse representa a resposta do healthcheck
@@ -63,6 +95,34 @@ type HealthResponse struct {
 	Version string            `json:"version" example:"1.0.0"`
 	Uptime  string            `json:"uptime,omitempty" example:"1h30m45s"`
 	Checks  map[string]string `json:"checks,omitempty"`
+	// Dependencies detalha cada verificação com a latência medida
+	Dependencies map[string]DependencyHealth `json:"dependencies,omitempty"`
+	// DatabasePool mostra a ocupação do pool de conexões do SQL Server
+	DatabasePool *DatabasePoolStats `json:"databasePool,omitempty"`
+	// ReplicaPool mostra a ocupação do pool da réplica de leitura das métricas, quando configurada
+	ReplicaPool *DatabasePoolStats `json:"replicaPool,omitempty"`
+	// Breakers mostra o estado de cada circuit breaker: closed, half-open ou open
+	Breakers map[string]string `json:"breakers,omitempty" example:"es_search:closed"`
+}
+
+// DatabasePoolStats representa o estado do pool de conexões do SQL Server
+type DatabasePoolStats struct {
+	MaxOpenConnections int   `json:"maxOpenConnections" example:"25"`
+	OpenConnections    int   `json:"openConnections" example:"4"`
+	InUse              int   `json:"inUse" example:"1"`
+	Idle               int   `json:"idle" example:"3"`
+	WaitCount          int64 `json:"waitCount" example:"0"`
+	WaitDurationMs     int64 `json:"waitDurationMs" example:"0"`
+	MaxIdleClosed      int64 `json:"maxIdleClosed" example:"0"`
+	MaxLifetimeClosed  int64 `json:"maxLifetimeClosed" example:"2"`
+}
+
+// DependencyHealth representa o resultado da verificação de uma dependência
+type DependencyHealth struct {
+	Status    string `json:"status" example:"OK" enums:"OK,UNAVAILABLE"`
+	Critical  bool   `json:"critical" example:"true"`
+	LatencyMs int64  `json:"latencyMs" example:"12"`
+	Error     string `json:"error,omitempty" example:"context deadline exceeded"`
 }
 
 // AuthErrorResponse representa erros específicos de autenticação
@@ -70,20 +130,39 @@ type AuthErrorResponse struct {
 	BaseResponse
 	Error    string `json:"error" example:"unauthorized"`
 	Code     int    `json:"code" example:"401"`
-	Message  string `json:"message" example:"Token de autorização inválido ou expirado"`
+	Message  string `json:"message" example:"Invalid token"`
 	LoginURL string `json:"login_url,omitempty" example:"/auth/login"`
 }
 
-// RateLimitErrorResponse representa erros de rate limit
+// Motivos das respostas 429/503, para que os clientes decidam como reagir sem interpretar a mensagem
+const (
+	// ReasonRateLimited indica que o IP excedeu o limite de requisições da janela
+	ReasonRateLimited = "rate_limited"
+	// ReasonConcurrencyLimit indica que o servidor atingiu o limite de requisições simultâneas
+	ReasonConcurrencyLimit = "concurrency_limit"
+	// ReasonQueueTimeout indica que a requisição esperou demais na fila de um pool de baixa prioridade
+	ReasonQueueTimeout = "queue_timeout"
+	// ReasonRateLimiterUnavailable indica que o rate limiter está indisponível e configurado para recusar requisições
+	ReasonRateLimiterUnavailable = "rate_limiter_unavailable"
+	// ReasonDependencyUnavailable indica que o circuito de uma dependência (Elasticsearch, SQL Server) está aberto
+	ReasonDependencyUnavailable = "dependency_unavailable"
+	// ReasonQuotaExceeded indica que o usuário esgotou a cota diária da operação; a cota volta à meia-noite UTC
+	ReasonQuotaExceeded = "quota_exceeded"
+)
+
+// RateLimitErrorResponse representa as respostas 429 e 503 de limitação de carga e indisponibilidade
 type RateLimitErrorResponse struct {
 	BaseResponse
-	Error      string    `json:"error" example:"rate_limit_exceeded"`
-	Code       int       `json:"code" example:"429"`
-	Message    string    `json:"message" example:"Limite de requisições excedido"`
-	RetryAfter string    `json:"retry_after" example:"60s"`
-	Limit      int       `json:"limit" example:"100"`
-	Remaining  int       `json:"remaining" example:"0"`
-	ResetTime  time.Time `json:"reset_time" example:"2024-01-01T12:01:00Z"`
+	Error             string    `json:"error" example:"rate_limit_exceeded"`
+	Code              int       `json:"code" example:"429"`
+	Message           string    `json:"message" example:"Rate limit exceeded"`
+	Reason            string    `json:"reason" example:"rate_limited" enums:"rate_limited,concurrency_limit,queue_timeout,rate_limiter_unavailable,dependency_unavailable,quota_exceeded"`
+	Retryable         bool      `json:"retryable" example:"true"`
+	RetryAfter        string    `json:"retry_after" example:"60s"`
+	RetryAfterSeconds int       `json:"retry_after_seconds" example:"60"`
+	Limit             int       `json:"limit,omitempty" example:"100"`
+	Remaining         int       `json:"remaining" example:"0"`
+	ResetTime         time.Time `json:"reset_time" example:"2024-01-01T12:01:00Z"`
 }
 
 // Helper functions para criar responses padronizadas
@@ -97,7 +176,7 @@ func NewSuccessResponse(c *gin.Context, data interface{}, message string) Succes
 			RequestID: getRequestID(c),
 		},
 		Data:    data,
-		Message: message,
+		Message: translate(c, message),
 	}
 }
 
@@ -111,7 +190,7 @@ func NewErrorResponse(c *gin.Context, code int, error string, message string, de
 		},
 		Error:   error,
 		Code:    code,
-		Message: message,
+		Message: translate(c, message),
 		Details: details,
 	}
 }
@@ -126,7 +205,7 @@ func NewPaginatedResponse(c *gin.Context, data interface{}, pagination Paginatio
 		},
 		Data:       data,
 		Pagination: pagination,
-		Message:    message,
+		Message:    translate(c, message),
 	}
 }
 
@@ -156,27 +235,65 @@ func NewAuthErrorResponse(c *gin.Context, message string) AuthErrorResponse {
 		},
 		Error:    "unauthorized",
 		Code:     401,
-		Message:  message,
+		Message:  translate(c, message),
 		LoginURL: "/auth/login",
 	}
 }
 
-// NewRateLimitErrorResponse cria uma nova resposta de rate limit
-func NewRateLimitErrorResponse(c *gin.Context, retryAfter string, limit, remaining int, resetTime time.Time) RateLimitErrorResponse {
+// NewValidationErrorResponse cria uma resposta 422 com os erros de cada campo
+func NewValidationErrorResponse(c *gin.Context, message string, errors []ValidationError) ValidationErrorResponse {
+	return ValidationErrorResponse{
+		BaseResponse: BaseResponse{
+			Success:   false,
+			Timestamp: time.Now().UTC(),
+			RequestID: getRequestID(c),
+		},
+		Error:   "Validation Failed",
+		Code:    http.StatusUnprocessableEntity,
+		Message: translate(c, message),
+		Errors:  errors,
+	}
+}
+
+// NewRateLimitErrorResponse cria uma resposta 429 ou 503 padronizada. A requisição é considerada
+// repetível quando há um tempo de espera sugerido.
+func NewRateLimitErrorResponse(c *gin.Context, status int, reason, message string, retryAfter time.Duration, limit, remaining int) RateLimitErrorResponse {
+	errorCode := "rate_limit_exceeded"
+	if status == http.StatusServiceUnavailable {
+		errorCode = "service_unavailable"
+	}
+
 	return RateLimitErrorResponse{
 		BaseResponse: BaseResponse{
 			Success:   false,
 			Timestamp: time.Now().UTC(),
 			RequestID: getRequestID(c),
 		},
-		Error:      "rate_limit_exceeded",
-		Code:       429,
-		Message:    "Limite de requisições excedido",
-		RetryAfter: retryAfter,
-		Limit:      limit,
-		Remaining:  remaining,
-		ResetTime:  resetTime,
+		Error:             errorCode,
+		Code:              status,
+		Message:           translate(c, message),
+		Reason:            reason,
+		Retryable:         retryAfter > 0,
+		RetryAfter:        retryAfter.String(),
+		RetryAfterSeconds: RetryAfterSeconds(retryAfter),
+		Limit:             limit,
+		Remaining:         remaining,
+		ResetTime:         time.Now().UTC().Add(retryAfter),
+	}
+}
+
+// RetryAfterSeconds converte a espera para segundos inteiros, arredondando para cima,
+// como exige o header Retry-After
+func RetryAfterSeconds(retryAfter time.Duration) int {
+	if retryAfter <= 0 {
+		return 0
 	}
+	return int((retryAfter + time.Second - 1) / time.Second)
+}
+
+// translate traduz a mensagem para o idioma negociado na requisição (header Accept-Language)
+func translate(c *gin.Context, message string) string {
+	return i18n.Translate(i18n.FromContext(c), message)
 }
 
 // getRequestID extrai o request ID do contexto
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/i18n"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryAfterSeconds(t *testing.T) {
+	assert.Equal(t, 0, RetryAfterSeconds(0))
+	assert.Equal(t, 1, RetryAfterSeconds(200*time.Millisecond))
+	assert.Equal(t, 30, RetryAfterSeconds(30*time.Second))
+	assert.Equal(t, 31, RetryAfterSeconds(30*time.Second+time.Millisecond))
+}
+
+func TestNewRateLimitErrorResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	tooMany := NewRateLimitErrorResponse(c, http.StatusTooManyRequests, ReasonRateLimited, "Rate limit exceeded", 42*time.Second, 1500, 0)
+	assert.Equal(t, "rate_limit_exceeded", tooMany.Error)
+	assert.Equal(t, http.StatusTooManyRequests, tooMany.Code)
+	assert.Equal(t, ReasonRateLimited, tooMany.Reason)
+	assert.True(t, tooMany.Retryable)
+	assert.Equal(t, 42, tooMany.RetryAfterSeconds)
+	assert.Equal(t, 1500, tooMany.Limit)
+
+	unavailable := NewRateLimitErrorResponse(c, http.StatusServiceUnavailable, ReasonQueueTimeout, "Too many exports in progress", 0, 0, 0)
+	assert.Equal(t, "service_unavailable", unavailable.Error)
+	assert.False(t, unavailable.Retryable)
+	assert.Equal(t, 0, unavailable.RetryAfterSeconds)
+}
+
+func TestResponsesAreTranslated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		language string
+		message  string
+		expected string
+	}{
+		{name: "english", language: i18n.English, message: "User not found", expected: "User not found"},
+		{name: "portuguese", language: i18n.PortugueseBR, message: "User not found", expected: "Usuário não encontrado"},
+		{name: "message without translation", language: i18n.PortugueseBR, message: "Invalid start date 2025-13-01", expected: "Invalid start date 2025-13-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set(i18n.ContextKey, tt.language)
+
+			assert.Equal(t, tt.expected, NewErrorResponse(c, http.StatusNotFound, "Not Found", tt.message, nil).Message)
+			assert.Equal(t, tt.expected, NewSuccessResponse(c, nil, tt.message).Message)
+			assert.Equal(t, tt.expected, NewPaginatedResponse(c, nil, Pagination{}, tt.message).Message)
+			assert.Equal(t, tt.expected, NewAuthErrorResponse(c, tt.message).Message)
+		})
+	}
+}
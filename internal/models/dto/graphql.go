@@ -0,0 +1,8 @@
+package dto
+
+// GraphQLRequest representa uma operação enviada para /graphql
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required" example:"{ me { id name } }"`
+	OperationName string                 `json:"operationName,omitempty" example:"Me"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
@@ -1,43 +1,58 @@
 package dto
 
+import "time"
+
 // TicketsMetricsResponse representa a resposta das métricas de tickets
 type MetricValue struct {
-	Name  string `json:"name"`
-	Value int64  `json:"value"`
+	Name  string `json:"name" example:"Alta"`
+	Value int64  `json:"value" example:"320"`
 }
 
 // Metric representa uma métrica com seus valores
 type TypeMetric struct {
-	Name   string        `json:"name"`
+	Name   string        `json:"name" example:"priority"`
 	Values []MetricValue `json:"values"`
 }
 
 // MetricValue representa um valor individual de métrica
 type TicketsMetricsResponse struct {
-	TotalTickets int64        `json:"totalTickets"`
+	TotalTickets int64        `json:"totalTickets" example:"1730"`
 	Metrics      []TypeMetric `json:"metrics"`
 }
 
 type MeanTimeByPriority struct {
-	PriorityName string  `json:"priorityName"`
-	MeanTimeHour float64 `json:"meanTimeHour"`
-	MeanTimeDay  float64 `json:"meanTimeDay"`
+	PriorityName   string  `json:"priorityName" example:"Alta"`
+	MeanTimeHour   float64 `json:"meanTimeHour" example:"30.5"`
+	MeanTimeDay    float64 `json:"meanTimeDay" example:"1.27"`
+	MedianTimeHour float64 `json:"medianTimeHour" example:"12"`
+	P90TimeHour    float64 `json:"p90TimeHour" example:"72"`
+	P99TimeHour    float64 `json:"p99TimeHour" example:"260.4"`
+}
+
+// PercentileMetrics é a distribuição do tempo de resolução, em horas, dos tickets fechados de um valor de dimensão
+type PercentileMetrics struct {
+	Name      string  `json:"name" example:"Alta"`
+	Tickets   int64   `json:"tickets" example:"240"`
+	MeanHours float64 `json:"meanHours" example:"30.5"`
+	P50Hours  float64 `json:"p50Hours" example:"12"`
+	P90Hours  float64 `json:"p90Hours" example:"72"`
+	P99Hours  float64 `json:"p99Hours" example:"260.4"`
 }
 
 // MonthlyCounts representa a contagem de tickets para cada mês.
 type MonthlyCounts struct {
-	Janeiro   int64 `json:"janeiro"`
-	Fevereiro int64 `json:"fevereiro"`
-	Marco     int64 `json:"marco"`
-	Abril     int64 `json:"abril"`
-	Maio      int64 `json:"maio"`
-	Junho     int64 `json:"junho"`
-	Julho     int64 `json:"julho"`
-	Agosto    int64 `json:"agosto"`
-	Setembro  int64 `json:"setembro"`
-	Outubro   int64 `json:"outubro"`
-	Novembro  int64 `json:"novembro"`
-	Dezembro  int64 `json:"dezembro"`
+	Janeiro   int64 `json:"janeiro" example:"42"`
+	Fevereiro int64 `json:"fevereiro" example:"38"`
+	Marco     int64 `json:"marco" example:"51"`
+	Abril     int64 `json:"abril" example:"47"`
+	Maio      int64 `json:"maio" example:"40"`
+	Junho     int64 `json:"junho" example:"36"`
+	Julho     int64 `json:"julho" example:"44"`
+	Agosto    int64 `json:"agosto" example:"49"`
+	Setembro  int64 `json:"setembro" example:"53"`
+	Outubro   int64 `json:"outubro" example:"58"`
+	Novembro  int64 `json:"novembro" example:"0"`
+	Dezembro  int64 `json:"dezembro" example:"0"`
 }
 
 // YearlyData é um mapa de anos (string) para uma lista de contagens mensais.
@@ -46,6 +61,82 @@ type YearlyData map[string][]MonthlyCounts
 // TicketsByStatusYearMonth é um mapa de status (string) para seus dados anuais.
 type TicketsByStatusYearMonth map[string]YearlyData
 
+// MonthlyPoint é a contagem de um mês, sem depender do idioma; month vai de 1 a 12.
+// Alternativa a MonthlyCounts retornada com ?format=timeseries.
+type MonthlyPoint struct {
+	Year  int   `json:"year" example:"2025"`
+	Month int   `json:"month" example:"1"`
+	Count int64 `json:"count" example:"42"`
+}
+
+// TopValue é um dos maiores valores de uma dimensão, com a participação no total de tickets da dimensão
+type TopValue struct {
+	Name  string  `json:"name" example:"Financeiro"`
+	Value int64   `json:"value" example:"320"`
+	Share float64 `json:"share" example:"18.5"`
+}
+
+// TopDimension reúne os maiores valores de uma dimensão
+type TopDimension struct {
+	Dimension string     `json:"dimension" example:"category"`
+	Total     int64      `json:"total" example:"1730"`
+	Values    []TopValue `json:"values"`
+}
+
+// TicketsTopResponse é a resposta do top-N de tickets por dimensão
+type TicketsTopResponse struct {
+	N          int            `json:"n" example:"10"`
+	Dimensions []TopDimension `json:"dimensions"`
+}
+
+// TrendPoint é o total de tickets de uma semana ou mês e a variação em relação ao período anterior.
+// deltaPercent é omitido quando o período anterior não teve tickets.
+type TrendPoint struct {
+	PeriodStart  string   `json:"periodStart" example:"2025-10-13"`
+	PeriodEnd    string   `json:"periodEnd" example:"2025-10-19"`
+	Count        int64    `json:"count" example:"120"`
+	Delta        int64    `json:"delta" example:"15"`
+	DeltaPercent *float64 `json:"deltaPercent,omitempty" example:"14.3"`
+	// Partial indica que o período ainda não terminou na data de referência
+	Partial bool `json:"partial" example:"false"`
+}
+
+// TrendMover é um valor de dimensão com a variação entre o período atual e o anterior
+type TrendMover struct {
+	Name         string   `json:"name" example:"Financeiro"`
+	Current      int64    `json:"current" example:"48"`
+	Previous     int64    `json:"previous" example:"30"`
+	Delta        int64    `json:"delta" example:"18"`
+	DeltaPercent *float64 `json:"deltaPercent,omitempty" example:"60"`
+}
+
+// TicketsTrendResponse é a resposta da tendência de tickets semana a semana ou mês a mês
+type TicketsTrendResponse struct {
+	Granularity string       `json:"granularity" example:"week" enums:"week,month"`
+	Points      []TrendPoint `json:"points"`
+	Dimension   string       `json:"dimension,omitempty" example:"category"`
+	Movers      []TrendMover `json:"movers,omitempty"`
+}
+
+// HistogramBucket é a quantidade de tickets abertos no período que começa em start
+type HistogramBucket struct {
+	Start string `json:"start" example:"2025-10-13"`
+	Count int64  `json:"count" example:"42"`
+}
+
+// TicketsHistogramResponse é a série de tickets abertos por dia, semana ou mês entre startDate e endDate
+type TicketsHistogramResponse struct {
+	Interval  string            `json:"interval" example:"week" enums:"day,week,month"`
+	StartDate string            `json:"startDate" example:"2025-04-28"`
+	EndDate   string            `json:"endDate" example:"2025-10-19"`
+	Timezone  string            `json:"timezone" example:"America/Sao_Paulo"`
+	Total     int64             `json:"total" example:"1250"`
+	Buckets   []HistogramBucket `json:"buckets"`
+}
+
+// GroupedTimeseries é um mapa do grupo (status ou prioridade) para a sua série mensal
+type GroupedTimeseries map[string][]MonthlyPoint
+
 type Months struct {
 	Month string `json:"month"`
 	Total int64  `json:"total"`
@@ -56,3 +147,70 @@ type TicketsByMonth struct {
 	Mes          int   `json:"mes"`
 	TotalTickets int64 `json:"totalTickets"`
 }
+
+// MetricsFilter restringe as métricas ao período de abertura e às dimensões dos tickets.
+// Campos nulos não filtram.
+type MetricsFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Year      *int
+	// Company é o nome da empresa do ticket (Dim_Companies.Name)
+	Company *string
+	// Department é o departamento do agente atribuído ao ticket (Dim_Agents.DepartmentName)
+	Department *string
+	Channel    *string
+	Priority   *string
+	// Timezone é o fuso IANA (ex.: America/Sao_Paulo) em que datas, meses e semanas são contados;
+	// vazio conta em UTC, o fuso em que o DW guarda as datas
+	Timezone string
+
+	// CompanyScoped restringe as métricas às empresas (CompanyId_BK) do usuário; sem empresas não retorna nada.
+	// Vem do escopo do token, nunca da query.
+	CompanyScoped bool
+	Companies     []string
+}
+
+// AgentWorkload representa a carga de trabalho de um agente ativo
+type AgentWorkload struct {
+	AgentId             string   `json:"agentId" example:"17"`
+	Name                string   `json:"name" example:"Maria Souza"`
+	Team                string   `json:"team" example:"Suporte N1"`
+	OpenTickets         int64    `json:"openTickets" example:"12"`
+	AssignedTickets     int64    `json:"assignedTickets" example:"85"`
+	AcknowledgedTickets int64    `json:"acknowledgedTickets" example:"80"`
+	MTTAHours           *float64 `json:"mttaHours" example:"1.8"`
+	TeamAverageOpen     float64  `json:"teamAverageOpenTickets" example:"9.5"`
+	LoadRatio           float64  `json:"loadRatio" example:"1.26"`
+}
+
+// DailyLogins representa as autenticações de um dia (UTC)
+type DailyLogins struct {
+	Date       string `json:"date" example:"2025-10-16"`
+	Successful int64  `json:"successful" example:"120"`
+	Failed     int64  `json:"failed" example:"8"`
+	Users      int64  `json:"users" example:"45"`
+}
+
+// ActiveUser representa as autenticações de um usuário no período
+type ActiveUser struct {
+	UserId       int        `json:"userId" example:"42"`
+	Name         *string    `json:"name,omitempty" example:"Maria Silva"`
+	Email        *string    `json:"email,omitempty" example:"maria.silva@empresa.com"`
+	UserType     *string    `json:"userType,omitempty" example:"AGENT"`
+	Logins       int64      `json:"logins" example:"37"`
+	FailedLogins int64      `json:"failedLogins" example:"2"`
+	LastLoginAt  *time.Time `json:"lastLoginAt,omitempty" example:"2025-10-16T10:30:00Z"`
+}
+
+// UserActivityResponse representa a atividade de autenticação do período: os totais, os logins por dia
+// (incluindo os dias sem logins) e a página pedida dos usuários mais ativos
+type UserActivityResponse struct {
+	StartDate          string        `json:"startDate" example:"2025-09-17"`
+	EndDate            string        `json:"endDate" example:"2025-10-16"`
+	Logins             int64         `json:"logins" example:"3600"`
+	FailedLogins       int64         `json:"failedLogins" example:"240"`
+	FailedLoginPercent float64       `json:"failedLoginPercent" example:"6.25"`
+	ActiveUsers        int64         `json:"activeUsers" example:"45"`
+	LoginsPerDay       []DailyLogins `json:"loginsPerDay"`
+	MostActiveUsers    []ActiveUser  `json:"mostActiveUsers"`
+}
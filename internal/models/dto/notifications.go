@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// FieldChange representa a alteração de um campo do ticket
+type FieldChange struct {
+	Field string `json:"field" example:"status"`
+	From  string `json:"from" example:"ABERTO"`
+	To    string `json:"to" example:"EM ANDAMENTO"`
+}
+
+// Notification representa uma notificação in-app de um usuário
+type Notification struct {
+	Id        string        `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type      string        `json:"type" example:"ticket_changed"`
+	TicketId  string        `json:"ticketId" example:"TKT-000123"`
+	Title     string        `json:"title,omitempty" example:"Falha no acesso à internet"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"createdAt" example:"2025-10-16T10:30:00Z"`
+}
+
+// TicketWatchResponse representa o estado de observação de um ticket pelo usuário
+type TicketWatchResponse struct {
+	TicketId string `json:"ticketId" example:"TKT-000123"`
+	Watching bool   `json:"watching" example:"true"`
+}
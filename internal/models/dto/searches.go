@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SavedSearchRequest representa a criação ou a atualização de uma busca salva
+type SavedSearchRequest struct {
+	Name   string          `json:"name" binding:"required,min=1,max=100" example:"Críticos sem resposta"`
+	Query  string          `json:"query" binding:"max=1000" example:"priority:Critical AND status:Open"`
+	Layout json.RawMessage `json:"layout,omitempty" swaggertype:"object"`
+}
+
+// SavedSearchResponse representa uma busca salva
+type SavedSearchResponse struct {
+	Id        int             `json:"id" example:"7"`
+	Name      string          `json:"name" example:"Críticos sem resposta"`
+	Query     string          `json:"query" example:"priority:Critical AND status:Open"`
+	Layout    json.RawMessage `json:"layout,omitempty" swaggertype:"object"`
+	CreatedAt time.Time       `json:"createdAt" example:"2025-06-01T12:00:00Z"`
+	UpdatedAt *time.Time      `json:"updatedAt,omitempty" example:"2025-06-02T08:30:00Z"`
+}
+
+// GlobalSearchParams representa os parâmetros da busca global
+type GlobalSearchParams struct {
+	Query string `form:"q" binding:"required,max=200"`
+	// Limit é quantos resultados de cada tipo são retornados
+	Limit int `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+// GlobalSearchResponse representa a busca global: os primeiros resultados de cada tipo e, em counts, o total
+// encontrado de cada tipo pesquisado. Users só é pesquisado para administradores; um tipo cuja fonte falhou
+// fica fora de counts e é listado em unavailable.
+type GlobalSearchResponse struct {
+	Query       string                   `json:"query" example:"boleto"`
+	Counts      map[string]int64         `json:"counts"`
+	Tickets     []map[string]interface{} `json:"tickets"`
+	Users       []UserResponse           `json:"users,omitempty"`
+	Unavailable []string                 `json:"unavailable,omitempty" example:"users"`
+}
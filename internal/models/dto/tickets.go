@@ -1,73 +1,202 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Ticket struct {
 	AssignedAgent AssignedAgent `json:"assigned_agent,omitempty"`
 	Attachments   []interface{} `json:"attachments,omitempty"`
 	AuditLogs     []interface{} `json:"audit_logs,omitempty"`
 	Category      Category      `json:"category,omitempty"`
-	Channel       string        `json:"channel,omitempty"`
+	Channel       string        `json:"channel,omitempty" binding:"required" example:"Email"`
 	Company       Company       `json:"company,omitempty"`
 	CreatedByUser CreatedByUser `json:"created_by_user,omitempty"`
-	CurrentStatus int64         `json:"current_status,omitempty"`
+	CurrentStatus int64         `json:"current_status,omitempty" example:"1"`
 	Dates         Dates         `json:"dates,omitempty"`
-	Description   string        `json:"description,omitempty"`
-	Device        string        `json:"device,omitempty"`
-	Priority      string        `json:"priority,omitempty"`
+	Description   string        `json:"description,omitempty" example:"O boleto da fatura de outubro não foi gerado"`
+	Device        string        `json:"device,omitempty" example:"desktop"`
+	Priority      string        `json:"priority,omitempty" binding:"required" example:"Alta"`
 	Product       Product       `json:"product,omitempty"`
-	SearchText    string        `json:"search_text,omitempty"`
+	SearchText    string        `json:"search_text,omitempty" example:"boleto fatura outubro"`
 	SLAMetrics    SLAMetrics    `json:"sla_metrics,omitempty"`
-	SLAPlan       int64         `json:"sla_plan,omitempty"`
+	SLAPlan       int64         `json:"sla_plan,omitempty" example:"2"`
 	StatusHistory []interface{} `json:"status_history,omitempty"`
 	Subcategory   Category      `json:"subcategory,omitempty"`
 	Tags          []interface{} `json:"tags,omitempty"`
-	TicketID      string        `json:"ticket_id,omitempty"`
-	Title         string        `json:"title,omitempty"`
+	TicketID      string        `json:"ticket_id,omitempty" binding:"omitempty,max=64" example:"TKT-2025-000123"`
+	Title         string        `json:"title,omitempty" binding:"required,max=500" example:"Boleto não gerado"`
 }
 
 type AssignedAgent struct {
-	Department int64  `json:"department,omitempty"`
-	Email      string `json:"email,omitempty"`
-	FullName   string `json:"full_name,omitempty"`
-	ID         int64  `json:"id,omitempty"`
+	Department int64  `json:"department,omitempty" example:"3"`
+	Email      string `json:"email,omitempty" example:"maria.souza@empresa.com.br"`
+	FullName   string `json:"full_name,omitempty" example:"Maria Souza"`
+	ID         int64  `json:"id,omitempty" example:"17"`
 }
 
 type Category struct {
-	ID   int64  `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
+	ID   int64  `json:"id,omitempty" example:"4"`
+	Name string `json:"name,omitempty" example:"Financeiro"`
 }
 
 type Company struct {
-	Cnpj    string `json:"cnpj,omitempty"`
-	ID      int64  `json:"id,omitempty"`
-	Name    string `json:"name,omitempty"`
-	Segment string `json:"segment,omitempty"`
+	Cnpj    string `json:"cnpj,omitempty" example:"12.345.678/0001-90"`
+	ID      int64  `json:"id,omitempty" example:"10"`
+	Name    string `json:"name,omitempty" example:"Acme Ltda"`
+	Segment string `json:"segment,omitempty" example:"Varejo"`
 }
 
 type CreatedByUser struct {
-	Cpf      string `json:"cpf,omitempty"`
-	Email    string `json:"email,omitempty"`
-	FullName string `json:"full_name,omitempty"`
-	ID       int64  `json:"id,omitempty"`
-	IsVip    bool   `json:"is_vip,omitempty"`
-	Phone    string `json:"phone,omitempty"`
+	Cpf      string `json:"cpf,omitempty" example:"123.456.789-00"`
+	Email    string `json:"email,omitempty" example:"joao.lima@acme.com.br"`
+	FullName string `json:"full_name,omitempty" example:"João Lima"`
+	ID       int64  `json:"id,omitempty" example:"5021"`
+	IsVip    bool   `json:"is_vip,omitempty" example:"false"`
+	Phone    string `json:"phone,omitempty" example:"+55 11 91234-5678"`
 }
 
 type Dates struct {
-	ClosedAt        interface{} `json:"closed_at,omitempty"`
-	CreatedAt       interface{} `json:"created_at,omitempty"`
-	FirstResponseAt interface{} `json:"first_response_at,omitempty"`
+	ClosedAt        interface{} `json:"closed_at,omitempty" swaggertype:"string" example:"2025-10-17 09:12:00"`
+	CreatedAt       interface{} `json:"created_at,omitempty" binding:"required" swaggertype:"string" example:"2025-10-16 10:30:00"`
+	FirstResponseAt interface{} `json:"first_response_at,omitempty" swaggertype:"string" example:"2025-10-16 11:05:00"`
 }
 
 type Product struct {
-	Code        int64  `json:"code,omitempty"`
-	Description string `json:"description,omitempty"`
-	ID          int64  `json:"id,omitempty"`
-	Name        string `json:"name,omitempty"`
+	Code        int64  `json:"code,omitempty" example:"1001"`
+	Description string `json:"description,omitempty" example:"Emissão de boletos e faturas"`
+	ID          int64  `json:"id,omitempty" example:"7"`
+	Name        string `json:"name,omitempty" example:"Faturamento"`
 }
 
 type SLAMetrics struct {
-	FirstResponseSLABreached bool        `json:"first_response_sla_breached,omitempty"`
-	FirstResponseTimeMinutes interface{} `json:"first_response_time_minutes,omitempty"`
-	ResolutionSLABreached    bool        `json:"resolution_sla_breached,omitempty"`
-	ResolutionTimeMinutes    interface{} `json:"resolution_time_minutes,omitempty"`
+	FirstResponseSLABreached bool        `json:"first_response_sla_breached,omitempty" example:"false"`
+	FirstResponseTimeMinutes interface{} `json:"first_response_time_minutes,omitempty" swaggertype:"number" example:"35"`
+	ResolutionSLABreached    bool        `json:"resolution_sla_breached,omitempty" example:"true"`
+	ResolutionTimeMinutes    interface{} `json:"resolution_time_minutes,omitempty" swaggertype:"number" example:"1362"`
+}
+
+// TicketDocument é o ticket gravado no índice e a versão do documento, usada no If-Match das atualizações
+type TicketDocument struct {
+	Ticket  Ticket `json:"ticket"`
+	Version string `json:"version" example:"42.1"`
+}
+
+// BulkTicketResult é o resultado de um ticket da importação em lote
+type BulkTicketResult struct {
+	Index    int    `json:"index" example:"3"`
+	TicketId string `json:"ticketId,omitempty" example:"TKT-000123"`
+	Status   int    `json:"status" example:"400"`
+	Result   string `json:"result,omitempty" example:"created"`
+	Error    string `json:"error,omitempty" example:"Key: 'Ticket.Title' Error:Field validation for 'Title' failed on the 'required' tag"`
+}
+
+// BulkTicketsResponse resume a importação em lote; Errors lista apenas os tickets que falharam
+type BulkTicketsResponse struct {
+	Total   int                `json:"total" example:"1000"`
+	Created int                `json:"created" example:"990"`
+	Updated int                `json:"updated" example:"8"`
+	Failed  int                `json:"failed" example:"2"`
+	Took    string             `json:"took" example:"3.2s"`
+	Errors  []BulkTicketResult `json:"errors"`
+}
+
+// TicketBatchResponse traz os tickets buscados em lote, na ordem pedida, e os IDs não encontrados
+type TicketBatchResponse struct {
+	Tickets  []map[string]interface{} `json:"tickets"`
+	NotFound []string                 `json:"notFound" example:"TKT-404"`
+}
+
+// TicketDetail junta o documento do ticket no Elasticsearch às métricas calculadas e ao contexto do DW
+type TicketDetail struct {
+	Ticket        map[string]interface{} `json:"ticket"`
+	Version       string                 `json:"version" example:"42.1"`
+	Metrics       TicketDetailMetrics    `json:"metrics"`
+	StatusHistory TicketStatusSummary    `json:"statusHistory"`
+	// Warehouse é nulo quando o DW não pôde ser consultado
+	Warehouse *TicketWarehouseContext `json:"warehouse"`
+}
+
+// TicketDetailMetrics são os tempos e as violações de SLA do ticket
+type TicketDetailMetrics struct {
+	IsClosed                 bool     `json:"isClosed" example:"true"`
+	FirstResponseTimeMinutes *float64 `json:"firstResponseTimeMinutes,omitempty" example:"35"`
+	ResolutionTimeMinutes    *float64 `json:"resolutionTimeMinutes,omitempty" example:"1440"`
+	FirstResponseSLABreached bool     `json:"firstResponseSlaBreached" example:"false"`
+	ResolutionSLABreached    bool     `json:"resolutionSlaBreached" example:"true"`
+}
+
+// TicketStatusSummary resume o histórico de status do ticket
+type TicketStatusSummary struct {
+	Changes      int              `json:"changes" example:"4"`
+	ByStatus     map[string]int64 `json:"byStatus"`
+	LastChangeAt *string          `json:"lastChangeAt,omitempty" example:"2025-06-01 14:30:00"`
+}
+
+// TicketWarehouseContext compara o ticket com os fatos do DW da mesma empresa
+type TicketWarehouseContext struct {
+	CompanyTotalTickets                int64    `json:"companyTotalTickets" example:"1520"`
+	PriorityAverageResolutionHours     *float64 `json:"priorityAverageResolutionHours,omitempty" example:"20.5"`
+	ResolutionVsPriorityAveragePercent *float64 `json:"resolutionVsPriorityAveragePercent,omitempty" example:"117.1"`
+}
+
+// TicketEventResponse representa o payload bruto de uma gravação do ticket, arquivado no MongoDB
+type TicketEventResponse struct {
+	Id        string          `json:"id" example:"665f1c2e9b1e8a3d4c5b6a79"`
+	TicketId  string          `json:"ticketId" example:"TKT-000123"`
+	Type      string          `json:"type" example:"UPDATED" enums:"CREATED,UPDATED,IMPORTED"`
+	ActorId   *int            `json:"actorId,omitempty" example:"1"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt time.Time       `json:"createdAt" example:"2025-10-16T10:30:00Z"`
+}
+
+// TicketAttachment são os metadados de um anexo do ticket. O caminho no storage não é exposto.
+type TicketAttachment struct {
+	Id         string  `json:"id" example:"ATT-0001"`
+	Filename   string  `json:"filename" example:"erro-boleto.png"`
+	MimeType   string  `json:"mimeType" example:"image/png"`
+	Category   string  `json:"category" example:"image" enums:"image,video,audio,document,archive,other"`
+	SizeBytes  *int64  `json:"sizeBytes,omitempty" example:"245760"`
+	UploadedAt *string `json:"uploadedAt,omitempty" example:"2025-06-01 08:05:00"`
+}
+
+// TicketAttachmentsResponse lista os anexos do ticket
+type TicketAttachmentsResponse struct {
+	TicketId       string             `json:"ticketId" example:"TKT-000123"`
+	Count          int                `json:"count" example:"2"`
+	TotalSizeBytes int64              `json:"totalSizeBytes" example:"512000"`
+	Attachments    []TicketAttachment `json:"attachments"`
+}
+
+// TicketAuditEntry é uma entrada de audit_logs do ticket
+type TicketAuditEntry struct {
+	EntityType  string      `json:"entityType,omitempty" example:"ticket"`
+	EntityId    string      `json:"entityId,omitempty" example:"TKT-000123"`
+	Operation   string      `json:"operation,omitempty" example:"UPDATE"`
+	PerformedBy string      `json:"performedBy,omitempty" example:"12"`
+	PerformedAt *string     `json:"performedAt,omitempty" example:"2025-06-01 14:30:00"`
+	Field       string      `json:"field,omitempty" example:"priority"`
+	OldValue    string      `json:"oldValue,omitempty" example:"ALTA"`
+	NewValue    string      `json:"newValue,omitempty" example:"BAIXA"`
+	Change      string      `json:"change,omitempty" example:"downgrade" enums:"upgrade,downgrade"`
+	Description string      `json:"description,omitempty" example:"priority alterado de ALTA para BAIXA"`
+	Details     interface{} `json:"details,omitempty" swaggertype:"object"`
+}
+
+// TicketAuditSearchResult é um ticket encontrado na busca de auditoria, com as entradas que atendem ao filtro
+type TicketAuditSearchResult struct {
+	TicketId      string             `json:"ticketId" example:"TKT-000123"`
+	Title         string             `json:"title" example:"Boleto não chegou"`
+	Priority      string             `json:"priority,omitempty" example:"BAIXA"`
+	CurrentStatus string             `json:"currentStatus,omitempty" example:"2"`
+	Entries       []TicketAuditEntry `json:"entries"`
+}
+
+// TicketSearchResult é uma página da busca de tickets. O repositório não conhece a requisição, então
+// quem responde ao cliente monta o envelope com NewPaginatedResponse.
+type TicketSearchResult struct {
+	Tickets    []map[string]interface{}
+	Pagination Pagination
+	Facets     Facets
 }
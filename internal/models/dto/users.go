@@ -1,6 +1,9 @@
 package dto
 
-import "time"
+import (
+	"orderstreamrest/internal/models/entities"
+	"time"
+)
 
 // ============================================
 // USER REQUEST DTOs
@@ -11,17 +14,26 @@ type CreateUserRequest struct {
 	Name        string  `json:"name" binding:"required,min=3,max=200" example:"João Silva"`
 	Email       string  `json:"email" binding:"required,email,max=255" example:"joao.silva@example.com"`
 	Password    *string `json:"password,omitempty" binding:"omitempty,min=8,max=100" example:"SenhaSegura@123"`
-	UserType    string  `json:"userType" binding:"required,oneof=ADMIN MANAGER AGENT VIEWER" example:"AGENT" enums:"ADMIN,MANAGER,AGENT,VIEWER"`
+	UserType    string  `json:"userType" binding:"required,enum=usertype" example:"AGENT" enums:"ADMIN,MANAGER,AGENT,VIEWER"`
 	MicrosoftId *string `json:"microsoftId,omitempty" binding:"omitempty,max=255" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890"`
+	CompanyId   *int64  `json:"companyId,omitempty" binding:"omitempty,min=1" example:"12"`
 }
 
 // UpdateUserRequest representa a requisição de atualização de usuário
 type UpdateUserRequest struct {
-	Name     *string `json:"name,omitempty" binding:"omitempty,min=3,max=200" example:"João Silva Atualizado"`
-	Email    *string `json:"email,omitempty" binding:"omitempty,email,max=255" example:"joao.novo@example.com"`
-	Password *string `json:"password,omitempty" binding:"omitempty,min=8,max=100" example:"NovaSenha@456"`
-	UserType *string `json:"userType,omitempty" binding:"omitempty,oneof=ADMIN MANAGER AGENT VIEWER" example:"MANAGER" enums:"ADMIN,MANAGER,AGENT,VIEWER"`
-	IsActive *bool   `json:"isActive,omitempty" example:"true"`
+	Name      *string `json:"name,omitempty" binding:"omitempty,min=3,max=200" example:"João Silva Atualizado"`
+	Email     *string `json:"email,omitempty" binding:"omitempty,email,max=255" example:"joao.novo@example.com"`
+	Password  *string `json:"password,omitempty" binding:"omitempty,min=8,max=100" example:"NovaSenha@456"`
+	UserType  *string `json:"userType,omitempty" binding:"omitempty,enum=usertype" example:"MANAGER" enums:"ADMIN,MANAGER,AGENT,VIEWER"`
+	IsActive  *bool   `json:"isActive,omitempty" example:"true"`
+	CompanyId *int64  `json:"companyId,omitempty" binding:"omitempty,min=1" example:"12"`
+}
+
+// UpdateProfileRequest representa a alteração do próprio perfil. Trocar o email exige a senha atual.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name,omitempty" binding:"omitempty,min=3,max=200" example:"João Silva"`
+	Email           *string `json:"email,omitempty" binding:"omitempty,email,max=255" example:"joao.novo@example.com"`
+	CurrentPassword *string `json:"currentPassword,omitempty" example:"SenhaAtual@123"`
 }
 
 // ChangePasswordRequest representa a requisição de mudança de senha
@@ -40,6 +52,18 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required" example:"SenhaSegura@123"`
 }
 
+// TwoFactorVerifyRequest confirma a configuração do 2FA com um código do aplicativo
+type TwoFactorVerifyRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+// TwoFactorLoginRequest conclui o login com o código do aplicativo ou um código de recuperação
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required" example:"3f2b8c1e-4d5a-4b6c-9e7f-1a2b3c4d5e6f"`
+	Code           string `json:"code,omitempty" binding:"required_without=RecoveryCode,omitempty,len=6,numeric" example:"123456"`
+	RecoveryCode   string `json:"recoveryCode,omitempty" binding:"omitempty,max=20" example:"k7pq-2xvm"`
+}
+
 // MicrosoftAuthRequest representa a requisição de autenticação Microsoft
 type MicrosoftAuthRequest struct {
 	AccessToken string `json:"accessToken" binding:"required" example:"EwAoA8l6BAAURSN/FjAGe3BVB..."`
@@ -56,18 +80,27 @@ type UserResponse struct {
 	Email       string     `json:"email" example:"joao.silva@example.com"`
 	UserType    string     `json:"userType" example:"AGENT" enums:"ADMIN,MANAGER,AGENT,VIEWER"`
 	MicrosoftId *string    `json:"microsoftId,omitempty" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890"`
+	CompanyId   *int64     `json:"companyId,omitempty" example:"12"`
 	IsActive    bool       `json:"isActive" example:"true"`
 	CreatedAt   time.Time  `json:"createdAt" example:"2025-10-16T10:30:00Z"`
 	UpdatedAt   *time.Time `json:"updatedAt,omitempty" example:"2025-10-16T15:45:00Z"`
 	LastLoginAt *time.Time `json:"lastLoginAt,omitempty" example:"2025-10-16T14:20:00Z"`
 }
 
-// UsersListResponse representa a lista de usuários
-type UsersListResponse struct {
-	Users      []UserResponse `json:"users"`
-	TotalCount int            `json:"totalCount" example:"50"`
-	Page       int            `json:"page" example:"1"`
-	PageSize   int            `json:"pageSize" example:"10"`
+// NewUserResponse converte o usuário na resposta da API, sem o hash da senha
+func NewUserResponse(user entities.User) UserResponse {
+	return UserResponse{
+		Id:          user.Id,
+		Name:        user.Name,
+		Email:       user.Email,
+		UserType:    user.UserType,
+		MicrosoftId: user.MicrosoftId,
+		CompanyId:   user.CompanyId,
+		IsActive:    user.IsActive,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		LastLoginAt: user.LastLoginAt,
+	}
 }
 
 // UserCreatedResponse representa a resposta de criação de usuário
@@ -89,6 +122,25 @@ type LoginResponse struct {
 	User      UserResponse `json:"user"`
 }
 
+// TwoFactorChallengeResponse é a resposta do login quando a conta exige o segundo fator.
+// O challengeToken é enviado com o código em POST /auth/2fa/login.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired" example:"true"`
+	ChallengeToken    string `json:"challengeToken" example:"3f2b8c1e-4d5a-4b6c-9e7f-1a2b3c4d5e6f"`
+	ExpiresIn         int    `json:"expires_in" example:"300"`
+}
+
+// TwoFactorSetupResponse traz o segredo TOTP a cadastrar no aplicativo autenticador
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OtpauthURL string `json:"otpauthUrl" example:"otpauth://totp/VisionData:joao.silva@example.com?secret=JBSWY3DPEHPK3PXP&issuer=VisionData"`
+}
+
+// TwoFactorRecoveryCodesResponse traz os códigos de recuperação, exibidos uma única vez
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes" example:"k7pq-2xvm,9hd3-wq8e"`
+}
+
 // UserAuthLogResponse representa um log de autenticação
 type UserAuthLogResponse struct {
 	Id           int       `json:"id" example:"1"`
@@ -121,3 +173,49 @@ type ValidationErrorResponse struct {
 	Message string            `json:"message" example:"Request validation failed"`
 	Errors  []ValidationError `json:"errors"`
 }
+
+// CascadeItemReport representa o efeito da remoção de um usuário sobre uma entidade vinculada
+type CascadeItemReport struct {
+	Entity   string `json:"entity" example:"auth_logs"`
+	Policy   string `json:"policy" example:"ANONYMIZE" enums:"DELETE,ANONYMIZE"`
+	Affected int64  `json:"affected" example:"12"`
+	// Error é a falha ao tratar registros fora do SQL Server depois que o usuário já foi removido
+	Error string `json:"error,omitempty" example:"failed to delete notifications: i/o timeout"`
+}
+
+// CascadeReportResponse representa o relatório de cascata da remoção de um usuário
+type CascadeReportResponse struct {
+	UserId int                 `json:"userId" example:"1"`
+	DryRun bool                `json:"dryRun" example:"true"`
+	Items  []CascadeItemReport `json:"items"`
+}
+
+// BulkUserResult representa o resultado de uma linha da importação de usuários
+type BulkUserResult struct {
+	Row     int               `json:"row" example:"1"`
+	Email   string            `json:"email" example:"joao.silva@example.com"`
+	Success bool              `json:"success" example:"true"`
+	Id      *int              `json:"id,omitempty" example:"42"`
+	Error   string            `json:"error,omitempty" example:"Email already exists"`
+	Fields  []ValidationError `json:"fields,omitempty"`
+}
+
+// BulkImportUsersResponse representa o resumo da importação de usuários
+type BulkImportUsersResponse struct {
+	Total   int              `json:"total" example:"3"`
+	Created int              `json:"created" example:"2"`
+	Failed  int              `json:"failed" example:"1"`
+	Results []BulkUserResult `json:"results"`
+}
+
+// SessionResponse representa um login ativo do usuário
+type SessionResponse struct {
+	Id         string    `json:"id" example:"3f2b8c1e-4d5a-4b6c-9e7f-1a2b3c4d5e6f"`
+	UserId     int       `json:"userId" example:"42"`
+	UserAgent  string    `json:"userAgent" example:"Mozilla/5.0 (Windows NT 10.0; Win64; x64)"`
+	IPAddress  string    `json:"ipAddress" example:"203.0.113.10"`
+	IssuedAt   time.Time `json:"issuedAt" example:"2025-10-18T12:00:00Z"`
+	LastSeenAt time.Time `json:"lastSeenAt" example:"2025-10-18T12:30:00Z"`
+	ExpiresAt  time.Time `json:"expiresAt" example:"2025-10-18T13:00:00Z"`
+	Current    bool      `json:"current" example:"true"`
+}
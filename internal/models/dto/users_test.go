@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"orderstreamrest/internal/utils"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUserTypeSwaggerEnums garante que os enums do Swagger acompanham o registro de tipos de usuário
+func TestUserTypeSwaggerEnums(t *testing.T) {
+	expected := strings.Join(utils.UserTypes.Values(), ",")
+
+	for _, value := range []interface{}{CreateUserRequest{}, UpdateUserRequest{}, UserResponse{}} {
+		field, ok := reflect.TypeOf(value).FieldByName("UserType")
+		if assert.True(t, ok) {
+			assert.Equal(t, expected, field.Tag.Get("enums"), reflect.TypeOf(value).Name())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+// Severidades dos alertas de volume de tickets
+const (
+	AlertSeverityWarning  = "WARNING"
+	AlertSeverityCritical = "CRITICAL"
+)
+
+// TicketVolumeAlert registra um dia em que a quantidade de tickets abertos fugiu da linha de base.
+// Há no máximo um alerta por dia; novas análises do mesmo dia atualizam os números e a severidade.
+type TicketVolumeAlert struct {
+	Id             int        `json:"id" gorm:"column:Id;primaryKey;autoIncrement"`
+	Day            time.Time  `json:"day" gorm:"column:Day;type:date;not null;unique"`
+	Tickets        int64      `json:"tickets" gorm:"column:Tickets;type:bigint;not null"`
+	BaselineMean   float64    `json:"baselineMean" gorm:"column:BaselineMean;type:float;not null"`
+	BaselineStdDev float64    `json:"baselineStdDev" gorm:"column:BaselineStdDev;type:float;not null"`
+	BaselineDays   int        `json:"baselineDays" gorm:"column:BaselineDays;type:int;not null"`
+	ZScore         float64    `json:"zScore" gorm:"column:ZScore;type:float;not null"`
+	Severity       string     `json:"severity" gorm:"column:Severity;type:nvarchar(20);not null"`
+	CreatedAt      time.Time  `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty" gorm:"column:UpdatedAt;type:datetime2"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (TicketVolumeAlert) TableName() string {
+	return "dbo.TicketVolumeAlerts"
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// AuditLog registra uma ação administrativa com o estado do registro antes e depois dela
+type AuditLog struct {
+	Id         int       `json:"id" gorm:"column:Id;primaryKey;autoIncrement"`
+	ActorId    *int      `json:"actorId,omitempty" gorm:"column:ActorId;type:int"`
+	Action     string    `json:"action" gorm:"column:Action;type:nvarchar(50);not null"`
+	EntityType string    `json:"entityType" gorm:"column:EntityType;type:nvarchar(50);not null"`
+	EntityId   string    `json:"entityId" gorm:"column:EntityId;type:nvarchar(100);not null"`
+	Before     *string   `json:"before,omitempty" gorm:"column:Before;type:nvarchar(max)"`
+	After      *string   `json:"after,omitempty" gorm:"column:After;type:nvarchar(max)"`
+	IPAddress  *string   `json:"ipAddress,omitempty" gorm:"column:IPAddress;type:nvarchar(50)"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETUTCDATE()"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (AuditLog) TableName() string {
+	return "dbo.AuditLogs"
+}
@@ -0,0 +1,67 @@
+package entities
+
+import "time"
+
+// ScheduledJob é a configuração persistida de uma rotina executada em segundo plano
+type ScheduledJob struct {
+	Name            string     `json:"name" gorm:"column:Name;type:nvarchar(100);primaryKey"`
+	Enabled         bool       `json:"enabled" gorm:"column:Enabled;type:bit;not null;default:1"`
+	IntervalMinutes int        `json:"intervalMinutes" gorm:"column:IntervalMinutes;type:int;not null"`
+	RetentionDays   int        `json:"retentionDays" gorm:"column:RetentionDays;type:int;not null;default:0"`
+	Policy          string     `json:"policy,omitempty" gorm:"column:Policy;type:nvarchar(50)"`
+	LastRunAt       *time.Time `json:"lastRunAt,omitempty" gorm:"column:LastRunAt;type:datetime2"`
+	UpdatedAt       *time.Time `json:"updatedAt,omitempty" gorm:"column:UpdatedAt;type:datetime2"`
+	UpdatedBy       *int       `json:"updatedBy,omitempty" gorm:"column:UpdatedBy;type:int"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (ScheduledJob) TableName() string {
+	return "dbo.ScheduledJobs"
+}
+
+// JobRun registra uma execução de rotina em segundo plano
+type JobRun struct {
+	Id           int        `json:"id" gorm:"column:Id;primaryKey;autoIncrement"`
+	JobName      string     `json:"jobName" gorm:"column:JobName;type:nvarchar(100);not null"`
+	Source       string     `json:"source" gorm:"column:Source;type:nvarchar(20);not null"`
+	TriggeredBy  *int       `json:"triggeredBy,omitempty" gorm:"column:TriggeredBy;type:int"`
+	StartedAt    time.Time  `json:"startedAt" gorm:"column:StartedAt;type:datetime2;not null"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty" gorm:"column:FinishedAt;type:datetime2"`
+	Success      bool       `json:"success" gorm:"column:Success;type:bit;not null;default:0"`
+	Affected     int64      `json:"affected" gorm:"column:Affected;type:bigint;not null;default:0"`
+	ErrorMessage *string    `json:"errorMessage,omitempty" gorm:"column:ErrorMessage;type:nvarchar(500)"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (JobRun) TableName() string {
+	return "dbo.JobRuns"
+}
+
+// Situações de uma tarefa da fila de processamento assíncrono
+const (
+	QueuedJobQueued    = "QUEUED"
+	QueuedJobRunning   = "RUNNING"
+	QueuedJobSucceeded = "SUCCEEDED"
+	QueuedJobFailed    = "FAILED"
+)
+
+// QueuedJob registra uma tarefa enviada à fila de processamento assíncrono e o seu resultado
+type QueuedJob struct {
+	Id           string     `json:"id" gorm:"column:Id;type:nvarchar(36);primaryKey"`
+	Type         string     `json:"type" gorm:"column:Type;type:nvarchar(100);not null"`
+	Payload      string     `json:"payload,omitempty" gorm:"column:Payload;type:nvarchar(max)"`
+	Status       string     `json:"status" gorm:"column:Status;type:nvarchar(20);not null"`
+	Attempts     int        `json:"attempts" gorm:"column:Attempts;type:int;not null;default:0"`
+	MaxAttempts  int        `json:"maxAttempts" gorm:"column:MaxAttempts;type:int;not null"`
+	Result       *string    `json:"result,omitempty" gorm:"column:Result;type:nvarchar(max)"`
+	ErrorMessage *string    `json:"errorMessage,omitempty" gorm:"column:ErrorMessage;type:nvarchar(500)"`
+	RequestedBy  *int       `json:"requestedBy,omitempty" gorm:"column:RequestedBy;type:int"`
+	CreatedAt    time.Time  `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null"`
+	StartedAt    *time.Time `json:"startedAt,omitempty" gorm:"column:StartedAt;type:datetime2"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty" gorm:"column:FinishedAt;type:datetime2"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (QueuedJob) TableName() string {
+	return "dbo.QueuedJobs"
+}
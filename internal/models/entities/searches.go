@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// SavedSearch é uma busca de tickets salva pelo usuário, com o layout do painel que a exibe
+type SavedSearch struct {
+	Id        int        `json:"id" gorm:"column:Id;primaryKey;autoIncrement"`
+	UserId    int        `json:"userId" gorm:"column:UserId;type:int;not null"`
+	Name      string     `json:"name" gorm:"column:Name;type:nvarchar(100);not null"`
+	Query     string     `json:"query" gorm:"column:Query;type:nvarchar(1000);not null"`
+	Layout    *string    `json:"layout,omitempty" gorm:"column:Layout;type:nvarchar(max)"` // JSON livre definido pelo frontend
+	CreatedAt time.Time  `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETUTCDATE()"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" gorm:"column:UpdatedAt;type:datetime2"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (SavedSearch) TableName() string {
+	return "dbo.SavedSearches"
+}
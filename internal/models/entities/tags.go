@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// TagAlias aponta uma grafia de tag (ex.: "boletos", "Boleto ") para o nome canônico em dbo.Dim_Tags.
+// Alias é a chave de comparação da grafia: minúsculas, sem acentos e com espaços simples.
+type TagAlias struct {
+	Id        int       `json:"id" gorm:"column:Id;primaryKey;autoIncrement"`
+	Alias     string    `json:"alias" gorm:"column:Alias;type:nvarchar(60);not null;unique"`
+	TagName   string    `json:"tagName" gorm:"column:TagName;type:nvarchar(60);not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETUTCDATE()"`
+	CreatedBy *int      `json:"createdBy,omitempty" gorm:"column:CreatedBy;type:int"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (TagAlias) TableName() string {
+	return "dbo.TagAliases"
+}
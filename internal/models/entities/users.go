@@ -1,6 +1,11 @@
 package entities
 
-import "time"
+import (
+	"orderstreamrest/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // User representa um usuário do sistema
 type User struct {
@@ -10,8 +15,9 @@ type User struct {
 	PasswordHash *string    `json:"-" gorm:"column:PasswordHash;type:nvarchar(500)"` // Nunca retornar no JSON
 	UserType     string     `json:"userType" gorm:"column:UserType;type:nvarchar(50);not null"`
 	MicrosoftId  *string    `json:"microsoftId,omitempty" gorm:"column:MicrosoftId;type:nvarchar(255);unique"`
+	CompanyId    *int64     `json:"companyId,omitempty" gorm:"column:CompanyId;type:bigint"` // Empresa (CompanyId_BK do DW) vista por gestores
 	IsActive     bool       `json:"isActive" gorm:"column:IsActive;type:bit;not null;default:1"`
-	CreatedAt    time.Time  `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETDATE()"`
+	CreatedAt    time.Time  `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETUTCDATE()"`
 	UpdatedAt    *time.Time `json:"updatedAt,omitempty" gorm:"column:UpdatedAt;type:datetime2"`
 	LastLoginAt  *time.Time `json:"lastLoginAt,omitempty" gorm:"column:LastLoginAt;type:datetime2"`
 	CreatedBy    *int       `json:"createdBy,omitempty" gorm:"column:CreatedBy;type:int"`
@@ -23,6 +29,12 @@ func (User) TableName() string {
 	return "dbo.Users"
 }
 
+// AfterFind converte o tipo de usuário lido do banco para a forma canônica
+func (u *User) AfterFind(tx *gorm.DB) error {
+	u.UserType = utils.UserTypes.Normalize(u.UserType)
+	return nil
+}
+
 // UserAuthLog representa um log de autenticação
 type UserAuthLog struct {
 	Id           int       `json:"id" gorm:"column:Id;primaryKey;autoIncrement"`
@@ -32,10 +44,38 @@ type UserAuthLog struct {
 	UserAgent    *string   `json:"userAgent,omitempty" gorm:"column:UserAgent;type:nvarchar(500)"`
 	Success      bool      `json:"success" gorm:"column:Success;type:bit;not null"`
 	ErrorMessage *string   `json:"errorMessage,omitempty" gorm:"column:ErrorMessage;type:nvarchar(500)"`
-	CreatedAt    time.Time `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETDATE()"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETUTCDATE()"`
 }
 
 // TableName especifica o nome da tabela no banco
 func (UserAuthLog) TableName() string {
 	return "dbo.UserAuthLogs"
 }
+
+// PasswordHistory guarda os hashes das senhas anteriores, para impedir a reutilização
+type PasswordHistory struct {
+	Id           int       `json:"id" gorm:"column:Id;primaryKey;autoIncrement"`
+	UserId       int       `json:"userId" gorm:"column:UserId;type:int;not null"`
+	PasswordHash string    `json:"-" gorm:"column:PasswordHash;type:nvarchar(500);not null"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETUTCDATE()"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (PasswordHistory) TableName() string {
+	return "dbo.PasswordHistory"
+}
+
+// UserTwoFactor guarda o segredo TOTP do usuário e os hashes dos códigos de recuperação
+type UserTwoFactor struct {
+	UserId        int        `json:"userId" gorm:"column:UserId;primaryKey"`
+	Secret        string     `json:"-" gorm:"column:Secret;type:nvarchar(100);not null"`
+	Enabled       bool       `json:"enabled" gorm:"column:Enabled;type:bit;not null;default:0"`
+	RecoveryCodes *string    `json:"-" gorm:"column:RecoveryCodes;type:nvarchar(max)"` // Array JSON de hashes bcrypt
+	CreatedAt     time.Time  `json:"createdAt" gorm:"column:CreatedAt;type:datetime2;not null;default:GETUTCDATE()"`
+	EnabledAt     *time.Time `json:"enabledAt,omitempty" gorm:"column:EnabledAt;type:datetime2"`
+}
+
+// TableName especifica o nome da tabela no banco
+func (UserTwoFactor) TableName() string {
+	return "dbo.UserTwoFactor"
+}
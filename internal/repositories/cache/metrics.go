@@ -0,0 +1,327 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/redis"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// metricsKeyPrefix prefixa todas as chaves do cache de métricas
+	metricsKeyPrefix = "metrics:cache:"
+
+	defaultMetricsTTL = 5 * time.Minute
+)
+
+// MetricsRepository decora o repositório de métricas do SQL Server com cache no Redis.
+// Cada consulta é guardada por método e argumentos (incluindo os filtros) durante o TTL configurado.
+type MetricsRepository struct {
+	repo  *sqlserver.Internal
+	redis *redis.RedisInternal
+	ttl   time.Duration
+}
+
+// NewMetricsRepository cria o repositório com cache. O TTL vem de METRICS_CACHE_TTL_SECONDS;
+// zero desativa o cache.
+func NewMetricsRepository(repo *sqlserver.Internal, rd *redis.RedisInternal) *MetricsRepository {
+	ttl := defaultMetricsTTL
+	if value := os.Getenv("METRICS_CACHE_TTL_SECONDS"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return &MetricsRepository{
+		repo:  repo,
+		redis: rd,
+		ttl:   ttl,
+	}
+}
+
+// TTL retorna por quanto tempo as consultas ficam em cache
+func (m *MetricsRepository) TTL() time.Duration {
+	return m.ttl
+}
+
+// warmTTLKey marca no contexto as consultas feitas pelo aquecimento do cache, com o TTL das entradas
+type warmTTLKey struct{}
+
+// cacheTTL retorna o TTL das entradas gravadas com o contexto e se a consulta é de aquecimento
+func (m *MetricsRepository) cacheTTL(ctx context.Context) (time.Duration, bool) {
+	if ttl, ok := ctx.Value(warmTTLKey{}).(time.Duration); ok {
+		return max(ttl, m.ttl), true
+	}
+	return m.ttl, false
+}
+
+// cached retorna o resultado guardado para o método e argumentos, ou executa load e guarda o resultado.
+// No aquecimento a consulta sempre vai ao banco e substitui a entrada. Falhas do Redis nunca impedem a
+// consulta ao banco.
+func cached[T any](ctx context.Context, m *MetricsRepository, method string, args []interface{}, load func() (T, error)) (T, error) {
+	if m.ttl <= 0 || m.redis == nil {
+		return load()
+	}
+
+	key := metricsCacheKey(method, args...)
+	ttl, warming := m.cacheTTL(ctx)
+
+	if !warming {
+		if value, err := m.redis.Get(ctx, key).Result(); err == nil {
+			var result T
+			if err := json.Unmarshal([]byte(value), &result); err == nil {
+				return result, nil
+			}
+		}
+	}
+
+	result, err := load()
+	if err != nil {
+		return result, err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("Failed to serialize metrics cache entry %s: %v", key, err)
+		return result, nil
+	}
+	if err := m.redis.Set(ctx, key, data, ttl).Err(); err != nil {
+		log.Printf("Failed to write metrics cache entry %s: %v", key, err)
+	}
+
+	return result, nil
+}
+
+// metricsCacheKey monta a chave do cache a partir do método e do hash dos argumentos
+func metricsCacheKey(method string, args ...interface{}) string {
+	data, err := json.Marshal(args)
+	if err != nil {
+		data = []byte(fmt.Sprint(args...))
+	}
+	sum := sha256.Sum256(data)
+	return metricsKeyPrefix + method + ":" + hex.EncodeToString(sum[:16])
+}
+
+// Invalidate remove todas as entradas do cache de métricas e retorna quantas foram removidas
+func (m *MetricsRepository) Invalidate(ctx context.Context) (int64, error) {
+	var deleted int64
+	var cursor uint64
+	for {
+		keys, next, err := m.redis.Scan(ctx, cursor, metricsKeyPrefix+"*", 500).Result()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to scan metrics cache: %w", err)
+		}
+
+		if len(keys) > 0 {
+			n, err := m.redis.Del(ctx, keys...).Result()
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete metrics cache: %w", err)
+			}
+			deleted += n
+		}
+
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
+
+// Refresh invalida o cache e recarrega as consultas sem filtro usadas pelos dashboards
+func (m *MetricsRepository) Refresh(ctx context.Context) (int64, error) {
+	deleted, err := m.Invalidate(ctx)
+	if err != nil {
+		return deleted, err
+	}
+
+	if _, err := m.Warm(ctx, m.ttl); err != nil {
+		return deleted, fmt.Errorf("failed to refresh metrics cache: %w", err)
+	}
+	return deleted, nil
+}
+
+// Warm recalcula no banco as consultas sem filtro usadas pelos dashboards e as grava no cache por ttl
+// (nunca menos que o TTL configurado), mesmo que ainda estejam em cache. Uma consulta que falha não
+// impede as demais; retorna quantas foram gravadas.
+func (m *MetricsRepository) Warm(ctx context.Context, ttl time.Duration) (int, error) {
+	if m.ttl <= 0 || m.redis == nil {
+		return 0, nil
+	}
+
+	ctx = context.WithValue(ctx, warmTTLKey{}, ttl)
+
+	var filter dto.MetricsFilter
+	warmers := []struct {
+		name string
+		load func() error
+	}{
+		{"GetTotalTickets", func() error { _, err := m.GetTotalTickets(ctx, filter); return err }},
+		{"GetTicketsByCategory", func() error { _, err := m.GetTicketsByCategory(ctx, filter); return err }},
+		{"GetTicketsByPriority", func() error { _, err := m.GetTicketsByPriority(ctx, filter); return err }},
+		{"GetTicketsByChannel", func() error { _, err := m.GetTicketsByChannel(ctx, filter); return err }},
+		{"GetTicketsByTag", func() error { _, err := m.GetTicketsByTag(ctx, filter); return err }},
+		{"GetTicketsByDepartment", func() error { _, err := m.GetTicketsByDepartment(ctx, filter); return err }},
+		{"GetAverageResolutionTime", func() error { _, err := m.GetAverageResolutionTime(ctx, filter); return err }},
+		{"GetTicketsByStatusAndMonth", func() error { _, err := m.GetTicketsByStatusAndMonth(ctx, filter); return err }},
+		{"GetTicketsByMonth", func() error { _, err := m.GetTicketsByMonth(ctx, filter); return err }},
+		{"GetTicketsByPriorityAndMonth", func() error { _, err := m.GetTicketsByPriorityAndMonth(ctx, filter); return err }},
+		{"GetAgentsWorkload", func() error { _, err := m.GetAgentsWorkload(ctx, filter); return err }},
+	}
+
+	warmed := 0
+	var errs []error
+	for _, warmer := range warmers {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := warmer.load(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", warmer.name, err))
+			continue
+		}
+		warmed++
+	}
+
+	return warmed, errors.Join(errs...)
+}
+
+// GetTotalTickets retorna o total de tickets
+func (m *MetricsRepository) GetTotalTickets(ctx context.Context, filter dto.MetricsFilter) (int64, error) {
+	return cached(ctx, m, "GetTotalTickets", []interface{}{filter}, func() (int64, error) {
+		return m.repo.GetTotalTickets(ctx, filter)
+	})
+}
+
+// GetTicketsByCategory retorna o total de tickets agrupados por categoria
+func (m *MetricsRepository) GetTicketsByCategory(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.CategoryTotal, error) {
+	return cached(ctx, m, "GetTicketsByCategory", []interface{}{filter}, func() ([]sqlserver.CategoryTotal, error) {
+		return m.repo.GetTicketsByCategory(ctx, filter)
+	})
+}
+
+// GetTicketsByPriority retorna o total de tickets agrupados por prioridade
+func (m *MetricsRepository) GetTicketsByPriority(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.PriorityTotal, error) {
+	return cached(ctx, m, "GetTicketsByPriority", []interface{}{filter}, func() ([]sqlserver.PriorityTotal, error) {
+		return m.repo.GetTicketsByPriority(ctx, filter)
+	})
+}
+
+// GetTicketsByChannel retorna o total de tickets por canal
+func (m *MetricsRepository) GetTicketsByChannel(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.ChannelTotal, error) {
+	return cached(ctx, m, "GetTicketsByChannel", []interface{}{filter}, func() ([]sqlserver.ChannelTotal, error) {
+		return m.repo.GetTicketsByChannel(ctx, filter)
+	})
+}
+
+// GetTicketsByTag retorna o total de tickets por tag
+func (m *MetricsRepository) GetTicketsByTag(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.TagTotal, error) {
+	return cached(ctx, m, "GetTicketsByTag", []interface{}{filter}, func() ([]sqlserver.TagTotal, error) {
+		return m.repo.GetTicketsByTag(ctx, filter)
+	})
+}
+
+// GetTicketsByDepartment retorna o total de tickets por departamento
+func (m *MetricsRepository) GetTicketsByDepartment(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.DepartmentTotal, error) {
+	return cached(ctx, m, "GetTicketsByDepartment", []interface{}{filter}, func() ([]sqlserver.DepartmentTotal, error) {
+		return m.repo.GetTicketsByDepartment(ctx, filter)
+	})
+}
+
+// GetAverageResolutionTime retorna o tempo médio de resolução de tickets por prioridade
+func (m *MetricsRepository) GetAverageResolutionTime(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.ResolutionTimeByPriority, error) {
+	return cached(ctx, m, "GetAverageResolutionTime", []interface{}{filter}, func() ([]sqlserver.ResolutionTimeByPriority, error) {
+		return m.repo.GetAverageResolutionTime(ctx, filter)
+	})
+}
+
+// GetResolutionTimePercentiles retorna a distribuição do tempo de resolução por valor da dimensão
+func (m *MetricsRepository) GetResolutionTimePercentiles(ctx context.Context, dimension string, filter dto.MetricsFilter) ([]sqlserver.ResolutionTimePercentiles, error) {
+	return cached(ctx, m, "GetResolutionTimePercentiles", []interface{}{dimension, filter}, func() ([]sqlserver.ResolutionTimePercentiles, error) {
+		return m.repo.GetResolutionTimePercentiles(ctx, dimension, filter)
+	})
+}
+
+// GetTicketsByStatusAndMonth retorna o total de tickets por status e mês
+func (m *MetricsRepository) GetTicketsByStatusAndMonth(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.StatusMonthCounts, error) {
+	return cached(ctx, m, "GetTicketsByStatusAndMonth", []interface{}{filter}, func() ([]sqlserver.StatusMonthCounts, error) {
+		return m.repo.GetTicketsByStatusAndMonth(ctx, filter)
+	})
+}
+
+// GetTicketsByMonth retorna o total de tickets por mês e ano
+func (m *MetricsRepository) GetTicketsByMonth(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.MonthTotal, error) {
+	return cached(ctx, m, "GetTicketsByMonth", []interface{}{filter}, func() ([]sqlserver.MonthTotal, error) {
+		return m.repo.GetTicketsByMonth(ctx, filter)
+	})
+}
+
+// GetTicketsByPriorityAndMonth retorna o total de tickets por prioridade e mês
+func (m *MetricsRepository) GetTicketsByPriorityAndMonth(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.PriorityMonthCounts, error) {
+	return cached(ctx, m, "GetTicketsByPriorityAndMonth", []interface{}{filter}, func() ([]sqlserver.PriorityMonthCounts, error) {
+		return m.repo.GetTicketsByPriorityAndMonth(ctx, filter)
+	})
+}
+
+// GetAgentsWorkload retorna os tickets atribuídos e abertos de cada agente ativo
+func (m *MetricsRepository) GetAgentsWorkload(ctx context.Context, filter dto.MetricsFilter) ([]sqlserver.AgentTickets, error) {
+	return cached(ctx, m, "GetAgentsWorkload", []interface{}{filter}, func() ([]sqlserver.AgentTickets, error) {
+		return m.repo.GetAgentsWorkload(ctx, filter)
+	})
+}
+
+// breakdownPage guarda no cache o resultado paginado de GetTicketsBreakdown
+type breakdownPage struct {
+	Values []dto.MetricValue
+	Count  int64
+}
+
+// GetTicketsBreakdown retorna uma página do total de tickets por dimensão e a quantidade de valores distintos
+func (m *MetricsRepository) GetTicketsBreakdown(ctx context.Context, dimension string, filter dto.MetricsFilter, offset, limit int) ([]dto.MetricValue, int64, error) {
+	page, err := cached(ctx, m, "GetTicketsBreakdown", []interface{}{dimension, filter, offset, limit}, func() (breakdownPage, error) {
+		values, count, err := m.repo.GetTicketsBreakdown(ctx, dimension, filter, offset, limit)
+		return breakdownPage{Values: values, Count: count}, err
+	})
+	return page.Values, page.Count, err
+}
+
+// GetTicketsBreakdownTotal retorna a soma de tickets de todos os valores da dimensão
+func (m *MetricsRepository) GetTicketsBreakdownTotal(ctx context.Context, dimension string, filter dto.MetricsFilter) (int64, error) {
+	return cached(ctx, m, "GetTicketsBreakdownTotal", []interface{}{dimension, filter}, func() (int64, error) {
+		return m.repo.GetTicketsBreakdownTotal(ctx, dimension, filter)
+	})
+}
+
+// StreamTicketsBreakdown percorre todos os valores da dimensão direto no banco, sem cache
+func (m *MetricsRepository) StreamTicketsBreakdown(ctx context.Context, dimension string, filter dto.MetricsFilter, fn func(dto.MetricValue) error) error {
+	return m.repo.StreamTicketsBreakdown(ctx, dimension, filter, fn)
+}
+
+// GetTicketsTop retorna os n valores da dimensão com mais tickets
+func (m *MetricsRepository) GetTicketsTop(ctx context.Context, dimension string, filter dto.MetricsFilter, n int) ([]dto.MetricValue, error) {
+	return cached(ctx, m, "GetTicketsTop", []interface{}{dimension, filter, n}, func() ([]dto.MetricValue, error) {
+		return m.repo.GetTicketsTop(ctx, dimension, filter, n)
+	})
+}
+
+// GetTicketsByPeriod retorna o total de tickets abertos por semana ou mês
+func (m *MetricsRepository) GetTicketsByPeriod(ctx context.Context, granularity string, filter dto.MetricsFilter) ([]sqlserver.PeriodTotal, error) {
+	return cached(ctx, m, "GetTicketsByPeriod", []interface{}{granularity, filter}, func() ([]sqlserver.PeriodTotal, error) {
+		return m.repo.GetTicketsByPeriod(ctx, granularity, filter)
+	})
+}
+
+// GetTrendMovers retorna os valores da dimensão com a maior variação entre o período atual e o anterior
+func (m *MetricsRepository) GetTrendMovers(ctx context.Context, dimension string, filter dto.MetricsFilter, currentStart, previousEnd time.Time, n int) ([]sqlserver.TrendMover, error) {
+	return cached(ctx, m, "GetTrendMovers", []interface{}{dimension, filter, currentStart, previousEnd, n}, func() ([]sqlserver.TrendMover, error) {
+		return m.repo.GetTrendMovers(ctx, dimension, filter, currentStart, previousEnd, n)
+	})
+}
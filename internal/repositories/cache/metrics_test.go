@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"orderstreamrest/internal/models/dto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsCacheKey(t *testing.T) {
+	year := 2024
+	otherYear := 2025
+
+	unfiltered := metricsCacheKey("GetTicketsByMonth", dto.MetricsFilter{})
+	filtered := metricsCacheKey("GetTicketsByMonth", dto.MetricsFilter{Year: &year})
+
+	assert.True(t, strings.HasPrefix(unfiltered, metricsKeyPrefix+"GetTicketsByMonth:"))
+	assert.Equal(t, filtered, metricsCacheKey("GetTicketsByMonth", dto.MetricsFilter{Year: &year}), "same filter must reuse the key")
+	assert.NotEqual(t, unfiltered, filtered)
+	assert.NotEqual(t, filtered, metricsCacheKey("GetTicketsByMonth", dto.MetricsFilter{Year: &otherYear}))
+	assert.NotEqual(t, unfiltered, metricsCacheKey("GetTicketsByTag", dto.MetricsFilter{}), "methods must not share keys")
+}
+
+func TestCachedDisabled(t *testing.T) {
+	m := &MetricsRepository{ttl: 0}
+
+	calls := 0
+	load := func() (int64, error) {
+		calls++
+		return 42, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		total, err := cached(context.Background(), m, "GetTotalTickets", nil, load)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), total)
+	}
+	assert.Equal(t, 2, calls, "a zero TTL must always hit the database")
+
+	expectedErr := errors.New("db down")
+	_, err := cached(context.Background(), m, "GetTotalTickets", nil, func() (int64, error) {
+		return 0, expectedErr
+	})
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestCacheTTL(t *testing.T) {
+	m := &MetricsRepository{ttl: 5 * time.Minute}
+
+	ttl, warming := m.cacheTTL(context.Background())
+	assert.Equal(t, 5*time.Minute, ttl)
+	assert.False(t, warming)
+
+	ttl, warming = m.cacheTTL(context.WithValue(context.Background(), warmTTLKey{}, 75*time.Minute))
+	assert.Equal(t, 75*time.Minute, ttl)
+	assert.True(t, warming)
+
+	// O aquecimento nunca grava entradas que expiram antes do TTL configurado
+	ttl, _ = m.cacheTTL(context.WithValue(context.Background(), warmTTLKey{}, time.Minute))
+	assert.Equal(t, 5*time.Minute, ttl)
+}
+
+func TestWarmDisabled(t *testing.T) {
+	warmed, err := (&MetricsRepository{ttl: 0}).Warm(context.Background(), time.Hour)
+	assert.NoError(t, err)
+	assert.Zero(t, warmed)
+}
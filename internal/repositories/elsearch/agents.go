@@ -0,0 +1,233 @@
+package elsearch
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+	"time"
+)
+
+const (
+	// acknowledgePageSize é a quantidade de tickets lida por página ao calcular o MTTA
+	acknowledgePageSize = 1000
+
+	esDateTimeLayout = "2006-01-02 15:04:05"
+	esDateLayout     = "2006-01-02"
+)
+
+// AgentAcknowledge acumula o tempo até a primeira ação dos tickets de um agente
+type AgentAcknowledge struct {
+	AgentID      string
+	AgentName    string
+	Tickets      int64
+	TotalSeconds float64
+}
+
+// MeanSeconds retorna o tempo médio até a primeira ação, em segundos
+func (a AgentAcknowledge) MeanSeconds() float64 {
+	if a.Tickets == 0 {
+		return 0
+	}
+	return a.TotalSeconds / float64(a.Tickets)
+}
+
+// GetAgentsAcknowledgeTime calcula, por agente atribuído, o tempo entre a abertura do ticket e a
+// primeira mudança registrada em status_history. Tickets sem histórico não entram na média.
+func (es *Client) GetAgentsAcknowledgeTime(ctx context.Context, filter dto.MetricsFilter) (map[string]AgentAcknowledge, error) {
+	agents := make(map[string]AgentAcknowledge)
+
+	var searchAfter []interface{}
+	for {
+		body := map[string]interface{}{
+			"size":  acknowledgePageSize,
+			"query": acknowledgeQuery(filter),
+			"sort": []map[string]interface{}{
+				{"ticket_id": map[string]string{"order": "asc"}},
+			},
+			"_source": []string{
+				"ticket_id",
+				"company.id",
+				"assigned_agent.id",
+				"assigned_agent.full_name",
+				"dates.created_at",
+				"status_history.changed_at",
+			},
+		}
+		if searchAfter != nil {
+			body["search_after"] = searchAfter
+		}
+
+		esResponse, err := es.search(ctx, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search tickets for acknowledge time: %w", err)
+		}
+
+		for _, ticket := range es.decodeHits(ctx, esResponse) {
+			agentID, agentName := assignedAgent(ticket)
+			seconds, ok := AcknowledgeSeconds(ticket)
+			if agentID == "" || !ok {
+				continue
+			}
+
+			agent := agents[agentID]
+			agent.AgentID = agentID
+			agent.AgentName = agentName
+			agent.Tickets++
+			agent.TotalSeconds += seconds
+			agents[agentID] = agent
+		}
+
+		hits := esResponse.Hits.Hits
+		if len(hits) < acknowledgePageSize {
+			break
+		}
+		searchAfter = hits[len(hits)-1].Sort
+		if searchAfter == nil {
+			break
+		}
+	}
+
+	return agents, nil
+}
+
+// acknowledgeQuery seleciona os tickets atribuídos e com histórico, aplicando o filtro de métricas
+func acknowledgeQuery(filter dto.MetricsFilter) map[string]interface{} {
+	filters := []interface{}{
+		map[string]interface{}{"exists": map[string]interface{}{"field": "assigned_agent.id"}},
+		map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path":  "status_history",
+				"query": map[string]interface{}{"exists": map[string]interface{}{"field": "status_history.changed_at"}},
+			},
+		},
+	}
+
+	createdAt := map[string]interface{}{}
+	if filter.StartDate != nil {
+		createdAt["gte"] = filter.StartDate.Format(esDateLayout)
+	}
+	if filter.EndDate != nil {
+		createdAt["lte"] = filter.EndDate.Format(esDateLayout)
+	}
+	if filter.Year != nil {
+		createdAt["gte"] = maxDate(createdAt["gte"], fmt.Sprintf("%04d-01-01", *filter.Year))
+		createdAt["lte"] = minDate(createdAt["lte"], fmt.Sprintf("%04d-12-31", *filter.Year))
+	}
+	if len(createdAt) > 0 {
+		createdAt["format"] = "yyyy-MM-dd"
+		filters = append(filters, map[string]interface{}{
+			"range": map[string]interface{}{"dates.created_at": createdAt},
+		})
+	}
+
+	filters = append(filters, dimensionFilters(filter)...)
+
+	return map[string]interface{}{
+		"bool": map[string]interface{}{"filter": filters},
+	}
+}
+
+// dimensionFilters traduz a empresa, o departamento do agente, o canal, a prioridade e o escopo de empresas do filtro de métricas
+// em filtros term; um escopo sem empresas não retorna nada, como em dimensionConditions
+func dimensionFilters(filter dto.MetricsFilter) []interface{} {
+	var filters []interface{}
+	if filter.Company != nil {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"company.name.keyword": *filter.Company}})
+	}
+	if filter.Department != nil {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"assigned_agent.department": *filter.Department}})
+	}
+	if filter.Channel != nil {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"channel": *filter.Channel}})
+	}
+	if filter.Priority != nil {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"priority": *filter.Priority}})
+	}
+	if filter.CompanyScoped {
+		if len(filter.Companies) > 0 {
+			filters = append(filters, map[string]interface{}{"terms": map[string]interface{}{"company.id": filter.Companies}})
+		} else {
+			filters = append(filters, map[string]interface{}{"match_none": map[string]interface{}{}})
+		}
+	}
+	return filters
+}
+
+// maxDate retorna a maior das datas YYYY-MM-DD, ignorando valores ausentes
+func maxDate(current interface{}, date string) string {
+	if value, ok := current.(string); ok && value > date {
+		return value
+	}
+	return date
+}
+
+// minDate retorna a menor das datas YYYY-MM-DD, ignorando valores ausentes
+func minDate(current interface{}, date string) string {
+	if value, ok := current.(string); ok && value < date {
+		return value
+	}
+	return date
+}
+
+// AcknowledgeSeconds retorna os segundos entre a abertura do ticket e a primeira mudança do status_history.
+// Retorna false quando o ticket não tem data de abertura ou histórico válido.
+func AcknowledgeSeconds(ticket map[string]interface{}) (float64, bool) {
+	dates, _ := ticket["dates"].(map[string]interface{})
+	createdAt, ok := parseESDate(dates["created_at"])
+	if !ok {
+		return 0, false
+	}
+
+	history, _ := ticket["status_history"].([]interface{})
+
+	var first time.Time
+	for _, item := range history {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		changedAt, ok := parseESDate(entry["changed_at"])
+		if !ok || changedAt.Before(createdAt) {
+			continue
+		}
+		if first.IsZero() || changedAt.Before(first) {
+			first = changedAt
+		}
+	}
+
+	if first.IsZero() {
+		return 0, false
+	}
+	return first.Sub(createdAt).Seconds(), true
+}
+
+// assignedAgent retorna o id e o nome do agente atribuído ao ticket
+func assignedAgent(ticket map[string]interface{}) (string, string) {
+	agent, ok := ticket["assigned_agent"].(map[string]interface{})
+	if !ok || agent["id"] == nil {
+		return "", ""
+	}
+
+	name, _ := agent["full_name"].(string)
+	return fmt.Sprint(agent["id"]), name
+}
+
+// parseESDate interpreta uma data nos formatos do índice de tickets
+// (yyyy-MM-dd HH:mm:ss, yyyy-MM-dd ou epoch_millis)
+func parseESDate(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range []string{esDateTimeLayout, esDateLayout, time.RFC3339} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+		if millis, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.UnixMilli(millis).UTC(), true
+		}
+	case float64:
+		return time.UnixMilli(int64(v)).UTC(), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,82 @@
+package elsearch
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcknowledgeSeconds(t *testing.T) {
+	tests := []struct {
+		name     string
+		ticket   map[string]interface{}
+		expected float64
+		ok       bool
+	}{
+		{
+			name: "First change of the history",
+			ticket: map[string]interface{}{
+				"dates": map[string]interface{}{"created_at": "2025-03-10 08:00:00"},
+				"status_history": []interface{}{
+					map[string]interface{}{"changed_at": "2025-03-10 12:00:00"},
+					map[string]interface{}{"changed_at": "2025-03-10 09:30:00"},
+				},
+			},
+			expected: 90 * 60,
+			ok:       true,
+		},
+		{
+			name: "Epoch millis dates",
+			ticket: map[string]interface{}{
+				"dates": map[string]interface{}{"created_at": float64(1741593600000)},
+				"status_history": []interface{}{
+					map[string]interface{}{"changed_at": float64(1741593600000 + 60000)},
+				},
+			},
+			expected: 60,
+			ok:       true,
+		},
+		{
+			name: "Changes before the opening are ignored",
+			ticket: map[string]interface{}{
+				"dates": map[string]interface{}{"created_at": "2025-03-10 08:00:00"},
+				"status_history": []interface{}{
+					map[string]interface{}{"changed_at": "2025-03-09 08:00:00"},
+				},
+			},
+		},
+		{
+			name: "Ticket without history",
+			ticket: map[string]interface{}{
+				"dates": map[string]interface{}{"created_at": "2025-03-10 08:00:00"},
+			},
+		},
+		{
+			name:   "Ticket without opening date",
+			ticket: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seconds, ok := AcknowledgeSeconds(tt.ticket)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, seconds)
+		})
+	}
+}
+
+func TestAcknowledgeQueryYearNarrowsDates(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	year := 2024
+
+	query := acknowledgeQuery(dto.MetricsFilter{StartDate: &start, Year: &year})
+
+	filters := query["bool"].(map[string]interface{})["filter"].([]interface{})
+	createdAt := filters[2].(map[string]interface{})["range"].(map[string]interface{})["dates.created_at"].(map[string]interface{})
+
+	assert.Equal(t, "2024-06-01", createdAt["gte"])
+	assert.Equal(t, "2024-12-31", createdAt["lte"])
+}
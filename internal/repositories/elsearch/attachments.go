@@ -0,0 +1,145 @@
+package elsearch
+
+import (
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/dto"
+	"strings"
+)
+
+// ErrInvalidAttachmentType is returned when attachment_type is neither a known category nor a mime type
+var ErrInvalidAttachmentType error = apperror.New(apperror.ErrValidation, "invalid attachment_type, expected a category (image, video, audio, document, archive) or a mime type such as image/png or image/*")
+
+// attachmentCategories são os prefixos de mime_type de cada categoria aceita em attachment_type
+var attachmentCategories = map[string][]string{
+	"image": {"image/"},
+	"video": {"video/"},
+	"audio": {"audio/"},
+	"document": {
+		"application/pdf",
+		"application/msword",
+		"application/vnd.ms-",
+		"application/vnd.openxmlformats-officedocument.",
+		"application/vnd.oasis.opendocument.",
+		"application/rtf",
+		"text/",
+	},
+	"archive": {
+		"application/zip",
+		"application/gzip",
+		"application/x-7z-compressed",
+		"application/x-rar",
+		"application/vnd.rar",
+		"application/x-tar",
+	},
+}
+
+// attachmentFilters monta os filtros de anexos da busca de tickets. Os anexos são nested, então cada
+// filtro é uma query nested em attachments: has_attachments pede (ou exclui) tickets com algum anexo e
+// attachment_type pede ao menos um anexo da categoria ou do mime type.
+func attachmentFilters(params dto.SearchParams) ([]interface{}, error) {
+	var filters []interface{}
+
+	if params.HasAttachments != nil {
+		present := nestedAttachments(map[string]interface{}{"match_all": map[string]interface{}{}})
+		if *params.HasAttachments {
+			filters = append(filters, present)
+		} else {
+			filters = append(filters, map[string]interface{}{
+				"bool": map[string]interface{}{"must_not": []interface{}{present}},
+			})
+		}
+	}
+
+	if params.AttachmentType != "" {
+		query, err := attachmentTypeQuery(params.AttachmentType)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, nestedAttachments(query))
+	}
+
+	return filters, nil
+}
+
+// attachmentTypeQuery converte attachment_type na query de attachments.mime_type: uma categoria vira
+// os seus prefixos, "image/*" vira o prefixo "image/" e um mime type completo é comparado exatamente
+func attachmentTypeQuery(value string) (map[string]interface{}, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if prefixes, ok := attachmentCategories[value]; ok {
+		should := make([]interface{}, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			should = append(should, map[string]interface{}{
+				"prefix": map[string]interface{}{"attachments.mime_type": prefix},
+			})
+		}
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+			},
+		}, nil
+	}
+
+	kind, subtype, ok := strings.Cut(value, "/")
+	if !ok || kind == "" || subtype == "" || strings.ContainsAny(value, " ,;") {
+		return nil, ErrInvalidAttachmentType
+	}
+	if subtype == "*" {
+		return map[string]interface{}{
+			"prefix": map[string]interface{}{"attachments.mime_type": kind + "/"},
+		}, nil
+	}
+	return map[string]interface{}{
+		"term": map[string]interface{}{"attachments.mime_type": value},
+	}, nil
+}
+
+// nestedAttachments envolve a query em uma query nested no caminho attachments
+func nestedAttachments(query map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"nested": map[string]interface{}{
+			"path":  "attachments",
+			"query": query,
+		},
+	}
+}
+
+// withFilters copia a busca acrescentando os filtros à query, que deixam de contar na relevância.
+// Sem query, os filtros são aplicados sobre todos os tickets.
+func withFilters(search map[string]interface{}, filters []interface{}) map[string]interface{} {
+	if len(filters) == 0 {
+		return search
+	}
+
+	body := make(map[string]interface{}, len(search)+1)
+	for k, v := range search {
+		body[k] = v
+	}
+
+	query, ok := search["query"]
+	if !ok {
+		query = map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+	body["query"] = map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   []interface{}{query},
+			"filter": filters,
+		},
+	}
+	return body
+}
+
+// AttachmentCategory retorna a categoria do mime type (image, video, audio, document, archive), ou
+// "other" quando ele não pertence a nenhuma
+func AttachmentCategory(mimeType string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	for _, category := range []string{"image", "video", "audio", "document", "archive"} {
+		for _, prefix := range attachmentCategories[category] {
+			if strings.HasPrefix(mimeType, prefix) {
+				return category
+			}
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,112 @@
+package elsearch
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentTypeQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "exact mime type",
+			value:    "Image/PNG",
+			expected: map[string]interface{}{"term": map[string]interface{}{"attachments.mime_type": "image/png"}},
+		},
+		{
+			name:     "mime wildcard",
+			value:    "video/*",
+			expected: map[string]interface{}{"prefix": map[string]interface{}{"attachments.mime_type": "video/"}},
+		},
+		{
+			name:  "category",
+			value: "image",
+			expected: map[string]interface{}{"bool": map[string]interface{}{
+				"should":               []interface{}{map[string]interface{}{"prefix": map[string]interface{}{"attachments.mime_type": "image/"}}},
+				"minimum_should_match": 1,
+			}},
+		},
+		{name: "unknown category", value: "screenshot", wantErr: true},
+		{name: "missing subtype", value: "image/", wantErr: true},
+		{name: "list", value: "image/png,image/jpeg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := attachmentTypeQuery(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAttachmentType)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestAttachmentFilters(t *testing.T) {
+	with, without := true, false
+
+	filters, err := attachmentFilters(dto.SearchParams{})
+	require.NoError(t, err)
+	assert.Empty(t, filters)
+
+	filters, err = attachmentFilters(dto.SearchParams{HasAttachments: &with, AttachmentType: "document"})
+	require.NoError(t, err)
+	require.Len(t, filters, 2)
+	assert.Equal(t, "attachments", filters[0].(map[string]interface{})["nested"].(map[string]interface{})["path"])
+	assert.Contains(t, filters[1].(map[string]interface{}), "nested")
+
+	filters, err = attachmentFilters(dto.SearchParams{HasAttachments: &without})
+	require.NoError(t, err)
+	require.Len(t, filters, 1)
+	mustNot := filters[0].(map[string]interface{})["bool"].(map[string]interface{})["must_not"].([]interface{})
+	assert.Contains(t, mustNot[0].(map[string]interface{}), "nested")
+}
+
+func TestWithFilters(t *testing.T) {
+	es := &Client{}
+	filter := nestedAttachments(map[string]interface{}{"match_all": map[string]interface{}{}})
+
+	empty := es.buildSearchQuery("", SearchModeAnalyzed, 0, 10)
+	assert.Equal(t, empty, withFilters(empty, nil))
+
+	filtered := withFilters(empty, []interface{}{filter})
+	boolQuery := filtered["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"match_all": map[string]interface{}{}}}, boolQuery["must"])
+	assert.Equal(t, []interface{}{filter}, boolQuery["filter"])
+	assert.NotContains(t, empty, "query", "the original search is not modified")
+
+	search := es.buildSearchQuery("boleto", SearchModeAnalyzed, 0, 10)
+	filtered = withFilters(search, []interface{}{filter})
+	boolQuery = filtered["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, []interface{}{search["query"]}, boolQuery["must"])
+	assert.Equal(t, search["highlight"], filtered["highlight"])
+}
+
+func TestAttachmentCategory(t *testing.T) {
+	tests := map[string]string{
+		"image/png":       "image",
+		"application/pdf": "document",
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": "document",
+		"text/plain":       "document",
+		"application/zip":  "archive",
+		"audio/mpeg":       "audio",
+		"video/mp4":        "video",
+		"application/json": "other",
+		"":                 "other",
+	}
+
+	for mimeType, expected := range tests {
+		t.Run(mimeType, func(t *testing.T) {
+			assert.Equal(t, expected, AttachmentCategory(mimeType))
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package elsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Direção de uma alteração de prioridade registrada em audit_logs
+const (
+	AuditChangeUpgrade   = "upgrade"
+	AuditChangeDowngrade = "downgrade"
+
+	// auditOperationUpdate é a operação das entradas geradas na atualização de um ticket
+	auditOperationUpdate = "UPDATE"
+	// auditInnerHitsSize é o máximo de entradas de auditoria retornadas por ticket na busca
+	auditInnerHitsSize = 100
+)
+
+// auditedFields são os campos do ticket cujas alterações são registradas em audit_logs, com o
+// caminho do valor comparado no documento
+var auditedFields = []struct {
+	name string
+	path string
+}{
+	{name: "priority", path: "priority"},
+	{name: "current_status", path: "current_status"},
+	{name: "assigned_agent", path: "assigned_agent.id"},
+	{name: "category", path: "category.id"},
+	{name: "subcategory", path: "subcategory.id"},
+	{name: "product", path: "product.id"},
+	{name: "channel", path: "channel"},
+	{name: "sla_plan", path: "sla_plan"},
+}
+
+// priorityRanks ordena as prioridades da mais para a menos urgente
+var priorityRanks = map[string]int{
+	"CRÍTICA": 1,
+	"CRITICA": 1,
+	"ALTA":    2,
+	"MÉDIA":   3,
+	"MEDIA":   3,
+	"BAIXA":   4,
+}
+
+// TicketAuditFilter restringe a busca nas entradas de auditoria dos tickets. Campos vazios não filtram.
+type TicketAuditFilter struct {
+	// Query é buscada no texto da descrição das entradas
+	Query       string
+	PerformedBy string
+	Operation   string
+	Field       string
+	Change      string
+	OldValue    string
+	NewValue    string
+	From        *time.Time
+	To          *time.Time
+}
+
+// TicketAuditHit é um ticket encontrado na busca de auditoria e as suas entradas que atendem ao filtro
+type TicketAuditHit struct {
+	Ticket  map[string]interface{}
+	Entries []map[string]interface{}
+}
+
+// SearchTicketAudit busca os tickets com alguma entrada de auditoria que atende ao filtro, dos mais
+// recentes para os mais antigos, com as entradas encontradas (até auditInnerHitsSize por ticket)
+func (es *Client) SearchTicketAudit(ctx context.Context, filter TicketAuditFilter, from, size int) ([]TicketAuditHit, int64, error) {
+	if from+size > es.MaxResultWindow() {
+		return nil, 0, ErrResultWindowExceeded
+	}
+
+	esResponse, err := es.search(ctx, auditSearchQuery(filter, from, size))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]TicketAuditHit, 0, len(esResponse.Hits.Hits))
+	for _, hit := range esResponse.Hits.Hits {
+		var ticket map[string]interface{}
+		if err := json.Unmarshal(hit.Source, &ticket); err != nil {
+			return nil, 0, fmt.Errorf("error deserializing ticket: %v", err)
+		}
+		if !allowsDocument(ctx, ticket) {
+			continue
+		}
+
+		result := TicketAuditHit{Ticket: ticket, Entries: []map[string]interface{}{}}
+		for _, inner := range hit.InnerHits["audit_logs"].Hits.Hits {
+			var entry map[string]interface{}
+			if err := json.Unmarshal(inner.Source, &entry); err != nil {
+				return nil, 0, fmt.Errorf("error deserializing audit entry: %v", err)
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+		hits = append(hits, result)
+	}
+
+	return hits, esResponse.Hits.Total.Value, nil
+}
+
+// auditSearchQuery monta a busca nested em audit_logs. Os filtros valem para a mesma entrada, de modo
+// que "priority rebaixada pelo agente 12" não encontra um ticket em que o agente 12 alterou outro campo.
+func auditSearchQuery(filter TicketAuditFilter, from, size int) map[string]interface{} {
+	filters := []interface{}{}
+	for _, term := range []struct{ field, value string }{
+		{field: "audit_logs.performed_by", value: filter.PerformedBy},
+		{field: "audit_logs.operation", value: filter.Operation},
+		{field: "audit_logs.field", value: filter.Field},
+		{field: "audit_logs.change", value: filter.Change},
+		{field: "audit_logs.old_value", value: filter.OldValue},
+		{field: "audit_logs.new_value", value: filter.NewValue},
+	} {
+		if term.value != "" {
+			filters = append(filters, map[string]interface{}{"term": map[string]interface{}{term.field: term.value}})
+		}
+	}
+	if filter.From != nil || filter.To != nil {
+		performedAt := map[string]interface{}{"format": "yyyy-MM-dd HH:mm:ss"}
+		if filter.From != nil {
+			performedAt["gte"] = filter.From.UTC().Format(esDateTimeLayout)
+		}
+		if filter.To != nil {
+			performedAt["lt"] = filter.To.UTC().Format(esDateTimeLayout)
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"audit_logs.performed_at": performedAt}})
+	}
+
+	entry := map[string]interface{}{"filter": filters}
+	if filter.Query != "" {
+		entry["must"] = []interface{}{
+			map[string]interface{}{
+				"match": map[string]interface{}{
+					"audit_logs.description": map[string]interface{}{"query": filter.Query, "operator": "and"},
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"from":             from,
+		"size":             size,
+		"track_total_hits": true,
+		"_source":          []string{"ticket_id", "title", "priority", "current_status", "company", "assigned_agent"},
+		"query": map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path":  "audit_logs",
+				"query": map[string]interface{}{"bool": entry},
+				"inner_hits": map[string]interface{}{
+					"size": auditInnerHitsSize,
+					"sort": []map[string]interface{}{
+						{"audit_logs.performed_at": map[string]string{"order": "desc"}},
+					},
+				},
+			},
+		},
+		"sort": []map[string]interface{}{
+			{"dates.created_at": map[string]string{"order": "desc"}},
+			{"ticket_id": map[string]string{"order": "asc"}},
+		},
+	}
+}
+
+// ticketAuditEntries compara o documento gravado com o ticket que vai substituí-lo e retorna uma
+// entrada de auditoria para cada campo de auditedFields que mudou
+func ticketAuditEntries(current map[string]interface{}, ticket dto.Ticket, performedBy string, now time.Time) ([]interface{}, error) {
+	body, err := json.Marshal(ticket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize ticket: %w", err)
+	}
+	var next map[string]interface{}
+	if err := json.Unmarshal(body, &next); err != nil {
+		return nil, fmt.Errorf("failed to serialize ticket: %w", err)
+	}
+
+	var entries []interface{}
+	for _, field := range auditedFields {
+		oldValue, newValue := documentField(current, field.path), documentField(next, field.path)
+		if oldValue == newValue {
+			continue
+		}
+
+		entry := map[string]interface{}{
+			"entity_type":  "ticket",
+			"entity_id":    ticket.TicketID,
+			"operation":    auditOperationUpdate,
+			"performed_by": performedBy,
+			"performed_at": now.UTC().Format(esDateTimeLayout),
+			"field":        field.name,
+			"old_value":    oldValue,
+			"new_value":    newValue,
+			"description":  fmt.Sprintf("%s alterado de %s para %s", field.name, auditDescriptionValue(oldValue), auditDescriptionValue(newValue)),
+		}
+		if field.name == "priority" {
+			if change := priorityChange(oldValue, newValue); change != "" {
+				entry["change"] = change
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// priorityChange indica se a prioridade ficou mais (upgrade) ou menos (downgrade) urgente. Prioridades
+// desconhecidas não têm direção.
+func priorityChange(oldValue, newValue string) string {
+	oldRank, okOld := priorityRanks[strings.ToUpper(oldValue)]
+	newRank, okNew := priorityRanks[strings.ToUpper(newValue)]
+	switch {
+	case !okOld || !okNew || oldRank == newRank:
+		return ""
+	case newRank > oldRank:
+		return AuditChangeDowngrade
+	default:
+		return AuditChangeUpgrade
+	}
+}
+
+// auditDescriptionValue mostra os valores vazios na descrição da entrada
+func auditDescriptionValue(value string) string {
+	if value == "" {
+		return "(vazio)"
+	}
+	return value
+}
+
+// documentField lê como texto o valor do caminho (separado por pontos) no documento
+func documentField(doc map[string]interface{}, path string) string {
+	var value interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		if v == 0 {
+			// Os campos numéricos do ticket são omitidos quando zero
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
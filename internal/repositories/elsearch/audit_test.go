@@ -0,0 +1,151 @@
+package elsearch
+
+import (
+	"context"
+	"net/http"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketAuditEntries(t *testing.T) {
+	now := time.Date(2025, 6, 1, 14, 30, 0, 0, time.UTC)
+	current := map[string]interface{}{
+		"ticket_id":      "TKT-1",
+		"priority":       "ALTA",
+		"current_status": float64(1),
+		"channel":        "EMAIL",
+		"assigned_agent": map[string]interface{}{"id": float64(7)},
+	}
+	ticket := dto.Ticket{
+		TicketID:      "TKT-1",
+		Priority:      "BAIXA",
+		CurrentStatus: 1,
+		Channel:       "EMAIL",
+		AssignedAgent: dto.AssignedAgent{ID: 9},
+		Category:      dto.Category{ID: 3},
+	}
+
+	entries, err := ticketAuditEntries(current, ticket, "12", now)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	priority := entries[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"entity_type":  "ticket",
+		"entity_id":    "TKT-1",
+		"operation":    "UPDATE",
+		"performed_by": "12",
+		"performed_at": "2025-06-01 14:30:00",
+		"field":        "priority",
+		"old_value":    "ALTA",
+		"new_value":    "BAIXA",
+		"change":       AuditChangeDowngrade,
+		"description":  "priority alterado de ALTA para BAIXA",
+	}, priority)
+
+	agent := entries[1].(map[string]interface{})
+	assert.Equal(t, "assigned_agent", agent["field"])
+	assert.Equal(t, "7", agent["old_value"])
+	assert.Equal(t, "9", agent["new_value"])
+	assert.NotContains(t, agent, "change")
+
+	category := entries[2].(map[string]interface{})
+	assert.Equal(t, "category", category["field"])
+	assert.Equal(t, "category alterado de (vazio) para 3", category["description"])
+
+	unchanged, err := ticketAuditEntries(map[string]interface{}{"priority": "ALTA", "channel": "EMAIL"}, dto.Ticket{Priority: "ALTA", Channel: "EMAIL"}, "12", now)
+	require.NoError(t, err)
+	assert.Empty(t, unchanged)
+}
+
+func TestPriorityChange(t *testing.T) {
+	tests := []struct {
+		old, new string
+		expected string
+	}{
+		{old: "ALTA", new: "BAIXA", expected: AuditChangeDowngrade},
+		{old: "Média", new: "crítica", expected: AuditChangeUpgrade},
+		{old: "MEDIA", new: "MÉDIA", expected: ""},
+		{old: "", new: "ALTA", expected: ""},
+		{old: "ALTA", new: "P1", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.old+"->"+tt.new, func(t *testing.T) {
+			assert.Equal(t, tt.expected, priorityChange(tt.old, tt.new))
+		})
+	}
+}
+
+func TestAuditSearchQuery(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	query := auditSearchQuery(TicketAuditFilter{
+		Query:       "prioridade",
+		PerformedBy: "12",
+		Field:       "priority",
+		Change:      AuditChangeDowngrade,
+		From:        &from,
+		To:          &to,
+	}, 20, 10)
+
+	assert.Equal(t, 20, query["from"])
+	assert.Equal(t, 10, query["size"])
+
+	nested := query["query"].(map[string]interface{})["nested"].(map[string]interface{})
+	assert.Equal(t, "audit_logs", nested["path"])
+	assert.Contains(t, nested, "inner_hits")
+
+	entry := nested["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"audit_logs.performed_by": "12"}},
+		map[string]interface{}{"term": map[string]interface{}{"audit_logs.field": "priority"}},
+		map[string]interface{}{"term": map[string]interface{}{"audit_logs.change": AuditChangeDowngrade}},
+		map[string]interface{}{"range": map[string]interface{}{"audit_logs.performed_at": map[string]interface{}{
+			"format": "yyyy-MM-dd HH:mm:ss",
+			"gte":    "2025-06-01 00:00:00",
+			"lt":     "2025-07-01 00:00:00",
+		}}},
+	}, entry["filter"])
+	assert.Len(t, entry["must"], 1)
+
+	empty := auditSearchQuery(TicketAuditFilter{}, 0, 10)
+	entry = empty["query"].(map[string]interface{})["nested"].(map[string]interface{})["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, []interface{}{}, entry["filter"])
+	assert.NotContains(t, entry, "must")
+}
+
+func TestSearchTicketAudit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{
+			"hits": {
+				"total": {"value": 2, "relation": "eq"},
+				"hits": [
+					{"_id": "1", "_source": {"ticket_id": "TKT-1", "company": {"id": 1}}, "inner_hits": {"audit_logs": {"hits": {"total": {"value": 1}, "hits": [
+						{"_source": {"field": "priority", "old_value": "ALTA", "new_value": "BAIXA"}}
+					]}}}},
+					{"_id": "2", "_source": {"ticket_id": "TKT-2", "company": {"id": 2}}, "inner_hits": {"audit_logs": {"hits": {"total": {"value": 0}, "hits": []}}}}
+				]
+			}
+		}`))
+	})
+
+	ctx := WithScope(context.Background(), CompanyScope("1"))
+	hits, total, err := client.SearchTicketAudit(ctx, TicketAuditFilter{Field: "priority"}, 0, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	require.Len(t, hits, 1, "tickets outside of the scope are discarded")
+	assert.Equal(t, "TKT-1", hits[0].Ticket["ticket_id"])
+	require.Len(t, hits[0].Entries, 1)
+	assert.Equal(t, "BAIXA", hits[0].Entries[0]["new_value"])
+
+	_, _, err = client.SearchTicketAudit(ctx, TicketAuditFilter{}, defaultMaxResultWindow, 10)
+	assert.ErrorIs(t, err, ErrResultWindowExceeded)
+}
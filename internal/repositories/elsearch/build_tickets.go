@@ -1,12 +1,57 @@
 package elsearch
 
+// Modos da query de busca de tickets, para comparar a relevância (teste A/B)
+const (
+	// SearchModeAnalyzed combina termos (com os sinônimos do analisador de busca), frase exata e
+	// uma cláusula com fuzziness de menor peso
+	SearchModeAnalyzed = "analyzed"
+	// SearchModeLegacy é a query anterior: um único multi_match com fuzziness
+	SearchModeLegacy = "legacy"
+)
+
+// searchFields são os campos consultados na busca de tickets, com os pesos
+var searchFields = []string{
+	"title^3",
+	"description^2",
+	"search_text^2",
+	"assigned_agent.full_name",
+	"company.name",
+	"created_by_user.full_name",
+	"category.name",
+	"subcategory.name",
+	"product.name",
+	"product.description",
+	"tags",
+	"ticket_id",
+}
+
+// SearchMode retorna o modo de busca a usar: o pedido, quando válido, ou o padrão da configuração
+// (ELASTICSEARCH_SEARCH_MODE), que por sua vez cai em SearchModeAnalyzed
+func (es *Client) SearchMode(requested string) string {
+	for _, mode := range []string{requested, es.defaultSearchMode()} {
+		if mode == SearchModeAnalyzed || mode == SearchModeLegacy {
+			return mode
+		}
+	}
+	return SearchModeAnalyzed
+}
+
+// defaultSearchMode retorna o modo configurado no cliente
+func (es *Client) defaultSearchMode() string {
+	if es.config == nil {
+		return ""
+	}
+	return es.config.SearchMode
+}
+
 // Construir query de busca
-func (es *Client) buildSearchQuery(query string, from, size int) map[string]interface{} {
+func (es *Client) buildSearchQuery(query, mode string, from, size int) map[string]interface{} {
 	if query == "" {
 		// Sem query: apenas paginação e ordenação
 		return map[string]interface{}{
 			"from": from,
 			"size": size,
+			"aggs": facetAggregations(),
 			"sort": []map[string]interface{}{
 				{
 					"dates.created_at": map[string]string{
@@ -16,37 +61,20 @@ func (es *Client) buildSearchQuery(query string, from, size int) map[string]inte
 			},
 		}
 	}
+
+	var match map[string]interface{}
+	if mode == SearchModeLegacy {
+		match = legacyMatch(query)
+	} else {
+		match = analyzedMatch(query)
+	}
+
 	// Com query: busca normal
 	return map[string]interface{}{
-		"from": from,
-		"size": size,
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must": map[string]interface{}{
-					"multi_match": map[string]interface{}{
-						"query": query,
-						"fields": []string{
-							"title^3",
-							"description^2",
-							"search_text^2",
-							"assigned_agent.full_name",
-							"company.name",
-							"created_by_user.full_name",
-							"category.name",
-							"subcategory.name",
-							"product.name",
-							"product.description",
-							"tags",
-							"ticket_id",
-						},
-						"type":                 "best_fields",
-						"fuzziness":            "AUTO",
-						"operator":             "or",
-						"minimum_should_match": "2",
-					},
-				},
-			},
-		},
+		"from":  from,
+		"size":  size,
+		"aggs":  facetAggregations(),
+		"query": match,
 		"sort": []map[string]interface{}{
 			{
 				"_score": map[string]string{
@@ -69,3 +97,62 @@ func (es *Client) buildSearchQuery(query string, from, size int) map[string]inte
 		},
 	}
 }
+
+// analyzedMatch monta a query que aproveita o analisador brazilian_search: os termos são expandidos
+// pelos sinônimos ("boleto" também encontra "fatura"), a frase exata ganha peso e a fuzziness fica
+// numa cláusula separada, já que o Elasticsearch não aplica fuzziness a termos com sinônimos
+func analyzedMatch(query string) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should": []map[string]interface{}{
+				{
+					"multi_match": map[string]interface{}{
+						"query":                query,
+						"fields":               searchFields,
+						"type":                 "best_fields",
+						"operator":             "or",
+						"minimum_should_match": "2<75%",
+					},
+				},
+				{
+					"multi_match": map[string]interface{}{
+						"query":  query,
+						"fields": searchFields,
+						"type":   "phrase",
+						"boost":  2,
+					},
+				},
+				{
+					"multi_match": map[string]interface{}{
+						"query":                query,
+						"fields":               searchFields,
+						"type":                 "best_fields",
+						"fuzziness":            "AUTO",
+						"operator":             "or",
+						"minimum_should_match": "2<75%",
+						"boost":                0.5,
+					},
+				},
+			},
+			"minimum_should_match": 1,
+		},
+	}
+}
+
+// legacyMatch monta a query usada antes do analisador de busca, mantida para o teste A/B
+func legacyMatch(query string) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": map[string]interface{}{
+				"multi_match": map[string]interface{}{
+					"query":                query,
+					"fields":               searchFields,
+					"type":                 "best_fields",
+					"fuzziness":            "AUTO",
+					"operator":             "or",
+					"minimum_should_match": "2",
+				},
+			},
+		},
+	}
+}
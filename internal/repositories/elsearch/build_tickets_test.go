@@ -0,0 +1,50 @@
+package elsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		requested  string
+		expected   string
+	}{
+		{name: "Default without configuration", expected: SearchModeAnalyzed},
+		{name: "Configured default", configured: SearchModeLegacy, expected: SearchModeLegacy},
+		{name: "Requested mode wins", configured: SearchModeLegacy, requested: SearchModeAnalyzed, expected: SearchModeAnalyzed},
+		{name: "Invalid requested mode falls back", requested: "bm25", expected: SearchModeAnalyzed},
+		{name: "Invalid configured mode falls back", configured: "bm25", expected: SearchModeAnalyzed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &Client{config: &Config{SearchMode: tt.configured}}
+			assert.Equal(t, tt.expected, es.SearchMode(tt.requested))
+		})
+	}
+
+	assert.Equal(t, SearchModeAnalyzed, (&Client{}).SearchMode(""))
+}
+
+func TestBuildSearchQueryModes(t *testing.T) {
+	es := &Client{}
+
+	analyzed := es.buildSearchQuery("boleto atrasado", SearchModeAnalyzed, 0, 10)
+	should := analyzed["query"].(map[string]interface{})["bool"].(map[string]interface{})["should"].([]map[string]interface{})
+	assert.Len(t, should, 3)
+	// A cláusula principal não usa fuzziness, que o Elasticsearch ignora em termos com sinônimos
+	assert.NotContains(t, should[0]["multi_match"], "fuzziness")
+	assert.Equal(t, "phrase", should[1]["multi_match"].(map[string]interface{})["type"])
+	assert.Equal(t, "AUTO", should[2]["multi_match"].(map[string]interface{})["fuzziness"])
+
+	legacy := es.buildSearchQuery("boleto atrasado", SearchModeLegacy, 0, 10)
+	must := legacy["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"].(map[string]interface{})
+	assert.Equal(t, "2", must["multi_match"].(map[string]interface{})["minimum_should_match"])
+
+	empty := es.buildSearchQuery("", SearchModeLegacy, 0, 10)
+	assert.NotContains(t, empty, "query")
+}
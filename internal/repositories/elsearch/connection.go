@@ -2,14 +2,18 @@ package elsearch
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"orderstreamrest/internal/resilience"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v9"
 	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"go.opentelemetry.io/otel"
 )
 
 type Config struct {
@@ -27,11 +31,25 @@ type Config struct {
 	InsecureSkipVerify bool
 
 	IndexName string
+
+	// SearchMode is the default ticket search query mode (SearchModeAnalyzed or SearchModeLegacy)
+	SearchMode string
+	// MaxResultWindow is the index.max_result_window of the tickets index; deeper pages need a cursor
+	MaxResultWindow int
+	// SlowQueryThreshold is the search duration from which the query is logged; zero disables the log
+	SlowQueryThreshold time.Duration
 }
 
 type Client struct {
 	ES     *elasticsearch.Client
 	config *Config
+
+	// searchBreaker e bulkBreaker protegem as buscas de tickets e a Bulk API
+	searchBreaker *resilience.Breaker
+	bulkBreaker   *resilience.Breaker
+
+	// queryLogger recebe as buscas mais lentas que config.SlowQueryThreshold
+	queryLogger QueryLogger
 }
 
 // NewClient creates a new Elasticsearch client with the provided configuration
@@ -63,6 +81,25 @@ func NewClient(cfg *Config) (*Client, error) {
 		}
 	}
 
+	if cfg.SearchMode == "" {
+		cfg.SearchMode = os.Getenv("ELASTICSEARCH_SEARCH_MODE")
+	}
+
+	if cfg.MaxResultWindow == 0 {
+		if window, err := strconv.Atoi(os.Getenv("ELASTICSEARCH_MAX_RESULT_WINDOW")); err == nil && window > 0 {
+			cfg.MaxResultWindow = window
+		} else {
+			cfg.MaxResultWindow = defaultMaxResultWindow
+		}
+	}
+
+	if cfg.SlowQueryThreshold == 0 {
+		cfg.SlowQueryThreshold = defaultSlowQueryThreshold
+		if value, err := strconv.Atoi(os.Getenv("ELASTICSEARCH_SLOW_QUERY_MS")); err == nil && value >= 0 {
+			cfg.SlowQueryThreshold = time.Duration(value) * time.Millisecond
+		}
+	}
+
 	// Set defaults
 	if cfg.MaxRetries == 0 {
 		cfg.MaxRetries = 3
@@ -80,8 +117,9 @@ func NewClient(cfg *Config) (*Client, error) {
 		Username:  cfg.Username,
 		Password:  cfg.Password,
 
-		RetryOnStatus: []int{502, 503, 504, 429},
-		MaxRetries:    cfg.MaxRetries,
+		RetryOnStatus:   []int{502, 503, 504, 429},
+		Instrumentation: elasticsearch.NewOpenTelemetryInstrumentation(otel.GetTracerProvider(), false),
+		MaxRetries:      cfg.MaxRetries,
 		RetryBackoff: func(i int) time.Duration {
 			return cfg.RetryBackoff * time.Duration(i)
 		},
@@ -107,6 +145,7 @@ func NewClient(cfg *Config) (*Client, error) {
 		ES:     es,
 		config: cfg,
 	}
+	client.searchBreaker, client.bulkBreaker = newBreakers()
 
 	// Test connection
 	if err := client.Ping(); err != nil {
@@ -116,17 +155,19 @@ func NewClient(cfg *Config) (*Client, error) {
 	return client, nil
 }
 
-// Ping tests the connection to Elasticsearch
+// Ping tests the connection to Elasticsearch. The requests always carry a context, which the
+// OpenTelemetry instrumentation needs to start their spans.
 func (c *Client) Ping() error {
-	res, err := c.ES.Ping()
+	return c.PingContext(context.Background())
+}
+
+// PingContext tests the connection to Elasticsearch, giving up when ctx is done
+func (c *Client) PingContext(ctx context.Context) error {
+	res, err := c.ES.Ping(c.ES.Ping.WithContext(ctx))
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			fmt.Printf("error closing response body: %v\n", err)
-		}
-	}()
+	defer closeBody(res.Body)
 
 	if res.IsError() {
 		return fmt.Errorf("elasticsearch ping failed with status: %s", res.Status())
@@ -137,18 +178,19 @@ func (c *Client) Ping() error {
 
 // Info returns cluster information
 func (c *Client) Info() (*esapi.Response, error) {
-	return c.ES.Info()
+	return c.ES.Info(c.ES.Info.WithContext(context.Background()))
 }
 
 // Health returns cluster health information
 func (c *Client) Health() (*esapi.Response, error) {
-	return c.ES.Cluster.Health()
+	return c.ES.Cluster.Health(c.ES.Cluster.Health.WithContext(context.Background()))
 }
 
 // CreateIndex creates an index with optional mapping
-func (c *Client) CreateIndex(indexName string, mapping []byte) error {
+func (c *Client) CreateIndex(ctx context.Context, indexName string, mapping []byte) error {
 	res, err := c.ES.Indices.Create(
 		indexName,
+		c.ES.Indices.Create.WithContext(ctx),
 		c.ES.Indices.Create.WithBody(bytes.NewReader(mapping)),
 		c.ES.Indices.Create.WithPretty(),
 	)
@@ -168,8 +210,8 @@ func (c *Client) CreateIndex(indexName string, mapping []byte) error {
 }
 
 // IndexExists checks if an index exists
-func (c *Client) IndexExists(indexName string) (bool, error) {
-	res, err := c.ES.Indices.Exists([]string{indexName})
+func (c *Client) IndexExists(ctx context.Context, indexName string) (bool, error) {
+	res, err := c.ES.Indices.Exists([]string{indexName}, c.ES.Indices.Exists.WithContext(ctx))
 	if err != nil {
 		return false, err
 	}
@@ -182,8 +224,8 @@ func (c *Client) IndexExists(indexName string) (bool, error) {
 }
 
 // DeleteIndex deletes an index
-func (c *Client) DeleteIndex(indexName string) error {
-	res, err := c.ES.Indices.Delete([]string{indexName})
+func (c *Client) DeleteIndex(ctx context.Context, indexName string) error {
+	res, err := c.ES.Indices.Delete([]string{indexName}, c.ES.Indices.Delete.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete index %s: %w", indexName, err)
 	}
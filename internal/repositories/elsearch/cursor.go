@@ -0,0 +1,93 @@
+package elsearch
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/dto"
+)
+
+// defaultMaxResultWindow é o index.max_result_window padrão do Elasticsearch
+const defaultMaxResultWindow = 10000
+
+// ErrResultWindowExceeded is returned when a page number goes beyond the index result window
+var ErrResultWindowExceeded error = apperror.New(apperror.ErrValidation, "page is beyond the search result window, continue with pagination.next_cursor")
+
+// ErrInvalidCursor is returned when a search cursor cannot be decoded or belongs to another search
+var ErrInvalidCursor error = apperror.New(apperror.ErrValidation, "invalid search cursor")
+
+// searchCursor é o conteúdo do cursor opaco da busca de tickets: a página que ele abre e os
+// valores de ordenação do último ticket da página anterior, para o search_after
+type searchCursor struct {
+	Page  int           `json:"p"`
+	Size  int           `json:"s"`
+	Query string        `json:"q"`
+	Mode  string        `json:"m"`
+	After []interface{} `json:"a"`
+	// HasAttachments e AttachmentType são os filtros de anexos da busca que gerou o cursor
+	HasAttachments *bool  `json:"h,omitempty"`
+	AttachmentType string `json:"t,omitempty"`
+}
+
+// encodeCursor serializa o cursor em base64 para a URL
+func encodeCursor(cursor searchCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor lê o cursor recebido do cliente. Os números são mantidos como json.Number para que
+// os valores de ordenação voltem ao Elasticsearch sem perder precisão.
+func decodeCursor(value string) (searchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return searchCursor{}, ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var cursor searchCursor
+	if err := decoder.Decode(&cursor); err != nil || cursor.Page < 2 || cursor.Size < 1 || len(cursor.After) == 0 {
+		return searchCursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// MaxResultWindow retorna o limite de from+size das buscas paginadas por número de página
+// (ELASTICSEARCH_MAX_RESULT_WINDOW, igual ao index.max_result_window do índice)
+func (es *Client) MaxResultWindow() int {
+	if es.config == nil || es.config.MaxResultWindow < 1 {
+		return defaultMaxResultWindow
+	}
+	return es.config.MaxResultWindow
+}
+
+// withTiebreaker copia a busca acrescentando ticket_id à ordenação, para que o search_after
+// não repita nem pule tickets com a mesma pontuação e data
+func withTiebreaker(search map[string]interface{}) map[string]interface{} {
+	body := make(map[string]interface{}, len(search))
+	for k, v := range search {
+		body[k] = v
+	}
+
+	sort, _ := search["sort"].([]map[string]interface{})
+	body["sort"] = append(append([]map[string]interface{}{}, sort...), map[string]interface{}{
+		"ticket_id": map[string]string{"order": "asc"},
+	})
+	return body
+}
+
+// sameAttachmentFilters indica se os filtros de anexos do cursor são os mesmos da busca
+func sameAttachmentFilters(cursor searchCursor, params dto.SearchParams) bool {
+	if (cursor.HasAttachments == nil) != (params.HasAttachments == nil) {
+		return false
+	}
+	if cursor.HasAttachments != nil && *cursor.HasAttachments != *params.HasAttachments {
+		return false
+	}
+	return cursor.AttachmentType == params.AttachmentType
+}
@@ -0,0 +1,124 @@
+package elsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeepSearchHandler devolve uma página de um resultado com 25.000 tickets, com os valores de ordenação
+func fakeDeepSearchHandler(t *testing.T, receivedBody *map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		*receivedBody = nil
+		require.NoError(t, json.Unmarshal(body, receivedBody))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{
+			"hits": {
+				"total": {"value": 25000, "relation": "eq"},
+				"hits": [
+					{"_id": "1", "_source": {"ticket_id": "TKT-001"}, "sort": [1735689600123, "TKT-001"]},
+					{"_id": "2", "_source": {"ticket_id": "TKT-002"}, "sort": [1735689600123, "TKT-002"]}
+				]
+			}
+		}`))
+	}
+}
+
+func TestSearchTicketsBySomeWord_DeepPaging(t *testing.T) {
+	var receivedBody map[string]interface{}
+	client := newTestClient(t, fakeDeepSearchHandler(t, &receivedBody))
+	ctx := WithScope(context.Background(), UnrestrictedScope())
+
+	first, err := client.SearchTicketsBySomeWord(ctx, dto.SearchParams{Page: 199, PageSize: 50})
+	require.NoError(t, err)
+	assert.Equal(t, true, receivedBody["track_total_hits"])
+	assert.EqualValues(t, 198*50, receivedBody["from"])
+	sort := receivedBody["sort"].([]interface{})
+	assert.Contains(t, sort[len(sort)-1], "ticket_id")
+	require.NotEmpty(t, first.Pagination.NextCursor)
+
+	// A página 201 passa do max_result_window e não chega ao Elasticsearch
+	receivedBody = nil
+	_, err = client.SearchTicketsBySomeWord(ctx, dto.SearchParams{Page: 201, PageSize: 50})
+	assert.ErrorIs(t, err, ErrResultWindowExceeded)
+	assert.Nil(t, receivedBody)
+
+	// O cursor continua a partir do último ticket, sem from
+	next, err := client.SearchTicketsBySomeWord(ctx, dto.SearchParams{Cursor: first.Pagination.NextCursor, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.NotContains(t, receivedBody, "from")
+	assert.Equal(t, []interface{}{float64(1735689600123), "TKT-002"}, receivedBody["search_after"])
+	assert.Equal(t, 200, next.Pagination.CurrentPage)
+	assert.Equal(t, 50, next.Pagination.PerPage)
+	assert.True(t, next.Pagination.HasPrev)
+	assert.NotEmpty(t, next.Pagination.NextCursor)
+}
+
+func TestSearchTicketsBySomeWord_AttachmentFilters(t *testing.T) {
+	var receivedBody map[string]interface{}
+	client := newTestClient(t, fakeDeepSearchHandler(t, &receivedBody))
+	ctx := WithScope(context.Background(), UnrestrictedScope())
+	with := true
+
+	params := dto.SearchParams{Query: "boleto", Page: 1, PageSize: 2, HasAttachments: &with, AttachmentType: "image"}
+	first, err := client.SearchTicketsBySomeWord(ctx, params)
+	require.NoError(t, err)
+	filters := receivedBody["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"].([]interface{})
+	assert.Len(t, filters, 2)
+
+	// O cursor guarda os filtros e só vale para a busca com os mesmos filtros
+	params.Cursor = first.Pagination.NextCursor
+	_, err = client.SearchTicketsBySomeWord(ctx, params)
+	require.NoError(t, err)
+	params.AttachmentType = "document"
+	_, err = client.SearchTicketsBySomeWord(ctx, params)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	receivedBody = nil
+	_, err = client.SearchTicketsBySomeWord(ctx, dto.SearchParams{AttachmentType: "screenshot"})
+	assert.ErrorIs(t, err, ErrInvalidAttachmentType)
+	assert.Nil(t, receivedBody)
+}
+
+func TestSearchTicketsBySomeWord_InvalidCursor(t *testing.T) {
+	var receivedBody map[string]interface{}
+	client := newTestClient(t, fakeDeepSearchHandler(t, &receivedBody))
+	ctx := WithScope(context.Background(), UnrestrictedScope())
+
+	otherSearch, err := encodeCursor(searchCursor{Page: 2, Size: 50, Query: "boleto", Mode: SearchModeAnalyzed, After: []interface{}{1, "TKT-001"}})
+	require.NoError(t, err)
+	withoutAfter, err := encodeCursor(searchCursor{Page: 2, Size: 50, Mode: SearchModeAnalyzed})
+	require.NoError(t, err)
+	otherFilters, err := encodeCursor(searchCursor{Page: 2, Size: 50, Query: "fatura", Mode: SearchModeAnalyzed, After: []interface{}{1, "TKT-001"}, AttachmentType: "image"})
+	require.NoError(t, err)
+
+	for name, cursor := range map[string]string{
+		"not base64":          "%%%",
+		"not json":            "bm90IGpzb24",
+		"another query":       otherSearch,
+		"missing sort values": withoutAfter,
+		"another filter":      otherFilters,
+	} {
+		t.Run(name, func(t *testing.T) {
+			receivedBody = nil
+			_, err := client.SearchTicketsBySomeWord(ctx, dto.SearchParams{Query: "fatura", Cursor: cursor})
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+			assert.Nil(t, receivedBody)
+		})
+	}
+}
+
+func TestMaxResultWindow(t *testing.T) {
+	assert.Equal(t, defaultMaxResultWindow, (&Client{}).MaxResultWindow())
+	assert.Equal(t, 500, (&Client{config: &Config{MaxResultWindow: 500}}).MaxResultWindow())
+}
@@ -0,0 +1,43 @@
+package elsearch
+
+import (
+	"context"
+	"fmt"
+)
+
+// exportPageSize é a quantidade de tickets buscada por página na exportação
+const exportPageSize = 1000
+
+// ExportTickets percorre todos os tickets da busca, na mesma ordem de SearchTicketsBySomeWord,
+// paginando com search_after. fn é chamada para cada ticket; um erro de fn interrompe a exportação.
+func (es *Client) ExportTickets(ctx context.Context, query string, fn func(ticket map[string]interface{}) error) error {
+	body := exportQuery(es.buildSearchQuery(query, es.SearchMode(""), 0, exportPageSize))
+
+	for {
+		esResponse, err := es.search(ctx, body)
+		if err != nil {
+			return fmt.Errorf("failed to export tickets: %w", err)
+		}
+
+		for _, ticket := range es.decodeHits(ctx, esResponse) {
+			if err := fn(ticket); err != nil {
+				return err
+			}
+		}
+
+		hits := esResponse.Hits.Hits
+		if len(hits) < exportPageSize || hits[len(hits)-1].Sort == nil {
+			return nil
+		}
+		body["search_after"] = hits[len(hits)-1].Sort
+	}
+}
+
+// exportQuery adapta a query de busca paginada para search_after: sem from e agregações,
+// com ticket_id como desempate para que nenhum ticket seja repetido ou pulado entre páginas
+func exportQuery(search map[string]interface{}) map[string]interface{} {
+	body := withTiebreaker(search)
+	delete(body, "from")
+	delete(body, "aggs")
+	return body
+}
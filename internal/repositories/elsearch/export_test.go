@@ -0,0 +1,25 @@
+package elsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportQuery(t *testing.T) {
+	es := &Client{}
+
+	for _, query := range []string{"", "internet lenta"} {
+		search := es.buildSearchQuery(query, SearchModeAnalyzed, 50, exportPageSize)
+		body := exportQuery(search)
+
+		assert.NotContains(t, body, "from")
+		assert.NotContains(t, body, "aggs")
+		assert.Equal(t, exportPageSize, body["size"])
+
+		sort := body["sort"].([]map[string]interface{})
+		assert.Len(t, sort, len(search["sort"].([]map[string]interface{}))+1)
+		assert.Contains(t, sort[len(sort)-1], "ticket_id")
+		assert.Contains(t, search, "from", "the search body must not be changed")
+	}
+}
@@ -0,0 +1,63 @@
+package elsearch
+
+import (
+	"fmt"
+	"orderstreamrest/internal/models/dto"
+	"sort"
+)
+
+// facetSize é a quantidade máxima de valores retornados por facet
+const facetSize = 20
+
+// ticketFacets relaciona o nome de cada facet ao campo keyword do índice de tickets
+var ticketFacets = map[string]string{
+	"status":   "current_status",
+	"priority": "priority",
+	"category": "category.name.keyword",
+	"channel":  "channel",
+}
+
+// facetAggregations monta as agregações terms dos facets da busca de tickets
+func facetAggregations() map[string]interface{} {
+	aggs := make(map[string]interface{}, len(ticketFacets))
+	for name, field := range ticketFacets {
+		aggs[name] = map[string]interface{}{
+			"terms": map[string]interface{}{
+				"field": field,
+				"size":  facetSize,
+			},
+		}
+	}
+	return aggs
+}
+
+// decodeFacets converte as agregações da resposta em facets, do valor mais frequente para o menos.
+// Como as agregações rodam sobre a query com escopo, as contagens respeitam o escopo do usuário.
+func decodeFacets(esResponse *dto.ESResponse) dto.Facets {
+	if len(esResponse.Aggregations) == 0 {
+		return nil
+	}
+
+	facets := make(dto.Facets, len(ticketFacets))
+	for name := range ticketFacets {
+		aggregation, ok := esResponse.Aggregations[name]
+		if !ok {
+			continue
+		}
+
+		values := make([]dto.FacetValue, 0, len(aggregation.Buckets))
+		for _, bucket := range aggregation.Buckets {
+			values = append(values, dto.FacetValue{
+				Value: fmt.Sprint(bucket.Key),
+				Count: bucket.DocCount,
+			})
+		}
+		sort.SliceStable(values, func(i, j int) bool {
+			return values[i].Count > values[j].Count
+		})
+
+		facets[name] = values
+	}
+
+	return facets
+}
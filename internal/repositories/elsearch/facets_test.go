@@ -0,0 +1,44 @@
+package elsearch
+
+import (
+	"encoding/json"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFacetAggregations(t *testing.T) {
+	aggs := facetAggregations()
+
+	require.Len(t, aggs, len(ticketFacets))
+	terms := aggs["category"].(map[string]interface{})["terms"].(map[string]interface{})
+	assert.Equal(t, "category.name.keyword", terms["field"])
+	assert.Equal(t, facetSize, terms["size"])
+}
+
+func TestDecodeFacets(t *testing.T) {
+	body := `{
+		"hits": {"total": {"value": 3}, "hits": []},
+		"aggregations": {
+			"status": {"buckets": [{"key": "FECHADO", "doc_count": 1}, {"key": "ABERTO", "doc_count": 2}]},
+			"priority": {"buckets": [{"key": "ALTA", "doc_count": 3}]},
+			"channel": {"buckets": []}
+		}
+	}`
+
+	var esResponse dto.ESResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &esResponse))
+
+	facets := decodeFacets(&esResponse)
+
+	assert.Equal(t, []dto.FacetValue{{Value: "ABERTO", Count: 2}, {Value: "FECHADO", Count: 1}}, facets["status"])
+	assert.Equal(t, []dto.FacetValue{{Value: "ALTA", Count: 3}}, facets["priority"])
+	assert.Empty(t, facets["channel"])
+	assert.NotContains(t, facets, "category")
+}
+
+func TestDecodeFacetsWithoutAggregations(t *testing.T) {
+	assert.Nil(t, decodeFacets(&dto.ESResponse{}))
+}
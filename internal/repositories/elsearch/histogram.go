@@ -0,0 +1,106 @@
+package elsearch
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/models/dto"
+	"time"
+)
+
+// Intervalos do histograma de tickets, na sintaxe do calendar_interval do Elasticsearch
+const (
+	HistogramDay   = "day"
+	HistogramWeek  = "week"
+	HistogramMonth = "month"
+)
+
+// TicketHistogramBucket é a quantidade de tickets abertos em um período do histograma. Start é o primeiro
+// dia do período no fuso do filtro, como data (meia-noite UTC).
+type TicketHistogramBucket struct {
+	Start time.Time
+	Count int64
+}
+
+// GetTicketHistogram conta os tickets abertos por dia, semana (de segunda a domingo) ou mês entre
+// filter.StartDate e filter.EndDate, inclusivos, pela data de criação contada no fuso do filtro.
+// Com filter.Year, a janela é a interseção das datas com o ano. Todos os períodos são retornados,
+// inclusive os sem tickets. As datas do filtro são obrigatórias.
+func (es *Client) GetTicketHistogram(ctx context.Context, interval string, filter dto.MetricsFilter) ([]TicketHistogramBucket, error) {
+	if filter.StartDate == nil || filter.EndDate == nil {
+		return nil, fmt.Errorf("ticket histogram needs startDate and endDate")
+	}
+	start, end := histogramBounds(filter)
+	if end < start {
+		return nil, fmt.Errorf("ticket histogram range %s to %s is empty", start, end)
+	}
+
+	esResponse, err := es.search(ctx, histogramQuery(interval, filter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tickets per %s: %w", interval, err)
+	}
+
+	buckets := esResponse.Aggregations["per_interval"].Buckets
+	histogram := make([]TicketHistogramBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		start, err := time.Parse(esDateLayout, bucket.KeyAsString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s bucket %q: %w", interval, bucket.KeyAsString, err)
+		}
+		histogram = append(histogram, TicketHistogramBucket{Start: start, Count: bucket.DocCount})
+	}
+	return histogram, nil
+}
+
+// histogramBounds retorna as datas YYYY-MM-DD da janela do histograma, limitadas ao ano do filtro
+// como em acknowledgeQuery
+func histogramBounds(filter dto.MetricsFilter) (string, string) {
+	start, end := filter.StartDate.Format(esDateLayout), filter.EndDate.Format(esDateLayout)
+	if filter.Year != nil {
+		start = maxDate(start, fmt.Sprintf("%04d-01-01", *filter.Year))
+		end = minDate(end, fmt.Sprintf("%04d-12-31", *filter.Year))
+	}
+	return start, end
+}
+
+// histogramQuery monta o date_histogram de dates.created_at com o filtro de métricas. O range e os
+// períodos usam o fuso do filtro; extended_bounds garante os períodos sem tickets nas pontas.
+func histogramQuery(interval string, filter dto.MetricsFilter) map[string]interface{} {
+	timeZone := filter.Timezone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	start, end := histogramBounds(filter)
+
+	filters := append([]interface{}{
+		map[string]interface{}{
+			"range": map[string]interface{}{
+				"dates.created_at": map[string]interface{}{
+					"gte":       start,
+					"lte":       end,
+					"format":    "yyyy-MM-dd",
+					"time_zone": timeZone,
+				},
+			},
+		},
+	}, dimensionFilters(filter)...)
+
+	return map[string]interface{}{
+		"size":  0,
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": filters}},
+		"aggs": map[string]interface{}{
+			"per_interval": map[string]interface{}{
+				"date_histogram": map[string]interface{}{
+					"field":             "dates.created_at",
+					"calendar_interval": interval,
+					"format":            "yyyy-MM-dd",
+					"time_zone":         timeZone,
+					"min_doc_count":     0,
+					"extended_bounds": map[string]interface{}{
+						"min": start,
+						"max": end,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,147 @@
+package elsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTicketHistogram(t *testing.T) {
+	var receivedBody map[string]interface{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &receivedBody))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{
+			"hits": {"total": {"value": 9, "relation": "eq"}, "hits": []},
+			"aggregations": {
+				"per_interval": {
+					"buckets": [
+						{"key": 1759719600000, "key_as_string": "2025-10-06", "doc_count": 5},
+						{"key": 1760324400000, "key_as_string": "2025-10-13", "doc_count": 0},
+						{"key": 1760929200000, "key_as_string": "2025-10-20", "doc_count": 4}
+					]
+				}
+			}
+		}`))
+	})
+
+	start := time.Date(2025, time.October, 8, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.October, 21, 0, 0, 0, 0, time.UTC)
+	channel, department := "Email", "Suporte"
+	filter := dto.MetricsFilter{StartDate: &start, EndDate: &end, Channel: &channel, Department: &department, Timezone: "America/Sao_Paulo"}
+
+	buckets, err := client.GetTicketHistogram(WithScope(context.Background(), UnrestrictedScope()), HistogramWeek, filter)
+	require.NoError(t, err)
+
+	assert.Equal(t, []TicketHistogramBucket{
+		{Start: time.Date(2025, time.October, 6, 0, 0, 0, 0, time.UTC), Count: 5},
+		{Start: time.Date(2025, time.October, 13, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Start: time.Date(2025, time.October, 20, 0, 0, 0, 0, time.UTC), Count: 4},
+	}, buckets)
+
+	histogram := receivedBody["aggs"].(map[string]interface{})["per_interval"].(map[string]interface{})["date_histogram"].(map[string]interface{})
+	assert.Equal(t, "dates.created_at", histogram["field"])
+	assert.Equal(t, "week", histogram["calendar_interval"])
+	assert.Equal(t, "America/Sao_Paulo", histogram["time_zone"])
+	assert.Equal(t, map[string]interface{}{"min": "2025-10-08", "max": "2025-10-21"}, histogram["extended_bounds"])
+
+	query, err := json.Marshal(receivedBody["query"])
+	require.NoError(t, err)
+	assert.Contains(t, string(query), `"gte":"2025-10-08"`)
+	assert.Contains(t, string(query), `{"term":{"channel":"Email"}}`)
+	assert.Contains(t, string(query), `{"term":{"assigned_agent.department":"Suporte"}}`)
+}
+
+func TestGetTicketHistogram_RequiresDates(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("search must not run without a date range")
+	})
+
+	_, err := client.GetTicketHistogram(WithScope(context.Background(), UnrestrictedScope()), HistogramDay, dto.MetricsFilter{})
+	assert.Error(t, err)
+}
+
+func TestHistogramQuery_YearAndCompanyScope(t *testing.T) {
+	start := time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)
+	year := 2025
+
+	tests := []struct {
+		name          string
+		filter        dto.MetricsFilter
+		expectedMin   string
+		expectedMax   string
+		expectedScope string
+	}{
+		{
+			name:        "Dates only",
+			filter:      dto.MetricsFilter{StartDate: &start, EndDate: &end},
+			expectedMin: "2024-12-01",
+			expectedMax: "2025-02-28",
+		},
+		{
+			name:        "Year intersects the dates",
+			filter:      dto.MetricsFilter{StartDate: &start, EndDate: &end, Year: &year},
+			expectedMin: "2025-01-01",
+			expectedMax: "2025-02-28",
+		},
+		{
+			name:          "Company scope",
+			filter:        dto.MetricsFilter{StartDate: &start, EndDate: &end, CompanyScoped: true, Companies: []string{"10", "20"}},
+			expectedMin:   "2024-12-01",
+			expectedMax:   "2025-02-28",
+			expectedScope: `{"terms":{"company.id":["10","20"]}}`,
+		},
+		{
+			name:          "Company scope without companies",
+			filter:        dto.MetricsFilter{StartDate: &start, EndDate: &end, CompanyScoped: true},
+			expectedMin:   "2024-12-01",
+			expectedMax:   "2025-02-28",
+			expectedScope: `{"match_none":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := histogramQuery(HistogramDay, tt.filter)
+
+			histogram := body["aggs"].(map[string]interface{})["per_interval"].(map[string]interface{})["date_histogram"].(map[string]interface{})
+			assert.Equal(t, map[string]interface{}{"min": tt.expectedMin, "max": tt.expectedMax}, histogram["extended_bounds"])
+
+			query, err := json.Marshal(body["query"])
+			require.NoError(t, err)
+			assert.Contains(t, string(query), `"gte":"`+tt.expectedMin+`"`)
+			assert.Contains(t, string(query), `"lte":"`+tt.expectedMax+`"`)
+			if tt.expectedScope != "" {
+				assert.Contains(t, string(query), tt.expectedScope)
+			} else {
+				assert.NotContains(t, string(query), "company.id")
+			}
+		})
+	}
+}
+
+func TestGetTicketHistogram_YearOutsideDates(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("search must not run with an empty date range")
+	})
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+	year := 2024
+	filter := dto.MetricsFilter{StartDate: &start, EndDate: &end, Year: &year}
+
+	_, err := client.GetTicketHistogram(WithScope(context.Background(), UnrestrictedScope()), HistogramDay, filter)
+	assert.Error(t, err)
+}
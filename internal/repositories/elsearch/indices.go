@@ -0,0 +1,446 @@
+package elsearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"orderstreamrest/internal/apperror"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// TicketsIndex é o alias do índice de tickets usado nas buscas
+	TicketsIndex = "support_tickets"
+	// LogsIndex é o alias do índice de logs da aplicação
+	LogsIndex = "datavision-api-logs"
+
+	// ticketSearchAnalyzer é o analisador de busca do índice de tickets, o único que aplica os sinônimos.
+	// Na indexação o analisador "brazilian" não expande sinônimos, então trocar as regras não exige reindexar.
+	ticketSearchAnalyzer = "brazilian_search"
+	// synonymsFilter é o nome do filtro de sinônimos do analisador de busca
+	synonymsFilter = "ticket_synonyms"
+)
+
+// ErrUnmanagedIndex is returned when an index is not managed by the application
+var ErrUnmanagedIndex error = apperror.New(apperror.ErrNotFound, "index is not managed by the application")
+
+//go:embed mappings/*.json
+var mappingFiles embed.FS
+
+// IndexDefinition descreve um índice gerenciado pela aplicação. A aplicação usa sempre o alias;
+// o índice físico leva a versão do mapping no nome (ex.: support_tickets_v2).
+type IndexDefinition struct {
+	Alias    string
+	File     string
+	Synonyms bool
+}
+
+// ManagedIndices são os índices criados no bootstrap e que podem ser reindexados
+var ManagedIndices = []IndexDefinition{
+	{Alias: TicketsIndex, File: "mappings/support_tickets.json", Synonyms: true},
+	{Alias: LogsIndex, File: "mappings/datavision-api-logs.json"},
+}
+
+// IndexStatus é a situação de um índice gerenciado após o bootstrap
+type IndexStatus struct {
+	Alias           string
+	Indices         []string
+	CurrentVersion  int
+	ExpectedVersion int
+	Created         bool
+}
+
+// ReindexResult é o resultado da migração de um alias para a versão atual do mapping
+type ReindexResult struct {
+	Alias     string
+	From      []string
+	To        string
+	Documents int64
+	Took      time.Duration
+}
+
+// LookupIndex retorna a definição do índice gerenciado com o alias informado
+func LookupIndex(alias string) (IndexDefinition, error) {
+	for _, definition := range ManagedIndices {
+		if definition.Alias == alias {
+			return definition, nil
+		}
+	}
+	return IndexDefinition{}, fmt.Errorf("%w: %s", ErrUnmanagedIndex, alias)
+}
+
+// Body retorna settings e mappings do índice, com os sinônimos de ELASTICSEARCH_SYNONYMS_FILE
+// no lugar dos sinônimos padrão do mapping quando configurados
+func (d IndexDefinition) Body() (map[string]interface{}, error) {
+	var synonyms []string
+	if d.Synonyms {
+		if path := os.Getenv("ELASTICSEARCH_SYNONYMS_FILE"); path != "" {
+			var err error
+			if synonyms, err = readSynonyms(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return d.bodyWithSynonyms(synonyms)
+}
+
+// bodyWithSynonyms retorna settings e mappings do índice com as regras de sinônimos informadas
+func (d IndexDefinition) bodyWithSynonyms(synonyms []string) (map[string]interface{}, error) {
+	data, err := mappingFiles.ReadFile(d.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping %s: %w", d.File, err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping %s: %w", d.File, err)
+	}
+
+	if d.Synonyms {
+		addSynonyms(body, synonyms)
+	}
+
+	return body, nil
+}
+
+// Version retorna a versão declarada em mappings._meta.version
+func (d IndexDefinition) Version() (int, error) {
+	body, err := d.Body()
+	if err != nil {
+		return 0, err
+	}
+	return mappingVersion(body["mappings"]), nil
+}
+
+// VersionedName retorna o nome do índice físico da versão informada
+func (d IndexDefinition) VersionedName(version int) string {
+	return fmt.Sprintf("%s_v%d", d.Alias, version)
+}
+
+// EnsureIndices cria os índices gerenciados que ainda não existem, já com o alias.
+// Índices existentes não são alterados; quando estão em uma versão anterior do mapping,
+// o status indica que é preciso reindexar.
+func (c *Client) EnsureIndices(ctx context.Context) ([]IndexStatus, error) {
+	statuses := make([]IndexStatus, 0, len(ManagedIndices))
+
+	for _, definition := range ManagedIndices {
+		status, err := c.ensureIndex(ctx, definition)
+		if err != nil {
+			return statuses, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// ensureIndex cria o índice da definição caso o alias ainda não exista
+func (c *Client) ensureIndex(ctx context.Context, definition IndexDefinition) (IndexStatus, error) {
+	body, err := definition.Body()
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	status := IndexStatus{
+		Alias:           definition.Alias,
+		ExpectedVersion: mappingVersion(body["mappings"]),
+	}
+
+	exists, err := c.IndexExists(ctx, definition.Alias)
+	if err != nil {
+		return status, fmt.Errorf("failed to check index %s: %w", definition.Alias, err)
+	}
+
+	if exists {
+		status.Indices, err = c.resolveAlias(ctx, definition.Alias)
+		if err != nil {
+			return status, err
+		}
+		status.CurrentVersion, err = c.indexVersion(ctx, definition.Alias)
+		return status, err
+	}
+
+	name := definition.VersionedName(status.ExpectedVersion)
+	body["aliases"] = map[string]interface{}{definition.Alias: map[string]interface{}{}}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return status, fmt.Errorf("failed to serialize index %s: %w", name, err)
+	}
+	if err := c.CreateIndex(ctx, name, data); err != nil {
+		return status, err
+	}
+
+	status.Indices = []string{name}
+	status.CurrentVersion = status.ExpectedVersion
+	status.Created = true
+	return status, nil
+}
+
+// Reindex copia os documentos do alias para um novo índice com a versão atual do mapping e
+// move o alias para ele em uma única operação, sem janela em que as buscas fiquem sem índice.
+// Um índice legado com o mesmo nome do alias é removido na troca.
+func (c *Client) Reindex(ctx context.Context, alias string) (*ReindexResult, error) {
+	definition, err := LookupIndex(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := definition.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := c.resolveAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("index %s does not exist", alias)
+	}
+
+	target := definition.VersionedName(version)
+	for _, source := range sources {
+		if source == target {
+			return nil, fmt.Errorf("index %s is already at mapping version %d", alias, version)
+		}
+	}
+
+	// Cria o destino sem o alias, que só é movido depois da cópia
+	body, err := definition.Body()
+	if err != nil {
+		return nil, err
+	}
+	// Um conjunto de sinônimos enviado por POST /admin/search/synonyms vale mais que o arquivo da configuração
+	if definition.Synonyms {
+		if body, err = c.carrySynonyms(ctx, definition, body); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize index %s: %w", target, err)
+	}
+	if err := c.CreateIndex(ctx, target, data); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	copied, err := c.copyDocuments(ctx, alias, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.swapAlias(ctx, alias, sources, target); err != nil {
+		return nil, err
+	}
+
+	return &ReindexResult{
+		Alias:     alias,
+		From:      sources,
+		To:        target,
+		Documents: copied,
+		Took:      time.Since(started),
+	}, nil
+}
+
+// copyDocuments executa o _reindex e aguarda sua conclusão
+func (c *Client) copyDocuments(ctx context.Context, source, target string) (int64, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"source": map[string]interface{}{"index": source},
+		"dest":   map[string]interface{}{"index": target},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize reindex request: %w", err)
+	}
+
+	res, err := c.ES.Reindex(
+		bytes.NewReader(body),
+		c.ES.Reindex.WithContext(ctx),
+		c.ES.Reindex.WithWaitForCompletion(true),
+		c.ES.Reindex.WithRefresh(true),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reindex %s into %s: %w", source, target, err)
+	}
+	defer closeBody(res.Body)
+
+	if res.IsError() {
+		return 0, fmt.Errorf("failed to reindex %s into %s: %s", source, target, res.String())
+	}
+
+	var result struct {
+		Total    int64             `json:"total"`
+		Failures []json.RawMessage `json:"failures"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode reindex response: %w", err)
+	}
+	if len(result.Failures) > 0 {
+		return result.Total, fmt.Errorf("reindex of %s into %s had %d failures: %s", source, target, len(result.Failures), result.Failures[0])
+	}
+
+	return result.Total, nil
+}
+
+// swapAlias aponta o alias para o novo índice e o remove dos anteriores atomicamente
+func (c *Client) swapAlias(ctx context.Context, alias string, sources []string, target string) error {
+	actions := make([]interface{}, 0, len(sources)+1)
+	for _, source := range sources {
+		if source == alias {
+			// Índice legado criado sem alias: precisa ser removido para o nome virar alias
+			actions = append(actions, map[string]interface{}{"remove_index": map[string]interface{}{"index": source}})
+			continue
+		}
+		actions = append(actions, map[string]interface{}{"remove": map[string]interface{}{"index": source, "alias": alias}})
+	}
+	actions = append(actions, map[string]interface{}{"add": map[string]interface{}{"index": target, "alias": alias}})
+
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("failed to serialize alias actions: %w", err)
+	}
+
+	res, err := c.ES.Indices.UpdateAliases(bytes.NewReader(body), c.ES.Indices.UpdateAliases.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to move alias %s to %s: %w", alias, target, err)
+	}
+	defer closeBody(res.Body)
+
+	if res.IsError() {
+		return fmt.Errorf("failed to move alias %s to %s: %s", alias, target, res.String())
+	}
+	return nil
+}
+
+// resolveAlias retorna os índices físicos por trás do nome. Um índice legado (sem alias)
+// é retornado com o próprio nome; um nome inexistente resulta em lista vazia.
+func (c *Client) resolveAlias(ctx context.Context, name string) ([]string, error) {
+	res, err := c.ES.Indices.GetAlias(
+		c.ES.Indices.GetAlias.WithContext(ctx),
+		c.ES.Indices.GetAlias.WithIndex(name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve index %s: %w", name, err)
+	}
+	defer closeBody(res.Body)
+
+	if res.StatusCode == 404 {
+		return nil, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to resolve index %s: %s", name, res.String())
+	}
+
+	var indices map[string]json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return nil, fmt.Errorf("failed to decode aliases of %s: %w", name, err)
+	}
+
+	names := make([]string, 0, len(indices))
+	for index := range indices {
+		names = append(names, index)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// indexVersion retorna a menor versão de mapping entre os índices do alias; 0 quando não declarada
+func (c *Client) indexVersion(ctx context.Context, name string) (int, error) {
+	res, err := c.ES.Indices.GetMapping(
+		c.ES.Indices.GetMapping.WithContext(ctx),
+		c.ES.Indices.GetMapping.WithIndex(name),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mapping of %s: %w", name, err)
+	}
+	defer closeBody(res.Body)
+
+	if res.IsError() {
+		return 0, fmt.Errorf("failed to get mapping of %s: %s", name, res.String())
+	}
+
+	var indices map[string]struct {
+		Mappings map[string]interface{} `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return 0, fmt.Errorf("failed to decode mapping of %s: %w", name, err)
+	}
+
+	version := -1
+	for _, index := range indices {
+		if v := mappingVersion(index.Mappings); version < 0 || v < version {
+			version = v
+		}
+	}
+	if version < 0 {
+		return 0, nil
+	}
+	return version, nil
+}
+
+// mappingVersion lê _meta.version de um mapping
+func mappingVersion(mappings interface{}) int {
+	m, _ := mappings.(map[string]interface{})
+	meta, _ := m["_meta"].(map[string]interface{})
+	version, _ := meta["version"].(float64)
+	return int(version)
+}
+
+// readSynonyms lê um arquivo de sinônimos no formato Solr (uma regra por linha, # para comentários)
+func readSynonyms(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open synonyms file: %w", err)
+	}
+	defer file.Close()
+
+	var synonyms []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		synonyms = append(synonyms, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read synonyms file: %w", err)
+	}
+
+	return synonyms, nil
+}
+
+// addSynonyms troca as regras padrão do filtro de sinônimos do mapping pelas informadas
+func addSynonyms(body map[string]interface{}, synonyms []string) {
+	if len(synonyms) == 0 {
+		return
+	}
+
+	settings, _ := body["settings"].(map[string]interface{})
+	analysis, _ := settings["analysis"].(map[string]interface{})
+	filters, _ := analysis["filter"].(map[string]interface{})
+	filter, _ := filters[synonymsFilter].(map[string]interface{})
+	if filter == nil {
+		log.Printf("Index has no %s filter, synonyms ignored", synonymsFilter)
+		return
+	}
+
+	filter["synonyms"] = synonyms
+}
+
+// closeBody fecha o corpo de uma resposta do Elasticsearch
+func closeBody(body io.ReadCloser) {
+	if err := body.Close(); err != nil {
+		log.Printf("error closing response body: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package elsearch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedIndicesMappings(t *testing.T) {
+	for _, definition := range ManagedIndices {
+		t.Run(definition.Alias, func(t *testing.T) {
+			body, err := definition.Body()
+			require.NoError(t, err)
+			assert.Contains(t, body, "mappings")
+
+			version, err := definition.Version()
+			require.NoError(t, err)
+			assert.Positive(t, version)
+			assert.Equal(t, definition.Alias+"_v1", definition.VersionedName(1))
+		})
+	}
+}
+
+func TestLookupIndex(t *testing.T) {
+	definition, err := LookupIndex(TicketsIndex)
+	require.NoError(t, err)
+	assert.True(t, definition.Synonyms)
+
+	_, err = LookupIndex("other-index")
+	assert.ErrorIs(t, err, ErrUnmanagedIndex)
+}
+
+func TestTicketsIndexWithSynonyms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synonyms.txt")
+	content := "# sinônimos de tickets\n\nnotebook, laptop\nimpressora => printer\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv("ELASTICSEARCH_SYNONYMS_FILE", path)
+
+	definition, err := LookupIndex(TicketsIndex)
+	require.NoError(t, err)
+
+	body, err := definition.Body()
+	require.NoError(t, err)
+
+	analysis := body["settings"].(map[string]interface{})["analysis"].(map[string]interface{})
+	filter := analysis["filter"].(map[string]interface{})[synonymsFilter].(map[string]interface{})
+	assert.Equal(t, []string{"notebook, laptop", "impressora => printer"}, filter["synonyms"])
+
+	assert.Equal(t, "synonym_graph", filter["type"])
+}
+
+func TestTicketsIndexAnalyzers(t *testing.T) {
+	definition, err := LookupIndex(TicketsIndex)
+	require.NoError(t, err)
+
+	body, err := definition.Body()
+	require.NoError(t, err)
+
+	analysis := body["settings"].(map[string]interface{})["analysis"].(map[string]interface{})
+	analyzers := analysis["analyzer"].(map[string]interface{})
+
+	// Os sinônimos ficam só no analisador de busca, para não exigir reindex ao trocá-los
+	searchChain := analyzers[ticketSearchAnalyzer].(map[string]interface{})["filter"].([]interface{})
+	assert.Equal(t, []interface{}{"lowercase", synonymsFilter}, searchChain[:2])
+	indexChain := analyzers["brazilian"].(map[string]interface{})["filter"].([]interface{})
+	assert.NotContains(t, indexChain, synonymsFilter)
+
+	filter := analysis["filter"].(map[string]interface{})[synonymsFilter].(map[string]interface{})
+	assert.Contains(t, filter["synonyms"], "boleto, fatura, cobrança")
+
+	properties := body["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
+	for _, field := range []string{"title", "description", "search_text"} {
+		assert.Equal(t, ticketSearchAnalyzer, properties[field].(map[string]interface{})["search_analyzer"], field)
+	}
+}
+
+func TestMissingSynonymsFile(t *testing.T) {
+	t.Setenv("ELASTICSEARCH_SYNONYMS_FILE", filepath.Join(t.TempDir(), "missing.txt"))
+
+	definition, err := LookupIndex(TicketsIndex)
+	require.NoError(t, err)
+
+	_, err = definition.Body()
+	assert.Error(t, err)
+}
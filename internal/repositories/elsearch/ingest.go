@@ -0,0 +1,308 @@
+package elsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+var (
+	// ErrTicketExists is returned when creating a ticket whose ticket_id is already indexed
+	ErrTicketExists error = apperror.New(apperror.ErrConflict, "ticket already exists")
+	// ErrTicketNotFound is returned when updating a ticket that does not exist or is outside of the scope
+	ErrTicketNotFound error = apperror.New(apperror.ErrNotFound, "ticket not found")
+	// ErrVersionConflict is returned when the ticket was changed since the version the caller read
+	ErrVersionConflict error = apperror.New(apperror.ErrPreconditionFailed, "ticket was modified by another request")
+	// ErrOutOfScope is returned when the ticket belongs to a company outside of the caller scope
+	ErrOutOfScope error = apperror.New(apperror.ErrForbidden, "ticket is outside of the caller scope")
+	// ErrInvalidVersion is returned when a document version cannot be parsed
+	ErrInvalidVersion error = apperror.New(apperror.ErrValidation, "invalid document version")
+)
+
+// DocumentVersion identifica a versão de um documento para o controle de concorrência otimista
+// do Elasticsearch (if_seq_no/if_primary_term)
+type DocumentVersion struct {
+	SeqNo       int64
+	PrimaryTerm int64
+}
+
+// String formata a versão como "<seq_no>.<primary_term>", o valor usado no ETag
+func (v DocumentVersion) String() string {
+	return strconv.FormatInt(v.SeqNo, 10) + "." + strconv.FormatInt(v.PrimaryTerm, 10)
+}
+
+// ParseDocumentVersion lê uma versão no formato de String, aceitando também um ETag entre aspas
+func ParseDocumentVersion(value string) (DocumentVersion, error) {
+	value = strings.Trim(strings.TrimPrefix(strings.TrimSpace(value), "W/"), `"`)
+
+	seqNo, primaryTerm, ok := strings.Cut(value, ".")
+	if !ok {
+		return DocumentVersion{}, fmt.Errorf("%w: %q", ErrInvalidVersion, value)
+	}
+
+	var version DocumentVersion
+	var err error
+	if version.SeqNo, err = strconv.ParseInt(seqNo, 10, 64); err != nil || version.SeqNo < 0 {
+		return DocumentVersion{}, fmt.Errorf("%w: %q", ErrInvalidVersion, value)
+	}
+	if version.PrimaryTerm, err = strconv.ParseInt(primaryTerm, 10, 64); err != nil || version.PrimaryTerm < 1 {
+		return DocumentVersion{}, fmt.Errorf("%w: %q", ErrInvalidVersion, value)
+	}
+	return version, nil
+}
+
+// storedTicket é um ticket já indexado, com o _id e a versão atual do documento
+type storedTicket struct {
+	ID      string
+	Source  map[string]interface{}
+	Version DocumentVersion
+}
+
+// CreateTicket indexa um novo ticket usando o ticket_id como _id do documento.
+// Retorna ErrTicketExists se o ticket_id já estiver indexado.
+func (es *Client) CreateTicket(ctx context.Context, ticket dto.Ticket) (*dto.TicketDocument, error) {
+	// A verificação ignora o escopo: um ticket de outra empresa com o mesmo ID também é conflito
+	if _, err := es.findTicket(WithScope(ctx, UnrestrictedScope()), ticket.TicketID); err == nil {
+		return nil, ErrTicketExists
+	} else if !errors.Is(err, ErrTicketNotFound) {
+		return nil, err
+	}
+
+	return es.writeTicket(ctx, ticket.TicketID, ticket, nil)
+}
+
+// UpdateTicket substitui o documento do ticket. Quando expected é informado, a escrita só acontece
+// se o documento ainda estiver nessa versão; caso contrário retorna ErrVersionConflict.
+// As alterações de auditedFields são acrescentadas a audit_logs em nome de performedBy; sem audit_logs
+// no ticket enviado, a trilha gravada é mantida.
+func (es *Client) UpdateTicket(ctx context.Context, ticketID string, ticket dto.Ticket, expected *DocumentVersion, performedBy string) (*dto.TicketDocument, error) {
+	current, err := es.findTicket(WithScope(ctx, UnrestrictedScope()), ticketID)
+	if err != nil {
+		return nil, err
+	}
+	// Tickets fora do escopo são tratados como inexistentes, como nas buscas
+	if !allowsDocument(ctx, current.Source) {
+		return nil, ErrTicketNotFound
+	}
+
+	if expected != nil && *expected != current.Version {
+		return nil, ErrVersionConflict
+	}
+
+	ticket.TicketID = ticketID
+	entries, err := ticketAuditEntries(current.Source, ticket, performedBy, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if ticket.AuditLogs == nil {
+		ticket.AuditLogs, _ = current.Source["audit_logs"].([]interface{})
+	}
+	ticket.AuditLogs = append(ticket.AuditLogs, entries...)
+
+	return es.writeTicket(ctx, current.ID, ticket, &current.Version)
+}
+
+// GetTicketDocument busca o documento do ticket pelo ticket_id, como gravado no índice, e a sua versão.
+// Retorna ErrTicketNotFound se o ticket não existir ou estiver fora do escopo.
+func (es *Client) GetTicketDocument(ctx context.Context, ticketID string) (map[string]interface{}, DocumentVersion, error) {
+	stored, err := es.findTicket(ctx, ticketID)
+	if err != nil {
+		return nil, DocumentVersion{}, err
+	}
+	return stored.Source, stored.Version, nil
+}
+
+// findTicket busca o documento do ticket pelo ticket_id, com seq_no e primary_term
+func (es *Client) findTicket(ctx context.Context, ticketID string) (*storedTicket, error) {
+	esResponse, err := es.search(ctx, map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{
+				"ticket_id": ticketID,
+			},
+		},
+		"size":                1,
+		"seq_no_primary_term": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(esResponse.Hits.Hits) == 0 {
+		return nil, ErrTicketNotFound
+	}
+
+	hit := esResponse.Hits.Hits[0]
+	if hit.SeqNo == nil || hit.PrimaryTerm == nil {
+		return nil, fmt.Errorf("search response for ticket %s has no document version", ticketID)
+	}
+
+	var source map[string]interface{}
+	if err := json.Unmarshal(hit.Source, &source); err != nil {
+		return nil, fmt.Errorf("error deserializing ticket: %v", err)
+	}
+
+	return &storedTicket{
+		ID:      hit.ID,
+		Source:  source,
+		Version: DocumentVersion{SeqNo: *hit.SeqNo, PrimaryTerm: *hit.PrimaryTerm},
+	}, nil
+}
+
+// writeTicket grava o ticket no documento id. Sem version o documento é criado (op_type=create);
+// com version a escrita é condicionada a ela.
+func (es *Client) writeTicket(ctx context.Context, id string, ticket dto.Ticket, version *DocumentVersion) (*dto.TicketDocument, error) {
+	body, err := json.Marshal(ticket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize ticket: %w", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to serialize ticket: %w", err)
+	}
+	if !allowsDocument(ctx, doc) {
+		return nil, ErrOutOfScope
+	}
+
+	options := []func(*esapi.IndexRequest){
+		es.ES.Index.WithContext(ctx),
+		es.ES.Index.WithDocumentID(id),
+		es.ES.Index.WithRefresh("wait_for"),
+	}
+	if version == nil {
+		options = append(options, es.ES.Index.WithOpType("create"))
+	} else {
+		options = append(options,
+			es.ES.Index.WithIfSeqNo(int(version.SeqNo)),
+			es.ES.Index.WithIfPrimaryTerm(int(version.PrimaryTerm)),
+		)
+	}
+
+	res, err := es.ES.Index(es.config.IndexName, bytes.NewReader(body), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to index ticket %s: %w", ticket.TicketID, err)
+	}
+	defer closeBody(res.Body)
+
+	if res.StatusCode == http.StatusConflict {
+		if version == nil {
+			return nil, ErrTicketExists
+		}
+		return nil, ErrVersionConflict
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to index ticket %s: %s", ticket.TicketID, res.String())
+	}
+
+	var result struct {
+		SeqNo       int64 `json:"_seq_no"`
+		PrimaryTerm int64 `json:"_primary_term"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode index response: %w", err)
+	}
+
+	return &dto.TicketDocument{
+		Ticket:  ticket,
+		Version: DocumentVersion{SeqNo: result.SeqNo, PrimaryTerm: result.PrimaryTerm}.String(),
+	}, nil
+}
+
+// BulkIndexTickets grava os tickets em uma única requisição à Bulk API, usando o ticket_id como _id.
+// Tickets existentes são substituídos. Retorna um resultado por ticket, na mesma ordem; tickets fora
+// do escopo não são enviados e voltam com status 403.
+func (es *Client) BulkIndexTickets(ctx context.Context, tickets []dto.Ticket) ([]dto.BulkTicketResult, error) {
+	results := make([]dto.BulkTicketResult, len(tickets))
+	sent := make([]int, 0, len(tickets))
+
+	var body bytes.Buffer
+	for i, ticket := range tickets {
+		results[i] = dto.BulkTicketResult{TicketId: ticket.TicketID}
+
+		source, err := json.Marshal(ticket)
+		if err != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = err.Error()
+			continue
+		}
+
+		var doc map[string]interface{}
+		if err := json.Unmarshal(source, &doc); err != nil || !allowsDocument(ctx, doc) {
+			results[i].Status = http.StatusForbidden
+			results[i].Error = ErrOutOfScope.Error()
+			continue
+		}
+
+		action, err := json.Marshal(map[string]interface{}{
+			"index": map[string]interface{}{"_id": ticket.TicketID},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize bulk action: %w", err)
+		}
+
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(source)
+		body.WriteByte('\n')
+		sent = append(sent, i)
+	}
+
+	if len(sent) == 0 {
+		return results, nil
+	}
+
+	res, err := doProtected(ctx, es.bulkBreaker, func(ctx context.Context) (*esapi.Response, error) {
+		return es.ES.Bulk(
+			bytes.NewReader(body.Bytes()),
+			es.ES.Bulk.WithContext(ctx),
+			es.ES.Bulk.WithIndex(es.config.IndexName),
+		)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute bulk request: %w", err)
+	}
+	defer closeBody(res.Body)
+
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to execute bulk request: %s", res.String())
+	}
+
+	var response struct {
+		Items []map[string]struct {
+			Status int    `json:"status"`
+			Result string `json:"result"`
+			Error  *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if len(response.Items) != len(sent) {
+		return nil, fmt.Errorf("bulk response has %d items for %d tickets", len(response.Items), len(sent))
+	}
+
+	for n, item := range response.Items {
+		i := sent[n]
+		for _, outcome := range item {
+			results[i].Status = outcome.Status
+			results[i].Result = outcome.Result
+			if outcome.Error != nil {
+				results[i].Error = outcome.Error.Type + ": " + outcome.Error.Reason
+			}
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,44 @@
+package elsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDocumentVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected DocumentVersion
+		wantErr  bool
+	}{
+		{name: "Plain version", value: "42.1", expected: DocumentVersion{SeqNo: 42, PrimaryTerm: 1}},
+		{name: "Quoted ETag", value: `"0.3"`, expected: DocumentVersion{SeqNo: 0, PrimaryTerm: 3}},
+		{name: "Weak ETag", value: `W/"7.2"`, expected: DocumentVersion{SeqNo: 7, PrimaryTerm: 2}},
+		{name: "Missing primary term", value: "42", wantErr: true},
+		{name: "Zero primary term", value: "42.0", wantErr: true},
+		{name: "Negative sequence number", value: "-1.1", wantErr: true},
+		{name: "Not a number", value: "abc.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := ParseDocumentVersion(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidVersion)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+			assert.Equal(t, version, mustParse(t, version.String()))
+		})
+	}
+}
+
+func mustParse(t *testing.T, value string) DocumentVersion {
+	t.Helper()
+	version, err := ParseDocumentVersion(value)
+	assert.NoError(t, err)
+	return version
+}
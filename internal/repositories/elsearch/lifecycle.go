@@ -0,0 +1,129 @@
+package elsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// LogsIndexPattern são os índices diários de logs (ex.: datavision-api-logs-2025.06.01)
+	LogsIndexPattern = LogsIndex + "-*"
+	// LogsIndexTemplate é o template aplicado aos índices diários de logs
+	LogsIndexTemplate = LogsIndex
+	// LogsLifecyclePolicy é a política de ILM que remove os índices diários após a retenção
+	LogsLifecyclePolicy = LogsIndex + "-retention"
+)
+
+// EnsureLogsLifecycle cria ou atualiza o template dos índices diários de logs, com o mapping e o
+// alias de LogsIndex, para que as buscas no alias continuem cobrindo todos os dias. Com retentionDays
+// maior que zero, cria também a política de ILM que apaga os índices mais antigos que a retenção.
+func (c *Client) EnsureLogsLifecycle(ctx context.Context, retentionDays int) error {
+	if retentionDays > 0 {
+		if err := c.putLifecyclePolicy(ctx, LogsLifecyclePolicy, retentionDays); err != nil {
+			return err
+		}
+	}
+
+	body, err := logsTemplateBody(retentionDays)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.ES.Indices.PutIndexTemplate(
+		LogsIndexTemplate,
+		bytes.NewReader(body),
+		c.ES.Indices.PutIndexTemplate.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to put index template %s: %w", LogsIndexTemplate, err)
+	}
+	defer closeBody(res.Body)
+
+	if res.IsError() {
+		return fmt.Errorf("failed to put index template %s: %s", LogsIndexTemplate, res.String())
+	}
+	return nil
+}
+
+// putLifecyclePolicy grava uma política de ILM que apenas apaga o índice após retentionDays
+func (c *Client) putLifecyclePolicy(ctx context.Context, name string, retentionDays int) error {
+	body, err := json.Marshal(lifecyclePolicyBody(retentionDays))
+	if err != nil {
+		return fmt.Errorf("failed to serialize lifecycle policy %s: %w", name, err)
+	}
+
+	res, err := c.ES.ILM.PutLifecycle(
+		name,
+		c.ES.ILM.PutLifecycle.WithBody(bytes.NewReader(body)),
+		c.ES.ILM.PutLifecycle.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to put lifecycle policy %s: %w", name, err)
+	}
+	defer closeBody(res.Body)
+
+	if res.IsError() {
+		return fmt.Errorf("failed to put lifecycle policy %s: %s", name, res.String())
+	}
+	return nil
+}
+
+// lifecyclePolicyBody monta a política com a fase delete após a retenção
+func lifecyclePolicyBody(retentionDays int) map[string]interface{} {
+	return map[string]interface{}{
+		"policy": map[string]interface{}{
+			"_meta": map[string]interface{}{"managed_by": "datavision-api"},
+			"phases": map[string]interface{}{
+				"hot": map[string]interface{}{
+					"min_age": "0ms",
+					"actions": map[string]interface{}{},
+				},
+				"delete": map[string]interface{}{
+					"min_age": fmt.Sprintf("%dd", retentionDays),
+					"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+				},
+			},
+		},
+	}
+}
+
+// logsTemplateBody monta o template dos índices diários a partir do mapping de LogsIndex
+func logsTemplateBody(retentionDays int) ([]byte, error) {
+	definition, err := LookupIndex(LogsIndex)
+	if err != nil {
+		return nil, err
+	}
+	index, err := definition.Body()
+	if err != nil {
+		return nil, err
+	}
+
+	settings, _ := index["settings"].(map[string]interface{})
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+	if retentionDays > 0 {
+		settings["index.lifecycle.name"] = LogsLifecyclePolicy
+	}
+
+	template := map[string]interface{}{
+		"mappings": index["mappings"],
+		"aliases":  map[string]interface{}{LogsIndex: map[string]interface{}{}},
+	}
+	if len(settings) > 0 {
+		template["settings"] = settings
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"index_patterns": []string{LogsIndexPattern},
+		"priority":       100,
+		"template":       template,
+		"_meta":          map[string]interface{}{"managed_by": "datavision-api"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize index template %s: %w", LogsIndexTemplate, err)
+	}
+	return body, nil
+}
@@ -0,0 +1,55 @@
+package elsearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsTemplateBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		retentionDays int
+		wantPolicy    bool
+	}{
+		{name: "with retention", retentionDays: 30, wantPolicy: true},
+		{name: "without retention", retentionDays: 0, wantPolicy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := logsTemplateBody(tt.retentionDays)
+			require.NoError(t, err)
+
+			var body struct {
+				IndexPatterns []string `json:"index_patterns"`
+				Template      struct {
+					Settings map[string]interface{} `json:"settings"`
+					Mappings map[string]interface{} `json:"mappings"`
+					Aliases  map[string]interface{} `json:"aliases"`
+				} `json:"template"`
+			}
+			require.NoError(t, json.Unmarshal(data, &body))
+
+			assert.Equal(t, []string{"datavision-api-logs-*"}, body.IndexPatterns)
+			assert.Contains(t, body.Template.Aliases, LogsIndex)
+			assert.Positive(t, mappingVersion(body.Template.Mappings))
+			if tt.wantPolicy {
+				assert.Equal(t, LogsLifecyclePolicy, body.Template.Settings["index.lifecycle.name"])
+			} else {
+				assert.NotContains(t, body.Template.Settings, "index.lifecycle.name")
+			}
+		})
+	}
+}
+
+func TestLifecyclePolicyBody(t *testing.T) {
+	body := lifecyclePolicyBody(7)
+
+	policy := body["policy"].(map[string]interface{})
+	phases := policy["phases"].(map[string]interface{})
+	remove := phases["delete"].(map[string]interface{})
+	assert.Equal(t, "7d", remove["min_age"])
+}
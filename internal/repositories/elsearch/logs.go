@@ -0,0 +1,203 @@
+package elsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"orderstreamrest/pkg/logger"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+// MaxLogsWindow é o limite de from+size de uma busca paginada (index.max_result_window padrão)
+const MaxLogsWindow = 10000
+
+// LogFilter são os critérios da busca nos logs da aplicação; campos vazios não filtram
+type LogFilter struct {
+	From      *time.Time
+	To        *time.Time
+	Levels    []string
+	RequestID string
+	UserID    string
+	// Path filtra pelo prefixo do caminho da requisição (ex.: /tickets cobre /tickets/123)
+	Path string
+}
+
+// SearchLogs busca os logs da aplicação no alias LogsIndex, do mais recente para o mais antigo,
+// e retorna a página pedida com o total de entradas encontradas
+func (c *Client) SearchLogs(ctx context.Context, filter LogFilter, offset, limit int) ([]logger.LogEntry, int64, error) {
+	body, err := json.Marshal(logsQuery(filter, offset, limit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to serialize logs query: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index:             []string{LogsIndex},
+		Body:              bytes.NewReader(body),
+		IgnoreUnavailable: esapi.BoolPtr(true),
+		TrackTotalHits:    true,
+	}
+
+	res, err := req.Do(ctx, c.ES)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search logs: %w", err)
+	}
+	defer closeBody(res.Body)
+
+	if res.StatusCode == 404 {
+		return []logger.LogEntry{}, 0, nil
+	}
+	if res.IsError() {
+		return nil, 0, fmt.Errorf("failed to search logs: %s", res.String())
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source logger.LogEntry `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode logs response: %w", err)
+	}
+
+	entries := make([]logger.LogEntry, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		entries = append(entries, hit.Source)
+	}
+	return entries, result.Hits.Total.Value, nil
+}
+
+// logsQuery monta a busca com os filtros em contexto de filtro (sem score), ordenada por @timestamp
+func logsQuery(filter LogFilter, offset, limit int) map[string]interface{} {
+	filters := make([]interface{}, 0, 5)
+
+	if filter.From != nil || filter.To != nil {
+		timestamp := map[string]interface{}{}
+		if filter.From != nil {
+			timestamp["gte"] = filter.From.UTC().Format(time.RFC3339Nano)
+		}
+		if filter.To != nil {
+			timestamp["lt"] = filter.To.UTC().Format(time.RFC3339Nano)
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"@timestamp": timestamp}})
+	}
+	if len(filter.Levels) > 0 {
+		filters = append(filters, map[string]interface{}{"terms": map[string]interface{}{"level": filter.Levels}})
+	}
+	if filter.RequestID != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"http.request_id": filter.RequestID}})
+	}
+	if filter.UserID != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"user.id": filter.UserID}})
+	}
+	if filter.Path != "" {
+		filters = append(filters, map[string]interface{}{"prefix": map[string]interface{}{"http.path": filter.Path}})
+	}
+
+	return map[string]interface{}{
+		"from": offset,
+		"size": limit,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{"filter": filters},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"@timestamp": map[string]interface{}{"order": "desc"}},
+		},
+	}
+}
+
+// RequestStats é a contagem das requisições registradas nos logs e das que terminaram em erro
+type RequestStats struct {
+	Requests     int64
+	ClientErrors int64
+	ServerErrors int64
+}
+
+// GetRequestStats conta as requisições HTTP registradas nos logs a partir de from e quantas
+// responderam 4xx e 5xx. Os caminhos que o LoggerMiddleware não registra ficam de fora.
+func (c *Client) GetRequestStats(ctx context.Context, from time.Time) (RequestStats, error) {
+	body, err := json.Marshal(requestStatsQuery(from))
+	if err != nil {
+		return RequestStats{}, fmt.Errorf("failed to serialize request stats query: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index:             []string{LogsIndex},
+		Body:              bytes.NewReader(body),
+		IgnoreUnavailable: esapi.BoolPtr(true),
+		TrackTotalHits:    true,
+	}
+
+	res, err := req.Do(ctx, c.ES)
+	if err != nil {
+		return RequestStats{}, fmt.Errorf("failed to get request stats: %w", err)
+	}
+	defer closeBody(res.Body)
+
+	if res.StatusCode == 404 {
+		return RequestStats{}, nil
+	}
+	if res.IsError() {
+		return RequestStats{}, fmt.Errorf("failed to get request stats: %s", res.String())
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+		} `json:"hits"`
+		Aggregations struct {
+			ClientErrors struct {
+				DocCount int64 `json:"doc_count"`
+			} `json:"client_errors"`
+			ServerErrors struct {
+				DocCount int64 `json:"doc_count"`
+			} `json:"server_errors"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return RequestStats{}, fmt.Errorf("failed to decode request stats response: %w", err)
+	}
+
+	return RequestStats{
+		Requests:     result.Hits.Total.Value,
+		ClientErrors: result.Aggregations.ClientErrors.DocCount,
+		ServerErrors: result.Aggregations.ServerErrors.DocCount,
+	}, nil
+}
+
+// requestStatsQuery conta os logs de requisição (os que têm http.status_code) desde from,
+// separando 4xx e 5xx em agregações de filtro
+func requestStatsQuery(from time.Time) map[string]interface{} {
+	statusRange := func(gte, lt int) map[string]interface{} {
+		return map[string]interface{}{
+			"filter": map[string]interface{}{
+				"range": map[string]interface{}{"http.status_code": map[string]interface{}{"gte": gte, "lt": lt}},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"range": map[string]interface{}{"@timestamp": map[string]interface{}{"gte": from.UTC().Format(time.RFC3339Nano)}}},
+					map[string]interface{}{"exists": map[string]interface{}{"field": "http.status_code"}},
+				},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"client_errors": statusRange(400, 500),
+			"server_errors": statusRange(500, 600),
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package elsearch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsQuery(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	query := logsQuery(LogFilter{
+		From:      &from,
+		Levels:    []string{"ERROR"},
+		RequestID: "req-1",
+		UserID:    "42",
+		Path:      "/tickets",
+	}, 50, 25)
+
+	data, err := json.Marshal(query)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"from": 50,
+		"size": 25,
+		"query": {"bool": {"filter": [
+			{"range": {"@timestamp": {"gte": "2025-06-01T00:00:00Z"}}},
+			{"terms": {"level": ["ERROR"]}},
+			{"term": {"http.request_id": "req-1"}},
+			{"term": {"user.id": "42"}},
+			{"prefix": {"http.path": "/tickets"}}
+		]}},
+		"sort": [{"@timestamp": {"order": "desc"}}]
+	}`, string(data))
+}
+
+func TestRequestStatsQuery(t *testing.T) {
+	from := time.Date(2025, 6, 1, 12, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+	data, err := json.Marshal(requestStatsQuery(from))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"size": 0,
+		"query": {"bool": {"filter": [
+			{"range": {"@timestamp": {"gte": "2025-06-01T15:00:00Z"}}},
+			{"exists": {"field": "http.status_code"}}
+		]}},
+		"aggs": {
+			"client_errors": {"filter": {"range": {"http.status_code": {"gte": 400, "lt": 500}}}},
+			"server_errors": {"filter": {"range": {"http.status_code": {"gte": 500, "lt": 600}}}}
+		}
+	}`, string(data))
+}
@@ -0,0 +1,235 @@
+package elsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+// MaxTicketBatch é a quantidade máxima de tickets buscados por SearchTicketsByIDs
+const MaxTicketBatch = 100
+
+// lookupDocument é um documento retornado pela Get API ou um item da resposta da MGet API
+type lookupDocument struct {
+	ID     string          `json:"_id"`
+	Found  bool            `json:"found"`
+	Source json.RawMessage `json:"_source"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// SearchTicketByID busca um ticket pelo ticket_id e retorna todas as informações do ticket.
+// Os tickets gravados pela API usam o ticket_id como _id, então a busca vai direto ao documento pela Get API;
+// a busca por termo em ticket_id só é feita quando não há documento com esse _id ou ele é de outro ticket.
+// Retorna nil quando o ticket não existe ou está fora do escopo.
+func (es *Client) SearchTicketByID(ctx context.Context, ticketID string) (*map[string]interface{}, error) {
+	if _, ok := ScopeFromContext(ctx); !ok {
+		return nil, ErrMissingScope
+	}
+
+	document, err := es.getDocument(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket, ok := lookupTicket(document, ticketID); ok {
+		if !allowsDocument(ctx, ticket) {
+			return nil, nil
+		}
+		return &ticket, nil
+	}
+
+	tickets, err := es.searchTicketsByTerm(ctx, []string{ticketID})
+	if err != nil {
+		return nil, err
+	}
+	if ticket, ok := tickets[ticketID]; ok {
+		return &ticket, nil
+	}
+	return nil, nil
+}
+
+// SearchTicketsByIDs busca vários tickets de uma vez pela MGet API, com a mesma regra de SearchTicketByID
+// para os documentos cujo _id não é o ticket_id. Retorna os tickets encontrados na ordem de ticketIDs,
+// sem repetições, e os IDs não encontrados ou fora do escopo.
+func (es *Client) SearchTicketsByIDs(ctx context.Context, ticketIDs []string) ([]map[string]interface{}, []string, error) {
+	if _, ok := ScopeFromContext(ctx); !ok {
+		return nil, nil, ErrMissingScope
+	}
+
+	ids := uniqueIDs(ticketIDs)
+	if len(ids) > MaxTicketBatch {
+		return nil, nil, fmt.Errorf("at most %d tickets can be fetched at once", MaxTicketBatch)
+	}
+	if len(ids) == 0 {
+		return []map[string]interface{}{}, []string{}, nil
+	}
+
+	documents, err := es.getDocuments(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Documentos fora do escopo ficam de fora, como em SearchTicketByID
+	found := make(map[string]map[string]interface{}, len(ids))
+	var missing []string
+	for i, id := range ids {
+		ticket, ok := lookupTicket(documents[i], id)
+		if !ok {
+			missing = append(missing, id)
+		} else if allowsDocument(ctx, ticket) {
+			found[id] = ticket
+		}
+	}
+
+	if len(missing) > 0 {
+		searched, err := es.searchTicketsByTerm(ctx, missing)
+		if err != nil {
+			return nil, nil, err
+		}
+		for id, ticket := range searched {
+			found[id] = ticket
+		}
+	}
+
+	tickets := make([]map[string]interface{}, 0, len(found))
+	notFound := []string{}
+	for _, id := range ids {
+		if ticket, ok := found[id]; ok {
+			tickets = append(tickets, ticket)
+		} else {
+			notFound = append(notFound, id)
+		}
+	}
+	return tickets, notFound, nil
+}
+
+// getDocument lê o documento com o _id informado pela Get API. O routing é o próprio _id, o padrão
+// do Elasticsearch, e leva a requisição direto ao shard do documento.
+func (es *Client) getDocument(ctx context.Context, id string) (lookupDocument, error) {
+	res, err := doProtected(ctx, es.searchBreaker, func(ctx context.Context) (*esapi.Response, error) {
+		return es.ES.Get(es.config.IndexName, id,
+			es.ES.Get.WithContext(ctx),
+			es.ES.Get.WithRouting(id),
+		)
+	})
+	if err != nil {
+		return lookupDocument{}, fmt.Errorf("error fetching ticket %s: %w", id, err)
+	}
+	defer closeBody(res.Body)
+
+	// A Get API responde 404 para documentos inexistentes, com found=false no corpo
+	if res.StatusCode == http.StatusNotFound {
+		return lookupDocument{ID: id}, nil
+	}
+	if res.IsError() {
+		return lookupDocument{}, fmt.Errorf("error fetching ticket %s: %s", id, res.String())
+	}
+
+	var document lookupDocument
+	if err := json.NewDecoder(res.Body).Decode(&document); err != nil {
+		return lookupDocument{}, fmt.Errorf("error decoding ticket %s: %w", id, err)
+	}
+	return document, nil
+}
+
+// getDocuments lê os documentos com os _ids informados em uma requisição à MGet API.
+// Retorna um documento por id, na mesma ordem.
+func (es *Client) getDocuments(ctx context.Context, ids []string) ([]lookupDocument, error) {
+	docs := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		docs = append(docs, map[string]interface{}{"_id": id, "routing": id})
+	}
+	body, err := json.Marshal(map[string]interface{}{"docs": docs})
+	if err != nil {
+		return nil, fmt.Errorf("error serializing ticket lookup: %v", err)
+	}
+
+	res, err := doProtected(ctx, es.searchBreaker, func(ctx context.Context) (*esapi.Response, error) {
+		return es.ES.Mget(bytes.NewReader(body),
+			es.ES.Mget.WithContext(ctx),
+			es.ES.Mget.WithIndex(es.config.IndexName),
+		)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching tickets: %w", err)
+	}
+	defer closeBody(res.Body)
+
+	if res.IsError() {
+		return nil, fmt.Errorf("error fetching tickets: %s", res.String())
+	}
+
+	var result struct {
+		Docs []lookupDocument `json:"docs"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding tickets: %w", err)
+	}
+	if len(result.Docs) != len(ids) {
+		return nil, fmt.Errorf("ticket lookup returned %d documents for %d ids", len(result.Docs), len(ids))
+	}
+	for _, document := range result.Docs {
+		if len(document.Error) > 0 {
+			return nil, fmt.Errorf("error fetching ticket %s: %s", document.ID, document.Error)
+		}
+	}
+	return result.Docs, nil
+}
+
+// searchTicketsByTerm busca pelo campo ticket_id os tickets cujo documento não usa o ticket_id como _id.
+// Retorna os tickets do escopo indexados pelo ticket_id.
+func (es *Client) searchTicketsByTerm(ctx context.Context, ticketIDs []string) (map[string]map[string]interface{}, error) {
+	esResponse, err := es.search(ctx, map[string]interface{}{
+		"query": map[string]interface{}{
+			"terms": map[string]interface{}{
+				"ticket_id": ticketIDs,
+			},
+		},
+		"size": len(ticketIDs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make(map[string]map[string]interface{}, len(ticketIDs))
+	for _, ticket := range es.decodeHits(ctx, esResponse) {
+		id := fmt.Sprint(ticket["ticket_id"])
+		if _, exists := tickets[id]; !exists {
+			tickets[id] = ticket
+		}
+	}
+	return tickets, nil
+}
+
+// lookupTicket decodifica o documento e confirma que ele é do ticket procurado
+func lookupTicket(document lookupDocument, ticketID string) (map[string]interface{}, bool) {
+	if !document.Found || len(document.Source) == 0 {
+		return nil, false
+	}
+
+	var ticket map[string]interface{}
+	if err := json.Unmarshal(document.Source, &ticket); err != nil {
+		return nil, false
+	}
+	if fmt.Sprint(ticket["ticket_id"]) != ticketID {
+		return nil, false
+	}
+	return ticket, true
+}
+
+// uniqueIDs remove os IDs vazios e repetidos, mantendo a ordem
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
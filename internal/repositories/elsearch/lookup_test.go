@@ -0,0 +1,166 @@
+package elsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLookupHandler responde a Get, MGet e busca como um índice em que TKT-001 e TKT-002 usam o ticket_id
+// como _id, TKT-003 foi gravado com outro _id e TKT-002 é de outra empresa
+func fakeLookupHandler(t *testing.T, searches *[]map[string]interface{}) http.HandlerFunc {
+	documents := map[string]string{
+		"TKT-001": `{"ticket_id": "TKT-001", "company": {"id": "10"}}`,
+		"TKT-002": `{"ticket_id": "TKT-002", "company": {"id": "20"}}`,
+	}
+	lookup := func(id string) map[string]interface{} {
+		document := map[string]interface{}{"_index": "support_tickets", "_id": id, "found": false}
+		if source, ok := documents[id]; ok {
+			document["found"] = true
+			document["_source"] = json.RawMessage(source)
+		}
+		return document
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/support_tickets/_doc/"):
+			id := strings.TrimPrefix(r.URL.Path, "/support_tickets/_doc/")
+			assert.Equal(t, id, r.URL.Query().Get("routing"))
+			document := lookup(id)
+			if !document["found"].(bool) {
+				w.WriteHeader(http.StatusNotFound)
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(document))
+
+		case r.URL.Path == "/support_tickets/_mget":
+			var body struct {
+				Docs []struct {
+					ID      string `json:"_id"`
+					Routing string `json:"routing"`
+				} `json:"docs"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			docs := make([]map[string]interface{}, 0, len(body.Docs))
+			for _, doc := range body.Docs {
+				assert.Equal(t, doc.ID, doc.Routing)
+				docs = append(docs, lookup(doc.ID))
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"docs": docs}))
+
+		case r.URL.Path == "/support_tickets/_search":
+			raw, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(raw, &body))
+			*searches = append(*searches, body)
+			_, _ = w.Write([]byte(`{"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [
+				{"_id": "auto-1", "_source": {"ticket_id": "TKT-003", "company": {"id": "10"}}}
+			]}}`))
+
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+}
+
+func TestSearchTicketByID(t *testing.T) {
+	tests := []struct {
+		name           string
+		scope          Scope
+		ticketID       string
+		expectedTicket string
+		expectSearch   bool
+	}{
+		{
+			name:           "Success - Document keyed by ticket_id is read with the Get API",
+			scope:          CompanyScope("10"),
+			ticketID:       "TKT-001",
+			expectedTicket: "TKT-001",
+		},
+		{
+			name:     "Success - Ticket of another company is not returned",
+			scope:    CompanyScope("10"),
+			ticketID: "TKT-002",
+		},
+		{
+			name:           "Success - Falls back to the term query when the _id differs",
+			scope:          CompanyScope("10"),
+			ticketID:       "TKT-003",
+			expectedTicket: "TKT-003",
+			expectSearch:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var searches []map[string]interface{}
+			client := newTestClient(t, fakeLookupHandler(t, &searches))
+
+			ticket, err := client.SearchTicketByID(WithScope(context.Background(), tt.scope), tt.ticketID)
+			require.NoError(t, err)
+
+			if tt.expectedTicket == "" {
+				assert.Nil(t, ticket)
+			} else {
+				require.NotNil(t, ticket)
+				assert.Equal(t, tt.expectedTicket, (*ticket)["ticket_id"])
+			}
+			assert.Equal(t, tt.expectSearch, len(searches) > 0)
+		})
+	}
+}
+
+func TestSearchTicketByID_RequiresScope(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request is sent without a scope")
+	})
+
+	_, err := client.SearchTicketByID(context.Background(), "TKT-001")
+	assert.ErrorIs(t, err, ErrMissingScope)
+}
+
+func TestSearchTicketsByIDs(t *testing.T) {
+	var searches []map[string]interface{}
+	client := newTestClient(t, fakeLookupHandler(t, &searches))
+
+	ctx := WithScope(context.Background(), CompanyScope("10"))
+	tickets, notFound, err := client.SearchTicketsByIDs(ctx, []string{"TKT-003", "TKT-001", "TKT-002", "TKT-404", "TKT-001", ""})
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		ids = append(ids, ticket["ticket_id"].(string))
+	}
+	assert.Equal(t, []string{"TKT-003", "TKT-001"}, ids)
+	assert.Equal(t, []string{"TKT-002", "TKT-404"}, notFound)
+
+	// Só os tickets sem documento com o mesmo _id vão para a busca por termo, que recebe o escopo
+	require.Len(t, searches, 1)
+	boolQuery := searches[0]["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"terms": map[string]interface{}{"ticket_id": []interface{}{"TKT-003", "TKT-404"}}},
+	}, boolQuery["must"])
+}
+
+func TestSearchTicketsByIDs_TooMany(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request is sent for an oversized batch")
+	})
+
+	ids := make([]string, MaxTicketBatch+1)
+	for i := range ids {
+		ids[i] = strings.Repeat("x", i+1)
+	}
+	_, _, err := client.SearchTicketsByIDs(WithScope(context.Background(), UnrestrictedScope()), ids)
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package elsearch
+
+import (
+	"context"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/pkg/logger"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// defaultSlowQueryThreshold é a duração a partir da qual uma busca é registrada no log
+	defaultSlowQueryThreshold = time.Second
+	// maxLoggedQueryBytes limita o tamanho da query gravada no log de buscas lentas
+	maxLoggedQueryBytes = 8192
+)
+
+var (
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "elasticsearch_query_duration_seconds",
+		Help:    "Elasticsearch search latency by index and outcome.",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"index", "outcome"})
+
+	queryHits = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "elasticsearch_query_hits",
+		Help:    "Total hits matched by Elasticsearch searches, by index.",
+		Buckets: []float64{0, 1, 10, 50, 100, 500, 1000, 10000, 100000},
+	}, []string{"index"})
+
+	slowQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "elasticsearch_slow_queries_total",
+		Help: "Elasticsearch searches slower than ELASTICSEARCH_SLOW_QUERY_MS, by index.",
+	}, []string{"index"})
+)
+
+// QueryCollectors são as métricas das buscas no Elasticsearch, registradas no endpoint do Prometheus
+func QueryCollectors() []prometheus.Collector {
+	return []prometheus.Collector{queryDuration, queryHits, slowQueries}
+}
+
+// QueryLogger recebe as buscas lentas; é satisfeito pelo logger da aplicação
+type QueryLogger interface {
+	WithContext(level logger.LogLevel, message string, ctx logger.LogContext)
+}
+
+// SetQueryLogger define o logger das buscas lentas. O cliente é criado antes do logger, que grava
+// no próprio Elasticsearch, então o logger é informado depois.
+func (es *Client) SetQueryLogger(queryLogger QueryLogger) {
+	es.queryLogger = queryLogger
+}
+
+// observeQuery registra a latência e os hits da busca e, quando ela passa do limite configurado,
+// grava no log a duração, os hits e a query serializada
+func (es *Client) observeQuery(ctx context.Context, query []byte, duration time.Duration, esResponse *dto.ESResponse, err error) {
+	index := es.config.IndexName
+
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	queryDuration.WithLabelValues(index, outcome).Observe(duration.Seconds())
+	if esResponse != nil {
+		queryHits.WithLabelValues(index).Observe(float64(esResponse.Hits.Total.Value))
+	}
+
+	threshold := es.config.SlowQueryThreshold
+	if threshold <= 0 || duration < threshold {
+		return
+	}
+	slowQueries.WithLabelValues(index).Inc()
+
+	if es.queryLogger == nil {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"index":           index,
+		"outcome":         outcome,
+		"threshold_ms":    threshold.Milliseconds(),
+		"query":           truncateQuery(query),
+		"query_bytes":     len(query),
+		"query_truncated": len(query) > maxLoggedQueryBytes,
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	if esResponse != nil {
+		fields["took_ms"] = esResponse.Took
+		fields["timed_out"] = esResponse.TimedOut
+		fields["hits"] = len(esResponse.Hits.Hits)
+		fields["total_hits"] = esResponse.Hits.Total.Value
+	}
+
+	logContext := logger.LogContext{
+		Performance: &logger.PerformanceContext{
+			Duration:   duration,
+			DurationMs: float64(duration.Microseconds()) / 1000,
+		},
+		Fields: fields,
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		logContext.Trace = &logger.TraceContext{
+			TraceID: span.TraceID().String(),
+			SpanID:  span.SpanID().String(),
+			Sampled: span.IsSampled(),
+		}
+	}
+
+	es.queryLogger.WithContext(logger.LevelWarn, "Slow Elasticsearch query", logContext)
+}
+
+// truncateQuery limita a query gravada no log a maxLoggedQueryBytes
+func truncateQuery(query []byte) string {
+	if len(query) > maxLoggedQueryBytes {
+		return string(query[:maxLoggedQueryBytes])
+	}
+	return string(query)
+}
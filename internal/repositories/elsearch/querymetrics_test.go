@@ -0,0 +1,108 @@
+package elsearch
+
+import (
+	"context"
+	"net/http"
+	"orderstreamrest/pkg/logger"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingQueryLogger guarda as entradas recebidas nos testes
+type recordingQueryLogger struct {
+	messages []string
+	contexts []logger.LogContext
+}
+
+func (l *recordingQueryLogger) WithContext(level logger.LogLevel, message string, ctx logger.LogContext) {
+	l.messages = append(l.messages, string(level)+" "+message)
+	l.contexts = append(l.contexts, ctx)
+}
+
+func TestSearch_SlowQueryLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		index     string
+		threshold time.Duration
+		expectLog bool
+	}{
+		{name: "slower than the threshold", index: "slow_query_logged", threshold: time.Nanosecond, expectLog: true},
+		{name: "faster than the threshold", index: "slow_query_fast", threshold: time.Hour},
+		{name: "disabled", index: "slow_query_disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedBody map[string]interface{}
+			client := newTestClient(t, fakeSearchHandler(t, &receivedBody))
+			client.config.IndexName = tt.index
+			client.config.SlowQueryThreshold = tt.threshold
+
+			queryLogger := &recordingQueryLogger{}
+			client.SetQueryLogger(queryLogger)
+
+			series := testutil.CollectAndCount(queryDuration)
+
+			ctx := WithScope(context.Background(), UnrestrictedScope())
+			_, err := client.search(ctx, map[string]interface{}{
+				"query": map[string]interface{}{"match": map[string]interface{}{"title": "boleto"}},
+			})
+			require.NoError(t, err)
+
+			// Cada caso usa um índice próprio, então a busca cria uma série nova
+			assert.Equal(t, series+1, testutil.CollectAndCount(queryDuration))
+
+			if !tt.expectLog {
+				assert.Empty(t, queryLogger.messages)
+				assert.Zero(t, testutil.ToFloat64(slowQueries.WithLabelValues(tt.index)))
+				return
+			}
+
+			require.Equal(t, []string{"WARN Slow Elasticsearch query"}, queryLogger.messages)
+			assert.Equal(t, 1.0, testutil.ToFloat64(slowQueries.WithLabelValues(tt.index)))
+
+			logged := queryLogger.contexts[0]
+			require.NotNil(t, logged.Performance)
+			assert.Positive(t, logged.Performance.Duration)
+			assert.Equal(t, tt.index, logged.Fields["index"])
+			assert.Equal(t, "success", logged.Fields["outcome"])
+			assert.Equal(t, 2, logged.Fields["hits"])
+			assert.Equal(t, int64(2), logged.Fields["total_hits"])
+			assert.Contains(t, logged.Fields["query"], "boleto")
+		})
+	}
+}
+
+func TestSearch_SlowQueryLogOnError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "parsing_exception"}`))
+	})
+	client.config.IndexName = "slow_query_error"
+	client.config.SlowQueryThreshold = time.Nanosecond
+
+	queryLogger := &recordingQueryLogger{}
+	client.SetQueryLogger(queryLogger)
+
+	_, err := client.search(WithScope(context.Background(), UnrestrictedScope()), map[string]interface{}{})
+	require.Error(t, err)
+
+	require.Len(t, queryLogger.contexts, 1)
+	logged := queryLogger.contexts[0]
+	assert.Equal(t, "error", logged.Fields["outcome"])
+	assert.Contains(t, logged.Fields["error"], "parsing_exception")
+	assert.NotContains(t, logged.Fields, "total_hits")
+}
+
+func TestTruncateQuery(t *testing.T) {
+	assert.Equal(t, `{"size":10}`, truncateQuery([]byte(`{"size":10}`)))
+
+	long := []byte(`{"query":"` + strings.Repeat("a", maxLoggedQueryBytes) + `"}`)
+	assert.Len(t, truncateQuery(long), maxLoggedQueryBytes)
+}
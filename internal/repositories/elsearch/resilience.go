@@ -0,0 +1,49 @@
+package elsearch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/resilience"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+// Nomes dos circuitos do Elasticsearch, usados também nas variáveis BREAKER_<NAME>_*
+const (
+	searchBreakerName = "es_search"
+	bulkBreakerName   = "es_bulk"
+)
+
+// newBreakers cria os circuitos das buscas e da Bulk API. As buscas são repetidas uma vez por padrão;
+// o bulk não, porque o cliente já repete 502/503/504/429 e um bulk grande pesa no cluster.
+func newBreakers() (search, bulk *resilience.Breaker) {
+	return resilience.NewBreaker(resilience.LoadPolicy(searchBreakerName, 1)),
+		resilience.NewBreaker(resilience.LoadPolicy(bulkBreakerName, 0))
+}
+
+// Breakers retorna os circuitos do cliente por nome, para o healthcheck
+func (es *Client) Breakers() map[string]*resilience.Breaker {
+	return map[string]*resilience.Breaker{
+		searchBreakerName: es.searchBreaker,
+		bulkBreakerName:   es.bulkBreaker,
+	}
+}
+
+// doProtected executa a requisição pelo circuito. Falhas de conexão e respostas 5xx contam como falha
+// da dependência e viram ErrUnavailable; as demais respostas voltam para o chamador tratar.
+func doProtected(ctx context.Context, breaker *resilience.Breaker, do func(ctx context.Context) (*esapi.Response, error)) (*esapi.Response, error) {
+	return resilience.Execute(ctx, breaker, func(ctx context.Context) (*esapi.Response, error) {
+		res, err := do(ctx)
+		if err != nil {
+			return nil, apperror.Wrap(apperror.ErrUnavailable, "elasticsearch request failed", err)
+		}
+		if res.StatusCode >= 500 {
+			body, _ := io.ReadAll(res.Body)
+			closeBody(res.Body)
+			return nil, apperror.Wrap(apperror.ErrUnavailable, "elasticsearch is unavailable", fmt.Errorf("%s - %s", res.Status(), string(body)))
+		}
+		return res, nil
+	})
+}
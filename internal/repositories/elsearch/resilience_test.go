@@ -0,0 +1,35 @@
+package elsearch
+
+import (
+	"context"
+	"net/http"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/resilience"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchCircuitBreaker(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error": "search_phase_execution_exception"}`))
+	})
+	client.searchBreaker = resilience.NewBreaker(resilience.Policy{Name: searchBreakerName, Failures: 1, OpenTimeout: time.Minute})
+	ctx := WithScope(context.Background(), UnrestrictedScope())
+
+	_, err := client.SearchTicketsBySomeWord(ctx, dto.SearchParams{Query: "boleto"})
+	assert.ErrorIs(t, err, apperror.ErrUnavailable)
+	assert.Equal(t, 1, requests)
+
+	// O circuito abriu: a busca falha na hora, com a espera para o Retry-After
+	_, err = client.SearchTicketsBySomeWord(ctx, dto.SearchParams{Query: "boleto"})
+	assert.ErrorIs(t, err, apperror.ErrUnavailable)
+	assert.Positive(t, apperror.RetryAfter(err))
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,110 @@
+package elsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrMissingScope is returned when a search runs without a scope in its context
+var ErrMissingScope = errors.New("search scope missing from context")
+
+// Scope restricts the documents a search is allowed to return
+type Scope struct {
+	// Unrestricted allows documents from every company (ADMIN users)
+	Unrestricted bool
+	// CompanyIDs lists the companies whose documents may be returned
+	CompanyIDs []string
+}
+
+type scopeKey struct{}
+
+// UnrestrictedScope returns a scope that allows every document
+func UnrestrictedScope() Scope {
+	return Scope{Unrestricted: true}
+}
+
+// CompanyScope returns a scope limited to the given companies
+func CompanyScope(companyIDs ...string) Scope {
+	return Scope{CompanyIDs: companyIDs}
+}
+
+// WithScope returns a copy of ctx carrying the search scope
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFromContext returns the search scope carried by ctx
+func ScopeFromContext(ctx context.Context) (Scope, bool) {
+	scope, ok := ctx.Value(scopeKey{}).(Scope)
+	return scope, ok
+}
+
+// applyScope wraps the query of a search body with the company filter of the scope in ctx.
+// Searches without a scope are refused, so a forgotten filter never returns other tenants' documents.
+func applyScope(ctx context.Context, body map[string]interface{}) (map[string]interface{}, error) {
+	scope, ok := ScopeFromContext(ctx)
+	if !ok {
+		return nil, ErrMissingScope
+	}
+
+	if scope.Unrestricted {
+		return body, nil
+	}
+
+	scoped := make(map[string]interface{}, len(body)+1)
+	for k, v := range body {
+		scoped[k] = v
+	}
+
+	query, ok := body["query"]
+	if !ok {
+		query = map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+
+	var filter interface{}
+	if len(scope.CompanyIDs) == 0 {
+		filter = map[string]interface{}{"match_none": map[string]interface{}{}}
+	} else {
+		filter = map[string]interface{}{
+			"terms": map[string]interface{}{
+				"company.id": scope.CompanyIDs,
+			},
+		}
+	}
+
+	scoped["query"] = map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   []interface{}{query},
+			"filter": []interface{}{filter},
+		},
+	}
+
+	return scoped, nil
+}
+
+// allowsDocument checks a returned document against the scope in ctx
+func allowsDocument(ctx context.Context, doc map[string]interface{}) bool {
+	scope, ok := ScopeFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	if scope.Unrestricted {
+		return true
+	}
+
+	company, ok := doc["company"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	companyID := fmt.Sprint(company["id"])
+	for _, id := range scope.CompanyIDs {
+		if id == companyID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,226 @@
+package elsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyScope(t *testing.T) {
+	termQuery := map[string]interface{}{
+		"term": map[string]interface{}{"ticket_id": "TKT-001"},
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		body        map[string]interface{}
+		expectedErr error
+		validate    func(t *testing.T, original, scoped map[string]interface{})
+	}{
+		{
+			name:        "Error - Search without scope is refused",
+			ctx:         context.Background(),
+			body:        map[string]interface{}{"query": termQuery},
+			expectedErr: ErrMissingScope,
+		},
+		{
+			name: "Success - Unrestricted scope keeps the query",
+			ctx:  WithScope(context.Background(), UnrestrictedScope()),
+			body: map[string]interface{}{"query": termQuery, "size": 1},
+			validate: func(t *testing.T, original, scoped map[string]interface{}) {
+				assert.Equal(t, original, scoped)
+			},
+		},
+		{
+			name: "Success - Company scope wraps the query with a company filter",
+			ctx:  WithScope(context.Background(), CompanyScope("10", "20")),
+			body: map[string]interface{}{"query": termQuery, "size": 1},
+			validate: func(t *testing.T, original, scoped map[string]interface{}) {
+				assert.Equal(t, 1, scoped["size"])
+
+				boolQuery := scoped["query"].(map[string]interface{})["bool"].(map[string]interface{})
+				assert.Equal(t, []interface{}{termQuery}, boolQuery["must"])
+				assert.Equal(t, []interface{}{
+					map[string]interface{}{
+						"terms": map[string]interface{}{"company.id": []string{"10", "20"}},
+					},
+				}, boolQuery["filter"])
+
+				// A query original não pode ser alterada
+				assert.Equal(t, termQuery, original["query"])
+			},
+		},
+		{
+			name: "Success - Query-less search is scoped with match_all",
+			ctx:  WithScope(context.Background(), CompanyScope("10")),
+			body: map[string]interface{}{"size": 10},
+			validate: func(t *testing.T, original, scoped map[string]interface{}) {
+				boolQuery := scoped["query"].(map[string]interface{})["bool"].(map[string]interface{})
+				assert.Equal(t, []interface{}{
+					map[string]interface{}{"match_all": map[string]interface{}{}},
+				}, boolQuery["must"])
+			},
+		},
+		{
+			name: "Success - Scope without companies matches nothing",
+			ctx:  WithScope(context.Background(), CompanyScope()),
+			body: map[string]interface{}{"query": termQuery},
+			validate: func(t *testing.T, original, scoped map[string]interface{}) {
+				boolQuery := scoped["query"].(map[string]interface{})["bool"].(map[string]interface{})
+				assert.Equal(t, []interface{}{
+					map[string]interface{}{"match_none": map[string]interface{}{}},
+				}, boolQuery["filter"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scoped, err := applyScope(tt.ctx, tt.body)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, scoped)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.validate != nil {
+				tt.validate(t, tt.body, scoped)
+			}
+		})
+	}
+}
+
+// newTestClient cria um cliente apontando para um Elasticsearch falso
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{server.URL},
+	})
+	require.NoError(t, err)
+
+	return &Client{
+		ES:     es,
+		config: &Config{IndexName: "support_tickets"},
+	}
+}
+
+// fakeSearchHandler ignora qualquer filtro e sempre devolve tickets de duas empresas
+func fakeSearchHandler(t *testing.T, receivedBody *map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, receivedBody))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{
+			"hits": {
+				"total": {"value": 2, "relation": "eq"},
+				"hits": [
+					{"_id": "1", "_source": {"ticket_id": "TKT-001", "company": {"id": "10"}}},
+					{"_id": "2", "_source": {"ticket_id": "TKT-002", "company": {"id": "20"}}}
+				]
+			}
+		}`))
+	}
+}
+
+func TestSearchTicketsBySomeWord_Scope(t *testing.T) {
+	tests := []struct {
+		name            string
+		scope           *Scope
+		expectedErr     error
+		expectedTickets []string
+		expectFilter    bool
+	}{
+		{
+			name:        "Error - Missing scope never reaches Elasticsearch",
+			scope:       nil,
+			expectedErr: ErrMissingScope,
+		},
+		{
+			name:            "Success - Documents from other companies are never returned",
+			scope:           &Scope{CompanyIDs: []string{"10"}},
+			expectedTickets: []string{"TKT-001"},
+			expectFilter:    true,
+		},
+		{
+			name:            "Success - Unrestricted scope returns every document",
+			scope:           &Scope{Unrestricted: true},
+			expectedTickets: []string{"TKT-001", "TKT-002"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedBody map[string]interface{}
+			client := newTestClient(t, fakeSearchHandler(t, &receivedBody))
+
+			ctx := context.Background()
+			if tt.scope != nil {
+				ctx = WithScope(ctx, *tt.scope)
+			}
+
+			result, err := client.SearchTicketsBySomeWord(ctx, dto.SearchParams{Query: "internet"})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, receivedBody)
+				return
+			}
+
+			require.NoError(t, err)
+
+			tickets := result.Tickets
+			ids := make([]string, 0, len(tickets))
+			for _, ticket := range tickets {
+				ids = append(ids, ticket["ticket_id"].(string))
+			}
+			assert.Equal(t, tt.expectedTickets, ids)
+
+			boolQuery, _ := receivedBody["query"].(map[string]interface{})["bool"].(map[string]interface{})
+			_, hasFilter := boolQuery["filter"]
+			assert.Equal(t, tt.expectFilter, hasFilter)
+		})
+	}
+}
+
+func TestSearchTicketByID_OutsideScope(t *testing.T) {
+	var receivedBody map[string]interface{}
+	search := fakeSearchHandler(t, &receivedBody)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Sem documento com o ticket_id como _id, a busca cai na query por termo
+		if strings.Contains(r.URL.Path, "/_doc/") {
+			w.Header().Set("X-Elastic-Product", "Elasticsearch")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"found": false}`))
+			return
+		}
+		search(w, r)
+	})
+
+	ctx := WithScope(context.Background(), CompanyScope("20"))
+
+	ticket, err := client.SearchTicketByID(ctx, "TKT-001")
+	require.NoError(t, err)
+
+	// O hit de TKT-001 é de outra empresa e deve ser descartado; o de TKT-002 não é o ticket pedido
+	assert.Nil(t, ticket)
+	assert.NotNil(t, receivedBody["query"].(map[string]interface{})["bool"])
+}
@@ -0,0 +1,260 @@
+package elsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// synonymsMetaKey é a chave de mappings._meta que registra o conjunto de sinônimos ativo
+const synonymsMetaKey = "synonyms"
+
+// SynonymSet descreve o conjunto de sinônimos ativo no índice de tickets
+type SynonymSet struct {
+	ID          string    `json:"id"`
+	Source      string    `json:"source"`
+	Format      string    `json:"format"`
+	Rules       int       `json:"rules"`
+	Checksum    string    `json:"checksum"`
+	ActivatedBy *int      `json:"activated_by,omitempty"`
+	ActivatedAt time.Time `json:"activated_at"`
+}
+
+// UpdateSynonyms troca as regras de sinônimos do analisador de busca de tickets em todos os índices do alias.
+// O Elasticsearch só aceita alterar a análise com o índice fechado, então ele é fechado, atualizado e
+// reaberto; enquanto isso as buscas falham. O conjunto é registrado em mappings._meta para consulta e para o reindex.
+func (c *Client) UpdateSynonyms(ctx context.Context, set SynonymSet, synonyms []string) error {
+	definition, err := LookupIndex(TicketsIndex)
+	if err != nil {
+		return err
+	}
+
+	body, err := definition.bodyWithSynonyms(synonyms)
+	if err != nil {
+		return err
+	}
+	settings, _ := body["settings"].(map[string]interface{})
+	data, err := json.Marshal(map[string]interface{}{"analysis": settings["analysis"]})
+	if err != nil {
+		return fmt.Errorf("failed to serialize synonyms settings: %w", err)
+	}
+
+	indices, err := c.resolveAlias(ctx, TicketsIndex)
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		return fmt.Errorf("index %s does not exist", TicketsIndex)
+	}
+
+	for _, index := range indices {
+		if err := c.updateAnalysis(ctx, index, data); err != nil {
+			return err
+		}
+		if err := c.putSynonymsMeta(ctx, index, set); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateAnalysis aplica as configurações de análise com o índice fechado e sempre tenta reabri-lo
+func (c *Client) updateAnalysis(ctx context.Context, index string, data []byte) (err error) {
+	res, err := c.ES.Indices.Close([]string{index}, c.ES.Indices.Close.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to close index %s: %w", index, err)
+	}
+	closeBody(res.Body)
+	if res.IsError() {
+		return fmt.Errorf("failed to close index %s: %s", index, res.String())
+	}
+
+	// Reabre mesmo quando a atualização falha ou o cliente desiste, para o índice não ficar fora do ar
+	defer func() {
+		res, openErr := c.ES.Indices.Open([]string{index},
+			c.ES.Indices.Open.WithContext(context.WithoutCancel(ctx)),
+			c.ES.Indices.Open.WithWaitForActiveShards("1"),
+		)
+		if openErr == nil {
+			closeBody(res.Body)
+			if res.IsError() {
+				openErr = fmt.Errorf("%s", res.String())
+			}
+		}
+		if openErr != nil && err == nil {
+			err = fmt.Errorf("failed to reopen index %s: %w", index, openErr)
+		}
+	}()
+
+	res, err = c.ES.Indices.PutSettings(bytes.NewReader(data),
+		c.ES.Indices.PutSettings.WithContext(ctx),
+		c.ES.Indices.PutSettings.WithIndex(index),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update synonyms of %s: %w", index, err)
+	}
+	defer closeBody(res.Body)
+	if res.IsError() {
+		return fmt.Errorf("failed to update synonyms of %s: %s", index, res.String())
+	}
+
+	return nil
+}
+
+// putSynonymsMeta registra o conjunto em mappings._meta preservando as demais chaves (ex.: version),
+// já que o Elasticsearch substitui o _meta inteiro
+func (c *Client) putSynonymsMeta(ctx context.Context, index string, set SynonymSet) error {
+	meta, err := c.mappingMeta(ctx, index)
+	if err != nil {
+		return err
+	}
+	meta[synonymsMetaKey] = set
+
+	data, err := json.Marshal(map[string]interface{}{"_meta": meta})
+	if err != nil {
+		return fmt.Errorf("failed to serialize mapping meta: %w", err)
+	}
+
+	res, err := c.ES.Indices.PutMapping([]string{index}, bytes.NewReader(data), c.ES.Indices.PutMapping.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to record synonym set on %s: %w", index, err)
+	}
+	defer closeBody(res.Body)
+	if res.IsError() {
+		return fmt.Errorf("failed to record synonym set on %s: %s", index, res.String())
+	}
+	return nil
+}
+
+// mappingMeta retorna o mappings._meta do índice
+func (c *Client) mappingMeta(ctx context.Context, index string) (map[string]interface{}, error) {
+	res, err := c.ES.Indices.GetMapping(
+		c.ES.Indices.GetMapping.WithContext(ctx),
+		c.ES.Indices.GetMapping.WithIndex(index),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mapping of %s: %w", index, err)
+	}
+	defer closeBody(res.Body)
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to get mapping of %s: %s", index, res.String())
+	}
+
+	var indices map[string]struct {
+		Mappings struct {
+			Meta map[string]interface{} `json:"_meta"`
+		} `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return nil, fmt.Errorf("failed to decode mapping of %s: %w", index, err)
+	}
+
+	meta := indices[index].Mappings.Meta
+	if meta == nil {
+		meta = map[string]interface{}{}
+	}
+	return meta, nil
+}
+
+// ActiveSynonyms retorna o conjunto de sinônimos enviado por último e as suas regras.
+// Retorna nil quando o índice usa os sinônimos padrão do mapping ou os de ELASTICSEARCH_SYNONYMS_FILE.
+func (c *Client) ActiveSynonyms(ctx context.Context) (*SynonymSet, []string, error) {
+	indices, err := c.resolveAlias(ctx, TicketsIndex)
+	if err != nil || len(indices) == 0 {
+		return nil, nil, err
+	}
+	// Durante uma troca de alias vale o conjunto ativado por último
+	var active *SynonymSet
+	var activeIndex string
+	for _, index := range indices {
+		meta, err := c.mappingMeta(ctx, index)
+		if err != nil {
+			return nil, nil, err
+		}
+		raw, ok := meta[synonymsMetaKey]
+		if !ok {
+			continue
+		}
+
+		var set SynonymSet
+		if err := remarshal(raw, &set); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode synonym set of %s: %w", index, err)
+		}
+		if active == nil || set.ActivatedAt.After(active.ActivatedAt) {
+			active, activeIndex = &set, index
+		}
+	}
+	if active == nil {
+		return nil, nil, nil
+	}
+
+	synonyms, err := c.indexSynonyms(ctx, activeIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	return active, synonyms, nil
+}
+
+// indexSynonyms lê as regras do filtro de sinônimos nas configurações do índice
+func (c *Client) indexSynonyms(ctx context.Context, index string) ([]string, error) {
+	res, err := c.ES.Indices.GetSettings(
+		c.ES.Indices.GetSettings.WithContext(ctx),
+		c.ES.Indices.GetSettings.WithIndex(index),
+		c.ES.Indices.GetSettings.WithName("index.analysis.filter."+synonymsFilter+".synonyms"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings of %s: %w", index, err)
+	}
+	defer closeBody(res.Body)
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to get settings of %s: %s", index, res.String())
+	}
+
+	var indices map[string]struct {
+		Settings struct {
+			Index struct {
+				Analysis struct {
+					Filter map[string]struct {
+						Synonyms []string `json:"synonyms"`
+					} `json:"filter"`
+				} `json:"analysis"`
+			} `json:"index"`
+		} `json:"settings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return nil, fmt.Errorf("failed to decode settings of %s: %w", index, err)
+	}
+
+	return indices[index].Settings.Index.Analysis.Filter[synonymsFilter].Synonyms, nil
+}
+
+// carrySynonyms leva para o corpo do novo índice o conjunto de sinônimos ativo no alias, quando houver
+func (c *Client) carrySynonyms(ctx context.Context, definition IndexDefinition, body map[string]interface{}) (map[string]interface{}, error) {
+	set, synonyms, err := c.ActiveSynonyms(ctx)
+	if err != nil || set == nil {
+		return body, err
+	}
+
+	carried, err := definition.bodyWithSynonyms(synonyms)
+	if err != nil {
+		return nil, err
+	}
+	if mappings, ok := carried["mappings"].(map[string]interface{}); ok {
+		if meta, ok := mappings["_meta"].(map[string]interface{}); ok {
+			meta[synonymsMetaKey] = set
+		}
+	}
+	return carried, nil
+}
+
+// remarshal converte um valor decodificado genericamente para o tipo de destino
+func remarshal(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,127 @@
+package elsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// maxTicketTags é a quantidade máxima de grafias de tag distintas lidas do índice de tickets
+const maxTicketTags = 10000
+
+// renameTagsScript troca cada tag pela grafia de params.tags e remove as repetidas, mantendo a ordem.
+// Documentos com uma única tag em texto (sem lista) também são tratados.
+const renameTagsScript = `
+if (ctx._source.tags instanceof List) {
+  def seen = new HashSet();
+  def renamed = new ArrayList();
+  for (def tag : ctx._source.tags) {
+    def value = params.tags.containsKey(tag) ? params.tags.get(tag) : tag;
+    if (seen.add(value)) {
+      renamed.add(value);
+    }
+  }
+  ctx._source.tags = renamed;
+} else if (ctx._source.tags != null && params.tags.containsKey(ctx._source.tags)) {
+  ctx._source.tags = params.tags.get(ctx._source.tags);
+}`
+
+// TicketTag é uma grafia de tag gravada nos tickets, com a quantidade de tickets que a usam
+type TicketTag struct {
+	Name    string
+	Tickets int64
+}
+
+// ListTicketTags retorna as grafias de tag distintas do índice de tickets (no máximo maxTicketTags),
+// da mais usada para a menos usada
+func (es *Client) ListTicketTags(ctx context.Context) ([]TicketTag, error) {
+	esResponse, err := es.search(ctx, map[string]interface{}{
+		"size": 0,
+		"aggs": map[string]interface{}{
+			"tags": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "tags",
+					"size":  maxTicketTags,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ticket tags: %w", err)
+	}
+
+	buckets := esResponse.Aggregations["tags"].Buckets
+	tags := make([]TicketTag, 0, len(buckets))
+	for _, bucket := range buckets {
+		tags = append(tags, TicketTag{Name: fmt.Sprint(bucket.Key), Tickets: bucket.DocCount})
+	}
+	return tags, nil
+}
+
+// RenameTicketTags troca, nos tickets, cada grafia de tag (chave de renames) pelo nome canônico
+// (valor), removendo as tags que ficarem repetidas. Retorna a quantidade de tickets alterados.
+// Tickets alterados por outra requisição durante a execução são pulados e corrigidos na próxima.
+func (es *Client) RenameTicketTags(ctx context.Context, renames map[string]string) (int64, error) {
+	if len(renames) == 0 {
+		return 0, nil
+	}
+
+	body, err := json.Marshal(renameTagsQuery(renames))
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize tag rename request: %w", err)
+	}
+
+	res, err := es.ES.UpdateByQuery(
+		[]string{es.config.IndexName},
+		es.ES.UpdateByQuery.WithContext(ctx),
+		es.ES.UpdateByQuery.WithBody(bytes.NewReader(body)),
+		es.ES.UpdateByQuery.WithConflicts("proceed"),
+		es.ES.UpdateByQuery.WithRefresh(true),
+		es.ES.UpdateByQuery.WithWaitForCompletion(true),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rename ticket tags: %w", err)
+	}
+	defer closeBody(res.Body)
+
+	if res.IsError() {
+		return 0, fmt.Errorf("failed to rename ticket tags: %s", res.String())
+	}
+
+	var result struct {
+		Updated  int64             `json:"updated"`
+		Failures []json.RawMessage `json:"failures"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode tag rename response: %w", err)
+	}
+	if len(result.Failures) > 0 {
+		return result.Updated, fmt.Errorf("tag rename had %d failures: %s", len(result.Failures), result.Failures[0])
+	}
+
+	return result.Updated, nil
+}
+
+// renameTagsQuery seleciona apenas os tickets com alguma das grafias a trocar
+func renameTagsQuery(renames map[string]string) map[string]interface{} {
+	names := make([]string, 0, len(renames))
+	for name := range renames {
+		names = append(names, name)
+	}
+
+	return map[string]interface{}{
+		"query": map[string]interface{}{
+			"terms": map[string]interface{}{
+				"tags": names,
+			},
+		},
+		"script": map[string]interface{}{
+			"source": renameTagsScript,
+			"lang":   "painless",
+			"params": map[string]interface{}{
+				"tags": renames,
+			},
+		},
+	}
+}
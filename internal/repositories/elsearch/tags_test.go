@@ -0,0 +1,74 @@
+package elsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTicketTags(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{
+			"hits": {"total": {"value": 9, "relation": "eq"}, "hits": []},
+			"aggregations": {
+				"tags": {
+					"buckets": [
+						{"key": "Boleto", "doc_count": 6},
+						{"key": "boletos", "doc_count": 3}
+					]
+				}
+			}
+		}`))
+	})
+
+	tags, err := client.ListTicketTags(WithScope(context.Background(), UnrestrictedScope()))
+	require.NoError(t, err)
+	assert.Equal(t, []TicketTag{{Name: "Boleto", Tickets: 6}, {Name: "boletos", Tickets: 3}}, tags)
+}
+
+func TestRenameTicketTags(t *testing.T) {
+	var (
+		receivedPath  string
+		receivedQuery string
+		receivedBody  map[string]interface{}
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		receivedQuery = r.URL.RawQuery
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &receivedBody))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{"updated": 3, "failures": []}`))
+	})
+
+	updated, err := client.RenameTicketTags(context.Background(), map[string]string{"boletos": "Boleto"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated)
+
+	assert.Equal(t, "/support_tickets/_update_by_query", receivedPath)
+	assert.Contains(t, receivedQuery, "conflicts=proceed")
+	assert.Equal(t, map[string]interface{}{"terms": map[string]interface{}{"tags": []interface{}{"boletos"}}}, receivedBody["query"])
+
+	script := receivedBody["script"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"tags": map[string]interface{}{"boletos": "Boleto"}}, script["params"])
+}
+
+func TestRenameTicketTags_NothingToRename(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request is sent without renames")
+	})
+
+	updated, err := client.RenameTicketTags(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Zero(t, updated)
+}
@@ -7,15 +7,18 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"orderstreamrest/internal/apperror"
 	"orderstreamrest/internal/models/dto"
 	"time"
 
-	"github.com/elastic/go-elasticsearch/esapi"
-	"github.com/google/uuid"
+	"github.com/elastic/go-elasticsearch/v9/esapi"
 )
 
-// SearchTicketsBySomeWord realiza uma busca paginada de tickets com base nos parâmetros fornecidos
-func (es *Client) SearchTicketsBySomeWord(ctx context.Context, params dto.SearchParams) (*dto.PaginatedResponse, error) {
+// SearchTicketsBySomeWord realiza uma busca paginada de tickets com base nos parâmetros fornecidos.
+// Páginas por número vão até MaxResultWindow; a partir daí a busca continua com o cursor de
+// pagination.next_cursor, que usa search_after.
+func (es *Client) SearchTicketsBySomeWord(ctx context.Context, params dto.SearchParams) (*dto.TicketSearchResult, error) {
 	// Configurar paginação
 	if params.Page < 1 {
 		params.Page = 1
@@ -23,109 +26,115 @@ func (es *Client) SearchTicketsBySomeWord(ctx context.Context, params dto.Search
 	if params.PageSize < 1 || params.PageSize > 100 {
 		params.PageSize = 50
 	}
+	mode := es.SearchMode(params.Mode)
 
-	from := (params.Page - 1) * params.PageSize
-
-	// Construir a query
-	searchQuery := es.buildSearchQuery(params.Query, from, params.PageSize)
-
-	// Converter query para JSON
-	queryJSON, err := json.Marshal(searchQuery)
-	if err != nil {
-		return nil, fmt.Errorf("error serializing query: %v", err)
-	}
-
-	// Executar a busca
-	req := esapi.SearchRequest{
-		Index: []string{es.config.IndexName},
-		Body:  bytes.NewReader(queryJSON),
+	var cursor *searchCursor
+	if params.Cursor != "" {
+		decoded, err := decodeCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// O cursor só vale para a mesma busca que o gerou
+		if decoded.Query != params.Query || decoded.Mode != mode || !sameAttachmentFilters(decoded, params) {
+			return nil, ErrInvalidCursor
+		}
+		cursor = &decoded
+		params.Page, params.PageSize = decoded.Page, decoded.Size
 	}
 
-	res, err := req.Do(ctx, es.ES)
+	filters, err := attachmentFilters(params)
 	if err != nil {
-		return nil, fmt.Errorf("error executing search: %v", err)
+		return nil, err
 	}
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			log.Printf("error closing response body: %v", err)
-		}
-	}()
 
-	if res.IsError() {
-		body, _ := io.ReadAll(res.Body)
-		return nil, fmt.Errorf("search error: %s - %s", res.Status(), string(body))
-	}
+	from := (params.Page - 1) * params.PageSize
 
-	// Ler resposta
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
+	// Construir a query
+	searchQuery := withTiebreaker(withFilters(es.buildSearchQuery(params.Query, mode, from, params.PageSize), filters))
+	// O total exato é necessário para saber se há próxima página além de 10.000 tickets
+	searchQuery["track_total_hits"] = true
+	if cursor != nil {
+		delete(searchQuery, "from")
+		searchQuery["search_after"] = cursor.After
+	} else if from+params.PageSize > es.MaxResultWindow() {
+		return nil, ErrResultWindowExceeded
 	}
 
-	// Parse da resposta
-	var esResponse dto.ESResponse
-	if err := json.Unmarshal(body, &esResponse); err != nil {
-		return nil, fmt.Errorf("error deserializing response: %v", err)
+	// Executar a busca
+	esResponse, err := es.search(ctx, searchQuery)
+	if err != nil {
+		return nil, err
 	}
 
 	// Processar resultados
-	tickets := make([]map[string]interface{}, 0, len(esResponse.Hits.Hits))
-	for _, hit := range esResponse.Hits.Hits {
-		var ticket map[string]interface{}
-		if err := json.Unmarshal(hit.Source, &ticket); err != nil {
-			log.Printf("Error deserializing ticket: %v", err)
-			continue
-		}
-		tickets = append(tickets, ticket)
-	}
+	tickets := es.decodeHits(ctx, esResponse)
 
 	// Calcular paginação
 	totalPages := int((esResponse.Hits.Total.Value + int64(params.PageSize) - 1) / int64(params.PageSize))
+	pagination := dto.Pagination{
+		CurrentPage:  params.Page,
+		TotalRecords: esResponse.Hits.Total.Value,
+		PerPage:      params.PageSize,
+		TotalPages:   totalPages,
+		HasNext:      from+params.PageSize < int(esResponse.Hits.Total.Value),
+		HasPrev:      from > 0,
+	}
+
+	hits := esResponse.Hits.Hits
+	if pagination.HasNext && len(hits) > 0 && hits[len(hits)-1].Sort != nil {
+		pagination.NextCursor, err = encodeCursor(searchCursor{
+			Page:  params.Page + 1,
+			Size:  params.PageSize,
+			Query: params.Query,
+			Mode:  mode,
+			After: hits[len(hits)-1].Sort,
+
+			HasAttachments: params.HasAttachments,
+			AttachmentType: params.AttachmentType,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error encoding search cursor: %v", err)
+		}
+	}
 
-	return &dto.PaginatedResponse{
-		BaseResponse: dto.BaseResponse{
-			Success:   true,
-			Timestamp: time.Now(),
-			RequestID: uuid.New().String(),
-		},
-		Data: tickets,
-		Pagination: dto.Pagination{
-			CurrentPage:  params.Page,
-			TotalRecords: esResponse.Hits.Total.Value,
-			PerPage:      params.PageSize,
-			TotalPages:   totalPages,
-			HasNext:      from+params.PageSize < int(esResponse.Hits.Total.Value),
-			HasPrev:      from > 0,
-		},
-		Message: "200 OK",
+	return &dto.TicketSearchResult{
+		Tickets:    tickets,
+		Pagination: pagination,
+		Facets:     decodeFacets(esResponse),
 	}, nil
 }
 
-// SearchTicketByID busca um ticket pelo ticket_id e retorna todas as informações do ticket
-func (es *Client) SearchTicketByID(ctx context.Context, ticketID string) (*map[string]interface{}, error) {
-	// Montar a query para buscar pelo ticket_id
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"term": map[string]interface{}{
-				"ticket_id": ticketID,
-			},
-		},
-		"size": 1,
+// search executa uma busca no índice de tickets aplicando o escopo do contexto.
+// Todas as buscas de tickets devem passar por aqui.
+func (es *Client) search(ctx context.Context, body map[string]interface{}) (*dto.ESResponse, error) {
+	scoped, err := applyScope(ctx, body)
+	if err != nil {
+		return nil, err
 	}
 
-	queryJSON, err := json.Marshal(query)
+	// Converter query para JSON
+	queryJSON, err := json.Marshal(scoped)
 	if err != nil {
 		return nil, fmt.Errorf("error serializing query: %v", err)
 	}
 
-	req := esapi.SearchRequest{
-		Index: []string{es.config.IndexName},
-		Body:  bytes.NewReader(queryJSON),
-	}
+	start := time.Now()
+	esResponse, err := es.executeSearch(ctx, queryJSON)
+	es.observeQuery(ctx, queryJSON, time.Since(start), esResponse, err)
+	return esResponse, err
+}
 
-	res, err := req.Do(ctx, es.ES)
+// executeSearch envia a busca já serializada ao índice de tickets, pelo circuit breaker de buscas
+func (es *Client) executeSearch(ctx context.Context, queryJSON []byte) (*dto.ESResponse, error) {
+	res, err := doProtected(ctx, es.searchBreaker, func(ctx context.Context) (*esapi.Response, error) {
+		req := esapi.SearchRequest{
+			Index: []string{es.config.IndexName},
+			Body:  bytes.NewReader(queryJSON),
+		}
+		return req.Do(ctx, es.ES)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error executing search: %v", err)
+		return nil, fmt.Errorf("error executing search: %w", err)
 	}
 	defer func() {
 		if err := res.Body.Close(); err != nil {
@@ -135,27 +144,45 @@ func (es *Client) SearchTicketByID(ctx context.Context, ticketID string) (*map[s
 
 	if res.IsError() {
 		body, _ := io.ReadAll(res.Body)
-		return nil, fmt.Errorf("search error: %s - %s", res.Status(), string(body))
+		err := fmt.Errorf("search error: %s - %s", res.Status(), string(body))
+		// O índice pode ter um max_result_window menor que o configurado na aplicação
+		if res.StatusCode == http.StatusBadRequest && bytes.Contains(body, []byte("max_result_window")) {
+			return nil, apperror.Wrap(apperror.ErrValidation, ErrResultWindowExceeded.Error(), err)
+		}
+		return nil, err
 	}
 
-	body, err := io.ReadAll(res.Body)
+	// Ler resposta
+	responseBody, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, fmt.Errorf("error reading response: %v", err)
 	}
 
+	// Parse da resposta
 	var esResponse dto.ESResponse
-	if err := json.Unmarshal(body, &esResponse); err != nil {
+	if err := json.Unmarshal(responseBody, &esResponse); err != nil {
 		return nil, fmt.Errorf("error deserializing response: %v", err)
 	}
 
-	if len(esResponse.Hits.Hits) == 0 {
-		return nil, nil // Not found
-	}
+	return &esResponse, nil
+}
 
-	var ticket map[string]interface{}
-	if err := json.Unmarshal(esResponse.Hits.Hits[0].Source, &ticket); err != nil {
-		return nil, fmt.Errorf("error deserializing ticket: %v", err)
-	}
+// decodeHits converte os hits em tickets, descartando documentos fora do escopo do contexto
+func (es *Client) decodeHits(ctx context.Context, esResponse *dto.ESResponse) []map[string]interface{} {
+	tickets := make([]map[string]interface{}, 0, len(esResponse.Hits.Hits))
+	for _, hit := range esResponse.Hits.Hits {
+		var ticket map[string]interface{}
+		if err := json.Unmarshal(hit.Source, &ticket); err != nil {
+			log.Printf("Error deserializing ticket: %v", err)
+			continue
+		}
+
+		if !allowsDocument(ctx, ticket) {
+			log.Printf("Discarding ticket %s outside of the search scope", hit.ID)
+			continue
+		}
 
-	return &ticket, nil
+		tickets = append(tickets, ticket)
+	}
+	return tickets
 }
@@ -0,0 +1,66 @@
+package elsearch
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DailyTicketCount é a quantidade de tickets abertos em um dia (UTC)
+type DailyTicketCount struct {
+	Day   time.Time
+	Count int64
+}
+
+// GetDailyTicketCounts conta os tickets abertos por dia (UTC) entre from e to (exclusivo), pela data
+// de criação. Todos os dias do período são retornados, inclusive os sem tickets.
+func (es *Client) GetDailyTicketCounts(ctx context.Context, from, to time.Time) ([]DailyTicketCount, error) {
+	esResponse, err := es.search(ctx, dailyCountsQuery(from, to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tickets per day: %w", err)
+	}
+
+	buckets := esResponse.Aggregations["per_day"].Buckets
+	counts := make([]DailyTicketCount, 0, len(buckets))
+	for _, bucket := range buckets {
+		day, err := time.Parse(esDateLayout, bucket.KeyAsString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid day bucket %q: %w", bucket.KeyAsString, err)
+		}
+		counts = append(counts, DailyTicketCount{Day: day, Count: bucket.DocCount})
+	}
+	return counts, nil
+}
+
+// dailyCountsQuery monta o histograma diário de dates.created_at. extended_bounds garante os dias
+// sem tickets nas pontas do período.
+func dailyCountsQuery(from, to time.Time) map[string]interface{} {
+	from, to = from.UTC().Truncate(24*time.Hour), to.UTC().Truncate(24*time.Hour)
+
+	return map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				"dates.created_at": map[string]interface{}{
+					"gte":    from.Format(esDateTimeLayout),
+					"lt":     to.Format(esDateTimeLayout),
+					"format": "yyyy-MM-dd HH:mm:ss",
+				},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"per_day": map[string]interface{}{
+				"date_histogram": map[string]interface{}{
+					"field":             "dates.created_at",
+					"calendar_interval": "day",
+					"format":            "yyyy-MM-dd",
+					"min_doc_count":     0,
+					"extended_bounds": map[string]interface{}{
+						"min": from.Format(esDateLayout),
+						"max": to.AddDate(0, 0, -1).Format(esDateLayout),
+					},
+				},
+			},
+		},
+	}
+}
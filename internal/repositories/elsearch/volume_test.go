@@ -0,0 +1,63 @@
+package elsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDailyTicketCounts(t *testing.T) {
+	var receivedBody map[string]interface{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &receivedBody))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		_, _ = w.Write([]byte(`{
+			"hits": {"total": {"value": 7, "relation": "eq"}, "hits": []},
+			"aggregations": {
+				"per_day": {
+					"buckets": [
+						{"key": 1760486400000, "key_as_string": "2025-10-15", "doc_count": 4},
+						{"key": 1760572800000, "key_as_string": "2025-10-16", "doc_count": 0},
+						{"key": 1760659200000, "key_as_string": "2025-10-17", "doc_count": 3}
+					]
+				}
+			}
+		}`))
+	})
+
+	from := time.Date(2025, time.October, 15, 13, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+	counts, err := client.GetDailyTicketCounts(WithScope(context.Background(), UnrestrictedScope()), from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, []DailyTicketCount{
+		{Day: time.Date(2025, time.October, 15, 0, 0, 0, 0, time.UTC), Count: 4},
+		{Day: time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Day: time.Date(2025, time.October, 17, 0, 0, 0, 0, time.UTC), Count: 3},
+	}, counts)
+
+	histogram := receivedBody["aggs"].(map[string]interface{})["per_day"].(map[string]interface{})["date_histogram"].(map[string]interface{})
+	assert.Equal(t, "dates.created_at", histogram["field"])
+	assert.Equal(t, "day", histogram["calendar_interval"])
+	assert.Equal(t, map[string]interface{}{"min": "2025-10-15", "max": "2025-10-17"}, histogram["extended_bounds"])
+}
+
+func TestGetDailyTicketCounts_RequiresScope(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("search must not run without a scope")
+	})
+
+	_, err := client.GetDailyTicketCounts(context.Background(), time.Now().AddDate(0, 0, -7), time.Now())
+	assert.ErrorIs(t, err, ErrMissingScope)
+}
@@ -3,6 +3,8 @@ package mongo
 import (
 	"context"
 	"os"
+	"strconv"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -10,9 +12,26 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+const (
+	// defaultDatabase is used when MONGO_DATABASE is not set
+	defaultDatabase = "visiondata"
+	// defaultEventRetentionDays is how long ticket events are kept when TICKET_EVENTS_RETENTION_DAYS is not set
+	defaultEventRetentionDays = 365
+
+	connectTimeout = 10 * time.Second
+)
+
 // MongoInternal is a struct that contains a MongoDB client
 type MongoInternal struct {
 	client *mongo.Client
+	db     *mongo.Database
+	// eventRetention is the TTL of the ticket events; 0 keeps them forever
+	eventRetention time.Duration
+}
+
+// Enabled reports whether MongoDB is configured (MONGO_URI)
+func Enabled() bool {
+	return os.Getenv("MONGO_URI") != ""
 }
 
 // NewMongoInternal is a function that returns a new MongoInternal struct
@@ -24,18 +43,54 @@ func NewMongoInternal() (*MongoInternal, error) {
 
 	opts := options.Client().ApplyURI(uri).SetServerAPIOptions(serverAPI)
 
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
 	// Create a new client and connect to the server
-	client, err := mongo.Connect(context.TODO(), opts)
+	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := client.Database("admin").RunCommand(context.TODO(), bson.D{{Key: "ping", Value: 1}}).Err(); err != nil {
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Err(); err != nil {
+		_ = client.Disconnect(context.Background())
 		return nil, err
 	}
 
-	return &MongoInternal{
-		client: client,
-	}, nil
+	database := os.Getenv("MONGO_DATABASE")
+	if database == "" {
+		database = defaultDatabase
+	}
 
+	m := &MongoInternal{
+		client:         client,
+		db:             client.Database(database),
+		eventRetention: eventRetention(),
+	}
+
+	if err := m.ensureTicketEventIndexes(ctx); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Ping checks the connection to MongoDB
+func (m *MongoInternal) Ping(ctx context.Context) error {
+	return m.client.Ping(ctx, nil)
+}
+
+// Close disconnects the client
+func (m *MongoInternal) Close(ctx context.Context) error {
+	return m.client.Disconnect(ctx)
+}
+
+// eventRetention reads TICKET_EVENTS_RETENTION_DAYS; 0 keeps the events forever
+func eventRetention() time.Duration {
+	days := defaultEventRetentionDays
+	if value, err := strconv.Atoi(os.Getenv("TICKET_EVENTS_RETENTION_DAYS")); err == nil && value >= 0 {
+		days = value
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
@@ -0,0 +1,166 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// ticketEventsCollection holds the raw payload of every ticket write
+	ticketEventsCollection = "ticket_events"
+	// ticketEventsTTLIndex is the name of the index that expires old events
+	ticketEventsTTLIndex = "created_at_ttl"
+)
+
+// Ticket event types
+const (
+	TicketEventCreated  = "CREATED"
+	TicketEventUpdated  = "UPDATED"
+	TicketEventImported = "IMPORTED"
+)
+
+// TicketEvent is the raw payload of a ticket write, archived outside Elasticsearch
+type TicketEvent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TicketID  string             `bson:"ticket_id"`
+	Type      string             `bson:"type"`
+	ActorID   *int               `bson:"actor_id,omitempty"`
+	Payload   bson.Raw           `bson:"payload"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// NewTicketEvent builds an event from the JSON payload of the ticket
+func NewTicketEvent(ticketID, eventType string, actorID *int, payload []byte) (TicketEvent, error) {
+	var document bson.Raw
+	if err := bson.UnmarshalExtJSON(payload, false, &document); err != nil {
+		return TicketEvent{}, fmt.Errorf("invalid ticket event payload: %w", err)
+	}
+
+	return TicketEvent{
+		TicketID:  ticketID,
+		Type:      eventType,
+		ActorID:   actorID,
+		Payload:   document,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// PayloadJSON returns the payload as plain JSON
+func (e TicketEvent) PayloadJSON() ([]byte, error) {
+	if len(e.Payload) == 0 {
+		return nil, nil
+	}
+	return bson.MarshalExtJSON(e.Payload, false, false)
+}
+
+// ensureTicketEventIndexes creates the ticket_id lookup index and the TTL index on created_at.
+// A TTL index with another retention is recreated, since MongoDB does not change it in place.
+func (m *MongoInternal) ensureTicketEventIndexes(ctx context.Context) error {
+	indexes := m.db.Collection(ticketEventsCollection).Indexes()
+
+	_, err := indexes.CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "ticket_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("ticket_id_created_at"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create ticket events index: %w", err)
+	}
+
+	if m.eventRetention <= 0 {
+		if _, err := indexes.DropOne(ctx, ticketEventsTTLIndex); err != nil && !hasErrorCode(err, codeIndexNotFound, codeNamespaceNotFound) {
+			return fmt.Errorf("failed to drop ticket events ttl index: %w", err)
+		}
+		return nil
+	}
+
+	ttl := mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().
+			SetName(ticketEventsTTLIndex).
+			SetExpireAfterSeconds(int32(m.eventRetention / time.Second)),
+	}
+	_, err = indexes.CreateOne(ctx, ttl)
+	if hasErrorCode(err, codeIndexOptionsConflict) {
+		if _, err := indexes.DropOne(ctx, ticketEventsTTLIndex); err != nil {
+			return fmt.Errorf("failed to drop ticket events ttl index: %w", err)
+		}
+		_, err = indexes.CreateOne(ctx, ttl)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create ticket events ttl index: %w", err)
+	}
+
+	return nil
+}
+
+// MongoDB server error codes handled by the index setup
+const (
+	codeNamespaceNotFound    = 26
+	codeIndexNotFound        = 27
+	codeIndexOptionsConflict = 85
+)
+
+// hasErrorCode reports whether err is a MongoDB command error with one of the codes
+func hasErrorCode(err error, codes ...int32) bool {
+	var commandErr mongo.CommandError
+	if !errors.As(err, &commandErr) {
+		return false
+	}
+	for _, code := range codes {
+		if commandErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// InsertTicketEvents archives the events in a single round trip
+func (m *MongoInternal) InsertTicketEvents(ctx context.Context, events []TicketEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(events))
+	for i, event := range events {
+		documents[i] = event
+	}
+
+	// Unordered: one rejected event does not stop the others from being archived
+	if _, err := m.db.Collection(ticketEventsCollection).InsertMany(ctx, documents, options.InsertMany().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to insert ticket events: %w", err)
+	}
+	return nil
+}
+
+// ListTicketEvents returns a page of the ticket's events, newest first, and the total count
+func (m *MongoInternal) ListTicketEvents(ctx context.Context, ticketID string, offset, limit int) ([]TicketEvent, int64, error) {
+	collection := m.db.Collection(ticketEventsCollection)
+	filter := bson.D{{Key: "ticket_id", Value: ticketID}}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count ticket events: %w", err)
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find ticket events: %w", err)
+	}
+
+	events := []TicketEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, 0, fmt.Errorf("failed to read ticket events: %w", err)
+	}
+
+	return events, total, nil
+}
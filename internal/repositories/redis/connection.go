@@ -39,6 +39,8 @@ func NewRedisInternal() (*RedisInternal, error) {
 		}
 	}
 
+	rdb.AddHook(tracingHook{})
+
 	return &RedisInternal{
 		Redis: rdb,
 	}, nil
@@ -7,6 +7,13 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// Ping is a function that checks the connection to Redis
+func (r *RedisInternal) Ping(ctx context.Context) *redis.StatusCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.Ping(ctx)
+}
+
 // Get is a function that returns the value of a key
 func (r *RedisInternal) Get(ctx context.Context, key string) *redis.StringCmd {
 	mu.Lock()
@@ -48,3 +55,122 @@ func (r *RedisInternal) Incr(ctx context.Context, key string) *redis.IntCmd {
 	defer mu.Unlock()
 	return r.Redis.Incr(ctx, key)
 }
+
+// Decr is a function that decrements a key
+func (r *RedisInternal) Decr(ctx context.Context, key string) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.Decr(ctx, key)
+}
+
+// HIncrBy is a function that increments a field of a hash
+func (r *RedisInternal) HIncrBy(ctx context.Context, key, field string, incr int64) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.HIncrBy(ctx, key, field, incr)
+}
+
+// HGetAll is a function that returns all fields of a hash
+func (r *RedisInternal) HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.HGetAll(ctx, key)
+}
+
+// HSet is a function that sets fields of a hash
+func (r *RedisInternal) HSet(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.HSet(ctx, key, values...)
+}
+
+// HDel is a function that deletes fields of a hash
+func (r *RedisInternal) HDel(ctx context.Context, key string, fields ...string) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.HDel(ctx, key, fields...)
+}
+
+// Del is a function that deletes keys
+func (r *RedisInternal) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.Del(ctx, keys...)
+}
+
+// Scan is a function that iterates over the keys matching a pattern
+func (r *RedisInternal) Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.Scan(ctx, cursor, match, count)
+}
+
+// SAdd is a function that adds members to a set
+func (r *RedisInternal) SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.SAdd(ctx, key, members...)
+}
+
+// SRem is a function that removes members from a set
+func (r *RedisInternal) SRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.SRem(ctx, key, members...)
+}
+
+// SMembers is a function that returns all members of a set
+func (r *RedisInternal) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.SMembers(ctx, key)
+}
+
+// SIsMember is a function that checks whether a value is a member of a set
+func (r *RedisInternal) SIsMember(ctx context.Context, key string, member interface{}) *redis.BoolCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.SIsMember(ctx, key, member)
+}
+
+// SCard is a function that returns the number of members of a set
+func (r *RedisInternal) SCard(ctx context.Context, key string) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.SCard(ctx, key)
+}
+
+// LPush is a function that prepends values to a list
+func (r *RedisInternal) LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.LPush(ctx, key, values...)
+}
+
+// LTrim is a function that trims a list to the given range
+func (r *RedisInternal) LTrim(ctx context.Context, key string, start, stop int64) *redis.StatusCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.LTrim(ctx, key, start, stop)
+}
+
+// LRange is a function that returns a range of a list
+func (r *RedisInternal) LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.LRange(ctx, key, start, stop)
+}
+
+// SetNX is a function that sets a key only if it does not exist yet
+func (r *RedisInternal) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.SetNX(ctx, key, value, expiration)
+}
+
+// LLen is a function that returns the length of a list
+func (r *RedisInternal) LLen(ctx context.Context, key string) *redis.IntCmd {
+	mu.Lock()
+	defer mu.Unlock()
+	return r.Redis.LLen(ctx, key)
+}
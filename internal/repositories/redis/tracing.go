@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"orderstreamrest/internal/telemetry"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingHook is a redis.Hook that wraps every command and pipeline in a client span
+type tracingHook struct{}
+
+var _ redis.Hook = tracingHook{}
+
+func (tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := startSpan(ctx, "redis."+cmd.Name(), semconv.DBOperationName(cmd.Name()))
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordError(span, err)
+		return err
+	}
+}
+
+func (tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := startSpan(ctx, "redis.pipeline",
+			semconv.DBOperationName("pipeline"),
+			attribute.Int("db.redis.pipeline_length", len(cmds)),
+		)
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordError(span, err)
+		return err
+	}
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return telemetry.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append([]attribute.KeyValue{semconv.DBSystemRedis}, attrs...)...),
+	)
+}
+
+// recordError marks the span as failed, except for redis.Nil which is a regular cache miss
+func recordError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
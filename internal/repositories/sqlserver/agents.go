@@ -0,0 +1,40 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/models/dto"
+)
+
+// GetAgentsWorkload retorna, para cada agente ativo, os tickets atribuídos e quantos ainda estão abertos.
+// Agentes sem tickets no filtro também são retornados, com totais zerados.
+func (s *Internal) GetAgentsWorkload(ctx context.Context, filter dto.MetricsFilter) ([]AgentTickets, error) {
+	var results []AgentTickets
+	query := `
+    SELECT
+        da.AgentId_BK AS agent_id,
+        da.FullName AS full_name,
+        da.DepartmentName AS department,
+        COALESCE(SUM(CASE WHEN t.ClosedDateKey IS NULL THEN t.QtTickets ELSE 0 END), 0) AS open_tickets,
+        COALESCE(SUM(t.QtTickets), 0) AS total_tickets
+    FROM dbo.Dim_Agents da
+    LEFT JOIN (
+        SELECT ft.AgentKey, ft.ClosedDateKey, ft.QtTickets
+        FROM dbo.Fact_Tickets ft
+        JOIN DW.dbo.Dim_Dates dd
+            ON ft.EntryDateKey = dd.DateKey
+        WHERE 1 = 1
+        %s
+    ) t
+        ON t.AgentKey = da.AgentKey
+    WHERE da.IsActive = 1
+    GROUP BY da.AgentId_BK, da.FullName, da.DepartmentName
+    ORDER BY open_tickets DESC, full_name;
+    `
+	conditions, args := andMetricsFilter("dd", filter)
+	err := s.metricsDB(ctx).Raw(fmt.Sprintf(query, conditions), args...).Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agents workload: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,92 @@
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orderstreamrest/internal/models/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TicketVolumeAlertFilter restringe a listagem dos alertas de volume. Campos vazios não filtram.
+type TicketVolumeAlertFilter struct {
+	Severity string
+	From     *time.Time
+	To       *time.Time
+}
+
+// SaveTicketVolumeAlert grava o alerta do dia, ou atualiza os números e a severidade do alerta já
+// registrado para o mesmo dia. Retorna true quando o alerta é novo.
+func (s *Internal) SaveTicketVolumeAlert(ctx context.Context, alert *entities.TicketVolumeAlert) (bool, error) {
+	var existing entities.TicketVolumeAlert
+	err := s.db.WithContext(ctx).
+		Table("dbo.TicketVolumeAlerts").
+		Where("Day = ?", alert.Day).
+		First(&existing).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := s.db.WithContext(ctx).Table("dbo.TicketVolumeAlerts").Create(alert).Error; err != nil {
+			return false, fmt.Errorf("failed to create ticket volume alert: %w", err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get ticket volume alert: %w", err)
+	}
+
+	updatedAt := time.Now().UTC()
+	err = s.db.WithContext(ctx).
+		Table("dbo.TicketVolumeAlerts").
+		Where("Id = ?", existing.Id).
+		Updates(map[string]interface{}{
+			"Tickets":        alert.Tickets,
+			"BaselineMean":   alert.BaselineMean,
+			"BaselineStdDev": alert.BaselineStdDev,
+			"BaselineDays":   alert.BaselineDays,
+			"ZScore":         alert.ZScore,
+			"Severity":       alert.Severity,
+			"UpdatedAt":      updatedAt,
+		}).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to update ticket volume alert: %w", err)
+	}
+
+	alert.Id = existing.Id
+	alert.CreatedAt = existing.CreatedAt
+	alert.UpdatedAt = &updatedAt
+	return false, nil
+}
+
+// ListTicketVolumeAlerts retorna os alertas de volume, do dia mais recente para o mais antigo, com paginação
+func (s *Internal) ListTicketVolumeAlerts(ctx context.Context, filter TicketVolumeAlertFilter, offset, limit int) ([]entities.TicketVolumeAlert, int64, error) {
+	query := s.db.WithContext(ctx).Table("dbo.TicketVolumeAlerts")
+
+	if filter.Severity != "" {
+		query = query.Where("Severity = ?", filter.Severity)
+	}
+	if filter.From != nil {
+		query = query.Where("Day >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("Day < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count ticket volume alerts: %w", err)
+	}
+
+	var alerts []entities.TicketVolumeAlert
+	err := query.
+		Order("Day DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&alerts).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get ticket volume alerts: %w", err)
+	}
+
+	return alerts, total, nil
+}
@@ -0,0 +1,73 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/models/entities"
+	"time"
+)
+
+// AuditLogFilter restringe a listagem da trilha de auditoria. Campos vazios não filtram.
+type AuditLogFilter struct {
+	ActorId    *int
+	Action     string
+	EntityType string
+	EntityId   string
+	From       *time.Time
+	To         *time.Time
+}
+
+// CreateAuditLog grava uma entrada na trilha de auditoria
+func (s *Internal) CreateAuditLog(ctx context.Context, entry *entities.AuditLog) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.AuditLogs").
+		Create(entry)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to create audit log: %w", result.Error)
+	}
+
+	return nil
+}
+
+// ListAuditLogs retorna as entradas da trilha de auditoria, da mais recente para a mais antiga, com paginação
+func (s *Internal) ListAuditLogs(ctx context.Context, filter AuditLogFilter, offset, limit int) ([]entities.AuditLog, int64, error) {
+	query := s.db.WithContext(ctx).Table("dbo.AuditLogs")
+
+	if filter.ActorId != nil {
+		query = query.Where("ActorId = ?", *filter.ActorId)
+	}
+	if filter.Action != "" {
+		query = query.Where("Action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("EntityType = ?", filter.EntityType)
+	}
+	if filter.EntityId != "" {
+		query = query.Where("EntityId = ?", filter.EntityId)
+	}
+	if filter.From != nil {
+		query = query.Where("CreatedAt >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("CreatedAt < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	var logs []entities.AuditLog
+	err := query.
+		Order("CreatedAt DESC").
+		Order("Id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&logs).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+
+	return logs, total, nil
+}
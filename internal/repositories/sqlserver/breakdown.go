@@ -0,0 +1,171 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/dto"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnknownDimension é retornado quando a dimensão solicitada não existe
+var ErrUnknownDimension error = apperror.New(apperror.ErrValidation, "unknown metrics dimension")
+
+// breakdownDimension descreve como agrupar o Fact_Tickets por uma dimensão
+type breakdownDimension struct {
+	column string
+	join   string
+}
+
+// breakdownDimensions lista as dimensões disponíveis para os breakdowns de tickets
+var breakdownDimensions = map[string]breakdownDimension{
+	"category": {
+		column: "dim.CategoryName",
+		join:   "INNER JOIN dbo.Dim_Categories dim ON ft.CategoryKey = dim.CategoryKey",
+	},
+	"priority": {
+		column: "dim.Name",
+		join:   "INNER JOIN dbo.Dim_Priorities dim ON ft.PriorityKey = dim.PriorityKey",
+	},
+	"channel": {
+		column: "dim.ChannelName",
+		join:   "INNER JOIN dbo.Dim_Channel dim ON ft.ChannelKey = dim.ChannelKey",
+	},
+	"tag": {
+		column: "dim.Name",
+		join:   "INNER JOIN dbo.Dim_Tags dim ON ft.TagKey = dim.TagKey",
+	},
+	"company": {
+		column: "dim.Name",
+		join:   "INNER JOIN dbo.Dim_Companies dim ON ft.CompanyKey = dim.CompanyKey",
+	},
+	"department": {
+		column: "dim.DepartmentName",
+		join:   "INNER JOIN dbo.Dim_Agents dim ON ft.AgentKey = dim.AgentKey",
+	},
+	"product": {
+		column: "dim.Name",
+		join:   "INNER JOIN dbo.Dim_Products dim ON ft.ProductKey = dim.ProductKey",
+	},
+}
+
+// IsBreakdownDimension indica se a dimensão pode ser usada nos breakdowns
+func IsBreakdownDimension(name string) bool {
+	_, ok := breakdownDimensions[name]
+	return ok
+}
+
+// BreakdownDimensions retorna os nomes das dimensões disponíveis, em ordem alfabética
+func BreakdownDimensions() []string {
+	names := make([]string, 0, len(breakdownDimensions))
+	for name := range breakdownDimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// breakdownQuery monta a consulta agrupada pela dimensão, sem ordenação
+func (s *Internal) breakdownQuery(ctx context.Context, dimension string, filter dto.MetricsFilter) (*gorm.DB, breakdownDimension, error) {
+	dim, ok := breakdownDimensions[dimension]
+	if !ok {
+		return nil, dim, fmt.Errorf("%w: %s", ErrUnknownDimension, dimension)
+	}
+
+	return s.metricsDB(ctx).
+		Table("dbo.Fact_Tickets ft").
+		Scopes(withMetricsFilter(filter)).
+		Select(dim.column + " AS Name, SUM(ft.QtTickets) AS Value").
+		Joins(dim.join).
+		Group(dim.column), dim, nil
+}
+
+// GetTicketsBreakdown retorna uma página do total de tickets por dimensão e a quantidade de valores distintos
+func (s *Internal) GetTicketsBreakdown(ctx context.Context, dimension string, filter dto.MetricsFilter, offset, limit int) ([]dto.MetricValue, int64, error) {
+	query, dim, err := s.breakdownQuery(ctx, dimension, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// O SQL Server não aceita ORDER BY em subconsultas, então a contagem usa a consulta sem ordenação
+	var count int64
+	if err := s.metricsDB(ctx).Table("(?) AS breakdown", query).Count(&count).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count breakdown values: %w", err)
+	}
+
+	query, _, err = s.breakdownQuery(ctx, dimension, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var results []dto.MetricValue
+	if err := query.Order("Value DESC").Order(dim.column).Offset(offset).Limit(limit).Scan(&results).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get tickets breakdown: %w", err)
+	}
+
+	return results, count, nil
+}
+
+// GetTicketsTop retorna os n valores da dimensão com mais tickets
+func (s *Internal) GetTicketsTop(ctx context.Context, dimension string, filter dto.MetricsFilter, n int) ([]dto.MetricValue, error) {
+	query, dim, err := s.breakdownQuery(ctx, dimension, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []dto.MetricValue
+	if err := query.Order("Value DESC").Order(dim.column).Limit(n).Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to get top tickets by %s: %w", dimension, err)
+	}
+
+	return results, nil
+}
+
+// GetTicketsBreakdownTotal retorna a soma de tickets de todos os valores da dimensão
+func (s *Internal) GetTicketsBreakdownTotal(ctx context.Context, dimension string, filter dto.MetricsFilter) (int64, error) {
+	dim, ok := breakdownDimensions[dimension]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownDimension, dimension)
+	}
+
+	var total int64
+	err := s.metricsDB(ctx).
+		Table("dbo.Fact_Tickets ft").
+		Scopes(withMetricsFilter(filter)).
+		Select("COALESCE(SUM(ft.QtTickets), 0)").
+		Joins(dim.join).
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get breakdown total: %w", err)
+	}
+
+	return total, nil
+}
+
+// StreamTicketsBreakdown percorre todos os valores da dimensão sem carregá-los em memória
+func (s *Internal) StreamTicketsBreakdown(ctx context.Context, dimension string, filter dto.MetricsFilter, fn func(dto.MetricValue) error) error {
+	query, dim, err := s.breakdownQuery(ctx, dimension, filter)
+	if err != nil {
+		return err
+	}
+
+	rows, err := query.Order("Value DESC").Order(dim.column).Rows()
+	if err != nil {
+		return fmt.Errorf("failed to stream tickets breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item dto.MetricValue
+		if err := rows.Scan(&item.Name, &item.Value); err != nil {
+			return fmt.Errorf("failed to read tickets breakdown: %w", err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
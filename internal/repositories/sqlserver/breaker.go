@@ -0,0 +1,88 @@
+package sqlserver
+
+import (
+	"errors"
+	"orderstreamrest/internal/resilience"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// metricsBreakerName é o nome do circuito das consultas de métricas (BREAKER_SQL_METRICS_*)
+	metricsBreakerName = "sql_metrics"
+
+	// metricsBreakerKey marca nas configurações do statement as consultas protegidas pelo circuito
+	metricsBreakerKey = "breaker:metrics"
+	// breakerDoneInstanceKey guarda entre os callbacks a função que informa o resultado ao circuito
+	breakerDoneInstanceKey = "breaker:done"
+)
+
+// registerMetricsBreaker protege as consultas de metricsDB com o circuito: aberto, a consulta nem chega
+// ao SQL Server e falha na hora com ErrUnavailable. Falhas e timeouts das consultas abrem o circuito.
+func registerMetricsBreaker(db *gorm.DB, breaker *resilience.Breaker) error {
+	if breaker == nil {
+		return nil
+	}
+
+	callbacks := db.Callback()
+
+	register := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range register {
+		if err := r.before("breaker:before_"+r.operation, allowQuery(breaker)); err != nil {
+			return err
+		}
+		if err := r.after("breaker:after_"+r.operation, reportQuery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// allowQuery consulta o circuito antes das consultas de métricas; o erro do circuito aberto impede a execução
+func allowQuery(breaker *resilience.Breaker) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if _, ok := db.Get(metricsBreakerKey); !ok || db.Error != nil {
+			return
+		}
+
+		done, err := breaker.Allow()
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+		db.InstanceSet(breakerDoneInstanceKey, done)
+	}
+}
+
+// reportQuery informa o resultado da consulta ao circuito; registro não encontrado não é falha
+func reportQuery(db *gorm.DB) {
+	value, ok := db.InstanceGet(breakerDoneInstanceKey)
+	if !ok {
+		return
+	}
+	done, ok := value.(func(error))
+	if !ok {
+		return
+	}
+
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	done(err)
+}
+
+// MetricsBreaker retorna o circuito das consultas de métricas, para o healthcheck
+func (s *Internal) MetricsBreaker() (string, *resilience.Breaker) {
+	return metricsBreakerName, s.metricsBreaker
+}
@@ -1,9 +1,13 @@
 package sqlserver
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/resilience"
 	"os"
+	"sync/atomic"
 
 	"gorm.io/driver/sqlserver"
 	"gorm.io/gorm"
@@ -19,6 +23,12 @@ import (
 // SQLServerInternal is a struct that contains a SQL Server database connection
 type Internal struct {
 	db *gorm.DB
+	// replica é a réplica somente leitura das métricas, nil quando não configurada
+	replica *sql.DB
+	// metricsBreaker é o circuito das consultas de métricas, nil quando desativado
+	metricsBreaker *resilience.Breaker
+	// userCompany indica que dbo.tb_users já tem a coluna CompanyId (ver HasUserCompany)
+	userCompany *atomic.Bool
 }
 
 // NewSQLServerInternal is a function that returns a new SQLServerInternal struct
@@ -31,46 +41,84 @@ func NewSQLServerInternal() (*Internal, error) {
 	sqlServerDatabase := os.Getenv("SQLSERVER_DATABASE")
 
 	dsn := "sqlserver://" + sqlServerUsername + ":" + sqlServerPassword + "@" + sqlServerHost + ":" + sqlServerPort + "?database=" + sqlServerDatabase
-	fmt.Println("DSN SQLSERVER:", dsn)
 
-	db, err := gorm.Open(sqlserver.Open(dsn), &gorm.Config{})
+	// TranslateError converte erros do driver (ex.: chave duplicada) nos erros do GORM; NowFunc grava
+	// CreatedAt/UpdatedAt em UTC, como o restante das datas do banco
+	db, err := gorm.Open(sqlserver.Open(dsn), &gorm.Config{TranslateError: true, NowFunc: utcNow})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := registerTracing(db); err != nil {
+		return nil, err
+	}
+
+	pool := LoadPoolConfig()
+	if err := registerQueryTimeout(db, pool.QueryTimeout); err != nil {
+		return nil, err
+	}
+
+	// Sem novas tentativas: uma consulta pesada ao DW repetida só aumenta a carga
+	metricsBreaker := resilience.NewBreaker(resilience.LoadPolicy(metricsBreakerName, 0))
+	if err := registerMetricsBreaker(db, metricsBreaker); err != nil {
+		return nil, err
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
 	}
+	applyPool(sqlDB, pool)
 
 	if err := sqlDB.Ping(); err != nil {
 		return nil, err
 	}
 
+	replica, err := registerReplica(db, pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to sql server replica: %w", err)
+	}
+
 	return &Internal{
-		db: db,
+		db:             db,
+		replica:        replica,
+		metricsBreaker: metricsBreaker,
+		userCompany:    new(atomic.Bool),
 	}, nil
 }
 
+// Ping verifica a conexão com o SQL Server
+func (s *Internal) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PingWarehouse verifica o acesso ao banco DW, usado pelas consultas de métricas (na réplica, quando configurada)
+func (s *Internal) PingWarehouse(ctx context.Context) error {
+	var one int
+	return s.metricsDB(ctx).Raw("SELECT TOP 1 1 FROM DW.dbo.Dim_Dates").Scan(&one).Error
+}
+
 // Retorna o total de tickets
-func (s *Internal) GetTotalTickets() (int64, error) {
+func (s *Internal) GetTotalTickets(ctx context.Context, filter dto.MetricsFilter) (int64, error) {
 	var total int64
-	err := s.db.Table("dbo.Fact_Tickets").
-		Select("SUM(QtTickets)").
+	err := s.metricsDB(ctx).
+		Table("dbo.Fact_Tickets ft").
+		Scopes(withMetricsFilter(filter)).
+		Select("SUM(ft.QtTickets)").
 		Scan(&total).Error
 	return total, err
 }
 
 // Retorna o total de tickets agrupados por categoria
-func (s *Internal) GetTicketsByCategory() ([]struct {
-	entities.Dim_Categories
-	Total int64
-}, error) {
-	var results []struct {
-		entities.Dim_Categories
-		Total int64
-	}
-	err := s.db.Table("dbo.Fact_Tickets ft").
+func (s *Internal) GetTicketsByCategory(ctx context.Context, filter dto.MetricsFilter) ([]CategoryTotal, error) {
+	var results []CategoryTotal
+	err := s.metricsDB(ctx).
+		Table("dbo.Fact_Tickets ft").
+		Scopes(withMetricsFilter(filter)).
 		Select("dc.CategoryName, SUM(ft.QtTickets) as Total").
 		Joins("INNER JOIN dbo.Dim_Categories dc ON ft.CategoryKey = dc.CategoryKey").
 		Group("dc.CategoryName").
@@ -80,15 +128,11 @@ func (s *Internal) GetTicketsByCategory() ([]struct {
 }
 
 // Retorna o total de tickets agrupados por prioridade
-func (s *Internal) GetTicketsByPriority() ([]struct {
-	entities.Dim_Priorities
-	Total int64
-}, error) {
-	var results []struct {
-		entities.Dim_Priorities
-		Total int64
-	}
-	err := s.db.Table("dbo.Fact_Tickets ft").
+func (s *Internal) GetTicketsByPriority(ctx context.Context, filter dto.MetricsFilter) ([]PriorityTotal, error) {
+	var results []PriorityTotal
+	err := s.metricsDB(ctx).
+		Table("dbo.Fact_Tickets ft").
+		Scopes(withMetricsFilter(filter)).
 		Select("dp.Name, SUM(ft.QtTickets) as Total").
 		Joins("INNER JOIN dbo.Dim_Priorities dp ON ft.PriorityKey = dp.PriorityKey").
 		Group("dp.Name").
@@ -98,15 +142,11 @@ func (s *Internal) GetTicketsByPriority() ([]struct {
 }
 
 // Retorna o total de tickets por channel
-func (s *Internal) GetTicketsByChannel() ([]struct {
-	entities.Dim_Channel
-	Total int64
-}, error) {
-	var results []struct {
-		entities.Dim_Channel
-		Total int64
-	}
-	err := s.db.Table("dbo.Fact_Tickets ft").
+func (s *Internal) GetTicketsByChannel(ctx context.Context, filter dto.MetricsFilter) ([]ChannelTotal, error) {
+	var results []ChannelTotal
+	err := s.metricsDB(ctx).
+		Table("dbo.Fact_Tickets ft").
+		Scopes(withMetricsFilter(filter)).
 		Select("dc.ChannelName, SUM(ft.QtTickets) as Total").
 		Joins("INNER JOIN dbo.Dim_Channel dc ON ft.ChannelKey = dc.ChannelKey").
 		Group("dc.ChannelName").
@@ -116,15 +156,11 @@ func (s *Internal) GetTicketsByChannel() ([]struct {
 }
 
 // Retorna o total de tickets por tag
-func (s *Internal) GetTicketsByTag() ([]struct {
-	entities.Dim_Tags
-	Total int64
-}, error) {
-	var results []struct {
-		entities.Dim_Tags
-		Total int64
-	}
-	err := s.db.Table("dbo.Fact_Tickets ft").
+func (s *Internal) GetTicketsByTag(ctx context.Context, filter dto.MetricsFilter) ([]TagTotal, error) {
+	var results []TagTotal
+	err := s.metricsDB(ctx).
+		Table("dbo.Fact_Tickets ft").
+		Scopes(withMetricsFilter(filter)).
 		Select("dt.Name, SUM(ft.QtTickets) as Total").
 		Joins("INNER JOIN dbo.Dim_Tags dt ON ft.TagKey = dt.TagKey").
 		Group("dt.Name").
@@ -134,15 +170,11 @@ func (s *Internal) GetTicketsByTag() ([]struct {
 }
 
 // Retorna o total de tickets por departamento
-func (s *Internal) GetTicketsByDepartment() ([]struct {
-	entities.Dim_Companies
-	Total int64
-}, error) {
-	var results []struct {
-		entities.Dim_Companies
-		Total int64
-	}
-	err := s.db.Table("dbo.Fact_Tickets ft").
+func (s *Internal) GetTicketsByDepartment(ctx context.Context, filter dto.MetricsFilter) ([]DepartmentTotal, error) {
+	var results []DepartmentTotal
+	err := s.metricsDB(ctx).
+		Table("dbo.Fact_Tickets ft").
+		Scopes(withMetricsFilter(filter)).
 		Select("dc.Name, SUM(ft.QtTickets) as Total").
 		Joins("INNER JOIN dbo.Dim_Companies dc ON ft.CompanyKey = dc.CompanyKey").
 		Group("dc.Name").
@@ -151,90 +183,92 @@ func (s *Internal) GetTicketsByDepartment() ([]struct {
 	return results, err
 }
 
-// Retorna o tempo médio de resolução de tickets por prioridade
-func (s *Internal) GetAverageResolutionTime() ([]struct {
-	NomePrioridade      string  `gorm:"column:nome_prioridade"`
-	MediaResolucaoHoras float64 `gorm:"column:media_resolucao_horas"`
-	MediaResolucaoDias  float64 `gorm:"column:media_resolucao_dias"`
-}, error) {
-	var results []struct {
-		NomePrioridade      string  `gorm:"column:nome_prioridade"`
-		MediaResolucaoHoras float64 `gorm:"column:media_resolucao_horas"`
-		MediaResolucaoDias  float64 `gorm:"column:media_resolucao_dias"`
+// Retorna o tempo médio, a mediana e os percentis 90 e 99 de resolução dos tickets por prioridade
+func (s *Internal) GetAverageResolutionTime(ctx context.Context, filter dto.MetricsFilter) ([]ResolutionTimeByPriority, error) {
+	percentiles, err := s.GetResolutionTimePercentiles(ctx, "priority", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ResolutionTimeByPriority, 0, len(percentiles))
+	for _, item := range percentiles {
+		results = append(results, ResolutionTimeByPriority{
+			NomePrioridade:        item.Name,
+			MediaResolucaoHoras:   item.MeanHours,
+			MediaResolucaoDias:    item.MeanHours / 24,
+			MedianaResolucaoHoras: item.P50Hours,
+			P90ResolucaoHoras:     item.P90Hours,
+			P99ResolucaoHoras:     item.P99Hours,
+		})
 	}
+	return results, nil
+}
+
+// GetResolutionTimePercentiles retorna, para cada valor da dimensão, a quantidade de tickets fechados e
+// o tempo de resolução médio, mediano (P50) e nos percentis 90 e 99, em horas. O PERCENTILE_CONT do
+// SQL Server só existe como função de janela, por isso o DISTINCT sobre as partições.
+func (s *Internal) GetResolutionTimePercentiles(ctx context.Context, dimension string, filter dto.MetricsFilter) ([]ResolutionTimePercentiles, error) {
+	dim, ok := breakdownDimensions[dimension]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDimension, dimension)
+	}
+
+	var results []ResolutionTimePercentiles
 	query := `
-    SELECT
-        dp.Name as nome_prioridade,
-        AVG(CAST(DATEDIFF(SECOND,
-            DATETIMEFROMPARTS(de.Year, de.Month, de.Day, de.Hour, de.Minute, 0,0),
-            DATETIMEFROMPARTS(dc.Year, dc.Month, dc.Day, dc.Hour, dc.Minute, 0,0)
-        ) AS FLOAT) / 3600.0) AS "media_resolucao_horas",
-        AVG(CAST(DATEDIFF(SECOND,
-            DATETIMEFROMPARTS(de.Year, de.Month, de.Day, de.Hour, de.Minute, 0,0),
-            DATETIMEFROMPARTS(dc.Year, dc.Month, dc.Day, dc.Hour, dc.Minute, 0,0)
-        ) AS FLOAT) / 86400.0) AS "media_resolucao_dias"
-    FROM dbo.Fact_Tickets ft
-    JOIN Dim_Priorities dp
-        ON ft.PriorityKey = dp.PriorityKey
-    JOIN DW.dbo.Dim_Dates de
-        ON ft.EntryDateKey = de.DateKey
-    JOIN DW.dbo.Dim_Dates dc
-        ON ft.ClosedDateKey = dc.DateKey
-    WHERE ft.ClosedDateKey IS NOT NULL
-    GROUP BY dp.Name
-    ORDER BY nome_prioridade;
+    WITH Resolutions AS (
+        SELECT
+            %[1]s AS name,
+            CAST(DATEDIFF(SECOND,
+                DATETIMEFROMPARTS(de.Year, de.Month, de.Day, de.Hour, de.Minute, 0,0),
+                DATETIMEFROMPARTS(dc.Year, dc.Month, dc.Day, dc.Hour, dc.Minute, 0,0)
+            ) AS FLOAT) / 3600.0 AS hours
+        FROM dbo.Fact_Tickets ft
+        %[2]s
+        JOIN DW.dbo.Dim_Dates de
+            ON ft.EntryDateKey = de.DateKey
+        JOIN DW.dbo.Dim_Dates dc
+            ON ft.ClosedDateKey = dc.DateKey
+        WHERE ft.ClosedDateKey IS NOT NULL
+        %[3]s
+    )
+    SELECT DISTINCT
+        name,
+        COUNT(*) OVER (PARTITION BY name) AS tickets,
+        AVG(hours) OVER (PARTITION BY name) AS mean_hours,
+        PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY hours) OVER (PARTITION BY name) AS p50_hours,
+        PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY hours) OVER (PARTITION BY name) AS p90_hours,
+        PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY hours) OVER (PARTITION BY name) AS p99_hours
+    FROM Resolutions
+    ORDER BY name;
     `
-	err := s.db.Raw(query).Scan(&results).Error
-	return results, err
+	conditions, args := andMetricsFilter("de", filter)
+	err := s.metricsDB(ctx).Raw(fmt.Sprintf(query, dim.column, dim.join, conditions), args...).Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resolution time percentiles by %s: %w", dimension, err)
+	}
+	return results, nil
 }
 
-// Retorna o total de tickets por status e mês
-func (s *Internal) GetTicketsByStatusAndMonth() ([]struct {
-	NomeStatus string `gorm:"column:nome_status"`
-	Ano        int    `gorm:"column:ano"`
-	Janeiro    int    `gorm:"column:janeiro"`
-	Fevereiro  int    `gorm:"column:fevereiro"`
-	Marco      int    `gorm:"column:marco"`
-	Abril      int    `gorm:"column:abril"`
-	Maio       int    `gorm:"column:maio"`
-	Junho      int    `gorm:"column:junho"`
-	Julho      int    `gorm:"column:julho"`
-	Agosto     int    `gorm:"column:agosto"`
-	Setembro   int    `gorm:"column:setembro"`
-	Outubro    int    `gorm:"column:outubro"`
-	Novembro   int    `gorm:"column:novembro"`
-	Dezembro   int    `gorm:"column:dezembro"`
-}, error) {
-	var results []struct {
-		NomeStatus string `gorm:"column:nome_status"`
-		Ano        int    `gorm:"column:ano"`
-		Janeiro    int    `gorm:"column:janeiro"`
-		Fevereiro  int    `gorm:"column:fevereiro"`
-		Marco      int    `gorm:"column:marco"`
-		Abril      int    `gorm:"column:abril"`
-		Maio       int    `gorm:"column:maio"`
-		Junho      int    `gorm:"column:junho"`
-		Julho      int    `gorm:"column:julho"`
-		Agosto     int    `gorm:"column:agosto"`
-		Setembro   int    `gorm:"column:setembro"`
-		Outubro    int    `gorm:"column:outubro"`
-		Novembro   int    `gorm:"column:novembro"`
-		Dezembro   int    `gorm:"column:dezembro"`
-	}
+// Retorna o total de tickets por status e mês, com os meses contados no fuso do filtro
+func (s *Internal) GetTicketsByStatusAndMonth(ctx context.Context, filter dto.MetricsFilter) ([]StatusMonthCounts, error) {
+	var results []StatusMonthCounts
 
 	query := `
     WITH Counts AS (
         SELECT
             ds.Name AS status,
-            dd.Year,
-            dd.Month AS monthnum,
+            %[1]s AS [Year],
+            %[2]s AS monthnum,
             COUNT(*) AS cnt
         FROM dbo.Fact_Tickets ft
         JOIN DW.dbo.Dim_Dates dd
             ON ft.EntryDateKey = dd.DateKey
+        %[4]s
         JOIN DW.dbo.Dim_Status ds
             ON ft.StatusKey = ds.StatusKey
-        GROUP BY ds.Name, dd.Year, dd.Month
+        WHERE 1 = 1
+        %[3]s
+        GROUP BY ds.Name, %[1]s, %[2]s
     ),
     Pivoted AS (
         SELECT
@@ -263,85 +297,57 @@ func (s *Internal) GetTicketsByStatusAndMonth() ([]struct {
     ORDER BY status, [Year];
     `
 
-	err := s.db.Raw(query).Scan(&results).Error
+	year, month := monthExprs("dd", filter)
+	conditions, args := metricsWhere("dd", filter).And()
+	err := s.metricsDB(ctx).Raw(fmt.Sprintf(query, year, month, conditions, zoneOffsetsJoin("dd", filter)), args...).Scan(&results).Error
 	return results, err
 }
 
-// Retorna o total de tickets por mês e ano
-func (s *Internal) GetTicketsByMonth() ([]struct {
-	Ano          int `gorm:"column:ano"`
-	Mes          int `gorm:"column:mes"`
-	TotalTickets int `gorm:"column:total_tickets"`
-}, error) {
-	var results []struct {
-		Ano          int `gorm:"column:ano"`
-		Mes          int `gorm:"column:mes"`
-		TotalTickets int `gorm:"column:total_tickets"`
-	}
+// Retorna o total de tickets por mês e ano, com os meses contados no fuso do filtro
+func (s *Internal) GetTicketsByMonth(ctx context.Context, filter dto.MetricsFilter) ([]MonthTotal, error) {
+	var results []MonthTotal
 
 	query := `
     SELECT
-        dd.Year AS ano,
-        dd.Month AS mes,
+        %[1]s AS ano,
+        %[2]s AS mes,
         COUNT(*) AS total_tickets
     FROM dbo.Fact_Tickets ft
     JOIN DW.dbo.Dim_Dates dd
         ON ft.EntryDateKey = dd.DateKey
-    GROUP BY dd.Year, dd.Month
+    %[4]s
+    WHERE 1 = 1
+    %[3]s
+    GROUP BY %[1]s, %[2]s
     ORDER BY ano, mes;
     `
 
-	err := s.db.Raw(query).Scan(&results).Error
+	year, month := monthExprs("dd", filter)
+	conditions, args := andMetricsFilter("dd", filter)
+	err := s.metricsDB(ctx).Raw(fmt.Sprintf(query, year, month, conditions, zoneOffsetsJoin("dd", filter)), args...).Scan(&results).Error
 	return results, err
 }
 
-// Retorna o total de tickets por prioridade e mês
-func (s *Internal) GetTicketsByPriorityAndMonth() ([]struct {
-	NomePrioridades string `gorm:"column:nome_prioridades"`
-	Ano             int    `gorm:"column:ano"`
-	Janeiro         int    `gorm:"column:janeiro"`
-	Fevereiro       int    `gorm:"column:fevereiro"`
-	Marco           int    `gorm:"column:marco"`
-	Abril           int    `gorm:"column:abril"`
-	Maio            int    `gorm:"column:maio"`
-	Junho           int    `gorm:"column:junho"`
-	Julho           int    `gorm:"column:julho"`
-	Agosto          int    `gorm:"column:agosto"`
-	Setembro        int    `gorm:"column:setembro"`
-	Outubro         int    `gorm:"column:outubro"`
-	Novembro        int    `gorm:"column:novembro"`
-	Dezembro        int    `gorm:"column:dezembro"`
-}, error) {
-	var results []struct {
-		NomePrioridades string `gorm:"column:nome_prioridades"`
-		Ano             int    `gorm:"column:ano"`
-		Janeiro         int    `gorm:"column:janeiro"`
-		Fevereiro       int    `gorm:"column:fevereiro"`
-		Marco           int    `gorm:"column:marco"`
-		Abril           int    `gorm:"column:abril"`
-		Maio            int    `gorm:"column:maio"`
-		Junho           int    `gorm:"column:junho"`
-		Julho           int    `gorm:"column:julho"`
-		Agosto          int    `gorm:"column:agosto"`
-		Setembro        int    `gorm:"column:setembro"`
-		Outubro         int    `gorm:"column:outubro"`
-		Novembro        int    `gorm:"column:novembro"`
-		Dezembro        int    `gorm:"column:dezembro"`
-	}
+// Retorna o total de tickets por prioridade e mês, com os meses contados no fuso do filtro
+func (s *Internal) GetTicketsByPriorityAndMonth(ctx context.Context, filter dto.MetricsFilter) ([]PriorityMonthCounts, error) {
+	var results []PriorityMonthCounts
 
 	query := `
     WITH Counts AS (
         SELECT
             dp.Name AS prioridades,
-            dd.Year,
-            dd.Month AS monthnum,
+            %[1]s AS [Year],
+            %[2]s AS monthnum,
             COUNT(*) AS cnt
         FROM dbo.Fact_Tickets ft
         JOIN DW.dbo.Dim_Dates dd
             ON ft.EntryDateKey = dd.DateKey
+        %[4]s
         JOIN DW.dbo.Dim_Priorities dp
             ON ft.PriorityKey = dp.PriorityKey
-        GROUP BY dp.Name, dd.Year, dd.Month
+        WHERE 1 = 1
+        %[3]s
+        GROUP BY dp.Name, %[1]s, %[2]s
     ),
     Pivoted AS (
         SELECT
@@ -370,6 +376,18 @@ func (s *Internal) GetTicketsByPriorityAndMonth() ([]struct {
     ORDER BY prioridades, [Year];
     `
 
-	err := s.db.Raw(query).Scan(&results).Error
+	year, month := monthExprs("dd", filter)
+	conditions, args := metricsWhere("dd", filter).And()
+	err := s.metricsDB(ctx).Raw(fmt.Sprintf(query, year, month, conditions, zoneOffsetsJoin("dd", filter)), args...).Scan(&results).Error
 	return results, err
 }
+
+// Transaction executa fn em uma transação: o Internal recebido grava pela conexão da transação, que é
+// confirmada quando fn retorna nil e desfeita quando retorna erro
+func (s *Internal) Transaction(ctx context.Context, fn func(tx *Internal) error) error {
+	return s.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		tx := *s
+		tx.db = db
+		return fn(&tx)
+	})
+}
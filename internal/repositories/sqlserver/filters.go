@@ -0,0 +1,101 @@
+package sqlserver
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/sqlserver/sqlfilter"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const filterDateLayout = "2006-01-02"
+
+// dateConditions monta as condições do filtro de período sobre a dimensão de datas informada, com os
+// dias e o ano contados no fuso do filtro. Os limites locais são convertidos para UTC antes, então as
+// condições comparam as colunas da dimensão (em UTC) com constantes, sem conversão de fuso por linha, e o
+// Year restringe a busca antes da comparação da data e hora.
+func dateConditions(alias string, filter dto.MetricsFilter) *sqlfilter.Where {
+	where := sqlfilter.New()
+
+	from, to := dateBounds(filter, FilterLocation(filter))
+	if from != nil {
+		where.Compare(alias+".Year", ">=", from.Year())
+		where.Compare(utcDateTimeExpr(alias), ">=", from.Format(sqlDateTimeLayout))
+	}
+	if to != nil {
+		where.Compare(alias+".Year", "<=", to.Add(-time.Minute).Year())
+		where.Compare(utcDateTimeExpr(alias), "<", to.Format(sqlDateTimeLayout))
+	}
+
+	return where
+}
+
+// dimensionConditions monta as condições de empresa, departamento do agente, canal e prioridade sobre as chaves de ft.
+// Subconsultas evitam conflito com os joins de dimensão já feitos pelas consultas.
+func dimensionConditions(filter dto.MetricsFilter) *sqlfilter.Where {
+	where := sqlfilter.New()
+
+	if filter.Company != nil {
+		where.Add("ft.CompanyKey IN (SELECT CompanyKey FROM dbo.Dim_Companies WHERE Name = ?)", *filter.Company)
+	}
+	if filter.Department != nil {
+		where.Add("ft.AgentKey IN (SELECT AgentKey FROM dbo.Dim_Agents WHERE DepartmentName = ?)", *filter.Department)
+	}
+	if filter.Channel != nil {
+		where.Add("ft.ChannelKey IN (SELECT ChannelKey FROM dbo.Dim_Channel WHERE ChannelName = ?)", *filter.Channel)
+	}
+	if filter.Priority != nil {
+		where.Add("ft.PriorityKey IN (SELECT PriorityKey FROM dbo.Dim_Priorities WHERE Name = ?)", *filter.Priority)
+	}
+	if filter.CompanyScoped {
+		if companies := companyKeys(filter.Companies); len(companies) > 0 {
+			where.Add("ft.CompanyKey IN (SELECT CompanyKey FROM dbo.Dim_Companies WHERE CompanyId_BK IN ?)", companies)
+		} else {
+			where.Never()
+		}
+	}
+
+	return where
+}
+
+// companyKeys converte os IDs de empresa do escopo para o tipo de CompanyId_BK, descartando os inválidos
+func companyKeys(companies []string) []int64 {
+	keys := make([]int64, 0, len(companies))
+	for _, company := range companies {
+		if key, err := strconv.ParseInt(company, 10, 64); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// metricsWhere monta o filtro de métricas completo (período e dimensões), usando o alias informado
+// para a dimensão de datas de abertura
+func metricsWhere(dateAlias string, filter dto.MetricsFilter) *sqlfilter.Where {
+	return dateConditions(dateAlias, filter).Merge(dimensionConditions(filter))
+}
+
+// andMetricsFilter retorna o filtro pronto para ser anexado a uma cláusula WHERE de SQL puro
+func andMetricsFilter(dateAlias string, filter dto.MetricsFilter) (string, []interface{}) {
+	return metricsWhere(dateAlias, filter).And()
+}
+
+// withMetricsFilter aplica o filtro às consultas sobre dbo.Fact_Tickets ft
+func withMetricsFilter(filter dto.MetricsFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if dates := dateConditions("fd", filter); !dates.Empty() {
+			conditions, args := dates.SQL()
+			db = db.
+				Joins("INNER JOIN DW.dbo.Dim_Dates fd ON ft.EntryDateKey = fd.DateKey").
+				Where(conditions, args...)
+		}
+
+		if dimensions := dimensionConditions(filter); !dimensions.Empty() {
+			conditions, args := dimensions.SQL()
+			db = db.Where(conditions, args...)
+		}
+
+		return db
+	}
+}
@@ -0,0 +1,192 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/models/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EnsureScheduledJob retorna a configuração persistida da rotina, gravando defaults quando ela ainda não existe
+func (s *Internal) EnsureScheduledJob(ctx context.Context, defaults entities.ScheduledJob) (*entities.ScheduledJob, error) {
+	var job entities.ScheduledJob
+	err := s.db.WithContext(ctx).
+		Table("dbo.ScheduledJobs").
+		Where("Name = ?", defaults.Name).
+		Attrs(defaults).
+		FirstOrCreate(&job).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled job %s: %w", defaults.Name, err)
+	}
+
+	return &job, nil
+}
+
+// UpdateScheduledJob altera a configuração de uma rotina
+func (s *Internal) UpdateScheduledJob(ctx context.Context, job *entities.ScheduledJob) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.ScheduledJobs").
+		Where("Name = ?", job.Name).
+		Updates(map[string]interface{}{
+			"Enabled":         job.Enabled,
+			"IntervalMinutes": job.IntervalMinutes,
+			"RetentionDays":   job.RetentionDays,
+			"Policy":          job.Policy,
+			"UpdatedAt":       time.Now().UTC(),
+			"UpdatedBy":       job.UpdatedBy,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update scheduled job: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("scheduled job not found")
+	}
+
+	return nil
+}
+
+// MarkScheduledJobRun grava o horário da última execução de uma rotina
+func (s *Internal) MarkScheduledJobRun(ctx context.Context, name string, ranAt time.Time) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.ScheduledJobs").
+		Where("Name = ?", name).
+		Update("LastRunAt", ranAt)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark scheduled job run: %w", result.Error)
+	}
+
+	return nil
+}
+
+// CreateJobRun registra o início de uma execução
+func (s *Internal) CreateJobRun(ctx context.Context, run *entities.JobRun) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.JobRuns").
+		Create(run)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to create job run: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FinishJobRun registra o resultado de uma execução
+func (s *Internal) FinishJobRun(ctx context.Context, run *entities.JobRun) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.JobRuns").
+		Where("Id = ?", run.Id).
+		Updates(map[string]interface{}{
+			"FinishedAt":   run.FinishedAt,
+			"Success":      run.Success,
+			"Affected":     run.Affected,
+			"ErrorMessage": run.ErrorMessage,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to finish job run: %w", result.Error)
+	}
+
+	return nil
+}
+
+// ListJobRuns retorna o histórico de execuções de uma rotina, da mais recente para a mais antiga, com paginação
+func (s *Internal) ListJobRuns(ctx context.Context, name string, offset, limit int) ([]entities.JobRun, int64, error) {
+	query := s.db.WithContext(ctx).
+		Table("dbo.JobRuns").
+		Where("JobName = ?", name)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count job runs: %w", err)
+	}
+
+	var runs []entities.JobRun
+	err := query.
+		Order("StartedAt DESC").
+		Order("Id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&runs).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get job runs: %w", err)
+	}
+
+	return runs, total, nil
+}
+
+// deletedUsersBefore seleciona os usuários removidos (soft delete) há mais tempo que o corte
+func (s *Internal) deletedUsersBefore(ctx context.Context, cutoff time.Time) *gorm.DB {
+	return s.db.WithContext(ctx).
+		Table("dbo.tb_users").
+		Select("Id").
+		Where("IsActive = ? AND Email IS NULL AND UpdatedAt < ?", false, cutoff)
+}
+
+// PurgeDeletedUsersAuthLogs remove os logs de autenticação dos usuários removidos antes do corte
+func (s *Internal) PurgeDeletedUsersAuthLogs(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Table("dbo.UserAuthLogs").
+		Where("UserId IN (?)", s.deletedUsersBefore(ctx, cutoff)).
+		Delete(&entities.UserAuthLog{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge auth logs: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// AnonymizeDeletedUsersAuthLogs remove IP, user agent e mensagem de erro dos logs de autenticação
+// dos usuários removidos antes do corte. Só altera as linhas que ainda têm esses dados.
+func (s *Internal) AnonymizeDeletedUsersAuthLogs(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Table("dbo.UserAuthLogs").
+		Where("UserId IN (?)", s.deletedUsersBefore(ctx, cutoff)).
+		Where("IPAddress IS NOT NULL OR UserAgent IS NOT NULL OR ErrorMessage IS NOT NULL").
+		Updates(map[string]interface{}{
+			"IPAddress":    nil,
+			"UserAgent":    nil,
+			"ErrorMessage": nil,
+		})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to anonymize auth logs: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// AnonymizeDeletedUsersAuditLogs apaga os dados pessoais que a trilha de auditoria guarda dos usuários
+// removidos antes do corte: os snapshots do próprio usuário e o IP das ações que ele executou.
+// A entrada continua registrando quem fez o quê e quando.
+func (s *Internal) AnonymizeDeletedUsersAuditLogs(ctx context.Context, cutoff time.Time) (int64, error) {
+	snapshots := s.db.WithContext(ctx).
+		Table("dbo.AuditLogs").
+		Where("EntityType = ? AND EntityId IN (?)", "USER",
+			s.deletedUsersBefore(ctx, cutoff).Select("CAST(Id AS nvarchar(100))")).
+		Where("[Before] IS NOT NULL OR [After] IS NOT NULL").
+		Updates(map[string]interface{}{
+			"Before": nil,
+			"After":  nil,
+		})
+	if snapshots.Error != nil {
+		return 0, fmt.Errorf("failed to anonymize audit snapshots: %w", snapshots.Error)
+	}
+
+	actions := s.db.WithContext(ctx).
+		Table("dbo.AuditLogs").
+		Where("ActorId IN (?)", s.deletedUsersBefore(ctx, cutoff)).
+		Where("IPAddress IS NOT NULL").
+		Update("IPAddress", nil)
+	if actions.Error != nil {
+		return snapshots.RowsAffected, fmt.Errorf("failed to anonymize audit actors: %w", actions.Error)
+	}
+
+	return snapshots.RowsAffected + actions.RowsAffected, nil
+}
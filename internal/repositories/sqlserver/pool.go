@@ -0,0 +1,137 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"orderstreamrest/internal/apperror"
+	"os"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaxOpenConns    = 25
+	defaultMaxIdleConns    = 10
+	defaultConnMaxLifetime = 30 * time.Minute
+	defaultConnMaxIdleTime = 5 * time.Minute
+	defaultQueryTimeout    = 30 * time.Second
+
+	// cancelInstanceKey guarda a função que libera o prazo da operação entre os callbacks
+	cancelInstanceKey = "timeout:cancel"
+)
+
+// PoolConfig são os limites do pool de conexões e o prazo de cada consulta
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	QueryTimeout    time.Duration
+}
+
+// LoadPoolConfig lê a configuração do pool das variáveis de ambiente, com os padrões para as ausentes ou inválidas
+func LoadPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    envInt("SQLSERVER_MAX_OPEN_CONNS", defaultMaxOpenConns),
+		MaxIdleConns:    envInt("SQLSERVER_MAX_IDLE_CONNS", defaultMaxIdleConns),
+		ConnMaxLifetime: time.Duration(envInt("SQLSERVER_CONN_MAX_LIFETIME_SECONDS", int(defaultConnMaxLifetime/time.Second))) * time.Second,
+		ConnMaxIdleTime: time.Duration(envInt("SQLSERVER_CONN_MAX_IDLE_SECONDS", int(defaultConnMaxIdleTime/time.Second))) * time.Second,
+		QueryTimeout:    time.Duration(envInt("SQLSERVER_QUERY_TIMEOUT_MS", int(defaultQueryTimeout/time.Millisecond))) * time.Millisecond,
+	}
+}
+
+// envInt lê um inteiro não negativo da variável de ambiente
+func envInt(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// applyPool aplica os limites ao pool de conexões
+func applyPool(sqlDB *sql.DB, pool PoolConfig) {
+	sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+}
+
+// PoolStats retorna as estatísticas atuais do pool de conexões
+func (s *Internal) PoolStats() (sql.DBStats, error) {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
+
+// registerQueryTimeout limita cada operação do GORM a timeout, para que uma consulta lenta ao DW
+// não segure conexões do pool. Um prazo menor já presente no contexto continua valendo; 0 desativa.
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	callbacks := db.Callback()
+
+	register := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range register {
+		if err := r.before("timeout:before_"+r.operation, startTimeout(timeout)); err != nil {
+			return err
+		}
+		// Em Row/Rows (inclusive Scan) as linhas são lidas depois do callback, então o prazo
+		// não é liberado ao fim dele: vale até a leitura terminar ou o tempo acabar
+		if err := r.after("timeout:after_"+r.operation, endTimeout(r.operation != "row")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startTimeout coloca o prazo da operação no contexto do statement
+func startTimeout(timeout time.Duration) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(db.Statement.Context, timeout)
+		db.Statement.Context = ctx
+		db.InstanceSet(cancelInstanceKey, cancel)
+	}
+}
+
+// endTimeout marca o estouro do prazo como indisponibilidade (503) e, quando release, libera o prazo
+func endTimeout(release bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil && errors.Is(db.Error, context.DeadlineExceeded) {
+			db.Error = apperror.Wrap(apperror.ErrUnavailable, "sql server query timed out", db.Error)
+		}
+
+		if !release {
+			return
+		}
+		if value, ok := db.InstanceGet(cancelInstanceKey); ok {
+			if cancel, ok := value.(context.CancelFunc); ok {
+				cancel()
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/entities"
+
+	"gorm.io/gorm"
+)
+
+// ErrQueuedJobNotFound é retornado quando a tarefa da fila não existe
+var ErrQueuedJobNotFound error = apperror.New(apperror.ErrNotFound, "queued job not found")
+
+// CreateQueuedJob grava uma nova tarefa da fila de processamento assíncrono
+func (s *Internal) CreateQueuedJob(ctx context.Context, job *entities.QueuedJob) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.QueuedJobs").
+		Create(job)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to create queued job: %w", result.Error)
+	}
+
+	return nil
+}
+
+// GetQueuedJob busca uma tarefa da fila pelo id
+func (s *Internal) GetQueuedJob(ctx context.Context, id string) (*entities.QueuedJob, error) {
+	var job entities.QueuedJob
+	err := s.db.WithContext(ctx).
+		Table("dbo.QueuedJobs").
+		Where("Id = ?", id).
+		First(&job).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQueuedJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queued job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// UpdateQueuedJob grava a situação, as tentativas e o resultado de uma tarefa da fila
+func (s *Internal) UpdateQueuedJob(ctx context.Context, job *entities.QueuedJob) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.QueuedJobs").
+		Where("Id = ?", job.Id).
+		Updates(map[string]interface{}{
+			"Status":       job.Status,
+			"Attempts":     job.Attempts,
+			"Result":       job.Result,
+			"ErrorMessage": job.ErrorMessage,
+			"StartedAt":    job.StartedAt,
+			"FinishedAt":   job.FinishedAt,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update queued job: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrQueuedJobNotFound
+	}
+
+	return nil
+}
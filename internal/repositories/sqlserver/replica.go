@@ -0,0 +1,97 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"os"
+
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// metricsResolver é o resolver das consultas de métricas e do DW, que leem da réplica quando configurada
+const metricsResolver = "metrics"
+
+// replicaDSN monta a conexão da réplica somente leitura a partir de SQLSERVER_REPLICA_HOST e
+// SQLSERVER_REPLICA_PORT, com as mesmas credenciais e banco do primário. Vazio quando não há réplica.
+func replicaDSN() string {
+	host := os.Getenv("SQLSERVER_REPLICA_HOST")
+	if host == "" {
+		return ""
+	}
+
+	port := os.Getenv("SQLSERVER_REPLICA_PORT")
+	if port == "" {
+		port = os.Getenv("SQLSERVER_PORT")
+	}
+
+	query := url.Values{}
+	query.Set("database", os.Getenv("SQLSERVER_DATABASE"))
+	// Em réplicas de leitura do Azure SQL (mesmo host) é o ApplicationIntent que direciona para a cópia
+	query.Set("ApplicationIntent", "ReadOnly")
+
+	dsn := url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(os.Getenv("SQLSERVER_USERNAME"), os.Getenv("SQLSERVER_PASSWORD")),
+		Host:     host + ":" + port,
+		RawQuery: query.Encode(),
+	}
+	return dsn.String()
+}
+
+// registerReplica abre a réplica com os mesmos limites de pool do primário e a registra no resolver
+// de métricas. Sem réplica configurada retorna nil e as métricas continuam no primário.
+func registerReplica(db *gorm.DB, pool PoolConfig) (*sql.DB, error) {
+	dsn := replicaDSN()
+	if dsn == "" {
+		return nil, nil
+	}
+
+	replica, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		return nil, err
+	}
+	applyPool(replica, pool)
+
+	if err := replica.Ping(); err != nil {
+		replica.Close()
+		return nil, err
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{sqlserver.New(sqlserver.Config{Conn: replica})},
+	}, metricsResolver)
+	if err := db.Use(resolver); err != nil {
+		replica.Close()
+		return nil, err
+	}
+
+	return replica, nil
+}
+
+// metricsDB retorna a sessão das consultas de métricas: lê da réplica quando configurada e passa
+// pelo circuito das métricas. A leitura é forçada porque as consultas com CTE (WITH ...) seriam
+// tratadas como escrita.
+func (s *Internal) metricsDB(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx).Clauses(dbresolver.Use(metricsResolver), dbresolver.Read).Set(metricsBreakerKey, true)
+}
+
+// HasReplica indica se as métricas estão sendo lidas de uma réplica
+func (s *Internal) HasReplica() bool {
+	return s.replica != nil
+}
+
+// PingReplica verifica a conexão com a réplica de leitura
+func (s *Internal) PingReplica(ctx context.Context) error {
+	return s.replica.PingContext(ctx)
+}
+
+// ReplicaPoolStats retorna as estatísticas do pool da réplica; false quando não há réplica
+func (s *Internal) ReplicaPoolStats() (sql.DBStats, bool) {
+	if s.replica == nil {
+		return sql.DBStats{}, false
+	}
+	return s.replica.Stats(), true
+}
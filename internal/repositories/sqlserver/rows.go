@@ -0,0 +1,121 @@
+package sqlserver
+
+import (
+	"orderstreamrest/internal/models/entities"
+	"time"
+)
+
+// CategoryTotal representa o total de tickets de uma categoria
+type CategoryTotal struct {
+	entities.Dim_Categories
+	Total int64
+}
+
+// PriorityTotal representa o total de tickets de uma prioridade
+type PriorityTotal struct {
+	entities.Dim_Priorities
+	Total int64
+}
+
+// ChannelTotal representa o total de tickets de um canal
+type ChannelTotal struct {
+	entities.Dim_Channel
+	Total int64
+}
+
+// TagTotal representa o total de tickets de uma tag
+type TagTotal struct {
+	entities.Dim_Tags
+	Total int64
+}
+
+// DepartmentTotal representa o total de tickets de um departamento
+type DepartmentTotal struct {
+	entities.Dim_Companies
+	Total int64
+}
+
+// ResolutionTimeByPriority representa o tempo médio de resolução de uma prioridade, com a mediana e os percentis
+type ResolutionTimeByPriority struct {
+	NomePrioridade        string  `gorm:"column:nome_prioridade"`
+	MediaResolucaoHoras   float64 `gorm:"column:media_resolucao_horas"`
+	MediaResolucaoDias    float64 `gorm:"column:media_resolucao_dias"`
+	MedianaResolucaoHoras float64 `gorm:"column:mediana_resolucao_horas"`
+	P90ResolucaoHoras     float64 `gorm:"column:p90_resolucao_horas"`
+	P99ResolucaoHoras     float64 `gorm:"column:p99_resolucao_horas"`
+}
+
+// ResolutionTimePercentiles representa a distribuição do tempo de resolução, em horas, de um valor de dimensão
+type ResolutionTimePercentiles struct {
+	Name      string  `gorm:"column:name"`
+	Tickets   int64   `gorm:"column:tickets"`
+	MeanHours float64 `gorm:"column:mean_hours"`
+	P50Hours  float64 `gorm:"column:p50_hours"`
+	P90Hours  float64 `gorm:"column:p90_hours"`
+	P99Hours  float64 `gorm:"column:p99_hours"`
+}
+
+// StatusMonthCounts representa a contagem mensal de tickets de um status em um ano
+type StatusMonthCounts struct {
+	NomeStatus string `gorm:"column:nome_status"`
+	Ano        int    `gorm:"column:ano"`
+	Janeiro    int    `gorm:"column:janeiro"`
+	Fevereiro  int    `gorm:"column:fevereiro"`
+	Marco      int    `gorm:"column:marco"`
+	Abril      int    `gorm:"column:abril"`
+	Maio       int    `gorm:"column:maio"`
+	Junho      int    `gorm:"column:junho"`
+	Julho      int    `gorm:"column:julho"`
+	Agosto     int    `gorm:"column:agosto"`
+	Setembro   int    `gorm:"column:setembro"`
+	Outubro    int    `gorm:"column:outubro"`
+	Novembro   int    `gorm:"column:novembro"`
+	Dezembro   int    `gorm:"column:dezembro"`
+}
+
+// MonthTotal representa o total de tickets de um mês
+type MonthTotal struct {
+	Ano          int `gorm:"column:ano"`
+	Mes          int `gorm:"column:mes"`
+	TotalTickets int `gorm:"column:total_tickets"`
+}
+
+// PriorityMonthCounts representa a contagem mensal de tickets de uma prioridade em um ano
+type PriorityMonthCounts struct {
+	NomePrioridades string `gorm:"column:nome_prioridades"`
+	Ano             int    `gorm:"column:ano"`
+	Janeiro         int    `gorm:"column:janeiro"`
+	Fevereiro       int    `gorm:"column:fevereiro"`
+	Marco           int    `gorm:"column:marco"`
+	Abril           int    `gorm:"column:abril"`
+	Maio            int    `gorm:"column:maio"`
+	Junho           int    `gorm:"column:junho"`
+	Julho           int    `gorm:"column:julho"`
+	Agosto          int    `gorm:"column:agosto"`
+	Setembro        int    `gorm:"column:setembro"`
+	Outubro         int    `gorm:"column:outubro"`
+	Novembro        int    `gorm:"column:novembro"`
+	Dezembro        int    `gorm:"column:dezembro"`
+}
+
+// AgentTickets representa os tickets atribuídos a um agente ativo
+type AgentTickets struct {
+	AgentId      int64  `gorm:"column:agent_id"`
+	FullName     string `gorm:"column:full_name"`
+	Department   string `gorm:"column:department"`
+	OpenTickets  int64  `gorm:"column:open_tickets"`
+	TotalTickets int64  `gorm:"column:total_tickets"`
+}
+
+// PeriodTotal representa o total de tickets abertos em uma semana ou mês, identificado pelo primeiro dia
+type PeriodTotal struct {
+	PeriodStart time.Time `gorm:"column:period_start"`
+	Total       int64     `gorm:"column:total"`
+}
+
+// TrendMover representa os tickets de um valor de dimensão no período atual e no anterior
+type TrendMover struct {
+	Name     string `gorm:"column:name"`
+	Current  int64  `gorm:"column:current_total"`
+	Previous int64  `gorm:"column:previous_total"`
+}
@@ -0,0 +1,140 @@
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSavedSearchNotFound é retornado quando a busca não existe ou pertence a outro usuário
+	ErrSavedSearchNotFound error = apperror.New(apperror.ErrNotFound, "saved search not found")
+	// ErrSavedSearchNameInUse é retornado quando o usuário já tem uma busca com o mesmo nome
+	ErrSavedSearchNameInUse error = apperror.New(apperror.ErrConflict, "a saved search with this name already exists")
+)
+
+// CreateSavedSearch grava uma nova busca salva
+func (s *Internal) CreateSavedSearch(ctx context.Context, search *entities.SavedSearch) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.SavedSearches").
+		Create(search)
+
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return apperror.Wrap(apperror.ErrConflict, ErrSavedSearchNameInUse.Error(), result.Error)
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to create saved search: %w", result.Error)
+	}
+
+	return nil
+}
+
+// ListSavedSearches retorna as buscas salvas do usuário em ordem alfabética
+func (s *Internal) ListSavedSearches(ctx context.Context, userId int) ([]entities.SavedSearch, error) {
+	var searches []entities.SavedSearch
+	err := s.db.WithContext(ctx).
+		Table("dbo.SavedSearches").
+		Where("UserId = ?", userId).
+		Order("Name").
+		Find(&searches).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+
+	return searches, nil
+}
+
+// GetSavedSearch busca uma busca salva do usuário
+func (s *Internal) GetSavedSearch(ctx context.Context, userId, id int) (*entities.SavedSearch, error) {
+	var search entities.SavedSearch
+	err := s.db.WithContext(ctx).
+		Table("dbo.SavedSearches").
+		Where("Id = ? AND UserId = ?", id, userId).
+		First(&search).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSavedSearchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	return &search, nil
+}
+
+// UpdateSavedSearch atualiza nome, consulta e layout de uma busca salva do usuário
+func (s *Internal) UpdateSavedSearch(ctx context.Context, search *entities.SavedSearch) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.SavedSearches").
+		Where("Id = ? AND UserId = ?", search.Id, search.UserId).
+		Updates(map[string]interface{}{
+			"Name":      search.Name,
+			"Query":     search.Query,
+			"Layout":    search.Layout,
+			"UpdatedAt": time.Now().UTC(),
+		})
+
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return apperror.Wrap(apperror.ErrConflict, ErrSavedSearchNameInUse.Error(), result.Error)
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to update saved search: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSavedSearchNotFound
+	}
+
+	return nil
+}
+
+// DeleteSavedSearch remove uma busca salva do usuário
+func (s *Internal) DeleteSavedSearch(ctx context.Context, userId, id int) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.SavedSearches").
+		Where("Id = ? AND UserId = ?", id, userId).
+		Delete(&entities.SavedSearch{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete saved search: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSavedSearchNotFound
+	}
+
+	return nil
+}
+
+// CountUserSavedSearches retorna a quantidade de buscas salvas de um usuário
+func (s *Internal) CountUserSavedSearches(ctx context.Context, userId int) (int64, error) {
+	var total int64
+	err := s.db.WithContext(ctx).
+		Table("dbo.SavedSearches").
+		Where("UserId = ?", userId).
+		Count(&total).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count saved searches: %w", err)
+	}
+
+	return total, nil
+}
+
+// DeleteUserSavedSearches remove as buscas salvas de um usuário
+func (s *Internal) DeleteUserSavedSearches(ctx context.Context, userId int) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Table("dbo.SavedSearches").
+		Where("UserId = ?", userId).
+		Delete(&entities.SavedSearch{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete saved searches: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
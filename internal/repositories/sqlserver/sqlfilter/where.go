@@ -0,0 +1,103 @@
+// Package sqlfilter monta cláusulas WHERE parametrizadas para as consultas em SQL puro do DW.
+//
+// As expressões (colunas, funções, subconsultas) vêm sempre do código; os valores vindos das
+// requisições entram apenas como parâmetros (?), nunca concatenados ao SQL.
+package sqlfilter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// operators são os operadores de comparação aceitos por Compare
+var operators = map[string]bool{"=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// Where acumula as condições de uma cláusula WHERE e os seus parâmetros, na ordem dos placeholders.
+// O valor zero é um filtro vazio, pronto para uso.
+type Where struct {
+	conditions []string
+	args       []interface{}
+}
+
+// New cria um filtro vazio
+func New() *Where {
+	return &Where{}
+}
+
+// Add acrescenta uma condição com os seus parâmetros. A quantidade de ? na condição precisa ser igual
+// à de args; a diferença é um erro de programação e gera panic.
+func (w *Where) Add(condition string, args ...interface{}) *Where {
+	if placeholders := strings.Count(condition, "?"); placeholders != len(args) {
+		panic(fmt.Sprintf("sqlfilter: condition %q has %d placeholders and %d args", condition, placeholders, len(args)))
+	}
+
+	w.conditions = append(w.conditions, condition)
+	w.args = append(w.args, args...)
+	return w
+}
+
+// Compare acrescenta "expr op ?" com o valor como parâmetro. op precisa ser um dos operadores de
+// comparação (=, <>, <, <=, >, >=).
+func (w *Where) Compare(expr, op string, value interface{}) *Where {
+	if !operators[op] {
+		panic(fmt.Sprintf("sqlfilter: unsupported operator %q", op))
+	}
+	return w.Add(expr+" "+op+" ?", value)
+}
+
+// Equals acrescenta "expr = ?"
+func (w *Where) Equals(expr string, value interface{}) *Where {
+	return w.Compare(expr, "=", value)
+}
+
+// In acrescenta "expr IN ?" com a lista como parâmetro, que o GORM expande em (?, ?, ...). Uma lista
+// vazia não casa com nenhuma linha ("1 = 0"), em vez de gerar um IN () inválido.
+func (w *Where) In(expr string, values interface{}) *Where {
+	list := reflect.ValueOf(values)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		panic(fmt.Sprintf("sqlfilter: In expects a slice, got %T", values))
+	}
+	if list.Len() == 0 {
+		return w.Never()
+	}
+	return w.Add(expr+" IN ?", values)
+}
+
+// Never acrescenta uma condição que nenhuma linha satisfaz, para escopos vazios
+func (w *Where) Never() *Where {
+	return w.Add("1 = 0")
+}
+
+// Merge acrescenta as condições e os parâmetros de outro filtro
+func (w *Where) Merge(other *Where) *Where {
+	if other == nil {
+		return w
+	}
+	w.conditions = append(w.conditions, other.conditions...)
+	w.args = append(w.args, other.args...)
+	return w
+}
+
+// Empty indica se o filtro não tem condições
+func (w *Where) Empty() bool {
+	return len(w.conditions) == 0
+}
+
+// SQL retorna as condições unidas por AND, sem o WHERE, e os parâmetros na ordem dos placeholders
+func (w *Where) SQL() (string, []interface{}) {
+	if w.Empty() {
+		return "", nil
+	}
+	return strings.Join(w.conditions, " AND "), w.args
+}
+
+// And retorna as condições prontas para serem anexadas a um "WHERE ..." existente ("AND a AND b"),
+// ou uma string vazia quando não há condições
+func (w *Where) And() (string, []interface{}) {
+	sql, args := w.SQL()
+	if sql == "" {
+		return "", nil
+	}
+	return "AND " + sql, args
+}
@@ -0,0 +1,89 @@
+package sqlfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhere(t *testing.T) {
+	tests := []struct {
+		name         string
+		build        func() *Where
+		expectedSQL  string
+		expectedArgs []interface{}
+	}{
+		{
+			name:  "empty filter",
+			build: New,
+		},
+		{
+			name: "comparisons in order",
+			build: func() *Where {
+				return New().
+					Compare("dd.Day", ">=", "2025-01-01").
+					Compare("dd.Day", "<=", "2025-01-31").
+					Equals("dd.Year", 2025)
+			},
+			expectedSQL:  "dd.Day >= ? AND dd.Day <= ? AND dd.Year = ?",
+			expectedArgs: []interface{}{"2025-01-01", "2025-01-31", 2025},
+		},
+		{
+			name: "values are never concatenated",
+			build: func() *Where {
+				return New().Add("ft.ChannelKey IN (SELECT ChannelKey FROM dbo.Dim_Channel WHERE ChannelName = ?)", "Email' OR 1=1 --")
+			},
+			expectedSQL:  "ft.ChannelKey IN (SELECT ChannelKey FROM dbo.Dim_Channel WHERE ChannelName = ?)",
+			expectedArgs: []interface{}{"Email' OR 1=1 --"},
+		},
+		{
+			name:         "in list",
+			build:        func() *Where { return New().In("ft.CompanyKey", []int64{1, 2}) },
+			expectedSQL:  "ft.CompanyKey IN ?",
+			expectedArgs: []interface{}{[]int64{1, 2}},
+		},
+		{
+			name:        "empty in list matches nothing",
+			build:       func() *Where { return New().In("ft.CompanyKey", []int64{}) },
+			expectedSQL: "1 = 0",
+		},
+		{
+			name: "merge keeps the placeholder order",
+			build: func() *Where {
+				dimensions := New().Equals("dp.Name", "Alta")
+				return New().Equals("dd.Year", 2025).Merge(dimensions).Merge(nil)
+			},
+			expectedSQL:  "dd.Year = ? AND dp.Name = ?",
+			expectedArgs: []interface{}{2025, "Alta"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := tt.build().SQL()
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestWhere_And(t *testing.T) {
+	sql, args := New().And()
+	assert.Empty(t, sql)
+	assert.Nil(t, args)
+
+	sql, args = New().Equals("dd.Year", 2025).And()
+	assert.Equal(t, "AND dd.Year = ?", sql)
+	assert.Equal(t, []interface{}{2025}, args)
+
+	var zero Where
+	assert.True(t, zero.Empty())
+	assert.False(t, zero.Equals("dd.Year", 2025).Empty())
+}
+
+func TestWhere_RejectsMisuse(t *testing.T) {
+	assert.Panics(t, func() { New().Add("dd.Year = ?") }, "placeholder without value")
+	assert.Panics(t, func() { New().Add("dd.Year = 2025", 2025) }, "value without placeholder")
+	assert.Panics(t, func() { New().Compare("dd.Year", "= 1; DROP TABLE x; --", 2025) }, "unknown operator")
+	assert.Panics(t, func() { New().In("ft.CompanyKey", 1) }, "in without a list")
+}
@@ -0,0 +1,149 @@
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orderstreamrest/internal/models/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TagMerge descreve a unificação de tags: as tags de Sources passam a ser Target
+type TagMerge struct {
+	// Sources são os nomes das tags unificadas, como gravados em dbo.Dim_Tags
+	Sources []string
+	// Aliases são as chaves de comparação das grafias que passam a apontar para Target
+	Aliases []string
+	// Target é o nome canônico; TargetAlias é a sua chave, que deixa de ser alias de outra tag
+	Target      string
+	TargetAlias string
+	CreatedBy   *int
+}
+
+// TagMergeResult resume o que a unificação alterou no DW
+type TagMergeResult struct {
+	// TargetKey é a TagKey da tag canônica; 0 quando nenhuma das tags existe no DW
+	TargetKey int64
+	// Renamed indica que a tag canônica foi renomeada: não existia (e uma das tags unificadas recebeu o
+	// nome) ou existia com outra grafia
+	Renamed bool
+	// MergedTags é a quantidade de tags cujos tickets passaram para a tag canônica
+	MergedTags int
+	// MovedTickets é a quantidade de linhas de dbo.Fact_Tickets que mudaram de tag
+	MovedTickets int64
+}
+
+// ListTagTotals retorna todas as tags de dbo.Dim_Tags com o total de tickets de cada uma, inclusive as sem tickets
+func (s *Internal) ListTagTotals(ctx context.Context) ([]TagTotal, error) {
+	var results []TagTotal
+	err := s.db.WithContext(ctx).
+		Table("dbo.Dim_Tags dt").
+		Select("dt.TagKey, dt.Name, dt.TagId_BK, ISNULL(SUM(ft.QtTickets), 0) AS Total").
+		Joins("LEFT JOIN dbo.Fact_Tickets ft ON ft.TagKey = dt.TagKey").
+		Group("dt.TagKey, dt.Name, dt.TagId_BK").
+		Order("dt.Name").
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return results, nil
+}
+
+// ListTagAliases retorna os aliases de tags cadastrados
+func (s *Internal) ListTagAliases(ctx context.Context) ([]entities.TagAlias, error) {
+	var aliases []entities.TagAlias
+	err := s.db.WithContext(ctx).
+		Table("dbo.TagAliases").
+		Order("TagName, Alias").
+		Find(&aliases).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tag aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// MergeTags unifica as tags em uma transação: os tickets das tags de origem passam para a tag canônica
+// (que é criada renomeando a primeira origem, quando ainda não existe) e as grafias viram aliases dela.
+// As linhas de origem continuam em dbo.Dim_Tags, sem tickets, para não quebrar a carga do DW pela TagId_BK.
+func (s *Internal) MergeTags(ctx context.Context, merge TagMerge) (*TagMergeResult, error) {
+	result := &TagMergeResult{}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var target entities.Dim_Tags
+		err := tx.Table("dbo.Dim_Tags").Where("Name = ?", merge.Target).Order("TagKey").First(&target).Error
+		targetExists := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get tag %s: %w", merge.Target, err)
+		}
+
+		var sources []entities.Dim_Tags
+		query := tx.Table("dbo.Dim_Tags").Where("Name IN ?", merge.Sources)
+		if targetExists {
+			query = query.Where("TagKey <> ?", target.TagKey)
+		}
+		if err := query.Order("TagKey").Find(&sources).Error; err != nil {
+			return fmt.Errorf("failed to get merged tags: %w", err)
+		}
+
+		// A comparação do banco ignora maiúsculas: a tag encontrada pode estar com outra grafia
+		if targetExists && target.Name != merge.Target {
+			if err := tx.Table("dbo.Dim_Tags").Where("TagKey = ?", target.TagKey).Update("Name", merge.Target).Error; err != nil {
+				return fmt.Errorf("failed to rename tag %s: %w", target.Name, err)
+			}
+			result.Renamed = true
+		}
+
+		if !targetExists && len(sources) > 0 {
+			target, sources = sources[0], sources[1:]
+			if err := tx.Table("dbo.Dim_Tags").Where("TagKey = ?", target.TagKey).Update("Name", merge.Target).Error; err != nil {
+				return fmt.Errorf("failed to rename tag %s: %w", target.Name, err)
+			}
+			result.Renamed = true
+		}
+		result.TargetKey = target.TagKey
+
+		if len(sources) > 0 {
+			keys := make([]int64, 0, len(sources))
+			for _, source := range sources {
+				keys = append(keys, source.TagKey)
+			}
+
+			moved := tx.Table("dbo.Fact_Tickets").Where("TagKey IN ?", keys).Update("TagKey", target.TagKey)
+			if moved.Error != nil {
+				return fmt.Errorf("failed to move tickets to tag %s: %w", merge.Target, moved.Error)
+			}
+			result.MergedTags = len(sources)
+			result.MovedTickets = moved.RowsAffected
+		}
+
+		// Aliases das tags unificadas passam a apontar direto para a canônica, sem cadeias
+		if err := tx.Table("dbo.TagAliases").Where("TagName IN ?", merge.Sources).Update("TagName", merge.Target).Error; err != nil {
+			return fmt.Errorf("failed to update tag aliases: %w", err)
+		}
+		if err := tx.Table("dbo.TagAliases").Where("Alias = ?", merge.TargetAlias).Delete(&entities.TagAlias{}).Error; err != nil {
+			return fmt.Errorf("failed to update tag aliases: %w", err)
+		}
+
+		for _, alias := range merge.Aliases {
+			// A condição em struct é copiada para o registro criado, o que não acontece com SQL em texto
+			var saved entities.TagAlias
+			err := tx.Table("dbo.TagAliases").
+				Where(entities.TagAlias{Alias: alias}).
+				Assign(map[string]interface{}{"TagName": merge.Target}).
+				Attrs(entities.TagAlias{CreatedAt: time.Now().UTC(), CreatedBy: merge.CreatedBy}).
+				FirstOrCreate(&saved).Error
+			if err != nil {
+				return fmt.Errorf("failed to save tag alias %s: %w", alias, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,196 @@
+package sqlserver
+
+import (
+	"fmt"
+	"orderstreamrest/internal/models/dto"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sqlDateTimeLayout é o formato ISO 8601 aceito pelo SQL Server independente do idioma da conexão
+	sqlDateTimeLayout = "2006-01-02T15:04:05"
+	// offsetSearchYearsAhead é até quantos anos à frente as mudanças de fuso são procuradas
+	offsetSearchYearsAhead = 2
+)
+
+// offsetSearchStart é a partir de quando as mudanças de fuso são procuradas; antes vale o primeiro deslocamento
+var offsetSearchStart = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// offsetChange é o deslocamento do fuso, em minutos, em vigor a partir de at (UTC)
+type offsetChange struct {
+	at      time.Time
+	minutes int
+}
+
+// offsetChanges guarda as mudanças já calculadas por nome de fuso
+var offsetChanges sync.Map
+
+// utcNow é o relógio do GORM: CreatedAt e UpdatedAt são gravados em UTC
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+// FilterLocation retorna o fuso do filtro, ou UTC quando ele é vazio ou desconhecido
+func FilterLocation(filter dto.MetricsFilter) *time.Location {
+	if filter.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(filter.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// zoneChanges retorna o deslocamento inicial do fuso seguido de cada mudança (horário de verão,
+// mudança de lei...) até offsetSearchYearsAhead anos à frente
+func zoneChanges(loc *time.Location) []offsetChange {
+	if cached, ok := offsetChanges.Load(loc.String()); ok {
+		return cached.([]offsetChange)
+	}
+
+	offset := func(t time.Time) int {
+		_, seconds := t.In(loc).Zone()
+		return seconds / 60
+	}
+
+	changes := []offsetChange{{at: offsetSearchStart, minutes: offset(offsetSearchStart)}}
+	until := time.Now().UTC().AddDate(offsetSearchYearsAhead, 0, 0)
+	for day := offsetSearchStart; day.Before(until); day = day.Add(24 * time.Hour) {
+		current := changes[len(changes)-1].minutes
+		next := day.Add(24 * time.Hour)
+		if offset(next) == current {
+			continue
+		}
+
+		// Busca binária do minuto da mudança dentro do dia
+		lo, hi := day, next
+		for hi.Sub(lo) > time.Minute {
+			mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Minute)
+			if offset(mid) == current {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		changes = append(changes, offsetChange{at: hi, minutes: offset(hi)})
+	}
+
+	offsetChanges.Store(loc.String(), changes)
+	return changes
+}
+
+// utcDateTimeExpr retorna a expressão SQL da data e hora da dimensão de datas alias. A dimensão do DW é
+// carregada em UTC (Year a Minute são o instante UTC de abertura ou fechamento do ticket); todas as
+// conversões de fuso partem dessa premissa.
+func utcDateTimeExpr(alias string) string {
+	return "DATETIMEFROMPARTS(" + alias + ".Year, " + alias + ".Month, " + alias + ".Day, " + alias + ".Hour, " + alias + ".Minute, 0, 0)"
+}
+
+// zoneOffsetsAlias é o alias da tabela de deslocamentos ligada à dimensão de datas alias
+func zoneOffsetsAlias(alias string) string {
+	return alias + "_tz"
+}
+
+// zoneOffsetsJoin retorna o JOIN da dimensão de datas alias com a tabela de deslocamentos do fuso do
+// filtro: uma linha por período [FromUtc, ToUtc) com o deslocamento em minutos em vigor. Assim o
+// deslocamento vem de uma coluna e não de um CASE repetido no SELECT e no GROUP BY. Retorna "" quando o
+// fuso tem um único deslocamento, como UTC. Toda consulta que usa dayExpr ou monthExprs deve incluí-lo.
+func zoneOffsetsJoin(alias string, filter dto.MetricsFilter) string {
+	changes := zoneChanges(FilterLocation(filter))
+	if len(changes) == 1 {
+		return ""
+	}
+
+	var rows strings.Builder
+	for i, change := range changes {
+		from, to := "1900-01-01T00:00:00", "9999-12-31T23:59:59"
+		if i > 0 {
+			from = change.at.Format(sqlDateTimeLayout)
+		}
+		if i < len(changes)-1 {
+			to = changes[i+1].at.Format(sqlDateTimeLayout)
+		}
+		if i > 0 {
+			rows.WriteString(", ")
+		}
+		fmt.Fprintf(&rows, "('%s', '%s', %d)", from, to, change.minutes)
+	}
+
+	tz, utc := zoneOffsetsAlias(alias), utcDateTimeExpr(alias)
+	return fmt.Sprintf("JOIN (VALUES %s) AS %s (FromUtc, ToUtc, OffsetMinutes) ON %s >= %s.FromUtc AND %s < %s.ToUtc",
+		rows.String(), tz, utc, tz, utc, tz)
+}
+
+// localDateTimeExpr retorna a expressão SQL da data e hora no fuso do filtro a partir da dimensão de
+// datas alias. O deslocamento vem da tabela de zoneOffsetsJoin, montada a partir do fuso IANA, de modo
+// que a expressão não depende da tabela de fusos do SQL Server (que só conhece os nomes do Windows).
+func localDateTimeExpr(alias string, loc *time.Location) string {
+	changes := zoneChanges(loc)
+	if len(changes) == 1 {
+		return fmt.Sprintf("DATEADD(MINUTE, %d, %s)", changes[0].minutes, utcDateTimeExpr(alias))
+	}
+	return fmt.Sprintf("DATEADD(MINUTE, %s.OffsetMinutes, %s)", zoneOffsetsAlias(alias), utcDateTimeExpr(alias))
+}
+
+// dateBounds converte startDate, endDate (inclusiva) e year do filtro, contados no fuso loc, no intervalo
+// UTC [from, to). Um limite nil é aberto.
+func dateBounds(filter dto.MetricsFilter, loc *time.Location) (from, to *time.Time) {
+	midnight := func(year int, month time.Month, day int) time.Time {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		// Quando a meia-noite não existe (horário de verão que começa às 00:00), o dia começa no
+		// primeiro instante válido e não no fim do dia anterior
+		for localDate(t, loc).Before(date) {
+			t = t.Add(15 * time.Minute)
+		}
+		return t.UTC()
+	}
+
+	if filter.StartDate != nil {
+		start := midnight(filter.StartDate.Year(), filter.StartDate.Month(), filter.StartDate.Day())
+		from = &start
+	}
+	if filter.EndDate != nil {
+		end := midnight(filter.EndDate.Year(), filter.EndDate.Month(), filter.EndDate.Day()+1)
+		to = &end
+	}
+	if filter.Year != nil {
+		yearStart, yearEnd := midnight(*filter.Year, time.January, 1), midnight(*filter.Year+1, time.January, 1)
+		if from == nil || yearStart.After(*from) {
+			from = &yearStart
+		}
+		if to == nil || yearEnd.Before(*to) {
+			to = &yearEnd
+		}
+	}
+	return from, to
+}
+
+// localDate retorna o dia de t no fuso loc, como data (meia-noite UTC)
+func localDate(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// dayExpr retorna a expressão SQL do dia na dimensão de datas alias, contado no fuso do filtro
+func dayExpr(alias string, filter dto.MetricsFilter) string {
+	loc := FilterLocation(filter)
+	if loc == time.UTC {
+		return "DATEFROMPARTS(" + alias + ".Year, " + alias + ".Month, " + alias + ".Day)"
+	}
+	return "CAST(" + localDateTimeExpr(alias, loc) + " AS date)"
+}
+
+// monthExprs retorna as expressões SQL do ano e do mês na dimensão de datas alias, contados no fuso
+// do filtro. Em UTC são as próprias colunas da dimensão.
+func monthExprs(alias string, filter dto.MetricsFilter) (year, month string) {
+	loc := FilterLocation(filter)
+	if loc == time.UTC {
+		return alias + ".Year", alias + ".Month"
+	}
+	local := localDateTimeExpr(alias, loc)
+	return "YEAR(" + local + ")", "MONTH(" + local + ")"
+}
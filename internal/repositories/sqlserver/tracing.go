@@ -0,0 +1,90 @@
+package sqlserver
+
+import (
+	"errors"
+	"orderstreamrest/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanInstanceKey guarda o span da operação na instância do *gorm.DB entre os callbacks
+const spanInstanceKey = "telemetry:span"
+
+// registerTracing cria um span para cada operação do GORM, filho do span presente no contexto da consulta
+func registerTracing(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	register := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range register {
+		if err := r.before("telemetry:before_"+r.operation, startSpan(r.operation)); err != nil {
+			return err
+		}
+		if err := r.after("telemetry:after_"+r.operation, endSpan); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startSpan abre o span da operação e o coloca no contexto do statement
+func startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+
+		ctx, span := telemetry.Tracer().Start(db.Statement.Context, "sqlserver."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemMSSQL,
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanInstanceKey, span)
+	}
+}
+
+// endSpan registra a consulta executada, as linhas afetadas e o erro, e fecha o span
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	// Apenas o SQL com placeholders; os valores dos parâmetros não são exportados
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/dto"
+	"time"
+)
+
+// Granularidades das tendências de tickets
+const (
+	TrendWeek  = "week"
+	TrendMonth = "month"
+)
+
+// ErrUnknownGranularity é retornado quando a granularidade da tendência não existe
+var ErrUnknownGranularity error = apperror.New(apperror.ErrValidation, "unknown trend granularity, expected week or month")
+
+// periodStartExpr retorna a expressão SQL do primeiro dia do período do ticket, a partir do dia de
+// abertura ticketDay. A semana começa na segunda-feira independente do SET DATEFIRST da conexão.
+func periodStartExpr(granularity, ticketDay string) (string, error) {
+	switch granularity {
+	case TrendWeek:
+		return "DATEADD(DAY, -((DATEPART(WEEKDAY, " + ticketDay + ") + @@DATEFIRST + 5) % 7), " + ticketDay + ")", nil
+	case TrendMonth:
+		return "DATEFROMPARTS(YEAR(" + ticketDay + "), MONTH(" + ticketDay + "), 1)", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownGranularity, granularity)
+	}
+}
+
+// GetTicketsByPeriod retorna o total de tickets abertos por semana ou mês dentro do filtro, contados
+// no fuso do filtro. Os períodos sem tickets não são retornados.
+func (s *Internal) GetTicketsByPeriod(ctx context.Context, granularity string, filter dto.MetricsFilter) ([]PeriodTotal, error) {
+	period, err := periodStartExpr(granularity, dayExpr("dd", filter))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []PeriodTotal
+	query := `
+    SELECT
+        %[1]s AS period_start,
+        SUM(ft.QtTickets) AS total
+    FROM dbo.Fact_Tickets ft
+    JOIN DW.dbo.Dim_Dates dd
+        ON ft.EntryDateKey = dd.DateKey
+    %[3]s
+    WHERE 1 = 1
+    %[2]s
+    GROUP BY %[1]s
+    ORDER BY period_start;
+    `
+	conditions, args := andMetricsFilter("dd", filter)
+	err = s.metricsDB(ctx).Raw(fmt.Sprintf(query, period, conditions, zoneOffsetsJoin("dd", filter)), args...).Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickets by %s: %w", granularity, err)
+	}
+	return results, nil
+}
+
+// GetTrendMovers compara os tickets de cada valor da dimensão abertos a partir de currentStart com os
+// abertos até previousEnd e retorna os n valores com a maior variação absoluta. O filtro deve cobrir
+// os dois períodos.
+func (s *Internal) GetTrendMovers(ctx context.Context, dimension string, filter dto.MetricsFilter, currentStart, previousEnd time.Time, n int) ([]TrendMover, error) {
+	dim, ok := breakdownDimensions[dimension]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDimension, dimension)
+	}
+
+	var results []TrendMover
+	query := `
+    SELECT TOP (?) name, current_total, previous_total
+    FROM (
+        SELECT
+            %[1]s AS name,
+            SUM(CASE WHEN %[2]s >= ? THEN ft.QtTickets ELSE 0 END) AS current_total,
+            SUM(CASE WHEN %[2]s <= ? THEN ft.QtTickets ELSE 0 END) AS previous_total
+        FROM dbo.Fact_Tickets ft
+        JOIN DW.dbo.Dim_Dates dd
+            ON ft.EntryDateKey = dd.DateKey
+        %[5]s
+        %[3]s
+        WHERE 1 = 1
+        %[4]s
+        GROUP BY %[1]s
+    ) movers
+    ORDER BY ABS(current_total - previous_total) DESC, name;
+    `
+	conditions, filterArgs := andMetricsFilter("dd", filter)
+	args := append([]interface{}{
+		n,
+		currentStart.Format(filterDateLayout),
+		previousEnd.Format(filterDateLayout),
+	}, filterArgs...)
+
+	err := s.metricsDB(ctx).Raw(fmt.Sprintf(query, dim.column, dayExpr("dd", filter), dim.join, conditions, zoneOffsetsJoin("dd", filter)), args...).Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trend movers by %s: %w", dimension, err)
+	}
+	return results, nil
+}
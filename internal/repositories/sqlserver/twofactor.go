@@ -0,0 +1,117 @@
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrTwoFactorNotConfigured é retornado quando o usuário não iniciou a configuração do 2FA
+var ErrTwoFactorNotConfigured error = apperror.New(apperror.ErrNotFound, "two-factor authentication not configured")
+
+// GetTwoFactor busca a configuração de 2FA do usuário
+func (s *Internal) GetTwoFactor(ctx context.Context, userId int) (*entities.UserTwoFactor, error) {
+	var twoFactor entities.UserTwoFactor
+	err := s.db.WithContext(ctx).
+		Table("dbo.UserTwoFactor").
+		Where("UserId = ?", userId).
+		First(&twoFactor).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTwoFactorNotConfigured
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+
+	return &twoFactor, nil
+}
+
+// StartTwoFactorSetup grava um novo segredo ainda não confirmado, substituindo uma configuração anterior
+func (s *Internal) StartTwoFactorSetup(ctx context.Context, userId int, secret string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("dbo.UserTwoFactor").Where("UserId = ?", userId).Delete(&entities.UserTwoFactor{}).Error; err != nil {
+			return fmt.Errorf("failed to reset two-factor settings: %w", err)
+		}
+
+		twoFactor := &entities.UserTwoFactor{
+			UserId:    userId,
+			Secret:    secret,
+			CreatedAt: time.Now().UTC(),
+		}
+		if err := tx.Table("dbo.UserTwoFactor").Create(twoFactor).Error; err != nil {
+			return fmt.Errorf("failed to create two-factor settings: %w", err)
+		}
+		return nil
+	})
+}
+
+// EnableTwoFactor ativa o 2FA confirmado, gravando os hashes dos códigos de recuperação
+func (s *Internal) EnableTwoFactor(ctx context.Context, userId int, recoveryCodes string) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.UserTwoFactor").
+		Where("UserId = ?", userId).
+		Updates(map[string]interface{}{
+			"Enabled":       true,
+			"RecoveryCodes": recoveryCodes,
+			"EnabledAt":     time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to enable two-factor: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorNotConfigured
+	}
+
+	return nil
+}
+
+// ConsumeRecoveryCode troca os hashes dos códigos de recuperação por remaining, desde que ainda sejam
+// current. Retorna false quando outro login os alterou antes, ou seja, quando o código já foi usado.
+func (s *Internal) ConsumeRecoveryCode(ctx context.Context, userId int, current, remaining string) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Table("dbo.UserTwoFactor").
+		Where("UserId = ? AND RecoveryCodes = ?", userId, current).
+		Update("RecoveryCodes", remaining)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update recovery codes: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteUserTwoFactor remove a configuração de 2FA de um usuário
+func (s *Internal) DeleteUserTwoFactor(ctx context.Context, userId int) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Table("dbo.UserTwoFactor").
+		Where("UserId = ?", userId).
+		Delete(&entities.UserTwoFactor{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete two-factor settings: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// CountUserTwoFactor retorna 1 quando o usuário tem configuração de 2FA
+func (s *Internal) CountUserTwoFactor(ctx context.Context, userId int) (int64, error) {
+	var total int64
+	err := s.db.WithContext(ctx).
+		Table("dbo.UserTwoFactor").
+		Where("UserId = ?", userId).
+		Count(&total).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count two-factor settings: %w", err)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,103 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// successfulAuth e failedAuth contam as tentativas de autenticação com e sem sucesso
+const (
+	successfulAuth = "SUM(CASE WHEN l.Success = 1 THEN 1 ELSE 0 END)"
+	failedAuth     = "SUM(CASE WHEN l.Success = 0 THEN 1 ELSE 0 END)"
+)
+
+// AuthActivityTotals é o total de autenticações do período e quantos usuários distintos entraram
+type AuthActivityTotals struct {
+	Successful  int64 `gorm:"column:successful"`
+	Failed      int64 `gorm:"column:failed"`
+	ActiveUsers int64 `gorm:"column:active_users"`
+}
+
+// DailyAuthActivity são as autenticações de um dia (UTC)
+type DailyAuthActivity struct {
+	Day        time.Time `gorm:"column:day"`
+	Successful int64     `gorm:"column:successful"`
+	Failed     int64     `gorm:"column:failed"`
+	Users      int64     `gorm:"column:users"`
+}
+
+// UserAuthActivity são as autenticações de um usuário no período
+type UserAuthActivity struct {
+	UserId      int        `gorm:"column:UserId"`
+	Name        *string    `gorm:"column:Name"`
+	Email       *string    `gorm:"column:Email"`
+	UserType    *string    `gorm:"column:UserType"`
+	Successful  int64      `gorm:"column:successful"`
+	Failed      int64      `gorm:"column:failed"`
+	LastLoginAt *time.Time `gorm:"column:last_login_at"`
+}
+
+// authActivity filtra os logs de autenticação do período [from, to)
+func (s *Internal) authActivity(ctx context.Context, from, to time.Time) *gorm.DB {
+	return s.db.WithContext(ctx).
+		Table("dbo.UserAuthLogs l").
+		Where("l.CreatedAt >= ? AND l.CreatedAt < ?", from, to)
+}
+
+// GetAuthActivityTotals retorna o total de autenticações com e sem sucesso do período [from, to)
+func (s *Internal) GetAuthActivityTotals(ctx context.Context, from, to time.Time) (AuthActivityTotals, error) {
+	var totals AuthActivityTotals
+	err := s.authActivity(ctx, from, to).
+		Select("ISNULL(" + successfulAuth + ", 0) AS successful, ISNULL(" + failedAuth + ", 0) AS failed, " +
+			"COUNT(DISTINCT CASE WHEN l.Success = 1 THEN l.UserId END) AS active_users").
+		Scan(&totals).Error
+	if err != nil {
+		return totals, fmt.Errorf("failed to get auth activity totals: %w", err)
+	}
+	return totals, nil
+}
+
+// GetDailyAuthActivity retorna as autenticações por dia do período [from, to). Os dias sem
+// autenticações não são retornados.
+func (s *Internal) GetDailyAuthActivity(ctx context.Context, from, to time.Time) ([]DailyAuthActivity, error) {
+	var days []DailyAuthActivity
+	err := s.authActivity(ctx, from, to).
+		Select("CAST(l.CreatedAt AS date) AS day, " + successfulAuth + " AS successful, " + failedAuth + " AS failed, " +
+			"COUNT(DISTINCT CASE WHEN l.Success = 1 THEN l.UserId END) AS users").
+		Group("CAST(l.CreatedAt AS date)").
+		Order("day").
+		Scan(&days).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily auth activity: %w", err)
+	}
+	return days, nil
+}
+
+// GetMostActiveUsers retorna uma página dos usuários com mais autenticações bem-sucedidas no período
+// [from, to) e a quantidade de usuários com alguma tentativa
+func (s *Internal) GetMostActiveUsers(ctx context.Context, from, to time.Time, offset, limit int) ([]UserAuthActivity, int64, error) {
+	var count int64
+	if err := s.authActivity(ctx, from, to).Distinct("l.UserId").Count(&count).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count active users: %w", err)
+	}
+
+	var users []UserAuthActivity
+	err := s.authActivity(ctx, from, to).
+		Select("l.UserId, u.Name, u.Email, u.UserType, " + successfulAuth + " AS successful, " + failedAuth + " AS failed, " +
+			"MAX(CASE WHEN l.Success = 1 THEN l.CreatedAt END) AS last_login_at").
+		Joins("LEFT JOIN dbo.tb_users u ON u.Id = l.UserId").
+		Group("l.UserId, u.Name, u.Email, u.UserType").
+		Order("successful DESC").
+		Order("l.UserId").
+		Offset(offset).
+		Limit(limit).
+		Scan(&users).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get most active users: %w", err)
+	}
+
+	return users, count, nil
+}
@@ -2,16 +2,61 @@ package sqlserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"orderstreamrest/internal/apperror"
 	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/utils"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-// CreateUser cria um novo usuário
+var (
+	// ErrUserNotFound é retornado quando o usuário não existe
+	ErrUserNotFound error = apperror.New(apperror.ErrNotFound, "user not found")
+	// ErrEmailInUse é retornado quando o email já pertence a outro usuário
+	ErrEmailInUse error = apperror.New(apperror.ErrConflict, "email already in use")
+)
+
+// HasUserCompany informa se dbo.tb_users já tem a coluna CompanyId, criada por migrations/0003_users_company.sql.
+// Depois de criada a coluna não some, então a resposta positiva é guardada e não consulta mais o banco.
+func (s *Internal) HasUserCompany(ctx context.Context) (bool, error) {
+	if s.userCompany.Load() {
+		return true, nil
+	}
+
+	var length *int
+	if err := s.db.WithContext(ctx).Raw("SELECT COL_LENGTH('dbo.tb_users', 'CompanyId')").Scan(&length).Error; err != nil {
+		return false, fmt.Errorf("failed to check tb_users.CompanyId: %w", err)
+	}
+	if length == nil {
+		return false, nil
+	}
+
+	s.userCompany.Store(true)
+	return true, nil
+}
+
+// CreateUser cria um novo usuário. A empresa só é gravada depois da migração que cria a coluna.
 func (s *Internal) CreateUser(ctx context.Context, user *entities.User) (int, error) {
-	result := s.db.WithContext(ctx).Table("dbo.tb_users").Create(user)
+	hasCompany, err := s.HasUserCompany(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	query := s.db.WithContext(ctx).Table("dbo.tb_users")
+	if !hasCompany {
+		warnMissingUserCompany(user.CompanyId)
+		query = query.Omit("CompanyId")
+	}
+
+	result := query.Create(user)
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return 0, apperror.Wrap(apperror.ErrConflict, ErrEmailInUse.Error(), result.Error)
+	}
 	if result.Error != nil {
 		return 0, fmt.Errorf("failed to create user: %w", result.Error)
 	}
@@ -26,8 +71,8 @@ func (s *Internal) GetUserByID(ctx context.Context, id int) (*entities.User, err
 		Where("Id = ?", id).
 		First(&user).Error
 
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("user not found")
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -44,8 +89,8 @@ func (s *Internal) GetUserByEmail(ctx context.Context, email string) (*entities.
 		Where("Email = ?", email).
 		First(&user).Error
 
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("user not found")
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -62,8 +107,8 @@ func (s *Internal) GetUserByMicrosoftID(ctx context.Context, microsoftId string)
 		Where("MicrosoftId = ?", microsoftId).
 		First(&user).Error
 
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("user not found")
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -103,33 +148,133 @@ func (s *Internal) GetAllUsers(ctx context.Context, page, pageSize int, onlyActi
 	return users, totalCount, nil
 }
 
-// UpdateUser atualiza um usuário
+// SearchUsers busca os usuários cujo nome ou e-mail contém o termo, ativos primeiro e em ordem alfabética,
+// e retorna até limit usuários com o total de encontrados
+func (s *Internal) SearchUsers(ctx context.Context, term string, limit int) ([]entities.User, int64, error) {
+	pattern := "%" + likeEscaper.Replace(term) + "%"
+	query := s.db.WithContext(ctx).
+		Table("dbo.tb_users").
+		Where(`Name LIKE ? ESCAPE '\' OR Email LIKE ? ESCAPE '\'`, pattern, pattern)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	var users []entities.User
+	err := query.
+		Order("IsActive DESC, Name ASC").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	return users, totalCount, nil
+}
+
+// likeEscaper escapa os curingas do LIKE (%, _ e [) para que o termo seja buscado literalmente, com \ como ESCAPE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "[", `\[`)
+
+// usersExportBatchSize é quantos usuários StreamUsers lê do banco por vez
+const usersExportBatchSize = 500
+
+// StreamUsers percorre todos os usuários, em lotes e em ordem de ID, com o mesmo filtro de GetAllUsers.
+// Um erro retornado por fn interrompe a leitura.
+func (s *Internal) StreamUsers(ctx context.Context, onlyActive bool, fn func(entities.User) error) error {
+	query := s.db.WithContext(ctx).Table("dbo.tb_users")
+
+	if onlyActive {
+		query = query.Where("IsActive = ?", true)
+	}
+
+	var users []entities.User
+	err := query.FindInBatches(&users, usersExportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, user := range users {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to stream users: %w", err)
+	}
+
+	return nil
+}
+
+// UserTypeCount é a quantidade de usuários de um tipo, ativos ou não
+type UserTypeCount struct {
+	UserType string `gorm:"column:UserType"`
+	IsActive bool   `gorm:"column:IsActive"`
+	Total    int64  `gorm:"column:Total"`
+}
+
+// CountUsersByType retorna a quantidade de usuários por tipo e situação
+func (s *Internal) CountUsersByType(ctx context.Context) ([]UserTypeCount, error) {
+	var counts []UserTypeCount
+	err := s.db.WithContext(ctx).
+		Table("dbo.tb_users").
+		Select("UserType, IsActive, COUNT(*) AS Total").
+		Group("UserType, IsActive").
+		Scan(&counts).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users by type: %w", err)
+	}
+
+	return counts, nil
+}
+
+// UpdateUser atualiza um usuário. A empresa só é gravada depois da migração que cria a coluna.
 func (s *Internal) UpdateUser(ctx context.Context, id int, user *entities.User) error {
+	hasCompany, err := s.HasUserCompany(ctx)
+	if err != nil {
+		return err
+	}
+
 	updates := map[string]interface{}{
 		"Name":      user.Name,
 		"Email":     user.Email,
 		"UserType":  user.UserType,
 		"IsActive":  user.IsActive,
-		"UpdatedAt": time.Now(),
+		"CompanyId": user.CompanyId,
+		"UpdatedAt": time.Now().UTC(),
 		"UpdatedBy": user.UpdatedBy,
 	}
+	if !hasCompany {
+		warnMissingUserCompany(user.CompanyId)
+		delete(updates, "CompanyId")
+	}
 
 	result := s.db.WithContext(ctx).
 		Table("dbo.tb_users").
 		Where("Id = ?", id).
 		Updates(updates)
 
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return apperror.Wrap(apperror.ErrConflict, ErrEmailInUse.Error(), result.Error)
+	}
 	if result.Error != nil {
 		return fmt.Errorf("failed to update user: %w", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
 }
 
+// warnMissingUserCompany avisa que a empresa informada não foi gravada por falta da coluna
+func warnMissingUserCompany(companyId *int64) {
+	if companyId != nil {
+		log.Printf("dbo.tb_users has no CompanyId column, company %d not saved; run migrations/0003_users_company.sql", *companyId)
+	}
+}
+
 // UpdatePassword atualiza a senha de um usuário
 func (s *Internal) UpdatePassword(ctx context.Context, id int, passwordHash string, updatedBy int) error {
 	result := s.db.WithContext(ctx).
@@ -137,7 +282,7 @@ func (s *Internal) UpdatePassword(ctx context.Context, id int, passwordHash stri
 		Where("Id = ?", id).
 		Updates(map[string]interface{}{
 			"PasswordHash": passwordHash,
-			"UpdatedAt":    time.Now(),
+			"UpdatedAt":    time.Now().UTC(),
 			"UpdatedBy":    updatedBy,
 		})
 
@@ -146,18 +291,87 @@ func (s *Internal) UpdatePassword(ctx context.Context, id int, passwordHash stri
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetUserActive ativa ou desativa um usuário sem alterar os demais dados
+func (s *Internal) SetUserActive(ctx context.Context, id int, active bool, updatedBy int) error {
+	result := s.db.WithContext(ctx).
+		Table("dbo.tb_users").
+		Where("Id = ?", id).
+		Updates(map[string]interface{}{
+			"IsActive":  active,
+			"UpdatedAt": time.Now().UTC(),
+			"UpdatedBy": updatedBy,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update user status: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// AddPasswordHistory guarda o hash da senha definida para o usuário e mantém apenas as keep mais recentes
+func (s *Internal) AddPasswordHistory(ctx context.Context, userId int, passwordHash string, keep int) error {
+	entry := &entities.PasswordHistory{
+		UserId:       userId,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	if err := s.db.WithContext(ctx).Table("dbo.PasswordHistory").Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create password history: %w", err)
+	}
+
+	recent := s.db.WithContext(ctx).
+		Table("dbo.PasswordHistory").
+		Select("Id").
+		Where("UserId = ?", userId).
+		Order("CreatedAt DESC, Id DESC").
+		Limit(keep)
+
+	err := s.db.WithContext(ctx).
+		Table("dbo.PasswordHistory").
+		Where("UserId = ? AND Id NOT IN (?)", userId, recent).
+		Delete(&entities.PasswordHistory{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to prune password history: %w", err)
 	}
 
 	return nil
 }
 
+// GetPasswordHistory retorna os hashes das últimas limit senhas do usuário, da mais recente para a mais antiga
+func (s *Internal) GetPasswordHistory(ctx context.Context, userId int, limit int) ([]string, error) {
+	var hashes []string
+	err := s.db.WithContext(ctx).
+		Table("dbo.PasswordHistory").
+		Where("UserId = ?", userId).
+		Order("CreatedAt DESC, Id DESC").
+		Limit(limit).
+		Pluck("PasswordHash", &hashes).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password history: %w", err)
+	}
+
+	return hashes, nil
+}
+
 // UpdateLastLogin atualiza o último login do usuário
 func (s *Internal) UpdateLastLogin(ctx context.Context, id int) error {
 	result := s.db.WithContext(ctx).
 		Table("dbo.tb_users").
 		Where("Id = ?", id).
-		Update("LastLoginAt", time.Now())
+		Update("LastLoginAt", time.Now().UTC())
 
 	if result.Error != nil {
 		return fmt.Errorf("failed to update last login: %w", result.Error)
@@ -189,7 +403,7 @@ func (s *Internal) DeleteUser(ctx context.Context, id int, deletedBy int) error
 		Where("Id = ?", id).
 		Updates(map[string]interface{}{
 			"IsActive":     false,
-			"UpdatedAt":    time.Now(),
+			"UpdatedAt":    time.Now().UTC(),
 			"UpdatedBy":    deletedBy,
 			"Name":         nil,
 			"Email":        nil,
@@ -203,7 +417,7 @@ func (s *Internal) DeleteUser(ctx context.Context, id int, deletedBy int) error
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
@@ -238,3 +452,113 @@ func (s *Internal) GetUserAuthLogs(ctx context.Context, userId int, limit int) (
 
 	return logs, nil
 }
+
+// CountUserAuthLogs retorna a quantidade de logs de autenticação de um usuário
+func (s *Internal) CountUserAuthLogs(ctx context.Context, userId int) (int64, error) {
+	var total int64
+	err := s.db.WithContext(ctx).
+		Table("dbo.UserAuthLogs").
+		Where("UserId = ?", userId).
+		Count(&total).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count auth logs: %w", err)
+	}
+
+	return total, nil
+}
+
+// DeleteUserAuthLogs remove os logs de autenticação de um usuário
+func (s *Internal) DeleteUserAuthLogs(ctx context.Context, userId int) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Table("dbo.UserAuthLogs").
+		Where("UserId = ?", userId).
+		Delete(&entities.UserAuthLog{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete auth logs: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// CountUserPasswordHistory retorna a quantidade de senhas anteriores guardadas de um usuário
+func (s *Internal) CountUserPasswordHistory(ctx context.Context, userId int) (int64, error) {
+	var total int64
+	err := s.db.WithContext(ctx).
+		Table("dbo.PasswordHistory").
+		Where("UserId = ?", userId).
+		Count(&total).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count password history: %w", err)
+	}
+
+	return total, nil
+}
+
+// DeleteUserPasswordHistory remove as senhas anteriores de um usuário
+func (s *Internal) DeleteUserPasswordHistory(ctx context.Context, userId int) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Table("dbo.PasswordHistory").
+		Where("UserId = ?", userId).
+		Delete(&entities.PasswordHistory{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete password history: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// AnonymizeUserAuthLogs remove IP e user agent dos logs de autenticação de um usuário
+func (s *Internal) AnonymizeUserAuthLogs(ctx context.Context, userId int) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Table("dbo.UserAuthLogs").
+		Where("UserId = ?", userId).
+		Updates(map[string]interface{}{
+			"IPAddress": nil,
+			"UserAgent": nil,
+		})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to anonymize auth logs: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// NormalizeUserTypes converte para a forma canônica os tipos de usuário gravados com sinônimos
+// ou outra grafia (ex.: "support" -> "AGENT"). É idempotente e retorna a quantidade de linhas alteradas.
+func (s *Internal) NormalizeUserTypes(ctx context.Context) (int64, error) {
+	var userTypes []string
+	err := s.db.WithContext(ctx).
+		Table("dbo.tb_users").
+		Where("UserType IS NOT NULL").
+		Distinct().
+		Pluck("UserType", &userTypes).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to list user types: %w", err)
+	}
+
+	var updated int64
+	for _, userType := range userTypes {
+		canonical, ok := utils.UserTypes.Canonical(userType)
+		if !ok {
+			log.Printf("Unknown user type %q left unchanged", userType)
+			continue
+		}
+
+		// A collation padrão ignora maiúsculas, então a comparação binária encontra grafias diferentes do canônico
+		result := s.db.WithContext(ctx).
+			Table("dbo.tb_users").
+			Where("UserType = ? AND UserType COLLATE Latin1_General_BIN <> ?", userType, canonical).
+			Update("UserType", canonical)
+		if result.Error != nil {
+			return updated, fmt.Errorf("failed to normalize user type %q: %w", userType, result.Error)
+		}
+		updated += result.RowsAffected
+	}
+
+	return updated, nil
+}
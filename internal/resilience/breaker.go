@@ -0,0 +1,182 @@
+// Package resilience protege as chamadas às dependências (Elasticsearch, SQL Server) com circuit
+// breaker e novas tentativas, para que uma dependência fora do ar responda 503 na hora em vez de
+// segurar cada requisição até o timeout
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"orderstreamrest/internal/apperror"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker/v2"
+)
+
+const (
+	defaultFailures     = 5
+	defaultOpenTimeout  = 30 * time.Second
+	defaultRetryBackoff = 200 * time.Millisecond
+)
+
+// Policy define quando o circuito abre e quantas vezes uma chamada é repetida
+type Policy struct {
+	// Name identifica a dependência nos logs e nas mensagens de erro
+	Name string
+	// Failures é o número de falhas seguidas que abre o circuito
+	Failures uint32
+	// OpenTimeout é o tempo que o circuito fica aberto antes de deixar uma chamada de teste passar
+	OpenTimeout time.Duration
+	// Retries é o número de novas tentativas após uma falha transitória; 0 desativa
+	Retries int
+	// RetryBackoff é a espera antes da primeira nova tentativa, dobrada a cada tentativa
+	RetryBackoff time.Duration
+}
+
+// LoadPolicy lê a política de BREAKER_<NAME>_FAILURES, BREAKER_<NAME>_OPEN_SECONDS e
+// BREAKER_<NAME>_RETRIES, com os padrões para as variáveis ausentes ou inválidas
+func LoadPolicy(name string, retries int) Policy {
+	prefix := "BREAKER_" + strings.ToUpper(name) + "_"
+	return Policy{
+		Name:         name,
+		Failures:     uint32(envInt(prefix+"FAILURES", defaultFailures)),
+		OpenTimeout:  time.Duration(envInt(prefix+"OPEN_SECONDS", int(defaultOpenTimeout/time.Second))) * time.Second,
+		Retries:      envInt(prefix+"RETRIES", retries),
+		RetryBackoff: defaultRetryBackoff,
+	}
+}
+
+// envInt lê um inteiro não negativo da variável de ambiente
+func envInt(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// Breaker é o circuit breaker de uma dependência. Um Breaker nil deixa todas as chamadas passarem.
+type Breaker struct {
+	policy   Policy
+	cb       *gobreaker.TwoStepCircuitBreaker[struct{}]
+	openedAt atomic.Int64
+}
+
+// NewBreaker cria o circuit breaker da política. Failures 0 desativa o circuito (retorna nil).
+func NewBreaker(policy Policy) *Breaker {
+	if policy.Failures == 0 {
+		return nil
+	}
+
+	b := &Breaker{policy: policy}
+	b.cb = gobreaker.NewTwoStepCircuitBreaker[struct{}](gobreaker.Settings{
+		Name:    policy.Name,
+		Timeout: policy.OpenTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= policy.Failures
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			if to == gobreaker.StateOpen {
+				b.openedAt.Store(time.Now().UnixNano())
+			}
+			log.Printf("Circuit breaker %s changed from %s to %s", name, from, to)
+		},
+		IsSuccessful: func(err error) bool {
+			return !IsFailure(err)
+		},
+		// O cliente que desistiu não diz nada sobre a saúde da dependência
+		IsExcluded: func(err error) bool {
+			return errors.Is(err, context.Canceled)
+		},
+	})
+	return b
+}
+
+// State retorna o estado do circuito: closed, half-open, open ou disabled
+func (b *Breaker) State() string {
+	if b == nil {
+		return "disabled"
+	}
+	return b.cb.State().String()
+}
+
+// Allow verifica se uma chamada pode seguir. Com o circuito aberto retorna um erro ErrUnavailable
+// com a espera até a próxima chamada de teste; caso contrário, done deve receber o resultado da chamada.
+func (b *Breaker) Allow() (done func(err error), err error) {
+	if b == nil {
+		return func(error) {}, nil
+	}
+
+	done, err = b.cb.Allow()
+	if err != nil {
+		return nil, &apperror.Error{
+			Kind:       apperror.ErrUnavailable,
+			Message:    fmt.Sprintf("%s is temporarily unavailable", b.policy.Name),
+			Cause:      err,
+			RetryAfter: b.retryAfter(),
+		}
+	}
+	return done, nil
+}
+
+// retryAfter é o tempo que falta para o circuito deixar uma chamada de teste passar, no mínimo 1s
+func (b *Breaker) retryAfter() time.Duration {
+	remaining := b.policy.OpenTimeout - time.Since(time.Unix(0, b.openedAt.Load()))
+	if remaining < time.Second {
+		return time.Second
+	}
+	return remaining
+}
+
+// Execute chama fn pelo circuito, repetindo as falhas transitórias conforme a política
+func Execute[T any](ctx context.Context, b *Breaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	retries, backoff := 0, time.Duration(0)
+	if b != nil {
+		retries, backoff = b.policy.Retries, b.policy.RetryBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		done, err := b.Allow()
+		if err != nil {
+			return zero, err
+		}
+
+		result, err := fn(ctx)
+		done(err)
+		if err == nil || !IsFailure(err) || attempt >= retries || ctx.Err() != nil {
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return zero, err
+		case <-time.After(backoff << attempt):
+		}
+	}
+}
+
+// IsFailure indica se o erro conta como falha da dependência. Erros do cliente (validação, não
+// encontrado, conflito...) e cancelamentos não indicam que a dependência está com problemas.
+func IsFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	for _, kind := range []error{
+		apperror.ErrNotFound,
+		apperror.ErrConflict,
+		apperror.ErrValidation,
+		apperror.ErrForbidden,
+		apperror.ErrPreconditionFailed,
+	} {
+		if errors.Is(err, kind) {
+			return false
+		}
+	}
+	return true
+}
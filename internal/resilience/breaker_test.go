@@ -0,0 +1,118 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"orderstreamrest/internal/apperror"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errDown = errors.New("connection refused")
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	breaker := NewBreaker(Policy{Name: "es_search", Failures: 3, OpenTimeout: time.Minute})
+	calls := 0
+	fail := func(context.Context) (int, error) {
+		calls++
+		return 0, errDown
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := Execute(context.Background(), breaker, fail)
+		assert.ErrorIs(t, err, errDown)
+	}
+	assert.Equal(t, "open", breaker.State())
+
+	// Com o circuito aberto a dependência não é chamada e o erro traz a espera
+	_, err := Execute(context.Background(), breaker, fail)
+	assert.ErrorIs(t, err, apperror.ErrUnavailable)
+	assert.Equal(t, 3, calls)
+	retryAfter := apperror.RetryAfter(err)
+	assert.Greater(t, retryAfter, 50*time.Second)
+	assert.LessOrEqual(t, retryAfter, time.Minute)
+	assert.Equal(t, "es_search is temporarily unavailable", apperror.Message(err, ""))
+}
+
+func TestBreakerIgnoresClientErrors(t *testing.T) {
+	breaker := NewBreaker(Policy{Name: "es_search", Failures: 2, OpenTimeout: time.Minute})
+
+	for _, err := range []error{
+		apperror.New(apperror.ErrValidation, "invalid cursor"),
+		apperror.New(apperror.ErrNotFound, "ticket not found"),
+		context.Canceled,
+		apperror.New(apperror.ErrValidation, "invalid cursor"),
+	} {
+		_, got := Execute(context.Background(), breaker, func(context.Context) (struct{}, error) {
+			return struct{}{}, err
+		})
+		assert.ErrorIs(t, got, err)
+	}
+
+	assert.Equal(t, "closed", breaker.State())
+}
+
+func TestExecuteRetries(t *testing.T) {
+	tests := []struct {
+		name          string
+		retries       int
+		errs          []error
+		expectedCalls int
+		expectError   bool
+	}{
+		{name: "Transient failure is retried", retries: 1, errs: []error{errDown, nil}, expectedCalls: 2},
+		{name: "Retries are limited", retries: 1, errs: []error{errDown, errDown, nil}, expectedCalls: 2, expectError: true},
+		{name: "Client errors are not retried", retries: 2, errs: []error{apperror.New(apperror.ErrValidation, "bad"), nil}, expectedCalls: 1, expectError: true},
+		{name: "No retries by default", errs: []error{errDown, nil}, expectedCalls: 1, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := NewBreaker(Policy{Name: "es_search", Failures: 10, Retries: tt.retries, RetryBackoff: time.Millisecond})
+			calls := 0
+
+			result, err := Execute(context.Background(), breaker, func(context.Context) (int, error) {
+				err := tt.errs[calls]
+				calls++
+				return calls, err
+			})
+
+			assert.Equal(t, tt.expectedCalls, calls)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCalls, result)
+		})
+	}
+}
+
+func TestDisabledBreaker(t *testing.T) {
+	breaker := NewBreaker(Policy{Name: "sql_metrics"})
+	assert.Nil(t, breaker)
+	assert.Equal(t, "disabled", breaker.State())
+
+	for i := 0; i < 10; i++ {
+		_, err := Execute(context.Background(), breaker, func(context.Context) (int, error) {
+			return 0, errDown
+		})
+		assert.ErrorIs(t, err, errDown)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Setenv("BREAKER_ES_SEARCH_FAILURES", "8")
+	t.Setenv("BREAKER_ES_SEARCH_OPEN_SECONDS", "10")
+	t.Setenv("BREAKER_ES_SEARCH_RETRIES", "invalid")
+
+	policy := LoadPolicy("es_search", 1)
+
+	assert.Equal(t, uint32(8), policy.Failures)
+	assert.Equal(t, 10*time.Second, policy.OpenTimeout)
+	assert.Equal(t, 1, policy.Retries)
+	assert.Equal(t, uint32(defaultFailures), LoadPolicy("sql_metrics", 0).Failures)
+}
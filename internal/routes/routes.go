@@ -1,58 +1,197 @@
 package routes
 
 import (
+	"log"
+	"orderstreamrest/internal/apidocs"
 	"orderstreamrest/internal/config"
 	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/service/admin"
+	"orderstreamrest/internal/service/alerts"
+	"orderstreamrest/internal/service/audit"
+	"orderstreamrest/internal/service/export"
+	"orderstreamrest/internal/service/graph"
 	"orderstreamrest/internal/service/healthcheck"
 	"orderstreamrest/internal/service/metrics"
+	"orderstreamrest/internal/service/notifications"
+	"orderstreamrest/internal/service/searches"
+	"orderstreamrest/internal/service/synonyms"
+	"orderstreamrest/internal/service/tags"
 	"orderstreamrest/internal/service/tickets"
 	"orderstreamrest/internal/service/users"
+	"orderstreamrest/internal/utils"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// legacyRoutesDeprecatedAt is when the unversioned routes were deprecated in favor of /api/v1
+var legacyRoutesDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
 // InitiateRoutes is a function that initializes the routes for the application
 func InitiateRoutes(engine *gin.Engine, cfg *config.App) {
 
+	// The published spec takes host, basePath and schemes from the environment, not from the annotations
+	if settings, err := apidocs.LoadSettings(middleware.APIVersionPrefix(middleware.APIVersion1)); err != nil {
+		log.Printf("Invalid Swagger settings, serving the generated spec as is: %v", err)
+	} else {
+		apidocs.Configure(settings)
+	}
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	engine.GET("/prometheus", middleware.PrometheusHandler())
+	engine.GET("/ping", healthcheck.Ping())
+	engine.HEAD("/ping", healthcheck.Ping())
 
 	healthGroup := engine.Group("/healthcheck")
 	{
 		healthGroup.GET("/", healthcheck.Health(cfg))
+		healthGroup.GET("/live", healthcheck.Live())
+		healthGroup.GET("/ready", healthcheck.Ready(cfg))
+		healthGroup.GET("/startup", healthcheck.Startup(cfg))
 	}
 
-	metricsGroup := engine.Group("/metrics", middleware.Auth())
+	registerAPIRoutes(engine.Group(middleware.APIVersionPrefix(middleware.APIVersion1), middleware.PinAPIVersion(middleware.APIVersion1)), cfg)
+
+	// Legacy aliases: the same routes without a version prefix, answering with deprecation headers
+	registerAPIRoutes(engine.Group("", middleware.NegotiateAPIVersion()), cfg)
+	deprecateLegacyRoutes(engine.Routes(), middleware.APIVersion1)
+}
+
+// registerAPIRoutes registers the versioned API routes on a router
+func registerAPIRoutes(router *gin.RouterGroup, cfg *config.App) {
+
+	metricsGroup := router.Group("/metrics", middleware.Auth(), middleware.ETag())
 	{
-		metricsGroup.GET("/tickets", metrics.GetTicketsMetrics(cfg))
-		metricsGroup.GET("/tickets/mean-time-resolution-by-priority", metrics.MeanTimeByPriority(cfg))
-		metricsGroup.GET("/tickets/qtd-tickets-by-status-year-month", metrics.QtdTicketsByStatusYearMonth(cfg))
-		metricsGroup.GET("/tickets/qtd-tickets-by-month", metrics.TicketsByMonth(cfg))
-		metricsGroup.GET("/tickets/qtd-tickets-by-priority-year-month", metrics.TicketsByPriorityAndMonth(cfg))
+		metricsGroup.GET("/tickets", export.Pool(middleware.ExportPool), metrics.GetTicketsMetrics(cfg))
+		metricsGroup.GET("/tickets/mean-time-resolution-by-priority", export.Pool(middleware.ExportPool), metrics.MeanTimeByPriority(cfg))
+		metricsGroup.GET("/tickets/resolution-time-percentiles", export.Pool(middleware.ExportPool), metrics.ResolutionTimePercentiles(cfg))
+		metricsGroup.GET("/tickets/qtd-tickets-by-status-year-month", export.Pool(middleware.ExportPool), metrics.QtdTicketsByStatusYearMonth(cfg))
+		metricsGroup.GET("/tickets/qtd-tickets-by-month", export.Pool(middleware.ExportPool), metrics.TicketsByMonth(cfg))
+		metricsGroup.GET("/tickets/qtd-tickets-by-priority-year-month", export.Pool(middleware.ExportPool), metrics.TicketsByPriorityAndMonth(cfg))
+		metricsGroup.GET("/tickets/top", export.Pool(middleware.ExportPool), metrics.TicketsTop(cfg))
+		metricsGroup.GET("/tickets/trend", export.Pool(middleware.ExportPool), metrics.TicketsTrend(cfg))
+		metricsGroup.GET("/tickets/histogram", metrics.TicketsHistogram(cfg))
+		metricsGroup.GET("/tickets/breakdown/:dimension", export.Pool(middleware.ExportPool), metrics.TicketsBreakdown(cfg))
+		metricsGroup.GET("/tickets/breakdown/:dimension/stream", middleware.ExportPool.Middleware(), middleware.NoCompression(), metrics.StreamTicketsBreakdown(cfg))
+		metricsGroup.GET("/agents/workload", middleware.RequireRoles(utils.RoleAdmin, utils.RoleManager), metrics.AgentsWorkload(cfg))
+		metricsGroup.GET("/users/activity", middleware.RequireRoles(utils.RoleAdmin), metrics.UsersActivity(cfg))
 	}
 
-	ticketsGroup := engine.Group("/tickets", middleware.Auth())
+	ticketsGroup := router.Group("/tickets", middleware.Auth())
 	{
+		ticketsGroup.POST("", middleware.RequireRoles(utils.RoleAdmin, utils.RoleManager), middleware.Idempotent(), tickets.CreateTicket(cfg))
+		ticketsGroup.POST("/bulk", middleware.RequireRoles(utils.RoleAdmin, utils.RoleManager), middleware.Quota(middleware.QuotaBulkImport), middleware.ExportPool.Middleware(), tickets.BulkImportTickets(cfg))
+		ticketsGroup.GET("/export", middleware.Quota(middleware.QuotaExport), middleware.ExportPool.Middleware(), middleware.NoCompression(), tickets.ExportTickets(cfg))
+		ticketsGroup.GET("/batch", tickets.GetTicketsBatch(cfg))
 		ticketsGroup.GET("/:id", tickets.SearchTicketByID(cfg))
+		ticketsGroup.GET("/:id/full", tickets.GetTicketDetail(cfg))
+		ticketsGroup.GET("/:id/events", tickets.ListTicketEvents(cfg))
+		ticketsGroup.GET("/:id/attachments", tickets.ListTicketAttachments(cfg))
+		ticketsGroup.GET("/:id/audit", tickets.ListTicketAudit(cfg))
+		ticketsGroup.PUT("/:id", middleware.RequireRoles(utils.RoleAdmin, utils.RoleManager), tickets.UpdateTicket(cfg))
 		ticketsGroup.GET("/query", tickets.GetByWord(cfg))
+		ticketsGroup.GET("/audit/search", middleware.RequireRoles(utils.RoleAdmin, utils.RoleManager), tickets.SearchTicketAudit(cfg))
+		ticketsGroup.POST("/:id/watch", tickets.WatchTicket(cfg))
+		ticketsGroup.DELETE("/:id/watch", tickets.UnwatchTicket(cfg))
 	}
 
-	userRoutes := engine.Group("/users", middleware.Auth())
+	meGroup := router.Group("/me", middleware.Auth())
 	{
-		userRoutes.POST("", users.CreateUser(cfg))
+		meGroup.GET("/notifications", notifications.GetMyNotifications(cfg))
+	}
+
+	userRoutes := router.Group("/users", middleware.RestrictIPs(), middleware.Auth())
+	{
+		userRoutes.POST("", middleware.Idempotent(), users.CreateUser(cfg))
 		userRoutes.GET("", users.GetAllUsers(cfg))
+		userRoutes.GET("/me/saved-searches", searches.ListSavedSearches(cfg))
+		userRoutes.POST("/me/saved-searches", middleware.Idempotent(), searches.CreateSavedSearch(cfg))
+		userRoutes.PUT("/me/saved-searches/:id", searches.UpdateSavedSearch(cfg))
+		userRoutes.DELETE("/me/saved-searches/:id", searches.DeleteSavedSearch(cfg))
+		userRoutes.GET("/me/saved-searches/:id/run", searches.RunSavedSearch(cfg))
+		userRoutes.POST("/bulk", middleware.RequireRoles(utils.RoleAdmin), middleware.Quota(middleware.QuotaBulkImport), users.BulkCreateUsers(cfg))
+		userRoutes.GET("/export", middleware.RequireRoles(utils.RoleAdmin), middleware.Quota(middleware.QuotaExport), middleware.ExportPool.Middleware(), middleware.NoCompression(), users.ExportUsers(cfg))
 		userRoutes.GET("/:id", users.GetUser(cfg))
 		userRoutes.PUT("/:id", users.UpdateUser(cfg))
 		userRoutes.DELETE("/:id", users.DeleteUser(cfg))
+		userRoutes.PATCH("/:id/deactivate", middleware.RequireRoles(utils.RoleAdmin), users.DeactivateUser(cfg))
+		userRoutes.PATCH("/:id/activate", middleware.RequireRoles(utils.RoleAdmin), users.ActivateUser(cfg))
+		userRoutes.GET("/:id/auth-logs", middleware.RequireRoles(utils.RoleAdmin), users.GetUserAuthLogs(cfg))
 
 		userRoutes.POST("/change-password", users.ChangePassword(cfg))
 	}
 
-	authRoutes := engine.Group("/auth")
+	authRoutes := router.Group("/auth")
 	{
 		authRoutes.POST("/login", users.Login(cfg))
+		authRoutes.GET("/me", middleware.Auth(), users.GetMe(cfg))
+		authRoutes.PUT("/me", middleware.Auth(), users.UpdateMe(cfg))
+		authRoutes.GET("/sessions", middleware.Auth(), users.ListSessions(cfg))
+		authRoutes.DELETE("/sessions/:id", middleware.Auth(), users.RevokeSession(cfg))
+		authRoutes.POST("/2fa/setup", middleware.Auth(), users.SetupTwoFactor(cfg))
+		authRoutes.POST("/2fa/verify", middleware.Auth(), users.VerifyTwoFactor(cfg))
+		authRoutes.POST("/2fa/login", users.LoginTwoFactor(cfg))
 		// authRoutes.POST("/microsoft", users.MicrosoftAuth(cfg))
 	}
 
+	router.GET("/search", middleware.Auth(), searches.GlobalSearch(cfg))
+
+	router.GET("/audit", middleware.Auth(), middleware.RequireRoles(utils.RoleAdmin), audit.ListAuditLogs(cfg))
+
+	router.GET("/alerts", middleware.Auth(), middleware.RequireRoles(utils.RoleAdmin, utils.RoleManager), alerts.ListAlerts(cfg))
+
+	router.POST("/graphql", middleware.Auth(), graph.Handler(cfg))
+
+	adminRoutes := router.Group("/admin", middleware.RestrictIPs(), middleware.Auth(), middleware.RequireRoles(utils.RoleAdmin))
+	{
+		adminRoutes.GET("/summary", admin.GetSummary(cfg))
+		adminRoutes.GET("/config", admin.GetConfig(cfg))
+		adminRoutes.GET("/deprecations", admin.ListDeprecations(cfg))
+		adminRoutes.GET("/pools", admin.GetWorkerPools(cfg))
+		adminRoutes.GET("/quotas/users/:id", admin.GetUserQuotas(cfg))
+		adminRoutes.PUT("/quotas/users/:id", admin.UpdateUserQuota(cfg))
+		adminRoutes.GET("/ip-rules", admin.GetIPRules(cfg))
+		adminRoutes.PUT("/ip-rules", admin.UpdateIPRules(cfg))
+		adminRoutes.DELETE("/ip-rules", admin.ResetIPRules(cfg))
+		adminRoutes.GET("/logs", admin.ListLogs(cfg))
+		adminRoutes.GET("/log-level", admin.GetLogLevel(cfg))
+		adminRoutes.PUT("/log-level", admin.UpdateLogLevel(cfg))
+		adminRoutes.DELETE("/metrics-cache", admin.InvalidateMetricsCache(cfg))
+		adminRoutes.POST("/metrics-cache/refresh", admin.RefreshMetricsCache(cfg))
+		adminRoutes.POST("/elasticsearch/reindex/:index", middleware.Quota(middleware.QuotaReindex), middleware.ExportPool.Middleware(), admin.ReindexIndex(cfg))
+		adminRoutes.GET("/search/synonyms", synonyms.GetSynonyms(cfg))
+		adminRoutes.POST("/search/synonyms", synonyms.UploadSynonyms(cfg))
+		adminRoutes.GET("/tags", tags.ListTags(cfg))
+		adminRoutes.POST("/tags/merge", tags.MergeTags(cfg))
+		adminRoutes.GET("/jobs", admin.ListJobs(cfg))
+		adminRoutes.GET("/jobs/queue/:id", admin.GetQueuedJob(cfg))
+		adminRoutes.PUT("/jobs/:name", admin.UpdateJob(cfg))
+		adminRoutes.POST("/jobs/:name/run", admin.RunJob(cfg))
+		adminRoutes.GET("/jobs/:name/runs", admin.ListJobRuns(cfg))
+	}
+}
+
+// deprecateLegacyRoutes registers every unversioned alias of a version's routes as deprecated,
+// pointing to the versioned route. Routes already in the registry keep their own plan.
+func deprecateLegacyRoutes(routes gin.RoutesInfo, version string) {
+	prefix := middleware.APIVersionPrefix(version)
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, prefix+"/") {
+			continue
+		}
+
+		legacyPath := strings.TrimPrefix(route.Path, prefix)
+		if _, ok := middleware.Deprecations.Lookup(route.Method, legacyPath); ok {
+			continue
+		}
+
+		middleware.Deprecations.Register(middleware.Deprecation{
+			Method:       route.Method,
+			Path:         legacyPath,
+			Replacement:  route.Path,
+			DeprecatedAt: legacyRoutesDeprecatedAt,
+		})
+	}
 }
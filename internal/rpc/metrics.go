@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"context"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/rpc/visiondatav1"
+	"orderstreamrest/internal/service/metrics"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// metricsServer implementa o MetricsService sobre o repositório de métricas (com cache no Redis)
+type metricsServer struct {
+	visiondatav1.UnimplementedMetricsServiceServer
+	cfg *config.App
+}
+
+// GetTicketsMetrics retorna os mesmos totais de GET /metrics/tickets
+func (s *metricsServer) GetTicketsMetrics(ctx context.Context, req *visiondatav1.GetTicketsMetricsRequest) (*visiondatav1.TicketsMetrics, error) {
+	filter, err := metrics.ParseMetricsFilter(ctx, metricsParams(req))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	top := int(req.GetTop())
+	if top < 0 {
+		top = 0
+	}
+
+	result, err := metrics.BuildTicketsMetrics(ctx, s.cfg, filter, top)
+	if err != nil {
+		return nil, statusError(err, "failed to retrieve total tickets")
+	}
+
+	response := &visiondatav1.TicketsMetrics{
+		TotalTickets: result.TotalTickets,
+		Metrics:      make([]*visiondatav1.TypeMetric, 0, len(result.Metrics)),
+	}
+	for _, metric := range result.Metrics {
+		typeMetric := &visiondatav1.TypeMetric{
+			Name:   metric.Name,
+			Values: make([]*visiondatav1.MetricValue, 0, len(metric.Values)),
+		}
+		for _, value := range metric.Values {
+			typeMetric.Values = append(typeMetric.Values, &visiondatav1.MetricValue{
+				Name:  value.Name,
+				Value: value.Value,
+			})
+		}
+		response.Metrics = append(response.Metrics, typeMetric)
+	}
+
+	return response, nil
+}
+
+// metricsParams expõe os campos da requisição com os nomes dos parâmetros da rota HTTP
+func metricsParams(req *visiondatav1.GetTicketsMetricsRequest) func(name string) string {
+	return func(name string) string {
+		switch name {
+		case "startDate":
+			return req.GetStartDate()
+		case "endDate":
+			return req.GetEndDate()
+		case "year":
+			if req.GetYear() == 0 {
+				return ""
+			}
+			return strconv.Itoa(int(req.GetYear()))
+		case "company":
+			return req.GetCompany()
+		case "department":
+			return req.GetDepartment()
+		case "channel":
+			return req.GetChannel()
+		case "priority":
+			return req.GetPriority()
+		default:
+			return ""
+		}
+	}
+}
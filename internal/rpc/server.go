@@ -0,0 +1,164 @@
+// Package rpc expõe a busca de tickets e as métricas por gRPC para os serviços internos (ex.: o
+// serviço de analytics em lote). Os handlers chamam os mesmos repositórios e funções de serviço das
+// rotas HTTP e autenticam com o mesmo JWT, enviado no metadata authorization.
+//
+// O código em visiondatav1 é gerado a partir de proto/visiondata/v1/visiondata.proto:
+//
+//	protoc --go_out=. --go_opt=module=orderstreamrest --go-grpc_out=. --go-grpc_opt=module=orderstreamrest proto/visiondata/v1/visiondata.proto
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/rpc/visiondatav1"
+	"orderstreamrest/internal/utils"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPort é a porta do gRPC quando GRPC_PORT não está definida
+const defaultPort = "9090"
+
+// NewServer cria o servidor gRPC com os serviços de tickets e métricas registrados
+func NewServer(cfg *config.App, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryAuth),
+		grpc.ChainStreamInterceptor(streamAuth),
+	)
+
+	server := grpc.NewServer(opts...)
+	visiondatav1.RegisterTicketServiceServer(server, &ticketServer{cfg: cfg})
+	visiondatav1.RegisterMetricsServiceServer(server, &metricsServer{cfg: cfg})
+	return server
+}
+
+// Start atende o gRPC na porta GRPC_PORT até o contexto terminar, com TLS quando CERT_FILE e KEY_FILE
+// estão definidos, como o servidor HTTP. GRPC_ENABLED=false desliga o servidor nesta instância.
+func Start(ctx context.Context, cfg *config.App) {
+	if os.Getenv("GRPC_ENABLED") == "false" {
+		cfg.Logger.Info("gRPC server disabled on this instance (GRPC_ENABLED=false)")
+		return
+	}
+
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
+	var opts []grpc.ServerOption
+	if certFile, keyFile := utils.GetCertFiles(); certFile != "" && keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			cfg.Logger.Error("Failed to load gRPC TLS certificates", err)
+			return
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		cfg.Logger.Error(fmt.Sprintf("Failed to listen for gRPC on port %s", port), err)
+		return
+	}
+
+	server := NewServer(cfg, opts...)
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	cfg.Logger.Info(fmt.Sprintf("Starting gRPC server on port %s", port))
+	if err := server.Serve(listener); err != nil {
+		cfg.Logger.Error("gRPC server stopped", err)
+	}
+}
+
+// unaryAuth autentica as chamadas unárias
+func unaryAuth(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuth autentica as chamadas com stream
+func streamAuth(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream troca o contexto do stream pelo contexto com o escopo do usuário
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate valida o token do metadata authorization ("Bearer <token>") e retorna o contexto com
+// o escopo de busca do usuário, o mesmo aplicado pelo middleware Auth
+func authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ctx, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	parts := strings.SplitN(values[0], " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ctx, status.Error(codes.Unauthenticated, "invalid token format, use: Bearer <token>")
+	}
+
+	_, scoped, err := middleware.AuthenticateToken(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, middleware.ErrTokenRevoked) {
+			return ctx, status.Error(codes.Unauthenticated, "token has been revoked")
+		}
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return scoped, nil
+}
+
+// codeByKind é o código gRPC de cada tipo de erro do apperror
+var codeByKind = []struct {
+	kind error
+	code codes.Code
+}{
+	{apperror.ErrNotFound, codes.NotFound},
+	{apperror.ErrConflict, codes.AlreadyExists},
+	{apperror.ErrValidation, codes.InvalidArgument},
+	{apperror.ErrForbidden, codes.PermissionDenied},
+	{apperror.ErrPreconditionFailed, codes.FailedPrecondition},
+	{apperror.ErrUnavailable, codes.Unavailable},
+	{context.DeadlineExceeded, codes.DeadlineExceeded},
+	{context.Canceled, codes.Canceled},
+}
+
+// statusError converte o erro de um repositório ou serviço no status gRPC equivalente ao status HTTP
+// que RespondError usaria; erros sem tipo conhecido viram Internal com a mensagem de contexto
+func statusError(err error, message string) error {
+	for _, entry := range codeByKind {
+		if errors.Is(err, entry.kind) {
+			return status.Error(entry.code, err.Error())
+		}
+	}
+	return status.Error(codes.Internal, message+": "+err.Error())
+}
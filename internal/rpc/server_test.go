@@ -0,0 +1,114 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/rpc/visiondatav1"
+	"orderstreamrest/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestAuthentication(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token, err := middleware.GenerateJWT(1, "admin@visiondata.com", utils.RoleAdmin, 0, "")
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(&config.App{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client := visiondatav1.NewTicketServiceClient(conn)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantCode      codes.Code
+	}{
+		{name: "Missing token", wantCode: codes.Unauthenticated},
+		{name: "Wrong scheme", authorization: "Basic " + token, wantCode: codes.Unauthenticated},
+		{name: "Invalid token", authorization: "Bearer invalid", wantCode: codes.Unauthenticated},
+		// Autenticado, a chamada chega ao handler, que recusa o ticket_id vazio
+		{name: "Valid token", authorization: "Bearer " + token, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.authorization)
+			}
+
+			_, err := client.GetTicket(ctx, &visiondatav1.GetTicketRequest{})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "Not found", err: apperror.New(apperror.ErrNotFound, "ticket not found"), want: codes.NotFound},
+		{name: "Wrapped validation", err: fmt.Errorf("search: %w", apperror.New(apperror.ErrValidation, "invalid query")), want: codes.InvalidArgument},
+		{name: "Unavailable", err: apperror.New(apperror.ErrUnavailable, "elasticsearch down"), want: codes.Unavailable},
+		{name: "Deadline", err: fmt.Errorf("search: %w", context.DeadlineExceeded), want: codes.DeadlineExceeded},
+		{name: "Unknown", err: errors.New("boom"), want: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status.Code(statusError(tt.err, "failed")))
+		})
+	}
+}
+
+func TestTicketMessage(t *testing.T) {
+	ticket := ticketMessage(map[string]interface{}{
+		"ticket_id":      "TCK-1",
+		"title":          "Erro no login",
+		"current_status": float64(2),
+		"priority":       "Alta",
+		"category":       map[string]interface{}{"name": "Acesso"},
+		"company":        map[string]interface{}{"id": float64(7), "name": "Acme"},
+		"dates":          map[string]interface{}{"created_at": "2025-06-01T12:00:00Z", "closed_at": nil},
+		"sla_metrics":    map[string]interface{}{"resolution_sla_breached": true},
+	})
+
+	assert.Equal(t, "TCK-1", ticket.GetTicketId())
+	assert.Equal(t, "Erro no login", ticket.GetTitle())
+	assert.Equal(t, int64(2), ticket.GetCurrentStatus())
+	assert.Equal(t, "Acesso", ticket.GetCategory())
+	assert.Equal(t, int64(7), ticket.GetCompanyId())
+	assert.Equal(t, "Acme", ticket.GetCompany())
+	assert.Equal(t, "2025-06-01T12:00:00Z", ticket.GetCreatedAt())
+	assert.Empty(t, ticket.GetClosedAt())
+	assert.Empty(t, ticket.GetSubcategory())
+	assert.True(t, ticket.GetResolutionSlaBreached())
+	assert.False(t, ticket.GetFirstResponseSlaBreached())
+}
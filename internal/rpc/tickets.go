@@ -0,0 +1,148 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/rpc/visiondatav1"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ticketServer implementa o TicketService sobre o índice de tickets do Elasticsearch
+type ticketServer struct {
+	visiondatav1.UnimplementedTicketServiceServer
+	cfg *config.App
+}
+
+// SearchTickets executa a mesma busca de GET /tickets/query
+func (s *ticketServer) SearchTickets(ctx context.Context, req *visiondatav1.SearchTicketsRequest) (*visiondatav1.SearchTicketsResponse, error) {
+	result, err := s.cfg.ES.SearchTicketsBySomeWord(ctx, dto.SearchParams{
+		Query:    req.GetQuery(),
+		Page:     int(req.GetPage()),
+		PageSize: int(req.GetPageSize()),
+	})
+	if err != nil {
+		return nil, statusError(err, "error while searching tickets")
+	}
+
+	documents := result.Tickets
+	response := &visiondatav1.SearchTicketsResponse{
+		Tickets: make([]*visiondatav1.Ticket, 0, len(documents)),
+		Pagination: &visiondatav1.Pagination{
+			CurrentPage:  int32(result.Pagination.CurrentPage),
+			PerPage:      int32(result.Pagination.PerPage),
+			TotalPages:   int32(result.Pagination.TotalPages),
+			TotalRecords: result.Pagination.TotalRecords,
+			HasNext:      result.Pagination.HasNext,
+			HasPrev:      result.Pagination.HasPrev,
+		},
+	}
+	for _, document := range documents {
+		response.Tickets = append(response.Tickets, ticketMessage(document))
+	}
+
+	return response, nil
+}
+
+// GetTicket busca um ticket pelo ticket_id, dentro do escopo do usuário
+func (s *ticketServer) GetTicket(ctx context.Context, req *visiondatav1.GetTicketRequest) (*visiondatav1.Ticket, error) {
+	ticketID := strings.TrimSpace(req.GetTicketId())
+	if ticketID == "" {
+		return nil, status.Error(codes.InvalidArgument, "ticket_id is required")
+	}
+
+	document, err := s.cfg.ES.SearchTicketByID(ctx, ticketID)
+	if err != nil {
+		return nil, statusError(err, "error while searching ticket")
+	}
+	if document == nil {
+		return nil, status.Error(codes.NotFound, "ticket not found")
+	}
+
+	return ticketMessage(*document), nil
+}
+
+// ExportTickets envia, um por mensagem, todos os tickets da busca, na ordem de GET /tickets/export
+func (s *ticketServer) ExportTickets(req *visiondatav1.ExportTicketsRequest, stream grpc.ServerStreamingServer[visiondatav1.Ticket]) error {
+	err := s.cfg.ES.ExportTickets(stream.Context(), strings.TrimSpace(req.GetQuery()), func(document map[string]interface{}) error {
+		return stream.Send(ticketMessage(document))
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return statusError(err, "failed to export tickets")
+	}
+	return nil
+}
+
+// ticketMessage converte o documento do Elasticsearch na mensagem Ticket
+func ticketMessage(document map[string]interface{}) *visiondatav1.Ticket {
+	return &visiondatav1.Ticket{
+		TicketId:                 stringField(document, "ticket_id"),
+		Title:                    stringField(document, "title"),
+		Description:              stringField(document, "description"),
+		CurrentStatus:            int64Field(document, "current_status"),
+		Priority:                 stringField(document, "priority"),
+		Channel:                  stringField(document, "channel"),
+		Category:                 stringField(document, "category.name"),
+		Subcategory:              stringField(document, "subcategory.name"),
+		Product:                  stringField(document, "product.name"),
+		CompanyId:                int64Field(document, "company.id"),
+		Company:                  stringField(document, "company.name"),
+		CreatedBy:                stringField(document, "created_by_user.full_name"),
+		AssignedAgent:            stringField(document, "assigned_agent.full_name"),
+		CreatedAt:                stringField(document, "dates.created_at"),
+		FirstResponseAt:          stringField(document, "dates.first_response_at"),
+		ClosedAt:                 stringField(document, "dates.closed_at"),
+		FirstResponseSlaBreached: boolField(document, "sla_metrics.first_response_sla_breached"),
+		ResolutionSlaBreached:    boolField(document, "sla_metrics.resolution_sla_breached"),
+	}
+}
+
+// field retorna o valor no caminho com pontos do documento, ou nil quando ausente
+func field(document map[string]interface{}, path string) interface{} {
+	var value interface{} = document
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+func stringField(document map[string]interface{}, path string) string {
+	switch value := field(document, path).(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func int64Field(document map[string]interface{}, path string) int64 {
+	switch value := field(document, path).(type) {
+	case float64:
+		return int64(value)
+	case int64:
+		return value
+	case int:
+		return int64(value)
+	default:
+		return 0
+	}
+}
+
+func boolField(document map[string]interface{}, path string) bool {
+	value, _ := field(document, path).(bool)
+	return value
+}
@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetConfig retorna a configuração efetiva da instância, sem segredos
+// @Summary      Configuração Efetiva
+// @Description  Retorna o ambiente, a versão, a execução e as variáveis de ambiente lidas pela API nesta instância, para comparar ambientes. Variáveis sem valor vêm com value nulo (vale o padrão); senhas, segredos e tokens vêm como [REDACTED] e as senhas em URLs são omitidas.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=config.Report}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Router       /admin/config [get]
+func GetConfig(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, cfg.Report(), "Configuration retrieved successfully"))
+	}
+}
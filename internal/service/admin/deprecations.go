@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListDeprecations lista as rotas depreciadas e os clientes que ainda as chamam
+// @Summary      Rotas Depreciadas
+// @Description  Retorna as rotas depreciadas, a data de sunset, a rota substituta e as chamadas por cliente
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.DeprecationResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/deprecations [get]
+func ListDeprecations(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deprecations := middleware.Deprecations.List()
+
+		response := make([]dto.DeprecationResponse, 0, len(deprecations))
+		for _, d := range deprecations {
+			usage, err := cfg.Redis.HGetAll(c.Request.Context(), middleware.DeprecationUsageKey(d)).Result()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(
+					c,
+					http.StatusInternalServerError,
+					"Internal Server Error",
+					"Failed to retrieve deprecated route usage",
+					err.Error(),
+				))
+				return
+			}
+
+			item := dto.DeprecationResponse{
+				Method:       d.Method,
+				Path:         d.Path,
+				Replacement:  d.Replacement,
+				DeprecatedAt: d.DeprecatedAt,
+				Clients:      make([]dto.DeprecationClientUsage, 0, len(usage)),
+			}
+			if !d.SunsetAt.IsZero() {
+				sunset := d.SunsetAt
+				item.SunsetAt = &sunset
+			}
+
+			for client, value := range usage {
+				calls, _ := strconv.ParseInt(value, 10, 64)
+				item.TotalCalls += calls
+				item.Clients = append(item.Clients, dto.DeprecationClientUsage{
+					Client: client,
+					Calls:  calls,
+				})
+			}
+
+			sort.Slice(item.Clients, func(i, j int) bool {
+				return item.Clients[i].Calls > item.Clients[j].Calls
+			})
+
+			response = append(response, item)
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Deprecated routes retrieved successfully"))
+	}
+}
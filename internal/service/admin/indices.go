@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReindexIndex migra um índice gerenciado para a versão atual do mapping
+// @Summary      Reindexar Índice do Elasticsearch
+// @Description  Cria um índice com a versão atual do mapping (ex.: support_tickets_v2), copia os documentos do alias e move o alias para o novo índice. Executa no pool de exportações.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        index path string true "Alias do índice" Enums(support_tickets, datavision-api-logs)
+// @Success      200 {object} dto.SuccessResponse{data=dto.ReindexResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Index not managed by the application"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Cota diária de reindexações esgotada"
+// @Failure 	 503 {object} dto.RateLimitErrorResponse "Too many exports in progress"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/elasticsearch/reindex/{index} [post]
+func ReindexIndex(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		alias := c.Param("index")
+
+		if _, err := elsearch.LookupIndex(alias); err != nil {
+			middleware.RespondError(c, err, "Index not managed by the application")
+			return
+		}
+
+		middleware.ReportProgress(c, 0, 1)
+
+		result, err := cfg.ES.Reindex(c.Request.Context(), alias)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to reindex index", err.Error()))
+			return
+		}
+
+		middleware.ReportProgress(c, 1, 1)
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.ReindexResponse{
+			Alias:     result.Alias,
+			From:      result.From,
+			To:        result.To,
+			Documents: result.Documents,
+			Took:      result.Took.String(),
+		}, "Index reindexed successfully"))
+	}
+}
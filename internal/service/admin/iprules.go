@@ -0,0 +1,165 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ipRulesAuditID identifica na auditoria o único conjunto de regras de IP
+const ipRulesAuditID = "global"
+
+// errIPRulesLockout recusa regras que bloqueariam o administrador que as envia
+var errIPRulesLockout = errors.New("the rules would block your own IP address")
+
+// GetIPRules retorna as redes aceitas e recusadas nas rotas sensíveis
+// @Summary      Regras de IP
+// @Description  Retorna as redes (IPs ou CIDRs) aceitas e recusadas em /admin e /users, de onde elas vêm (configuração ou administradores) e o IP com que esta requisição chegou.
+// @Tags         admin
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=dto.IPRulesResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/ip-rules [get]
+func GetIPRules(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ipFilterConfigured(c) {
+			return
+		}
+
+		rules, err := middleware.IPFilters.Rules(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve IP rules", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.IPRulesResponse{IPRules: rules, ClientIP: c.ClientIP()}, "IP rules retrieved successfully"))
+	}
+}
+
+// UpdateIPRules substitui as redes aceitas e recusadas nas rotas sensíveis
+// @Summary      Alterar Regras de IP
+// @Description  Substitui as listas de redes aceitas (allow; vazia aceita qualquer rede) e recusadas (deny, que vale mesmo para as redes de allow) em /admin e /users, em todas as instâncias em até 30 segundos.
+// @Description  A alteração é recusada quando bloquearia o IP de quem a faz e é registrada na auditoria.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        request body dto.UpdateIPRulesRequest true "Redes aceitas e recusadas"
+// @Success      200 {object} dto.SuccessResponse{data=dto.IPRulesResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 409 {object} dto.ErrorResponse "Conflict"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/ip-rules [put]
+func UpdateIPRules(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ipFilterConfigured(c) {
+			return
+		}
+
+		var req dto.UpdateIPRulesRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+		if err := validateIPRules(req, c.ClientIP()); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errIPRulesLockout) {
+				status = http.StatusConflict
+			}
+			c.JSON(status, dto.NewErrorResponse(c, status, http.StatusText(status), "Invalid IP rules", err.Error()))
+			return
+		}
+
+		ctx := c.Request.Context()
+		before, err := middleware.IPFilters.Rules(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve IP rules", err.Error()))
+			return
+		}
+
+		userID, _ := middleware.GetCurrentUserID(c)
+		after, err := middleware.IPFilters.SetRules(ctx, req.Allow, req.Deny, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to update IP rules", err.Error()))
+			return
+		}
+
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntityIPRules, ipRulesAuditID, before, after)
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.IPRulesResponse{IPRules: after, ClientIP: c.ClientIP()}, "IP rules updated successfully"))
+	}
+}
+
+// ResetIPRules volta às redes da configuração
+// @Summary      Restaurar Regras de IP
+// @Description  Descarta as regras definidas pelos administradores e volta às de IP_ALLOWLIST e IP_DENYLIST. É recusada quando as regras da configuração bloqueariam o IP de quem a faz. Registrada na auditoria.
+// @Tags         admin
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=dto.IPRulesResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 409 {object} dto.ErrorResponse "Conflict"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/ip-rules [delete]
+func ResetIPRules(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ipFilterConfigured(c) {
+			return
+		}
+
+		defaults := middleware.IPFilters.Defaults()
+		if !middleware.IPAllowed(defaults.Allow, defaults.Deny, c.ClientIP()) {
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(c, http.StatusConflict, "Conflict", "Invalid IP rules", errIPRulesLockout.Error()))
+			return
+		}
+
+		ctx := c.Request.Context()
+		before, err := middleware.IPFilters.Rules(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve IP rules", err.Error()))
+			return
+		}
+
+		after, err := middleware.IPFilters.ResetRules(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to update IP rules", err.Error()))
+			return
+		}
+
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntityIPRules, ipRulesAuditID, before, after)
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.IPRulesResponse{IPRules: after, ClientIP: c.ClientIP()}, "IP rules updated successfully"))
+	}
+}
+
+// ipFilterConfigured responde 500 quando o filtro de IP não foi habilitado
+func ipFilterConfigured(c *gin.Context) bool {
+	if middleware.IPFilters == nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve IP rules", "the IP filter is not configured"))
+		return false
+	}
+	return true
+}
+
+// validateIPRules confere que as listas só têm IPs ou CIDRs e que não bloqueiam o IP de quem as envia
+func validateIPRules(req dto.UpdateIPRulesRequest, clientIP string) error {
+	if invalid := middleware.InvalidNetworks(req.Allow, req.Deny); len(invalid) > 0 {
+		return fmt.Errorf("invalid IPs or CIDRs: %v", invalid)
+	}
+	if !middleware.IPAllowed(req.Allow, req.Deny, clientIP) {
+		return errIPRulesLockout
+	}
+	return nil
+}
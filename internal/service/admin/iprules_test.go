@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIPRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           dto.UpdateIPRulesRequest
+		clientIP      string
+		expectedError string
+	}{
+		{
+			name:     "Caller inside the allowlist",
+			req:      dto.UpdateIPRulesRequest{Allow: []string{"10.8.0.0/16"}, Deny: []string{"10.8.4.17"}},
+			clientIP: "10.8.2.31",
+		},
+		{
+			name:     "Empty lists allow every network",
+			req:      dto.UpdateIPRulesRequest{},
+			clientIP: "203.0.113.9",
+		},
+		{
+			name:          "Invalid network",
+			req:           dto.UpdateIPRulesRequest{Allow: []string{"10.8.0.0/16", "corporate-vpn"}},
+			clientIP:      "10.8.2.31",
+			expectedError: "invalid IPs or CIDRs: [corporate-vpn]",
+		},
+		{
+			name:          "Allowlist without the caller",
+			req:           dto.UpdateIPRulesRequest{Allow: []string{"10.8.0.0/16"}},
+			clientIP:      "203.0.113.9",
+			expectedError: errIPRulesLockout.Error(),
+		},
+		{
+			name:          "Denylist with the caller",
+			req:           dto.UpdateIPRulesRequest{Deny: []string{"10.8.2.0/24"}},
+			clientIP:      "10.8.2.31",
+			expectedError: errIPRulesLockout.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateIPRules(tt.req, tt.clientIP)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
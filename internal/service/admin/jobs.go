@@ -0,0 +1,290 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/service/jobs"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultJobRunsPageSize = 20
+	maxJobRunsPageSize     = 100
+)
+
+// ListJobs lista as rotinas em segundo plano e a configuração de cada uma
+// @Summary      Listar Rotinas
+// @Description  Retorna as rotinas executadas em segundo plano, a configuração persistida e a próxima execução prevista
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.ScheduledJobResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/jobs [get]
+func ListJobs(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		registered := jobs.Registered()
+		response := make([]dto.ScheduledJobResponse, 0, len(registered))
+
+		for _, job := range registered {
+			settings, err := cfg.SqlServer.EnsureScheduledJob(c.Request.Context(), job.Defaults)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve jobs", err.Error()))
+				return
+			}
+			response = append(response, jobResponse(job, *settings))
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Jobs retrieved successfully"))
+	}
+}
+
+// UpdateJob altera a configuração de uma rotina
+// @Summary      Configurar Rotina
+// @Description  Habilita ou desabilita a rotina e altera intervalo, retenção e política. A nova configuração vale a partir da próxima verificação do agendador.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        name path string true "Nome da rotina"
+// @Param        request body dto.UpdateScheduledJobRequest true "Configuração"
+// @Success      200 {object} dto.SuccessResponse{data=dto.ScheduledJobResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/jobs/{name} [put]
+func UpdateJob(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, ok := jobs.Lookup(c.Param("name"))
+		if !ok {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Job not found", nil))
+			return
+		}
+
+		var req dto.UpdateScheduledJobRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+
+		settings, err := cfg.SqlServer.EnsureScheduledJob(c.Request.Context(), job.Defaults)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve job", err.Error()))
+			return
+		}
+
+		if req.Enabled != nil {
+			settings.Enabled = *req.Enabled
+		}
+		if req.IntervalMinutes != nil {
+			settings.IntervalMinutes = *req.IntervalMinutes
+		}
+		if req.RetentionDays != nil {
+			settings.RetentionDays = *req.RetentionDays
+		}
+		if req.Policy != nil {
+			settings.Policy = strings.ToUpper(strings.TrimSpace(*req.Policy))
+		}
+
+		if err := jobs.ValidateSettings(job, *settings); err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid job settings", err.Error()))
+			return
+		}
+
+		if userID, ok := middleware.GetCurrentUserID(c); ok {
+			settings.UpdatedBy = &userID
+		}
+		now := time.Now().UTC()
+		settings.UpdatedAt = &now
+
+		if err := cfg.SqlServer.UpdateScheduledJob(c.Request.Context(), settings); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to update job", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, jobResponse(job, *settings), "Job updated successfully"))
+	}
+}
+
+// RunJob executa uma rotina imediatamente
+// @Summary      Executar Rotina
+// @Description  Executa a rotina agora, mesmo que esteja desabilitada, e retorna o registro da execução
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        name path string true "Nome da rotina"
+// @Success      200 {object} dto.SuccessResponse{data=dto.JobRunResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 409 {object} dto.ErrorResponse "Conflict - rotina já em execução"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/jobs/{name}/run [post]
+func RunJob(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, ok := jobs.Lookup(c.Param("name"))
+		if !ok {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Job not found", nil))
+			return
+		}
+
+		var triggeredBy *int
+		if userID, ok := middleware.GetCurrentUserID(c); ok {
+			triggeredBy = &userID
+		}
+
+		run, err := jobs.Execute(c.Request.Context(), cfg, job, jobs.SourceManual, triggeredBy)
+		if err != nil {
+			middleware.RespondError(c, err, "Job failed")
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, jobRunResponse(*run), "Job finished successfully"))
+	}
+}
+
+// ListJobRuns lista o histórico de execuções de uma rotina
+// @Summary      Histórico da Rotina
+// @Description  Retorna as execuções da rotina, da mais recente para a mais antiga
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        name path string true "Nome da rotina"
+// @Param        page query int false "Página" default(1)
+// @Param        pageSize query int false "Itens por página (máximo 100)" default(20)
+// @Success      200 {object} dto.PaginatedResponse{data=[]dto.JobRunResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/jobs/{name}/runs [get]
+func ListJobRuns(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, ok := jobs.Lookup(c.Param("name"))
+		if !ok {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Job not found", nil))
+			return
+		}
+
+		page := middleware.ParsePage(c, defaultJobRunsPageSize, maxJobRunsPageSize)
+		runs, count, err := cfg.SqlServer.ListJobRuns(c.Request.Context(), job.Name, page.Offset(), page.Size)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve job runs", err.Error()))
+			return
+		}
+
+		response := make([]dto.JobRunResponse, 0, len(runs))
+		for _, run := range runs {
+			response = append(response, jobRunResponse(run))
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, response, middleware.NewPagination(c, page, count), "Job runs retrieved successfully"))
+	}
+}
+
+// GetQueuedJob consulta uma tarefa da fila de processamento assíncrono
+// @Summary      Consultar Tarefa da Fila
+// @Description  Retorna a situação, as tentativas e o resultado de uma tarefa enviada à fila (ex.: reindexação, geração de relatório)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path string true "Id da tarefa"
+// @Success      200 {object} dto.SuccessResponse{data=dto.QueuedJobResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/jobs/queue/{id} [get]
+func GetQueuedJob(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, err := cfg.SqlServer.GetQueuedJob(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve job")
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, queuedJobResponse(*job), "Job retrieved successfully"))
+	}
+}
+
+// jobResponse monta a resposta de uma rotina com a próxima execução prevista
+func jobResponse(job jobs.Job, settings entities.ScheduledJob) dto.ScheduledJobResponse {
+	response := dto.ScheduledJobResponse{
+		Name:            job.Name,
+		Description:     job.Description,
+		Enabled:         settings.Enabled,
+		IntervalMinutes: settings.IntervalMinutes,
+		RetentionDays:   settings.RetentionDays,
+		Policy:          settings.Policy,
+		Policies:        job.Policies,
+		RunsOnStartup:   job.Startup,
+		LastRunAt:       settings.LastRunAt,
+		UpdatedAt:       settings.UpdatedAt,
+		UpdatedBy:       settings.UpdatedBy,
+	}
+
+	if settings.Enabled {
+		next := time.Now().UTC()
+		if settings.LastRunAt != nil {
+			next = settings.LastRunAt.Add(time.Duration(settings.IntervalMinutes) * time.Minute)
+		}
+		response.NextRunAt = &next
+	}
+
+	return response
+}
+
+// jobRunResponse converte o registro de execução na resposta da API
+func jobRunResponse(run entities.JobRun) dto.JobRunResponse {
+	return dto.JobRunResponse{
+		Id:           run.Id,
+		JobName:      run.JobName,
+		Source:       run.Source,
+		TriggeredBy:  run.TriggeredBy,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+		Success:      run.Success,
+		Affected:     run.Affected,
+		ErrorMessage: run.ErrorMessage,
+	}
+}
+
+// queuedJobResponse converte a tarefa da fila na resposta da API
+func queuedJobResponse(job entities.QueuedJob) dto.QueuedJobResponse {
+	response := dto.QueuedJobResponse{
+		Id:           job.Id,
+		Type:         job.Type,
+		Status:       job.Status,
+		Attempts:     job.Attempts,
+		MaxAttempts:  job.MaxAttempts,
+		ErrorMessage: job.ErrorMessage,
+		RequestedBy:  job.RequestedBy,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+
+	if job.Payload != "" && json.Valid([]byte(job.Payload)) {
+		response.Payload = json.RawMessage(job.Payload)
+	}
+	if job.Result != nil && json.Valid([]byte(*job.Result)) {
+		response.Result = json.RawMessage(*job.Result)
+	}
+
+	return response
+}
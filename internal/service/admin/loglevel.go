@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLogLevel retorna o nível mínimo dos logs da instância
+// @Summary      Nível dos Logs
+// @Description  Retorna o nível mínimo dos logs desta instância e o nível configurado na inicialização (LOG_LEVEL ou o padrão do ambiente)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=dto.LogLevelResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Router       /admin/log-level [get]
+func GetLogLevel(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, logLevelResponse(cfg, ""), "Log level retrieved successfully"))
+	}
+}
+
+// UpdateLogLevel altera o nível mínimo dos logs sem reiniciar a instância
+// @Summary      Alterar Nível dos Logs
+// @Description  Altera o nível mínimo dos logs (DEBUG, INFO, WARN, ERROR ou FATAL) enquanto a instância estiver no ar, para investigar incidentes em produção. Vale apenas para a instância que atendeu a requisição (executionId na resposta) e volta ao nível configurado quando ela reinicia.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        request body dto.LogLevelRequest true "Novo nível mínimo"
+// @Success      200 {object} dto.SuccessResponse{data=dto.LogLevelResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Router       /admin/log-level [put]
+func UpdateLogLevel(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.LogLevelRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+
+		level, err := logger.ParseLevel(req.Level)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid log level", err.Error()))
+			return
+		}
+
+		previous, err := cfg.Logger.SetLevel(level)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid log level", err.Error()))
+			return
+		}
+
+		// Registrado como WARN para aparecer mesmo quando o novo nível é WARN ou mais alto
+		userID, _ := middleware.GetCurrentUserID(c)
+		cfg.Logger.Warn(fmt.Sprintf("Log level changed from %s to %s", previous, level), map[string]interface{}{
+			"previous_level": previous,
+			"level":          level,
+			"user_id":        userID,
+		})
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, logLevelResponse(cfg, previous), "Log level updated successfully"))
+	}
+}
+
+// logLevelResponse monta a resposta com o nível atual e o configurado da instância
+func logLevelResponse(cfg *config.App, previous logger.LogLevel) dto.LogLevelResponse {
+	return dto.LogLevelResponse{
+		Level:           string(cfg.Logger.Level()),
+		ConfiguredLevel: string(cfg.Logger.ConfiguredLevel()),
+		PreviousLevel:   string(previous),
+		ExecutionId:     cfg.ExecutionID,
+	}
+}
@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLogsPageSize = 50
+	maxLogsPageSize     = 200
+
+	// defaultLogsPeriod é o intervalo buscado quando from não é informado
+	defaultLogsPeriod = 24 * time.Hour
+)
+
+// logLevels são os níveis aceitos no filtro level
+var logLevels = map[string]bool{
+	string(logger.LevelDebug): true,
+	string(logger.LevelInfo):  true,
+	string(logger.LevelWarn):  true,
+	string(logger.LevelError): true,
+	string(logger.LevelFatal): true,
+}
+
+// ListLogs busca os logs da aplicação
+// @Summary      Logs da Aplicação
+// @Description  Busca os logs da API no Elasticsearch, do mais recente para o mais antigo. Sem from, retorna as últimas 24 horas. Use requestId para localizar os logs de uma resposta de erro.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        from query string false "Início do período (RFC3339)"
+// @Param        to query string false "Fim do período, exclusivo (RFC3339)"
+// @Param        level query string false "Níveis separados por vírgula" example(WARN,ERROR)
+// @Param        requestId query string false "ID da requisição (X-Request-ID)"
+// @Param        userId query string false "ID do usuário autenticado"
+// @Param        path query string false "Prefixo do caminho da requisição" example(/tickets)
+// @Param        page query int false "Página" default(1)
+// @Param        pageSize query int false "Itens por página (máximo 200)" default(50)
+// @Success      200 {object} dto.PaginatedResponse{data=[]dto.LogEntryResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/logs [get]
+func ListLogs(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseLogFilter(c, time.Now())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid logs filter", err.Error()))
+			return
+		}
+
+		page := middleware.ParsePage(c, defaultLogsPageSize, maxLogsPageSize)
+		if page.Offset()+page.Size > elsearch.MaxLogsWindow {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Page out of range",
+				fmt.Sprintf("only the first %d entries can be paged; narrow the filter", elsearch.MaxLogsWindow)))
+			return
+		}
+
+		logs, count, err := cfg.ES.SearchLogs(c.Request.Context(), filter, page.Offset(), page.Size)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to search logs", err.Error()))
+			return
+		}
+
+		entries := make([]dto.LogEntryResponse, 0, len(logs))
+		for _, entry := range logs {
+			entries = append(entries, logEntryResponse(entry))
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, entries, middleware.NewPagination(c, page, count), "Logs retrieved successfully"))
+	}
+}
+
+// parseLogFilter lê os parâmetros from, to, level, requestId, userId e path da query
+func parseLogFilter(c *gin.Context, now time.Time) (elsearch.LogFilter, error) {
+	filter := elsearch.LogFilter{
+		RequestID: strings.TrimSpace(c.Query("requestId")),
+		UserID:    strings.TrimSpace(c.Query("userId")),
+		Path:      strings.TrimSpace(c.Query("path")),
+	}
+
+	from := now.Add(-defaultLogsPeriod)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from %q, expected RFC3339 (e.g. 2025-06-01T00:00:00Z)", value)
+		}
+		from = parsed
+	}
+	filter.From = &from
+
+	if value := c.Query("to"); value != "" {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to %q, expected RFC3339 (e.g. 2025-06-01T23:59:59Z)", value)
+		}
+		if !to.After(from) {
+			return filter, errors.New("to must be after from")
+		}
+		filter.To = &to
+	}
+
+	if value := c.Query("level"); value != "" {
+		for _, level := range strings.Split(value, ",") {
+			level = strings.ToUpper(strings.TrimSpace(level))
+			if !logLevels[level] {
+				return filter, fmt.Errorf("invalid level %q", level)
+			}
+			filter.Levels = append(filter.Levels, level)
+		}
+	}
+
+	return filter, nil
+}
+
+// logEntryResponse resume a entrada do log nos campos usados para investigar uma requisição
+func logEntryResponse(entry logger.LogEntry) dto.LogEntryResponse {
+	response := dto.LogEntryResponse{
+		Id:        entry.ID,
+		Timestamp: entry.Timestamp,
+		Level:     string(entry.Level),
+		Message:   entry.Message,
+		Hostname:  entry.Hostname,
+		Fields:    entry.Fields,
+	}
+	if entry.HTTP != nil {
+		response.RequestId = entry.HTTP.RequestID
+		response.Method = entry.HTTP.Method
+		response.Path = entry.HTTP.Path
+		response.StatusCode = entry.HTTP.StatusCode
+	}
+	if entry.Performance != nil {
+		response.DurationMs = entry.Performance.DurationMs
+	}
+	if entry.User != nil {
+		response.UserId = entry.User.ID
+	}
+	if entry.Trace != nil {
+		response.TraceId = entry.Trace.TraceID
+	}
+	if entry.Error != nil {
+		response.Error = entry.Error.Message
+	}
+	return response
+}
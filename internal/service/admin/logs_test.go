@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       string
+		expectError bool
+
+		expectedFrom   time.Time
+		expectedLevels []string
+		expectedPath   string
+		expectedTo     bool
+	}{
+		{
+			name:         "Success - Defaults to the last 24 hours",
+			query:        "",
+			expectedFrom: now.Add(-24 * time.Hour),
+		},
+		{
+			name:           "Success - Period, levels and path",
+			query:          "from=2025-06-01T00:00:00Z&to=2025-06-01T06:00:00Z&level=warn,%20error&path=/tickets",
+			expectedFrom:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedLevels: []string{"WARN", "ERROR"},
+			expectedPath:   "/tickets",
+			expectedTo:     true,
+		},
+		{
+			name:        "Error - Invalid from",
+			query:       "from=2025-06-01",
+			expectError: true,
+		},
+		{
+			name:        "Error - To before from",
+			query:       "from=2025-06-01T06:00:00Z&to=2025-06-01T00:00:00Z",
+			expectError: true,
+		},
+		{
+			name:        "Error - Unknown level",
+			query:       "level=TRACE",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/logs?"+tt.query, nil)
+
+			filter, err := parseLogFilter(c, now)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NotNil(t, filter.From)
+			assert.True(t, tt.expectedFrom.Equal(*filter.From))
+			assert.Equal(t, tt.expectedLevels, filter.Levels)
+			assert.Equal(t, tt.expectedPath, filter.Path)
+			assert.Equal(t, tt.expectedTo, filter.To != nil)
+		})
+	}
+}
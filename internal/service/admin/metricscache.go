@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidateMetricsCache remove todas as consultas de métricas do cache
+// @Summary      Invalidar Cache de Métricas
+// @Description  Remove todas as consultas de métricas guardadas no Redis; as próximas requisições consultam o DW
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=dto.MetricsCacheResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/metrics-cache [delete]
+func InvalidateMetricsCache(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deleted, err := cfg.Metrics.Invalidate(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to invalidate metrics cache", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.MetricsCacheResponse{
+			DeletedKeys: deleted,
+			TTL:         cfg.Metrics.TTL().String(),
+		}, "Metrics cache invalidated successfully"))
+	}
+}
+
+// RefreshMetricsCache invalida o cache e recarrega as métricas sem filtro
+// @Summary      Recarregar Cache de Métricas
+// @Description  Invalida o cache de métricas e recarrega as consultas sem filtro usadas pelos dashboards
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=dto.MetricsCacheResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/metrics-cache/refresh [post]
+func RefreshMetricsCache(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deleted, err := cfg.Metrics.Refresh(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to refresh metrics cache", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.MetricsCacheResponse{
+			DeletedKeys: deleted,
+			Refreshed:   true,
+			TTL:         cfg.Metrics.TTL().String(),
+		}, "Metrics cache refreshed successfully"))
+	}
+}
@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetWorkerPools retorna o uso dos pools de baixa prioridade (exportações e relatórios)
+// @Summary      Pools de Baixa Prioridade
+// @Description  Retorna a capacidade, as requisições em execução e na fila e o progresso de cada exportação
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.WorkerPoolStats}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Router       /admin/pools [get]
+func GetWorkerPools(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pools := []dto.WorkerPoolStats{
+			middleware.ExportPool.Stats(),
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, pools, "Worker pools retrieved successfully"))
+	}
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/audit"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUserQuotas retorna as cotas diárias de um usuário
+// @Summary      Cotas Diárias do Usuário
+// @Description  Retorna, para cada operação cara (export, bulk_import, reindex), o limite padrão, a cota própria do usuário, o consumo do dia e quando a cota volta (meia-noite UTC).
+// @Description  Limit 0 indica operação sem cota.
+// @Tags         admin
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path int true "ID do usuário"
+// @Success      200 {object} dto.SuccessResponse{data=dto.UserQuotasResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/quotas/users/{id} [get]
+func GetUserQuotas(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := quotaUser(c, cfg)
+		if !ok {
+			return
+		}
+
+		quotas, err := middleware.Quotas.Usage(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve quotas", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.UserQuotasResponse{UserId: userID, Quotas: quotas}, "Quotas retrieved successfully"))
+	}
+}
+
+// UpdateUserQuota ajusta a cota diária de um usuário em uma operação
+// @Summary      Ajustar Cota Diária do Usuário
+// @Description  Define uma cota própria para o usuário na operação (limit; 0 libera a operação), volta ao limite padrão (useDefault) e/ou zera o consumo do dia (resetUsage).
+// @Description  A alteração vale a partir da próxima requisição e é registrada na auditoria.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path int true "ID do usuário"
+// @Param        request body dto.UpdateQuotaRequest true "Operação e ajuste"
+// @Success      200 {object} dto.SuccessResponse{data=dto.UserQuotasResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/quotas/users/{id} [put]
+func UpdateUserQuota(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := quotaUser(c, cfg)
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateQuotaRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+		action, err := validateQuotaUpdate(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid quota settings", err.Error()))
+			return
+		}
+
+		ctx := c.Request.Context()
+		before, err := middleware.Quotas.Usage(ctx, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve quotas", err.Error()))
+			return
+		}
+
+		if req.Limit != nil || req.UseDefault {
+			err = middleware.Quotas.SetLimit(ctx, userID, action, req.Limit)
+		}
+		if err == nil && req.ResetUsage {
+			err = middleware.Quotas.ResetUsage(ctx, userID, action)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to update quota", err.Error()))
+			return
+		}
+
+		after, err := middleware.Quotas.Usage(ctx, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve quotas", err.Error()))
+			return
+		}
+
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntityQuota, strconv.Itoa(userID), before, after)
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.UserQuotasResponse{UserId: userID, Quotas: after}, "Quota updated successfully"))
+	}
+}
+
+// quotaUser lê o ID do usuário da rota e confirma que ele existe; responde o erro quando não
+func quotaUser(c *gin.Context, cfg *config.App) (int, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid user ID", nil))
+		return 0, false
+	}
+
+	if middleware.Quotas == nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve quotas", "quotas are not configured"))
+		return 0, false
+	}
+
+	if _, err := cfg.SqlServer.GetUserByID(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "User not found", err.Error()))
+		return 0, false
+	}
+	return userID, true
+}
+
+// validateQuotaUpdate confere a operação e que o pedido ajusta algo sem combinar cota própria e limite padrão
+func validateQuotaUpdate(req dto.UpdateQuotaRequest) (middleware.QuotaAction, error) {
+	action := middleware.QuotaAction(req.Action)
+	if !action.Valid() {
+		return "", fmt.Errorf("action must be one of %v", middleware.QuotaActions)
+	}
+	if req.Limit != nil && req.UseDefault {
+		return "", fmt.Errorf("limit and useDefault cannot be combined")
+	}
+	if req.Limit == nil && !req.UseDefault && !req.ResetUsage {
+		return "", fmt.Errorf("set limit, useDefault or resetUsage")
+	}
+	return action, nil
+}
@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateQuotaUpdate(t *testing.T) {
+	limit := 100
+
+	tests := []struct {
+		name           string
+		req            dto.UpdateQuotaRequest
+		expectedAction middleware.QuotaAction
+		expectedError  string
+	}{
+		{
+			name:           "Custom limit",
+			req:            dto.UpdateQuotaRequest{Action: "export", Limit: &limit},
+			expectedAction: middleware.QuotaExport,
+		},
+		{
+			name:           "Back to the default and reset today's usage",
+			req:            dto.UpdateQuotaRequest{Action: "reindex", UseDefault: true, ResetUsage: true},
+			expectedAction: middleware.QuotaReindex,
+		},
+		{
+			name:          "Unknown action",
+			req:           dto.UpdateQuotaRequest{Action: "download", Limit: &limit},
+			expectedError: "action must be one of [export bulk_import reindex]",
+		},
+		{
+			name:          "Limit and default together",
+			req:           dto.UpdateQuotaRequest{Action: "export", Limit: &limit, UseDefault: true},
+			expectedError: "limit and useDefault cannot be combined",
+		},
+		{
+			name:          "Nothing to change",
+			req:           dto.UpdateQuotaRequest{Action: "bulk_import"},
+			expectedError: "set limit, useDefault or resetUsage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, err := validateQuotaUpdate(tt.req)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAction, action)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"orderstreamrest/internal/utils"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultErrorWindow é a janela da taxa de erros quando window não é informado, em minutos
+	defaultErrorWindow = 60
+	// maxErrorWindow limita a janela da taxa de erros a um dia
+	maxErrorWindow = 24 * 60
+	// recentTicketsDays é o período dos tickets recentes do resumo
+	recentTicketsDays = 30
+)
+
+// GetSummary retorna os indicadores da página inicial do administrador
+// @Summary      Resumo do Sistema
+// @Description  Reúne em uma chamada os usuários ativos por tipo, o total de tickets e a taxa de erros das requisições registradas nos logs. As fontes são consultadas em paralelo; uma seção cuja fonte falhar vem nula e é listada em unavailable.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        window query int false "Janela da taxa de erros, em minutos (máximo 1440)" default(60)
+// @Success      200 {object} dto.SuccessResponse{data=dto.AdminSummaryResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Router       /admin/summary [get]
+func GetSummary(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		window := defaultErrorWindow
+		if value := c.Query("window"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > maxErrorWindow {
+				c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid window",
+					fmt.Sprintf("window must be between 1 and %d minutes", maxErrorWindow)))
+				return
+			}
+			window = parsed
+		}
+
+		ctx := c.Request.Context()
+		now := time.Now().UTC()
+
+		var (
+			response dto.AdminSummaryResponse
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+		)
+		section := func(name string, load func(ctx context.Context) error) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := load(ctx); err != nil {
+					cfg.Logger.Warn(fmt.Sprintf("Admin summary section %s unavailable: %v", name, err))
+					mu.Lock()
+					response.Unavailable = append(response.Unavailable, name)
+					mu.Unlock()
+				}
+			}()
+		}
+
+		section("users", func(ctx context.Context) error {
+			counts, err := cfg.SqlServer.CountUsersByType(ctx)
+			if err != nil {
+				return err
+			}
+			response.Users = usersSummary(counts)
+			return nil
+		})
+
+		section("tickets", func(ctx context.Context) error {
+			total, err := cfg.Metrics.GetTotalTickets(ctx, dto.MetricsFilter{})
+			if err != nil {
+				return err
+			}
+			since := now.AddDate(0, 0, -recentTicketsDays)
+			recent, err := cfg.Metrics.GetTotalTickets(ctx, dto.MetricsFilter{StartDate: &since})
+			if err != nil {
+				return err
+			}
+			response.Tickets = &dto.TicketsSummary{Total: total, Last30Days: recent}
+			return nil
+		})
+
+		section("errors", func(ctx context.Context) error {
+			stats, err := cfg.ES.GetRequestStats(ctx, now.Add(-time.Duration(window)*time.Minute))
+			if err != nil {
+				return err
+			}
+			response.Errors = errorRateSummary(stats, window)
+			return nil
+		})
+
+		wg.Wait()
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Admin summary retrieved successfully"))
+	}
+}
+
+// usersSummary soma os usuários por situação e os ativos por tipo canônico, com todos os tipos
+// presentes mesmo sem usuários
+func usersSummary(counts []sqlserver.UserTypeCount) *dto.UsersSummary {
+	summary := &dto.UsersSummary{ActiveByRole: make(map[string]int64)}
+	for _, role := range utils.UserTypes.Values() {
+		summary.ActiveByRole[role] = 0
+	}
+
+	for _, count := range counts {
+		if !count.IsActive {
+			summary.Inactive += count.Total
+			continue
+		}
+		summary.Active += count.Total
+		summary.ActiveByRole[utils.UserTypes.Normalize(count.UserType)] += count.Total
+	}
+	return summary
+}
+
+// errorRateSummary calcula a porcentagem de respostas 5xx, com duas casas decimais
+func errorRateSummary(stats elsearch.RequestStats, window int) *dto.ErrorRateSummary {
+	summary := &dto.ErrorRateSummary{
+		WindowMinutes: window,
+		Requests:      stats.Requests,
+		ClientErrors:  stats.ClientErrors,
+		ServerErrors:  stats.ServerErrors,
+	}
+	if stats.Requests > 0 {
+		summary.ServerErrorPercent = math.Round(float64(stats.ServerErrors)/float64(stats.Requests)*10000) / 100
+	}
+	return summary
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsersSummary(t *testing.T) {
+	summary := usersSummary([]sqlserver.UserTypeCount{
+		{UserType: "ADMIN", IsActive: true, Total: 2},
+		{UserType: "AGENT", IsActive: true, Total: 10},
+		{UserType: "support", IsActive: true, Total: 1},
+		{UserType: "AGENT", IsActive: false, Total: 4},
+		{UserType: "LEGACY", IsActive: true, Total: 1},
+	})
+
+	assert.Equal(t, &dto.UsersSummary{
+		Active:   14,
+		Inactive: 4,
+		ActiveByRole: map[string]int64{
+			"ADMIN":   2,
+			"MANAGER": 0,
+			"AGENT":   11,
+			"VIEWER":  0,
+			"LEGACY":  1,
+		},
+	}, summary)
+}
+
+func TestErrorRateSummary(t *testing.T) {
+	tests := []struct {
+		name            string
+		stats           elsearch.RequestStats
+		expectedPercent float64
+	}{
+		{name: "No requests", stats: elsearch.RequestStats{}, expectedPercent: 0},
+		{name: "Rounded to two decimals", stats: elsearch.RequestStats{Requests: 3000, ClientErrors: 40, ServerErrors: 2}, expectedPercent: 0.07},
+		{name: "Only server errors", stats: elsearch.RequestStats{Requests: 4, ServerErrors: 4}, expectedPercent: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := errorRateSummary(tt.stats, 60)
+
+			assert.Equal(t, 60, summary.WindowMinutes)
+			assert.Equal(t, tt.stats.Requests, summary.Requests)
+			assert.Equal(t, tt.stats.ClientErrors, summary.ClientErrors)
+			assert.Equal(t, tt.stats.ServerErrors, summary.ServerErrors)
+			assert.Equal(t, tt.expectedPercent, summary.ServerErrorPercent)
+		})
+	}
+}
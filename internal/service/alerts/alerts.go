@@ -0,0 +1,115 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	alertDateLayout = "2006-01-02"
+
+	defaultAlertsPageSize = 20
+	maxAlertsPageSize     = 100
+)
+
+// ListAlerts lista os alertas de volume de tickets
+// @Summary      Alertas de Volume
+// @Description  Retorna os dias em que a quantidade de tickets abertos ficou acima da linha de base dos 28 dias anteriores, do mais recente para o mais antigo. WARNING indica ao menos 3 desvios-padrão acima da média e CRITICAL ao menos 5. Os alertas são gerados de hora em hora pela rotina ticket_volume_anomalies.
+// @Tags         alerts
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        severity query string false "Severidade" Enums(WARNING, CRITICAL)
+// @Param        startDate query string false "Data inicial (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final, inclusiva (YYYY-MM-DD)"
+// @Param        page query int false "Página" default(1)
+// @Param        pageSize query int false "Itens por página (máximo 100)" default(20)
+// @Success      200 {object} dto.PaginatedResponse{data=[]dto.TicketVolumeAlertResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /alerts [get]
+func ListAlerts(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseAlertFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid alert filter", err.Error()))
+			return
+		}
+
+		page := middleware.ParsePage(c, defaultAlertsPageSize, maxAlertsPageSize)
+		alerts, count, err := cfg.SqlServer.ListTicketVolumeAlerts(c.Request.Context(), filter, page.Offset(), page.Size)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve alerts")
+			return
+		}
+
+		entries := make([]dto.TicketVolumeAlertResponse, 0, len(alerts))
+		for _, alert := range alerts {
+			entries = append(entries, alertResponse(alert))
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, entries, middleware.NewPagination(c, page, count), "Alerts retrieved successfully"))
+	}
+}
+
+// parseAlertFilter lê os parâmetros severity, startDate e endDate (YYYY-MM-DD) da query
+func parseAlertFilter(c *gin.Context) (sqlserver.TicketVolumeAlertFilter, error) {
+	var filter sqlserver.TicketVolumeAlertFilter
+
+	filter.Severity = strings.ToUpper(strings.TrimSpace(c.Query("severity")))
+	if filter.Severity != "" && filter.Severity != entities.AlertSeverityWarning && filter.Severity != entities.AlertSeverityCritical {
+		return filter, fmt.Errorf("invalid severity %q, expected WARNING or CRITICAL", filter.Severity)
+	}
+
+	if value := c.Query("startDate"); value != "" {
+		startDate, err := time.Parse(alertDateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid startDate %q, expected format YYYY-MM-DD", value)
+		}
+		filter.From = &startDate
+	}
+
+	if value := c.Query("endDate"); value != "" {
+		endDate, err := time.Parse(alertDateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid endDate %q, expected format YYYY-MM-DD", value)
+		}
+		// endDate é inclusiva: o filtro vai até o início do dia seguinte
+		to := endDate.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return filter, errors.New("endDate must not be before startDate")
+	}
+
+	return filter, nil
+}
+
+// alertResponse converte o alerta gravado na resposta da API
+func alertResponse(alert entities.TicketVolumeAlert) dto.TicketVolumeAlertResponse {
+	return dto.TicketVolumeAlertResponse{
+		Id:             alert.Id,
+		Day:            alert.Day.Format(alertDateLayout),
+		Tickets:        alert.Tickets,
+		BaselineMean:   alert.BaselineMean,
+		BaselineStdDev: alert.BaselineStdDev,
+		BaselineDays:   alert.BaselineDays,
+		ZScore:         alert.ZScore,
+		Severity:       alert.Severity,
+		CreatedAt:      alert.CreatedAt,
+		UpdatedAt:      alert.UpdatedAt,
+	}
+}
@@ -0,0 +1,167 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/service/jobs"
+	"time"
+)
+
+// VolumeAnalyzerJobName é a rotina que compara o volume diário de tickets com a linha de base
+const VolumeAnalyzerJobName = "ticket_volume_anomalies"
+
+const (
+	// baselineDays é a janela de dias anteriores usada como linha de base de cada dia analisado
+	baselineDays = 28
+	// minBaselineDays é o histórico mínimo para que um dia seja analisado
+	minBaselineDays = 7
+
+	// warningZScore e criticalZScore são os desvios-padrão acima da média de cada severidade
+	warningZScore  = 3.0
+	criticalZScore = 5.0
+	// minSpikeRatio exige que o dia tenha ao menos 25% a mais de tickets que a média, para que uma
+	// linha de base muito estável não gere alertas por poucos tickets
+	minSpikeRatio = 1.25
+	// minSpikeTickets ignora picos em volumes pequenos demais para importar
+	minSpikeTickets = 20
+)
+
+func init() {
+	jobs.Register(jobs.Job{
+		Name:        VolumeAnalyzerJobName,
+		Description: "Compares yesterday's and today's ticket counts with the previous 28 days and records WARNING or CRITICAL alerts on volume spikes",
+		Defaults: entities.ScheduledJob{
+			Enabled:         true,
+			IntervalMinutes: 60,
+		},
+		Run: analyzeTicketVolume,
+	})
+}
+
+// volumeStats são os números de um dia comparado com a sua linha de base
+type volumeStats struct {
+	Tickets int64
+	Mean    float64
+	StdDev  float64
+	Days    int
+	ZScore  float64
+}
+
+// analyzeTicketVolume analisa ontem (dia completo) e hoje (parcial; como só picos geram alerta, um dia
+// incompleto não gera falso positivo) e grava um alerta para cada dia acima da linha de base.
+// Retorna a quantidade de alertas gravados.
+func analyzeTicketVolume(ctx context.Context, cfg *config.App, _ entities.ScheduledJob) (int64, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+
+	searchCtx := elsearch.WithScope(ctx, elsearch.UnrestrictedScope())
+	counts, err := cfg.ES.GetDailyTicketCounts(searchCtx, yesterday.AddDate(0, 0, -baselineDays), today.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+
+	var saved int64
+	for _, day := range []time.Time{yesterday, today} {
+		baseline, count, ok := baselineFor(counts, day)
+		if !ok {
+			continue
+		}
+
+		stats := volumeStatistics(baseline, count)
+		severity := spikeSeverity(stats)
+		if severity == "" {
+			continue
+		}
+
+		alert := &entities.TicketVolumeAlert{
+			Day:            day,
+			Tickets:        stats.Tickets,
+			BaselineMean:   stats.Mean,
+			BaselineStdDev: stats.StdDev,
+			BaselineDays:   stats.Days,
+			ZScore:         stats.ZScore,
+			Severity:       severity,
+			CreatedAt:      time.Now().UTC(),
+		}
+		created, err := cfg.SqlServer.SaveTicketVolumeAlert(ctx, alert)
+		if err != nil {
+			return saved, err
+		}
+		saved++
+
+		if created {
+			cfg.Logger.Warn(fmt.Sprintf("Ticket volume spike on %s: %d tickets, baseline %.1f", day.Format(alertDateLayout), stats.Tickets, stats.Mean), map[string]interface{}{
+				"alert_id": alert.Id,
+				"severity": severity,
+				"z_score":  stats.ZScore,
+			})
+		}
+	}
+
+	return saved, nil
+}
+
+// baselineFor separa a contagem do dia e as contagens dos baselineDays dias anteriores a ele
+func baselineFor(counts []elsearch.DailyTicketCount, day time.Time) ([]int64, int64, bool) {
+	start := day.AddDate(0, 0, -baselineDays)
+
+	var baseline []int64
+	var count int64
+	found := false
+	for _, item := range counts {
+		switch {
+		case item.Day.Equal(day):
+			count, found = item.Count, true
+		case !item.Day.Before(start) && item.Day.Before(day):
+			baseline = append(baseline, item.Count)
+		}
+	}
+	return baseline, count, found
+}
+
+// volumeStatistics calcula a média e o desvio-padrão da linha de base e o z-score do dia.
+// O desvio-padrão usado no z-score é no mínimo 1, para que uma linha de base constante não divida por zero.
+func volumeStatistics(baseline []int64, count int64) volumeStats {
+	stats := volumeStats{Tickets: count, Days: len(baseline)}
+	if len(baseline) == 0 {
+		return stats
+	}
+
+	var sum float64
+	for _, value := range baseline {
+		sum += float64(value)
+	}
+	stats.Mean = sum / float64(len(baseline))
+
+	var squares float64
+	for _, value := range baseline {
+		squares += math.Pow(float64(value)-stats.Mean, 2)
+	}
+	stats.StdDev = math.Sqrt(squares / float64(len(baseline)))
+
+	stats.ZScore = (float64(count) - stats.Mean) / math.Max(stats.StdDev, 1)
+	return stats
+}
+
+// spikeSeverity classifica o dia; vazio quando não há pico ou o histórico é curto demais
+func spikeSeverity(stats volumeStats) string {
+	if stats.Days < minBaselineDays || stats.Tickets < minSpikeTickets {
+		return ""
+	}
+	if float64(stats.Tickets) < stats.Mean*minSpikeRatio {
+		return ""
+	}
+
+	switch {
+	case stats.ZScore >= criticalZScore:
+		return entities.AlertSeverityCritical
+	case stats.ZScore >= warningZScore:
+		return entities.AlertSeverityWarning
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,82 @@
+package alerts
+
+import (
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/repositories/elsearch"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// repeat devolve uma linha de base com o mesmo valor em todos os dias
+func repeat(value int64, days int) []int64 {
+	baseline := make([]int64, days)
+	for i := range baseline {
+		baseline[i] = value
+	}
+	return baseline
+}
+
+func TestSpikeSeverity(t *testing.T) {
+	// Média 100 e desvio-padrão 10
+	alternating := make([]int64, 0, baselineDays)
+	// Média 100 e desvio-padrão 5
+	stable := make([]int64, 0, baselineDays)
+	for i := 0; i < baselineDays/2; i++ {
+		alternating = append(alternating, 90, 110)
+		stable = append(stable, 95, 105)
+	}
+
+	tests := []struct {
+		name     string
+		baseline []int64
+		count    int64
+		expected string
+	}{
+		{name: "within the baseline", baseline: alternating, count: 120},
+		{name: "four deviations but below the minimum ratio", baseline: stable, count: 120},
+		{name: "warning", baseline: alternating, count: 140, expected: entities.AlertSeverityWarning},
+		{name: "critical", baseline: alternating, count: 150, expected: entities.AlertSeverityCritical},
+		{name: "drop is not a spike", baseline: alternating, count: 0},
+		{name: "constant baseline uses a deviation of one", baseline: repeat(10, baselineDays), count: 40, expected: entities.AlertSeverityCritical},
+		{name: "too few tickets", baseline: repeat(1, baselineDays), count: minSpikeTickets - 1},
+		{name: "short history", baseline: repeat(10, minBaselineDays-1), count: 500},
+		{name: "no history", count: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, spikeSeverity(volumeStatistics(tt.baseline, tt.count)))
+		})
+	}
+}
+
+func TestVolumeStatistics(t *testing.T) {
+	stats := volumeStatistics([]int64{90, 110, 90, 110}, 130)
+
+	assert.Equal(t, int64(130), stats.Tickets)
+	assert.Equal(t, 4, stats.Days)
+	assert.InDelta(t, 100, stats.Mean, 1e-9)
+	assert.InDelta(t, 10, stats.StdDev, 1e-9)
+	assert.InDelta(t, 3, stats.ZScore, 1e-9)
+}
+
+func TestBaselineFor(t *testing.T) {
+	day := time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC)
+
+	var counts []elsearch.DailyTicketCount
+	for offset := -baselineDays - 2; offset <= 1; offset++ {
+		counts = append(counts, elsearch.DailyTicketCount{Day: day.AddDate(0, 0, offset), Count: int64(offset + 100)})
+	}
+
+	baseline, count, ok := baselineFor(counts, day)
+	assert.True(t, ok)
+	assert.Equal(t, int64(100), count)
+	assert.Len(t, baseline, baselineDays)
+	assert.Equal(t, int64(100-baselineDays), baseline[0])
+	assert.Equal(t, int64(99), baseline[len(baseline)-1])
+
+	_, _, ok = baselineFor(counts, day.AddDate(0, 0, 5))
+	assert.False(t, ok)
+}
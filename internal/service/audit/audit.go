@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"encoding/json"
+	"log"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/entities"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ações registradas na trilha de auditoria
+const (
+	ActionCreate = "CREATE"
+	ActionUpdate = "UPDATE"
+	ActionDelete = "DELETE"
+	ActionImport = "IMPORT"
+	// ActionExport registra quem exportou dados e com quais filtros
+	ActionExport = "EXPORT"
+)
+
+// Tipos de registro auditados
+const (
+	EntityUser    = "USER"
+	EntitySession = "SESSION"
+	// EntitySynonyms é o conjunto de sinônimos do índice de tickets
+	EntitySynonyms = "SYNONYMS"
+	// EntityTag é uma tag canônica de dbo.Dim_Tags, identificada pela TagKey
+	EntityTag = "TAG"
+	// EntityQuota são as cotas diárias de um usuário, identificadas pelo ID do usuário
+	EntityQuota = "QUOTA"
+	// EntityIPRules são as listas de redes aceitas e recusadas nas rotas sensíveis
+	EntityIPRules = "IP_RULES"
+)
+
+// Record grava na trilha de auditoria a ação do usuário autenticado sobre um registro.
+// before e after são serializados em JSON; nil indica que o registro não existia antes ou deixou de existir.
+// Falhas ao gravar a auditoria não devem desfazer a operação, apenas são logadas.
+func Record(c *gin.Context, cfg *config.App, action, entityType, entityId string, before, after interface{}) {
+	entry := &entities.AuditLog{
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Before:     snapshot(before),
+		After:      snapshot(after),
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if actorId, ok := middleware.GetCurrentUserID(c); ok {
+		entry.ActorId = &actorId
+	}
+	if ip := c.ClientIP(); ip != "" {
+		entry.IPAddress = &ip
+	}
+
+	if err := cfg.SqlServer.CreateAuditLog(c.Request.Context(), entry); err != nil {
+		log.Printf("Failed to record audit log (%s %s %s): %v", action, entityType, entityId, err)
+	}
+}
+
+// snapshot serializa o estado do registro. Campos marcados com json:"-", como o hash da senha, ficam de fora.
+func snapshot(value interface{}) *string {
+	if value == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+
+	encoded := string(data)
+	return &encoded
+}
@@ -0,0 +1,128 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	filterDateLayout = "2006-01-02"
+
+	defaultAuditPageSize = 20
+	maxAuditPageSize     = 100
+)
+
+// ListAuditLogs lista a trilha de auditoria
+// @Summary      Trilha de Auditoria
+// @Description  Retorna as ações administrativas registradas (quem criou, alterou ou removeu qual registro), da mais recente para a mais antiga, com o estado antes e depois de cada ação
+// @Tags         audit
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        actorId query int false "ID do usuário que executou a ação"
+// @Param        action query string false "Ação" Enums(CREATE, UPDATE, DELETE, IMPORT, EXPORT)
+// @Param        entityType query string false "Tipo do registro afetado" Enums(USER)
+// @Param        entityId query string false "ID do registro afetado"
+// @Param        startDate query string false "Data inicial (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final, inclusiva (YYYY-MM-DD)"
+// @Param        page query int false "Página" default(1)
+// @Param        pageSize query int false "Itens por página (máximo 100)" default(20)
+// @Success      200 {object} dto.PaginatedResponse{data=[]dto.AuditLogResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /audit [get]
+func ListAuditLogs(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseAuditFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid audit filter", err.Error()))
+			return
+		}
+
+		page := middleware.ParsePage(c, defaultAuditPageSize, maxAuditPageSize)
+		logs, count, err := cfg.SqlServer.ListAuditLogs(c.Request.Context(), filter, page.Offset(), page.Size)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve audit logs", err.Error()))
+			return
+		}
+
+		entries := make([]dto.AuditLogResponse, 0, len(logs))
+		for _, entry := range logs {
+			entries = append(entries, dto.AuditLogResponse{
+				Id:         entry.Id,
+				ActorId:    entry.ActorId,
+				Action:     entry.Action,
+				EntityType: entry.EntityType,
+				EntityId:   entry.EntityId,
+				Before:     rawSnapshot(entry.Before),
+				After:      rawSnapshot(entry.After),
+				IPAddress:  entry.IPAddress,
+				CreatedAt:  entry.CreatedAt,
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, entries, middleware.NewPagination(c, page, count), "Audit logs retrieved successfully"))
+	}
+}
+
+// parseAuditFilter lê os parâmetros actorId, action, entityType, entityId, startDate e endDate (YYYY-MM-DD) da query
+func parseAuditFilter(c *gin.Context) (sqlserver.AuditLogFilter, error) {
+	var filter sqlserver.AuditLogFilter
+
+	if value := c.Query("actorId"); value != "" {
+		actorId, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid actorId %q", value)
+		}
+		filter.ActorId = &actorId
+	}
+
+	filter.Action = strings.ToUpper(strings.TrimSpace(c.Query("action")))
+	filter.EntityType = strings.ToUpper(strings.TrimSpace(c.Query("entityType")))
+	filter.EntityId = strings.TrimSpace(c.Query("entityId"))
+
+	if value := c.Query("startDate"); value != "" {
+		startDate, err := time.Parse(filterDateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid startDate %q, expected format YYYY-MM-DD", value)
+		}
+		filter.From = &startDate
+	}
+
+	if value := c.Query("endDate"); value != "" {
+		endDate, err := time.Parse(filterDateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid endDate %q, expected format YYYY-MM-DD", value)
+		}
+		// endDate é inclusiva: o filtro vai até o início do dia seguinte
+		to := endDate.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return filter, errors.New("endDate must not be before startDate")
+	}
+
+	return filter, nil
+}
+
+// rawSnapshot devolve o estado gravado como JSON, para não ser reescapado na resposta
+func rawSnapshot(value *string) json.RawMessage {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return json.RawMessage(*value)
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuditFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		query       string
+		expectError bool
+
+		expectedActor      int
+		expectedAction     string
+		expectedEntityType string
+		expectedFrom       string
+		expectedTo         string
+	}{
+		{
+			name:  "Success - No filter",
+			query: "",
+		},
+		{
+			name:               "Success - Actor, action and entity",
+			query:              "actorId=7&action=update&entityType=user",
+			expectedActor:      7,
+			expectedAction:     "UPDATE",
+			expectedEntityType: "USER",
+		},
+		{
+			name:         "Success - End date is inclusive",
+			query:        "startDate=2025-10-01&endDate=2025-10-01",
+			expectedFrom: "2025-10-01",
+			expectedTo:   "2025-10-02",
+		},
+		{
+			name:        "Error - Invalid actor",
+			query:       "actorId=abc",
+			expectError: true,
+		},
+		{
+			name:        "Error - Invalid date",
+			query:       "startDate=01/10/2025",
+			expectError: true,
+		},
+		{
+			name:        "Error - End before start",
+			query:       "startDate=2025-10-02&endDate=2025-10-01",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/audit?"+tt.query, nil)
+
+			filter, err := parseAuditFilter(c)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.expectedActor != 0 {
+				require.NotNil(t, filter.ActorId)
+				assert.Equal(t, tt.expectedActor, *filter.ActorId)
+			} else {
+				assert.Nil(t, filter.ActorId)
+			}
+			assert.Equal(t, tt.expectedAction, filter.Action)
+			assert.Equal(t, tt.expectedEntityType, filter.EntityType)
+
+			if tt.expectedFrom != "" {
+				require.NotNil(t, filter.From)
+				assert.Equal(t, tt.expectedFrom, filter.From.Format(filterDateLayout))
+			}
+			if tt.expectedTo != "" {
+				require.NotNil(t, filter.To)
+				assert.Equal(t, tt.expectedTo, filter.To.Format(filterDateLayout))
+			}
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	type record struct {
+		Name   string  `json:"name"`
+		Secret *string `json:"-"`
+	}
+	secret := "hash"
+
+	assert.Nil(t, snapshot(nil))
+	assert.Nil(t, snapshot((*record)(nil)))
+
+	encoded := snapshot(&record{Name: "Ana", Secret: &secret})
+	require.NotNil(t, encoded)
+	assert.JSONEq(t, `{"name":"Ana"}`, *encoded)
+}
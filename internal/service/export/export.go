@@ -0,0 +1,233 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// Format é o formato de resposta solicitado em ?format=
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	// FormatNDJSON só é usado pelas exportações em stream, não é aceito em ParseFormat
+	FormatNDJSON Format = "ndjson"
+
+	CSVContentType    = "text/csv; charset=utf-8"
+	NDJSONContentType = "application/x-ndjson"
+	xlsxContentType   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// maxSheetNameLength é o limite do Excel para o nome de uma aba
+	maxSheetNameLength = 31
+)
+
+// ErrUnsupportedFormat is returned when the format query parameter is not json, csv or xlsx
+var ErrUnsupportedFormat error = apperror.New(apperror.ErrValidation, "unsupported export format")
+
+// utf8BOM faz o Excel reconhecer acentos ao abrir o CSV
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Table é o conteúdo de um arquivo exportado. As células podem ser string, inteiros ou float64.
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]any
+}
+
+// ParseFormat lê o formato da query; a ausência do parâmetro resulta em JSON
+func ParseFormat(c *gin.Context) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(c.Query("format")))) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("%w: %q (use json, csv or xlsx)", ErrUnsupportedFormat, c.Query("format"))
+	}
+}
+
+// Requested informa se a requisição pede um arquivo em vez de JSON
+func Requested(c *gin.Context) bool {
+	format, err := ParseFormat(c)
+	return err == nil && format != FormatJSON
+}
+
+// Pool executa no pool informado apenas as requisições que pedem um arquivo;
+// as respostas JSON continuam no semáforo interativo.
+func Pool(pool *middleware.WorkerPool) gin.HandlerFunc {
+	queued := pool.Middleware()
+	return func(c *gin.Context) {
+		if !Requested(c) {
+			c.Next()
+			return
+		}
+		queued(c)
+	}
+}
+
+// Write envia a tabela como anexo no formato informado
+func Write(c *gin.Context, format Format, name string, table Table) {
+	total := int64(len(table.Rows))
+	middleware.ReportProgress(c, 0, total)
+
+	var (
+		buf         bytes.Buffer
+		contentType string
+		err         error
+	)
+
+	switch format {
+	case FormatCSV:
+		contentType = CSVContentType
+		err = WriteCSV(&buf, table)
+	case FormatXLSX:
+		contentType = xlsxContentType
+		err = WriteXLSX(&buf, table)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to export data", err.Error()))
+		return
+	}
+
+	middleware.ReportProgress(c, total, total)
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, Filename(name, format, time.Now())))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// Filename monta o nome do arquivo exportado com a data da exportação
+func Filename(name string, format Format, now time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", name, now.Format("20060102-150405"), format)
+}
+
+// NewCSVWriter escreve o BOM e o cabeçalho e retorna o writer das linhas, para exportações
+// enviadas aos poucos. Quem chama é responsável pelo Flush.
+func NewCSVWriter(w io.Writer, header []string) (*csv.Writer, error) {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return nil, fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+	return writer, nil
+}
+
+// CSVRecord converte as células de uma linha para texto
+func CSVRecord(row []any) []string {
+	record := make([]string, 0, len(row))
+	for _, cell := range row {
+		record = append(record, formatCell(cell))
+	}
+	return record
+}
+
+// WriteCSV escreve a tabela em CSV, com cabeçalho na primeira linha
+func WriteCSV(w io.Writer, table Table) error {
+	writer, err := NewCSVWriter(w, table.Header)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range table.Rows {
+		if err := writer.Write(CSVRecord(row)); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// WriteXLSX escreve a tabela em uma planilha com uma única aba
+func WriteXLSX(w io.Writer, table Table) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := sheetName(table.Name)
+	if err := file.SetSheetName(file.GetSheetName(0), sheet); err != nil {
+		return fmt.Errorf("failed to name sheet: %w", err)
+	}
+
+	header := make([]any, len(table.Header))
+	for i, column := range table.Header {
+		header[i] = column
+	}
+	if err := file.SetSheetRow(sheet, "A1", &header); err != nil {
+		return fmt.Errorf("failed to write xlsx header: %w", err)
+	}
+
+	for i, row := range table.Rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return fmt.Errorf("failed to write xlsx row: %w", err)
+		}
+		if err := file.SetSheetRow(sheet, cell, &row); err != nil {
+			return fmt.Errorf("failed to write xlsx row: %w", err)
+		}
+	}
+
+	if err := file.Write(w); err != nil {
+		return fmt.Errorf("failed to write xlsx: %w", err)
+	}
+	return nil
+}
+
+// sheetName remove os caracteres que o Excel não aceita em nomes de aba
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+
+	if name == "" {
+		return "Sheet1"
+	}
+	if runes := []rune(name); len(runes) > maxSheetNameLength {
+		name = string(runes[:maxSheetNameLength])
+	}
+	return name
+}
+
+// formatCell converte uma célula para texto no CSV
+func formatCell(cell any) string {
+	switch value := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
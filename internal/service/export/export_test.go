@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected Format
+		wantErr  bool
+	}{
+		{name: "Missing format defaults to json", query: "", expected: FormatJSON},
+		{name: "CSV", query: "?format=csv", expected: FormatCSV},
+		{name: "XLSX is case insensitive", query: "?format=XLSX", expected: FormatXLSX},
+		{name: "Unknown format", query: "?format=pdf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/metrics/tickets"+tt.query, nil)
+
+			format, err := ParseFormat(c)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupportedFormat)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	table := MeanTimeByPriority([]dto.MeanTimeByPriority{
+		{PriorityName: "CRÍTICA", MeanTimeHour: 12.5, MeanTimeDay: 0.52, MedianTimeHour: 8, P90TimeHour: 30, P99TimeHour: 70.5},
+		{PriorityName: "BAIXA, sem SLA", MeanTimeHour: 48, MeanTimeDay: 2, MedianTimeHour: 40, P90TimeHour: 96, P99TimeHour: 200},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, table))
+
+	expected := "\xEF\xBB\xBF" +
+		"priorityName,meanTimeHour,meanTimeDay,medianTimeHour,p90TimeHour,p99TimeHour\n" +
+		"CRÍTICA,12.5,0.52,8,30,70.5\n" +
+		"\"BAIXA, sem SLA\",48,2,40,96,200\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteXLSX(t *testing.T) {
+	table := GroupedYearlyData("TicketsByStatusYearMonth", "status", dto.TicketsByStatusYearMonth{
+		"Fechado": {"2024": {{Janeiro: 3, Dezembro: 7}}},
+		"Aberto":  {"2025": {{Fevereiro: 1}}, "2024": {{Marco: 2}}},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteXLSX(&buf, table))
+
+	file, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows("TicketsByStatusYearMonth")
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, []string{"status", "ano", "janeiro", "fevereiro"}, rows[0][:4])
+	assert.Equal(t, []string{"Aberto", "2024", "0", "0", "2"}, rows[1][:5])
+	assert.Equal(t, []string{"Aberto", "2025", "0", "1"}, rows[2][:4])
+	assert.Equal(t, "Fechado", rows[3][0])
+	assert.Equal(t, "7", rows[3][13])
+}
+
+func TestTicketsMetrics(t *testing.T) {
+	table := TicketsMetrics(dto.TicketsMetricsResponse{
+		TotalTickets: 15,
+		Metrics: []dto.TypeMetric{
+			{Name: "TicketsByChannel", Values: []dto.MetricValue{{Name: "Email", Value: 10}, {Name: "Chat", Value: 5}}},
+		},
+	})
+
+	assert.Equal(t, [][]any{
+		{"TotalTickets", "", int64(15)},
+		{"TicketsByChannel", "Email", int64(10)},
+		{"TicketsByChannel", "Chat", int64(5)},
+	}, table.Rows)
+}
+
+func TestSheetName(t *testing.T) {
+	assert.Equal(t, "Sheet1", sheetName(""))
+	assert.Equal(t, "a-b-c", sheetName("a/b:c"))
+	assert.Len(t, []rune(sheetName("tickets-breakdown-department-with-a-long-name")), maxSheetNameLength)
+}
+
+func TestFilename(t *testing.T) {
+	now := time.Date(2025, 3, 9, 14, 5, 0, 0, time.UTC)
+	assert.Equal(t, "tickets-metrics-20250309-140500.xlsx", Filename("tickets-metrics", FormatXLSX, now))
+}
+
+func TestResolutionPercentiles(t *testing.T) {
+	table := ResolutionPercentiles("category", []dto.PercentileMetrics{
+		{Name: "Financeiro", Tickets: 10, MeanHours: 20.5, P50Hours: 12, P90Hours: 48, P99Hours: 96.2},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, table))
+
+	expected := "\xEF\xBB\xBF" +
+		"category,tickets,meanHours,p50Hours,p90Hours,p99Hours\n" +
+		"Financeiro,10,20.5,12,48,96.2\n"
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"sort"
+)
+
+// monthColumns são as colunas mensais, na mesma grafia do JSON de dto.MonthlyCounts
+var monthColumns = []string{
+	"janeiro", "fevereiro", "marco", "abril", "maio", "junho",
+	"julho", "agosto", "setembro", "outubro", "novembro", "dezembro",
+}
+
+// TicketsMetrics converte as métricas agregadas em uma linha por valor de cada métrica,
+// precedidas pelo total de tickets
+func TicketsMetrics(response dto.TicketsMetricsResponse) Table {
+	table := Table{
+		Name:   "TicketsMetrics",
+		Header: []string{"metric", "name", "value"},
+		Rows:   [][]any{{"TotalTickets", "", response.TotalTickets}},
+	}
+
+	for _, metric := range response.Metrics {
+		for _, value := range metric.Values {
+			table.Rows = append(table.Rows, []any{metric.Name, value.Name, value.Value})
+		}
+	}
+
+	return table
+}
+
+// MetricValues converte uma lista de valores de uma dimensão
+func MetricValues(name string, values []dto.MetricValue) Table {
+	table := Table{
+		Name:   name,
+		Header: []string{"name", "value"},
+		Rows:   make([][]any, 0, len(values)),
+	}
+
+	for _, value := range values {
+		table.Rows = append(table.Rows, []any{value.Name, value.Value})
+	}
+
+	return table
+}
+
+// MeanTimeByPriority converte o tempo médio de resolução por prioridade
+func MeanTimeByPriority(values []dto.MeanTimeByPriority) Table {
+	table := Table{
+		Name:   "MeanTimeByPriority",
+		Header: []string{"priorityName", "meanTimeHour", "meanTimeDay", "medianTimeHour", "p90TimeHour", "p99TimeHour"},
+		Rows:   make([][]any, 0, len(values)),
+	}
+
+	for _, value := range values {
+		table.Rows = append(table.Rows, []any{value.PriorityName, value.MeanTimeHour, value.MeanTimeDay, value.MedianTimeHour, value.P90TimeHour, value.P99TimeHour})
+	}
+
+	return table
+}
+
+// ResolutionPercentiles converte a distribuição do tempo de resolução por valor de dimensão
+func ResolutionPercentiles(dimension string, values []dto.PercentileMetrics) Table {
+	table := Table{
+		Name:   "ResolutionPercentiles",
+		Header: []string{dimension, "tickets", "meanHours", "p50Hours", "p90Hours", "p99Hours"},
+		Rows:   make([][]any, 0, len(values)),
+	}
+
+	for _, value := range values {
+		table.Rows = append(table.Rows, []any{value.Name, value.Tickets, value.MeanHours, value.P50Hours, value.P90Hours, value.P99Hours})
+	}
+
+	return table
+}
+
+// YearlyData converte as contagens mensais em uma linha por ano, em ordem crescente
+func YearlyData(name string, data dto.YearlyData) Table {
+	table := Table{
+		Name:   name,
+		Header: append([]string{"ano"}, monthColumns...),
+	}
+
+	for _, year := range sortedKeys(data) {
+		for _, counts := range data[year] {
+			table.Rows = append(table.Rows, append([]any{year}, monthlyCells(counts)...))
+		}
+	}
+
+	return table
+}
+
+// GroupedYearlyData converte as contagens mensais agrupadas (por status ou prioridade)
+// em uma linha por grupo e ano; group é o nome da coluna do agrupamento
+func GroupedYearlyData(name, group string, data dto.TicketsByStatusYearMonth) Table {
+	table := Table{
+		Name:   name,
+		Header: append([]string{group, "ano"}, monthColumns...),
+	}
+
+	for _, key := range sortedKeys(data) {
+		yearly := data[key]
+		for _, year := range sortedKeys(yearly) {
+			for _, counts := range yearly[year] {
+				table.Rows = append(table.Rows, append([]any{key, year}, monthlyCells(counts)...))
+			}
+		}
+	}
+
+	return table
+}
+
+// monthlyCells retorna as contagens na ordem de monthColumns
+func monthlyCells(counts dto.MonthlyCounts) []any {
+	return []any{
+		counts.Janeiro, counts.Fevereiro, counts.Marco, counts.Abril, counts.Maio, counts.Junho,
+		counts.Julho, counts.Agosto, counts.Setembro, counts.Outubro, counts.Novembro, counts.Dezembro,
+	}
+}
+
+// sortedKeys retorna as chaves do mapa em ordem crescente, para que o arquivo seja estável
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
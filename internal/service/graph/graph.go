@@ -0,0 +1,122 @@
+// Package graph expõe em /graphql as consultas de leitura usadas pelos painéis, para que o frontend
+// monte um painel inteiro em uma requisição. Os resolvers chamam os mesmos repositórios e funções de
+// serviço das rotas REST e aplicam as mesmas permissões.
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+)
+
+const (
+	// maxDepth limita o aninhamento das consultas
+	maxDepth = 8
+	// maxParallelism limita quantos resolvers de uma consulta rodam ao mesmo tempo
+	maxParallelism = 10
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// ErrForbidden é retornado pelos campos que o tipo de usuário não pode consultar
+var ErrForbidden error = apperror.New(apperror.ErrForbidden, "User does not have permission to access this resource")
+
+// ginContextKey guarda o contexto do Gin, com as claims do JWT, no contexto dos resolvers
+type ginContextKey struct{}
+
+// Handler executa consultas GraphQL
+// @Summary      GraphQL
+// @Description  Executa uma consulta GraphQL sobre usuários, métricas e tickets. O schema está em internal/service/graph/schema.graphql e pode ser lido por introspecção.
+// @Description  A resposta segue o formato GraphQL ({data, errors}); erros de um campo não impedem os demais e trazem o status HTTP equivalente em extensions.status.
+// @Tags         graphql
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        request body dto.GraphQLRequest true "Operação GraphQL"
+// @Success      200 {object} map[string]interface{}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Router       /graphql [post]
+func Handler(cfg *config.App) gin.HandlerFunc {
+	schema := graphql.MustParseSchema(schemaSDL, &resolver{cfg: cfg},
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+
+	return func(c *gin.Context) {
+		var req dto.GraphQLRequest
+		if !middleware.BindJSON(c, &req, "Invalid GraphQL request") {
+			return
+		}
+
+		ctx := context.WithValue(c.Request.Context(), ginContextKey{}, c)
+		response := schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ginContext retorna o contexto do Gin da requisição em execução
+func ginContext(ctx context.Context) *gin.Context {
+	c, _ := ctx.Value(ginContextKey{}).(*gin.Context)
+	return c
+}
+
+// requireRoles aplica a mesma regra de middleware.RequireRoles a um campo
+func requireRoles(ctx context.Context, roles ...utils.Role) error {
+	c := ginContext(ctx)
+	if c == nil {
+		return ErrForbidden
+	}
+
+	current := middleware.GetCurrentRole(c)
+	for _, role := range roles {
+		if role == current {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// resolverError expõe ao cliente o status HTTP equivalente ao erro em extensions.status
+type resolverError struct {
+	err error
+}
+
+func (e resolverError) Error() string {
+	var appErr *apperror.Error
+	if errors.As(e.err, &appErr) {
+		return appErr.Message
+	}
+	return e.err.Error()
+}
+
+func (e resolverError) Unwrap() error {
+	return e.err
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"status": apperror.HTTPStatus(e.err),
+	}
+}
+
+// fieldError embrulha o erro de um resolver; erros sem tipo conhecido ficam como 500
+func fieldError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return resolverError{err: err}
+}
@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"orderstreamrest/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphResponse é a resposta GraphQL decodificada nos testes
+type graphResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, role float64, body string) (int, graphResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.POST("/graphql", func(c *gin.Context) {
+		c.Set("currentUser", jwt.MapClaims{"user_id": float64(1), "role": role})
+		c.Next()
+	}, Handler(&config.App{}))
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	var response graphResponse
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	}
+	return rec.Code, response
+}
+
+func TestAgentsWorkloadRequiresManagerRole(t *testing.T) {
+	tests := []struct {
+		name string
+		role float64
+	}{
+		{name: "Agent", role: 3},
+		{name: "Viewer", role: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := execute(t, tt.role, `{"query":"{ agentsWorkload { agentId } }"}`)
+
+			require.Equal(t, http.StatusOK, status)
+			require.Len(t, response.Errors, 1)
+			assert.Equal(t, "User does not have permission to access this resource", response.Errors[0].Message)
+			assert.EqualValues(t, http.StatusForbidden, response.Errors[0].Extensions["status"])
+		})
+	}
+}
+
+func TestInvalidMetricsFilter(t *testing.T) {
+	status, response := execute(t, 1, `{"query":"{ ticketsMetrics(filter: {startDate: \"2025-13-01\"}) { totalTickets } }"}`)
+
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, response.Errors, 1)
+	assert.EqualValues(t, http.StatusBadRequest, response.Errors[0].Extensions["status"])
+}
+
+func TestQueryValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "Missing query", body: `{}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "Unknown field", body: `{"query":"{ password }"}`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := execute(t, 1, tt.body)
+
+			assert.Equal(t, tt.wantStatus, status)
+			if status == http.StatusOK {
+				assert.NotEmpty(t, response.Errors)
+				assert.Nil(t, response.Data)
+			}
+		})
+	}
+}
+
+func TestNewTicket(t *testing.T) {
+	result := newTicket(map[string]interface{}{
+		"ticket_id":      "TCK-1",
+		"current_status": float64(2),
+		"company":        map[string]interface{}{"id": float64(7), "name": "Acme"},
+		"dates":          map[string]interface{}{"created_at": "2025-06-01T12:00:00Z", "closed_at": nil},
+		"sla_metrics":    map[string]interface{}{"first_response_sla_breached": true},
+	})
+
+	assert.Equal(t, "TCK-1", result.TicketId)
+	assert.Equal(t, int32(2), result.CurrentStatus)
+	assert.Equal(t, int32(7), result.CompanyId)
+	assert.Equal(t, "Acme", result.Company)
+	assert.Equal(t, "2025-06-01T12:00:00Z", result.CreatedAt)
+	assert.Nil(t, result.ClosedAt)
+	assert.Nil(t, result.FirstResponseAt)
+	assert.True(t, result.FirstResponseSlaBreached)
+}
@@ -0,0 +1,411 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/service/metrics"
+	"orderstreamrest/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errNotAuthenticated é retornado quando não há usuário no contexto
+var errNotAuthenticated error = apperror.New(apperror.ErrForbidden, "User not authenticated")
+
+// resolver é a raiz das consultas
+type resolver struct {
+	cfg *config.App
+}
+
+// Me retorna o usuário autenticado
+func (r *resolver) Me(ctx context.Context) (*user, error) {
+	c := ginContext(ctx)
+	if c == nil {
+		return nil, fieldError(errNotAuthenticated)
+	}
+
+	userId, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		return nil, fieldError(errNotAuthenticated)
+	}
+
+	found, err := r.cfg.SqlServer.GetUserByID(ctx, userId)
+	if err != nil {
+		return nil, fieldError(err)
+	}
+	return newUser(found), nil
+}
+
+// Users lista os usuários com a mesma paginação de GET /users
+func (r *resolver) Users(ctx context.Context, args struct {
+	Page       int32
+	PageSize   int32
+	OnlyActive bool
+}) (*userPage, error) {
+	page, pageSize := args.Page, args.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	users, total, err := r.cfg.SqlServer.GetAllUsers(ctx, int(page), int(pageSize), args.OnlyActive)
+	if err != nil {
+		return nil, fieldError(err)
+	}
+
+	result := &userPage{
+		Users:      make([]*user, 0, len(users)),
+		TotalCount: int32(total),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	for i := range users {
+		result.Users = append(result.Users, newUser(&users[i]))
+	}
+	return result, nil
+}
+
+// TicketsMetrics retorna os totais de GET /metrics/tickets
+func (r *resolver) TicketsMetrics(ctx context.Context, args struct {
+	Filter *metricsFilter
+	Top    int32
+}) (*ticketsMetrics, error) {
+	filter, err := args.Filter.parse(ctx)
+	if err != nil {
+		return nil, fieldError(err)
+	}
+
+	top := int(args.Top)
+	if top < 0 {
+		top = 0
+	}
+
+	response, err := metrics.BuildTicketsMetrics(ctx, r.cfg, filter, top)
+	if err != nil {
+		return nil, fieldError(err)
+	}
+
+	result := &ticketsMetrics{
+		TotalTickets: int32(response.TotalTickets),
+		Metrics:      make([]*typeMetric, 0, len(response.Metrics)),
+	}
+	for _, metric := range response.Metrics {
+		item := &typeMetric{Name: metric.Name, Values: make([]*metricValue, 0, len(metric.Values))}
+		for _, value := range metric.Values {
+			item.Values = append(item.Values, &metricValue{Name: value.Name, Value: int32(value.Value)})
+		}
+		result.Metrics = append(result.Metrics, item)
+	}
+	return result, nil
+}
+
+// AgentsWorkload retorna a carga dos agentes, como GET /metrics/agents/workload
+func (r *resolver) AgentsWorkload(ctx context.Context, args struct {
+	Filter *metricsFilter
+}) ([]*agentWorkload, error) {
+	if err := requireRoles(ctx, utils.RoleAdmin, utils.RoleManager); err != nil {
+		return nil, fieldError(err)
+	}
+
+	filter, err := args.Filter.parse(ctx)
+	if err != nil {
+		return nil, fieldError(err)
+	}
+
+	workload, err := metrics.LoadAgentsWorkload(ctx, r.cfg, filter)
+	if err != nil {
+		return nil, fieldError(err)
+	}
+
+	result := make([]*agentWorkload, 0, len(workload))
+	for _, agent := range workload {
+		result = append(result, &agentWorkload{
+			AgentId:                agent.AgentId,
+			Name:                   agent.Name,
+			Team:                   agent.Team,
+			OpenTickets:            int32(agent.OpenTickets),
+			AssignedTickets:        int32(agent.AssignedTickets),
+			AcknowledgedTickets:    int32(agent.AcknowledgedTickets),
+			MttaHours:              agent.MTTAHours,
+			TeamAverageOpenTickets: agent.TeamAverageOpen,
+			LoadRatio:              agent.LoadRatio,
+		})
+	}
+	return result, nil
+}
+
+// SearchTickets executa a busca de GET /tickets/query
+func (r *resolver) SearchTickets(ctx context.Context, args struct {
+	Query    string
+	Page     int32
+	PageSize int32
+}) (*ticketPage, error) {
+	response, err := r.cfg.ES.SearchTicketsBySomeWord(ctx, dto.SearchParams{
+		Query:    args.Query,
+		Page:     int(args.Page),
+		PageSize: int(args.PageSize),
+	})
+	if err != nil {
+		return nil, fieldError(err)
+	}
+
+	documents := response.Tickets
+	result := &ticketPage{
+		Tickets: make([]*ticket, 0, len(documents)),
+		Pagination: &pagination{
+			CurrentPage:  int32(response.Pagination.CurrentPage),
+			PerPage:      int32(response.Pagination.PerPage),
+			TotalPages:   int32(response.Pagination.TotalPages),
+			TotalRecords: int32(response.Pagination.TotalRecords),
+			HasNext:      response.Pagination.HasNext,
+			HasPrev:      response.Pagination.HasPrev,
+		},
+	}
+	for _, document := range documents {
+		result.Tickets = append(result.Tickets, newTicket(document))
+	}
+	return result, nil
+}
+
+// Ticket busca um ticket pelo ticket_id, dentro do escopo do usuário
+func (r *resolver) Ticket(ctx context.Context, args struct {
+	Id string
+}) (*ticket, error) {
+	document, err := r.cfg.ES.SearchTicketByID(ctx, strings.TrimSpace(args.Id))
+	if err != nil {
+		return nil, fieldError(err)
+	}
+	if document == nil {
+		return nil, nil
+	}
+	return newTicket(*document), nil
+}
+
+// metricsFilter é o input MetricsFilter, com os nomes dos parâmetros das rotas de métricas
+type metricsFilter struct {
+	StartDate  *string
+	EndDate    *string
+	Year       *int32
+	Company    *string
+	Department *string
+	Channel    *string
+	Priority   *string
+	Timezone   *string
+}
+
+// parse monta o filtro com as mesmas validações e o mesmo escopo de empresas das rotas REST
+func (f *metricsFilter) parse(ctx context.Context) (dto.MetricsFilter, error) {
+	filter, err := metrics.ParseMetricsFilter(ctx, func(name string) string {
+		if f == nil {
+			return ""
+		}
+		switch name {
+		case "startDate":
+			return optional(f.StartDate)
+		case "endDate":
+			return optional(f.EndDate)
+		case "year":
+			if f.Year == nil {
+				return ""
+			}
+			return strconv.Itoa(int(*f.Year))
+		case "company":
+			return optional(f.Company)
+		case "department":
+			return optional(f.Department)
+		case "channel":
+			return optional(f.Channel)
+		case "priority":
+			return optional(f.Priority)
+		case "timezone":
+			return optional(f.Timezone)
+		default:
+			return ""
+		}
+	})
+	if err != nil {
+		return filter, apperror.Wrap(apperror.ErrValidation, "Invalid date filter", err)
+	}
+	return filter, nil
+}
+
+func optional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+type user struct {
+	Id          int32
+	Name        string
+	Email       string
+	UserType    string
+	CompanyId   *int32
+	IsActive    bool
+	CreatedAt   string
+	LastLoginAt *string
+}
+
+func newUser(entity *entities.User) *user {
+	result := &user{
+		Id:        int32(entity.Id),
+		Name:      entity.Name,
+		Email:     entity.Email,
+		UserType:  entity.UserType,
+		IsActive:  entity.IsActive,
+		CreatedAt: entity.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if entity.CompanyId != nil {
+		companyId := int32(*entity.CompanyId)
+		result.CompanyId = &companyId
+	}
+	if entity.LastLoginAt != nil {
+		lastLogin := entity.LastLoginAt.UTC().Format(time.RFC3339)
+		result.LastLoginAt = &lastLogin
+	}
+	return result
+}
+
+type userPage struct {
+	Users      []*user
+	TotalCount int32
+	Page       int32
+	PageSize   int32
+}
+
+type ticketsMetrics struct {
+	TotalTickets int32
+	Metrics      []*typeMetric
+}
+
+type typeMetric struct {
+	Name   string
+	Values []*metricValue
+}
+
+type metricValue struct {
+	Name  string
+	Value int32
+}
+
+type agentWorkload struct {
+	AgentId                string
+	Name                   string
+	Team                   string
+	OpenTickets            int32
+	AssignedTickets        int32
+	AcknowledgedTickets    int32
+	MttaHours              *float64
+	TeamAverageOpenTickets float64
+	LoadRatio              float64
+}
+
+type ticketPage struct {
+	Tickets    []*ticket
+	Pagination *pagination
+}
+
+type pagination struct {
+	CurrentPage  int32
+	PerPage      int32
+	TotalPages   int32
+	TotalRecords int32
+	HasNext      bool
+	HasPrev      bool
+}
+
+type ticket struct {
+	TicketId                 string
+	Title                    string
+	Description              string
+	CurrentStatus            int32
+	Priority                 string
+	Channel                  string
+	Category                 string
+	Subcategory              string
+	Product                  string
+	CompanyId                int32
+	Company                  string
+	CreatedBy                string
+	AssignedAgent            string
+	CreatedAt                string
+	FirstResponseAt          *string
+	ClosedAt                 *string
+	FirstResponseSlaBreached bool
+	ResolutionSlaBreached    bool
+}
+
+// newTicket converte o documento do Elasticsearch no tipo Ticket
+func newTicket(document map[string]interface{}) *ticket {
+	return &ticket{
+		TicketId:                 stringField(document, "ticket_id"),
+		Title:                    stringField(document, "title"),
+		Description:              stringField(document, "description"),
+		CurrentStatus:            int32(numberField(document, "current_status")),
+		Priority:                 stringField(document, "priority"),
+		Channel:                  stringField(document, "channel"),
+		Category:                 stringField(document, "category.name"),
+		Subcategory:              stringField(document, "subcategory.name"),
+		Product:                  stringField(document, "product.name"),
+		CompanyId:                int32(numberField(document, "company.id")),
+		Company:                  stringField(document, "company.name"),
+		CreatedBy:                stringField(document, "created_by_user.full_name"),
+		AssignedAgent:            stringField(document, "assigned_agent.full_name"),
+		CreatedAt:                stringField(document, "dates.created_at"),
+		FirstResponseAt:          optionalStringField(document, "dates.first_response_at"),
+		ClosedAt:                 optionalStringField(document, "dates.closed_at"),
+		FirstResponseSlaBreached: boolField(document, "sla_metrics.first_response_sla_breached"),
+		ResolutionSlaBreached:    boolField(document, "sla_metrics.resolution_sla_breached"),
+	}
+}
+
+// field retorna o valor no caminho com pontos do documento, ou nil quando ausente
+func field(document map[string]interface{}, path string) interface{} {
+	var value interface{} = document
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+func stringField(document map[string]interface{}, path string) string {
+	switch value := field(document, path).(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func optionalStringField(document map[string]interface{}, path string) *string {
+	if field(document, path) == nil {
+		return nil
+	}
+	value := stringField(document, path)
+	return &value
+}
+
+func numberField(document map[string]interface{}, path string) float64 {
+	value, _ := field(document, path).(float64)
+	return value
+}
+
+func boolField(document map[string]interface{}, path string) bool {
+	value, _ := field(document, path).(bool)
+	return value
+}
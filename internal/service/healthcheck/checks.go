@@ -0,0 +1,187 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"log"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/pkg/logger"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	statusOK          = "OK"
+	statusDegraded    = "DEGRADED"
+	statusUnavailable = "UNAVAILABLE"
+
+	defaultCheckTimeout = 2 * time.Second
+)
+
+// errNotConfigured é retornado quando a dependência não foi inicializada
+var errNotConfigured = errors.New("not configured")
+
+// dependencyCheck é a verificação de uma dependência. Uma dependência crítica indisponível
+// deixa o serviço UNAVAILABLE; as demais apenas DEGRADED.
+type dependencyCheck struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+// dependencyChecks retorna as verificações das dependências configuradas em cfg
+func dependencyChecks(cfg *config.App) []dependencyCheck {
+	checks := []dependencyCheck{
+		{
+			Name:     "sqlserver",
+			Critical: true,
+			Run: func(ctx context.Context) error {
+				if cfg.SqlServer == nil {
+					return errNotConfigured
+				}
+				return cfg.SqlServer.Ping(ctx)
+			},
+		},
+		{
+			Name: "sqlserver_dw",
+			Run: func(ctx context.Context) error {
+				if cfg.SqlServer == nil {
+					return errNotConfigured
+				}
+				return cfg.SqlServer.PingWarehouse(ctx)
+			},
+		},
+		{
+			Name:     "elasticsearch",
+			Critical: true,
+			Run: func(ctx context.Context) error {
+				if cfg.ES == nil {
+					return errNotConfigured
+				}
+				return cfg.ES.PingContext(ctx)
+			},
+		},
+		{
+			// O rate limiter e o cache de métricas continuam funcionando sem o Redis
+			Name: "redis",
+			Run: func(ctx context.Context) error {
+				if cfg.Redis == nil {
+					return errNotConfigured
+				}
+				return cfg.Redis.Ping(ctx).Err()
+			},
+		},
+	}
+
+	// O arquivo de eventos dos tickets é opcional e só é verificado quando configurado
+	if cfg.Mongo != nil {
+		checks = append(checks, dependencyCheck{
+			Name: "mongodb",
+			Run:  cfg.Mongo.Ping,
+		})
+	}
+
+	// Sem a réplica as métricas falham, mas login e usuários continuam no primário
+	if cfg.SqlServer != nil && cfg.SqlServer.HasReplica() {
+		checks = append(checks, dependencyCheck{
+			Name: "sqlserver_replica",
+			Run:  cfg.SqlServer.PingReplica,
+		})
+	}
+
+	return checks
+}
+
+// runChecks executa as verificações em paralelo, cada uma com o seu limite de tempo,
+// e retorna o status geral e o resultado de cada dependência
+func runChecks(ctx context.Context, checks []dependencyCheck, timeout time.Duration) (string, map[string]dto.DependencyHealth) {
+	results := make(map[string]dto.DependencyHealth, len(checks))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check dependencyCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			started := time.Now()
+			err := runCheck(checkCtx, check)
+
+			result := dto.DependencyHealth{
+				Status:    statusOK,
+				Critical:  check.Critical,
+				LatencyMs: time.Since(started).Milliseconds(),
+			}
+			if err != nil {
+				result.Status = statusUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	return overallStatus(results), results
+}
+
+// runCheck executa a verificação sem esperar além do prazo do contexto, mesmo que o cliente ignore o contexto
+func runCheck(ctx context.Context, check dependencyCheck) error {
+	done := make(chan error, 1)
+	go func() { done <- check.Run(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// overallStatus combina os resultados: qualquer dependência crítica fora deixa o serviço UNAVAILABLE
+func overallStatus(results map[string]dto.DependencyHealth) string {
+	status := statusOK
+	for _, result := range results {
+		if result.Status == statusOK {
+			continue
+		}
+		if result.Critical {
+			return statusUnavailable
+		}
+		status = statusDegraded
+	}
+	return status
+}
+
+// healthLogLevel é o nível dos logs de GET /healthcheck, configurável em HEALTHCHECK_LOG_LEVEL
+// (DEBUG, INFO, WARN; padrão INFO). NONE desliga esses logs; retorna false nesse caso.
+func healthLogLevel() (logger.LogLevel, bool) {
+	switch level := logger.LogLevel(strings.ToUpper(strings.TrimSpace(os.Getenv("HEALTHCHECK_LOG_LEVEL")))); level {
+	case "":
+		return logger.LevelInfo, true
+	case "NONE", "OFF":
+		return "", false
+	case logger.LevelDebug, logger.LevelInfo, logger.LevelWarn:
+		return level, true
+	default:
+		log.Printf("Invalid HEALTHCHECK_LOG_LEVEL %q, using INFO", level)
+		return logger.LevelInfo, true
+	}
+}
+
+// checkTimeout é o limite de tempo de cada verificação, configurável em HEALTHCHECK_TIMEOUT_MS
+func checkTimeout() time.Duration {
+	if value, err := strconv.Atoi(os.Getenv("HEALTHCHECK_TIMEOUT_MS")); err == nil && value > 0 {
+		return time.Duration(value) * time.Millisecond
+	}
+	return defaultCheckTimeout
+}
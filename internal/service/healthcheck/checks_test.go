@@ -0,0 +1,80 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"orderstreamrest/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunChecks(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error { time.Sleep(time.Second); return nil }
+
+	tests := []struct {
+		name     string
+		checks   []dependencyCheck
+		expected string
+	}{
+		{
+			name:     "All dependencies OK",
+			checks:   []dependencyCheck{{Name: "sqlserver", Critical: true, Run: ok}, {Name: "redis", Run: ok}},
+			expected: statusOK,
+		},
+		{
+			name:     "Non critical dependency down",
+			checks:   []dependencyCheck{{Name: "sqlserver", Critical: true, Run: ok}, {Name: "redis", Run: failing}},
+			expected: statusDegraded,
+		},
+		{
+			name:     "Critical dependency down",
+			checks:   []dependencyCheck{{Name: "sqlserver", Critical: true, Run: failing}, {Name: "redis", Run: ok}},
+			expected: statusUnavailable,
+		},
+		{
+			name:     "Check ignoring its context times out",
+			checks:   []dependencyCheck{{Name: "elasticsearch", Critical: true, Run: hanging}},
+			expected: statusUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, results := runChecks(context.Background(), tt.checks, 50*time.Millisecond)
+			assert.Equal(t, tt.expected, status)
+			assert.Len(t, results, len(tt.checks))
+			for _, check := range tt.checks {
+				assert.Equal(t, check.Critical, results[check.Name].Critical)
+			}
+		})
+	}
+}
+
+func TestHealthLogLevel(t *testing.T) {
+	tests := []struct {
+		value   string
+		level   logger.LogLevel
+		enabled bool
+	}{
+		{value: "", level: logger.LevelInfo, enabled: true},
+		{value: "debug", level: logger.LevelDebug, enabled: true},
+		{value: " WARN ", level: logger.LevelWarn, enabled: true},
+		{value: "NONE", enabled: false},
+		{value: "off", enabled: false},
+		{value: "verbose", level: logger.LevelInfo, enabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("HEALTHCHECK_LOG_LEVEL", tt.value)
+
+			level, enabled := healthLogLevel()
+			assert.Equal(t, tt.enabled, enabled)
+			assert.Equal(t, tt.level, level)
+		})
+	}
+}
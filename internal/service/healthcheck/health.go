@@ -1,10 +1,12 @@
 package healthcheck
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"orderstreamrest/internal/config"
 	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/pkg/logger"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,49 +16,39 @@ var startTime = time.Now()
 
 // Health godoc
 // @Summary      Health Check
-// @Description  Verifica a saúde do serviço. Endpoint protegido com autenticação e rate limiting.
+// @Description  Verifica a saúde do serviço consultando SQL Server (conexão e DW), Elasticsearch e Redis, cada um com limite de tempo.
+// @Description  UNAVAILABLE (503) quando uma dependência crítica (SQL Server ou Elasticsearch) falha; DEGRADED (200) quando apenas uma não crítica falha.
+// @Description  Inclui a ocupação do pool de conexões do SQL Server (databasePool) e, quando configurada, da réplica de leitura das métricas (replicaPool).
+// @Description  breakers mostra o estado dos circuit breakers do Elasticsearch e das métricas do SQL Server.
 // @Tags         health
 // @Accept       json
 // @Produce      json
-// @Security     BearerAuth
 // @Success      200  {object}  dto.HealthResponse           "Status do serviço"
-// @Failure      400  {object}  dto.ErrorResponse            "Bad Request"
-// @Failure      401  {object}  dto.AuthErrorResponse        "Unauthorized - Token inválido"
-// @Failure      403  {object}  dto.ErrorResponse            "Forbidden - Sem permissão"
 // @Failure      429  {object}  dto.RateLimitErrorResponse   "Rate limit excedido"
-// @Failure      500  {object}  dto.ErrorResponse            "Internal Server Error"
+// @Failure      503  {object}  dto.HealthResponse           "Dependência crítica indisponível"
 // @Header       200  {string}  X-RateLimit-Limit            "Limite de requests por minuto"
 // @Header       200  {string}  X-RateLimit-Remaining        "Requests restantes no período"
 // @Header       200  {string}  X-RateLimit-Reset            "Timestamp do reset do rate limit"
 // @Router       /healthcheck [get]
 func Health(cfg *config.App) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		cfg.Logger.Info(fmt.Sprintf("Healthcheck endpoint hit... IP %s", c.ClientIP()))
+	checks := dependencyChecks(cfg)
+	timeout := checkTimeout()
+	logLevel, logEnabled := healthLogLevel()
 
-		// Removi o log de erro desnecessário
-		// cfg.Logger.Error("Vish, deu erro aqui", errors.New("Ola"))
+	logHealth := func(message string) {
+		if logEnabled {
+			cfg.Logger.WithContext(logLevel, message, logger.LogContext{})
+		}
+	}
 
-		// Verificações de saúde do sistema
-		checks := make(map[string]string)
+	return func(c *gin.Context) {
+		logHealth(fmt.Sprintf("Healthcheck endpoint hit... IP %s", c.ClientIP()))
 
-		// Verificar conexão com Redis (exemplo)
-		if cfg.Redis != nil {
-			checks["redis"] = "OK"
-		} else {
-			checks["redis"] = "UNAVAILABLE"
-		}
+		status, dependencies := runChecks(c.Request.Context(), checks, timeout)
 
-		// Verificar outras dependências
-		checks["database"] = "OK" // substitua pela verificação real
-		checks["memory"] = "OK"   // você pode adicionar verificação de memória
-
-		// Determinar status geral
-		status := "OK"
-		for _, checkStatus := range checks {
-			if checkStatus != "OK" {
-				status = "DEGRADED"
-				break
-			}
+		summary := make(map[string]string, len(dependencies))
+		for name, dependency := range dependencies {
+			summary[name] = dependency.Status
 		}
 
 		uptime := time.Since(startTime).String()
@@ -67,17 +59,78 @@ func Health(cfg *config.App) gin.HandlerFunc {
 			"VisionData API",
 			"1.0.0",
 			uptime,
-			checks,
+			summary,
 		)
+		healthResponse.Dependencies = dependencies
+		healthResponse.DatabasePool, healthResponse.ReplicaPool = databasePools(cfg)
+		healthResponse.Breakers = breakerStates(cfg)
 
-		cfg.Logger.Info(fmt.Sprintf("Healthcheck status: %s", status))
+		logHealth(fmt.Sprintf("Healthcheck status: %s", status))
 
-		// Status HTTP baseado no status das verificações
+		// Só a falta de uma dependência crítica tira a instância de serviço
 		httpStatus := http.StatusOK
-		if status != "OK" {
+		if status == statusUnavailable {
 			httpStatus = http.StatusServiceUnavailable
 		}
 
 		c.JSON(httpStatus, healthResponse)
 	}
 }
+
+// databasePools retorna as estatísticas dos pools do SQL Server e da réplica, nil quando não estão disponíveis
+func databasePools(cfg *config.App) (*dto.DatabasePoolStats, *dto.DatabasePoolStats) {
+	if cfg.SqlServer == nil {
+		return nil, nil
+	}
+
+	var primary, replica *dto.DatabasePoolStats
+	if stats, err := cfg.SqlServer.PoolStats(); err == nil {
+		primary = poolStats(stats)
+	}
+	if stats, ok := cfg.SqlServer.ReplicaPoolStats(); ok {
+		replica = poolStats(stats)
+	}
+	return primary, replica
+}
+
+// breakerStates retorna o estado dos circuit breakers das dependências configuradas
+func breakerStates(cfg *config.App) map[string]string {
+	states := map[string]string{}
+	if cfg.ES != nil {
+		for name, breaker := range cfg.ES.Breakers() {
+			states[name] = breaker.State()
+		}
+	}
+	if cfg.SqlServer != nil {
+		name, breaker := cfg.SqlServer.MetricsBreaker()
+		states[name] = breaker.State()
+	}
+	return states
+}
+
+// poolStats converte as estatísticas do database/sql para a resposta
+func poolStats(stats sql.DBStats) *dto.DatabasePoolStats {
+	return &dto.DatabasePoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+// Live godoc
+// @Summary      Liveness
+// @Description  Indica apenas que o processo está de pé, sem consultar dependências. Usado pelo liveness probe do Kubernetes.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  dto.HealthResponse  "Processo ativo"
+// @Router       /healthcheck/live [get]
+func Live() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, dto.NewHealthResponse(c, statusOK, "VisionData API", "1.0.0", time.Since(startTime).String(), nil))
+	}
+}
@@ -0,0 +1,111 @@
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const statusPending = "PENDING"
+
+// Ping godoc
+// @Summary      Ping
+// @Description  Responde "pong" sem consultar dependências, sem gerar log e sem contar no rate limit. Feito para o HEALTHCHECK do Docker e probes executadas a cada poucos segundos.
+// @Tags         health
+// @Produce      plain
+// @Success      200  {string}  string  "pong"
+// @Router       /ping [get]
+func Ping() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	}
+}
+
+// Ready godoc
+// @Summary      Readiness
+// @Description  Retorna 200 apenas depois que os índices do Elasticsearch foram criados, as migrações do SQL Server executaram
+// @Description  e o Redis responde. Usado pelo readiness probe do Kubernetes para só enviar tráfego a instâncias prontas.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  dto.HealthResponse  "Instância pronta"
+// @Failure      503  {object}  dto.HealthResponse  "Etapas de inicialização pendentes ou Redis indisponível"
+// @Router       /healthcheck/ready [get]
+func Ready(cfg *config.App) gin.HandlerFunc {
+	redisCheck := dependencyCheck{
+		Name: "redis",
+		Run: func(ctx context.Context) error {
+			if cfg.Redis == nil {
+				return errNotConfigured
+			}
+			return cfg.Redis.Ping(ctx).Err()
+		},
+	}
+	timeout := checkTimeout()
+
+	return func(c *gin.Context) {
+		checks := startupChecks(cfg.Readiness)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		checks[redisCheck.Name] = statusOK
+		if err := runCheck(ctx, redisCheck); err != nil {
+			checks[redisCheck.Name] = statusUnavailable
+		}
+
+		respondProbe(c, checks)
+	}
+}
+
+// Startup godoc
+// @Summary      Startup
+// @Description  Retorna 200 depois que as etapas de inicialização (índices do Elasticsearch e migrações do SQL Server) terminaram.
+// @Description  Usado pelo startup probe do Kubernetes, para que o liveness probe só comece depois da inicialização.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  dto.HealthResponse  "Inicialização concluída"
+// @Failure      503  {object}  dto.HealthResponse  "Etapas de inicialização pendentes"
+// @Router       /healthcheck/startup [get]
+func Startup(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondProbe(c, startupChecks(cfg.Readiness))
+	}
+}
+
+// startupChecks retorna a situação de cada etapa de inicialização
+func startupChecks(readiness *config.Readiness) map[string]string {
+	checks := make(map[string]string)
+	if readiness == nil {
+		return checks
+	}
+
+	for step, done := range readiness.Steps() {
+		checks[step] = statusPending
+		if done {
+			checks[step] = statusOK
+		}
+	}
+	return checks
+}
+
+// respondProbe responde 200 quando todas as verificações estão OK e 503 caso contrário
+func respondProbe(c *gin.Context, checks map[string]string) {
+	status := statusOK
+	for _, check := range checks {
+		if check != statusOK {
+			status = statusUnavailable
+			break
+		}
+	}
+
+	httpStatus := http.StatusOK
+	if status != statusOK {
+		httpStatus = http.StatusServiceUnavailable
+	}
+
+	c.JSON(httpStatus, dto.NewHealthResponse(c, status, "VisionData API", "1.0.0", time.Since(startTime).String(), checks))
+}
@@ -0,0 +1,63 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartupProbe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	readiness := config.NewReadiness(config.ReadinessIndices, config.ReadinessMigrations)
+	cfg := &config.App{Readiness: readiness}
+
+	engine := gin.New()
+	engine.GET("/healthcheck/startup", Startup(cfg))
+
+	probe := func() (int, dto.HealthResponse) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthcheck/startup", nil))
+
+		var response dto.HealthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	readiness.Done(config.ReadinessIndices)
+	code, response := probe()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, statusUnavailable, response.Status)
+	assert.Equal(t, map[string]string{config.ReadinessIndices: statusOK, config.ReadinessMigrations: statusPending}, response.Checks)
+	assert.Equal(t, []string{config.ReadinessMigrations}, readiness.Pending())
+
+	readiness.Done(config.ReadinessMigrations)
+	code, response = probe()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, statusOK, response.Status)
+	assert.Empty(t, readiness.Pending())
+}
+
+func TestPing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.GET("/ping", Ping())
+	engine.HEAD("/ping", Ping())
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "pong", w.Body.String())
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/ping", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
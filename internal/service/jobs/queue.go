@@ -0,0 +1,138 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/entities"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// queueStream é o stream do Redis com as tarefas aguardando um worker
+	queueStream = "jobs:queue"
+	// queueGroup é o consumer group compartilhado pelos workers de todas as instâncias
+	queueGroup = "workers"
+
+	// defaultMaxAttempts é o número de tentativas das tarefas que não definem MaxAttempts
+	defaultMaxAttempts = 3
+	// defaultTaskTimeout limita a execução das tarefas que não definem Timeout
+	defaultTaskTimeout = 10 * time.Minute
+	// maxErrorMessage é o tamanho da coluna ErrorMessage
+	maxErrorMessage = 500
+)
+
+// ErrUnknownTask indica que não há tarefa registrada com o tipo informado
+var ErrUnknownTask error = apperror.New(apperror.ErrValidation, "unknown job type")
+
+// Task é um tipo de tarefa executada de forma assíncrona pelos workers da fila
+type Task struct {
+	// Tipo da tarefa, gravado em cada execução enviada à fila
+	Type string
+	// Descrição da tarefa
+	Description string
+	// Número de tentativas antes de a tarefa ser marcada como FAILED; 0 usa o padrão
+	MaxAttempts int
+	// Tempo máximo de cada tentativa; 0 usa o padrão
+	Timeout time.Duration
+	// Executa a tarefa com o payload enviado e retorna o resultado, gravado em JSON
+	Run func(ctx context.Context, cfg *config.App, payload json.RawMessage) (interface{}, error)
+}
+
+var tasks = map[string]Task{}
+
+// RegisterTask registra um tipo de tarefa na fila
+func RegisterTask(task Task) {
+	if task.MaxAttempts < 1 {
+		task.MaxAttempts = defaultMaxAttempts
+	}
+	if task.Timeout <= 0 {
+		task.Timeout = defaultTaskTimeout
+	}
+	tasks[task.Type] = task
+}
+
+// LookupTask retorna a tarefa registrada com o tipo informado
+func LookupTask(taskType string) (Task, bool) {
+	task, ok := tasks[taskType]
+	return task, ok
+}
+
+// RegisteredTasks retorna as tarefas registradas em ordem alfabética
+func RegisteredTasks() []Task {
+	registered := make([]Task, 0, len(tasks))
+	for _, task := range tasks {
+		registered = append(registered, task)
+	}
+	sort.Slice(registered, func(i, j int) bool { return registered[i].Type < registered[j].Type })
+	return registered
+}
+
+// Enqueue grava a tarefa em dbo.QueuedJobs e a envia à fila. O id retornado é consultado em
+// GET /admin/jobs/queue/{id}. Se o Redis recusar a tarefa, ela é gravada como FAILED e o erro é retornado.
+func Enqueue(ctx context.Context, cfg *config.App, taskType string, payload interface{}, requestedBy *int) (*entities.QueuedJob, error) {
+	task, ok := LookupTask(taskType)
+	if !ok {
+		return nil, ErrUnknownTask
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload of job %s: %w", taskType, err)
+	}
+
+	job := &entities.QueuedJob{
+		Id:          uuid.NewString(),
+		Type:        task.Type,
+		Payload:     string(data),
+		Status:      entities.QueuedJobQueued,
+		MaxAttempts: task.MaxAttempts,
+		RequestedBy: requestedBy,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := cfg.SqlServer.CreateQueuedJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if err := publish(ctx, queueClient(cfg), job.Id); err != nil {
+		finishedAt := time.Now().UTC()
+		job.Status = entities.QueuedJobFailed
+		job.FinishedAt = &finishedAt
+		job.ErrorMessage = errorMessage(err)
+		if updateErr := cfg.SqlServer.UpdateQueuedJob(context.Background(), job); updateErr != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Failed to record enqueue failure of job %s: %v", job.Id, updateErr))
+		}
+		return nil, apperror.Wrap(apperror.ErrUnavailable, "job queue is unavailable", err)
+	}
+
+	return job, nil
+}
+
+// queueClient retorna o cliente do Redis usado pela fila. Os comandos de stream usam o cliente
+// direto, já que a leitura bloqueante dos workers seguraria o lock dos métodos de RedisInternal.
+func queueClient(cfg *config.App) *redis.Client {
+	return cfg.Redis.Redis
+}
+
+// publish envia o id da tarefa ao stream da fila
+func publish(ctx context.Context, rdb *redis.Client, id string) error {
+	return rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: queueStream,
+		Values: map[string]interface{}{"id": id},
+	}).Err()
+}
+
+// errorMessage limita a mensagem de erro ao tamanho da coluna ErrorMessage
+func errorMessage(err error) *string {
+	message := err.Error()
+	if len(message) > maxErrorMessage {
+		message = message[:maxErrorMessage]
+	}
+	return &message
+}
@@ -0,0 +1,99 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/models/entities"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		attempts    int
+		maxAttempts int
+		err         error
+		expected    string
+	}{
+		{
+			name:        "Success",
+			attempts:    1,
+			maxAttempts: 3,
+			expected:    entities.QueuedJobSucceeded,
+		},
+		{
+			name:        "Transient failure is retried",
+			attempts:    1,
+			maxAttempts: 3,
+			err:         errors.New("connection reset"),
+			expected:    entities.QueuedJobQueued,
+		},
+		{
+			name:        "Timeout is retried",
+			attempts:    2,
+			maxAttempts: 3,
+			err:         context.DeadlineExceeded,
+			expected:    entities.QueuedJobQueued,
+		},
+		{
+			name:        "Last attempt fails",
+			attempts:    3,
+			maxAttempts: 3,
+			err:         errors.New("connection reset"),
+			expected:    entities.QueuedJobFailed,
+		},
+		{
+			name:        "Client error is not retried",
+			attempts:    1,
+			maxAttempts: 3,
+			err:         apperror.New(apperror.ErrValidation, "invalid payload"),
+			expected:    entities.QueuedJobFailed,
+		},
+		{
+			name:        "Unknown task is not retried",
+			attempts:    1,
+			maxAttempts: 3,
+			err:         ErrUnknownTask,
+			expected:    entities.QueuedJobFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextStatus(tt.attempts, tt.maxAttempts, tt.err))
+		})
+	}
+}
+
+func TestRegisterTaskDefaults(t *testing.T) {
+	RegisterTask(Task{Type: "test_defaults"})
+	RegisterTask(Task{Type: "test_long", MaxAttempts: 1, Timeout: time.Hour})
+	defer delete(tasks, "test_defaults")
+	defer delete(tasks, "test_long")
+
+	task, ok := LookupTask("test_defaults")
+	assert.True(t, ok)
+	assert.Equal(t, defaultMaxAttempts, task.MaxAttempts)
+	assert.Equal(t, defaultTaskTimeout, task.Timeout)
+
+	// Uma tarefa em execução não pode ser tomada por outro worker antes do seu timeout
+	assert.Equal(t, time.Hour+time.Minute, claimIdle())
+}
+
+func TestEnqueueUnknownTask(t *testing.T) {
+	job, err := Enqueue(context.Background(), nil, "does_not_exist", nil, nil)
+
+	assert.Nil(t, job)
+	assert.ErrorIs(t, err, ErrUnknownTask)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
+
+func TestErrorMessage(t *testing.T) {
+	assert.Equal(t, "boom", *errorMessage(errors.New("boom")))
+	assert.Len(t, *errorMessage(errors.New(strings.Repeat("x", maxErrorMessage+10))), maxErrorMessage)
+}
@@ -0,0 +1,234 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/entities"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// SourceSchedule identifica as execuções disparadas pelo agendador
+	SourceSchedule = "SCHEDULE"
+	// SourceManual identifica as execuções disparadas por um administrador
+	SourceManual = "MANUAL"
+	// SourceStartup identifica as execuções disparadas pela inicialização da instância
+	SourceStartup = "STARTUP"
+
+	// tickInterval é a frequência com que o agendador verifica as rotinas vencidas
+	tickInterval = time.Minute
+	// lockTTL limita por quanto tempo uma instância segura a execução de uma rotina
+	lockTTL = 30 * time.Minute
+)
+
+// ErrJobRunning indica que a rotina já está em execução nesta ou em outra instância
+var ErrJobRunning error = apperror.New(apperror.ErrConflict, "job is already running")
+
+// Job é uma rotina executada periodicamente em segundo plano
+type Job struct {
+	// Nome da rotina, usado como chave da configuração e do histórico
+	Name string
+	// Descrição exibida na listagem de rotinas
+	Description string
+	// Configuração gravada na primeira vez que a rotina é carregada
+	Defaults entities.ScheduledJob
+	// Políticas aceitas na configuração; vazio quando a rotina não usa política
+	Policies []string
+	// Startup executa a rotina também ao iniciar a instância, sem esperar o intervalo
+	Startup bool
+	// Executa a rotina com a configuração atual e retorna a quantidade de registros afetados
+	Run func(ctx context.Context, cfg *config.App, settings entities.ScheduledJob) (int64, error)
+}
+
+var (
+	registry = map[string]Job{}
+	// running evita duas execuções simultâneas da mesma rotina nesta instância
+	running sync.Map
+)
+
+// Register registra uma rotina no agendador
+func Register(job Job) {
+	job.Defaults.Name = job.Name
+	registry[job.Name] = job
+}
+
+// Lookup retorna a rotina registrada com o nome informado
+func Lookup(name string) (Job, bool) {
+	job, ok := registry[name]
+	return job, ok
+}
+
+// Registered retorna as rotinas registradas em ordem alfabética
+func Registered() []Job {
+	registered := make([]Job, 0, len(registry))
+	for _, job := range registry {
+		registered = append(registered, job)
+	}
+	sort.Slice(registered, func(i, j int) bool { return registered[i].Name < registered[j].Name })
+	return registered
+}
+
+// Start verifica a cada minuto as rotinas vencidas e as executa, até o contexto ser cancelado.
+// JOBS_ENABLED=false desliga o agendador nesta instância; as rotinas continuam disponíveis para execução manual.
+func Start(ctx context.Context, cfg *config.App) {
+	if os.Getenv("JOBS_ENABLED") == "false" {
+		cfg.Logger.Info("Background jobs disabled on this instance (JOBS_ENABLED=false)")
+		return
+	}
+
+	runStartupJobs(ctx, cfg)
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runDueJobs(ctx, cfg)
+		}
+	}
+}
+
+// runDueJobs executa as rotinas habilitadas cujo intervalo já passou desde a última execução
+func runDueJobs(ctx context.Context, cfg *config.App) {
+	for _, job := range Registered() {
+		settings, err := cfg.SqlServer.EnsureScheduledJob(ctx, job.Defaults)
+		if err != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Failed to load job %s: %v", job.Name, err))
+			continue
+		}
+
+		if !isDue(*settings, time.Now()) {
+			continue
+		}
+
+		if _, err := Execute(ctx, cfg, job, SourceSchedule, nil); err != nil && !errors.Is(err, ErrJobRunning) {
+			cfg.Logger.Warn(fmt.Sprintf("Job %s failed: %v", job.Name, err))
+		}
+	}
+}
+
+// runStartupJobs executa as rotinas habilitadas marcadas com Startup. Com várias instâncias subindo
+// juntas, o lock de Execute mantém uma única execução.
+func runStartupJobs(ctx context.Context, cfg *config.App) {
+	for _, job := range Registered() {
+		if !job.Startup {
+			continue
+		}
+
+		settings, err := cfg.SqlServer.EnsureScheduledJob(ctx, job.Defaults)
+		if err != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Failed to load job %s: %v", job.Name, err))
+			continue
+		}
+		if !settings.Enabled {
+			continue
+		}
+
+		if _, err := Execute(ctx, cfg, job, SourceStartup, nil); err != nil && !errors.Is(err, ErrJobRunning) {
+			cfg.Logger.Warn(fmt.Sprintf("Job %s failed on startup: %v", job.Name, err))
+		}
+	}
+}
+
+// isDue informa se a rotina deve ser executada agora
+func isDue(settings entities.ScheduledJob, now time.Time) bool {
+	if !settings.Enabled || settings.IntervalMinutes < 1 {
+		return false
+	}
+	if settings.LastRunAt == nil {
+		return true
+	}
+	return !now.Before(settings.LastRunAt.Add(time.Duration(settings.IntervalMinutes) * time.Minute))
+}
+
+// Execute executa a rotina uma vez e grava a execução no histórico.
+// Com várias instâncias da API, um lock no Redis garante que só uma delas execute a rotina;
+// sem o Redis a execução segue, já que as rotinas são idempotentes.
+func Execute(ctx context.Context, cfg *config.App, job Job, source string, triggeredBy *int) (*entities.JobRun, error) {
+	if _, busy := running.LoadOrStore(job.Name, struct{}{}); busy {
+		return nil, ErrJobRunning
+	}
+	defer running.Delete(job.Name)
+
+	lockKey := "jobs:lock:" + job.Name
+	acquired, err := cfg.Redis.SetNX(ctx, lockKey, time.Now().Unix(), lockTTL).Result()
+	if err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to lock job %s, running without lock: %v", job.Name, err))
+	} else if !acquired {
+		return nil, ErrJobRunning
+	} else {
+		defer cfg.Redis.Del(context.Background(), lockKey)
+	}
+
+	settings, err := cfg.SqlServer.EnsureScheduledJob(ctx, job.Defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &entities.JobRun{
+		JobName:     job.Name,
+		Source:      source,
+		TriggeredBy: triggeredBy,
+		StartedAt:   time.Now().UTC(),
+	}
+	if err := cfg.SqlServer.CreateJobRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	affected, runErr := job.Run(ctx, cfg, *settings)
+
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+	run.Affected = affected
+	run.Success = runErr == nil
+	if runErr != nil {
+		run.ErrorMessage = errorMessage(runErr)
+	}
+
+	if err := cfg.SqlServer.FinishJobRun(ctx, run); err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to record run of job %s: %v", job.Name, err))
+	}
+	if err := cfg.SqlServer.MarkScheduledJobRun(ctx, job.Name, run.StartedAt); err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to update last run of job %s: %v", job.Name, err))
+	}
+
+	if runErr != nil {
+		return run, runErr
+	}
+
+	cfg.Logger.Info(fmt.Sprintf("Job %s finished: %d records affected in %s", job.Name, affected, finishedAt.Sub(run.StartedAt)))
+	return run, nil
+}
+
+// ValidateSettings verifica uma nova configuração da rotina
+func ValidateSettings(job Job, settings entities.ScheduledJob) error {
+	if settings.IntervalMinutes < 1 {
+		return errors.New("intervalMinutes must be at least 1")
+	}
+	if settings.RetentionDays < 0 {
+		return errors.New("retentionDays must not be negative")
+	}
+
+	if len(job.Policies) == 0 {
+		if settings.Policy != "" {
+			return fmt.Errorf("job %s does not accept a policy", job.Name)
+		}
+		return nil
+	}
+
+	for _, policy := range job.Policies {
+		if policy == settings.Policy {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid policy %q for job %s", settings.Policy, job.Name)
+}
@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"orderstreamrest/internal/models/entities"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDue(t *testing.T) {
+	now := time.Date(2025, 10, 16, 12, 0, 0, 0, time.UTC)
+	ranAt := func(ago time.Duration) *time.Time {
+		at := now.Add(-ago)
+		return &at
+	}
+
+	tests := []struct {
+		name     string
+		settings entities.ScheduledJob
+		expected bool
+	}{
+		{
+			name:     "Never ran",
+			settings: entities.ScheduledJob{Enabled: true, IntervalMinutes: 60},
+			expected: true,
+		},
+		{
+			name:     "Interval elapsed",
+			settings: entities.ScheduledJob{Enabled: true, IntervalMinutes: 60, LastRunAt: ranAt(time.Hour)},
+			expected: true,
+		},
+		{
+			name:     "Interval not elapsed",
+			settings: entities.ScheduledJob{Enabled: true, IntervalMinutes: 60, LastRunAt: ranAt(59 * time.Minute)},
+			expected: false,
+		},
+		{
+			name:     "Disabled",
+			settings: entities.ScheduledJob{Enabled: false, IntervalMinutes: 60},
+			expected: false,
+		},
+		{
+			name:     "Invalid interval",
+			settings: entities.ScheduledJob{Enabled: true},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isDue(tt.settings, now))
+		})
+	}
+}
+
+func TestValidateSettings(t *testing.T) {
+	withPolicy := Job{Name: "retention", Policies: []string{"ANONYMIZE", "DELETE"}}
+	withoutPolicy := Job{Name: "refresh"}
+
+	tests := []struct {
+		name        string
+		job         Job
+		settings    entities.ScheduledJob
+		expectError bool
+	}{
+		{
+			name:     "Valid policy",
+			job:      withPolicy,
+			settings: entities.ScheduledJob{IntervalMinutes: 60, RetentionDays: 30, Policy: "DELETE"},
+		},
+		{
+			name:        "Unknown policy",
+			job:         withPolicy,
+			settings:    entities.ScheduledJob{IntervalMinutes: 60, Policy: "REASSIGN"},
+			expectError: true,
+		},
+		{
+			name:        "Policy on job without policies",
+			job:         withoutPolicy,
+			settings:    entities.ScheduledJob{IntervalMinutes: 60, Policy: "DELETE"},
+			expectError: true,
+		},
+		{
+			name:        "Zero interval",
+			job:         withoutPolicy,
+			settings:    entities.ScheduledJob{},
+			expectError: true,
+		},
+		{
+			name:        "Negative retention",
+			job:         withoutPolicy,
+			settings:    entities.ScheduledJob{IntervalMinutes: 60, RetentionDays: -1},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSettings(tt.job, tt.settings)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"orderstreamrest/internal/apperror"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/resilience"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// defaultWorkers é o número de workers por instância quando JOB_WORKERS não é informado
+	defaultWorkers = 2
+	// readBlock é quanto tempo um worker espera por uma nova tarefa antes de procurar tarefas abandonadas
+	readBlock = 5 * time.Second
+	// retryDelay é a espera após uma falha de comunicação com o Redis ou o banco
+	retryDelay = 10 * time.Second
+)
+
+// StartWorkers inicia os workers da fila de tarefas e bloqueia até o contexto ser cancelado.
+// JOB_WORKERS define quantos workers a instância executa (padrão 2); 0 desliga a fila nesta
+// instância, e as tarefas enviadas ficam aguardando os workers das outras.
+func StartWorkers(ctx context.Context, cfg *config.App) {
+	workers := defaultWorkers
+	if value, err := strconv.Atoi(os.Getenv("JOB_WORKERS")); err == nil && value >= 0 {
+		workers = value
+	}
+	if workers == 0 {
+		cfg.Logger.Info("Job queue workers disabled on this instance (JOB_WORKERS=0)")
+		return
+	}
+
+	rdb := queueClient(cfg)
+	for {
+		err := ensureGroup(ctx, rdb)
+		if err == nil {
+			break
+		}
+		cfg.Logger.Warn(fmt.Sprintf("Failed to create job queue consumer group: %v", err))
+		if !sleep(ctx, retryDelay) {
+			return
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(consumer string) {
+			defer wg.Done()
+			work(ctx, cfg, rdb, consumer)
+		}(fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), i))
+	}
+
+	cfg.Logger.Info(fmt.Sprintf("Job queue started with %d workers", workers))
+	wg.Wait()
+}
+
+// ensureGroup cria o stream e o consumer group da fila, caso ainda não existam
+func ensureGroup(ctx context.Context, rdb *redis.Client) error {
+	err := rdb.XGroupCreateMkStream(ctx, queueStream, queueGroup, "0").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// work processa as tarefas da fila, uma por vez, até o contexto ser cancelado
+func work(ctx context.Context, cfg *config.App, rdb *redis.Client, consumer string) {
+	for ctx.Err() == nil {
+		messages, err := next(ctx, rdb, consumer)
+		if err != nil {
+			if ctx.Err() == nil {
+				cfg.Logger.Warn(fmt.Sprintf("Failed to read job queue: %v", err))
+				sleep(ctx, retryDelay)
+			}
+			continue
+		}
+
+		for _, message := range messages {
+			process(ctx, cfg, rdb, message)
+		}
+	}
+}
+
+// next retorna a próxima tarefa: primeiro as entregues a um worker que parou sem confirmá-las
+// (instância reiniciada no meio da execução) e, sem elas, as novas tarefas do stream
+func next(ctx context.Context, rdb *redis.Client, consumer string) ([]redis.XMessage, error) {
+	claimed, _, err := rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   queueStream,
+		Group:    queueGroup,
+		Consumer: consumer,
+		MinIdle:  claimIdle(),
+		Start:    "0-0",
+		Count:    1,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(claimed) > 0 {
+		return claimed, nil
+	}
+
+	streams, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    queueGroup,
+		Consumer: consumer,
+		Streams:  []string{queueStream, ">"},
+		Count:    1,
+		Block:    readBlock,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []redis.XMessage
+	for _, stream := range streams {
+		messages = append(messages, stream.Messages...)
+	}
+	return messages, nil
+}
+
+// claimIdle é o tempo sem confirmação a partir do qual uma tarefa é considerada abandonada:
+// maior que o timeout da tarefa mais longa, para não tomar uma tarefa ainda em execução
+func claimIdle() time.Duration {
+	idle := defaultTaskTimeout
+	for _, task := range tasks {
+		if task.Timeout > idle {
+			idle = task.Timeout
+		}
+	}
+	return idle + time.Minute
+}
+
+// process executa uma tarefa da fila e grava o resultado. A mensagem só é confirmada depois
+// que a situação da tarefa foi gravada; se o banco falhar, ela volta a ser entregue mais tarde.
+func process(ctx context.Context, cfg *config.App, rdb *redis.Client, message redis.XMessage) {
+	id, _ := message.Values["id"].(string)
+
+	job, err := cfg.SqlServer.GetQueuedJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			cfg.Logger.Warn(fmt.Sprintf("Dropping queue message %s: job %q not found", message.ID, id))
+			ack(ctx, cfg, rdb, message.ID)
+		} else {
+			cfg.Logger.Warn(fmt.Sprintf("Failed to load queued job %s: %v", id, err))
+		}
+		return
+	}
+
+	// Entrega repetida de uma tarefa que já terminou
+	if job.Status == entities.QueuedJobSucceeded || job.Status == entities.QueuedJobFailed {
+		ack(ctx, cfg, rdb, message.ID)
+		return
+	}
+
+	startedAt := time.Now().UTC()
+	job.Status = entities.QueuedJobRunning
+	job.Attempts++
+	job.StartedAt = &startedAt
+	job.ErrorMessage = nil
+	if err := cfg.SqlServer.UpdateQueuedJob(ctx, job); err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to start queued job %s: %v", job.Id, err))
+		return
+	}
+
+	result, runErr := run(ctx, cfg, job)
+
+	// Instância encerrando: a tarefa fica pendente e é retomada por outro worker
+	if ctx.Err() != nil {
+		return
+	}
+
+	job.Status = nextStatus(job.Attempts, job.MaxAttempts, runErr)
+	job.Result = result
+	if runErr != nil {
+		job.ErrorMessage = errorMessage(runErr)
+	}
+	if job.Status != entities.QueuedJobQueued {
+		finishedAt := time.Now().UTC()
+		job.FinishedAt = &finishedAt
+	}
+
+	if err := cfg.SqlServer.UpdateQueuedJob(ctx, job); err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to record result of queued job %s: %v", job.Id, err))
+		return
+	}
+
+	switch job.Status {
+	case entities.QueuedJobQueued:
+		cfg.Logger.Warn(fmt.Sprintf("Queued job %s (%s) failed on attempt %d of %d, retrying: %v", job.Id, job.Type, job.Attempts, job.MaxAttempts, runErr))
+		if err := publish(ctx, rdb, job.Id); err != nil {
+			// Sem confirmar a mensagem, a tarefa é retomada como abandonada
+			cfg.Logger.Warn(fmt.Sprintf("Failed to requeue job %s: %v", job.Id, err))
+			return
+		}
+	case entities.QueuedJobFailed:
+		cfg.Logger.Warn(fmt.Sprintf("Queued job %s (%s) failed after %d attempts: %v", job.Id, job.Type, job.Attempts, runErr))
+	default:
+		cfg.Logger.Info(fmt.Sprintf("Queued job %s (%s) finished in %s", job.Id, job.Type, time.Since(startedAt)))
+	}
+
+	ack(ctx, cfg, rdb, message.ID)
+}
+
+// run executa a tarefa com o timeout do tipo, convertendo um panic em erro para não derrubar o worker
+func run(ctx context.Context, cfg *config.App, job *entities.QueuedJob) (result *string, err error) {
+	task, ok := LookupTask(job.Type)
+	if !ok {
+		return nil, ErrUnknownTask
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, task.Timeout)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result, err = nil, fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+
+	output, err := task.Run(ctx, cfg, json.RawMessage(job.Payload))
+	if err != nil || output == nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job result: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// nextStatus decide a situação da tarefa após uma tentativa. Erros do cliente (payload inválido,
+// registro inexistente...) não mudam com uma nova tentativa, então a tarefa falha na hora.
+func nextStatus(attempts, maxAttempts int, err error) string {
+	switch {
+	case err == nil:
+		return entities.QueuedJobSucceeded
+	case !resilience.IsFailure(err) || attempts >= maxAttempts:
+		return entities.QueuedJobFailed
+	default:
+		return entities.QueuedJobQueued
+	}
+}
+
+// ack confirma e remove a mensagem do stream
+func ack(ctx context.Context, cfg *config.App, rdb *redis.Client, messageID string) {
+	if err := rdb.XAck(ctx, queueStream, queueGroup, messageID).Err(); err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to acknowledge queue message %s: %v", messageID, err))
+		return
+	}
+	rdb.XDel(ctx, queueStream, messageID)
+}
+
+// sleep espera o intervalo e retorna false se o contexto for cancelado antes
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AgentsWorkload retorna a carga de trabalho e o MTTA de cada agente
+// @Summary      Carga de Trabalho dos Agentes
+// @Description  Retorna, para cada agente ativo, os tickets abertos atribuídos, o tempo médio até a primeira ação (MTTA, a partir do status_history) e a carga em relação à média da equipe (departamento do agente). loadRatio acima de 1 indica carga acima da média.
+// @Tags         metrics
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.AgentWorkload} "Agents workload retrieved successfully"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden - No permission"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Rate limit exceeded"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /metrics/agents/workload [get]
+func AgentsWorkload(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseMetricsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+
+		workload, err := LoadAgentsWorkload(c.Request.Context(), cfg, filter)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve agents workload")
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, workload, "Agents workload retrieved successfully"))
+	}
+}
+
+// LoadAgentsWorkload busca os tickets dos agentes no DW e o MTTA no Elasticsearch e monta a carga de cada agente
+func LoadAgentsWorkload(ctx context.Context, cfg *config.App, filter dto.MetricsFilter) ([]dto.AgentWorkload, error) {
+	agents, err := cfg.Metrics.GetAgentsWorkload(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	acknowledge, err := cfg.ES.GetAgentsAcknowledgeTime(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving agents acknowledge time: %w", err)
+	}
+
+	return buildAgentsWorkload(agents, acknowledge), nil
+}
+
+// buildAgentsWorkload combina os tickets do DW com o MTTA do Elasticsearch e calcula a carga relativa
+// à média de tickets abertos da equipe. O resultado vem ordenado da maior para a menor carga.
+func buildAgentsWorkload(agents []sqlserver.AgentTickets, acknowledge map[string]elsearch.AgentAcknowledge) []dto.AgentWorkload {
+	openByTeam := make(map[string]int64)
+	agentsByTeam := make(map[string]int64)
+	for _, agent := range agents {
+		openByTeam[agent.Department] += agent.OpenTickets
+		agentsByTeam[agent.Department]++
+	}
+
+	workload := make([]dto.AgentWorkload, 0, len(agents))
+	for _, agent := range agents {
+		agentID := strconv.FormatInt(agent.AgentId, 10)
+		teamAverage := float64(openByTeam[agent.Department]) / float64(agentsByTeam[agent.Department])
+
+		item := dto.AgentWorkload{
+			AgentId:         agentID,
+			Name:            agent.FullName,
+			Team:            agent.Department,
+			OpenTickets:     agent.OpenTickets,
+			AssignedTickets: agent.TotalTickets,
+			TeamAverageOpen: round2(teamAverage),
+		}
+		if teamAverage > 0 {
+			item.LoadRatio = round2(float64(agent.OpenTickets) / teamAverage)
+		}
+
+		if ack, ok := acknowledge[agentID]; ok && ack.Tickets > 0 {
+			hours := round2(ack.MeanSeconds() / 3600)
+			item.MTTAHours = &hours
+			item.AcknowledgedTickets = ack.Tickets
+		}
+
+		workload = append(workload, item)
+	}
+
+	sort.SliceStable(workload, func(i, j int) bool {
+		return workload[i].LoadRatio > workload[j].LoadRatio
+	})
+
+	return workload
+}
+
+// round2 arredonda para duas casas decimais
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAgentsWorkload(t *testing.T) {
+	agents := []sqlserver.AgentTickets{
+		{AgentId: 1, FullName: "Ana", Department: "Suporte", OpenTickets: 6, TotalTickets: 20},
+		{AgentId: 2, FullName: "Bruno", Department: "Suporte", OpenTickets: 2, TotalTickets: 10},
+		{AgentId: 3, FullName: "Carla", Department: "Financeiro", OpenTickets: 0, TotalTickets: 4},
+	}
+	acknowledge := map[string]elsearch.AgentAcknowledge{
+		"1": {AgentID: "1", Tickets: 2, TotalSeconds: 3 * 3600},
+	}
+
+	workload := buildAgentsWorkload(agents, acknowledge)
+	require.Len(t, workload, 3)
+
+	assert.Equal(t, "1", workload[0].AgentId)
+	assert.Equal(t, 4.0, workload[0].TeamAverageOpen)
+	assert.Equal(t, 1.5, workload[0].LoadRatio)
+	require.NotNil(t, workload[0].MTTAHours)
+	assert.Equal(t, 1.5, *workload[0].MTTAHours)
+	assert.Equal(t, int64(2), workload[0].AcknowledgedTickets)
+
+	assert.Equal(t, "2", workload[1].AgentId)
+	assert.Equal(t, 0.5, workload[1].LoadRatio)
+	assert.Nil(t, workload[1].MTTAHours)
+
+	// Equipe sem tickets abertos não tem média para comparar
+	assert.Equal(t, "3", workload[2].AgentId)
+	assert.Equal(t, 0.0, workload[2].LoadRatio)
+}
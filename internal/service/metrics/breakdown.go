@@ -0,0 +1,252 @@
+package metrics
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"orderstreamrest/internal/service/export"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// otherLabel agrupa os valores que ficaram fora do top-N
+	otherLabel = "Outros"
+
+	defaultBreakdownPageSize = 50
+	maxBreakdownPageSize     = 500
+	maxBreakdownTop          = 1000
+
+	// streamFlushEvery define a cada quantas linhas o stream NDJSON é enviado ao cliente
+	streamFlushEvery = 100
+)
+
+// TicketsBreakdown retorna o total de tickets por valor de uma dimensão, paginado ou em top-N
+// @Summary      Breakdown de Tickets por Dimensão
+// @Description  Retorna o total de tickets por valor da dimensão (category, channel, company, department, priority, product, tag). Com top, retorna os N maiores valores e agrupa o restante em "Outros"; sem top, retorna a página solicitada. Com format=csv ou xlsx, o resultado é enviado como arquivo.
+// @Tags         metrics
+// @Accept       json
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security 	 BearerAuth
+// @Param        dimension path string true "Dimensão" Enums(category, channel, company, department, priority, product, tag)
+// @Param        top query int false "Quantidade de maiores valores; o restante é agrupado em Outros"
+// @Param        page query int false "Página (padrão 1)"
+// @Param        pageSize query int false "Itens por página (padrão 50, máximo 500)"
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Param        format query string false "Formato da resposta (padrão json)" Enums(json, csv, xlsx)
+// @Success      200 {object} dto.PaginatedResponse{data=[]dto.MetricValue} "Tickets breakdown retrieved successfully"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Rate limit exceeded"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /metrics/tickets/breakdown/{dimension} [get]
+func TicketsBreakdown(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, ok := parseExportFormat(c)
+		if !ok {
+			return
+		}
+
+		dimension, filter, ok := parseBreakdownRequest(c)
+		if !ok {
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		if top := parseTop(c); top > 0 {
+			if top > maxBreakdownTop {
+				top = maxBreakdownTop
+			}
+
+			values, _, err := cfg.Metrics.GetTicketsBreakdown(ctx, dimension, filter, 0, top)
+			if err != nil {
+				middleware.RespondError(c, err, "Failed to retrieve tickets breakdown")
+				return
+			}
+
+			total, err := cfg.Metrics.GetTicketsBreakdownTotal(ctx, dimension, filter)
+			if err != nil {
+				middleware.RespondError(c, err, "Failed to retrieve tickets breakdown")
+				return
+			}
+
+			var topTotal int64
+			for _, value := range values {
+				topTotal += value.Value
+			}
+			if other := total - topTotal; other > 0 {
+				values = append(values, dto.MetricValue{Name: otherLabel, Value: other})
+			}
+
+			if format != export.FormatJSON {
+				export.Write(c, format, "tickets-breakdown-"+dimension, export.MetricValues(dimension, values))
+				return
+			}
+
+			c.JSON(http.StatusOK, dto.NewSuccessResponse(c, values, "Tickets breakdown retrieved successfully"))
+			return
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(defaultBreakdownPageSize)))
+		if err != nil || pageSize < 1 {
+			pageSize = defaultBreakdownPageSize
+		}
+		if pageSize > maxBreakdownPageSize {
+			pageSize = maxBreakdownPageSize
+		}
+
+		offset := (page - 1) * pageSize
+		values, count, err := cfg.Metrics.GetTicketsBreakdown(ctx, dimension, filter, offset, pageSize)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve tickets breakdown")
+			return
+		}
+
+		if format != export.FormatJSON {
+			export.Write(c, format, "tickets-breakdown-"+dimension, export.MetricValues(dimension, values))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, values, dto.Pagination{
+			CurrentPage:  page,
+			PerPage:      pageSize,
+			TotalPages:   int((count + int64(pageSize) - 1) / int64(pageSize)),
+			TotalRecords: count,
+			HasNext:      int64(offset+pageSize) < count,
+			HasPrev:      page > 1,
+		}, "Tickets breakdown retrieved successfully"))
+	}
+}
+
+// StreamTicketsBreakdown envia a distribuição completa de uma dimensão em NDJSON
+// @Summary      Stream do Breakdown de Tickets por Dimensão
+// @Description  Envia todos os valores da dimensão em NDJSON, um objeto {"name","value"} por linha, do maior para o menor total. Executa no pool de exportações.
+// @Tags         metrics
+// @Produce      application/x-ndjson
+// @Security 	 BearerAuth
+// @Param        dimension path string true "Dimensão" Enums(category, channel, company, department, priority, product, tag)
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Success      200 {object} dto.MetricValue "Uma linha por valor da dimensão"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
+// @Failure 	 503 {object} dto.RateLimitErrorResponse "Too many exports in progress"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /metrics/tickets/breakdown/{dimension}/stream [get]
+func StreamTicketsBreakdown(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dimension, filter, ok := parseBreakdownRequest(c)
+		if !ok {
+			return
+		}
+
+		var written int64
+		encoder := json.NewEncoder(c.Writer)
+
+		err := cfg.Metrics.StreamTicketsBreakdown(c.Request.Context(), dimension, filter, func(value dto.MetricValue) error {
+			if written == 0 {
+				c.Header("Content-Type", "application/x-ndjson")
+				c.Status(http.StatusOK)
+			}
+
+			if err := encoder.Encode(value); err != nil {
+				return err
+			}
+
+			written++
+			if written%streamFlushEvery == 0 {
+				c.Writer.Flush()
+				middleware.ReportProgress(c, written, 0)
+			}
+			return nil
+		})
+
+		if err != nil {
+			// Depois que o stream começou não é mais possível mudar o status da resposta
+			if written > 0 {
+				log.Printf("Tickets breakdown stream interrupted after %d rows: %v", written, err)
+				return
+			}
+			middleware.RespondError(c, err, "Failed to stream tickets breakdown")
+			return
+		}
+
+		if written == 0 {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+		}
+		c.Writer.Flush()
+		middleware.ReportProgress(c, written, written)
+	}
+}
+
+// parseBreakdownRequest valida a dimensão e o filtro de período, respondendo 400 quando inválidos
+func parseBreakdownRequest(c *gin.Context) (string, dto.MetricsFilter, bool) {
+	dimension := strings.ToLower(c.Param("dimension"))
+	if !sqlserver.IsBreakdownDimension(dimension) {
+		respondInvalidDimension(c, dimension)
+		return "", dto.MetricsFilter{}, false
+	}
+
+	filter, err := parseMetricsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+		return "", dto.MetricsFilter{}, false
+	}
+
+	return dimension, filter, true
+}
+
+// rollupTop mantém os n maiores valores e soma o restante em "Outros"
+func rollupTop(values []dto.MetricValue, n int) []dto.MetricValue {
+	if n <= 0 || len(values) <= n {
+		return values
+	}
+
+	sorted := make([]dto.MetricValue, len(values))
+	copy(sorted, values)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value > sorted[j].Value
+	})
+
+	var other int64
+	for _, value := range sorted[n:] {
+		other += value.Value
+	}
+
+	return append(sorted[:n:n], dto.MetricValue{Name: otherLabel, Value: other})
+}
+
+// parseTop lê o parâmetro top, ignorando valores inválidos
+func parseTop(c *gin.Context) int {
+	top, err := strconv.Atoi(c.Query("top"))
+	if err != nil || top < 0 {
+		return 0
+	}
+	return top
+}
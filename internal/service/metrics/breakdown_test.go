@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollupTop(t *testing.T) {
+	values := []dto.MetricValue{
+		{Name: "Hardware", Value: 10},
+		{Name: "Rede", Value: 40},
+		{Name: "Software", Value: 30},
+		{Name: "Acesso", Value: 5},
+	}
+
+	tests := []struct {
+		name     string
+		n        int
+		expected []dto.MetricValue
+	}{
+		{
+			name:     "No top keeps every value",
+			n:        0,
+			expected: values,
+		},
+		{
+			name:     "Top larger than the list keeps every value",
+			n:        10,
+			expected: values,
+		},
+		{
+			name: "Top two rolls up the rest into Outros",
+			n:    2,
+			expected: []dto.MetricValue{
+				{Name: "Rede", Value: 40},
+				{Name: "Software", Value: 30},
+				{Name: otherLabel, Value: 15},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rollupTop(values, tt.n))
+		})
+	}
+
+	// A lista original não pode ser reordenada
+	assert.Equal(t, "Hardware", values[0].Name)
+}
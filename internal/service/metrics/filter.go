@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const filterDateLayout = "2006-01-02"
+
+// timezoneHeader informa o fuso das métricas quando a query não traz timezone
+const timezoneHeader = "Accept-Timezone"
+
+// parseMetricsFilter lê os parâmetros startDate, endDate (YYYY-MM-DD), year, company, department, channel,
+// priority e timezone da query. Sem timezone na query vale o header Accept-Timezone.
+func parseMetricsFilter(c *gin.Context) (dto.MetricsFilter, error) {
+	return ParseMetricsFilter(c.Request.Context(), func(name string) string {
+		if value := c.Query(name); value != "" || name != "timezone" {
+			return value
+		}
+		return c.GetHeader(timezoneHeader)
+	})
+}
+
+// ParseMetricsFilter monta o filtro das métricas a partir dos parâmetros devolvidos por param (a query
+// do HTTP ou os campos de uma requisição gRPC) e aplica o escopo de empresas do contexto
+func ParseMetricsFilter(ctx context.Context, param func(name string) string) (dto.MetricsFilter, error) {
+	var filter dto.MetricsFilter
+
+	if value := param("startDate"); value != "" {
+		startDate, err := time.Parse(filterDateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid startDate %q, expected format YYYY-MM-DD", value)
+		}
+		filter.StartDate = &startDate
+	}
+
+	if value := param("endDate"); value != "" {
+		endDate, err := time.Parse(filterDateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid endDate %q, expected format YYYY-MM-DD", value)
+		}
+		filter.EndDate = &endDate
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return filter, errors.New("endDate must not be before startDate")
+	}
+
+	if value := param("year"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil || year < 1900 || year > 9999 {
+			return filter, fmt.Errorf("invalid year %q", value)
+		}
+		filter.Year = &year
+	}
+
+	if value := strings.TrimSpace(param("timezone")); value != "" {
+		timezone, err := parseTimezone(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Timezone = timezone
+	}
+
+	filter.Company = optionalParam(param, "company")
+	filter.Department = optionalParam(param, "department")
+	filter.Channel = optionalParam(param, "channel")
+	filter.Priority = optionalParam(param, "priority")
+
+	applyCompanyScope(ctx, &filter)
+
+	return filter, nil
+}
+
+// applyCompanyScope restringe o filtro às empresas do escopo definido pelo Auth, o mesmo aplicado
+// às buscas no Elasticsearch. Sem escopo no contexto nada é retornado.
+func applyCompanyScope(ctx context.Context, filter *dto.MetricsFilter) {
+	scope, ok := elsearch.ScopeFromContext(ctx)
+	if ok && scope.Unrestricted {
+		return
+	}
+
+	filter.CompanyScoped = true
+	filter.Companies = scope.CompanyIDs
+}
+
+// optionalParam retorna o parâmetro sem espaços, ou nil quando ausente
+func optionalParam(param func(name string) string, name string) *string {
+	value := strings.TrimSpace(param(name))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// parseTimezone valida o fuso IANA (ex.: America/Sao_Paulo). UTC vira vazio, o padrão do filtro,
+// para que as duas formas usem a mesma entrada do cache.
+func parseTimezone(value string) (string, error) {
+	loc, err := time.LoadLocation(value)
+	if err != nil || strings.EqualFold(value, "local") {
+		return "", fmt.Errorf("invalid timezone %q, expected an IANA name such as America/Sao_Paulo", value)
+	}
+	if loc == time.UTC {
+		return "", nil
+	}
+	return loc.String(), nil
+}
@@ -0,0 +1,232 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/repositories/elsearch"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetricsFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectError   bool
+		expectedStart string
+		expectedEnd   string
+		expectedYear  int
+
+		expectedCompany    string
+		expectedDepartment string
+		expectedChannel    string
+		expectedPriority   string
+	}{
+		{
+			name:  "Success - No filter",
+			query: "",
+		},
+		{
+			name:          "Success - Custom period",
+			query:         "startDate=2025-01-01&endDate=2025-01-31",
+			expectedStart: "2025-01-01",
+			expectedEnd:   "2025-01-31",
+		},
+		{
+			name:         "Success - Year only",
+			query:        "year=2024",
+			expectedYear: 2024,
+		},
+		{
+			name:               "Success - Dimension filters",
+			query:              "company=Acme&department=Suporte&channel=%20Email%20&priority=ALTA",
+			expectedCompany:    "Acme",
+			expectedDepartment: "Suporte",
+			expectedChannel:    "Email",
+			expectedPriority:   "ALTA",
+		},
+		{
+			name:        "Error - Invalid date format",
+			query:       "startDate=01/01/2025",
+			expectError: true,
+		},
+		{
+			name:        "Error - End date before start date",
+			query:       "startDate=2025-02-01&endDate=2025-01-01",
+			expectError: true,
+		},
+		{
+			name:        "Error - Invalid year",
+			query:       "year=abc",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/metrics/tickets?"+tt.query, nil)
+
+			filter, err := parseMetricsFilter(c)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.expectedStart != "" {
+				require.NotNil(t, filter.StartDate)
+				assert.Equal(t, tt.expectedStart, filter.StartDate.Format(time.DateOnly))
+			} else {
+				assert.Nil(t, filter.StartDate)
+			}
+
+			if tt.expectedEnd != "" {
+				require.NotNil(t, filter.EndDate)
+				assert.Equal(t, tt.expectedEnd, filter.EndDate.Format(time.DateOnly))
+			} else {
+				assert.Nil(t, filter.EndDate)
+			}
+
+			if tt.expectedYear != 0 {
+				require.NotNil(t, filter.Year)
+				assert.Equal(t, tt.expectedYear, *filter.Year)
+			} else {
+				assert.Nil(t, filter.Year)
+			}
+
+			assertOptional(t, tt.expectedCompany, filter.Company)
+			assertOptional(t, tt.expectedDepartment, filter.Department)
+			assertOptional(t, tt.expectedChannel, filter.Channel)
+			assertOptional(t, tt.expectedPriority, filter.Priority)
+		})
+	}
+}
+
+// assertOptional verifica um filtro opcional, que deve ser nil quando não esperado
+func assertOptional(t *testing.T, expected string, value *string) {
+	t.Helper()
+
+	if expected == "" {
+		assert.Nil(t, value)
+		return
+	}
+	require.NotNil(t, value)
+	assert.Equal(t, expected, *value)
+}
+
+func TestParseMetricsFilterCompanyScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name              string
+		scope             *elsearch.Scope
+		expectedScoped    bool
+		expectedCompanies []string
+	}{
+		{
+			name:  "Unrestricted scope does not filter companies",
+			scope: &elsearch.Scope{Unrestricted: true},
+		},
+		{
+			name:              "Company scope filters its companies",
+			scope:             &elsearch.Scope{CompanyIDs: []string{"12"}},
+			expectedScoped:    true,
+			expectedCompanies: []string{"12"},
+		},
+		{
+			name:           "Missing scope returns nothing",
+			expectedScoped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			req := httptest.NewRequest(http.MethodGet, "/metrics/tickets", nil)
+			if tt.scope != nil {
+				req = req.WithContext(elsearch.WithScope(req.Context(), *tt.scope))
+			}
+			c.Request = req
+
+			filter, err := parseMetricsFilter(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedScoped, filter.CompanyScoped)
+			assert.Equal(t, tt.expectedCompanies, filter.Companies)
+		})
+	}
+}
+
+func TestParseMetricsFilterTimezone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name             string
+		query            string
+		header           string
+		expectError      bool
+		expectedTimezone string
+	}{
+		{
+			name:  "Success - Defaults to UTC",
+			query: "",
+		},
+		{
+			name:             "Success - Query parameter",
+			query:            "timezone=America/Sao_Paulo",
+			expectedTimezone: "America/Sao_Paulo",
+		},
+		{
+			name:             "Success - Accept-Timezone header",
+			header:           "Europe/Lisbon",
+			expectedTimezone: "Europe/Lisbon",
+		},
+		{
+			name:             "Success - Query parameter wins over header",
+			query:            "timezone=America/Sao_Paulo",
+			header:           "Europe/Lisbon",
+			expectedTimezone: "America/Sao_Paulo",
+		},
+		{
+			name:  "Success - UTC is the default",
+			query: "timezone=UTC",
+		},
+		{
+			name:        "Error - Unknown timezone",
+			query:       "timezone=Mars/Olympus",
+			expectError: true,
+		},
+		{
+			name:        "Error - Server local timezone",
+			header:      "Local",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/metrics/tickets?"+tt.query, nil)
+			if tt.header != "" {
+				c.Request.Header.Set(timezoneHeader, tt.header)
+			}
+
+			filter, err := parseMetricsFilter(c)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTimezone, filter.Timezone)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"net/http"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/export"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseExportFormat lê o parâmetro format, respondendo 400 quando inválido
+func parseExportFormat(c *gin.Context) (export.Format, bool) {
+	format, err := export.ParseFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid export format", err.Error()))
+		return "", false
+	}
+	return format, true
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxHistogramBuckets limita o tamanho da série para manter a agregação e o gráfico leves
+	maxHistogramBuckets = 500
+
+	defaultHistogramDays   = 30
+	defaultHistogramWeeks  = 26
+	defaultHistogramMonths = 12
+)
+
+// histogramIntervals são os intervalos aceitos em interval
+var histogramIntervals = []string{elsearch.HistogramDay, elsearch.HistogramWeek, elsearch.HistogramMonth}
+
+// TicketsHistogram retorna a quantidade de tickets abertos por dia, semana ou mês
+// @Summary      Histograma de Tickets
+// @Description  Retorna a série de tickets abertos por dia, semana (segunda a domingo) ou mês, calculada no Elasticsearch, com um ponto por período, inclusive os sem tickets. Sem datas, a janela termina hoje e cobre 30 dias, 26 semanas ou 12 meses; com year, cobre o ano.
+// @Description  O primeiro e o último período podem começar antes de startDate ou terminar depois de endDate, mas contam apenas os tickets dentro da janela. A série tem no máximo 500 períodos.
+// @Tags         metrics
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        interval query string false "Intervalo dos períodos" Enums(day, week, month) default(day)
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e períodos (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsHistogramResponse} "Tickets histogram retrieved successfully"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Rate limit exceeded"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /metrics/tickets/histogram [get]
+func TicketsHistogram(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		interval := strings.ToLower(c.DefaultQuery("interval", elsearch.HistogramDay))
+		if !isHistogramInterval(interval) {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid interval", map[string]interface{}{
+				"interval": interval,
+				"allowed":  histogramIntervals,
+			}))
+			return
+		}
+
+		filter, err := parseMetricsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+
+		loc := sqlserver.FilterLocation(filter)
+		start, end, err := histogramWindow(filter, interval, truncateDay(time.Now().In(loc)))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid date filter", err.Error()))
+			return
+		}
+		filter.StartDate, filter.EndDate, filter.Year = &start, &end, nil
+
+		buckets, err := cfg.ES.GetTicketHistogram(c.Request.Context(), interval, filter)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve tickets histogram")
+			return
+		}
+
+		response := dto.TicketsHistogramResponse{
+			Interval:  interval,
+			StartDate: start.Format(filterDateLayout),
+			EndDate:   end.Format(filterDateLayout),
+			Timezone:  loc.String(),
+			Buckets:   make([]dto.HistogramBucket, 0, len(buckets)),
+		}
+		for _, bucket := range buckets {
+			response.Total += bucket.Count
+			response.Buckets = append(response.Buckets, dto.HistogramBucket{
+				Start: bucket.Start.Format(filterDateLayout),
+				Count: bucket.Count,
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Tickets histogram retrieved successfully"))
+	}
+}
+
+// isHistogramInterval informa se o intervalo é aceito pelo histograma
+func isHistogramInterval(interval string) bool {
+	for _, allowed := range histogramIntervals {
+		if interval == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// histogramWindow resolve as datas do histograma. O ano limita startDate e endDate; sem fim, a janela
+// termina hoje (ou no fim do ano) e, sem início, volta a quantidade padrão de períodos a partir do fim.
+func histogramWindow(filter dto.MetricsFilter, interval string, today time.Time) (time.Time, time.Time, error) {
+	var start, end *time.Time
+	if filter.StartDate != nil {
+		day := truncateDay(*filter.StartDate)
+		start = &day
+	}
+	if filter.EndDate != nil {
+		day := truncateDay(*filter.EndDate)
+		end = &day
+	}
+
+	if filter.Year != nil {
+		yearStart := time.Date(*filter.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		yearEnd := time.Date(*filter.Year, time.December, 31, 0, 0, 0, 0, time.UTC)
+		if start == nil || start.Before(yearStart) {
+			start = &yearStart
+		}
+		if end == nil || end.After(yearEnd) {
+			end = &yearEnd
+		}
+	}
+
+	if end == nil {
+		end = &today
+	}
+	if start == nil {
+		day := defaultHistogramStart(*end, interval)
+		start = &day
+	}
+
+	if end.Before(*start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the date range %s to %s is empty", start.Format(filterDateLayout), end.Format(filterDateLayout))
+	}
+	if count := histogramBucketCount(*start, *end, interval); count > maxHistogramBuckets {
+		return time.Time{}, time.Time{}, fmt.Errorf("the date range covers %d %ss, the maximum is %d", count, interval, maxHistogramBuckets)
+	}
+	return *start, *end, nil
+}
+
+// defaultHistogramStart retorna o início da janela padrão que termina em end: os últimos 30 dias ou o
+// início das últimas 26 semanas ou 12 meses, contando o período de end
+func defaultHistogramStart(end time.Time, interval string) time.Time {
+	switch interval {
+	case elsearch.HistogramWeek:
+		return addPeriods(periodStart(end, sqlserver.TrendWeek), sqlserver.TrendWeek, 1-defaultHistogramWeeks)
+	case elsearch.HistogramMonth:
+		return addPeriods(periodStart(end, sqlserver.TrendMonth), sqlserver.TrendMonth, 1-defaultHistogramMonths)
+	default:
+		return end.AddDate(0, 0, 1-defaultHistogramDays)
+	}
+}
+
+// histogramBucketCount retorna quantos períodos do intervalo a janela toca, inclusive os incompletos
+func histogramBucketCount(start, end time.Time, interval string) int {
+	switch interval {
+	case elsearch.HistogramWeek:
+		return int(periodStart(end, sqlserver.TrendWeek).Sub(periodStart(start, sqlserver.TrendWeek)).Hours()/24)/7 + 1
+	case elsearch.HistogramMonth:
+		return (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month()) + 1
+	default:
+		return int(end.Sub(start).Hours()/24) + 1
+	}
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramWindow(t *testing.T) {
+	today := day("2025-10-16")
+	year := 2024
+	start, end, longAgo := day("2024-11-10"), day("2025-02-20"), day("2024-01-01")
+
+	tests := []struct {
+		name          string
+		filter        dto.MetricsFilter
+		interval      string
+		expectedStart string
+		expectedEnd   string
+		expectedError string
+	}{
+		{name: "Default days", interval: elsearch.HistogramDay, expectedStart: "2025-09-17", expectedEnd: "2025-10-16"},
+		{name: "Default weeks start on Monday", interval: elsearch.HistogramWeek, expectedStart: "2025-04-21", expectedEnd: "2025-10-16"},
+		{name: "Default months", interval: elsearch.HistogramMonth, expectedStart: "2024-11-01", expectedEnd: "2025-10-16"},
+		{name: "Default start counts back from endDate", filter: dto.MetricsFilter{EndDate: &end}, interval: elsearch.HistogramDay, expectedStart: "2025-01-22", expectedEnd: "2025-02-20"},
+		{name: "Year covers the whole year", filter: dto.MetricsFilter{Year: &year}, interval: elsearch.HistogramMonth, expectedStart: "2024-01-01", expectedEnd: "2024-12-31"},
+		{name: "Year limits the dates", filter: dto.MetricsFilter{StartDate: &start, EndDate: &end, Year: &year}, interval: elsearch.HistogramDay, expectedStart: "2024-11-10", expectedEnd: "2024-12-31"},
+		{name: "Dates outside the year", filter: dto.MetricsFilter{StartDate: &end, Year: &year}, interval: elsearch.HistogramDay, expectedError: "the date range 2025-02-20 to 2024-12-31 is empty"},
+		{name: "Too many days", filter: dto.MetricsFilter{StartDate: &longAgo}, interval: elsearch.HistogramDay, expectedError: "the date range covers 655 days, the maximum is 500"},
+		{name: "Same range in weeks", filter: dto.MetricsFilter{StartDate: &longAgo}, interval: elsearch.HistogramWeek, expectedStart: "2024-01-01", expectedEnd: "2025-10-16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd, err := histogramWindow(tt.filter, tt.interval, today)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStart, gotStart.Format(filterDateLayout))
+			assert.Equal(t, tt.expectedEnd, gotEnd.Format(filterDateLayout))
+		})
+	}
+}
+
+func TestHistogramBucketCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		interval string
+		expected int
+	}{
+		{name: "Single day", start: "2025-10-16", end: "2025-10-16", interval: elsearch.HistogramDay, expected: 1},
+		{name: "Days across a month", start: "2025-09-28", end: "2025-10-03", interval: elsearch.HistogramDay, expected: 6},
+		{name: "Partial weeks on both ends", start: "2025-10-08", end: "2025-10-21", interval: elsearch.HistogramWeek, expected: 3},
+		{name: "Months across a year", start: "2024-11-30", end: "2025-02-01", interval: elsearch.HistogramMonth, expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, histogramBucketCount(day(tt.start), day(tt.end), tt.interval))
+		})
+	}
+}
+
+func TestTicketsHistogram_InvalidParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// Parâmetros inválidos são recusados antes de consultar o Elasticsearch
+	router.GET("/metrics/tickets/histogram", TicketsHistogram(nil))
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "Unknown interval", url: "/metrics/tickets/histogram?interval=hour"},
+		{name: "Invalid date", url: "/metrics/tickets/histogram?startDate=16/10/2025"},
+		{name: "Too many buckets", url: "/metrics/tickets/histogram?startDate=2020-01-01&endDate=2025-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"orderstreamrest/internal/service/export"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResolutionTimePercentiles retorna a distribuição do tempo de resolução por prioridade, categoria ou outra dimensão
+// @Summary      Percentis do Tempo de Resolução
+// @Description  Retorna, para cada valor da dimensão, a quantidade de tickets fechados e o tempo de resolução médio, mediano (P50) e nos percentis 90 e 99, em horas. A média sozinha esconde os tickets que demoram muito mais que os demais.
+// @Tags         metrics
+// @Accept       json
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security 	 BearerAuth
+// @Param        groupBy query string false "Dimensão de agrupamento" Enums(category, channel, company, department, priority, product, tag) default(priority)
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Param        format query string false "Formato da resposta (padrão json)" Enums(json, csv, xlsx)
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.PercentileMetrics} "Resolution time percentiles retrieved successfully"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Rate limit exceeded"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /metrics/tickets/resolution-time-percentiles [get]
+func ResolutionTimePercentiles(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, ok := parseExportFormat(c)
+		if !ok {
+			return
+		}
+
+		dimension := strings.ToLower(strings.TrimSpace(c.DefaultQuery("groupBy", "priority")))
+		if !sqlserver.IsBreakdownDimension(dimension) {
+			respondInvalidDimension(c, dimension)
+			return
+		}
+
+		filter, err := parseMetricsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+
+		rows, err := cfg.Metrics.GetResolutionTimePercentiles(c.Request.Context(), dimension, filter)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve resolution time percentiles")
+			return
+		}
+
+		metrics := make([]dto.PercentileMetrics, 0, len(rows))
+		for _, row := range rows {
+			metrics = append(metrics, dto.PercentileMetrics{
+				Name:      row.Name,
+				Tickets:   row.Tickets,
+				MeanHours: row.MeanHours,
+				P50Hours:  row.P50Hours,
+				P90Hours:  row.P90Hours,
+				P99Hours:  row.P99Hours,
+			})
+		}
+
+		if format != export.FormatJSON {
+			export.Write(c, format, "resolution-time-percentiles-"+dimension, export.ResolutionPercentiles(dimension, metrics))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, metrics, "Resolution time percentiles retrieved successfully"))
+	}
+}
@@ -1,13 +1,15 @@
 package metrics
 
 import (
+	"context"
 	"net/http"
 	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
 	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/export"
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,9 +19,19 @@ import (
 // @Description  Retorna métricas agregadas dos tickets por categoria, prioridade, canal e tag
 // @Tags         metrics
 // @Accept       json
-// @Produce      json
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Security 	 BearerAuth
-// @Success      200 {object} dto.TicketsMetricsResponse "Tickets metrics retrieved successfully"
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Param        format query string false "Formato da resposta (padrão json)" Enums(json, csv, xlsx)
+// @Param        top query int false "Mantém os N maiores valores de categoria, canal, tag e departamento e agrupa o restante em Outros"
+// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsMetricsResponse} "Tickets metrics retrieved successfully"
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
 // @Failure 	 403 {object} dto.ErrorResponse "Forbidden - No permission"
@@ -31,140 +43,158 @@ import (
 // @Router       /metrics/tickets [get]
 func GetTicketsMetrics(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		format, ok := parseExportFormat(c)
+		if !ok {
+			return
+		}
 
-		// total de tickets
-		total, err := cfg.SqlServer.GetTotalTickets()
+		filter, err := parseMetricsFilter(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: c.GetTime("request_start_time"),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to retrieve total tickets",
-				Details: err.Error(),
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
 			return
 		}
 
-		var metrics []dto.TypeMetric
+		response, err := BuildTicketsMetrics(c.Request.Context(), cfg, filter, parseTop(c))
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve total tickets")
+			return
+		}
 
-		// total de tickets por categoria
-		ticketsByCategory, err := cfg.SqlServer.GetTicketsByCategory()
-		if err == nil {
-			var categoryMetrics []dto.MetricValue
-			for _, item := range ticketsByCategory {
-				categoryMetrics = append(categoryMetrics, dto.MetricValue{
-					Name:  item.CategoryName,
-					Value: item.Total,
-				})
-			}
-			metrics = append(metrics, dto.TypeMetric{
-				Name:   "TicketsByCategory",
-				Values: categoryMetrics,
-			})
+		if format != export.FormatJSON {
+			export.Write(c, format, "tickets-metrics", export.TicketsMetrics(response))
+			return
 		}
 
-		// total de tickets por prioridade
-		ticketsByPriority, err := cfg.SqlServer.GetTicketsByPriority()
-		if err == nil {
-			// Ordena as prioridades: CRÍTICA, ALTA, MÉDIA, BAIXA
-			priorityOrder := map[string]int{
-				"CRÍTICA": 1,
-				"ALTA":    2,
-				"MÉDIA":   3,
-				"BAIXA":   4,
-			}
-			sort.Slice(ticketsByPriority, func(i, j int) bool {
-				return priorityOrder[strings.ToUpper(ticketsByPriority[i].Name)] < priorityOrder[strings.ToUpper(ticketsByPriority[j].Name)]
-			})
-			var priorityMetrics []dto.MetricValue
-			for _, item := range ticketsByPriority {
-				priorityMetrics = append(priorityMetrics, dto.MetricValue{
-					Name:  item.Name,
-					Value: item.Total,
-				})
-			}
-			metrics = append(metrics, dto.TypeMetric{
-				Name:   "TicketsByPriority",
-				Values: priorityMetrics,
+		// montando o json de response
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Tickets metrics retrieved successfully"))
+
+	}
+}
+
+// BuildTicketsMetrics calcula o total de tickets e os totais por categoria, prioridade, canal, tag e
+// departamento. top mantém os N maiores valores de cada agrupamento (0 mantém todos). Compartilhado
+// pelo endpoint HTTP e pelo serviço gRPC.
+func BuildTicketsMetrics(ctx context.Context, cfg *config.App, filter dto.MetricsFilter, top int) (dto.TicketsMetricsResponse, error) {
+	// total de tickets
+	total, err := cfg.Metrics.GetTotalTickets(ctx, filter)
+	if err != nil {
+		return dto.TicketsMetricsResponse{}, err
+	}
+
+	var metrics []dto.TypeMetric
+
+	// total de tickets por categoria
+	ticketsByCategory, err := cfg.Metrics.GetTicketsByCategory(ctx, filter)
+	if err == nil {
+		var categoryMetrics []dto.MetricValue
+		for _, item := range ticketsByCategory {
+			categoryMetrics = append(categoryMetrics, dto.MetricValue{
+				Name:  item.CategoryName,
+				Value: item.Total,
 			})
 		}
+		metrics = append(metrics, dto.TypeMetric{
+			Name:   "TicketsByCategory",
+			Values: rollupTop(categoryMetrics, top),
+		})
+	}
 
-		// total de tickets por canal
-		ticketsByChannel, err := cfg.SqlServer.GetTicketsByChannel()
-		if err == nil {
-			var channelMetrics []dto.MetricValue
-			for _, item := range ticketsByChannel {
-				channelMetrics = append(channelMetrics, dto.MetricValue{
-					Name:  item.ChannelName,
-					Value: item.Total,
-				})
-			}
-			metrics = append(metrics, dto.TypeMetric{
-				Name:   "TicketsByChannel",
-				Values: channelMetrics,
+	// total de tickets por prioridade
+	ticketsByPriority, err := cfg.Metrics.GetTicketsByPriority(ctx, filter)
+	if err == nil {
+		// Ordena as prioridades: CRÍTICA, ALTA, MÉDIA, BAIXA
+		priorityOrder := map[string]int{
+			"CRÍTICA": 1,
+			"ALTA":    2,
+			"MÉDIA":   3,
+			"BAIXA":   4,
+		}
+		sort.Slice(ticketsByPriority, func(i, j int) bool {
+			return priorityOrder[strings.ToUpper(ticketsByPriority[i].Name)] < priorityOrder[strings.ToUpper(ticketsByPriority[j].Name)]
+		})
+		var priorityMetrics []dto.MetricValue
+		for _, item := range ticketsByPriority {
+			priorityMetrics = append(priorityMetrics, dto.MetricValue{
+				Name:  item.Name,
+				Value: item.Total,
 			})
 		}
+		metrics = append(metrics, dto.TypeMetric{
+			Name:   "TicketsByPriority",
+			Values: priorityMetrics,
+		})
+	}
 
-		// total de tickets por Tag
-		ticketsByTag, err := cfg.SqlServer.GetTicketsByTag()
-		if err == nil {
-			var tagMetrics []dto.MetricValue
-			for _, item := range ticketsByTag {
-				tagMetrics = append(tagMetrics, dto.MetricValue{
-					Name:  item.Name,
-					Value: item.Total,
-				})
-			}
-			metrics = append(metrics, dto.TypeMetric{
-				Name:   "TicketsByTag",
-				Values: tagMetrics,
+	// total de tickets por canal
+	ticketsByChannel, err := cfg.Metrics.GetTicketsByChannel(ctx, filter)
+	if err == nil {
+		var channelMetrics []dto.MetricValue
+		for _, item := range ticketsByChannel {
+			channelMetrics = append(channelMetrics, dto.MetricValue{
+				Name:  item.ChannelName,
+				Value: item.Total,
 			})
 		}
+		metrics = append(metrics, dto.TypeMetric{
+			Name:   "TicketsByChannel",
+			Values: rollupTop(channelMetrics, top),
+		})
+	}
 
-		// total de tickets por departamento
-		ticketsByDepartment, err := cfg.SqlServer.GetTicketsByDepartment()
-		if err == nil {
-			var departmentMetrics []dto.MetricValue
-			for _, item := range ticketsByDepartment {
-				departmentMetrics = append(departmentMetrics, dto.MetricValue{
-					Name:  item.Name,
-					Value: item.Total,
-				})
-			}
-			metrics = append(metrics, dto.TypeMetric{
-				Name:   "TicketsByDepartment",
-				Values: departmentMetrics,
+	// total de tickets por Tag
+	ticketsByTag, err := cfg.Metrics.GetTicketsByTag(ctx, filter)
+	if err == nil {
+		var tagMetrics []dto.MetricValue
+		for _, item := range ticketsByTag {
+			tagMetrics = append(tagMetrics, dto.MetricValue{
+				Name:  item.Name,
+				Value: item.Total,
 			})
 		}
+		metrics = append(metrics, dto.TypeMetric{
+			Name:   "TicketsByTag",
+			Values: rollupTop(tagMetrics, top),
+		})
+	}
 
-		response := dto.TicketsMetricsResponse{
-			TotalTickets: total,
-			Metrics:      metrics,
+	// total de tickets por departamento
+	ticketsByDepartment, err := cfg.Metrics.GetTicketsByDepartment(ctx, filter)
+	if err == nil {
+		var departmentMetrics []dto.MetricValue
+		for _, item := range ticketsByDepartment {
+			departmentMetrics = append(departmentMetrics, dto.MetricValue{
+				Name:  item.Name,
+				Value: item.Total,
+			})
 		}
-
-		// montando o json de response
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data:    response,
-			Message: "Tickets metrics retrieved successfully",
+		metrics = append(metrics, dto.TypeMetric{
+			Name:   "TicketsByDepartment",
+			Values: rollupTop(departmentMetrics, top),
 		})
-
 	}
+
+	return dto.TicketsMetricsResponse{
+		TotalTickets: total,
+		Metrics:      metrics,
+	}, nil
 }
 
 // MeanTimeByPriority Tempo médio por prioridade
 // @Summary      Tempo Médio de Resolução por Prioridade
-// @Description  Retorna o tempo médio de resolução dos tickets, agrupado por prioridade, em horas e dias.
+// @Description  Retorna o tempo médio de resolução dos tickets, agrupado por prioridade, em horas e dias, com a mediana e os percentis 90 e 99 em horas.
 // @Tags         metrics
 // @Accept       json
-// @Produce      json
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Security 	 BearerAuth
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Param        format query string false "Formato da resposta (padrão json)" Enums(json, csv, xlsx)
 // @Success      200 {object} dto.SuccessResponse{data=[]dto.MeanTimeByPriority} "Mean time by priority retrieved successfully"
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
@@ -177,39 +207,41 @@ func GetTicketsMetrics(cfg *config.App) gin.HandlerFunc {
 // @Router       /metrics/tickets/mean-time-resolution-by-priority [get]
 func MeanTimeByPriority(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		format, ok := parseExportFormat(c)
+		if !ok {
+			return
+		}
 
-		meanTimeByPriority, err := cfg.SqlServer.GetAverageResolutionTime()
+		filter, err := parseMetricsFilter(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: c.GetTime("request_start_time"),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to retrieve mean time by priority",
-				Details: err.Error(),
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+
+		meanTimeByPriority, err := cfg.Metrics.GetAverageResolutionTime(c.Request.Context(), filter)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve mean time by priority")
 			return
 		}
 
 		var metrics []dto.MeanTimeByPriority
 		for _, item := range meanTimeByPriority {
 			metrics = append(metrics, dto.MeanTimeByPriority{
-				PriorityName: item.NomePrioridade,
-				MeanTimeHour: item.MediaResolucaoHoras,
-				MeanTimeDay:  item.MediaResolucaoDias,
+				PriorityName:   item.NomePrioridade,
+				MeanTimeHour:   item.MediaResolucaoHoras,
+				MeanTimeDay:    item.MediaResolucaoDias,
+				MedianTimeHour: item.MedianaResolucaoHoras,
+				P90TimeHour:    item.P90ResolucaoHoras,
+				P99TimeHour:    item.P99ResolucaoHoras,
 			})
 		}
 
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data:    metrics,
-			Message: "Mean time by priority retrieved successfully",
-		})
+		if format != export.FormatJSON {
+			export.Write(c, format, "mean-time-resolution-by-priority", export.MeanTimeByPriority(metrics))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, metrics, "Mean time by priority retrieved successfully"))
 	}
 }
 
@@ -218,9 +250,18 @@ func MeanTimeByPriority(cfg *config.App) gin.HandlerFunc {
 // @Description  Retorna a contagem de tickets agrupados por status, ano e mês.
 // @Tags         metrics
 // @Accept       json
-// @Produce      json
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Security 	 BearerAuth
-// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsByStatusYearMonth} "Tickets by status and month retrieved successfully"
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Param        format query string false "Formato da resposta (padrão json); timeseries retorna pontos {year, month, count}" Enums(json, csv, xlsx, timeseries)
+// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsByStatusYearMonth} "Tickets by status and month retrieved successfully; com format=timeseries, data é dto.GroupedTimeseries (status para a lista de dto.MonthlyPoint)"
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
 // @Failure 	 403 {object} dto.ErrorResponse "Forbidden - No permission"
@@ -232,18 +273,20 @@ func MeanTimeByPriority(cfg *config.App) gin.HandlerFunc {
 // @Router       /metrics/tickets/qtd-tickets-by-status-year-month [get]
 func QtdTicketsByStatusYearMonth(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		data, err := cfg.SqlServer.GetTicketsByStatusAndMonth()
+		format, timeseries, ok := parseMonthlyFormat(c)
+		if !ok {
+			return
+		}
+
+		filter, err := parseMetricsFilter(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: c.GetTime("request_start_time"),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to retrieve tickets by status and month",
-				Details: err.Error(),
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+
+		data, err := cfg.Metrics.GetTicketsByStatusAndMonth(c.Request.Context(), filter)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve tickets by status and month")
 			return
 		}
 
@@ -272,14 +315,17 @@ func QtdTicketsByStatusYearMonth(cfg *config.App) gin.HandlerFunc {
 			result[status][year] = append(result[status][year], monthly)
 		}
 
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data:    result,
-			Message: "Tickets by status and month retrieved successfully",
-		})
+		if format != export.FormatJSON {
+			export.Write(c, format, "tickets-by-status-year-month", export.GroupedYearlyData("TicketsByStatusYearMonth", "status", result))
+			return
+		}
+
+		if timeseries {
+			c.JSON(http.StatusOK, dto.NewSuccessResponse(c, groupedTimeseries(result), "Tickets by status and month retrieved successfully"))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, result, "Tickets by status and month retrieved successfully"))
 	}
 }
 
@@ -288,9 +334,18 @@ func QtdTicketsByStatusYearMonth(cfg *config.App) gin.HandlerFunc {
 // @Description  Retorna a quantidade de tickets agrupados por ano e mês.
 // @Tags         metrics
 // @Accept       json
-// @Produce      json
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Security 	 BearerAuth
-// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsByStatusYearMonth} "Tickets by status and month retrieved successfully"
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Param        format query string false "Formato da resposta (padrão json); timeseries retorna pontos {year, month, count}" Enums(json, csv, xlsx, timeseries)
+// @Success      200 {object} dto.SuccessResponse{data=dto.YearlyData} "Tickets by month retrieved successfully; com format=timeseries, data é uma lista de dto.MonthlyPoint"
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
 // @Failure 	 403 {object} dto.ErrorResponse "Forbidden - No permission"
@@ -302,18 +357,20 @@ func QtdTicketsByStatusYearMonth(cfg *config.App) gin.HandlerFunc {
 // @Router       /metrics/tickets/qtd-tickets-by-month [get]
 func TicketsByMonth(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		data, err := cfg.SqlServer.GetTicketsByMonth()
+		format, timeseries, ok := parseMonthlyFormat(c)
+		if !ok {
+			return
+		}
+
+		filter, err := parseMetricsFilter(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: c.GetTime("request_start_time"),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to retrieve tickets by month",
-				Details: err.Error(),
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+
+		data, err := cfg.Metrics.GetTicketsByMonth(c.Request.Context(), filter)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve tickets by month")
 			return
 		}
 
@@ -328,14 +385,17 @@ func TicketsByMonth(cfg *config.App) gin.HandlerFunc {
 		}
 		formattedData := transformToYearlyData(convertedData)
 
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data:    formattedData,
-			Message: "Tickets by month retrieved successfully",
-		})
+		if format != export.FormatJSON {
+			export.Write(c, format, "tickets-by-month", export.YearlyData("TicketsByMonth", formattedData))
+			return
+		}
+
+		if timeseries {
+			c.JSON(http.StatusOK, dto.NewSuccessResponse(c, yearlyTimeseries(formattedData), "Tickets by month retrieved successfully"))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, formattedData, "Tickets by month retrieved successfully"))
 
 	}
 }
@@ -391,9 +451,18 @@ func transformToYearlyData(data []dto.TicketsByMonth) dto.YearlyData {
 // @Description  Retorna a contagem de tickets agrupados por prioridade, ano e mês.
 // @Tags         metrics
 // @Accept       json
-// @Produce      json
+// @Produce      json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Security 	 BearerAuth
-// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsByStatusYearMonth} "Tickets by priority and month retrieved successfully"
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Param        format query string false "Formato da resposta (padrão json); timeseries retorna pontos {year, month, count}" Enums(json, csv, xlsx, timeseries)
+// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsByStatusYearMonth} "Tickets by priority and month retrieved successfully; com format=timeseries, data é dto.GroupedTimeseries (prioridade para a lista de dto.MonthlyPoint)"
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
 // @Failure 	 403 {object} dto.ErrorResponse "Forbidden - No permission"
@@ -405,18 +474,20 @@ func transformToYearlyData(data []dto.TicketsByMonth) dto.YearlyData {
 // @Router       /metrics/tickets/qtd-tickets-by-priority-year-month [get]
 func TicketsByPriorityAndMonth(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		data, err := cfg.SqlServer.GetTicketsByPriorityAndMonth()
+		format, timeseries, ok := parseMonthlyFormat(c)
+		if !ok {
+			return
+		}
+
+		filter, err := parseMetricsFilter(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: c.GetTime("request_start_time"),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to retrieve tickets by priority and month",
-				Details: err.Error(),
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+
+		data, err := cfg.Metrics.GetTicketsByPriorityAndMonth(c.Request.Context(), filter)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve tickets by priority and month")
 			return
 		}
 
@@ -446,13 +517,16 @@ func TicketsByPriorityAndMonth(cfg *config.App) gin.HandlerFunc {
 			result[priority][year] = append(result[priority][year], monthly)
 		}
 
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data:    result,
-			Message: "Tickets by priority and month retrieved successfully",
-		})
+		if format != export.FormatJSON {
+			export.Write(c, format, "tickets-by-priority-year-month", export.GroupedYearlyData("TicketsByPriorityYearMonth", "priority", result))
+			return
+		}
+
+		if timeseries {
+			c.JSON(http.StatusOK, dto.NewSuccessResponse(c, groupedTimeseries(result), "Tickets by priority and month retrieved successfully"))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, result, "Tickets by priority and month retrieved successfully"))
 	}
 }
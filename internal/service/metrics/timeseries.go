@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/export"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// formatTimeseries pede as contagens mensais como uma série de pontos {year, month, count}
+// em vez dos nomes de mês em português usados por dto.MonthlyCounts
+const formatTimeseries = "timeseries"
+
+// parseMonthlyFormat lê o parâmetro format dos handlers mensais, que além de json, csv e xlsx
+// aceitam timeseries. Responde 400 quando o formato é inválido.
+func parseMonthlyFormat(c *gin.Context) (format export.Format, timeseries bool, ok bool) {
+	if strings.EqualFold(strings.TrimSpace(c.Query("format")), formatTimeseries) {
+		return export.FormatJSON, true, true
+	}
+
+	format, ok = parseExportFormat(c)
+	return format, false, ok
+}
+
+// monthlyPoints converte as contagens de um ano nos doze pontos da série, de janeiro a dezembro
+func monthlyPoints(year int, counts dto.MonthlyCounts) []dto.MonthlyPoint {
+	values := []int64{
+		counts.Janeiro, counts.Fevereiro, counts.Marco, counts.Abril, counts.Maio, counts.Junho,
+		counts.Julho, counts.Agosto, counts.Setembro, counts.Outubro, counts.Novembro, counts.Dezembro,
+	}
+
+	points := make([]dto.MonthlyPoint, 0, len(values))
+	for i, count := range values {
+		points = append(points, dto.MonthlyPoint{Year: year, Month: i + 1, Count: count})
+	}
+	return points
+}
+
+// yearlyTimeseries converte os dados anuais em uma série ordenada por ano e mês
+func yearlyTimeseries(data dto.YearlyData) []dto.MonthlyPoint {
+	years := make([]int, 0, len(data))
+	for key := range data {
+		year, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	points := make([]dto.MonthlyPoint, 0, len(years)*12)
+	for _, year := range years {
+		for _, counts := range data[strconv.Itoa(year)] {
+			points = append(points, monthlyPoints(year, counts)...)
+		}
+	}
+	return points
+}
+
+// groupedTimeseries converte os dados anuais de cada grupo em uma série por grupo
+func groupedTimeseries(data dto.TicketsByStatusYearMonth) dto.GroupedTimeseries {
+	result := make(dto.GroupedTimeseries, len(data))
+	for group, yearly := range data {
+		result[group] = yearlyTimeseries(yearly)
+	}
+	return result
+}
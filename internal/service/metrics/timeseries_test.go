@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/export"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMonthlyFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name               string
+		query              string
+		expectedFormat     export.Format
+		expectedTimeseries bool
+		expectedOk         bool
+	}{
+		{name: "Default is legacy JSON", query: "", expectedFormat: export.FormatJSON, expectedOk: true},
+		{name: "Timeseries", query: "format=timeseries", expectedFormat: export.FormatJSON, expectedTimeseries: true, expectedOk: true},
+		{name: "Timeseries is case insensitive", query: "format=TimeSeries", expectedFormat: export.FormatJSON, expectedTimeseries: true, expectedOk: true},
+		{name: "Export format", query: "format=csv", expectedFormat: export.FormatCSV, expectedOk: true},
+		{name: "Invalid format", query: "format=xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/metrics?"+tt.query, nil)
+
+			format, timeseries, ok := parseMonthlyFormat(c)
+			assert.Equal(t, tt.expectedOk, ok)
+			if !ok {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				return
+			}
+			assert.Equal(t, tt.expectedFormat, format)
+			assert.Equal(t, tt.expectedTimeseries, timeseries)
+		})
+	}
+}
+
+func TestYearlyTimeseries(t *testing.T) {
+	data := dto.YearlyData{
+		"2025": {{Janeiro: 5, Dezembro: 7}},
+		"2024": {{Marco: 3}},
+	}
+
+	points := yearlyTimeseries(data)
+
+	require.Len(t, points, 24)
+	assert.Equal(t, dto.MonthlyPoint{Year: 2024, Month: 1, Count: 0}, points[0])
+	assert.Equal(t, dto.MonthlyPoint{Year: 2024, Month: 3, Count: 3}, points[2])
+	assert.Equal(t, dto.MonthlyPoint{Year: 2025, Month: 1, Count: 5}, points[12])
+	assert.Equal(t, dto.MonthlyPoint{Year: 2025, Month: 12, Count: 7}, points[23])
+}
+
+func TestGroupedTimeseries(t *testing.T) {
+	data := dto.TicketsByStatusYearMonth{
+		"ABERTO":  {"2025": {{Fevereiro: 2}}},
+		"FECHADO": {"2025": {{Junho: 9}}},
+	}
+
+	result := groupedTimeseries(data)
+
+	require.Len(t, result, 2)
+	require.Len(t, result["ABERTO"], 12)
+	assert.Equal(t, int64(2), result["ABERTO"][1].Count)
+	assert.Equal(t, dto.MonthlyPoint{Year: 2025, Month: 6, Count: 9}, result["FECHADO"][5])
+}
@@ -0,0 +1,350 @@
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultTopN = 10
+	maxTopN     = 100
+
+	defaultTrendPeriods = 8
+	maxTrendPeriods     = 52
+	defaultMoversN      = 5
+	maxMoversN          = 50
+)
+
+// defaultTopDimensions são as dimensões do top-N quando dimensions não é informado
+var defaultTopDimensions = []string{"category", "tag", "product"}
+
+// TicketsTop retorna os maiores valores de cada dimensão no período
+// @Summary      Top-N de Tickets
+// @Description  Retorna, para cada dimensão pedida (padrão category, tag e product), os N valores com mais tickets abertos no período e a participação de cada um no total da dimensão.
+// @Tags         metrics
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        dimensions query string false "Dimensões separadas por vírgula (category, channel, company, department, priority, product, tag)" default(category,tag,product)
+// @Param        n query int false "Quantidade de valores por dimensão (máximo 100)" default(10)
+// @Param        startDate query string false "Data inicial de abertura (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final de abertura, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsTopResponse} "Top tickets retrieved successfully"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Rate limit exceeded"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /metrics/tickets/top [get]
+func TicketsTop(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dimensions, invalid := parseDimensions(c.Query("dimensions"))
+		if invalid != "" {
+			respondInvalidDimension(c, invalid)
+			return
+		}
+
+		filter, err := parseMetricsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+
+		n := parseLimit(c, "n", defaultTopN, maxTopN)
+		ctx := c.Request.Context()
+
+		response := dto.TicketsTopResponse{N: n, Dimensions: make([]dto.TopDimension, 0, len(dimensions))}
+		for _, dimension := range dimensions {
+			values, err := cfg.Metrics.GetTicketsTop(ctx, dimension, filter, n)
+			if err != nil {
+				middleware.RespondError(c, err, "Failed to retrieve top tickets")
+				return
+			}
+
+			total, err := cfg.Metrics.GetTicketsBreakdownTotal(ctx, dimension, filter)
+			if err != nil {
+				middleware.RespondError(c, err, "Failed to retrieve top tickets")
+				return
+			}
+
+			response.Dimensions = append(response.Dimensions, topDimension(dimension, values, total))
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Top tickets retrieved successfully"))
+	}
+}
+
+// TicketsTrend retorna a variação de tickets semana a semana ou mês a mês
+// @Summary      Tendência de Tickets
+// @Description  Retorna o total de tickets abertos nas últimas semanas ou meses até endDate (padrão hoje) e a variação de cada período em relação ao anterior. O último período pode estar incompleto (partial). Com dimension, retorna também os valores da dimensão que mais variaram entre o período atual e o mesmo intervalo do período anterior.
+// @Tags         metrics
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        granularity query string false "Granularidade" Enums(week, month) default(week)
+// @Param        periods query int false "Quantidade de períodos (máximo 52)" default(8)
+// @Param        dimension query string false "Dimensão dos destaques" Enums(category, channel, company, department, priority, product, tag)
+// @Param        n query int false "Quantidade de destaques (máximo 50)" default(5)
+// @Param        endDate query string false "Data de referência, inclusiva (YYYY-MM-DD)"
+// @Param        year query int false "Ano de abertura"
+// @Param        company query string false "Nome da empresa"
+// @Param        department query string false "Departamento do agente atribuído"
+// @Param        channel query string false "Canal de abertura"
+// @Param        priority query string false "Prioridade"
+// @Param        timezone query string false "Fuso IANA das datas e meses (ex.: America/Sao_Paulo); também aceito no header Accept-Timezone. Padrão UTC"
+// @Success      200 {object} dto.SuccessResponse{data=dto.TicketsTrendResponse} "Tickets trend retrieved successfully"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Rate limit exceeded"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /metrics/tickets/trend [get]
+func TicketsTrend(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granularity := strings.ToLower(c.DefaultQuery("granularity", sqlserver.TrendWeek))
+		if granularity != sqlserver.TrendWeek && granularity != sqlserver.TrendMonth {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid granularity", map[string]interface{}{
+				"granularity": granularity,
+				"allowed":     []string{sqlserver.TrendWeek, sqlserver.TrendMonth},
+			}))
+			return
+		}
+
+		dimension := strings.ToLower(strings.TrimSpace(c.Query("dimension")))
+		if dimension != "" && !sqlserver.IsBreakdownDimension(dimension) {
+			respondInvalidDimension(c, dimension)
+			return
+		}
+
+		filter, err := parseMetricsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid metrics filter", err.Error()))
+			return
+		}
+		if filter.StartDate != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid date filter",
+				"startDate is not accepted, the trend window is defined by endDate, granularity and periods"))
+			return
+		}
+
+		reference := truncateDay(time.Now().In(sqlserver.FilterLocation(filter)))
+		if filter.EndDate != nil {
+			reference = truncateDay(*filter.EndDate)
+		}
+
+		// Um período a mais no início para calcular a variação do primeiro ponto
+		starts := periodStarts(reference, granularity, parseLimit(c, "periods", defaultTrendPeriods, maxTrendPeriods)+1)
+		filter.StartDate = &starts[0]
+		filter.EndDate = &reference
+
+		ctx := c.Request.Context()
+		totals, err := cfg.Metrics.GetTicketsByPeriod(ctx, granularity, filter)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve tickets trend")
+			return
+		}
+
+		response := dto.TicketsTrendResponse{
+			Granularity: granularity,
+			Points:      buildTrendPoints(starts, granularity, reference, totals),
+		}
+
+		if dimension != "" {
+			currentStart := starts[len(starts)-1]
+			previousStart, previousEnd := comparablePeriod(currentStart, reference, granularity)
+
+			moversFilter := filter
+			moversFilter.StartDate = &previousStart
+
+			movers, err := cfg.Metrics.GetTrendMovers(ctx, dimension, moversFilter, currentStart, previousEnd, parseLimit(c, "n", defaultMoversN, maxMoversN))
+			if err != nil {
+				middleware.RespondError(c, err, "Failed to retrieve tickets trend")
+				return
+			}
+
+			response.Dimension = dimension
+			response.Movers = trendMovers(movers)
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Tickets trend retrieved successfully"))
+	}
+}
+
+// respondInvalidDimension responde 400 com as dimensões aceitas
+func respondInvalidDimension(c *gin.Context, dimension string) {
+	c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid dimension", map[string]interface{}{
+		"dimension": dimension,
+		"allowed":   sqlserver.BreakdownDimensions(),
+	}))
+}
+
+// parseDimensions lê a lista de dimensões separadas por vírgula, sem repetições. Retorna a primeira
+// dimensão inválida, se houver.
+func parseDimensions(value string) ([]string, string) {
+	if strings.TrimSpace(value) == "" {
+		return defaultTopDimensions, ""
+	}
+
+	var dimensions []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		dimension := strings.ToLower(strings.TrimSpace(part))
+		if dimension == "" || seen[dimension] {
+			continue
+		}
+		if !sqlserver.IsBreakdownDimension(dimension) {
+			return nil, dimension
+		}
+		seen[dimension] = true
+		dimensions = append(dimensions, dimension)
+	}
+
+	if len(dimensions) == 0 {
+		return defaultTopDimensions, ""
+	}
+	return dimensions, ""
+}
+
+// parseLimit lê um inteiro positivo da query, usando fallback para valores ausentes ou inválidos e
+// limitando ao máximo
+func parseLimit(c *gin.Context, name string, fallback, max int) int {
+	value, err := strconv.Atoi(c.Query(name))
+	if err != nil || value < 1 {
+		return fallback
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// topDimension calcula a participação de cada valor no total da dimensão
+func topDimension(dimension string, values []dto.MetricValue, total int64) dto.TopDimension {
+	result := dto.TopDimension{Dimension: dimension, Total: total, Values: make([]dto.TopValue, 0, len(values))}
+	for _, value := range values {
+		var share float64
+		if total > 0 {
+			share = roundOne(float64(value.Value) * 100 / float64(total))
+		}
+		result.Values = append(result.Values, dto.TopValue{Name: value.Name, Value: value.Value, Share: share})
+	}
+	return result
+}
+
+// truncateDay descarta o horário, mantendo o dia do calendário
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart retorna o primeiro dia do período do dia: a segunda-feira da semana ou o dia 1 do mês
+func periodStart(day time.Time, granularity string) time.Time {
+	day = truncateDay(day)
+	if granularity == sqlserver.TrendMonth {
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// addPeriods desloca o início de período em n semanas ou meses
+func addPeriods(start time.Time, granularity string, n int) time.Time {
+	if granularity == sqlserver.TrendMonth {
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, 7*n)
+}
+
+// periodStarts retorna o início dos count períodos que terminam no período da data de referência, em ordem
+func periodStarts(reference time.Time, granularity string, count int) []time.Time {
+	last := periodStart(reference, granularity)
+	starts := make([]time.Time, count)
+	for i := range starts {
+		starts[i] = addPeriods(last, granularity, i-count+1)
+	}
+	return starts
+}
+
+// comparablePeriod retorna o intervalo do período anterior com a mesma duração já decorrida do
+// período atual, para comparar uma semana ou mês em andamento sem desvantagem
+func comparablePeriod(currentStart, reference time.Time, granularity string) (time.Time, time.Time) {
+	previousStart := addPeriods(currentStart, granularity, -1)
+	elapsedDays := int(reference.Sub(currentStart).Hours() / 24)
+
+	previousEnd := previousStart.AddDate(0, 0, elapsedDays)
+	if !previousEnd.Before(currentStart) {
+		previousEnd = currentStart.AddDate(0, 0, -1)
+	}
+	return previousStart, previousEnd
+}
+
+// buildTrendPoints monta um ponto por período, inclusive os sem tickets, com a variação em relação
+// ao período anterior. O primeiro início serve apenas de base para a variação e não vira ponto.
+func buildTrendPoints(starts []time.Time, granularity string, reference time.Time, totals []sqlserver.PeriodTotal) []dto.TrendPoint {
+	byStart := make(map[string]int64, len(totals))
+	for _, total := range totals {
+		byStart[total.PeriodStart.Format(filterDateLayout)] += total.Total
+	}
+
+	if len(starts) < 2 {
+		return []dto.TrendPoint{}
+	}
+
+	points := make([]dto.TrendPoint, 0, len(starts)-1)
+	previous := byStart[starts[0].Format(filterDateLayout)]
+	for _, start := range starts[1:] {
+		end := addPeriods(start, granularity, 1).AddDate(0, 0, -1)
+		count := byStart[start.Format(filterDateLayout)]
+
+		points = append(points, dto.TrendPoint{
+			PeriodStart:  start.Format(filterDateLayout),
+			PeriodEnd:    end.Format(filterDateLayout),
+			Count:        count,
+			Delta:        count - previous,
+			DeltaPercent: deltaPercent(count, previous),
+			Partial:      end.After(reference),
+		})
+		previous = count
+	}
+	return points
+}
+
+// trendMovers converte as variações do banco na resposta da API
+func trendMovers(movers []sqlserver.TrendMover) []dto.TrendMover {
+	result := make([]dto.TrendMover, 0, len(movers))
+	for _, mover := range movers {
+		result = append(result, dto.TrendMover{
+			Name:         mover.Name,
+			Current:      mover.Current,
+			Previous:     mover.Previous,
+			Delta:        mover.Current - mover.Previous,
+			DeltaPercent: deltaPercent(mover.Current, mover.Previous),
+		})
+	}
+	return result
+}
+
+// deltaPercent retorna a variação percentual com uma casa decimal, ou nil quando não havia base
+func deltaPercent(current, previous int64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	percent := roundOne(float64(current-previous) * 100 / float64(previous))
+	return &percent
+}
+
+// roundOne arredonda para uma casa decimal
+func roundOne(value float64) float64 {
+	return math.Round(value*10) / 10
+}
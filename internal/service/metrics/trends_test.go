@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(value string) time.Time {
+	parsed, err := time.Parse(filterDateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		name        string
+		day         string
+		granularity string
+		expected    string
+	}{
+		{name: "Monday starts its own week", day: "2025-10-13", granularity: sqlserver.TrendWeek, expected: "2025-10-13"},
+		{name: "Sunday belongs to the previous Monday", day: "2025-10-19", granularity: sqlserver.TrendWeek, expected: "2025-10-13"},
+		{name: "Week across months", day: "2025-10-01", granularity: sqlserver.TrendWeek, expected: "2025-09-29"},
+		{name: "Month", day: "2025-10-19", granularity: sqlserver.TrendMonth, expected: "2025-10-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, periodStart(day(tt.day), tt.granularity).Format(filterDateLayout))
+		})
+	}
+}
+
+func TestPeriodStarts(t *testing.T) {
+	starts := periodStarts(day("2025-03-15"), sqlserver.TrendMonth, 3)
+
+	formatted := make([]string, 0, len(starts))
+	for _, start := range starts {
+		formatted = append(formatted, start.Format(filterDateLayout))
+	}
+	assert.Equal(t, []string{"2025-01-01", "2025-02-01", "2025-03-01"}, formatted)
+}
+
+func TestComparablePeriod(t *testing.T) {
+	tests := []struct {
+		name          string
+		currentStart  string
+		reference     string
+		granularity   string
+		expectedStart string
+		expectedEnd   string
+	}{
+		{
+			name:          "Week in progress compares the same weekdays",
+			currentStart:  "2025-10-13",
+			reference:     "2025-10-15",
+			granularity:   sqlserver.TrendWeek,
+			expectedStart: "2025-10-06",
+			expectedEnd:   "2025-10-08",
+		},
+		{
+			name:          "Month in progress compares the same days",
+			currentStart:  "2025-10-01",
+			reference:     "2025-10-10",
+			granularity:   sqlserver.TrendMonth,
+			expectedStart: "2025-09-01",
+			expectedEnd:   "2025-09-10",
+		},
+		{
+			name:          "Longer month is capped at the end of the previous one",
+			currentStart:  "2025-03-01",
+			reference:     "2025-03-31",
+			granularity:   sqlserver.TrendMonth,
+			expectedStart: "2025-02-01",
+			expectedEnd:   "2025-02-28",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := comparablePeriod(day(tt.currentStart), day(tt.reference), tt.granularity)
+			assert.Equal(t, tt.expectedStart, start.Format(filterDateLayout))
+			assert.Equal(t, tt.expectedEnd, end.Format(filterDateLayout))
+		})
+	}
+}
+
+func TestBuildTrendPoints(t *testing.T) {
+	starts := periodStarts(day("2025-10-15"), sqlserver.TrendWeek, 4)
+	totals := []sqlserver.PeriodTotal{
+		{PeriodStart: day("2025-09-22"), Total: 10},
+		{PeriodStart: day("2025-09-29"), Total: 15},
+		{PeriodStart: day("2025-10-13"), Total: 6},
+	}
+
+	points := buildTrendPoints(starts, sqlserver.TrendWeek, day("2025-10-15"), totals)
+
+	fifty := 50.0
+	minusHundred := -100.0
+	assert.Equal(t, []dto.TrendPoint{
+		{PeriodStart: "2025-09-29", PeriodEnd: "2025-10-05", Count: 15, Delta: 5, DeltaPercent: &fifty},
+		{PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12", Count: 0, Delta: -15, DeltaPercent: &minusHundred},
+		{PeriodStart: "2025-10-13", PeriodEnd: "2025-10-19", Count: 6, Delta: 6, Partial: true},
+	}, points)
+}
+
+func TestTrendMovers(t *testing.T) {
+	movers := trendMovers([]sqlserver.TrendMover{
+		{Name: "Financeiro", Current: 48, Previous: 30},
+		{Name: "Novo", Current: 7, Previous: 0},
+	})
+
+	sixty := 60.0
+	assert.Equal(t, []dto.TrendMover{
+		{Name: "Financeiro", Current: 48, Previous: 30, Delta: 18, DeltaPercent: &sixty},
+		{Name: "Novo", Current: 7, Previous: 0, Delta: 7},
+	}, movers)
+}
+
+func TestParseDimensions(t *testing.T) {
+	tests := []struct {
+		name            string
+		value           string
+		expected        []string
+		expectedInvalid string
+	}{
+		{name: "Empty uses the defaults", value: "", expected: defaultTopDimensions},
+		{name: "List is normalized and deduplicated", value: " Tag,category,tag ", expected: []string{"tag", "category"}},
+		{name: "Unknown dimension", value: "category,status", expectedInvalid: "status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dimensions, invalid := parseDimensions(tt.value)
+			assert.Equal(t, tt.expectedInvalid, invalid)
+			if tt.expectedInvalid == "" {
+				assert.Equal(t, tt.expected, dimensions)
+			}
+		})
+	}
+}
+
+func TestTopDimension(t *testing.T) {
+	result := topDimension("product", []dto.MetricValue{{Name: "ERP", Value: 37}, {Name: "CRM", Value: 13}}, 200)
+
+	assert.Equal(t, dto.TopDimension{
+		Dimension: "product",
+		Total:     200,
+		Values: []dto.TopValue{
+			{Name: "ERP", Value: 37, Share: 18.5},
+			{Name: "CRM", Value: 13, Share: 6.5},
+		},
+	}, result)
+}
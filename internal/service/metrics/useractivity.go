@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultActivityDays é o período da atividade dos usuários quando startDate não é informado
+	defaultActivityDays = 30
+	// maxActivityDays limita o período a um ano, para manter a série diária pequena
+	maxActivityDays = 366
+
+	defaultActiveUsersPageSize = 20
+	maxActiveUsersPageSize     = 100
+)
+
+// UsersActivity retorna a atividade de autenticação dos usuários no período
+// @Summary      Atividade dos Usuários
+// @Description  Retorna, a partir de dbo.UserAuthLogs, o total de logins com e sem sucesso, a porcentagem de falhas, os usuários distintos que entraram, os logins por dia (UTC, incluindo os dias sem logins) e a página pedida dos usuários com mais logins. Sem datas, retorna os últimos 30 dias.
+// @Tags         metrics
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        startDate query string false "Data inicial (YYYY-MM-DD)"
+// @Param        endDate query string false "Data final, inclusiva (YYYY-MM-DD); padrão hoje"
+// @Param        page query int false "Página dos usuários mais ativos" default(1)
+// @Param        pageSize query int false "Usuários por página (máximo 100)" default(20)
+// @Success      200 {object} dto.PaginatedResponse{data=dto.UserActivityResponse} "Users activity retrieved successfully"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized - Invalid token"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden - No permission"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Rate limit exceeded"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /metrics/users/activity [get]
+func UsersActivity(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start, end, err := parseActivityPeriod(c.Query("startDate"), c.Query("endDate"), time.Now().UTC())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid date filter", err.Error()))
+			return
+		}
+
+		ctx := c.Request.Context()
+		page := middleware.ParsePage(c, defaultActiveUsersPageSize, maxActiveUsersPageSize)
+		to := end.AddDate(0, 0, 1)
+
+		totals, err := cfg.SqlServer.GetAuthActivityTotals(ctx, start, to)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve users activity")
+			return
+		}
+
+		days, err := cfg.SqlServer.GetDailyAuthActivity(ctx, start, to)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve users activity")
+			return
+		}
+
+		users, count, err := cfg.SqlServer.GetMostActiveUsers(ctx, start, to, page.Offset(), page.Size)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve users activity")
+			return
+		}
+
+		response := dto.UserActivityResponse{
+			StartDate:          start.Format(filterDateLayout),
+			EndDate:            end.Format(filterDateLayout),
+			Logins:             totals.Successful,
+			FailedLogins:       totals.Failed,
+			FailedLoginPercent: failedLoginPercent(totals),
+			ActiveUsers:        totals.ActiveUsers,
+			LoginsPerDay:       dailyLogins(start, end, days),
+			MostActiveUsers:    activeUsers(users),
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, response, middleware.NewPagination(c, page, count), "Users activity retrieved successfully"))
+	}
+}
+
+// parseActivityPeriod lê o período da atividade: endDate padrão hoje e startDate padrão 29 dias antes,
+// com no máximo maxActivityDays dias
+func parseActivityPeriod(startValue, endValue string, now time.Time) (start, end time.Time, err error) {
+	end = truncateDay(now)
+	if endValue != "" {
+		if end, err = time.Parse(filterDateLayout, endValue); err != nil {
+			return start, end, fmt.Errorf("invalid endDate %q, expected format YYYY-MM-DD", endValue)
+		}
+	}
+
+	start = end.AddDate(0, 0, -(defaultActivityDays - 1))
+	if startValue != "" {
+		if start, err = time.Parse(filterDateLayout, startValue); err != nil {
+			return start, end, fmt.Errorf("invalid startDate %q, expected format YYYY-MM-DD", startValue)
+		}
+	}
+
+	if end.Before(start) {
+		return start, end, errors.New("endDate must not be before startDate")
+	}
+	if end.Sub(start) >= maxActivityDays*24*time.Hour {
+		return start, end, fmt.Errorf("the period must have at most %d days", maxActivityDays)
+	}
+	return start, end, nil
+}
+
+// dailyLogins monta a série diária do período, com zero nos dias sem autenticações
+func dailyLogins(start, end time.Time, days []sqlserver.DailyAuthActivity) []dto.DailyLogins {
+	byDay := make(map[string]sqlserver.DailyAuthActivity, len(days))
+	for _, day := range days {
+		byDay[day.Day.Format(filterDateLayout)] = day
+	}
+
+	var series []dto.DailyLogins
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(filterDateLayout)
+		activity := byDay[date]
+		series = append(series, dto.DailyLogins{
+			Date:       date,
+			Successful: activity.Successful,
+			Failed:     activity.Failed,
+			Users:      activity.Users,
+		})
+	}
+	return series
+}
+
+// activeUsers converte a página de usuários mais ativos
+func activeUsers(users []sqlserver.UserAuthActivity) []dto.ActiveUser {
+	result := make([]dto.ActiveUser, 0, len(users))
+	for _, user := range users {
+		result = append(result, dto.ActiveUser{
+			UserId:       user.UserId,
+			Name:         user.Name,
+			Email:        user.Email,
+			UserType:     user.UserType,
+			Logins:       user.Successful,
+			FailedLogins: user.Failed,
+			LastLoginAt:  user.LastLoginAt,
+		})
+	}
+	return result
+}
+
+// failedLoginPercent é a porcentagem das tentativas que falharam, com duas casas decimais
+func failedLoginPercent(totals sqlserver.AuthActivityTotals) float64 {
+	attempts := totals.Successful + totals.Failed
+	if attempts == 0 {
+		return 0
+	}
+	return math.Round(float64(totals.Failed)/float64(attempts)*10000) / 100
+}
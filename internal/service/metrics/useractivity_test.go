@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"orderstreamrest/internal/repositories/sqlserver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActivityPeriod(t *testing.T) {
+	now := time.Date(2025, 10, 16, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		startDate     string
+		endDate       string
+		expectError   bool
+		expectedStart string
+		expectedEnd   string
+	}{
+		{name: "Success - Defaults to the last 30 days", expectedStart: "2025-09-17", expectedEnd: "2025-10-16"},
+		{name: "Success - End date only", endDate: "2025-06-30", expectedStart: "2025-06-01", expectedEnd: "2025-06-30"},
+		{name: "Success - Custom period", startDate: "2025-01-01", endDate: "2025-01-01", expectedStart: "2025-01-01", expectedEnd: "2025-01-01"},
+		{name: "Success - One year", startDate: "2024-01-01", endDate: "2024-12-31", expectedStart: "2024-01-01", expectedEnd: "2024-12-31"},
+		{name: "Error - Longer than a year", startDate: "2024-01-01", endDate: "2025-01-01", expectError: true},
+		{name: "Error - End before start", startDate: "2025-02-01", endDate: "2025-01-01", expectError: true},
+		{name: "Error - Invalid date", startDate: "01/01/2025", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseActivityPeriod(tt.startDate, tt.endDate, now)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStart, start.Format(time.DateOnly))
+			assert.Equal(t, tt.expectedEnd, end.Format(time.DateOnly))
+		})
+	}
+}
+
+func TestDailyLogins(t *testing.T) {
+	start := time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	series := dailyLogins(start, end, []sqlserver.DailyAuthActivity{
+		{Day: time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC), Successful: 12, Failed: 1, Users: 5},
+	})
+
+	require.Len(t, series, 3)
+	assert.Equal(t, "2025-10-14", series[0].Date)
+	assert.Zero(t, series[0].Successful)
+	assert.Equal(t, "2025-10-15", series[1].Date)
+	assert.Equal(t, int64(12), series[1].Successful)
+	assert.Equal(t, int64(1), series[1].Failed)
+	assert.Equal(t, int64(5), series[1].Users)
+	assert.Equal(t, "2025-10-16", series[2].Date)
+}
+
+func TestFailedLoginPercent(t *testing.T) {
+	assert.Zero(t, failedLoginPercent(sqlserver.AuthActivityTotals{}))
+	assert.Equal(t, 6.25, failedLoginPercent(sqlserver.AuthActivityTotals{Successful: 150, Failed: 10}))
+	assert.Equal(t, 33.33, failedLoginPercent(sqlserver.AuthActivityTotals{Successful: 2, Failed: 1}))
+}
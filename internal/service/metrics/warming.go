@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/service/jobs"
+	"time"
+)
+
+// CacheWarmingJobName é a rotina que pré-calcula no Redis as métricas pesadas dos dashboards
+const CacheWarmingJobName = "metrics_cache_warming"
+
+// cacheWarmingMargin mantém as entradas aquecidas até um pouco depois da próxima execução, para que o
+// cache não esvazie enquanto a rotina recalcula as consultas
+const cacheWarmingMargin = 15 * time.Minute
+
+func init() {
+	jobs.Register(jobs.Job{
+		Name:        CacheWarmingJobName,
+		Description: "Pre-computes the unfiltered dashboard metrics queries into the Redis cache, on startup and at every interval",
+		Defaults: entities.ScheduledJob{
+			Enabled:         true,
+			IntervalMinutes: 60,
+		},
+		Startup: true,
+		Run:     warmMetricsCache,
+	})
+}
+
+// warmMetricsCache recalcula as consultas dos dashboards no DW. As entradas valem até a próxima
+// execução, de modo que a primeira carga do dashboard não espera pelo banco.
+func warmMetricsCache(ctx context.Context, cfg *config.App, settings entities.ScheduledJob) (int64, error) {
+	ttl := time.Duration(settings.IntervalMinutes)*time.Minute + cacheWarmingMargin
+	warmed, err := cfg.Metrics.Warm(ctx, ttl)
+	return int64(warmed), err
+}
@@ -0,0 +1,51 @@
+package notifications
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultNotificationsLimit = 50
+
+// GetMyNotifications lista as notificações do usuário autenticado
+// @Summary      Minhas Notificações
+// @Description  Retorna as notificações in-app mais recentes do usuário autenticado, como alterações em tickets observados
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        limit query int false "Quantidade máxima de notificações (padrão 50, máximo 100)"
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.Notification}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /me/notifications [get]
+func GetMyNotifications(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Authenticated user not found", nil))
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultNotificationsLimit)))
+		if err != nil || limit < 1 {
+			limit = defaultNotificationsLimit
+		}
+		if limit > maxNotificationsPerUser {
+			limit = maxNotificationsPerUser
+		}
+
+		notifications, err := List(c.Request.Context(), cfg, userID, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve notifications", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, notifications, "Notifications retrieved successfully"))
+	}
+}
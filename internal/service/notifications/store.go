@@ -0,0 +1,194 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+)
+
+const (
+	// watchedTicketsKey guarda os IDs de todos os tickets com pelo menos um observador
+	watchedTicketsKey = "ticket:watched"
+
+	maxNotificationsPerUser = 100
+)
+
+func watchersKey(ticketID string) string {
+	return "ticket:watchers:" + ticketID
+}
+
+func snapshotKey(ticketID string) string {
+	return "ticket:snapshot:" + ticketID
+}
+
+func userNotificationsKey(userID int) string {
+	return "notifications:user:" + strconv.Itoa(userID)
+}
+
+// Watch registra o usuário como observador do ticket e guarda o estado atual para comparação
+func Watch(ctx context.Context, cfg *config.App, userID int, ticketID string, ticket map[string]interface{}) error {
+	if err := cfg.Redis.SAdd(ctx, watchersKey(ticketID), userID).Err(); err != nil {
+		return fmt.Errorf("failed to add ticket watcher: %w", err)
+	}
+
+	if err := cfg.Redis.SAdd(ctx, watchedTicketsKey, ticketID).Err(); err != nil {
+		return fmt.Errorf("failed to register watched ticket: %w", err)
+	}
+
+	// Só grava o estado inicial se o ticket ainda não é observado, para não perder alterações pendentes
+	exists, err := cfg.Redis.HGetAll(ctx, snapshotKey(ticketID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get ticket snapshot: %w", err)
+	}
+	if len(exists) == 0 {
+		if err := saveSnapshot(ctx, cfg, ticketID, snapshot(ticket)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Unwatch remove o usuário dos observadores do ticket
+func Unwatch(ctx context.Context, cfg *config.App, userID int, ticketID string) error {
+	if err := cfg.Redis.SRem(ctx, watchersKey(ticketID), userID).Err(); err != nil {
+		return fmt.Errorf("failed to remove ticket watcher: %w", err)
+	}
+
+	remaining, err := cfg.Redis.SCard(ctx, watchersKey(ticketID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to count ticket watchers: %w", err)
+	}
+
+	// Sem observadores, o ticket deixa de ser verificado
+	if remaining == 0 {
+		if err := cfg.Redis.SRem(ctx, watchedTicketsKey, ticketID).Err(); err != nil {
+			return fmt.Errorf("failed to unregister watched ticket: %w", err)
+		}
+		if err := cfg.Redis.Del(ctx, snapshotKey(ticketID)).Err(); err != nil {
+			return fmt.Errorf("failed to delete ticket snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// List retorna as notificações mais recentes do usuário
+func List(ctx context.Context, cfg *config.App, userID int, limit int) ([]dto.Notification, error) {
+	values, err := cfg.Redis.LRange(ctx, userNotificationsKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	notifications := make([]dto.Notification, 0, len(values))
+	for _, value := range values {
+		var notification dto.Notification
+		if err := json.Unmarshal([]byte(value), &notification); err != nil {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// push adiciona uma notificação ao usuário, mantendo apenas as mais recentes
+func push(ctx context.Context, cfg *config.App, userID int, notification dto.Notification) error {
+	value, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to serialize notification: %w", err)
+	}
+
+	key := userNotificationsKey(userID)
+	if err := cfg.Redis.LPush(ctx, key, value).Err(); err != nil {
+		return fmt.Errorf("failed to push notification: %w", err)
+	}
+
+	if err := cfg.Redis.LTrim(ctx, key, 0, maxNotificationsPerUser-1).Err(); err != nil {
+		return fmt.Errorf("failed to trim notifications: %w", err)
+	}
+
+	return nil
+}
+
+// saveSnapshot grava o último estado conhecido do ticket
+func saveSnapshot(ctx context.Context, cfg *config.App, ticketID string, values map[string]string) error {
+	fields := make([]interface{}, 0, len(values)*2)
+	for field, value := range values {
+		fields = append(fields, field, value)
+	}
+
+	if err := cfg.Redis.HSet(ctx, snapshotKey(ticketID), fields...).Err(); err != nil {
+		return fmt.Errorf("failed to save ticket snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// CountUserWatches conta os tickets observados pelo usuário
+func CountUserWatches(ctx context.Context, cfg *config.App, userID int) (int64, error) {
+	ticketIDs, err := cfg.Redis.SMembers(ctx, watchedTicketsKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list watched tickets: %w", err)
+	}
+
+	var count int64
+	for _, ticketID := range ticketIDs {
+		watching, err := cfg.Redis.SIsMember(ctx, watchersKey(ticketID), userID).Result()
+		if err != nil {
+			return count, fmt.Errorf("failed to check ticket watcher: %w", err)
+		}
+		if watching {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// UnwatchAll remove o usuário dos observadores de todos os tickets e retorna quantos ele observava
+func UnwatchAll(ctx context.Context, cfg *config.App, userID int) (int64, error) {
+	ticketIDs, err := cfg.Redis.SMembers(ctx, watchedTicketsKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list watched tickets: %w", err)
+	}
+
+	var count int64
+	for _, ticketID := range ticketIDs {
+		watching, err := cfg.Redis.SIsMember(ctx, watchersKey(ticketID), userID).Result()
+		if err != nil {
+			return count, fmt.Errorf("failed to check ticket watcher: %w", err)
+		}
+		if !watching {
+			continue
+		}
+		if err := Unwatch(ctx, cfg, userID, ticketID); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// CountNotifications conta as notificações guardadas do usuário
+func CountNotifications(ctx context.Context, cfg *config.App, userID int) (int64, error) {
+	count, err := cfg.Redis.LLen(ctx, userNotificationsKey(userID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteNotifications apaga as notificações do usuário e retorna quantas havia
+func DeleteNotifications(ctx context.Context, cfg *config.App, userID int) (int64, error) {
+	count, err := CountNotifications(ctx, cfg, userID)
+	if err != nil {
+		return 0, err
+	}
+	if err := cfg.Redis.Del(ctx, userNotificationsKey(userID)).Err(); err != nil {
+		return 0, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,172 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultWatchInterval = 60 * time.Second
+
+// watchedField é um campo do ticket cuja alteração gera notificação
+type watchedField struct {
+	name  string
+	value func(ticket map[string]interface{}) string
+}
+
+var watchedFields = []watchedField{
+	{name: "status", value: func(ticket map[string]interface{}) string {
+		return stringField(ticket, "current_status")
+	}},
+	{name: "priority", value: func(ticket map[string]interface{}) string {
+		return stringField(ticket, "priority")
+	}},
+	{name: "assigned_agent", value: func(ticket map[string]interface{}) string {
+		agent, ok := ticket["assigned_agent"].(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		if name := stringField(agent, "full_name"); name != "" {
+			return name
+		}
+		return stringField(agent, "id")
+	}},
+}
+
+// StartTicketWatcher compara periodicamente os tickets observados com o último estado conhecido
+// e notifica os observadores quando status, prioridade ou responsável mudam
+func StartTicketWatcher(ctx context.Context, cfg *config.App) {
+	interval := defaultWatchInterval
+	if seconds, err := strconv.Atoi(os.Getenv("TICKET_WATCH_INTERVAL_SECONDS")); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkWatchedTickets(ctx, cfg)
+		}
+	}
+}
+
+// checkWatchedTickets verifica todos os tickets que possuem observadores
+func checkWatchedTickets(ctx context.Context, cfg *config.App) {
+	ticketIDs, err := cfg.Redis.SMembers(ctx, watchedTicketsKey).Result()
+	if err != nil {
+		cfg.Logger.Warn(fmt.Sprintf("Failed to list watched tickets: %v", err))
+		return
+	}
+
+	// O escopo foi verificado quando o ticket passou a ser observado
+	searchCtx := elsearch.WithScope(ctx, elsearch.UnrestrictedScope())
+
+	for _, ticketID := range ticketIDs {
+		if err := checkTicket(searchCtx, cfg, ticketID); err != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Failed to check watched ticket %s: %v", ticketID, err))
+		}
+	}
+}
+
+// checkTicket compara o ticket com o último estado conhecido e notifica os observadores
+func checkTicket(ctx context.Context, cfg *config.App, ticketID string) error {
+	ticket, err := cfg.ES.SearchTicketByID(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	if ticket == nil {
+		return nil
+	}
+
+	previous, err := cfg.Redis.HGetAll(ctx, snapshotKey(ticketID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get ticket snapshot: %w", err)
+	}
+
+	current := snapshot(*ticket)
+
+	// Sem estado anterior não há com o que comparar
+	if len(previous) == 0 {
+		return saveSnapshot(ctx, cfg, ticketID, current)
+	}
+
+	changes := diffSnapshots(previous, current)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := saveSnapshot(ctx, cfg, ticketID, current); err != nil {
+		return err
+	}
+
+	watchers, err := cfg.Redis.SMembers(ctx, watchersKey(ticketID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list ticket watchers: %w", err)
+	}
+
+	notification := dto.Notification{
+		Id:        uuid.New().String(),
+		Type:      "ticket_changed",
+		TicketId:  ticketID,
+		Title:     stringField(*ticket, "title"),
+		Changes:   changes,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	for _, watcher := range watchers {
+		userID, err := strconv.Atoi(watcher)
+		if err != nil {
+			continue
+		}
+		if err := push(ctx, cfg, userID, notification); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// snapshot extrai os campos observados do ticket
+func snapshot(ticket map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(watchedFields))
+	for _, field := range watchedFields {
+		values[field.name] = field.value(ticket)
+	}
+	return values
+}
+
+// diffSnapshots lista os campos observados que mudaram entre dois estados
+func diffSnapshots(previous, current map[string]string) []dto.FieldChange {
+	var changes []dto.FieldChange
+	for _, field := range watchedFields {
+		from, to := previous[field.name], current[field.name]
+		if from != to {
+			changes = append(changes, dto.FieldChange{
+				Field: field.name,
+				From:  from,
+				To:    to,
+			})
+		}
+	}
+	return changes
+}
+
+// stringField retorna o valor do campo como texto, ou vazio se ausente
+func stringField(doc map[string]interface{}, field string) string {
+	value, ok := doc[field]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	ticket := map[string]interface{}{
+		"ticket_id":      "TKT-001",
+		"current_status": "ABERTO",
+		"priority":       "ALTA",
+		"assigned_agent": map[string]interface{}{
+			"id":        float64(7),
+			"full_name": "Maria Silva",
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"status":         "ABERTO",
+		"priority":       "ALTA",
+		"assigned_agent": "Maria Silva",
+	}, snapshot(ticket))
+
+	// Sem responsável atribuído
+	assert.Equal(t, "", snapshot(map[string]interface{}{})["assigned_agent"])
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	previous := map[string]string{
+		"status":         "ABERTO",
+		"priority":       "ALTA",
+		"assigned_agent": "",
+	}
+
+	tests := []struct {
+		name     string
+		current  map[string]string
+		expected []dto.FieldChange
+	}{
+		{
+			name:     "No changes",
+			current:  previous,
+			expected: nil,
+		},
+		{
+			name: "Status and assignment changed",
+			current: map[string]string{
+				"status":         "EM ANDAMENTO",
+				"priority":       "ALTA",
+				"assigned_agent": "Maria Silva",
+			},
+			expected: []dto.FieldChange{
+				{Field: "status", From: "ABERTO", To: "EM ANDAMENTO"},
+				{Field: "assigned_agent", From: "", To: "Maria Silva"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, diffSnapshots(previous, tt.current))
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package searches
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultGlobalSearchLimit é quantos resultados de cada tipo a busca global retorna sem limit
+	defaultGlobalSearchLimit = 5
+	// globalSearchTimeout limita a espera pelas fontes da busca global
+	globalSearchTimeout = 10 * time.Second
+
+	searchTypeTickets = "tickets"
+	searchTypeUsers   = "users"
+)
+
+// GlobalSearch busca o termo nos tickets e nos usuários ao mesmo tempo
+// @Summary      Busca Global
+// @Description  Pesquisa o termo em paralelo nos tickets do Elasticsearch (mesma busca de GET /tickets/query, dentro do escopo do usuário) e, para administradores, no nome e no e-mail dos usuários.
+// @Description  Retorna os primeiros resultados de cada tipo e o total encontrado de cada um em counts. Um tipo cuja fonte falhar fica fora de counts e é listado em unavailable.
+// @Tags         search
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        q query string true "Termo pesquisado"
+// @Param        limit query int false "Resultados de cada tipo (máximo 20)" default(5)
+// @Success      200 {object} dto.SuccessResponse{data=dto.GlobalSearchResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Router       /search [get]
+func GlobalSearch(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params dto.GlobalSearchParams
+		if !middleware.BindQuery(c, &params, "Invalid search query") {
+			return
+		}
+		query := strings.TrimSpace(params.Query)
+		if query == "" {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid search query", "q must not be blank"))
+			return
+		}
+		limit := params.Limit
+		if limit == 0 {
+			limit = defaultGlobalSearchLimit
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), globalSearchTimeout)
+		defer cancel()
+
+		response := dto.GlobalSearchResponse{
+			Query:   query,
+			Counts:  map[string]int64{},
+			Tickets: []map[string]interface{}{},
+		}
+		var (
+			mu sync.Mutex
+			wg sync.WaitGroup
+		)
+		source := func(name string, load func(ctx context.Context) (int64, error)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				total, err := load(ctx)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					cfg.Logger.Warn(fmt.Sprintf("Global search source %s unavailable: %v", name, err))
+					response.Unavailable = append(response.Unavailable, name)
+					return
+				}
+				response.Counts[name] = total
+			}()
+		}
+
+		source(searchTypeTickets, func(ctx context.Context) (int64, error) {
+			result, err := cfg.ES.SearchTicketsBySomeWord(ctx, dto.SearchParams{Query: query, Page: 1, PageSize: limit})
+			if err != nil {
+				return 0, err
+			}
+			if result.Tickets != nil {
+				response.Tickets = result.Tickets
+			}
+			return result.Pagination.TotalRecords, nil
+		})
+
+		if middleware.GetCurrentRole(c) == utils.RoleAdmin {
+			response.Users = []dto.UserResponse{}
+			source(searchTypeUsers, func(ctx context.Context) (int64, error) {
+				users, total, err := cfg.SqlServer.SearchUsers(ctx, query, limit)
+				if err != nil {
+					return 0, err
+				}
+				response.Users = userResults(users)
+				return total, nil
+			})
+		}
+
+		wg.Wait()
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Search results retrieved successfully"))
+	}
+}
+
+// userResults converte os usuários encontrados para a resposta, sem o hash da senha
+func userResults(users []entities.User) []dto.UserResponse {
+	results := make([]dto.UserResponse, 0, len(users))
+	for _, user := range users {
+		results = append(results, dto.NewUserResponse(user))
+	}
+	return results
+}
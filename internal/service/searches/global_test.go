@@ -0,0 +1,52 @@
+package searches
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalSearch_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// As requisições inválidas são recusadas antes de consultar as fontes
+	router.GET("/search", GlobalSearch(nil))
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{name: "missing q", url: "/search", expectedStatus: http.StatusUnprocessableEntity},
+		{name: "blank q", url: "/search?q=%20%20", expectedStatus: http.StatusBadRequest},
+		{name: "limit above the maximum", url: "/search?q=boleto&limit=50", expectedStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestUserResultsOmitPasswordHash(t *testing.T) {
+	hash := "$2a$10$secret"
+	results := userResults([]entities.User{{Id: 3, Name: "Ana", Email: "ana@example.com", PasswordHash: &hash, IsActive: true}})
+
+	require.Len(t, results, 1)
+	assert.Equal(t, dto.UserResponse{Id: 3, Name: "Ana", Email: "ana@example.com", IsActive: true}, results[0])
+
+	data, err := json.Marshal(results)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), hash)
+}
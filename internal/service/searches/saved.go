@@ -0,0 +1,290 @@
+package searches
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSavedSearchesPerUser limita as buscas salvas de cada usuário
+const maxSavedSearchesPerUser = 50
+
+// ListSavedSearches lista as buscas salvas do usuário autenticado
+// @Summary      Minhas Buscas Salvas
+// @Description  Retorna as buscas de tickets salvas pelo usuário autenticado, com o layout do painel de cada uma, em ordem alfabética
+// @Tags         saved-searches
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.SavedSearchResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/saved-searches [get]
+func ListSavedSearches(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		searches, err := cfg.SqlServer.ListSavedSearches(c.Request.Context(), userId)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve saved searches")
+			return
+		}
+
+		response := make([]dto.SavedSearchResponse, 0, len(searches))
+		for i := range searches {
+			response = append(response, savedSearchResponse(&searches[i]))
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Saved searches retrieved successfully"))
+	}
+}
+
+// CreateSavedSearch salva uma busca
+// @Summary      Salvar Busca
+// @Description  Salva a consulta (mesma sintaxe do parâmetro q de GET /tickets/query) com um nome e, opcionalmente, o layout do painel em JSON. Cada usuário pode ter até 50 buscas.
+// @Tags         saved-searches
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        request body dto.SavedSearchRequest true "Busca"
+// @Success      201 {object} dto.SuccessResponse{data=dto.SavedSearchResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 409 {object} dto.ErrorResponse "Conflict - Nome já usado ou limite atingido"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/saved-searches [post]
+func CreateSavedSearch(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.SavedSearchRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+
+		userId, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		total, err := cfg.SqlServer.CountUserSavedSearches(c.Request.Context(), userId)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to create saved search")
+			return
+		}
+		if total >= maxSavedSearchesPerUser {
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(c, http.StatusConflict, "Conflict",
+				fmt.Sprintf("A user can keep at most %d saved searches", maxSavedSearchesPerUser), nil))
+			return
+		}
+
+		search := &entities.SavedSearch{
+			UserId:    userId,
+			CreatedAt: time.Now().UTC(),
+		}
+		applySavedSearchRequest(search, &req)
+
+		if err := cfg.SqlServer.CreateSavedSearch(c.Request.Context(), search); err != nil {
+			middleware.RespondError(c, err, "Failed to create saved search")
+			return
+		}
+
+		c.JSON(http.StatusCreated, dto.NewSuccessResponse(c, savedSearchResponse(search), "Saved search created successfully"))
+	}
+}
+
+// UpdateSavedSearch altera uma busca salva
+// @Summary      Atualizar Busca Salva
+// @Description  Substitui o nome, a consulta e o layout de uma busca salva do usuário autenticado
+// @Tags         saved-searches
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path int true "ID da busca"
+// @Param        request body dto.SavedSearchRequest true "Busca"
+// @Success      200 {object} dto.SuccessResponse{data=dto.SavedSearchResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 409 {object} dto.ErrorResponse "Conflict - Nome já usado"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/saved-searches/{id} [put]
+func UpdateSavedSearch(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := savedSearchID(c)
+		if !ok {
+			return
+		}
+
+		var req dto.SavedSearchRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+
+		userId, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		search, err := cfg.SqlServer.GetSavedSearch(c.Request.Context(), userId, id)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve saved search")
+			return
+		}
+
+		applySavedSearchRequest(search, &req)
+		if err := cfg.SqlServer.UpdateSavedSearch(c.Request.Context(), search); err != nil {
+			middleware.RespondError(c, err, "Failed to update saved search")
+			return
+		}
+
+		now := time.Now().UTC()
+		search.UpdatedAt = &now
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, savedSearchResponse(search), "Saved search updated successfully"))
+	}
+}
+
+// DeleteSavedSearch remove uma busca salva
+// @Summary      Remover Busca Salva
+// @Description  Remove uma busca salva do usuário autenticado
+// @Tags         saved-searches
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path int true "ID da busca"
+// @Success      200 {object} dto.SuccessResponse
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/saved-searches/{id} [delete]
+func DeleteSavedSearch(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := savedSearchID(c)
+		if !ok {
+			return
+		}
+
+		userId, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		if err := cfg.SqlServer.DeleteSavedSearch(c.Request.Context(), userId, id); err != nil {
+			middleware.RespondError(c, err, "Failed to delete saved search")
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, nil, "Saved search deleted successfully"))
+	}
+}
+
+// RunSavedSearch executa uma busca salva
+// @Summary      Executar Busca Salva
+// @Description  Executa a consulta salva da mesma forma que GET /tickets/query, com a paginação informada na chamada
+// @Tags         saved-searches
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path int true "ID da busca"
+// @Param        page      query     int     false "Page number" default(1)
+// @Param        page_size query     int     false "Number of items per page" default(50) maximum(100)
+// @Success      200 {object} dto.PaginatedResponse{data=[]dto.Ticket}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/me/saved-searches/{id}/run [get]
+func RunSavedSearch(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := savedSearchID(c)
+		if !ok {
+			return
+		}
+
+		var params dto.SearchParams
+		if !middleware.BindQuery(c, &params, "Error while searching tickets") {
+			return
+		}
+
+		userId, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		search, err := cfg.SqlServer.GetSavedSearch(c.Request.Context(), userId, id)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve saved search")
+			return
+		}
+		params.Query = search.Query
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		result, err := cfg.ES.SearchTicketsBySomeWord(ctx, params)
+		if err != nil {
+			middleware.RespondError(c, err, "Error while searching tickets")
+			return
+		}
+
+		response := dto.NewPaginatedResponse(c, result.Tickets, result.Pagination, "Saved search executed successfully")
+		response.Facets = result.Facets
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// applySavedSearchRequest copia os campos da requisição para a busca
+func applySavedSearchRequest(search *entities.SavedSearch, req *dto.SavedSearchRequest) {
+	search.Name = strings.TrimSpace(req.Name)
+	search.Query = strings.TrimSpace(req.Query)
+	search.Layout = nil
+	if len(req.Layout) > 0 && string(req.Layout) != "null" {
+		layout := string(req.Layout)
+		search.Layout = &layout
+	}
+}
+
+func savedSearchResponse(search *entities.SavedSearch) dto.SavedSearchResponse {
+	response := dto.SavedSearchResponse{
+		Id:        search.Id,
+		Name:      search.Name,
+		Query:     search.Query,
+		CreatedAt: search.CreatedAt,
+		UpdatedAt: search.UpdatedAt,
+	}
+	if search.Layout != nil {
+		response.Layout = json.RawMessage(*search.Layout)
+	}
+	return response
+}
+
+func savedSearchID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid saved search ID", nil))
+		return 0, false
+	}
+	return id, true
+}
+
+func currentUserID(c *gin.Context) (int, bool) {
+	userId, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated", nil))
+	}
+	return userId, ok
+}
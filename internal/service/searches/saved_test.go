@@ -0,0 +1,58 @@
+package searches
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySavedSearchRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout string
+		want   *string
+	}{
+		{name: "with layout", layout: `{"columns":["priority","status"]}`, want: strPtr(`{"columns":["priority","status"]}`)},
+		{name: "null layout clears it", layout: `null`, want: nil},
+		{name: "without layout", layout: ``, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := &entities.SavedSearch{Layout: strPtr(`{"old":true}`)}
+			applySavedSearchRequest(search, &dto.SavedSearchRequest{
+				Name:   "  Críticos  ",
+				Query:  " priority:Critical ",
+				Layout: json.RawMessage(tt.layout),
+			})
+
+			assert.Equal(t, "Críticos", search.Name)
+			assert.Equal(t, "priority:Critical", search.Query)
+			assert.Equal(t, tt.want, search.Layout)
+		})
+	}
+}
+
+func TestSavedSearchResponseKeepsLayoutAsJSON(t *testing.T) {
+	response := savedSearchResponse(&entities.SavedSearch{
+		Id:        7,
+		Name:      "Críticos",
+		Query:     "priority:Critical",
+		Layout:    strPtr(`{"columns":["priority"]}`),
+		CreatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"layout":{"columns":["priority"]}`)
+}
+
+func strPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,166 @@
+package synonyms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/service/audit"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxSynonymsFileSize é o tamanho máximo do arquivo enviado; o th_pt_BR.dat completo tem cerca de 2 MB
+	maxSynonymsFileSize = 16 << 20
+	// synonymsPreviewSize é a quantidade de regras exibidas na resposta
+	synonymsPreviewSize = 20
+)
+
+// UploadSynonyms troca os sinônimos do índice de tickets pelo arquivo enviado
+// @Summary      Atualizar Sinônimos da Busca
+// @Description  Converte o arquivo enviado (formato Solr ou thesaurus do OpenOffice/LibreOffice, detectado pelo conteúdo quando format não é informado) em regras de sinônimos,
+// @Description  descartando anotações, termos longos e regras com menos de dois termos, e as aplica ao analisador de busca do índice support_tickets.
+// @Description  O índice é fechado e reaberto durante a troca, então as buscas falham por alguns segundos. Os sinônimos valem na busca, sem reindex.
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        file formData file true "Arquivo de sinônimos ou thesaurus"
+// @Param        format formData string false "Formato do arquivo" Enums(solr, thesaurus)
+// @Success      200 {object} dto.SuccessResponse{data=dto.SynonymSetResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 413 {object} dto.ErrorResponse "File too large"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/search/synonyms [post]
+func UploadSynonyms(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSynonymsFileSize)
+
+		header, err := c.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, dto.NewErrorResponse(c, http.StatusRequestEntityTooLarge, "Request Entity Too Large", "Synonyms file too large", nil))
+				return
+			}
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "The synonyms file is required in the file field", err.Error()))
+			return
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Failed to read synonyms file", err.Error()))
+			return
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Failed to read synonyms file", err.Error()))
+			return
+		}
+
+		conversion, err := ConvertSynonyms(data, strings.ToLower(strings.TrimSpace(c.PostForm("format"))))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid synonyms file", err.Error()))
+			return
+		}
+
+		previous, _, err := cfg.ES.ActiveSynonyms(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to read the active synonyms", err.Error()))
+			return
+		}
+
+		set := newSynonymSet(header.Filename, conversion)
+		if userID, ok := middleware.GetCurrentUserID(c); ok {
+			set.ActivatedBy = &userID
+		}
+
+		if err := cfg.ES.UpdateSynonyms(c.Request.Context(), set, conversion.Rules); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to update synonyms", err.Error()))
+			return
+		}
+
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntitySynonyms, elsearch.TicketsIndex, previous, set)
+
+		response := synonymSetResponse(set, conversion.Rules)
+		response.Skipped = &conversion.Skipped
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Synonyms updated successfully"))
+	}
+}
+
+// GetSynonyms retorna o conjunto de sinônimos ativo
+// @Summary      Sinônimos Ativos da Busca
+// @Description  Retorna o conjunto de sinônimos enviado por último para o índice support_tickets, com as primeiras regras.
+// @Description  404 quando o índice usa os sinônimos padrão do mapping ou os de ELASTICSEARCH_SYNONYMS_FILE.
+// @Tags         admin
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=dto.SynonymSetResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/search/synonyms [get]
+func GetSynonyms(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		set, rules, err := cfg.ES.ActiveSynonyms(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to read the active synonyms", err.Error()))
+			return
+		}
+		if set == nil {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "No synonym set was uploaded", nil))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, synonymSetResponse(*set, rules), "Synonyms retrieved successfully"))
+	}
+}
+
+// newSynonymSet identifica o conjunto pelo conteúdo: o mesmo arquivo convertido gera o mesmo ID
+func newSynonymSet(source string, conversion Conversion) elsearch.SynonymSet {
+	sum := sha256.Sum256([]byte(strings.Join(conversion.Rules, "\n")))
+	checksum := hex.EncodeToString(sum[:])
+
+	return elsearch.SynonymSet{
+		ID:          checksum[:12],
+		Source:      source,
+		Format:      conversion.Format,
+		Rules:       len(conversion.Rules),
+		Checksum:    checksum,
+		ActivatedAt: time.Now().UTC(),
+	}
+}
+
+// synonymSetResponse converte o conjunto para a resposta, com as primeiras regras
+func synonymSetResponse(set elsearch.SynonymSet, rules []string) dto.SynonymSetResponse {
+	preview := rules
+	if len(preview) > synonymsPreviewSize {
+		preview = preview[:synonymsPreviewSize]
+	}
+	if preview == nil {
+		preview = []string{}
+	}
+
+	return dto.SynonymSetResponse{
+		Id:          set.ID,
+		Source:      set.Source,
+		Format:      set.Format,
+		Rules:       set.Rules,
+		Checksum:    set.Checksum,
+		ActivatedBy: set.ActivatedBy,
+		ActivatedAt: set.ActivatedAt,
+		Preview:     preview,
+	}
+}
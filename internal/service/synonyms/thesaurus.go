@@ -0,0 +1,224 @@
+package synonyms
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formatos de arquivo aceitos na conversão
+const (
+	// FormatSolr é o formato de sinônimos do Solr/Elasticsearch: "a, b, c" ou "a, b => c", uma regra por linha
+	FormatSolr = "solr"
+	// FormatThesaurus é o thesaurus do OpenOffice/LibreOffice (th_pt_BR.dat): a codificação na
+	// primeira linha, depois "palavra|N" seguida de N linhas "(classe)|sinônimo|sinônimo..."
+	FormatThesaurus = "thesaurus"
+)
+
+const (
+	// maxTermsPerRule limita os termos de uma regra; palavras muito genéricas do thesaurus
+	// trazem dezenas de sinônimos e poluem a busca
+	maxTermsPerRule = 10
+	// maxTermLength descarta definições e frases longas que aparecem no lugar de sinônimos
+	maxTermLength = 40
+)
+
+// ErrNoRules é retornado quando nenhuma regra sobra depois dos filtros
+var ErrNoRules = errors.New("no synonym rules left after conversion")
+
+var (
+	// annotationPattern remove anotações como "(termo genérico)" ou "(antônimo)"
+	annotationPattern = regexp.MustCompile(`\([^)]*\)`)
+	// spacesPattern junta espaços repetidos
+	spacesPattern = regexp.MustCompile(`\s+`)
+)
+
+// Conversion é o resultado da conversão de um arquivo em regras de sinônimos
+type Conversion struct {
+	Format  string
+	Rules   []string
+	Skipped int
+}
+
+// ConvertSynonyms converte o arquivo em regras no formato Solr, passando cada regra pelos filtros:
+// minúsculas, sem anotações, termos curtos e sem repetição, no máximo maxTermsPerRule termos por regra
+// e ao menos dois termos. format vazio detecta o formato pelo conteúdo.
+func ConvertSynonyms(data []byte, format string) (Conversion, error) {
+	if format == "" {
+		format = detectFormat(data)
+	}
+
+	var conversion Conversion
+	var err error
+	switch format {
+	case FormatSolr:
+		conversion, err = convertSolr(data)
+	case FormatThesaurus:
+		conversion, err = convertThesaurus(data)
+	default:
+		return Conversion{}, fmt.Errorf("unknown synonyms format %q, expected %s or %s", format, FormatSolr, FormatThesaurus)
+	}
+	if err != nil {
+		return Conversion{}, err
+	}
+	if len(conversion.Rules) == 0 {
+		return conversion, ErrNoRules
+	}
+	return conversion, nil
+}
+
+// detectFormat reconhece o thesaurus pela linha de codificação seguida de "palavra|N"
+func detectFormat(data []byte) string {
+	lines := strings.SplitN(string(data), "\n", 3)
+	if len(lines) >= 2 && !strings.Contains(lines[0], "|") && isThesaurusHeader(lines[1]) {
+		return FormatThesaurus
+	}
+	return FormatSolr
+}
+
+// isThesaurusHeader indica se a linha é o cabeçalho de uma entrada do thesaurus ("palavra|N")
+func isThesaurusHeader(line string) bool {
+	_, count, ok := strings.Cut(strings.TrimSpace(line), "|")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(count)
+	return err == nil
+}
+
+// convertSolr valida e normaliza um arquivo já no formato Solr
+func convertSolr(data []byte) (Conversion, error) {
+	text, err := decodeText(data, "UTF-8")
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	conversion := Conversion{Format: FormatSolr}
+	seen := map[string]bool{}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule string
+		if left, right, explicit := strings.Cut(line, "=>"); explicit {
+			from, to := normalizeTerms(strings.Split(left, ",")), normalizeTerms(strings.Split(right, ","))
+			if len(from) > 0 && len(to) > 0 {
+				rule = strings.Join(from, ", ") + " => " + strings.Join(to, ", ")
+			}
+		} else if terms := normalizeTerms(strings.Split(line, ",")); len(terms) >= 2 {
+			rule = strings.Join(terms, ", ")
+		}
+
+		if rule == "" || seen[rule] {
+			conversion.Skipped++
+			continue
+		}
+		seen[rule] = true
+		conversion.Rules = append(conversion.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return Conversion{}, fmt.Errorf("failed to read synonyms: %w", err)
+	}
+
+	return conversion, nil
+}
+
+// convertThesaurus transforma cada entrada do thesaurus em uma regra de equivalência com a palavra
+// e os sinônimos de todas as suas classes
+func convertThesaurus(data []byte) (Conversion, error) {
+	encoding, _, _ := bytes.Cut(data, []byte("\n"))
+	text, err := decodeText(data, strings.TrimSpace(string(encoding)))
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	conversion := Conversion{Format: FormatThesaurus}
+	seen := map[string]bool{}
+
+	// A primeira linha é a codificação
+	for i := 1; i < len(lines); i++ {
+		header := strings.TrimSpace(lines[i])
+		if header == "" {
+			continue
+		}
+
+		word, countText, ok := strings.Cut(header, "|")
+		count, err := strconv.Atoi(countText)
+		if !ok || err != nil || count < 0 {
+			return Conversion{}, fmt.Errorf("invalid thesaurus entry at line %d: %q", i+1, header)
+		}
+		if i+count >= len(lines) {
+			return Conversion{}, fmt.Errorf("thesaurus entry %q at line %d expects %d meanings", word, i+1, count)
+		}
+
+		terms := []string{word}
+		for _, meaning := range lines[i+1 : i+1+count] {
+			// O primeiro campo é a classe gramatical, ex.: "(Substantivo)"
+			if fields := strings.Split(meaning, "|"); len(fields) > 1 {
+				terms = append(terms, fields[1:]...)
+			}
+		}
+		i += count
+
+		terms = normalizeTerms(terms)
+		rule := strings.Join(terms, ", ")
+		if len(terms) < 2 || seen[rule] {
+			conversion.Skipped++
+			continue
+		}
+		seen[rule] = true
+		conversion.Rules = append(conversion.Rules, rule)
+	}
+
+	return conversion, nil
+}
+
+// normalizeTerms aplica os filtros aos termos de uma regra, mantendo a ordem do arquivo
+func normalizeTerms(terms []string) []string {
+	normalized := make([]string, 0, len(terms))
+	seen := map[string]bool{}
+	for _, term := range terms {
+		term = annotationPattern.ReplaceAllString(term, "")
+		term = spacesPattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(term)), " ")
+		// Vírgula e => separam termos no formato Solr e não podem aparecer dentro de um termo
+		if term == "" || seen[term] || utf8.RuneCountInString(term) > maxTermLength ||
+			strings.Contains(term, ",") || strings.Contains(term, "=>") || strings.Contains(term, "#") {
+			continue
+		}
+		seen[term] = true
+		normalized = append(normalized, term)
+		if len(normalized) == maxTermsPerRule {
+			break
+		}
+	}
+	return normalized
+}
+
+// decodeText converte o conteúdo para UTF-8. Os thesaurus antigos do OpenOffice são ISO-8859-1,
+// em que cada byte é o próprio code point.
+func decodeText(data []byte, encoding string) (string, error) {
+	switch strings.ToUpper(strings.ReplaceAll(encoding, "-", "")) {
+	case "ISO88591", "LATIN1":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes), nil
+	default:
+		data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+		if !utf8.Valid(data) {
+			return "", errors.New("synonyms file is not valid UTF-8")
+		}
+		return string(data), nil
+	}
+}
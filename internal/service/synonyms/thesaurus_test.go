@@ -0,0 +1,117 @@
+package synonyms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertSynonyms(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		format          string
+		expectError     error
+		expectAnyError  bool
+		expectedFormat  string
+		expectedRules   []string
+		expectedSkipped int
+	}{
+		{
+			name:           "Success - Solr rules are normalized",
+			data:           "# comentário\n\nImpressora,  Printer , impressora\nNF, nota   fiscal => nota fiscal\n",
+			expectedFormat: FormatSolr,
+			expectedRules:  []string{"impressora, printer", "nf, nota fiscal => nota fiscal"},
+		},
+		{
+			name:            "Success - Solr duplicates and single terms are skipped",
+			data:            "senha, password\nSENHA, Password\nsozinho\n",
+			format:          FormatSolr,
+			expectedFormat:  FormatSolr,
+			expectedRules:   []string{"senha, password"},
+			expectedSkipped: 2,
+		},
+		{
+			name: "Success - Thesaurus is detected and converted",
+			data: "UTF-8\n" +
+				"computador|2\n" +
+				"(Substantivo)|PC|micro (termo genérico)\n" +
+				"(Substantivo)|máquina|pc\n" +
+				"isolado|1\n" +
+				"(Adjetivo)|\n",
+			expectedFormat:  FormatThesaurus,
+			expectedRules:   []string{"computador, pc, micro, máquina"},
+			expectedSkipped: 1,
+		},
+		{
+			name:           "Success - ISO-8859-1 thesaurus",
+			data:           "ISO8859-1\nlento|1\n(Adjetivo)|vagaroso|devagar\xe3o\n",
+			format:         FormatThesaurus,
+			expectedFormat: FormatThesaurus,
+			expectedRules:  []string{"lento, vagaroso, devagarão"},
+		},
+		{
+			name:           "Success - Long terms and separators are dropped",
+			data:           "rede, " + strings.Repeat("x", maxTermLength+1) + ", network, a#b\n",
+			expectedFormat: FormatSolr,
+			expectedRules:  []string{"rede, network"},
+		},
+		{
+			name:        "Error - No rules left",
+			data:        "# só comentários\nsozinho\n",
+			expectError: ErrNoRules,
+		},
+		{
+			name:           "Error - Truncated thesaurus entry",
+			data:           "UTF-8\nlento|3\n(Adjetivo)|vagaroso\n",
+			format:         FormatThesaurus,
+			expectAnyError: true,
+		},
+		{
+			name:           "Error - Invalid UTF-8",
+			data:           "lento, devagar\xe3o\n",
+			format:         FormatSolr,
+			expectAnyError: true,
+		},
+		{
+			name:           "Error - Unknown format",
+			data:           "a, b\n",
+			format:         "wordnet",
+			expectAnyError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversion, err := ConvertSynonyms([]byte(tt.data), tt.format)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+			if tt.expectAnyError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFormat, conversion.Format)
+			assert.Equal(t, tt.expectedRules, conversion.Rules)
+			assert.Equal(t, tt.expectedSkipped, conversion.Skipped)
+		})
+	}
+}
+
+func TestNormalizeTermsLimit(t *testing.T) {
+	terms := make([]string, 0, maxTermsPerRule+5)
+	for i := 0; i < maxTermsPerRule+5; i++ {
+		terms = append(terms, strings.Repeat("a", i+1))
+	}
+
+	normalized := normalizeTerms(terms)
+
+	assert.Len(t, normalized, maxTermsPerRule)
+	assert.Equal(t, "a", normalized[0])
+}
@@ -0,0 +1,107 @@
+package tags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"orderstreamrest/internal/service/jobs"
+	"sort"
+)
+
+// NormalizationJobName é a rotina (e a tarefa da fila) que reescreve as tags dos tickets no Elasticsearch
+// com os nomes canônicos
+const NormalizationJobName = "tag_normalization"
+
+func init() {
+	jobs.Register(jobs.Job{
+		Name:        NormalizationJobName,
+		Description: "Rewrites ticket tags in Elasticsearch to their canonical names, merging spellings that differ only in case, accents, spacing or registered aliases",
+		Defaults: entities.ScheduledJob{
+			Enabled:         true,
+			IntervalMinutes: 1440,
+		},
+		Run: func(ctx context.Context, cfg *config.App, _ entities.ScheduledJob) (int64, error) {
+			return normalizeTicketTags(ctx, cfg)
+		},
+	})
+
+	jobs.RegisterTask(jobs.Task{
+		Type:        NormalizationJobName,
+		Description: "Rewrites ticket tags in Elasticsearch after a tag merge",
+		Run: func(ctx context.Context, cfg *config.App, _ json.RawMessage) (interface{}, error) {
+			updated, err := normalizeTicketTags(ctx, cfg)
+			if err != nil {
+				return nil, err
+			}
+			return normalizationResult{UpdatedTickets: updated}, nil
+		},
+	})
+}
+
+// normalizationResult é o resultado gravado na tarefa da fila
+type normalizationResult struct {
+	UpdatedTickets int64 `json:"updatedTickets"`
+}
+
+// normalizeTicketTags troca, nos tickets, cada grafia de tag pelo nome canônico e retorna a quantidade de
+// tickets alterados
+func normalizeTicketTags(ctx context.Context, cfg *config.App) (int64, error) {
+	searchCtx := elsearch.WithScope(ctx, elsearch.UnrestrictedScope())
+	ticketTags, err := cfg.ES.ListTicketTags(searchCtx)
+	if err != nil {
+		return 0, err
+	}
+
+	catalog, err := loadCatalog(ctx, cfg, ticketTags)
+	if err != nil {
+		return 0, err
+	}
+
+	names := make([]string, 0, len(ticketTags))
+	for _, tag := range ticketTags {
+		names = append(names, tag.Name)
+	}
+	renames := catalog.Renames(names)
+	if len(renames) == 0 {
+		return 0, nil
+	}
+
+	cfg.Logger.Info(fmt.Sprintf("Normalizing %d ticket tag spellings", len(renames)))
+	return cfg.ES.RenameTicketTags(searchCtx, renames)
+}
+
+// loadCatalog monta o catálogo com os aliases, as tags do DW (as com mais tickets primeiro) e as grafias
+// do índice, da mais usada para a menos usada
+func loadCatalog(ctx context.Context, cfg *config.App, ticketTags []elsearch.TicketTag) (*Catalog, error) {
+	aliases, err := cfg.SqlServer.ListTagAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := cfg.SqlServer.ListTagTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCatalog(aliases, catalogNames(totals, ticketTags)...), nil
+}
+
+// catalogNames ordena os nomes em ordem de preferência para o catálogo: as tags do DW com mais tickets
+// primeiro e depois as grafias do índice, que já vêm da mais usada para a menos usada
+func catalogNames(totals []sqlserver.TagTotal, ticketTags []elsearch.TicketTag) []string {
+	sorted := make([]sqlserver.TagTotal, len(totals))
+	copy(sorted, totals)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Total > sorted[j].Total })
+
+	names := make([]string, 0, len(sorted)+len(ticketTags))
+	for _, total := range sorted {
+		names = append(names, total.Name)
+	}
+	for _, tag := range ticketTags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
@@ -0,0 +1,73 @@
+package tags
+
+import (
+	"orderstreamrest/internal/models/entities"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Clean remove os espaços das pontas e troca sequências de espaços por um só, preservando a grafia
+func Clean(tag string) string {
+	return strings.Join(strings.Fields(tag), " ")
+}
+
+// Key é a chave de comparação da tag: as grafias com a mesma chave são a mesma tag.
+// Além de Clean, passa para minúsculas e remove os acentos ("  Cartão de  Crédito" vira "cartao de credito").
+func Key(tag string) string {
+	decomposed := norm.NFD.String(strings.ToLower(Clean(tag)))
+
+	var key strings.Builder
+	key.Grow(len(decomposed))
+	for _, r := range decomposed {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		key.WriteRune(r)
+	}
+	return key.String()
+}
+
+// Catalog resolve o nome canônico de cada grafia de tag
+type Catalog struct {
+	canonical map[string]string
+}
+
+// NewCatalog monta o catálogo. Os aliases têm prioridade; depois, para cada chave, vale o primeiro nome
+// de names, que deve vir em ordem de preferência (ex.: as tags do DW e depois as grafias mais usadas no índice).
+func NewCatalog(aliases []entities.TagAlias, names ...string) *Catalog {
+	catalog := &Catalog{canonical: make(map[string]string, len(aliases)+len(names))}
+	for _, alias := range aliases {
+		catalog.canonical[Key(alias.Alias)] = Clean(alias.TagName)
+	}
+	for _, name := range names {
+		key := Key(name)
+		if key == "" {
+			continue
+		}
+		if _, exists := catalog.canonical[key]; !exists {
+			catalog.canonical[key] = Clean(name)
+		}
+	}
+	return catalog
+}
+
+// Canonical retorna o nome canônico da tag; tags fora do catálogo voltam apenas com os espaços corrigidos
+func (c *Catalog) Canonical(tag string) string {
+	if name, ok := c.canonical[Key(tag)]; ok {
+		return name
+	}
+	return Clean(tag)
+}
+
+// Renames retorna as grafias de tags que precisam ser trocadas, com o nome canônico de cada uma
+func (c *Catalog) Renames(tags []string) map[string]string {
+	renames := make(map[string]string)
+	for _, tag := range tags {
+		if canonical := c.Canonical(tag); canonical != "" && canonical != tag {
+			renames[tag] = canonical
+		}
+	}
+	return renames
+}
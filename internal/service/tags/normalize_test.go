@@ -0,0 +1,66 @@
+package tags
+
+import (
+	"orderstreamrest/internal/models/entities"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{tag: "Boleto", expected: "boleto"},
+		{tag: "  Cartão de   Crédito ", expected: "cartao de credito"},
+		{tag: "AÇÃO\tURGENTE", expected: "acao urgente"},
+		{tag: "   ", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Key(tt.tag))
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	aliases := []entities.TagAlias{
+		{Alias: "boletos", TagName: "Boleto"},
+		{Alias: "bolto", TagName: "Boleto"},
+	}
+	catalog := NewCatalog(aliases, "Boleto", "Cartão de Crédito", "cartao de credito", "boletos", "Pix")
+
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{tag: "Boleto", expected: "Boleto"},
+		{tag: "BOLETO", expected: "Boleto"},
+		{tag: "Boletos", expected: "Boleto"},
+		{tag: "bolto", expected: "Boleto"},
+		{tag: "cartao de credito", expected: "Cartão de Crédito"},
+		{tag: "PIX ", expected: "Pix"},
+		{tag: "  Nova   tag ", expected: "Nova tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			assert.Equal(t, tt.expected, catalog.Canonical(tt.tag))
+		})
+	}
+}
+
+func TestCatalog_Renames(t *testing.T) {
+	catalog := NewCatalog([]entities.TagAlias{{Alias: "boletos", TagName: "Boleto"}}, "Boleto", "Pix")
+
+	renames := catalog.Renames([]string{"Boleto", "boletos", "BOLETO", "Pix", "pix", "Nova tag", "Nova  tag"})
+
+	assert.Equal(t, map[string]string{
+		"boletos":   "Boleto",
+		"BOLETO":    "Boleto",
+		"pix":       "Pix",
+		"Nova  tag": "Nova tag",
+	}, renames)
+}
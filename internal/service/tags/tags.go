@@ -0,0 +1,201 @@
+package tags
+
+import (
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"orderstreamrest/internal/service/audit"
+	"orderstreamrest/internal/service/jobs"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTagLength é o tamanho da coluna Name de dbo.Dim_Tags
+const maxTagLength = 60
+
+// ListTags lista o catálogo de tags
+// @Summary      Catálogo de Tags
+// @Description  Retorna as tags canônicas de dbo.Dim_Tags com o total de tickets e as grafias (aliases) unificadas em cada uma, da mais usada para a menos usada.
+// @Description  Tags unificadas em outra não aparecem na lista.
+// @Tags         admin
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.TagResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/tags [get]
+func ListTags(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		totals, err := cfg.SqlServer.ListTagTotals(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to list tags", err.Error()))
+			return
+		}
+		aliases, err := cfg.SqlServer.ListTagAliases(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to list tags", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, tagCatalog(totals, aliases), "Tags retrieved successfully"))
+	}
+}
+
+// MergeTags unifica tags em uma tag canônica
+// @Summary      Unificar Tags
+// @Description  Unifica as tags de from na tag to: no DW os tickets passam para to (criada renomeando a primeira tag de from, quando ainda não existe) e as grafias de from viram aliases de to.
+// @Description  Com from igual a to, exceto por maiúsculas e acentos, a tag é apenas renomeada. Os nomes são comparados sem diferenciar maiúsculas, acentos e espaços.
+// @Description  O cache de métricas é invalidado e os tickets no Elasticsearch são reescritos pela tarefa tag_normalization, acompanhada em GET /admin/jobs/queue/{id}.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        request body dto.MergeTagsRequest true "Tags unificadas e nome canônico"
+// @Success      200 {object} dto.SuccessResponse{data=dto.MergeTagsResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /admin/tags/merge [post]
+func MergeTags(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.MergeTagsRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+
+		merge, err := buildMerge(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid tag merge", err.Error()))
+			return
+		}
+
+		var userID *int
+		if id, ok := middleware.GetCurrentUserID(c); ok {
+			userID = &id
+			merge.CreatedBy = userID
+		}
+
+		result, err := cfg.SqlServer.MergeTags(c.Request.Context(), merge)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to merge tags", err.Error()))
+			return
+		}
+
+		// Falhas aqui não desfazem a unificação: o cache expira pelo TTL e a rotina diária reescreve os tickets
+		if _, err := cfg.Metrics.Invalidate(c.Request.Context()); err != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Failed to invalidate metrics cache after tag merge: %v", err))
+		}
+
+		response := dto.MergeTagsResponse{
+			Tag:          merge.Target,
+			TagKey:       result.TargetKey,
+			Renamed:      result.Renamed,
+			MergedTags:   result.MergedTags,
+			MovedTickets: result.MovedTickets,
+			Aliases:      merge.Aliases,
+		}
+		if job, err := jobs.Enqueue(c.Request.Context(), cfg, NormalizationJobName, nil, userID); err != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Failed to enqueue tag normalization after merging into %s: %v", merge.Target, err))
+		} else {
+			response.RewriteJobId = &job.Id
+		}
+
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntityTag, strconv.FormatInt(result.TargetKey, 10), gin.H{"tags": req.From}, response)
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Tags merged successfully"))
+	}
+}
+
+// buildMerge valida a requisição e monta a unificação: os nomes de from são limpos e deduplicados, e as
+// chaves diferentes da chave de to viram aliases
+func buildMerge(req dto.MergeTagsRequest) (sqlserver.TagMerge, error) {
+	target := Clean(req.To)
+	if target == "" {
+		return sqlserver.TagMerge{}, fmt.Errorf("to must not be blank")
+	}
+	if len([]rune(target)) > maxTagLength {
+		return sqlserver.TagMerge{}, fmt.Errorf("to must have at most %d characters", maxTagLength)
+	}
+
+	merge := sqlserver.TagMerge{Target: target, TargetAlias: Key(target), Aliases: []string{}}
+	seenSources := map[string]bool{}
+	seenAliases := map[string]bool{merge.TargetAlias: true}
+	for _, tag := range req.From {
+		source := Clean(tag)
+		if source == "" {
+			return sqlserver.TagMerge{}, fmt.Errorf("from must not contain blank tags")
+		}
+		if len([]rune(source)) > maxTagLength {
+			return sqlserver.TagMerge{}, fmt.Errorf("tags in from must have at most %d characters", maxTagLength)
+		}
+
+		if !seenSources[source] && source != target {
+			seenSources[source] = true
+			merge.Sources = append(merge.Sources, source)
+		}
+		if key := Key(source); !seenAliases[key] {
+			seenAliases[key] = true
+			merge.Aliases = append(merge.Aliases, key)
+		}
+	}
+
+	if len(merge.Sources) == 0 {
+		return sqlserver.TagMerge{}, fmt.Errorf("from must contain at least one tag other than to")
+	}
+	return merge, nil
+}
+
+// tagCatalog agrupa as tags do DW pelo nome canônico: tags cuja chave é alias de outra tag não aparecem,
+// e tags canônicas que só existem nos aliases aparecem sem tickets
+func tagCatalog(totals []sqlserver.TagTotal, aliases []entities.TagAlias) []dto.TagResponse {
+	aliasOf := make(map[string]string, len(aliases))
+	for _, alias := range aliases {
+		aliasOf[alias.Alias] = alias.TagName
+	}
+
+	byName := map[string]*dto.TagResponse{}
+	catalog := make([]*dto.TagResponse, 0, len(totals))
+	for _, total := range totals {
+		if canonical, ok := aliasOf[Key(total.Name)]; ok && canonical != total.Name {
+			continue
+		}
+		if tag, ok := byName[total.Name]; ok {
+			tag.Tickets += total.Total
+			continue
+		}
+		tag := &dto.TagResponse{TagKey: total.TagKey, Name: total.Name, Tickets: total.Total, Aliases: []string{}}
+		byName[total.Name] = tag
+		catalog = append(catalog, tag)
+	}
+
+	for _, alias := range aliases {
+		tag, ok := byName[alias.TagName]
+		if !ok {
+			tag = &dto.TagResponse{Name: alias.TagName, Aliases: []string{}}
+			byName[alias.TagName] = tag
+			catalog = append(catalog, tag)
+		}
+		tag.Aliases = append(tag.Aliases, alias.Alias)
+	}
+
+	sort.SliceStable(catalog, func(i, j int) bool {
+		if catalog[i].Tickets != catalog[j].Tickets {
+			return catalog[i].Tickets > catalog[j].Tickets
+		}
+		return catalog[i].Name < catalog[j].Name
+	})
+
+	response := make([]dto.TagResponse, 0, len(catalog))
+	for _, tag := range catalog {
+		response = append(response, *tag)
+	}
+	return response
+}
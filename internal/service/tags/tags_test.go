@@ -0,0 +1,105 @@
+package tags
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           dto.MergeTagsRequest
+		expected      sqlserver.TagMerge
+		expectedError string
+	}{
+		{
+			name: "spellings become aliases of the target",
+			req:  dto.MergeTagsRequest{From: []string{"boletos", " Boletos ", "BOLETO", "Boleto"}, To: " Boleto"},
+			expected: sqlserver.TagMerge{
+				Sources:     []string{"boletos", "Boletos", "BOLETO"},
+				Aliases:     []string{"boletos"},
+				Target:      "Boleto",
+				TargetAlias: "boleto",
+			},
+		},
+		{
+			name: "case only rename",
+			req:  dto.MergeTagsRequest{From: []string{"cartao de credito"}, To: "Cartão de Crédito"},
+			expected: sqlserver.TagMerge{
+				Sources:     []string{"cartao de credito"},
+				Aliases:     []string{},
+				Target:      "Cartão de Crédito",
+				TargetAlias: "cartao de credito",
+			},
+		},
+		{
+			name:          "blank target",
+			req:           dto.MergeTagsRequest{From: []string{"boletos"}, To: "  "},
+			expectedError: "to must not be blank",
+		},
+		{
+			name:          "blank source",
+			req:           dto.MergeTagsRequest{From: []string{"boletos", " "}, To: "Boleto"},
+			expectedError: "from must not contain blank tags",
+		},
+		{
+			name:          "target too long",
+			req:           dto.MergeTagsRequest{From: []string{"boletos"}, To: strings.Repeat("a", 61)},
+			expectedError: "to must have at most 60 characters",
+		},
+		{
+			name:          "nothing to merge",
+			req:           dto.MergeTagsRequest{From: []string{"Boleto", " Boleto "}, To: "Boleto"},
+			expectedError: "from must contain at least one tag other than to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merge, err := buildMerge(tt.req)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, merge)
+		})
+	}
+}
+
+func TestTagCatalog(t *testing.T) {
+	totals := []sqlserver.TagTotal{
+		{Dim_Tags: entities.Dim_Tags{TagKey: 1, Name: "Boleto"}, Total: 30},
+		{Dim_Tags: entities.Dim_Tags{TagKey: 2, Name: "boletos"}, Total: 0},
+		{Dim_Tags: entities.Dim_Tags{TagKey: 3, Name: "Pix"}, Total: 50},
+	}
+	aliases := []entities.TagAlias{
+		{Alias: "boletos", TagName: "Boleto"},
+		{Alias: "transferencia", TagName: "Transferência"},
+	}
+
+	assert.Equal(t, []dto.TagResponse{
+		{TagKey: 3, Name: "Pix", Tickets: 50, Aliases: []string{}},
+		{TagKey: 1, Name: "Boleto", Tickets: 30, Aliases: []string{"boletos"}},
+		{Name: "Transferência", Aliases: []string{"transferencia"}},
+	}, tagCatalog(totals, aliases))
+}
+
+func TestCatalogNames(t *testing.T) {
+	totals := []sqlserver.TagTotal{
+		{Dim_Tags: entities.Dim_Tags{Name: "boleto"}, Total: 2},
+		{Dim_Tags: entities.Dim_Tags{Name: "Boleto"}, Total: 40},
+	}
+	ticketTags := []elsearch.TicketTag{{Name: "BOLETO", Tickets: 90}, {Name: "pix", Tickets: 5}}
+
+	names := catalogNames(totals, ticketTags)
+	assert.Equal(t, []string{"Boleto", "boleto", "BOLETO", "pix"}, names)
+	assert.Equal(t, "Boleto", NewCatalog(nil, names...).Canonical("BOLETO"), "DW tags win over index spellings")
+}
@@ -0,0 +1,84 @@
+package tickets
+
+import (
+	"context"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTicketAttachments handles the GET /tickets/:id/attachments endpoint
+// @Summary      List ticket attachments
+// @Description  Returns the metadata of the ticket attachments (id, filename, mime type, category, size and upload date), in upload order. Storage paths are not exposed. Use has_attachments and attachment_type on /tickets/query to find tickets by their attachments.
+// @Tags         tickets
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id   path      string  true  "Ticket ID"
+// @Success      200  {object}  dto.SuccessResponse{data=dto.TicketAttachmentsResponse}
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets/{id}/attachments [get]
+func ListTicketAttachments(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		document, _, err := cfg.ES.GetTicketDocument(ctx, c.Param("id"))
+		if err != nil {
+			middleware.RespondError(c, err, "Error while fetching ticket attachments")
+			return
+		}
+
+		attachments := ticketAttachments(document)
+		response := dto.TicketAttachmentsResponse{
+			TicketId:    documentString(document["ticket_id"]),
+			Count:       len(attachments),
+			Attachments: attachments,
+		}
+		for _, attachment := range attachments {
+			if attachment.SizeBytes != nil {
+				response.TotalSizeBytes += *attachment.SizeBytes
+			}
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Ticket attachments retrieved successfully"))
+	}
+}
+
+// ticketAttachments converte os anexos do documento do ticket, ignorando itens que não são objetos
+func ticketAttachments(document map[string]interface{}) []dto.TicketAttachment {
+	items, _ := document["attachments"].([]interface{})
+	attachments := make([]dto.TicketAttachment, 0, len(items))
+	for _, item := range items {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		mimeType := documentString(entry["mime_type"])
+		attachment := dto.TicketAttachment{
+			Id:       documentString(entry["id"]),
+			Filename: documentString(entry["filename"]),
+			MimeType: mimeType,
+			Category: elsearch.AttachmentCategory(mimeType),
+		}
+		if size := documentNumber(entry["size_bytes"]); size != nil && *size >= 0 {
+			bytes := int64(*size)
+			attachment.SizeBytes = &bytes
+		}
+		if uploadedAt, ok := documentTime(entry["uploaded_at"]); ok {
+			formatted := uploadedAt.Format(ticketDateLayouts[0])
+			attachment.UploadedAt = &formatted
+		}
+
+		attachments = append(attachments, attachment)
+	}
+	return attachments
+}
@@ -0,0 +1,46 @@
+package tickets
+
+import (
+	"orderstreamrest/internal/models/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketAttachments(t *testing.T) {
+	size := int64(245760)
+	uploadedAt := "2025-06-01 08:05:00"
+
+	tests := []struct {
+		name     string
+		document map[string]interface{}
+		expected []dto.TicketAttachment
+	}{
+		{name: "no attachments", document: map[string]interface{}{}, expected: []dto.TicketAttachment{}},
+		{
+			name: "typed metadata without storage path",
+			document: map[string]interface{}{"attachments": []interface{}{
+				map[string]interface{}{
+					"id":           "ATT-1",
+					"filename":     "erro-boleto.png",
+					"mime_type":    "image/png",
+					"size_bytes":   float64(245760),
+					"storage_path": "s3://bucket/ATT-1",
+					"uploaded_at":  "2025-06-01 08:05:00",
+				},
+				"not an object",
+				map[string]interface{}{"id": float64(2), "filename": "dump.bin", "size_bytes": "-1", "uploaded_at": "yesterday"},
+			}},
+			expected: []dto.TicketAttachment{
+				{Id: "ATT-1", Filename: "erro-boleto.png", MimeType: "image/png", Category: "image", SizeBytes: &size, UploadedAt: &uploadedAt},
+				{Id: "2", Filename: "dump.bin", Category: "other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ticketAttachments(tt.document))
+		})
+	}
+}
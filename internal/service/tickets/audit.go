@@ -0,0 +1,208 @@
+package tickets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditFilterDateLayout = "2006-01-02"
+
+	defaultAuditPageSize = 20
+	maxAuditPageSize     = 100
+)
+
+// ListTicketAudit handles the GET /tickets/:id/audit endpoint
+// @Summary      List ticket audit trail
+// @Description  Returns the audit_logs entries of the ticket, newest first. Ticket updates append one entry per changed field (priority, status, assignee, category, subcategory, product, channel, SLA plan) with the old and new values and the user who made the change.
+// @Tags         tickets
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id        path   string  true   "Ticket ID"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size (default 20, max 100)"
+// @Success      200  {object}  dto.PaginatedResponse{data=[]dto.TicketAuditEntry}
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets/{id}/audit [get]
+func ListTicketAudit(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page := middleware.ParsePage(c, defaultAuditPageSize, maxAuditPageSize)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		document, _, err := cfg.ES.GetTicketDocument(ctx, c.Param("id"))
+		if err != nil {
+			middleware.RespondError(c, err, "Error while fetching ticket audit trail")
+			return
+		}
+
+		items, _ := document["audit_logs"].([]interface{})
+		entries := ticketAuditEntries(items)
+		sortAuditEntries(entries)
+
+		count := int64(len(entries))
+		from := min(page.Offset(), len(entries))
+		to := min(from+page.Size, len(entries))
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, entries[from:to], middleware.NewPagination(c, page, count), "Ticket audit trail retrieved successfully"))
+	}
+}
+
+// SearchTicketAudit handles the GET /tickets/audit/search endpoint
+// @Summary      Search ticket audit trails
+// @Description  Finds the tickets with an audit_logs entry matching all the filters at once, newest tickets first, each with its matching entries (up to 100).
+// @Description  For example field=priority, change=downgrade, performedBy=12, startDate and endDate find the tickets whose priority user 12 lowered in the period. q is a full-text search on the entry description.
+// @Tags         tickets
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        q            query  string  false  "Full-text search on the entry description"
+// @Param        performedBy  query  string  false  "ID of the user who made the change"
+// @Param        operation    query  string  false  "Operation (UPDATE for the entries written by ticket updates)"
+// @Param        field        query  string  false  "Changed field" Enums(priority, current_status, assigned_agent, category, subcategory, product, channel, sla_plan)
+// @Param        change       query  string  false  "Direction of a priority change" Enums(upgrade, downgrade)
+// @Param        oldValue     query  string  false  "Value before the change"
+// @Param        newValue     query  string  false  "Value after the change"
+// @Param        startDate    query  string  false  "Changes from this date (YYYY-MM-DD, UTC)"
+// @Param        endDate      query  string  false  "Changes up to this date, inclusive (YYYY-MM-DD, UTC)"
+// @Param        page         query  int     false  "Page number (default 1)"
+// @Param        pageSize     query  int     false  "Page size (default 20, max 100)"
+// @Success      200  {object}  dto.PaginatedResponse{data=[]dto.TicketAuditSearchResult}
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets/audit/search [get]
+func SearchTicketAudit(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseTicketAuditFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid audit filter", err.Error()))
+			return
+		}
+
+		page := middleware.ParsePage(c, defaultAuditPageSize, maxAuditPageSize)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		hits, count, err := cfg.ES.SearchTicketAudit(ctx, filter, page.Offset(), page.Size)
+		if err != nil {
+			middleware.RespondError(c, err, "Error while searching ticket audit trails")
+			return
+		}
+
+		results := make([]dto.TicketAuditSearchResult, 0, len(hits))
+		for _, hit := range hits {
+			entries := make([]interface{}, 0, len(hit.Entries))
+			for _, entry := range hit.Entries {
+				entries = append(entries, entry)
+			}
+			results = append(results, dto.TicketAuditSearchResult{
+				TicketId:      documentString(hit.Ticket["ticket_id"]),
+				Title:         documentString(hit.Ticket["title"]),
+				Priority:      documentString(hit.Ticket["priority"]),
+				CurrentStatus: documentString(hit.Ticket["current_status"]),
+				Entries:       ticketAuditEntries(entries),
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, results, middleware.NewPagination(c, page, count), "Ticket audit trails retrieved successfully"))
+	}
+}
+
+// parseTicketAuditFilter lê os filtros da busca de auditoria; endDate é inclusiva
+func parseTicketAuditFilter(c *gin.Context) (elsearch.TicketAuditFilter, error) {
+	filter := elsearch.TicketAuditFilter{
+		Query:       strings.TrimSpace(c.Query("q")),
+		PerformedBy: strings.TrimSpace(c.Query("performedBy")),
+		Operation:   strings.ToUpper(strings.TrimSpace(c.Query("operation"))),
+		Field:       strings.ToLower(strings.TrimSpace(c.Query("field"))),
+		Change:      strings.ToLower(strings.TrimSpace(c.Query("change"))),
+		OldValue:    strings.TrimSpace(c.Query("oldValue")),
+		NewValue:    strings.TrimSpace(c.Query("newValue")),
+	}
+
+	if filter.Change != "" && filter.Change != elsearch.AuditChangeUpgrade && filter.Change != elsearch.AuditChangeDowngrade {
+		return filter, fmt.Errorf("invalid change %q, expected %s or %s", filter.Change, elsearch.AuditChangeUpgrade, elsearch.AuditChangeDowngrade)
+	}
+
+	if value := c.Query("startDate"); value != "" {
+		startDate, err := time.Parse(auditFilterDateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid startDate %q, expected format YYYY-MM-DD", value)
+		}
+		filter.From = &startDate
+	}
+
+	if value := c.Query("endDate"); value != "" {
+		endDate, err := time.Parse(auditFilterDateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid endDate %q, expected format YYYY-MM-DD", value)
+		}
+		to := endDate.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return filter, errors.New("endDate must not be before startDate")
+	}
+
+	return filter, nil
+}
+
+// ticketAuditEntries converte as entradas de audit_logs, ignorando itens que não são objetos
+func ticketAuditEntries(items []interface{}) []dto.TicketAuditEntry {
+	entries := make([]dto.TicketAuditEntry, 0, len(items))
+	for _, item := range items {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		entry := dto.TicketAuditEntry{
+			EntityType:  documentString(raw["entity_type"]),
+			EntityId:    documentString(raw["entity_id"]),
+			Operation:   documentString(raw["operation"]),
+			PerformedBy: documentString(raw["performed_by"]),
+			Field:       documentString(raw["field"]),
+			OldValue:    documentString(raw["old_value"]),
+			NewValue:    documentString(raw["new_value"]),
+			Change:      documentString(raw["change"]),
+			Description: documentString(raw["description"]),
+			Details:     raw["details"],
+		}
+		if performedAt, ok := documentTime(raw["performed_at"]); ok {
+			formatted := performedAt.Format(ticketDateLayouts[0])
+			entry.PerformedAt = &formatted
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// sortAuditEntries ordena as entradas da mais recente para a mais antiga; sem data, vão para o fim
+// na ordem em que foram gravadas
+func sortAuditEntries(entries []dto.TicketAuditEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].PerformedAt, entries[j].PerformedAt
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		// O formato yyyy-MM-dd HH:mm:ss ordena como texto
+		return *a > *b
+	})
+}
@@ -0,0 +1,68 @@
+package tickets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketAuditEntriesSorted(t *testing.T) {
+	entries := ticketAuditEntries([]interface{}{
+		map[string]interface{}{"field": "priority", "performed_at": "2025-06-01 08:00:00", "performed_by": float64(12)},
+		"not an object",
+		map[string]interface{}{"operation": "CREATE"},
+		map[string]interface{}{"field": "category", "performed_at": float64(1748858400000), "details": map[string]interface{}{"reason": "triagem"}},
+	})
+	sortAuditEntries(entries)
+
+	require.Len(t, entries, 3)
+	assert.Equal(t, "category", entries[0].Field)
+	require.NotNil(t, entries[0].PerformedAt)
+	assert.Equal(t, "2025-06-02 10:00:00", *entries[0].PerformedAt)
+	assert.Equal(t, map[string]interface{}{"reason": "triagem"}, entries[0].Details)
+	assert.Equal(t, "priority", entries[1].Field)
+	assert.Equal(t, "12", entries[1].PerformedBy)
+	assert.Equal(t, "CREATE", entries[2].Operation, "entries without a date go last")
+	assert.Nil(t, entries[2].PerformedAt)
+}
+
+func TestParseTicketAuditFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{name: "no filters", query: ""},
+		{name: "priority downgrades by a user in a period", query: "?field=PRIORITY&change=Downgrade&performedBy=12&startDate=2025-06-01&endDate=2025-06-30"},
+		{name: "invalid change", query: "?change=sideways", wantErr: true},
+		{name: "invalid date", query: "?startDate=01/06/2025", wantErr: true},
+		{name: "end before start", query: "?startDate=2025-06-02&endDate=2025-06-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/tickets/audit/search"+tt.query, nil)
+
+			filter, err := parseTicketAuditFilter(c)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.query != "" {
+				assert.Equal(t, "priority", filter.Field)
+				assert.Equal(t, "downgrade", filter.Change)
+				assert.Equal(t, "12", filter.PerformedBy)
+				require.NotNil(t, filter.To)
+				assert.Equal(t, "2025-07-01", filter.To.Format(auditFilterDateLayout))
+			}
+		})
+	}
+}
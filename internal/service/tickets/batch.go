@@ -0,0 +1,76 @@
+package tickets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTicketsBatch handles the GET /tickets/batch endpoint to fetch several tickets in one call
+// @Summary      Get tickets by IDs
+// @Description  Returns the tickets with the given IDs, in the requested order and without duplicates, through a single multi-get request.
+// @Description  IDs may be comma separated or repeated (ids=1,2&ids=3). Tickets that do not exist or are outside of the caller scope are listed in notFound.
+// @Tags         tickets
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        ids  query     []string  true  "Ticket IDs (at most 100)" collectionFormat(csv)
+// @Success      200  {object}  dto.SuccessResponse{data=dto.TicketBatchResponse}
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets/batch [get]
+func GetTicketsBatch(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ids, err := parseTicketIDs(c.QueryArray("ids"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid ticket IDs", err.Error()))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		tickets, notFound, err := cfg.ES.SearchTicketsByIDs(ctx, ids)
+		if err != nil {
+			middleware.RespondError(c, err, "Error while fetching tickets")
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.TicketBatchResponse{
+			Tickets:  tickets,
+			NotFound: notFound,
+		}, "Tickets retrieved successfully"))
+	}
+}
+
+// parseTicketIDs splits the ids query values on commas and drops blanks and duplicates
+func parseTicketIDs(values []string) ([]string, error) {
+	seen := map[string]bool{}
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, id := range strings.Split(value, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("ids is required")
+	}
+	if len(ids) > elsearch.MaxTicketBatch {
+		return nil, fmt.Errorf("at most %d ids are allowed, got %d", elsearch.MaxTicketBatch, len(ids))
+	}
+	return ids, nil
+}
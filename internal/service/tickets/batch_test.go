@@ -0,0 +1,51 @@
+package tickets
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTicketIDs(t *testing.T) {
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = "TKT-" + strconv.Itoa(i)
+	}
+
+	tests := []struct {
+		name          string
+		values        []string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:     "comma separated and repeated values",
+			values:   []string{"TKT-1, TKT-2", "TKT-3", "TKT-1,,"},
+			expected: []string{"TKT-1", "TKT-2", "TKT-3"},
+		},
+		{
+			name:          "missing ids",
+			values:        []string{" , "},
+			expectedError: "ids is required",
+		},
+		{
+			name:          "too many ids",
+			values:        tooMany,
+			expectedError: "at most 100 ids are allowed, got 101",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := parseTicketIDs(tt.values)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
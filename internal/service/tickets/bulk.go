@@ -0,0 +1,216 @@
+package tickets
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/mongo"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bulkBatchSize is how many tickets are sent to Elasticsearch per Bulk API request
+const bulkBatchSize = 500
+
+// BulkImportTickets handles the POST /tickets/bulk endpoint to back-fill tickets
+// @Summary      Bulk import tickets
+// @Description  Accepts NDJSON (one ticket per line, Content-Type application/x-ndjson) or a JSON array of tickets.
+// @Description  Every ticket is validated like in POST /tickets and written through the Bulk API in batches; existing tickets are replaced.
+// @Description  The response summarizes the import and lists the tickets that failed, by their position in the payload.
+// @Tags         tickets
+// @Accept       json
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        tickets body []dto.Ticket true "Tickets as a JSON array or NDJSON"
+// @Success      200  {object}  dto.SuccessResponse{data=dto.BulkTicketsResponse}
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      429  {object}  dto.RateLimitErrorResponse "Daily bulk import quota exceeded"
+// @Failure      500  {object}  dto.ErrorResponse
+// @Failure      503  {object}  dto.RateLimitErrorResponse
+// @Router       /tickets/bulk [post]
+func BulkImportTickets(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Minute)
+		defer cancel()
+
+		started := time.Now()
+		summary := dto.BulkTicketsResponse{Errors: []dto.BulkTicketResult{}}
+
+		batch := make([]dto.Ticket, 0, bulkBatchSize)
+		positions := make([]int, 0, bulkBatchSize)
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+
+			results, err := cfg.ES.BulkIndexTickets(ctx, batch)
+			if err != nil {
+				return err
+			}
+			written := make([]dto.Ticket, 0, len(batch))
+			for i, result := range results {
+				result.Index = positions[i]
+				addBulkResult(&summary, result)
+				if result.Error == "" && result.Status < http.StatusBadRequest {
+					written = append(written, batch[i])
+				}
+			}
+			archiveTicketEvents(c, cfg, mongo.TicketEventImported, written...)
+
+			batch = batch[:0]
+			positions = positions[:0]
+			middleware.ReportProgress(c, int64(summary.Total), 0)
+			return nil
+		}
+
+		err := readTickets(c.Request.Body, isNDJSON(c), func(index int, raw json.RawMessage) error {
+			ticket, err := decodeTicket(raw)
+			if err != nil {
+				addBulkResult(&summary, dto.BulkTicketResult{Index: index, TicketId: ticket.TicketID, Status: http.StatusBadRequest, Error: err.Error()})
+				return nil
+			}
+
+			batch = append(batch, ticket)
+			positions = append(positions, index)
+			if len(batch) < bulkBatchSize {
+				return nil
+			}
+			return flush()
+		})
+
+		var malformed *malformedPayloadError
+		switch {
+		case errors.As(err, &malformed) && summary.Total+len(batch) == 0:
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid tickets payload", err.Error()))
+			return
+		case errors.As(err, &malformed):
+			// Part of the payload was already written; report where it stopped instead of failing the whole request
+			addBulkResult(&summary, dto.BulkTicketResult{Index: malformed.Index, Status: http.StatusBadRequest, Error: err.Error()})
+		case err != nil:
+			middleware.RespondError(c, err, "Error while importing tickets")
+			return
+		}
+
+		if err := flush(); err != nil {
+			middleware.RespondError(c, err, "Error while importing tickets")
+			return
+		}
+
+		summary.Took = time.Since(started).String()
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, summary, "Tickets imported"))
+	}
+}
+
+// malformedPayloadError reports a payload that could not be parsed from Index onwards
+type malformedPayloadError struct {
+	Index int
+	Err   error
+}
+
+func (e *malformedPayloadError) Error() string {
+	return fmt.Sprintf("malformed payload at ticket %d: %v", e.Index, e.Err)
+}
+
+func (e *malformedPayloadError) Unwrap() error {
+	return e.Err
+}
+
+// isNDJSON reports whether the request body is NDJSON rather than a JSON array
+func isNDJSON(c *gin.Context) bool {
+	contentType := c.ContentType()
+	return strings.Contains(contentType, "ndjson") || strings.Contains(contentType, "jsonlines")
+}
+
+// readTickets calls fn with the raw JSON of every ticket in the payload and its position.
+// In NDJSON a malformed line only fails that ticket; in a JSON array it stops the reading.
+func readTickets(r io.Reader, ndjson bool, fn func(index int, raw json.RawMessage) error) error {
+	if ndjson {
+		return readNDJSON(r, fn)
+	}
+
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return &malformedPayloadError{Index: 0, Err: err}
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return &malformedPayloadError{Index: 0, Err: errors.New("expected a JSON array of tickets")}
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return &malformedPayloadError{Index: index, Err: err}
+		}
+		if err := fn(index, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readNDJSON calls fn for every non-empty line of the payload
+func readNDJSON(r io.Reader, fn func(index int, raw json.RawMessage) error) error {
+	reader := bufio.NewReader(r)
+	for index := 0; ; {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return &malformedPayloadError{Index: index, Err: err}
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if callbackErr := fn(index, json.RawMessage(line)); callbackErr != nil {
+				return callbackErr
+			}
+			index++
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+// decodeTicket parses and validates a ticket with the same rules as POST /tickets
+func decodeTicket(raw json.RawMessage) (dto.Ticket, error) {
+	var ticket dto.Ticket
+	if err := json.Unmarshal(raw, &ticket); err != nil {
+		return ticket, err
+	}
+	if err := binding.Validator.ValidateStruct(&ticket); err != nil {
+		return ticket, err
+	}
+	if ticket.TicketID == "" {
+		return ticket, errors.New("ticket_id is required")
+	}
+	return ticket, nil
+}
+
+// addBulkResult counts the result in the summary, keeping only the failures in the list
+func addBulkResult(summary *dto.BulkTicketsResponse, result dto.BulkTicketResult) {
+	summary.Total++
+	switch {
+	case result.Error != "" || result.Status >= http.StatusBadRequest:
+		summary.Failed++
+		summary.Errors = append(summary.Errors, result)
+	case result.Result == "created":
+		summary.Created++
+	default:
+		summary.Updated++
+	}
+}
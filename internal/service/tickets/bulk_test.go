@@ -0,0 +1,83 @@
+package tickets
+
+import (
+	"encoding/json"
+	"net/http"
+	"orderstreamrest/internal/models/dto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validTicket = `{"ticket_id":"TKT-1","title":"Sem internet","priority":"ALTA","channel":"Email","dates":{"created_at":"2025-01-10 08:00:00"}}`
+
+func collectTickets(t *testing.T, payload string, ndjson bool) ([]int, error) {
+	t.Helper()
+	var indexes []int
+	err := readTickets(strings.NewReader(payload), ndjson, func(index int, raw json.RawMessage) error {
+		indexes = append(indexes, index)
+		return nil
+	})
+	return indexes, err
+}
+
+func TestReadTickets(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   string
+		ndjson    bool
+		expected  []int
+		malformed bool
+	}{
+		{name: "JSON array", payload: "[" + validTicket + "," + validTicket + "]", expected: []int{0, 1}},
+		{name: "Empty array", payload: "[]"},
+		{name: "NDJSON skips blank lines", payload: validTicket + "\n\n" + validTicket, ndjson: true, expected: []int{0, 1}},
+		{name: "NDJSON keeps malformed lines for validation", payload: validTicket + "\n{broken\n", ndjson: true, expected: []int{0, 1}},
+		{name: "Object instead of array", payload: validTicket, malformed: true},
+		{name: "Truncated array", payload: "[" + validTicket + ",{", expected: []int{0}, malformed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexes, err := collectTickets(t, tt.payload, tt.ndjson)
+			if tt.malformed {
+				var malformed *malformedPayloadError
+				assert.ErrorAs(t, err, &malformed)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, indexes)
+		})
+	}
+}
+
+func TestDecodeTicket(t *testing.T) {
+	ticket, err := decodeTicket(json.RawMessage(validTicket))
+	require.NoError(t, err)
+	assert.Equal(t, "TKT-1", ticket.TicketID)
+
+	_, err = decodeTicket(json.RawMessage(`{"ticket_id":"TKT-2","priority":"ALTA","channel":"Email","dates":{"created_at":"2025-01-10"}}`))
+	assert.ErrorContains(t, err, "Title")
+
+	_, err = decodeTicket(json.RawMessage(`{"title":"Sem internet","priority":"ALTA","channel":"Email","dates":{"created_at":"2025-01-10"}}`))
+	assert.ErrorContains(t, err, "ticket_id")
+
+	_, err = decodeTicket(json.RawMessage(`{broken`))
+	assert.Error(t, err)
+}
+
+func TestAddBulkResult(t *testing.T) {
+	var summary dto.BulkTicketsResponse
+	addBulkResult(&summary, dto.BulkTicketResult{Index: 0, Status: http.StatusCreated, Result: "created"})
+	addBulkResult(&summary, dto.BulkTicketResult{Index: 1, Status: http.StatusOK, Result: "updated"})
+	addBulkResult(&summary, dto.BulkTicketResult{Index: 2, Status: http.StatusBadRequest, Error: "mapper_parsing_exception: failed to parse"})
+
+	assert.Equal(t, 3, summary.Total)
+	assert.Equal(t, 1, summary.Created)
+	assert.Equal(t, 1, summary.Updated)
+	assert.Equal(t, 1, summary.Failed)
+	require.Len(t, summary.Errors, 1)
+	assert.Equal(t, 2, summary.Errors[0].Index)
+}
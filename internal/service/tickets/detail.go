@@ -0,0 +1,196 @@
+package tickets
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ticketDateLayouts são os formatos de data aceitos pelo mapping de support_tickets
+var ticketDateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02"}
+
+// GetTicketDetail handles the GET /tickets/:id/full endpoint
+// @Summary      Get ticket detail
+// @Description  Returns the ticket document merged with its resolution and first response times, SLA breach flags, a status history summary and, from the warehouse, how its company and priority usually perform. The warehouse block is null when the DW is unavailable.
+// @Tags         tickets
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id   path      string  true  "Ticket ID"
+// @Success      200  {object}  dto.SuccessResponse{data=dto.TicketDetail}
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets/{id}/full [get]
+func GetTicketDetail(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		document, version, err := cfg.ES.GetTicketDocument(ctx, c.Param("id"))
+		if err != nil {
+			middleware.RespondError(c, err, "Error while fetching ticket")
+			return
+		}
+
+		detail := buildTicketDetail(document)
+		detail.Version = version.String()
+
+		// O DW complementa o detalhe; sem ele o ticket é retornado assim mesmo
+		warehouse, err := ticketWarehouseContext(ctx, cfg, document, detail.Metrics)
+		if err != nil {
+			log.Printf("Failed to load warehouse context for ticket %s: %v", c.Param("id"), err)
+		}
+		detail.Warehouse = warehouse
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, detail, "Ticket retrieved successfully"))
+	}
+}
+
+// buildTicketDetail calcula as métricas e o resumo de status a partir do documento do ticket.
+// Tempos ausentes em sla_metrics são calculados pelas datas do ticket.
+func buildTicketDetail(document map[string]interface{}) dto.TicketDetail {
+	detail := dto.TicketDetail{
+		Ticket:        document,
+		StatusHistory: dto.TicketStatusSummary{ByStatus: map[string]int64{}},
+	}
+
+	dates, _ := document["dates"].(map[string]interface{})
+	createdAt, hasCreated := documentTime(dates["created_at"])
+	closedAt, hasClosed := documentTime(dates["closed_at"])
+	firstResponseAt, hasFirstResponse := documentTime(dates["first_response_at"])
+
+	sla, _ := document["sla_metrics"].(map[string]interface{})
+	metrics := &detail.Metrics
+	metrics.IsClosed = hasClosed
+	metrics.FirstResponseSLABreached, _ = sla["first_response_sla_breached"].(bool)
+	metrics.ResolutionSLABreached, _ = sla["resolution_sla_breached"].(bool)
+
+	metrics.FirstResponseTimeMinutes = documentNumber(sla["first_response_time_minutes"])
+	if metrics.FirstResponseTimeMinutes == nil && hasCreated && hasFirstResponse {
+		metrics.FirstResponseTimeMinutes = minutesBetween(createdAt, firstResponseAt)
+	}
+	metrics.ResolutionTimeMinutes = documentNumber(sla["resolution_time_minutes"])
+	if metrics.ResolutionTimeMinutes == nil && hasCreated && hasClosed {
+		metrics.ResolutionTimeMinutes = minutesBetween(createdAt, closedAt)
+	}
+
+	history, _ := document["status_history"].([]interface{})
+	var lastChange time.Time
+	for _, item := range history {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		detail.StatusHistory.Changes++
+		if status := documentString(entry["to_status"]); status != "" {
+			detail.StatusHistory.ByStatus[status]++
+		}
+		if changedAt, ok := documentTime(entry["changed_at"]); ok && changedAt.After(lastChange) {
+			lastChange = changedAt
+		}
+	}
+	if !lastChange.IsZero() {
+		formatted := lastChange.Format(ticketDateLayouts[0])
+		detail.StatusHistory.LastChangeAt = &formatted
+	}
+
+	return detail
+}
+
+// ticketWarehouseContext busca no DW o volume da empresa do ticket e a média de resolução da sua prioridade
+func ticketWarehouseContext(ctx context.Context, cfg *config.App, document map[string]interface{}, metrics dto.TicketDetailMetrics) (*dto.TicketWarehouseContext, error) {
+	company, _ := document["company"].(map[string]interface{})
+	companyID := documentString(company["id"])
+	if companyID == "" {
+		return nil, fmt.Errorf("ticket has no company")
+	}
+
+	filter := dto.MetricsFilter{CompanyScoped: true, Companies: []string{companyID}}
+	total, err := cfg.SqlServer.GetTotalTickets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	warehouse := &dto.TicketWarehouseContext{CompanyTotalTickets: total}
+
+	if priority := documentString(document["priority"]); priority != "" {
+		filter.Priority = &priority
+		averages, err := cfg.SqlServer.GetAverageResolutionTime(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(averages) > 0 {
+			hours := averages[0].MediaResolucaoHoras
+			warehouse.PriorityAverageResolutionHours = &hours
+			if metrics.ResolutionTimeMinutes != nil && hours > 0 {
+				percent := round1(*metrics.ResolutionTimeMinutes / 60 / hours * 100)
+				warehouse.ResolutionVsPriorityAveragePercent = &percent
+			}
+		}
+	}
+
+	return warehouse, nil
+}
+
+// documentTime lê uma data do documento: texto nos formatos do mapping ou epoch em milissegundos
+func documentTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range ticketDateLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+		if millis, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.UnixMilli(millis).UTC(), true
+		}
+	case float64:
+		return time.UnixMilli(int64(v)).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// documentNumber lê um número gravado como número ou texto
+func documentNumber(value interface{}) *float64 {
+	switch v := value.(type) {
+	case float64:
+		return &v
+	case string:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return &n
+		}
+	}
+	return nil
+}
+
+// documentString lê um campo keyword, que pode ter sido gravado como número
+func documentString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func minutesBetween(from, to time.Time) *float64 {
+	if to.Before(from) {
+		return nil
+	}
+	minutes := round1(to.Sub(from).Minutes())
+	return &minutes
+}
+
+func round1(value float64) float64 {
+	return float64(int64(value*10+0.5)) / 10
+}
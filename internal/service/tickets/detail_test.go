@@ -0,0 +1,69 @@
+package tickets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTicketDetail(t *testing.T) {
+	t.Run("uses sla_metrics when present", func(t *testing.T) {
+		detail := buildTicketDetail(map[string]interface{}{
+			"dates": map[string]interface{}{
+				"created_at": "2025-06-01 08:00:00",
+				"closed_at":  "2025-06-02 08:00:00",
+			},
+			"sla_metrics": map[string]interface{}{
+				"first_response_time_minutes": float64(35),
+				"resolution_time_minutes":     "1500",
+				"resolution_sla_breached":     true,
+			},
+			"status_history": []interface{}{
+				map[string]interface{}{"from_status": "1", "to_status": "2", "changed_at": "2025-06-01 09:00:00"},
+				map[string]interface{}{"from_status": "2", "to_status": "3", "changed_at": "2025-06-02 08:00:00"},
+				map[string]interface{}{"from_status": "3", "to_status": "2", "changed_at": "2025-06-01 12:00:00"},
+			},
+		})
+
+		assert.True(t, detail.Metrics.IsClosed)
+		require.NotNil(t, detail.Metrics.FirstResponseTimeMinutes)
+		assert.Equal(t, 35.0, *detail.Metrics.FirstResponseTimeMinutes)
+		require.NotNil(t, detail.Metrics.ResolutionTimeMinutes)
+		assert.Equal(t, 1500.0, *detail.Metrics.ResolutionTimeMinutes)
+		assert.True(t, detail.Metrics.ResolutionSLABreached)
+		assert.False(t, detail.Metrics.FirstResponseSLABreached)
+
+		assert.Equal(t, 3, detail.StatusHistory.Changes)
+		assert.Equal(t, map[string]int64{"2": 2, "3": 1}, detail.StatusHistory.ByStatus)
+		require.NotNil(t, detail.StatusHistory.LastChangeAt)
+		assert.Equal(t, "2025-06-02 08:00:00", *detail.StatusHistory.LastChangeAt)
+	})
+
+	t.Run("computes times from the dates", func(t *testing.T) {
+		detail := buildTicketDetail(map[string]interface{}{
+			"dates": map[string]interface{}{
+				"created_at":        "2025-06-01 08:00:00",
+				"first_response_at": float64(1748766600000), // 2025-06-01 08:30:00 UTC
+				"closed_at":         "2025-06-01 10:15:00",
+			},
+		})
+
+		require.NotNil(t, detail.Metrics.FirstResponseTimeMinutes)
+		assert.Equal(t, 30.0, *detail.Metrics.FirstResponseTimeMinutes)
+		require.NotNil(t, detail.Metrics.ResolutionTimeMinutes)
+		assert.Equal(t, 135.0, *detail.Metrics.ResolutionTimeMinutes)
+	})
+
+	t.Run("open ticket without history", func(t *testing.T) {
+		detail := buildTicketDetail(map[string]interface{}{
+			"dates": map[string]interface{}{"created_at": "2025-06-01"},
+		})
+
+		assert.False(t, detail.Metrics.IsClosed)
+		assert.Nil(t, detail.Metrics.ResolutionTimeMinutes)
+		assert.Zero(t, detail.StatusHistory.Changes)
+		assert.Empty(t, detail.StatusHistory.ByStatus)
+		assert.Nil(t, detail.StatusHistory.LastChangeAt)
+	})
+}
@@ -0,0 +1,143 @@
+package tickets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/mongo"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultEventsPageSize = 20
+	maxEventsPageSize     = 100
+
+	// archiveTimeout bounds how long a ticket write waits for its events to be archived
+	archiveTimeout = 5 * time.Second
+)
+
+// ListTicketEvents handles the GET /tickets/:id/events endpoint
+// @Summary      List ticket events
+// @Description  Returns the raw payload of every write of the ticket (create, update and bulk import), newest first.
+// @Description  The payloads are archived in MongoDB for TICKET_EVENTS_RETENTION_DAYS; 503 when the archive is not configured and 404 when the ticket is outside the user's scope.
+// @Tags         tickets
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id        path   string  true   "Ticket ID"
+// @Param        page      query  int     false  "Page number (default 1)"
+// @Param        pageSize  query  int     false  "Page size (default 20, max 100)"
+// @Success      200  {object}  dto.PaginatedResponse{data=[]dto.TicketEventResponse}
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Failure      503  {object}  dto.ErrorResponse
+// @Router       /tickets/{id}/events [get]
+func ListTicketEvents(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.Mongo == nil {
+			c.JSON(http.StatusServiceUnavailable, dto.NewErrorResponse(c, http.StatusServiceUnavailable, "Service Unavailable", "Ticket events archive is not configured", nil))
+			return
+		}
+
+		page := middleware.ParsePage(c, defaultEventsPageSize, maxEventsPageSize)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		ticketID := c.Param("id")
+
+		// The archive is not scoped, so the ticket is resolved first with the user's scope
+		ticket, err := cfg.ES.SearchTicketByID(ctx, ticketID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, err.Error(), "Error while fetching ticket", nil))
+			return
+		}
+		if ticket == nil {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Ticket not found", "Error while fetching ticket", nil))
+			return
+		}
+
+		events, count, err := cfg.Mongo.ListTicketEvents(ctx, ticketID, page.Offset(), page.Size)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve ticket events", err.Error()))
+			return
+		}
+
+		entries := make([]dto.TicketEventResponse, 0, len(events))
+		for _, event := range events {
+			entry, err := ticketEventResponse(event)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve ticket events", err.Error()))
+				return
+			}
+			entries = append(entries, entry)
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, entries, middleware.NewPagination(c, page, count), "Ticket events retrieved successfully"))
+	}
+}
+
+// ticketEventResponse converts an archived event, turning its BSON payload back into JSON
+func ticketEventResponse(event mongo.TicketEvent) (dto.TicketEventResponse, error) {
+	payload, err := event.PayloadJSON()
+	if err != nil {
+		return dto.TicketEventResponse{}, fmt.Errorf("decoding payload of event %s: %w", event.ID.Hex(), err)
+	}
+
+	return dto.TicketEventResponse{
+		Id:        event.ID.Hex(),
+		TicketId:  event.TicketID,
+		Type:      event.Type,
+		ActorId:   event.ActorID,
+		Payload:   payload,
+		CreatedAt: event.CreatedAt,
+	}, nil
+}
+
+// newTicketEvents builds one event per ticket with the ticket as payload
+func newTicketEvents(eventType string, actorID *int, tickets []dto.Ticket) ([]mongo.TicketEvent, error) {
+	events := make([]mongo.TicketEvent, 0, len(tickets))
+	for _, ticket := range tickets {
+		payload, err := json.Marshal(ticket)
+		if err != nil {
+			return nil, err
+		}
+
+		event, err := mongo.NewTicketEvent(ticket.TicketID, eventType, actorID, payload)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// archiveTicketEvents stores the payload of the written tickets when the archive is configured.
+// The tickets are already in Elasticsearch, so a failure is only logged.
+func archiveTicketEvents(c *gin.Context, cfg *config.App, eventType string, tickets ...dto.Ticket) {
+	if cfg.Mongo == nil || len(tickets) == 0 {
+		return
+	}
+
+	var actorID *int
+	if userID, ok := middleware.GetCurrentUserID(c); ok {
+		actorID = &userID
+	}
+
+	events, err := newTicketEvents(eventType, actorID, tickets)
+	if err == nil {
+		// Not tied to the client: a disconnect after the write must not lose the event
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), archiveTimeout)
+		defer cancel()
+		err = cfg.Mongo.InsertTicketEvents(ctx, events)
+	}
+	if err != nil {
+		cfg.Logger.Error(fmt.Sprintf("Failed to archive %d ticket events", len(tickets)), err)
+	}
+}
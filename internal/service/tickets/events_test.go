@@ -0,0 +1,88 @@
+package tickets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/mongo"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTicketEventRoundTrip(t *testing.T) {
+	actorID := 7
+	tickets := []dto.Ticket{
+		{TicketID: "TKT-1", Title: "Sem internet", Channel: "EMAIL", Priority: "ALTA", Attachments: []interface{}{map[string]interface{}{"name": "log.txt", "size": float64(2048)}}},
+		{TicketID: "TKT-2", Title: "Fatura duplicada", Channel: "CHAT", Priority: "BAIXA", SLAPlan: 3},
+	}
+
+	events, err := newTicketEvents(mongo.TicketEventImported, &actorID, tickets)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	for i, event := range events {
+		event.ID = primitive.NewObjectID()
+
+		response, err := ticketEventResponse(event)
+		require.NoError(t, err)
+
+		assert.Equal(t, tickets[i].TicketID, response.TicketId)
+		assert.Equal(t, mongo.TicketEventImported, response.Type)
+		require.NotNil(t, response.ActorId)
+		assert.Equal(t, 7, *response.ActorId)
+		assert.Equal(t, event.ID.Hex(), response.Id)
+		assert.False(t, response.CreatedAt.IsZero())
+	}
+
+	first, err := ticketEventResponse(events[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"assigned_agent":{},"attachments":[{"name":"log.txt","size":2048}],"category":{},"channel":"EMAIL","company":{},"created_by_user":{},"dates":{},"priority":"ALTA","product":{},"sla_metrics":{},"subcategory":{},"ticket_id":"TKT-1","title":"Sem internet"}`, string(first.Payload))
+}
+
+func TestListTicketEventsWithoutArchive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/tickets/:id/events", ListTicketEvents(&config.App{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/tickets/TKT-1/events", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestListTicketEventsOutsideScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write([]byte(`{"_id": "TKT-9", "found": true, "_source": {"ticket_id": "TKT-9", "company": {"id": 20}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	es, err := elsearch.NewClient(&elsearch.Config{Addresses: []string{server.URL}, IndexName: "support_tickets"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(elsearch.WithScope(c.Request.Context(), elsearch.CompanyScope("10")))
+	})
+	router.GET("/tickets/:id/events", ListTicketEvents(&config.App{ES: es, Mongo: &mongo.MongoInternal{}}))
+
+	req := httptest.NewRequest(http.MethodGet, "/tickets/TKT-9/events", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,183 @@
+package tickets
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/export"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushEvery is how many tickets are written between flushes to the client
+const exportFlushEvery = 500
+
+// ticketCSVColumns are the CSV header and the document field written in each column
+var ticketCSVColumns = []struct {
+	Header string
+	Field  string
+}{
+	{"ticket_id", "ticket_id"},
+	{"title", "title"},
+	{"current_status", "current_status"},
+	{"priority", "priority"},
+	{"channel", "channel"},
+	{"category", "category.name"},
+	{"subcategory", "subcategory.name"},
+	{"product", "product.name"},
+	{"company", "company.name"},
+	{"created_by", "created_by_user.full_name"},
+	{"assigned_agent", "assigned_agent.full_name"},
+	{"created_at", "dates.created_at"},
+	{"first_response_at", "dates.first_response_at"},
+	{"closed_at", "dates.closed_at"},
+	{"first_response_sla_breached", "sla_metrics.first_response_sla_breached"},
+	{"resolution_sla_breached", "sla_metrics.resolution_sla_breached"},
+}
+
+// ExportTickets handles the GET /tickets/export endpoint to stream every ticket matching a query
+// @Summary      Export tickets
+// @Description  Streams all tickets matching the search query, in the same order as /tickets/query and without the page size limit.
+// @Description  NDJSON writes one full ticket per line; CSV writes the main fields of each ticket. Runs in the export pool.
+// @Tags         tickets
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Security 	 BearerAuth
+// @Param        q       query  string  false  "Search query"
+// @Param        format  query  string  false  "Output format" Enums(ndjson, csv) default(ndjson)
+// @Success      200  {object}  dto.Ticket "One line per ticket"
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      429  {object}  dto.RateLimitErrorResponse "Daily export quota exceeded"
+// @Failure      500  {object}  dto.ErrorResponse
+// @Failure      503  {object}  dto.RateLimitErrorResponse
+// @Router       /tickets/export [get]
+func ExportTickets(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, err := parseTicketExportFormat(c.Query("format"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid export format", err.Error()))
+			return
+		}
+
+		var (
+			written int64
+			write   func(ticket map[string]interface{}) error
+			csvOut  *csv.Writer
+		)
+
+		start := func() error {
+			contentType := export.NDJSONContentType
+			if format == export.FormatCSV {
+				contentType = export.CSVContentType
+			}
+			c.Header("Content-Type", contentType)
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.Filename("tickets", format, time.Now())))
+			c.Status(http.StatusOK)
+
+			if format != export.FormatCSV {
+				encoder := json.NewEncoder(c.Writer)
+				write = func(ticket map[string]interface{}) error { return encoder.Encode(ticket) }
+				return nil
+			}
+
+			header := make([]string, len(ticketCSVColumns))
+			for i, column := range ticketCSVColumns {
+				header[i] = column.Header
+			}
+			writer, err := export.NewCSVWriter(c.Writer, header)
+			if err != nil {
+				return err
+			}
+			csvOut = writer
+			write = func(ticket map[string]interface{}) error { return csvOut.Write(ticketCSVRecord(ticket)) }
+			return nil
+		}
+
+		flush := func() {
+			if csvOut != nil {
+				csvOut.Flush()
+			}
+			c.Writer.Flush()
+		}
+
+		err = cfg.ES.ExportTickets(c.Request.Context(), strings.TrimSpace(c.Query("q")), func(ticket map[string]interface{}) error {
+			if written == 0 {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+
+			if err := write(ticket); err != nil {
+				return err
+			}
+
+			written++
+			if written%exportFlushEvery == 0 {
+				flush()
+				middleware.ReportProgress(c, written, 0)
+			}
+			return nil
+		})
+
+		if err != nil {
+			// Once the stream started the status can no longer change
+			if written > 0 {
+				log.Printf("Tickets export interrupted after %d tickets: %v", written, err)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to export tickets", err.Error()))
+			return
+		}
+
+		if written == 0 {
+			if err := start(); err != nil {
+				log.Printf("Failed to start tickets export: %v", err)
+				return
+			}
+		}
+		flush()
+		middleware.ReportProgress(c, written, written)
+	}
+}
+
+// parseTicketExportFormat accepts ndjson (default) and csv
+func parseTicketExportFormat(value string) (export.Format, error) {
+	switch export.Format(strings.ToLower(strings.TrimSpace(value))) {
+	case "", export.FormatNDJSON:
+		return export.FormatNDJSON, nil
+	case export.FormatCSV:
+		return export.FormatCSV, nil
+	default:
+		return "", fmt.Errorf("%w: %q (use ndjson or csv)", export.ErrUnsupportedFormat, value)
+	}
+}
+
+// ticketCSVRecord returns the CSV columns of a ticket document
+func ticketCSVRecord(ticket map[string]interface{}) []string {
+	row := make([]any, len(ticketCSVColumns))
+	for i, column := range ticketCSVColumns {
+		row[i] = lookupField(ticket, column.Field)
+	}
+	return export.CSVRecord(row)
+}
+
+// lookupField returns the value at a dotted path of the document, or nil when absent
+func lookupField(doc map[string]interface{}, path string) interface{} {
+	var value interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
@@ -0,0 +1,27 @@
+package tickets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketCSVRecord(t *testing.T) {
+	record := ticketCSVRecord(map[string]interface{}{
+		"ticket_id": "TKT-1",
+		"title":     "Sem internet",
+		"company":   map[string]interface{}{"id": 3, "name": "ACME"},
+		"dates":     map[string]interface{}{"created_at": "2025-01-10 08:00:00"},
+		"sla_metrics": map[string]interface{}{
+			"resolution_sla_breached": true,
+		},
+	})
+
+	require.Len(t, record, len(ticketCSVColumns))
+	assert.Equal(t, "TKT-1", record[0])
+	assert.Equal(t, "ACME", record[8])
+	assert.Equal(t, "", record[10])
+	assert.Equal(t, "2025-01-10 08:00:00", record[11])
+	assert.Equal(t, "true", record[15])
+}
@@ -17,7 +17,7 @@ import (
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Ticket ID"
-// @Success      200  {object}  dto.Ticket
+// @Success      200  {object}  dto.SuccessResponse{data=dto.Ticket}
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
@@ -30,7 +30,7 @@ func SearchTicketByID(cfg *config.App) gin.HandlerFunc {
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 		defer cancel()
 
 		ticket, err := cfg.ES.SearchTicketByID(ctx, ticketID)
@@ -43,6 +43,6 @@ func SearchTicketByID(cfg *config.App) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, ticket)
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, ticket, "Ticket retrieved successfully"))
 	}
 }
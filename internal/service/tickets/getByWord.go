@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
 	"orderstreamrest/internal/models/dto"
 	"time"
 
@@ -12,23 +13,29 @@ import (
 
 // GetByWord handles the GET /tickets endpoint to search tickets by a query word
 // @Summary      Search tickets by query word
-// @Description  Returns tickets matching the search query
+// @Description  Returns tickets matching the search query, with facets counting the matches by status, priority, category and channel.
+// @Description  Page numbers only reach the first 10000 tickets; deeper pages are read with the cursor in pagination.next_cursor.
 // @Tags         tickets
 // @Accept       json
 // @Produce      json
 // @Param        q     		query     string  false  "Search query"
 // @Param        page      query     int     false "Page number" default(1)
 // @Param        page_size query     int     false "Number of items per page" default(50) maximum(100)
+// @Param        mode      query     string  false "Query mode to compare relevance; defaults to ELASTICSEARCH_SEARCH_MODE" Enums(analyzed, legacy)
+// @Param        cursor    query     string  false "Opaque cursor from pagination.next_cursor; required past the result window (10000 tickets), page and page_size are ignored"
+// @Param        has_attachments query bool    false "Only tickets with (true) or without (false) attachments"
+// @Param        attachment_type query string  false "Only tickets with an attachment of this category (image, video, audio, document, archive) or mime type (image/png, image/*)"
 // @Success 	  200 {object} dto.PaginatedResponse{data=[]dto.Ticket}
+// @Header       200 {string} X-Search-Mode "Query mode used for the search"
 // @Failure      400   {object}  dto.ErrorResponse
+// @Failure      422   {object}  dto.ValidationErrorResponse "Validation Failed"
 // @Failure      500   {object}  dto.ErrorResponse
 // @Router       /tickets/query [get]
 func GetByWord(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		var params dto.SearchParams
-		if err := c.ShouldBindQuery(&params); err != nil {
-			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, err.Error(), "Error while searching tickets", nil))
+		if !middleware.BindQuery(c, &params, "Error while searching tickets") {
 			return
 		}
 
@@ -40,16 +47,27 @@ func GetByWord(cfg *config.App) gin.HandlerFunc {
 		// }
 
 		// Executar a busca
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 		defer cancel()
 
 		result, err := cfg.ES.SearchTicketsBySomeWord(ctx, params)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, err.Error(), "Error while searching tickets", nil))
+			middleware.RespondError(c, err, "Error while searching tickets")
 			return
 		}
 
-		c.JSON(http.StatusOK, result)
+		pagination := result.Pagination
+		pagination.Links = middleware.CursorPaginationLinks(c, pagination, "cursor")
+		// Cursors only move forward; a previous page past the result window cannot be opened by number
+		if (pagination.CurrentPage-1)*pagination.PerPage > cfg.ES.MaxResultWindow() {
+			pagination.Links.Prev = ""
+		}
+
+		response := dto.NewPaginatedResponse(c, result.Tickets, pagination, "Tickets retrieved successfully")
+		response.Facets = result.Facets
+
+		c.Header("X-Search-Mode", cfg.ES.SearchMode(params.Mode))
+		c.JSON(http.StatusOK, response)
 
 	}
 }
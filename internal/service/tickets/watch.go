@@ -0,0 +1,90 @@
+package tickets
+
+import (
+	"context"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/notifications"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WatchTicket handles the POST /tickets/:id/watch endpoint to subscribe to ticket changes
+// @Summary      Watch ticket
+// @Description  Subscribes the authenticated user to status, priority and assignment changes of the ticket
+// @Tags         tickets
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id   path      string  true  "Ticket ID"
+// @Success      200  {object}  dto.SuccessResponse{data=dto.TicketWatchResponse}
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets/{id}/watch [post]
+func WatchTicket(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Authenticated user not found", nil))
+			return
+		}
+
+		ticketID := c.Param("id")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		// A busca respeita o escopo do usuário, então só é possível observar tickets visíveis
+		ticket, err := cfg.ES.SearchTicketByID(ctx, ticketID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, err.Error(), "Error while fetching ticket", nil))
+			return
+		}
+		if ticket == nil {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Ticket not found", "Error while watching ticket", nil))
+			return
+		}
+
+		if err := notifications.Watch(ctx, cfg, userID, ticketID, *ticket); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, err.Error(), "Error while watching ticket", nil))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.TicketWatchResponse{TicketId: ticketID, Watching: true}, "Ticket watched successfully"))
+	}
+}
+
+// UnwatchTicket handles the DELETE /tickets/:id/watch endpoint to unsubscribe from ticket changes
+// @Summary      Unwatch ticket
+// @Description  Stops notifying the authenticated user about changes of the ticket
+// @Tags         tickets
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id   path      string  true  "Ticket ID"
+// @Success      200  {object}  dto.SuccessResponse{data=dto.TicketWatchResponse}
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets/{id}/watch [delete]
+func UnwatchTicket(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Authenticated user not found", nil))
+			return
+		}
+
+		ticketID := c.Param("id")
+
+		if err := notifications.Unwatch(c.Request.Context(), cfg, userID, ticketID); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, err.Error(), "Error while unwatching ticket", nil))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.TicketWatchResponse{TicketId: ticketID, Watching: false}, "Ticket unwatched successfully"))
+	}
+}
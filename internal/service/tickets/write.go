@@ -0,0 +1,128 @@
+package tickets
+
+import (
+	"context"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/elsearch"
+	"orderstreamrest/internal/repositories/mongo"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTicket handles the POST /tickets endpoint to index a new ticket
+// @Summary      Create ticket
+// @Description  Validates the ticket and writes it to the support_tickets index. The ticket_id is required and must not exist yet.
+// @Description  The document version is returned in the body and in the ETag header.
+// @Tags         tickets
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        ticket body dto.Ticket true "Ticket document"
+// @Success      201  {object}  dto.SuccessResponse{data=dto.TicketDocument}
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      422  {object}  dto.ValidationErrorResponse "Validation Failed"
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets [post]
+func CreateTicket(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ticket dto.Ticket
+		if !middleware.BindJSON(c, &ticket, "Invalid ticket") {
+			return
+		}
+		if ticket.TicketID == "" {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid ticket", "ticket_id is required"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		stored, err := cfg.ES.CreateTicket(ctx, ticket)
+		if err != nil {
+			writeTicketError(c, err, "Error while creating ticket")
+			return
+		}
+		archiveTicketEvents(c, cfg, mongo.TicketEventCreated, ticket)
+
+		c.Header("ETag", strconv.Quote(stored.Version))
+		c.JSON(http.StatusCreated, dto.NewSuccessResponse(c, stored, "Ticket created successfully"))
+	}
+}
+
+// UpdateTicket handles the PUT /tickets/:id endpoint to replace a ticket
+// @Summary      Update ticket
+// @Description  Validates the ticket and replaces the stored document. Send the version returned by a previous write in If-Match
+// @Description  to reject the update when the ticket was changed in the meantime; without it the write still fails if the ticket changes during the update.
+// @Description  Changes to priority, status, assignee, category, subcategory, product, channel and SLA plan are appended to audit_logs; when the body has no audit_logs the stored trail is kept.
+// @Tags         tickets
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id       path    string      true   "Ticket ID"
+// @Param        If-Match header  string      false  "Expected document version"
+// @Param        ticket   body    dto.Ticket  true   "Ticket document"
+// @Success      200  {object}  dto.SuccessResponse{data=dto.TicketDocument}
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      422  {object}  dto.ValidationErrorResponse "Validation Failed"
+// @Failure      401  {object}  dto.AuthErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      412  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /tickets/{id} [put]
+func UpdateTicket(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticketID := c.Param("id")
+
+		var ticket dto.Ticket
+		if !middleware.BindJSON(c, &ticket, "Invalid ticket") {
+			return
+		}
+		if ticket.TicketID != "" && ticket.TicketID != ticketID {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid ticket", "ticket_id does not match the path"))
+			return
+		}
+
+		var expected *elsearch.DocumentVersion
+		if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && ifMatch != "*" {
+			version, err := elsearch.ParseDocumentVersion(ifMatch)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid If-Match header", err.Error()))
+				return
+			}
+			expected = &version
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		var performedBy string
+		if userID, ok := middleware.GetCurrentUserID(c); ok {
+			performedBy = strconv.Itoa(userID)
+		}
+
+		stored, err := cfg.ES.UpdateTicket(ctx, ticketID, ticket, expected, performedBy)
+		if err != nil {
+			writeTicketError(c, err, "Error while updating ticket")
+			return
+		}
+		ticket.TicketID = ticketID
+		archiveTicketEvents(c, cfg, mongo.TicketEventUpdated, ticket)
+
+		c.Header("ETag", strconv.Quote(stored.Version))
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, stored, "Ticket updated successfully"))
+	}
+}
+
+// writeTicketError responds with the repository write error; the status comes from its apperror kind
+func writeTicketError(c *gin.Context, err error, message string) {
+	middleware.RespondError(c, err, message)
+}
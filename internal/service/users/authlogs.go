@@ -0,0 +1,119 @@
+package users
+
+import (
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authTypeJWT = "JWT"
+
+	defaultAuthLogsLimit = 50
+	maxAuthLogsLimit     = 500
+)
+
+// recordAuthLog grava uma tentativa de autenticação em dbo.UserAuthLogs.
+// Falhas ao gravar o log não devem impedir o login.
+func recordAuthLog(c *gin.Context, cfg *config.App, userId int, authType string, success bool, errorMessage string) {
+	authLog := &entities.UserAuthLog{
+		UserId:    userId,
+		AuthType:  authType,
+		Success:   success,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if ip := c.ClientIP(); ip != "" {
+		authLog.IPAddress = &ip
+	}
+	if userAgent := c.Request.UserAgent(); userAgent != "" {
+		// A coluna UserAgent aceita até 500 caracteres
+		if len(userAgent) > 500 {
+			userAgent = userAgent[:500]
+		}
+		authLog.UserAgent = &userAgent
+	}
+	if errorMessage != "" {
+		authLog.ErrorMessage = &errorMessage
+	}
+
+	if err := cfg.SqlServer.CreateAuthLog(c.Request.Context(), authLog); err != nil {
+		log.Printf("Failed to record auth log for user %d: %v", userId, err)
+	}
+}
+
+// GetUserAuthLogs lista os logs de autenticação de um usuário
+// @Summary      Logs de Autenticação
+// @Description  Retorna as tentativas de autenticação de um usuário, da mais recente para a mais antiga
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path int true "ID do usuário"
+// @Param        limit query int false "Quantidade máxima de logs (padrão 50, máximo 500)"
+// @Success      200 {object} dto.SuccessResponse{data=dto.UserAuthLogsResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/{id}/auth-logs [get]
+func GetUserAuthLogs(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid user ID", nil))
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAuthLogsLimit)))
+		if err != nil || limit < 1 {
+			limit = defaultAuthLogsLimit
+		}
+		if limit > maxAuthLogsLimit {
+			limit = maxAuthLogsLimit
+		}
+
+		if _, err := cfg.SqlServer.GetUserByID(c.Request.Context(), id); err != nil {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "User not found", err.Error()))
+			return
+		}
+
+		logs, err := cfg.SqlServer.GetUserAuthLogs(c.Request.Context(), id, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve auth logs", err.Error()))
+			return
+		}
+
+		total, err := cfg.SqlServer.CountUserAuthLogs(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to count auth logs", err.Error()))
+			return
+		}
+
+		response := dto.UserAuthLogsResponse{
+			Logs:       make([]dto.UserAuthLogResponse, 0, len(logs)),
+			TotalCount: int(total),
+		}
+		for _, l := range logs {
+			response.Logs = append(response.Logs, dto.UserAuthLogResponse{
+				Id:           l.Id,
+				UserId:       l.UserId,
+				AuthType:     l.AuthType,
+				IPAddress:    l.IPAddress,
+				UserAgent:    l.UserAgent,
+				Success:      l.Success,
+				ErrorMessage: l.ErrorMessage,
+				CreatedAt:    l.CreatedAt,
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Auth logs retrieved successfully"))
+	}
+}
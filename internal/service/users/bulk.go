@@ -0,0 +1,250 @@
+package users
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/service/audit"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxBulkUsers limita as linhas por importação; cada senha passa pelo bcrypt
+const maxBulkUsers = 200
+
+// bulkCSVColumns são as colunas aceitas no CSV; name, email e userType são obrigatórias
+var bulkCSVColumns = []string{"name", "email", "password", "userType", "microsoftId", "companyId"}
+
+// BulkCreateUsers importa usuários em lote
+// @Summary      Importar Usuários
+// @Description  Cria usuários a partir de um array JSON (application/json) ou de um CSV (text/csv) com cabeçalho name,email,password,userType,microsoftId,companyId. Cada linha é validada e criada de forma independente: linhas sem senha precisam de microsoftId e viram contas apenas Microsoft. Retorna o resultado por linha e registra a importação na auditoria. Máximo de 200 linhas.
+// @Tags         users
+// @Accept       json
+// @Accept       text/csv
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        users body []dto.CreateUserRequest true "Usuários a importar (ou CSV)"
+// @Success      200 {object} dto.SuccessResponse{data=dto.BulkImportUsersResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 413 {object} dto.ErrorResponse "Too many rows"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Cota diária de importações esgotada"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/bulk [post]
+func BulkCreateUsers(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			rows []dto.CreateUserRequest
+			err  error
+		)
+		if strings.HasPrefix(c.ContentType(), "text/csv") {
+			rows, err = parseUsersCSV(c.Request.Body)
+		} else {
+			err = json.NewDecoder(c.Request.Body).Decode(&rows)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid import file", err.Error()))
+			return
+		}
+
+		if len(rows) == 0 {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "No users to import", nil))
+			return
+		}
+		if len(rows) > maxBulkUsers {
+			c.JSON(http.StatusRequestEntityTooLarge, dto.NewErrorResponse(c, http.StatusRequestEntityTooLarge, "Request Entity Too Large",
+				fmt.Sprintf("At most %d users can be imported at once", maxBulkUsers), nil))
+			return
+		}
+
+		var createdBy *int
+		if currentUserId, ok := middleware.GetCurrentUserID(c); ok {
+			createdBy = &currentUserId
+		}
+
+		response := dto.BulkImportUsersResponse{
+			Total:   len(rows),
+			Results: make([]dto.BulkUserResult, 0, len(rows)),
+		}
+		seen := make(map[string]int, len(rows))
+		createdIds := make([]int, 0, len(rows))
+
+		for i := range rows {
+			req := rows[i]
+			result := dto.BulkUserResult{Row: i + 1, Email: req.Email}
+
+			if fields := middleware.ValidateStruct(c, &req); fields != nil {
+				result.Error = "Validation failed"
+				result.Fields = fields
+			} else if violations := rowPasswordViolations(&req); len(violations) > 0 {
+				result.Error = "Password does not meet the password policy"
+				result.Fields = middleware.RuleErrors(c, "password", violations)
+			} else if req.Password == nil && req.MicrosoftId == nil {
+				result.Error = "Either password or microsoftId must be provided"
+			} else if first, dup := seen[strings.ToLower(req.Email)]; dup {
+				result.Error = fmt.Sprintf("Email repeated from row %d", first)
+			} else {
+				seen[strings.ToLower(req.Email)] = result.Row
+				id, err := importUser(c, cfg, &req, createdBy)
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Success = true
+					result.Id = &id
+					createdIds = append(createdIds, id)
+				}
+			}
+
+			if result.Success {
+				response.Created++
+			} else {
+				response.Failed++
+			}
+			response.Results = append(response.Results, result)
+		}
+
+		audit.Record(c, cfg, audit.ActionImport, audit.EntityUser, "bulk", nil, gin.H{
+			"total":      response.Total,
+			"created":    response.Created,
+			"failed":     response.Failed,
+			"createdIds": createdIds,
+		})
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Users import finished"))
+	}
+}
+
+// importUser cria o usuário de uma linha já validada; o erro retornado vai para o resultado da linha
+func importUser(c *gin.Context, cfg *config.App, req *dto.CreateUserRequest, createdBy *int) (int, error) {
+	existingUser, _ := cfg.SqlServer.GetUserByEmail(c.Request.Context(), req.Email)
+	if existingUser != nil {
+		return 0, errors.New("Email already exists")
+	}
+
+	var passwordHash *string
+	if req.Password != nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return 0, errors.New("Failed to hash password")
+		}
+		hashStr := string(hash)
+		passwordHash = &hashStr
+	}
+
+	user := &entities.User{
+		Name:         req.Name,
+		Email:        req.Email,
+		PasswordHash: passwordHash,
+		UserType:     req.UserType,
+		MicrosoftId:  req.MicrosoftId,
+		CompanyId:    req.CompanyId,
+		IsActive:     true,
+		CreatedBy:    createdBy,
+	}
+
+	id, err := cfg.SqlServer.CreateUser(c.Request.Context(), user)
+	if err != nil {
+		return 0, errors.New("Failed to create user")
+	}
+	if passwordHash != nil {
+		recordPasswordHistory(c.Request.Context(), cfg, id, *passwordHash)
+	}
+	return id, nil
+}
+
+// rowPasswordViolations aplica a política de senhas à senha da linha, quando houver
+func rowPasswordViolations(req *dto.CreateUserRequest) []middleware.RuleViolation {
+	if req.Password == nil {
+		return nil
+	}
+	return passwordPolicy.Check(*req.Password, req.Name, req.Email)
+}
+
+// parseUsersCSV lê o CSV da importação. A primeira linha é o cabeçalho, em qualquer ordem;
+// células vazias dos campos opcionais ficam nil.
+func parseUsersCSV(r io.Reader) ([]dto.CreateUserRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		known := false
+		for _, column := range bulkCSVColumns {
+			if strings.EqualFold(name, column) {
+				columns[column] = i
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	for _, column := range []string{"name", "email", "userType"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("missing column %q", column)
+		}
+	}
+
+	var rows []dto.CreateUserRequest
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		optional := func(column string) *string {
+			if value := cell(column); value != "" {
+				return &value
+			}
+			return nil
+		}
+
+		row := dto.CreateUserRequest{
+			Name:        cell("name"),
+			Email:       cell("email"),
+			Password:    optional("password"),
+			UserType:    strings.ToUpper(cell("userType")),
+			MicrosoftId: optional("microsoftId"),
+		}
+		if value := cell("companyId"); value != "" {
+			companyId, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid companyId %q", line, value)
+			}
+			row.CompanyId = &companyId
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
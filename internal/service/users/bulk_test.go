@@ -0,0 +1,57 @@
+package users
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUsersCSV(t *testing.T) {
+	t.Run("reads rows in header order", func(t *testing.T) {
+		input := "email,name,userType,password,microsoftId,companyId\n" +
+			"ana@example.com,Ana Souza,agent,Senha@1234,,12\n" +
+			"bia@example.com,Bia Lima,VIEWER,,ms-123,\n"
+
+		rows, err := parseUsersCSV(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		assert.Equal(t, "Ana Souza", rows[0].Name)
+		assert.Equal(t, "ana@example.com", rows[0].Email)
+		assert.Equal(t, "AGENT", rows[0].UserType)
+		require.NotNil(t, rows[0].Password)
+		assert.Equal(t, "Senha@1234", *rows[0].Password)
+		assert.Nil(t, rows[0].MicrosoftId)
+		require.NotNil(t, rows[0].CompanyId)
+		assert.Equal(t, int64(12), *rows[0].CompanyId)
+
+		assert.Nil(t, rows[1].Password)
+		require.NotNil(t, rows[1].MicrosoftId)
+		assert.Equal(t, "ms-123", *rows[1].MicrosoftId)
+		assert.Nil(t, rows[1].CompanyId)
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "unknown column", input: "name,email,userType,role\n", wantErr: `unknown column "role"`},
+		{name: "missing column", input: "name,email\n", wantErr: `missing column "userType"`},
+		{name: "invalid company", input: "name,email,userType,companyId\nAna,ana@example.com,AGENT,abc\n", wantErr: `line 2: invalid companyId "abc"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseUsersCSV(strings.NewReader(tt.input))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("empty input", func(t *testing.T) {
+		rows, err := parseUsersCSV(strings.NewReader(""))
+		assert.NoError(t, err)
+		assert.Empty(t, rows)
+	})
+}
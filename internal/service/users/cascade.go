@@ -0,0 +1,220 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"orderstreamrest/internal/service/notifications"
+	"os"
+	"strings"
+)
+
+// CascadePolicy define o que acontece com os registros vinculados a um usuário removido
+type CascadePolicy string
+
+const (
+	CascadeDelete    CascadePolicy = "DELETE"
+	CascadeAnonymize CascadePolicy = "ANONYMIZE"
+)
+
+// cascadeHandler trata um tipo de entidade vinculada ao usuário
+type cascadeHandler struct {
+	// Nome da entidade, usado no relatório e na variável de ambiente da política
+	entity string
+	// Política aplicada quando nenhuma for configurada
+	defaultPolicy CascadePolicy
+	// Políticas suportadas pela entidade
+	policies []CascadePolicy
+	// external indica registros fora do SQL Server (Redis), que não entram na transação da remoção
+	// e são tratados depois que ela é confirmada
+	external bool
+	// Conta os registros afetados; repo é o SQL Server (ou a transação) onde os registros estão
+	count func(ctx context.Context, cfg *config.App, repo *sqlserver.Internal, userId int) (int64, error)
+	// Aplica a política e retorna a quantidade de registros afetados
+	apply func(ctx context.Context, cfg *config.App, repo *sqlserver.Internal, policy CascadePolicy, userId int) (int64, error)
+}
+
+// cascadeHandlers contém os handlers de todas as entidades vinculadas ao usuário
+var cascadeHandlers []cascadeHandler
+
+// registerCascadeHandler registra um handler de cascata para uma entidade
+func registerCascadeHandler(h cascadeHandler) {
+	cascadeHandlers = append(cascadeHandlers, h)
+}
+
+func init() {
+	registerCascadeHandler(cascadeHandler{
+		entity:        "auth_logs",
+		defaultPolicy: CascadeAnonymize,
+		policies:      []CascadePolicy{CascadeDelete, CascadeAnonymize},
+		count: func(ctx context.Context, _ *config.App, repo *sqlserver.Internal, userId int) (int64, error) {
+			return repo.CountUserAuthLogs(ctx, userId)
+		},
+		apply: func(ctx context.Context, _ *config.App, repo *sqlserver.Internal, policy CascadePolicy, userId int) (int64, error) {
+			if policy == CascadeDelete {
+				return repo.DeleteUserAuthLogs(ctx, userId)
+			}
+			return repo.AnonymizeUserAuthLogs(ctx, userId)
+		},
+	})
+
+	// Hashes de senhas antigas não têm utilidade depois da remoção e não podem ser anonimizados
+	registerCascadeHandler(cascadeHandler{
+		entity:        "password_history",
+		defaultPolicy: CascadeDelete,
+		policies:      []CascadePolicy{CascadeDelete},
+		count: func(ctx context.Context, _ *config.App, repo *sqlserver.Internal, userId int) (int64, error) {
+			return repo.CountUserPasswordHistory(ctx, userId)
+		},
+		apply: func(ctx context.Context, _ *config.App, repo *sqlserver.Internal, _ CascadePolicy, userId int) (int64, error) {
+			return repo.DeleteUserPasswordHistory(ctx, userId)
+		},
+	})
+
+	registerCascadeHandler(cascadeHandler{
+		entity:        "two_factor",
+		defaultPolicy: CascadeDelete,
+		policies:      []CascadePolicy{CascadeDelete},
+		count: func(ctx context.Context, _ *config.App, repo *sqlserver.Internal, userId int) (int64, error) {
+			return repo.CountUserTwoFactor(ctx, userId)
+		},
+		apply: func(ctx context.Context, _ *config.App, repo *sqlserver.Internal, _ CascadePolicy, userId int) (int64, error) {
+			return repo.DeleteUserTwoFactor(ctx, userId)
+		},
+	})
+
+	registerCascadeHandler(cascadeHandler{
+		entity:        "saved_searches",
+		defaultPolicy: CascadeDelete,
+		policies:      []CascadePolicy{CascadeDelete},
+		count: func(ctx context.Context, _ *config.App, repo *sqlserver.Internal, userId int) (int64, error) {
+			return repo.CountUserSavedSearches(ctx, userId)
+		},
+		apply: func(ctx context.Context, _ *config.App, repo *sqlserver.Internal, _ CascadePolicy, userId int) (int64, error) {
+			return repo.DeleteUserSavedSearches(ctx, userId)
+		},
+	})
+
+	// Observações de tickets e notificações ficam no Redis
+	registerCascadeHandler(cascadeHandler{
+		entity:        "ticket_watches",
+		defaultPolicy: CascadeDelete,
+		policies:      []CascadePolicy{CascadeDelete},
+		external:      true,
+		count: func(ctx context.Context, cfg *config.App, _ *sqlserver.Internal, userId int) (int64, error) {
+			return notifications.CountUserWatches(ctx, cfg, userId)
+		},
+		apply: func(ctx context.Context, cfg *config.App, _ *sqlserver.Internal, _ CascadePolicy, userId int) (int64, error) {
+			return notifications.UnwatchAll(ctx, cfg, userId)
+		},
+	})
+
+	registerCascadeHandler(cascadeHandler{
+		entity:        "notifications",
+		defaultPolicy: CascadeDelete,
+		policies:      []CascadePolicy{CascadeDelete},
+		external:      true,
+		count: func(ctx context.Context, cfg *config.App, _ *sqlserver.Internal, userId int) (int64, error) {
+			return notifications.CountNotifications(ctx, cfg, userId)
+		},
+		apply: func(ctx context.Context, cfg *config.App, _ *sqlserver.Internal, _ CascadePolicy, userId int) (int64, error) {
+			return notifications.DeleteNotifications(ctx, cfg, userId)
+		},
+	})
+}
+
+// policy retorna a política configurada para a entidade (USER_CASCADE_POLICY_<ENTITY>)
+func (h cascadeHandler) policy() (CascadePolicy, error) {
+	envName := "USER_CASCADE_POLICY_" + strings.ToUpper(h.entity)
+
+	value := strings.ToUpper(strings.TrimSpace(os.Getenv(envName)))
+	if value == "" {
+		return h.defaultPolicy, nil
+	}
+
+	for _, p := range h.policies {
+		if string(p) == value {
+			return p, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid cascade policy %q for %s", value, h.entity)
+}
+
+// cascadePolicies resolve e valida as políticas de todas as entidades antes de alterar qualquer registro
+func cascadePolicies() ([]CascadePolicy, error) {
+	policies := make([]CascadePolicy, len(cascadeHandlers))
+	for i, h := range cascadeHandlers {
+		policy, err := h.policy()
+		if err != nil {
+			return nil, err
+		}
+		policies[i] = policy
+	}
+	return policies, nil
+}
+
+// previewCascade conta os registros que a remoção do usuário afetaria, sem alterar nada
+func previewCascade(ctx context.Context, cfg *config.App, userId int, policies []CascadePolicy) (*dto.CascadeReportResponse, error) {
+	report := newCascadeReport(userId, true)
+	for i, h := range cascadeHandlers {
+		affected, err := h.count(ctx, cfg, cfg.SqlServer, userId)
+		if err != nil {
+			return nil, fmt.Errorf("cascade %s: %w", h.entity, err)
+		}
+		report.Items = append(report.Items, dto.CascadeItemReport{Entity: h.entity, Policy: string(policies[i]), Affected: affected})
+	}
+	return report, nil
+}
+
+// deleteUserCascade aplica as políticas de cascata e remove os dados pessoais do usuário na mesma
+// transação: se qualquer etapa falhar nada é alterado no SQL Server. Os registros do Redis só são
+// tratados depois da confirmação; uma falha neles não desfaz a remoção e fica no relatório.
+func deleteUserCascade(ctx context.Context, cfg *config.App, userId, deletedBy int, policies []CascadePolicy) (*dto.CascadeReportResponse, error) {
+	report := newCascadeReport(userId, false)
+	items := make([]dto.CascadeItemReport, len(cascadeHandlers))
+
+	err := cfg.SqlServer.Transaction(ctx, func(tx *sqlserver.Internal) error {
+		for i, h := range cascadeHandlers {
+			if h.external {
+				continue
+			}
+			affected, err := h.apply(ctx, cfg, tx, policies[i], userId)
+			if err != nil {
+				return fmt.Errorf("cascade %s: %w", h.entity, err)
+			}
+			items[i] = dto.CascadeItemReport{Entity: h.entity, Policy: string(policies[i]), Affected: affected}
+		}
+		return tx.DeleteUser(ctx, userId, deletedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, h := range cascadeHandlers {
+		if !h.external {
+			continue
+		}
+		items[i] = dto.CascadeItemReport{Entity: h.entity, Policy: string(policies[i])}
+		affected, err := h.apply(ctx, cfg, cfg.SqlServer, policies[i], userId)
+		if err != nil {
+			cfg.Logger.Warn(fmt.Sprintf("Cascade %s of deleted user %d failed: %v", h.entity, userId, err))
+			items[i].Error = err.Error()
+		}
+		items[i].Affected = affected
+	}
+
+	report.Items = items
+	return report, nil
+}
+
+// newCascadeReport cria o relatório de cascata vazio do usuário
+func newCascadeReport(userId int, dryRun bool) *dto.CascadeReportResponse {
+	return &dto.CascadeReportResponse{
+		UserId: userId,
+		DryRun: dryRun,
+		Items:  make([]dto.CascadeItemReport, 0, len(cascadeHandlers)),
+	}
+}
@@ -0,0 +1,51 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCascadeHandlersCoverRedisRecords(t *testing.T) {
+	external := map[string]bool{}
+	for _, h := range cascadeHandlers {
+		external[h.entity] = h.external
+	}
+
+	assert.Equal(t, map[string]bool{
+		"auth_logs":        false,
+		"password_history": false,
+		"two_factor":       false,
+		"saved_searches":   false,
+		"ticket_watches":   true,
+		"notifications":    true,
+	}, external)
+}
+
+func TestCascadePolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		expected CascadePolicy
+		wantErr  bool
+	}{
+		{name: "default policy", env: "", expected: CascadeAnonymize},
+		{name: "configured policy in any case", env: " delete ", expected: CascadeDelete},
+		{name: "unsupported policy", env: "REASSIGN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("USER_CASCADE_POLICY_AUTH_LOGS", tt.env)
+
+			policies, err := cascadePolicies()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, policies[0])
+			assert.Len(t, policies, len(cascadeHandlers))
+		})
+	}
+}
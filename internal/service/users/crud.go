@@ -1,20 +1,27 @@
 package users
 
 import (
+	"log"
 	"net/http"
 	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
 	"orderstreamrest/internal/models/dto"
 	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/service/audit"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	defaultUsersPageSize = 10
+	maxUsersPageSize     = 100
+)
+
 // CreateUser cria um novo usuário
 // @Summary      Criar Usuário
-// @Description  Cria um novo usuário no sistema
+// @Description  Cria um novo usuário no sistema. A senha deve seguir a política de senhas (tamanho, classes de caracteres, senhas comuns e dados pessoais)
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -22,6 +29,7 @@ import (
 // @Param        user body dto.CreateUserRequest true "Dados do usuário"
 // @Success      201 {object} dto.SuccessResponse{data=dto.UserCreatedResponse}
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
 // @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
 // @Failure 	 409 {object} dto.ErrorResponse "Conflict - Email já existe"
@@ -30,46 +38,27 @@ import (
 func CreateUser(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req dto.CreateUserRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			})
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
 			return
 		}
 
 		// Validar que pelo menos senha ou MicrosoftId foi fornecido
 		if req.Password == nil && req.MicrosoftId == nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "Either password or microsoftId must be provided",
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Either password or microsoftId must be provided", nil))
 			return
 		}
 
+		if req.Password != nil {
+			if violations := passwordPolicy.Check(*req.Password, req.Name, req.Email); len(violations) > 0 {
+				middleware.RespondRuleErrors(c, "password", violations)
+				return
+			}
+		}
+
 		// Verificar se email já existe
 		existingUser, _ := cfg.SqlServer.GetUserByEmail(c.Request.Context(), req.Email)
 		if existingUser != nil {
-			c.JSON(http.StatusConflict, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Conflict",
-				Code:    http.StatusConflict,
-				Message: "Email already exists",
-			})
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(c, http.StatusConflict, "Conflict", "Email already exists", nil))
 			return
 		}
 
@@ -78,27 +67,17 @@ func CreateUser(cfg *config.App) gin.HandlerFunc {
 		if req.Password != nil {
 			hash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-					BaseResponse: dto.BaseResponse{
-						Success:   false,
-						Timestamp: time.Now(),
-					},
-					Error:   "Internal Server Error",
-					Code:    http.StatusInternalServerError,
-					Message: "Failed to hash password",
-					Details: err.Error(),
-				})
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to hash password", err.Error()))
 				return
 			}
 			hashStr := string(hash)
 			passwordHash = &hashStr
 		}
 
-		// Pegar ID do usuário autenticado (assumindo que está no contexto)
-		currentUserId, _ := c.Get("user_id")
+		// Pegar ID do usuário autenticado a partir do JWT
 		var createdBy *int
-		if id, ok := currentUserId.(int); ok {
-			createdBy = &id
+		if currentUserId, ok := middleware.GetCurrentUserID(c); ok {
+			createdBy = &currentUserId
 		}
 
 		user := &entities.User{
@@ -107,36 +86,27 @@ func CreateUser(cfg *config.App) gin.HandlerFunc {
 			PasswordHash: passwordHash,
 			UserType:     req.UserType,
 			MicrosoftId:  req.MicrosoftId,
+			CompanyId:    req.CompanyId,
 			IsActive:     true,
 			CreatedBy:    createdBy,
 		}
 
 		id, err := cfg.SqlServer.CreateUser(c.Request.Context(), user)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to create user",
-				Details: err.Error(),
-			})
+			middleware.RespondError(c, err, "Failed to create user")
 			return
 		}
 
-		c.JSON(http.StatusCreated, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data: dto.UserCreatedResponse{
-				Id:      id,
-				Message: "User created successfully",
-			},
+		if passwordHash != nil {
+			recordPasswordHistory(c.Request.Context(), cfg, id, *passwordHash)
+		}
+
+		audit.Record(c, cfg, audit.ActionCreate, audit.EntityUser, strconv.Itoa(id), nil, user)
+
+		c.JSON(http.StatusCreated, dto.NewSuccessResponse(c, dto.UserCreatedResponse{
+			Id:      id,
 			Message: "User created successfully",
-		})
+		}, "User created successfully"))
 	}
 }
 
@@ -159,129 +129,52 @@ func GetUser(cfg *config.App) gin.HandlerFunc {
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "Invalid user ID",
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid user ID", nil))
 			return
 		}
 
 		user, err := cfg.SqlServer.GetUserByID(c.Request.Context(), id)
 		if err != nil {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Not Found",
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-				Details: err.Error(),
-			})
+			middleware.RespondError(c, err, "Failed to retrieve user")
 			return
 		}
 
-		response := dto.UserResponse{
-			Id:          user.Id,
-			Name:        user.Name,
-			Email:       user.Email,
-			UserType:    user.UserType,
-			MicrosoftId: user.MicrosoftId,
-			IsActive:    user.IsActive,
-			CreatedAt:   user.CreatedAt,
-			UpdatedAt:   user.UpdatedAt,
-			LastLoginAt: user.LastLoginAt,
-		}
-
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data:    response,
-			Message: "User retrieved successfully",
-		})
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.NewUserResponse(*user), "User retrieved successfully"))
 	}
 }
 
 // GetAllUsers lista todos os usuários com paginação
 // @Summary      Listar Usuários
-// @Description  Retorna lista de usuários com paginação
+// @Description  Retorna lista de usuários com paginação; pagination.links traz os endereços da próxima página e da anterior
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Security 	 BearerAuth
 // @Param        page query int false "Número da página" default(1)
-// @Param        pageSize query int false "Tamanho da página" default(10)
+// @Param        pageSize query int false "Tamanho da página (máximo 100)" default(10)
 // @Param        onlyActive query bool false "Apenas usuários ativos" default(false)
-// @Success      200 {object} dto.SuccessResponse{data=dto.UsersListResponse}
+// @Success      200 {object} dto.PaginatedResponse{data=[]dto.UserResponse}
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
 // @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
 // @Router       /users [get]
 func GetAllUsers(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-		pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+		page := middleware.ParsePage(c, defaultUsersPageSize, maxUsersPageSize)
 		onlyActive, _ := strconv.ParseBool(c.DefaultQuery("onlyActive", "false"))
 
-		if page < 1 {
-			page = 1
-		}
-		if pageSize < 1 || pageSize > 100 {
-			pageSize = 10
-		}
-
-		users, totalCount, err := cfg.SqlServer.GetAllUsers(c.Request.Context(), page, pageSize, onlyActive)
+		users, totalCount, err := cfg.SqlServer.GetAllUsers(c.Request.Context(), page.Number, page.Size, onlyActive)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to retrieve users",
-				Details: err.Error(),
-			})
+			middleware.RespondError(c, err, "Failed to retrieve users")
 			return
 		}
 
-		var userResponses []dto.UserResponse
+		userResponses := make([]dto.UserResponse, 0, len(users))
 		for _, user := range users {
-			userResponses = append(userResponses, dto.UserResponse{
-				Id:          user.Id,
-				Name:        user.Name,
-				Email:       user.Email,
-				UserType:    user.UserType,
-				MicrosoftId: user.MicrosoftId,
-				IsActive:    user.IsActive,
-				CreatedAt:   user.CreatedAt,
-				UpdatedAt:   user.UpdatedAt,
-				LastLoginAt: user.LastLoginAt,
-			})
-		}
-
-		response := dto.UsersListResponse{
-			Users:      userResponses,
-			TotalCount: int(totalCount),
-			Page:       page,
-			PageSize:   pageSize,
-		}
-
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data:    response,
-			Message: "Users retrieved successfully",
-		})
+			userResponses = append(userResponses, dto.NewUserResponse(user))
+		}
+
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(c, userResponses, middleware.NewPagination(c, page, totalCount), "Users retrieved successfully"))
 	}
 }
 
@@ -296,6 +189,7 @@ func GetAllUsers(cfg *config.App) gin.HandlerFunc {
 // @Param        user body dto.UpdateUserRequest true "Dados para atualização"
 // @Success      200 {object} dto.SuccessResponse
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
 // @Failure 	 404 {object} dto.ErrorResponse "Not Found"
 // @Failure 	 409 {object} dto.ErrorResponse "Conflict"
@@ -306,45 +200,19 @@ func UpdateUser(cfg *config.App) gin.HandlerFunc {
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "Invalid user ID",
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid user ID", nil))
 			return
 		}
 
 		var req dto.UpdateUserRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			})
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
 			return
 		}
 
 		// Buscar usuário existente
 		user, err := cfg.SqlServer.GetUserByID(c.Request.Context(), id)
 		if err != nil {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Not Found",
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-			})
+			middleware.RespondError(c, err, "Failed to retrieve user")
 			return
 		}
 
@@ -352,19 +220,13 @@ func UpdateUser(cfg *config.App) gin.HandlerFunc {
 		if req.Email != nil && *req.Email != user.Email {
 			existingUser, _ := cfg.SqlServer.GetUserByEmail(c.Request.Context(), *req.Email)
 			if existingUser != nil && existingUser.Id != id {
-				c.JSON(http.StatusConflict, dto.ErrorResponse{
-					BaseResponse: dto.BaseResponse{
-						Success:   false,
-						Timestamp: time.Now(),
-					},
-					Error:   "Conflict",
-					Code:    http.StatusConflict,
-					Message: "Email already in use",
-				})
+				c.JSON(http.StatusConflict, dto.NewErrorResponse(c, http.StatusConflict, "Conflict", "Email already in use", nil))
 				return
 			}
 		}
 
+		before := *user
+
 		// Atualizar campos se fornecidos
 		if req.Name != nil {
 			user.Name = *req.Name
@@ -378,69 +240,61 @@ func UpdateUser(cfg *config.App) gin.HandlerFunc {
 		if req.IsActive != nil {
 			user.IsActive = *req.IsActive
 		}
+		if req.CompanyId != nil {
+			user.CompanyId = req.CompanyId
+		}
 
-		// Pegar ID do usuário autenticado
-		currentUserId, _ := c.Get("user_id")
-		if uid, ok := currentUserId.(int); ok {
-			user.UpdatedBy = &uid
+		// Pegar ID do usuário autenticado a partir do JWT
+		if currentUserId, ok := middleware.GetCurrentUserID(c); ok {
+			user.UpdatedBy = &currentUserId
 		}
 
 		// Atualizar senha se fornecida
 		if req.Password != nil {
+			violations := passwordPolicy.Check(*req.Password, user.Name, user.Email)
+			if len(violations) == 0 {
+				violations, err = checkPasswordReuse(c.Request.Context(), cfg, id, *req.Password, user.PasswordHash)
+				if err != nil {
+					middleware.RespondError(c, err, "Failed to check password history")
+					return
+				}
+			}
+			if len(violations) > 0 {
+				middleware.RespondRuleErrors(c, "password", violations)
+				return
+			}
+
 			hash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-					BaseResponse: dto.BaseResponse{
-						Success:   false,
-						Timestamp: time.Now(),
-					},
-					Error:   "Internal Server Error",
-					Code:    http.StatusInternalServerError,
-					Message: "Failed to hash password",
-					Details: err.Error(),
-				})
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to hash password", err.Error()))
 				return
 			}
 
 			if user.UpdatedBy != nil {
 				if err := cfg.SqlServer.UpdatePassword(c.Request.Context(), id, string(hash), *user.UpdatedBy); err != nil {
-					c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-						BaseResponse: dto.BaseResponse{
-							Success:   false,
-							Timestamp: time.Now(),
-						},
-						Error:   "Internal Server Error",
-						Code:    http.StatusInternalServerError,
-						Message: "Failed to update password",
-						Details: err.Error(),
-					})
+					middleware.RespondError(c, err, "Failed to update password")
 					return
 				}
+				recordPasswordHistory(c.Request.Context(), cfg, id, string(hash))
 			}
 		}
 
 		// Atualizar usuário
 		if err := cfg.SqlServer.UpdateUser(c.Request.Context(), id, user); err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to update user",
-				Details: err.Error(),
-			})
+			middleware.RespondError(c, err, "Failed to update user")
 			return
 		}
 
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Message: "User updated successfully",
-		})
+		// Desativar pelo PUT também encerra as sessões do usuário
+		if before.IsActive && !user.IsActive {
+			if err := middleware.RevokeUserTokens(c.Request.Context(), id); err != nil {
+				log.Printf("Failed to revoke tokens of user %d: %v", id, err)
+			}
+		}
+
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntityUser, strconv.Itoa(id), &before, user)
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, nil, "User updated successfully"))
 	}
 }
 
@@ -454,6 +308,7 @@ func UpdateUser(cfg *config.App) gin.HandlerFunc {
 // @Param        request body dto.ChangePasswordRequest true "Senha atual e nova senha"
 // @Success      200 {object} dto.SuccessResponse
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
 // @Failure 	 403 {object} dto.ErrorResponse "Current password incorrect"
 // @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
@@ -461,130 +316,77 @@ func UpdateUser(cfg *config.App) gin.HandlerFunc {
 func ChangePassword(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req dto.ChangePasswordRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			})
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
 			return
 		}
 
 		// Pegar ID do usuário autenticado
-		currentUserId, exists := c.Get("user_id")
+		userId, exists := middleware.GetCurrentUserID(c)
 		if !exists {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Unauthorized",
-				Code:    http.StatusUnauthorized,
-				Message: "User not authenticated",
-			})
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated", nil))
 			return
 		}
 
-		userId := currentUserId.(int)
-
 		// Buscar usuário
 		user, err := cfg.SqlServer.GetUserByID(c.Request.Context(), userId)
 		if err != nil {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Not Found",
-				Code:    http.StatusNotFound,
-				Message: "User not found",
-			})
+			middleware.RespondError(c, err, "Failed to retrieve user")
 			return
 		}
 
 		// Verificar senha atual
 		if user.PasswordHash == nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "User does not have a password (uses Microsoft authentication)",
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "User does not have a password (uses Microsoft authentication)", nil))
 			return
 		}
 
 		err = bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(req.CurrentPassword))
 		if err != nil {
-			c.JSON(http.StatusForbidden, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Forbidden",
-				Code:    http.StatusForbidden,
-				Message: "Current password is incorrect",
-			})
+			c.JSON(http.StatusForbidden, dto.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "Current password is incorrect", nil))
+			return
+		}
+
+		violations := passwordPolicy.Check(req.NewPassword, user.Name, user.Email)
+		if len(violations) == 0 {
+			violations, err = checkPasswordReuse(c.Request.Context(), cfg, userId, req.NewPassword, user.PasswordHash)
+			if err != nil {
+				middleware.RespondError(c, err, "Failed to check password history")
+				return
+			}
+		}
+		if len(violations) > 0 {
+			middleware.RespondRuleErrors(c, "newPassword", violations)
 			return
 		}
 
 		// Gerar hash da nova senha
 		hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to hash password",
-				Details: err.Error(),
-			})
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to hash password", err.Error()))
 			return
 		}
 
 		// Atualizar senha
 		if err := cfg.SqlServer.UpdatePassword(c.Request.Context(), userId, string(hash), userId); err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to update password",
-				Details: err.Error(),
-			})
+			middleware.RespondError(c, err, "Failed to update password")
 			return
 		}
+		recordPasswordHistory(c.Request.Context(), cfg, userId, string(hash))
 
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Message: "Password changed successfully",
-		})
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, nil, "Password changed successfully"))
 	}
 }
 
 // DeleteUser deleta (desativa) um usuário
 // @Summary      Deletar Usuário
-// @Description  Desativa um usuário do sistema (soft delete)
+// @Description  Remove definitivamente os dados pessoais do usuário, para pedidos de eliminação da LGPD, aplicando a política de cascata aos registros vinculados na mesma transação: se algo falhar, nada é alterado. Observações de tickets e notificações, guardadas no Redis, são apagadas em seguida; uma falha nelas aparece em error no relatório. Com dryRun=true apenas retorna o relatório do que seria afetado. Para apenas bloquear o acesso use PATCH /users/{id}/deactivate.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Security 	 BearerAuth
 // @Param        id path int true "ID do usuário"
-// @Success      200 {object} dto.SuccessResponse
+// @Param        dryRun query bool false "Apenas simular a remoção" default(false)
+// @Success      200 {object} dto.SuccessResponse{data=dto.CascadeReportResponse}
 // @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
 // @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
 // @Failure 	 404 {object} dto.ErrorResponse "Not Found"
@@ -595,59 +397,58 @@ func DeleteUser(cfg *config.App) gin.HandlerFunc {
 		idParam := c.Param("id")
 		id, err := strconv.Atoi(idParam)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "Invalid user ID",
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid user ID", nil))
 			return
 		}
 
-		// Pegar ID do usuário autenticado
-		currentUserId, _ := c.Get("user_id")
-		var deletedBy int
-		if uid, ok := currentUserId.(int); ok {
-			deletedBy = uid
-		}
+		// Pegar ID do usuário autenticado a partir do JWT
+		deletedBy, _ := middleware.GetCurrentUserID(c)
 
 		// Não permitir que usuário delete a si mesmo
 		if deletedBy == id {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "User cannot delete themselves",
-			})
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "User cannot delete themselves", nil))
 			return
 		}
 
-		if err := cfg.SqlServer.DeleteUser(c.Request.Context(), id, deletedBy); err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to delete user",
-				Details: err.Error(),
-			})
+		dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+		ctx := c.Request.Context()
+
+		// Estado anterior para a trilha de auditoria, já que a remoção apaga os dados pessoais.
+		// Confirma também que o usuário existe antes de tocar nos registros vinculados.
+		before, err := cfg.SqlServer.GetUserByID(ctx, id)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to delete user")
 			return
 		}
 
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Message: "User deleted successfully",
-		})
+		policies, err := cascadePolicies()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to process linked records", err.Error()))
+			return
+		}
+
+		if dryRun {
+			report, err := previewCascade(ctx, cfg, id, policies)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to process linked records", err.Error()))
+				return
+			}
+			c.JSON(http.StatusOK, dto.NewSuccessResponse(c, report, "Dry run completed, no records were changed"))
+			return
+		}
+
+		report, err := deleteUserCascade(ctx, cfg, id, deletedBy, policies)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to delete user")
+			return
+		}
+
+		if err := middleware.RevokeUserTokens(c.Request.Context(), id); err != nil {
+			log.Printf("Failed to revoke tokens of user %d: %v", id, err)
+		}
+
+		audit.Record(c, cfg, audit.ActionDelete, audit.EntityUser, strconv.Itoa(id), before, nil)
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, report, "User deleted successfully"))
 	}
 }
@@ -0,0 +1,149 @@
+package users
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/service/audit"
+	"orderstreamrest/internal/service/export"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// usersExportFlushEvery é quantos usuários são escritos entre os envios ao cliente
+const usersExportFlushEvery = 500
+
+// userCSVHeader são as colunas do CSV de usuários. Hash da senha e identidade Microsoft nunca são exportados.
+var userCSVHeader = []string{"id", "name", "email", "user_type", "company_id", "is_active", "created_at", "updated_at", "last_login_at"}
+
+// ExportUsers envia a lista de usuários em CSV
+// @Summary      Exportar Usuários
+// @Description  Envia em CSV todos os usuários, com o mesmo filtro de GET /users e sem limite de página, para auditorias de conformidade. Colunas sensíveis (hash da senha, identidade Microsoft) não são exportadas. Cada exportação é registrada na trilha de auditoria com o autor, o filtro e a quantidade de usuários.
+// @Tags         users
+// @Produce      text/csv
+// @Security 	 BearerAuth
+// @Param        onlyActive query bool false "Apenas usuários ativos" default(false)
+// @Success      200 {string} string "CSV com um usuário por linha"
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 429 {object} dto.RateLimitErrorResponse "Cota diária de exportações esgotada"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Failure 	 503 {object} dto.RateLimitErrorResponse "Service Unavailable"
+// @Router       /users/export [get]
+func ExportUsers(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		onlyActive, err := strconv.ParseBool(c.DefaultQuery("onlyActive", "false"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid onlyActive filter", err.Error()))
+			return
+		}
+
+		var (
+			written int64
+			csvOut  *csv.Writer
+		)
+
+		// O cabeçalho só é enviado com o primeiro usuário, para que um erro antes disso ainda vire uma resposta JSON
+		start := func() error {
+			c.Header("Content-Type", export.CSVContentType)
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.Filename("users", export.FormatCSV, time.Now())))
+			c.Status(http.StatusOK)
+
+			writer, err := export.NewCSVWriter(c.Writer, userCSVHeader)
+			if err != nil {
+				return err
+			}
+			csvOut = writer
+			return nil
+		}
+
+		flush := func() {
+			if csvOut != nil {
+				csvOut.Flush()
+			}
+			c.Writer.Flush()
+		}
+
+		err = cfg.SqlServer.StreamUsers(c.Request.Context(), onlyActive, func(user entities.User) error {
+			if written == 0 {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+
+			if err := csvOut.Write(export.CSVRecord(userCSVRow(user))); err != nil {
+				return err
+			}
+
+			written++
+			if written%usersExportFlushEvery == 0 {
+				flush()
+				middleware.ReportProgress(c, written, 0)
+			}
+			return nil
+		})
+
+		audit.Record(c, cfg, audit.ActionExport, audit.EntityUser, "export", nil, gin.H{
+			"format":     export.FormatCSV,
+			"onlyActive": onlyActive,
+			"columns":    userCSVHeader,
+			"exported":   written,
+			"completed":  err == nil,
+		})
+
+		if err != nil {
+			// Depois que o envio começou o status não pode mais mudar
+			if written > 0 {
+				log.Printf("Users export interrupted after %d users: %v", written, err)
+				return
+			}
+			middleware.RespondError(c, err, "Failed to export users")
+			return
+		}
+
+		if written == 0 {
+			if err := start(); err != nil {
+				log.Printf("Failed to start users export: %v", err)
+				return
+			}
+		}
+		flush()
+		middleware.ReportProgress(c, written, written)
+	}
+}
+
+// userCSVRow retorna as colunas de userCSVHeader de um usuário, com as datas em RFC 3339 (UTC)
+func userCSVRow(user entities.User) []any {
+	var companyId any
+	if user.CompanyId != nil {
+		companyId = *user.CompanyId
+	}
+
+	return []any{
+		user.Id,
+		user.Name,
+		user.Email,
+		user.UserType,
+		companyId,
+		strconv.FormatBool(user.IsActive),
+		formatExportTime(&user.CreatedAt),
+		formatExportTime(user.UpdatedAt),
+		formatExportTime(user.LastLoginAt),
+	}
+}
+
+// formatExportTime formata a data em RFC 3339 (UTC); datas ausentes viram células vazias
+func formatExportTime(value *time.Time) any {
+	if value == nil || value.IsZero() {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,56 @@
+package users
+
+import (
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/service/export"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserCSVRow(t *testing.T) {
+	hash := "$2a$10$hash"
+	microsoftId := "ms-123"
+	companyId := int64(12)
+	loginAt := time.Date(2025, 10, 16, 9, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+	tests := []struct {
+		name     string
+		user     entities.User
+		expected []string
+	}{
+		{
+			name: "Every column filled",
+			user: entities.User{
+				Id:           7,
+				Name:         "Ana Souza",
+				Email:        "ana@example.com",
+				PasswordHash: &hash,
+				UserType:     "AGENT",
+				MicrosoftId:  &microsoftId,
+				CompanyId:    &companyId,
+				IsActive:     true,
+				CreatedAt:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				LastLoginAt:  &loginAt,
+			},
+			expected: []string{"7", "Ana Souza", "ana@example.com", "AGENT", "12", "true", "2025-01-02T03:04:05Z", "", "2025-10-16T12:30:00Z"},
+		},
+		{
+			name:     "Optional columns empty",
+			user:     entities.User{Id: 8, Name: "Bia", Email: "bia@example.com", UserType: "VIEWER"},
+			expected: []string{"8", "Bia", "bia@example.com", "VIEWER", "", "false", "", "", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := export.CSVRecord(userCSVRow(tt.user))
+			require.Len(t, record, len(userCSVHeader))
+			assert.Equal(t, tt.expected, record)
+			assert.NotContains(t, record, hash)
+			assert.NotContains(t, record, microsoftId)
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"orderstreamrest/internal/config"
 	"orderstreamrest/internal/middleware"
 	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,13 +16,15 @@ import (
 
 // Login autentica um usuário e retorna um JWT token
 // @Summary      Login
-// @Description  Autentica um usuário com email e senha e retorna um JWT token
+// @Description  Autentica um usuário com email e senha e retorna um JWT token. Quando o usuário tem 2FA ativo, responde 202 com um challengeToken, a ser concluído em POST /auth/2fa/login.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        credentials body dto.LoginRequest true "Credenciais de login"
 // @Success      200 {object} dto.SuccessResponse{data=dto.LoginResponse}
+// @Success      202 {object} dto.SuccessResponse{data=dto.TwoFactorChallengeResponse} "Two-factor authentication required"
 // @Failure      400 {object} dto.ErrorResponse "Bad Request - Dados inválidos"
+// @Failure      422 {object} dto.ValidationErrorResponse "Validation Failed"
 // @Failure      401 {object} dto.ErrorResponse "Unauthorized - Credenciais inválidas"
 // @Failure      403 {object} dto.ErrorResponse "Forbidden - Usuário inativo"
 // @Failure      500 {object} dto.ErrorResponse "Internal Server Error"
@@ -28,130 +32,106 @@ import (
 func Login(cfg *config.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req dto.LoginRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "Invalid request body",
-				Details: err.Error(),
-			})
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
 			return
 		}
 
 		// Buscar usuário por email
 		user, err := cfg.SqlServer.GetUserByEmail(c.Request.Context(), req.Email)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Unauthorized",
-				Code:    http.StatusUnauthorized,
-				Message: "Invalid credentials",
-			})
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Invalid credentials", nil))
 			return
 		}
 
 		// Verificar se usuário está ativo
 		if !user.IsActive {
-			c.JSON(http.StatusForbidden, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Forbidden",
-				Code:    http.StatusForbidden,
-				Message: "User account is inactive",
-			})
+			recordAuthLog(c, cfg, user.Id, authTypeJWT, false, "User account is inactive")
+			c.JSON(http.StatusForbidden, dto.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "User account is inactive", nil))
 			return
 		}
 
 		// Verificar se usuário tem senha (não é apenas Microsoft Auth)
 		if user.PasswordHash == nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Bad Request",
-				Code:    http.StatusBadRequest,
-				Message: "User uses Microsoft authentication. Please use Microsoft login",
-			})
+			recordAuthLog(c, cfg, user.Id, authTypeJWT, false, "User uses Microsoft authentication")
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "User uses Microsoft authentication. Please use Microsoft login", nil))
 			return
 		}
 
 		// Verificar senha
 		err = bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(req.Password))
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Unauthorized",
-				Code:    http.StatusUnauthorized,
-				Message: "Invalid credentials",
-			})
+			recordAuthLog(c, cfg, user.Id, authTypeJWT, false, "Invalid credentials")
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Invalid credentials", nil))
 			return
 		}
 
-		// Gerar JWT token
-		token, err := middleware.GenerateJWT(int64(user.Id), user.Email, 1)
+		// Contas com 2FA recebem um desafio no lugar do token
+		required, err := twoFactorRequired(c.Request.Context(), cfg, user.Id)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				BaseResponse: dto.BaseResponse{
-					Success:   false,
-					Timestamp: time.Now(),
-				},
-				Error:   "Internal Server Error",
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to generate authentication token",
-				Details: err.Error(),
-			})
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to check two-factor authentication", err.Error()))
 			return
 		}
+		if required {
+			respondTwoFactorChallenge(c, cfg, user.Id)
+			return
+		}
+
+		issueLoginToken(c, cfg, user)
+	}
+}
 
-		// Atualizar LastLoginAt
-		now := time.Now()
-		user.LastLoginAt = &now
-		if err := cfg.SqlServer.UpdateUser(c.Request.Context(), user.Id, user); err != nil {
-			// Log error but don't fail the login
-			// A falha em atualizar LastLoginAt não deve impedir o login
-			log.Printf("Failed to update LastLoginAt for user %d: %v", user.Id, err)
+// issueLoginToken conclui o login: abre a sessão, emite o token e registra o acesso
+func issueLoginToken(c *gin.Context, cfg *config.App, user *entities.User) {
+	// Tipos de usuário desconhecidos não viram um token sem permissões
+	role, ok := utils.ParseRole(user.UserType)
+	if !ok {
+		recordAuthLog(c, cfg, user.Id, authTypeJWT, false, "Unknown user type")
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "User type is not recognized", user.UserType))
+		return
+	}
 
-		}
+	// Gerar JWT token
+	var companyID int64
+	if user.CompanyId != nil {
+		companyID = *user.CompanyId
+	}
 
-		// Calcular tempo de expiração (1 hora a partir de agora)
-		expiresAt := time.Now().Add(1 * time.Hour)
-
-		c.JSON(http.StatusOK, dto.SuccessResponse{
-			BaseResponse: dto.BaseResponse{
-				Success:   true,
-				Timestamp: time.Now(),
-			},
-			Data: dto.LoginResponse{
-				Token:     token,
-				TokenType: "Bearer",
-				ExpiresIn: 3600, // segundos (1 hora)
-				ExpiresAt: expiresAt,
-				User: dto.UserResponse{
-					Id:          user.Id,
-					Name:        user.Name,
-					Email:       user.Email,
-					UserType:    user.UserType,
-					MicrosoftId: user.MicrosoftId,
-					IsActive:    user.IsActive,
-					CreatedAt:   user.CreatedAt,
-					UpdatedAt:   user.UpdatedAt,
-					LastLoginAt: user.LastLoginAt,
-				},
-			},
-			Message: "Login successful",
-		})
+	// Sessão que permite listar e encerrar o login; sem Redis o token é emitido sem sessão
+	var sessionID string
+	if session, err := middleware.StartSession(c.Request.Context(), user.Id, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		log.Printf("Failed to start session for user %d: %v", user.Id, err)
+	} else {
+		sessionID = session.Id
 	}
+
+	token, err := middleware.GenerateJWT(int64(user.Id), user.Email, role, companyID, sessionID)
+	if err != nil {
+		recordAuthLog(c, cfg, user.Id, authTypeJWT, false, "Failed to generate authentication token")
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to generate authentication token", err.Error()))
+		return
+	}
+
+	// Atualizar LastLoginAt
+	now := time.Now().UTC()
+	user.LastLoginAt = &now
+	if err := cfg.SqlServer.UpdateUser(c.Request.Context(), user.Id, user); err != nil {
+		// Log error but don't fail the login
+		// A falha em atualizar LastLoginAt não deve impedir o login
+		log.Printf("Failed to update LastLoginAt for user %d: %v", user.Id, err)
+
+	}
+
+	recordAuthLog(c, cfg, user.Id, authTypeJWT, true, "")
+
+	// Calcular tempo de expiração (JWT_TTL_MINUTES, padrão 1 hora)
+	lifetime := middleware.TokenLifetime()
+	expiresAt := time.Now().Add(lifetime)
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.LoginResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresIn: int(lifetime.Seconds()),
+		ExpiresAt: expiresAt,
+		User:      dto.NewUserResponse(*user),
+	}, "Login successful"))
 }
@@ -0,0 +1,126 @@
+package users
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/audit"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// GetMe retorna o perfil do usuário autenticado
+// @Summary      Meu Perfil
+// @Description  Retorna o perfil do usuário do token, disponível para qualquer tipo de usuário
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=dto.UserResponse}
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /auth/me [get]
+func GetMe(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated", nil))
+			return
+		}
+
+		user, err := cfg.SqlServer.GetUserByID(c.Request.Context(), userId)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve user")
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.NewUserResponse(*user), "Profile retrieved successfully"))
+	}
+}
+
+// UpdateMe altera o nome e o email do usuário autenticado
+// @Summary      Atualizar Meu Perfil
+// @Description  Altera o nome e o email do usuário do token. Tipo de usuário, empresa e status continuam restritos a PUT /users/{id}. Trocar o email exige a senha atual e não é permitido para contas Microsoft, cujo email vem do Microsoft Entra ID. O novo email vale imediatamente, sem confirmação por email, pois a API não envia emails.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        request body dto.UpdateProfileRequest true "Dados do perfil"
+// @Success      200 {object} dto.SuccessResponse{data=dto.UserResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Current password incorrect"
+// @Failure 	 409 {object} dto.ErrorResponse "Conflict - Email already in use"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /auth/me [put]
+func UpdateMe(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.UpdateProfileRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+
+		userId, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated", nil))
+			return
+		}
+
+		user, err := cfg.SqlServer.GetUserByID(c.Request.Context(), userId)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve user")
+			return
+		}
+		before := *user
+
+		if req.Name != nil {
+			user.Name = strings.TrimSpace(*req.Name)
+		}
+
+		if req.Email != nil && !strings.EqualFold(*req.Email, user.Email) {
+			// A troca de email é confirmada com a senha atual, já que o email é o login
+			if user.PasswordHash == nil {
+				c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "The email of Microsoft accounts cannot be changed", nil))
+				return
+			}
+			if req.CurrentPassword == nil || *req.CurrentPassword == "" {
+				c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "currentPassword is required to change the email", nil))
+				return
+			}
+			if err := bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(*req.CurrentPassword)); err != nil {
+				recordAuthLog(c, cfg, user.Id, authTypeJWT, false, "Invalid password on email change")
+				c.JSON(http.StatusForbidden, dto.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "Current password is incorrect", nil))
+				return
+			}
+
+			existingUser, _ := cfg.SqlServer.GetUserByEmail(c.Request.Context(), *req.Email)
+			if existingUser != nil && existingUser.Id != user.Id {
+				c.JSON(http.StatusConflict, dto.NewErrorResponse(c, http.StatusConflict, "Conflict", "Email already in use", nil))
+				return
+			}
+			user.Email = *req.Email
+		}
+
+		user.UpdatedBy = &userId
+		if err := cfg.SqlServer.UpdateUser(c.Request.Context(), userId, user); err != nil {
+			middleware.RespondError(c, err, "Failed to update profile")
+			return
+		}
+
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntityUser, strconv.Itoa(userId), &before, user)
+
+		// Recarrega para devolver UpdatedAt gravado pelo repositório
+		updated, err := cfg.SqlServer.GetUserByID(c.Request.Context(), userId)
+		if err != nil {
+			updated = user
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.NewUserResponse(*updated), "Profile updated successfully"))
+	}
+}
@@ -0,0 +1,182 @@
+package users
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	_ "embed"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Valores padrão da política de senhas
+const (
+	defaultPasswordMinLength  = 8
+	defaultPasswordMinClasses = 3
+	defaultPasswordHistory    = 5
+)
+
+// commonPasswords são senhas comuns recusadas em qualquer variação de maiúsculas
+//
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// PasswordPolicy define as regras aplicadas às senhas definidas no cadastro, na atualização
+// e na troca de senha
+type PasswordPolicy struct {
+	MinLength  int             // Tamanho mínimo
+	MinClasses int             // Quantas classes (minúsculas, maiúsculas, números, símbolos) devem aparecer
+	History    int             // Quantas senhas anteriores não podem ser reutilizadas; 0 desativa
+	Denylist   map[string]bool // Senhas recusadas, em minúsculas
+}
+
+// LoadPasswordPolicy lê a política das variáveis PASSWORD_MIN_LENGTH, PASSWORD_MIN_CLASSES e
+// PASSWORD_HISTORY. PASSWORD_DENYLIST_FILE aponta um arquivo, uma senha por linha, somado à lista embutida.
+func LoadPasswordPolicy() PasswordPolicy {
+	policy := PasswordPolicy{
+		MinLength:  envInt("PASSWORD_MIN_LENGTH", defaultPasswordMinLength),
+		MinClasses: envInt("PASSWORD_MIN_CLASSES", defaultPasswordMinClasses),
+		History:    envInt("PASSWORD_HISTORY", defaultPasswordHistory),
+		Denylist:   make(map[string]bool),
+	}
+	if policy.MinClasses > 4 {
+		policy.MinClasses = 4
+	}
+
+	addPasswords(policy.Denylist, strings.NewReader(commonPasswords))
+	if path := os.Getenv("PASSWORD_DENYLIST_FILE"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Printf("Failed to read password denylist %s: %v", path, err)
+		} else {
+			addPasswords(policy.Denylist, file)
+			file.Close()
+		}
+	}
+
+	return policy
+}
+
+// passwordPolicy é a política em uso, carregada na inicialização
+var passwordPolicy = LoadPasswordPolicy()
+
+// Check retorna as regras que a senha viola; name e email do usuário não podem fazer parte dela
+func (p PasswordPolicy) Check(password, name, email string) []middleware.RuleViolation {
+	var violations []middleware.RuleViolation
+
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, middleware.RuleViolation{Rule: "password_length", Param: strconv.Itoa(p.MinLength)})
+	}
+	if characterClasses(password) < p.MinClasses {
+		violations = append(violations, middleware.RuleViolation{Rule: "password_classes", Param: strconv.Itoa(p.MinClasses)})
+	}
+
+	lower := strings.ToLower(password)
+	if p.Denylist[lower] {
+		violations = append(violations, middleware.RuleViolation{Rule: "password_common"})
+	}
+	if containsPersonalData(lower, name, email) {
+		violations = append(violations, middleware.RuleViolation{Rule: "password_personal"})
+	}
+
+	return violations
+}
+
+// checkPasswordReuse verifica se a senha é a atual ou uma das últimas senhas do usuário.
+// currentHash é o hash em vigor, que pode ainda não estar no histórico.
+func checkPasswordReuse(ctx context.Context, cfg *config.App, userId int, password string, currentHash *string) ([]middleware.RuleViolation, error) {
+	if passwordPolicy.History <= 0 {
+		return nil, nil
+	}
+
+	hashes, err := cfg.SqlServer.GetPasswordHistory(ctx, userId, passwordPolicy.History)
+	if err != nil {
+		return nil, err
+	}
+	if currentHash != nil {
+		hashes = append(hashes, *currentHash)
+	}
+
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return []middleware.RuleViolation{{Rule: "password_reused", Param: strconv.Itoa(passwordPolicy.History)}}, nil
+		}
+	}
+	return nil, nil
+}
+
+// recordPasswordHistory guarda a senha definida no histórico. A falha não desfaz a troca de senha.
+func recordPasswordHistory(ctx context.Context, cfg *config.App, userId int, passwordHash string) {
+	if passwordPolicy.History <= 0 {
+		return
+	}
+	if err := cfg.SqlServer.AddPasswordHistory(ctx, userId, passwordHash, passwordPolicy.History); err != nil {
+		log.Printf("Failed to record password history for user %d: %v", userId, err)
+	}
+}
+
+// characterClasses conta quantas classes de caracteres aparecem na senha
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
+
+// containsPersonalData verifica se a senha (em minúsculas) contém o usuário do email ou
+// alguma parte do nome com pelo menos 4 letras
+func containsPersonalData(password, name, email string) bool {
+	parts := strings.Fields(strings.ToLower(name))
+	if local, _, ok := strings.Cut(strings.ToLower(email), "@"); ok {
+		parts = append(parts, local)
+	}
+
+	for _, part := range parts {
+		if len([]rune(part)) >= 4 && strings.Contains(password, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func addPasswords(denylist map[string]bool, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if password := strings.ToLower(strings.TrimSpace(scanner.Text())); password != "" {
+			denylist[password] = true
+		}
+	}
+}
+
+func envInt(name string, fallback int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,53 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:  8,
+		MinClasses: 3,
+		History:    5,
+		Denylist:   map[string]bool{"p@ssw0rd123": true},
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		expected []string
+	}{
+		{name: "strong password", password: "Lua-Cheia42", expected: nil},
+		{name: "too short", password: "Ab1!", expected: []string{"password_length"}},
+		{name: "too few classes", password: "somenteletras", expected: []string{"password_classes"}},
+		{name: "common password in any case", password: "P@SSW0RD123", expected: []string{"password_common"}},
+		{name: "contains part of the name", password: "Joaquim#2025", expected: []string{"password_personal"}},
+		{name: "contains the email user", password: "x-JSILVA-99", expected: []string{"password_personal"}},
+		{name: "several rules", password: "abc", expected: []string{"password_length", "password_classes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []string
+			for _, v := range policy.Check(tt.password, "Joaquim Silva", "jsilva@example.com") {
+				rules = append(rules, v.Rule)
+			}
+			assert.Equal(t, tt.expected, rules)
+		})
+	}
+}
+
+func TestLoadPasswordPolicy(t *testing.T) {
+	t.Setenv("PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("PASSWORD_MIN_CLASSES", "9")
+	t.Setenv("PASSWORD_HISTORY", "invalid")
+
+	policy := LoadPasswordPolicy()
+
+	assert.Equal(t, 12, policy.MinLength)
+	assert.Equal(t, 4, policy.MinClasses)
+	assert.Equal(t, defaultPasswordHistory, policy.History)
+	assert.True(t, policy.Denylist["senha123"])
+}
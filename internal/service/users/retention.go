@@ -0,0 +1,55 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/service/jobs"
+	"time"
+)
+
+// AnonymizationJobName é a rotina que limpa os rastros pessoais dos usuários removidos
+const AnonymizationJobName = "deleted_users_anonymization"
+
+func init() {
+	jobs.Register(jobs.Job{
+		Name:        AnonymizationJobName,
+		Description: "Anonymizes (ANONYMIZE) or purges (DELETE) auth logs and audit snapshots of users deleted longer ago than the retention period",
+		Defaults: entities.ScheduledJob{
+			Enabled:         true,
+			IntervalMinutes: 24 * 60,
+			RetentionDays:   30,
+			Policy:          string(CascadeAnonymize),
+		},
+		Policies: []string{string(CascadeAnonymize), string(CascadeDelete)},
+		Run:      anonymizeDeletedUsers,
+	})
+}
+
+// anonymizeDeletedUsers completa a remoção dos usuários desativados há mais de RetentionDays.
+// DeleteUser já apaga os dados do cadastro; aqui são tratados os registros vinculados que ainda guardam
+// dados pessoais. Os logs de autenticação seguem a política da rotina; a trilha de auditoria é sempre
+// anonimizada, para não perder o registro de quem fez cada alteração.
+func anonymizeDeletedUsers(ctx context.Context, cfg *config.App, settings entities.ScheduledJob) (int64, error) {
+	cutoff := time.Now().UTC().AddDate(0, 0, -settings.RetentionDays)
+
+	var affected int64
+	var err error
+	if CascadePolicy(settings.Policy) == CascadeDelete {
+		affected, err = cfg.SqlServer.PurgeDeletedUsersAuthLogs(ctx, cutoff)
+	} else {
+		affected, err = cfg.SqlServer.AnonymizeDeletedUsersAuthLogs(ctx, cutoff)
+	}
+	if err != nil {
+		return affected, fmt.Errorf("auth logs: %w", err)
+	}
+
+	audited, err := cfg.SqlServer.AnonymizeDeletedUsersAuditLogs(ctx, cutoff)
+	affected += audited
+	if err != nil {
+		return affected, fmt.Errorf("audit logs: %w", err)
+	}
+
+	return affected, nil
+}
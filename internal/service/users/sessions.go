@@ -0,0 +1,121 @@
+package users
+
+import (
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/audit"
+	"orderstreamrest/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSessions lista os logins ativos
+// @Summary      Listar Sessões
+// @Description  Retorna os logins ativos do usuário autenticado, do mais recente ao mais antigo. ADMIN pode consultar as sessões de outro usuário com userId.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        userId query int false "ID do usuário (apenas ADMIN)"
+// @Success      200 {object} dto.SuccessResponse{data=[]dto.SessionResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /auth/sessions [get]
+func ListSessions(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated", nil))
+			return
+		}
+
+		if param := c.Query("userId"); param != "" {
+			targetId, err := strconv.Atoi(param)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid user ID", nil))
+				return
+			}
+			if targetId != userId && middleware.GetCurrentRole(c) != utils.RoleAdmin {
+				c.JSON(http.StatusForbidden, dto.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "User does not have permission to access this resource", nil))
+				return
+			}
+			userId = targetId
+		}
+
+		sessions, err := middleware.ListSessions(c.Request.Context(), userId)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve sessions")
+			return
+		}
+
+		currentId, _ := middleware.GetCurrentSessionID(c)
+		response := make([]dto.SessionResponse, 0, len(sessions))
+		for _, session := range sessions {
+			response = append(response, sessionResponse(&session, currentId))
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, response, "Sessions retrieved successfully"))
+	}
+}
+
+// RevokeSession encerra um login
+// @Summary      Encerrar Sessão
+// @Description  Encerra a sessão; o token dela deixa de ser aceito na próxima requisição. O usuário encerra as próprias sessões e ADMIN encerra as de qualquer usuário.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path string true "ID da sessão"
+// @Success      200 {object} dto.SuccessResponse
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /auth/sessions/{id} [delete]
+func RevokeSession(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated", nil))
+			return
+		}
+
+		session, err := middleware.GetSession(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve session")
+			return
+		}
+
+		// Sessões de outros usuários aparecem como inexistentes para quem não é ADMIN
+		if session == nil || (session.UserId != userId && middleware.GetCurrentRole(c) != utils.RoleAdmin) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Session not found", nil))
+			return
+		}
+
+		if err := middleware.RevokeSession(c.Request.Context(), session); err != nil {
+			middleware.RespondError(c, err, "Failed to revoke session")
+			return
+		}
+
+		audit.Record(c, cfg, audit.ActionDelete, audit.EntitySession, session.Id, session, nil)
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, nil, "Session revoked successfully"))
+	}
+}
+
+func sessionResponse(session *middleware.Session, currentId string) dto.SessionResponse {
+	return dto.SessionResponse{
+		Id:         session.Id,
+		UserId:     session.UserId,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		IssuedAt:   session.IssuedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		Current:    session.Id == currentId,
+	}
+}
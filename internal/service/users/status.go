@@ -0,0 +1,99 @@
+package users
+
+import (
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/service/audit"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeactivateUser desativa um usuário e encerra suas sessões
+// @Summary      Desativar Usuário
+// @Description  Marca o usuário como inativo, impedindo novos logins, e invalida os tokens já emitidos. Os dados são mantidos; a remoção definitiva (LGPD) continua em DELETE /users/{id}.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path int true "ID do usuário"
+// @Success      200 {object} dto.SuccessResponse{data=dto.UserResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/{id}/deactivate [patch]
+func DeactivateUser(cfg *config.App) gin.HandlerFunc {
+	return setUserActive(cfg, false)
+}
+
+// ActivateUser reativa um usuário desativado
+// @Summary      Reativar Usuário
+// @Description  Marca o usuário como ativo, permitindo que ele volte a fazer login
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        id path int true "ID do usuário"
+// @Success      200 {object} dto.SuccessResponse{data=dto.UserResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /users/{id}/activate [patch]
+func ActivateUser(cfg *config.App) gin.HandlerFunc {
+	return setUserActive(cfg, true)
+}
+
+func setUserActive(cfg *config.App, active bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid user ID", nil))
+			return
+		}
+
+		updatedBy, _ := middleware.GetCurrentUserID(c)
+		if !active && updatedBy == id {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "User cannot deactivate themselves", nil))
+			return
+		}
+
+		user, err := cfg.SqlServer.GetUserByID(c.Request.Context(), id)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve user")
+			return
+		}
+		before := *user
+
+		if err := cfg.SqlServer.SetUserActive(c.Request.Context(), id, active, updatedBy); err != nil {
+			middleware.RespondError(c, err, "Failed to update user status")
+			return
+		}
+
+		// A revogação falhar não desfaz a desativação: o login já é bloqueado e o token expira em até uma hora
+		if active {
+			err = middleware.RestoreUserTokens(c.Request.Context(), id)
+		} else {
+			err = middleware.RevokeUserTokens(c.Request.Context(), id)
+		}
+		if err != nil {
+			log.Printf("Failed to update token blacklist for user %d: %v", id, err)
+		}
+
+		user.IsActive = active
+		user.UpdatedBy = &updatedBy
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntityUser, strconv.Itoa(id), &before, user)
+
+		message := "User deactivated successfully"
+		if active {
+			message = "User activated successfully"
+		}
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.NewUserResponse(*user), message))
+	}
+}
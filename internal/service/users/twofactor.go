@@ -0,0 +1,439 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"orderstreamrest/internal/config"
+	"orderstreamrest/internal/middleware"
+	"orderstreamrest/internal/models/dto"
+	"orderstreamrest/internal/models/entities"
+	"orderstreamrest/internal/repositories/sqlserver"
+	"orderstreamrest/internal/service/audit"
+	"orderstreamrest/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// twoFactorIssuer é o nome exibido no aplicativo autenticador
+	twoFactorIssuer = "VisionData"
+	// twoFactorChallengeTTL é o prazo para concluir o login com o segundo fator
+	twoFactorChallengeTTL = 5 * time.Minute
+	// twoFactorMaxAttempts é quantos códigos errados seguidos o usuário pode enviar antes de ser bloqueado
+	twoFactorMaxAttempts = 5
+	// twoFactorFailureWindow é por quanto tempo os códigos errados do usuário continuam contando
+	twoFactorFailureWindow = time.Hour
+	// twoFactorLockoutBase é o bloqueio ao atingir twoFactorMaxAttempts; dobra a cada novo erro
+	twoFactorLockoutBase = 30 * time.Second
+	// twoFactorLockoutMax limita o bloqueio do usuário
+	twoFactorLockoutMax = 15 * time.Minute
+	// recoveryCodesCount é quantos códigos de recuperação são gerados ao ativar o 2FA
+	recoveryCodesCount = 10
+
+	twoFactorChallengeKeyPrefix = "auth:2fa:challenge:"
+	twoFactorFailuresKeyPrefix  = "auth:2fa:failures:"
+	twoFactorLockKeyPrefix      = "auth:2fa:lock:"
+	twoFactorUsedKeyPrefix      = "auth:2fa:used:"
+
+	authTypeTOTP     = "JWT_TOTP"
+	authTypeRecovery = "JWT_RECOVERY_CODE"
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// SetupTwoFactor inicia a configuração do 2FA
+// @Summary      Configurar 2FA
+// @Description  Gera um segredo TOTP para o usuário autenticado e retorna a URL otpauth:// (para gerar o QR code no frontend). O 2FA só passa a valer depois de confirmado em POST /auth/2fa/verify. Disponível apenas para contas com senha.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Success      200 {object} dto.SuccessResponse{data=dto.TwoFactorSetupResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request - Conta Microsoft"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 409 {object} dto.ErrorResponse "Conflict - 2FA já ativo"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /auth/2fa/setup [post]
+func SetupTwoFactor(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, cfg)
+		if !ok {
+			return
+		}
+
+		if user.PasswordHash == nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Two-factor authentication is only available for password accounts", nil))
+			return
+		}
+
+		existing, err := cfg.SqlServer.GetTwoFactor(c.Request.Context(), user.Id)
+		if err != nil && !errors.Is(err, sqlserver.ErrTwoFactorNotConfigured) {
+			middleware.RespondError(c, err, "Failed to retrieve two-factor settings")
+			return
+		}
+		if existing != nil && existing.Enabled {
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(c, http.StatusConflict, "Conflict", "Two-factor authentication is already enabled", nil))
+			return
+		}
+
+		secret, err := utils.NewTOTPSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to generate secret", err.Error()))
+			return
+		}
+
+		if err := cfg.SqlServer.StartTwoFactorSetup(c.Request.Context(), user.Id, secret); err != nil {
+			middleware.RespondError(c, err, "Failed to save two-factor settings")
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.TwoFactorSetupResponse{
+			Secret:     secret,
+			OtpauthURL: utils.TOTPURL(twoFactorIssuer, user.Email, secret),
+		}, "Scan the QR code and confirm with a code to enable two-factor authentication"))
+	}
+}
+
+// VerifyTwoFactor confirma e ativa o 2FA
+// @Summary      Ativar 2FA
+// @Description  Confirma o segredo gerado em POST /auth/2fa/setup com um código do aplicativo e ativa o 2FA. Retorna os códigos de recuperação, que não são exibidos novamente.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security 	 BearerAuth
+// @Param        request body dto.TwoFactorVerifyRequest true "Código do aplicativo"
+// @Success      200 {object} dto.SuccessResponse{data=dto.TwoFactorRecoveryCodesResponse}
+// @Failure 	 400 {object} dto.ErrorResponse "Bad Request - Código inválido"
+// @Failure 	 422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure 	 401 {object} dto.AuthErrorResponse "Unauthorized"
+// @Failure 	 404 {object} dto.ErrorResponse "Not Found - 2FA não configurado"
+// @Failure 	 409 {object} dto.ErrorResponse "Conflict - 2FA já ativo"
+// @Failure 	 500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /auth/2fa/verify [post]
+func VerifyTwoFactor(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.TwoFactorVerifyRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+
+		user, ok := currentUser(c, cfg)
+		if !ok {
+			return
+		}
+
+		twoFactor, err := cfg.SqlServer.GetTwoFactor(c.Request.Context(), user.Id)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve two-factor settings")
+			return
+		}
+		if twoFactor.Enabled {
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(c, http.StatusConflict, "Conflict", "Two-factor authentication is already enabled", nil))
+			return
+		}
+
+		if _, valid := utils.ValidateTOTP(twoFactor.Secret, req.Code, time.Now()); !valid {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(c, http.StatusBadRequest, "Bad Request", "Invalid verification code", nil))
+			return
+		}
+
+		codes, hashes, err := newRecoveryCodes()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to generate recovery codes", err.Error()))
+			return
+		}
+
+		if err := cfg.SqlServer.EnableTwoFactor(c.Request.Context(), user.Id, hashes); err != nil {
+			middleware.RespondError(c, err, "Failed to enable two-factor authentication")
+			return
+		}
+
+		audit.Record(c, cfg, audit.ActionUpdate, audit.EntityUser, strconv.Itoa(user.Id),
+			gin.H{"twoFactorEnabled": false}, gin.H{"twoFactorEnabled": true})
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(c, dto.TwoFactorRecoveryCodesResponse{
+			RecoveryCodes: codes,
+		}, "Two-factor authentication enabled"))
+	}
+}
+
+// LoginTwoFactor conclui o login de uma conta com 2FA
+// @Summary      Login com 2FA
+// @Description  Conclui o login iniciado em POST /auth/login com o challengeToken e um código do aplicativo ou um código de recuperação (cada um vale uma vez). O desafio expira em 5 minutos.
+// @Description  Após 5 códigos errados do mesmo usuário, em qualquer desafio, o usuário fica bloqueado por 30 segundos, tempo que dobra a cada novo erro até 15 minutos; o desafio em uso é descartado. Os erros deixam de contar após 1 hora sem erros ou com um login concluído.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.TwoFactorLoginRequest true "Desafio e código"
+// @Success      200 {object} dto.SuccessResponse{data=dto.LoginResponse}
+// @Failure      400 {object} dto.ErrorResponse "Bad Request"
+// @Failure      422 {object} dto.ValidationErrorResponse "Validation Failed"
+// @Failure      401 {object} dto.ErrorResponse "Unauthorized - Desafio expirado ou código inválido"
+// @Failure      403 {object} dto.ErrorResponse "Forbidden - Usuário inativo"
+// @Failure      429 {object} dto.RateLimitErrorResponse "Too Many Requests - Usuário bloqueado por códigos errados"
+// @Failure      500 {object} dto.ErrorResponse "Internal Server Error"
+// @Router       /auth/2fa/login [post]
+func LoginTwoFactor(cfg *config.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.TwoFactorLoginRequest
+		if !middleware.BindJSON(c, &req, "Invalid request body") {
+			return
+		}
+
+		ctx := c.Request.Context()
+		challengeKey := twoFactorChallengeKeyPrefix + req.ChallengeToken
+
+		value, err := cfg.Redis.Get(ctx, challengeKey).Result()
+		if errors.Is(err, redis.Nil) {
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Invalid or expired challenge", nil))
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to retrieve challenge", err.Error()))
+			return
+		}
+		userId, _ := strconv.Atoi(value)
+
+		// O bloqueio vale por usuário, para que novos desafios não renovem as tentativas
+		locked, err := cfg.Redis.TTL(ctx, twoFactorLockKey(userId)).Result()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to check two-factor code", err.Error()))
+			return
+		}
+		if locked > 0 {
+			middleware.AbortWithRetry(c, http.StatusTooManyRequests, dto.ReasonRateLimited, "Too many invalid two-factor codes, try again later", locked, 0)
+			return
+		}
+
+		user, err := cfg.SqlServer.GetUserByID(ctx, userId)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve user")
+			return
+		}
+		if !user.IsActive {
+			cfg.Redis.Del(ctx, challengeKey)
+			recordAuthLog(c, cfg, user.Id, authTypeTOTP, false, "User account is inactive")
+			c.JSON(http.StatusForbidden, dto.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "User account is inactive", nil))
+			return
+		}
+
+		twoFactor, err := cfg.SqlServer.GetTwoFactor(ctx, user.Id)
+		if err != nil {
+			middleware.RespondError(c, err, "Failed to retrieve two-factor settings")
+			return
+		}
+
+		authType := authTypeTOTP
+		var valid bool
+		if req.Code != "" {
+			valid, err = useTOTPCode(ctx, cfg, twoFactor, req.Code)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to check two-factor code", err.Error()))
+				return
+			}
+		} else {
+			authType = authTypeRecovery
+			valid, err = useRecoveryCode(ctx, cfg, twoFactor, req.RecoveryCode)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to check recovery code", err.Error()))
+				return
+			}
+		}
+
+		if !valid {
+			recordAuthLog(c, cfg, user.Id, authType, false, "Invalid two-factor code")
+			// Os erros contam por usuário, com bloqueio crescente, para impedir a força bruta dos 6 dígitos
+			lockout, err := recordTwoFactorFailure(ctx, cfg, user.Id)
+			if err != nil {
+				log.Printf("Failed to record two-factor failure of user %d: %v", user.Id, err)
+			}
+			if lockout > 0 {
+				cfg.Redis.Del(ctx, challengeKey)
+				middleware.AbortWithRetry(c, http.StatusTooManyRequests, dto.ReasonRateLimited, "Too many invalid two-factor codes, try again later", lockout, 0)
+				return
+			}
+			c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Invalid two-factor code", nil))
+			return
+		}
+
+		cfg.Redis.Del(ctx, challengeKey, twoFactorFailuresKey(user.Id))
+		issueLoginToken(c, cfg, user)
+	}
+}
+
+// twoFactorRequired indica se o login do usuário exige o segundo fator
+func twoFactorRequired(ctx context.Context, cfg *config.App, userId int) (bool, error) {
+	twoFactor, err := cfg.SqlServer.GetTwoFactor(ctx, userId)
+	if errors.Is(err, sqlserver.ErrTwoFactorNotConfigured) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return twoFactor.Enabled, nil
+}
+
+// respondTwoFactorChallenge guarda o desafio do login no Redis e o devolve ao cliente
+func respondTwoFactorChallenge(c *gin.Context, cfg *config.App, userId int) {
+	challenge := uuid.NewString()
+	if err := cfg.Redis.Set(c.Request.Context(), twoFactorChallengeKeyPrefix+challenge, userId, twoFactorChallengeTTL).Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "Failed to start two-factor authentication", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.NewSuccessResponse(c, dto.TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge,
+		ExpiresIn:         int(twoFactorChallengeTTL.Seconds()),
+	}, "Two-factor authentication required"))
+}
+
+// recordTwoFactorFailure conta um código errado do usuário e, ao atingir twoFactorMaxAttempts, o bloqueia.
+// Retorna o bloqueio aplicado, zero quando o usuário ainda pode tentar.
+func recordTwoFactorFailure(ctx context.Context, cfg *config.App, userId int) (time.Duration, error) {
+	failuresKey := twoFactorFailuresKey(userId)
+	failures, err := cfg.Redis.Incr(ctx, failuresKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if err := cfg.Redis.Expire(ctx, failuresKey, twoFactorFailureWindow).Err(); err != nil {
+		return 0, err
+	}
+
+	lockout := twoFactorLockout(failures)
+	if lockout > 0 {
+		if err := cfg.Redis.Set(ctx, twoFactorLockKey(userId), failures, lockout).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return lockout, nil
+}
+
+// twoFactorLockout retorna o bloqueio após failures códigos errados: nenhum antes de twoFactorMaxAttempts,
+// depois twoFactorLockoutBase, dobrando a cada erro até twoFactorLockoutMax
+func twoFactorLockout(failures int64) time.Duration {
+	if failures < twoFactorMaxAttempts {
+		return 0
+	}
+
+	lockout := twoFactorLockoutBase
+	for i := int64(twoFactorMaxAttempts); i < failures && lockout < twoFactorLockoutMax; i++ {
+		lockout *= 2
+	}
+	if lockout > twoFactorLockoutMax {
+		return twoFactorLockoutMax
+	}
+	return lockout
+}
+
+// twoFactorFailuresKey é a chave com os códigos errados do usuário
+func twoFactorFailuresKey(userId int) string {
+	return fmt.Sprintf("%s%d", twoFactorFailuresKeyPrefix, userId)
+}
+
+// twoFactorLockKey é a chave que bloqueia o login com 2FA do usuário enquanto existir
+func twoFactorLockKey(userId int) string {
+	return fmt.Sprintf("%s%d", twoFactorLockKeyPrefix, userId)
+}
+
+// useTOTPCode valida o código do aplicativo, recusando um código já usado no mesmo período. Sem o Redis
+// não há como garantir o uso único, então o erro é retornado e o código é recusado.
+func useTOTPCode(ctx context.Context, cfg *config.App, twoFactor *entities.UserTwoFactor, code string) (bool, error) {
+	step, valid := utils.ValidateTOTP(twoFactor.Secret, code, time.Now())
+	if !valid {
+		return false, nil
+	}
+
+	usedKey := fmt.Sprintf("%s%d:%d", twoFactorUsedKeyPrefix, twoFactor.UserId, step)
+	first, err := cfg.Redis.SetNX(ctx, usedKey, 1, 3*utils.TOTPPeriod).Result()
+	if err != nil {
+		return false, fmt.Errorf("recording used TOTP code: %w", err)
+	}
+	return first, nil
+}
+
+// useRecoveryCode valida o código de recuperação e o invalida. A troca dos hashes só vale se ninguém os
+// alterou desde a leitura, então dois logins simultâneos não conseguem usar o mesmo código.
+func useRecoveryCode(ctx context.Context, cfg *config.App, twoFactor *entities.UserTwoFactor, code string) (bool, error) {
+	if twoFactor.RecoveryCodes == nil {
+		return false, nil
+	}
+
+	var hashes []string
+	if err := json.Unmarshal([]byte(*twoFactor.RecoveryCodes), &hashes); err != nil {
+		return false, fmt.Errorf("decoding recovery codes: %w", err)
+	}
+
+	code = normalizeRecoveryCode(code)
+	for i, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) != nil {
+			continue
+		}
+
+		remaining := append(hashes[:i:i], hashes[i+1:]...)
+		data, _ := json.Marshal(remaining)
+		return cfg.SqlServer.ConsumeRecoveryCode(ctx, twoFactor.UserId, *twoFactor.RecoveryCodes, string(data))
+	}
+	return false, nil
+}
+
+// newRecoveryCodes gera os códigos de recuperação e o array JSON com seus hashes
+func newRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, "", err
+		}
+		encoded := strings.ToLower(recoveryEncoding.EncodeToString(raw))
+		code := encoded[:4] + "-" + encoded[4:]
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(normalizeRecoveryCode(code)), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, "", err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, string(hash))
+	}
+
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, string(data), nil
+}
+
+// normalizeRecoveryCode aceita o código com ou sem hífen, em qualquer caixa
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// currentUser busca o usuário do token, respondendo quando não for possível
+func currentUser(c *gin.Context, cfg *config.App) (*entities.User, bool) {
+	userId, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User not authenticated", nil))
+		return nil, false
+	}
+
+	user, err := cfg.SqlServer.GetUserByID(c.Request.Context(), userId)
+	if err != nil {
+		middleware.RespondError(c, err, "Failed to retrieve user")
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,53 @@
+package users
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNewRecoveryCodes(t *testing.T) {
+	codes, encoded, err := newRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodesCount)
+
+	var hashes []string
+	require.NoError(t, json.Unmarshal([]byte(encoded), &hashes))
+	require.Len(t, hashes, recoveryCodesCount)
+
+	seen := map[string]bool{}
+	for i, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+		assert.False(t, seen[code], "duplicated code %s", code)
+		seen[code] = true
+
+		// O código vale digitado com ou sem hífen e em maiúsculas
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hashes[i]), []byte(normalizeRecoveryCode(code))))
+		assert.Equal(t, normalizeRecoveryCode(code), normalizeRecoveryCode(" "+strings.ToUpper(code[:4]+code[5:])+" "))
+	}
+}
+
+func TestTwoFactorLockout(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int64
+		expected time.Duration
+	}{
+		{name: "first failure", failures: 1, expected: 0},
+		{name: "below the limit", failures: twoFactorMaxAttempts - 1, expected: 0},
+		{name: "limit reached", failures: twoFactorMaxAttempts, expected: 30 * time.Second},
+		{name: "doubles after each failure", failures: twoFactorMaxAttempts + 2, expected: 2 * time.Minute},
+		{name: "capped", failures: twoFactorMaxAttempts + 10, expected: twoFactorLockoutMax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, twoFactorLockout(tt.failures))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package telemetry
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand/v2"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestTrace são os identificadores e a decisão de amostragem que o middleware de tracing já
+// atribuiu à requisição. O span da requisição usa esses valores, para que trace_id dos logs,
+// exemplars do Prometheus e spans exportados sejam os mesmos.
+type RequestTrace struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+type requestTraceKey struct{}
+
+// WithRequestTrace guarda em ctx os identificadores do span da requisição, que deve ser o próximo span iniciado
+func WithRequestTrace(ctx context.Context, rt RequestTrace) context.Context {
+	return context.WithValue(ctx, requestTraceKey{}, rt)
+}
+
+// requestTraceFrom retorna os identificadores da requisição quando o span a ser criado é o da requisição:
+// os spans filhos já encontram um span local no contexto
+func requestTraceFrom(ctx context.Context) (RequestTrace, bool) {
+	if parent := trace.SpanContextFromContext(ctx); parent.IsValid() && !parent.IsRemote() {
+		return RequestTrace{}, false
+	}
+	rt, ok := ctx.Value(requestTraceKey{}).(RequestTrace)
+	return rt, ok
+}
+
+// requestIDGenerator usa os identificadores da requisição no span da requisição e gera ids aleatórios nos demais
+type requestIDGenerator struct{}
+
+var _ sdktrace.IDGenerator = requestIDGenerator{}
+
+func (requestIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if rt, ok := requestTraceFrom(ctx); ok {
+		traceID, errTrace := trace.TraceIDFromHex(rt.TraceID)
+		spanID, errSpan := trace.SpanIDFromHex(rt.SpanID)
+		if errTrace == nil && errSpan == nil {
+			return traceID, spanID
+		}
+	}
+	return randomTraceID(), randomSpanID()
+}
+
+func (requestIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	if rt, ok := requestTraceFrom(ctx); ok && rt.TraceID == traceID.String() {
+		if spanID, err := trace.SpanIDFromHex(rt.SpanID); err == nil {
+			return spanID
+		}
+	}
+	return randomSpanID()
+}
+
+// requestSampler respeita a decisão de amostragem da requisição nos traces iniciados pela API
+type requestSampler struct {
+	fallback sdktrace.Sampler
+}
+
+func (s requestSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	rt, ok := requestTraceFrom(p.ParentContext)
+	if !ok {
+		return s.fallback.ShouldSample(p)
+	}
+
+	decision := sdktrace.Drop
+	if rt.Sampled {
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s requestSampler) Description() string {
+	return "RequestSampler{" + s.fallback.Description() + "}"
+}
+
+func randomTraceID() trace.TraceID {
+	var id trace.TraceID
+	if _, err := crand.Read(id[:]); err != nil {
+		binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	}
+	return id
+}
+
+func randomSpanID() trace.SpanID {
+	var id trace.SpanID
+	if _, err := crand.Read(id[:]); err != nil {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return id
+}
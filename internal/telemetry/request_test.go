@@ -0,0 +1,70 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRequestSpanUsesRequestTrace(t *testing.T) {
+	tests := []struct {
+		name    string
+		sampled bool
+		want    int
+	}{
+		{name: "sampled request is exported", sampled: true, want: 2},
+		{name: "unsampled request is dropped", sampled: false, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(
+				sdktrace.WithSpanProcessor(recorder),
+				sdktrace.WithIDGenerator(requestIDGenerator{}),
+				sdktrace.WithSampler(sdktrace.ParentBased(requestSampler{fallback: sdktrace.AlwaysSample()})),
+			)
+			tracer := provider.Tracer(TracerName)
+
+			rt := RequestTrace{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Sampled: tt.sampled,
+			}
+			ctx, request := tracer.Start(WithRequestTrace(context.Background(), rt), "GET /tickets")
+			_, child := tracer.Start(ctx, "sqlserver.query")
+			child.End()
+			request.End()
+
+			assert.Equal(t, rt.TraceID, request.SpanContext().TraceID().String())
+			assert.Equal(t, rt.SpanID, request.SpanContext().SpanID().String())
+			assert.Equal(t, rt.TraceID, child.SpanContext().TraceID().String())
+			assert.NotEqual(t, rt.SpanID, child.SpanContext().SpanID().String())
+
+			ended := recorder.Ended()
+			require.Len(t, ended, tt.want)
+			if tt.want > 0 {
+				assert.Equal(t, request.SpanContext().SpanID(), ended[0].Parent().SpanID())
+			}
+		})
+	}
+}
+
+func TestSpansWithoutRequestTraceUseFallback(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithIDGenerator(requestIDGenerator{}),
+		sdktrace.WithSampler(sdktrace.ParentBased(requestSampler{fallback: sdktrace.NeverSample()})),
+	)
+
+	_, span := provider.Tracer(TracerName).Start(context.Background(), "logger.flush")
+	span.End()
+
+	assert.True(t, span.SpanContext().IsValid())
+	assert.Empty(t, recorder.Ended())
+}
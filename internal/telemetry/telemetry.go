@@ -0,0 +1,80 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName é o nome do instrumentation scope dos spans criados pela API
+const TracerName = "orderstreamrest"
+
+const defaultSampleRatio = 0.1
+
+// Tracer retorna o tracer da API a partir do provider global
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Setup configura o OpenTelemetry com exportação OTLP/HTTP quando OTEL_EXPORTER_OTLP_ENDPOINT
+// (ou OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) está definido. Sem endpoint, ou com OTEL_SDK_DISABLED=true,
+// o provider global continua no-op e os spans não custam nada.
+// Retorna a função que envia os spans pendentes e encerra o provider.
+func Setup(ctx context.Context, serviceName, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	if os.Getenv("OTEL_SDK_DISABLED") == "true" {
+		return noop, nil
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return noop, nil
+	}
+
+	// Endpoint, headers, compressão e TLS vêm das variáveis OTEL_EXPORTER_OTLP_* padrão
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+			semconv.DeploymentEnvironment(os.Getenv("ENVIRONMENT_APP")),
+		),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("creating OpenTelemetry resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithIDGenerator(requestIDGenerator{}),
+		sdktrace.WithSampler(sdktrace.ParentBased(requestSampler{fallback: sdktrace.TraceIDRatioBased(sampleRatio())})),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// sampleRatio é a proporção de traces iniciados pela API que são exportados, a mesma de TRACE_SAMPLE_RATIO
+func sampleRatio() float64 {
+	if ratio, err := strconv.ParseFloat(os.Getenv("TRACE_SAMPLE_RATIO"), 64); err == nil {
+		return ratio
+	}
+	return defaultSampleRatio
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Enum is a set of canonical values that also accepts synonyms on input
+type Enum struct {
+	Name     string
+	values   []string
+	synonyms map[string]string
+}
+
+// enums holds every registered enum by name, used by the "enum" binding validator
+var enums = map[string]*Enum{}
+
+// UserTypes is the enum of user types (see Role) accepted by the "enum=usertype" binding tag
+var UserTypes = RegisterEnum("usertype", roleNames(), map[string]string{
+	"ADMINISTRATOR": string(RoleAdmin),
+	"ADMINISTRADOR": string(RoleAdmin),
+	"GERENTE":       string(RoleManager),
+	"SUPPORT":       string(RoleAgent),
+	"SUPORTE":       string(RoleAgent),
+	"AGENTE":        string(RoleAgent),
+	"READONLY":      string(RoleViewer),
+	"READ_ONLY":     string(RoleViewer),
+	"VISUALIZADOR":  string(RoleViewer),
+})
+
+// RegisterEnum creates an enum and registers it under name
+func RegisterEnum(name string, values []string, synonyms map[string]string) *Enum {
+	e := &Enum{
+		Name:     name,
+		values:   values,
+		synonyms: make(map[string]string, len(synonyms)),
+	}
+	for synonym, canonical := range synonyms {
+		e.synonyms[normalizeEnumInput(synonym)] = canonical
+	}
+
+	enums[name] = e
+	return e
+}
+
+// LookupEnum returns the enum registered under name
+func LookupEnum(name string) (*Enum, bool) {
+	e, ok := enums[name]
+	return e, ok
+}
+
+// Values returns the canonical values of the enum
+func (e *Enum) Values() []string {
+	values := make([]string, len(e.values))
+	copy(values, e.values)
+	return values
+}
+
+// Canonical returns the canonical form of value, ignoring case, surrounding spaces and separators
+func (e *Enum) Canonical(value string) (string, bool) {
+	normalized := normalizeEnumInput(value)
+
+	for _, canonical := range e.values {
+		if normalized == canonical {
+			return canonical, true
+		}
+	}
+
+	canonical, ok := e.synonyms[normalized]
+	return canonical, ok
+}
+
+// Normalize returns the canonical form of value, or value unchanged when it is not recognized
+func (e *Enum) Normalize(value string) string {
+	if canonical, ok := e.Canonical(value); ok {
+		return canonical
+	}
+	return value
+}
+
+// normalizeEnumInput uppercases the value and unifies spaces and hyphens as underscores
+func normalizeEnumInput(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(value)
+}
+
+// RegisterEnumValidator registers the "enum=<name>" binding tag.
+// Valid values are rewritten to their canonical form, so handlers only ever see canonical values.
+func RegisterEnumValidator(v *validator.Validate) error {
+	return v.RegisterValidation("enum", validateEnum)
+}
+
+func validateEnum(fl validator.FieldLevel) bool {
+	e, ok := LookupEnum(fl.Param())
+	if !ok {
+		return false
+	}
+
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return false
+	}
+
+	canonical, ok := e.Canonical(field.String())
+	if !ok {
+		return false
+	}
+
+	if field.CanSet() {
+		field.SetString(canonical)
+	}
+	return true
+}
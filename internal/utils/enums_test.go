@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumCanonical(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  string
+		expectsOk bool
+	}{
+		{name: "Canonical value", input: "AGENT", expected: "AGENT", expectsOk: true},
+		{name: "Lowercase with spaces", input: "  manager ", expected: "MANAGER", expectsOk: true},
+		{name: "Synonym", input: "Support", expected: "AGENT", expectsOk: true},
+		{name: "Synonym with separator", input: "read-only", expected: "VIEWER", expectsOk: true},
+		{name: "Unknown value", input: "ROOT", expectsOk: false},
+		{name: "Empty value", input: "", expectsOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, ok := UserTypes.Canonical(tt.input)
+			assert.Equal(t, tt.expectsOk, ok)
+			assert.Equal(t, tt.expected, canonical)
+		})
+	}
+
+	assert.Equal(t, "ROOT", UserTypes.Normalize("ROOT"))
+}
+
+func TestUserTypesMatchRoles(t *testing.T) {
+	// Todo tipo de usuário precisa de um role no JWT, e vice-versa
+	for _, userType := range UserTypes.Values() {
+		role := Role(userType)
+		require.True(t, role.Valid(), userType)
+
+		fromCode, ok := RoleFromCode(role.Code())
+		require.True(t, ok, userType)
+		assert.Equal(t, role, fromCode)
+	}
+	assert.Len(t, roleCodes, len(UserTypes.Values()))
+}
+
+func TestEnumValidator(t *testing.T) {
+	v := validator.New()
+	require.NoError(t, RegisterEnumValidator(v))
+
+	type request struct {
+		UserType string  `validate:"required,enum=usertype"`
+		Optional *string `validate:"omitempty,enum=usertype"`
+	}
+
+	optional := "administrator"
+	req := request{UserType: "support", Optional: &optional}
+	require.NoError(t, v.Struct(&req))
+
+	// O validador reescreve os valores na forma canônica
+	assert.Equal(t, "AGENT", req.UserType)
+	assert.Equal(t, "ADMIN", *req.Optional)
+
+	assert.Error(t, v.Struct(&request{UserType: "ROOT"}))
+}
@@ -0,0 +1,69 @@
+package utils
+
+// Role is a user type. It is stored by name in dbo.Users.UserType and carried in the JWT "role"
+// claim as its numeric code; Roles is the single list both sides (and the "usertype" enum) are built from.
+type Role string
+
+// User types
+const (
+	RoleAdmin   Role = "ADMIN"
+	RoleManager Role = "MANAGER"
+	RoleAgent   Role = "AGENT"
+	RoleViewer  Role = "VIEWER"
+)
+
+// Roles lists every user type, from the most to the least privileged
+var Roles = []Role{RoleAdmin, RoleManager, RoleAgent, RoleViewer}
+
+// roleCodes are the codes carried in the JWT. Codes are part of issued tokens and must never be reused.
+var roleCodes = map[Role]int64{
+	RoleAdmin:   1,
+	RoleManager: 2,
+	RoleAgent:   3,
+	RoleViewer:  4,
+}
+
+// ParseRole returns the role of a user type as stored or sent by clients, accepting the synonyms of
+// UserTypes (e.g. SUPPORT -> AGENT)
+func ParseRole(value string) (Role, bool) {
+	canonical, ok := UserTypes.Canonical(value)
+	if !ok {
+		return "", false
+	}
+	return Role(canonical), true
+}
+
+// RoleFromCode returns the role of a JWT role code
+func RoleFromCode(code int64) (Role, bool) {
+	for role, roleCode := range roleCodes {
+		if roleCode == code {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// Code returns the code carried in the JWT, or 0 for an unknown role
+func (r Role) Code() int64 {
+	return roleCodes[r]
+}
+
+// Valid reports whether r is one of Roles
+func (r Role) Valid() bool {
+	_, ok := roleCodes[r]
+	return ok
+}
+
+// String returns the user type name
+func (r Role) String() string {
+	return string(r)
+}
+
+// roleNames returns the names of Roles, the canonical values of UserTypes
+func roleNames() []string {
+	names := make([]string, len(Roles))
+	for i, role := range Roles {
+		names[i] = string(role)
+	}
+	return names
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  Role
+		expectsOk bool
+	}{
+		{name: "Canonical value", input: "MANAGER", expected: RoleManager, expectsOk: true},
+		{name: "Legacy value", input: "SUPPORT", expected: RoleAgent, expectsOk: true},
+		{name: "Lowercase", input: "viewer", expected: RoleViewer, expectsOk: true},
+		{name: "Unknown value", input: "OWNER"},
+		{name: "Empty value", input: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, ok := ParseRole(tt.input)
+			assert.Equal(t, tt.expectsOk, ok)
+			assert.Equal(t, tt.expected, role)
+		})
+	}
+}
+
+func TestRoleCodes(t *testing.T) {
+	// Os códigos fazem parte dos tokens já emitidos e não podem mudar
+	assert.Equal(t, int64(1), RoleAdmin.Code())
+	assert.Equal(t, int64(2), RoleManager.Code())
+	assert.Equal(t, int64(3), RoleAgent.Code())
+	assert.Equal(t, int64(4), RoleViewer.Code())
+
+	assert.Zero(t, Role("OWNER").Code())
+	assert.False(t, Role("OWNER").Valid())
+
+	_, ok := RoleFromCode(0)
+	assert.False(t, ok)
+}
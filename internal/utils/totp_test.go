@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode(t *testing.T) {
+	// Last 6 digits of the 8-digit RFC 6238 SHA1 vectors
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1111111111, expected: "050471"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := TOTPCode(rfcSecret, time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, code, "time %d", tt.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := TOTPCode(rfcSecret, now)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		code  string
+		at    time.Time
+		valid bool
+	}{
+		{name: "current period", code: code, at: now, valid: true},
+		{name: "previous period still accepted", code: code, at: now.Add(TOTPPeriod), valid: true},
+		{name: "two periods later", code: code, at: now.Add(2 * TOTPPeriod), valid: false},
+		{name: "wrong code", code: "000000", at: now, valid: false},
+		{name: "wrong length", code: "12345", at: now, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid := ValidateTOTP(rfcSecret, tt.code, tt.at)
+			assert.Equal(t, tt.valid, valid)
+		})
+	}
+}
+
+func TestNewTOTPSecretAndURL(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	url := TOTPURL("VisionData", "ana@example.com", secret)
+	assert.True(t, strings.HasPrefix(url, "otpauth://totp/VisionData:ana@example.com?"))
+	assert.Contains(t, url, "secret="+secret)
+	assert.Contains(t, url, "issuer=VisionData")
+}
@@ -0,0 +1,15 @@
+-- Registro das migrações aplicadas. Cada script grava a sua versão ao final e pode ser executado de novo
+-- sem efeito: todas as alterações conferem antes se o objeto já existe.
+IF OBJECT_ID('dbo.SchemaMigrations', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.SchemaMigrations (
+        Version   nvarchar(10)  NOT NULL CONSTRAINT PK_SchemaMigrations PRIMARY KEY,
+        Name      nvarchar(100) NOT NULL,
+        AppliedAt datetime2     NOT NULL CONSTRAINT DF_SchemaMigrations_AppliedAt DEFAULT GETUTCDATE()
+    );
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0000')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0000', 'schema_migrations');
+GO
@@ -0,0 +1,24 @@
+-- Trilha de auditoria das ações administrativas (GET /audit)
+IF OBJECT_ID('dbo.AuditLogs', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.AuditLogs (
+        Id         int IDENTITY(1,1) NOT NULL CONSTRAINT PK_AuditLogs PRIMARY KEY,
+        ActorId    int           NULL,
+        Action     nvarchar(50)  NOT NULL,
+        EntityType nvarchar(50)  NOT NULL,
+        EntityId   nvarchar(100) NOT NULL,
+        [Before]   nvarchar(max) NULL,
+        [After]    nvarchar(max) NULL,
+        IPAddress  nvarchar(50)  NULL,
+        CreatedAt  datetime2     NOT NULL CONSTRAINT DF_AuditLogs_CreatedAt DEFAULT GETUTCDATE()
+    );
+
+    CREATE INDEX IX_AuditLogs_CreatedAt ON dbo.AuditLogs (CreatedAt DESC, Id DESC);
+    CREATE INDEX IX_AuditLogs_Entity ON dbo.AuditLogs (EntityType, EntityId);
+    CREATE INDEX IX_AuditLogs_ActorId ON dbo.AuditLogs (ActorId);
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0001')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0001', 'audit_logs');
+GO
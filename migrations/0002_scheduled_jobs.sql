@@ -0,0 +1,37 @@
+-- Configuração e histórico das rotinas em segundo plano (/admin/jobs)
+IF OBJECT_ID('dbo.ScheduledJobs', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.ScheduledJobs (
+        Name            nvarchar(100) NOT NULL CONSTRAINT PK_ScheduledJobs PRIMARY KEY,
+        Enabled         bit           NOT NULL CONSTRAINT DF_ScheduledJobs_Enabled DEFAULT 1,
+        IntervalMinutes int           NOT NULL,
+        RetentionDays   int           NOT NULL CONSTRAINT DF_ScheduledJobs_RetentionDays DEFAULT 0,
+        Policy          nvarchar(50)  NULL,
+        LastRunAt       datetime2     NULL,
+        UpdatedAt       datetime2     NULL,
+        UpdatedBy       int           NULL
+    );
+END
+GO
+
+IF OBJECT_ID('dbo.JobRuns', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.JobRuns (
+        Id           int IDENTITY(1,1) NOT NULL CONSTRAINT PK_JobRuns PRIMARY KEY,
+        JobName      nvarchar(100) NOT NULL,
+        Source       nvarchar(20)  NOT NULL,
+        TriggeredBy  int           NULL,
+        StartedAt    datetime2     NOT NULL,
+        FinishedAt   datetime2     NULL,
+        Success      bit           NOT NULL CONSTRAINT DF_JobRuns_Success DEFAULT 0,
+        Affected     bigint        NOT NULL CONSTRAINT DF_JobRuns_Affected DEFAULT 0,
+        ErrorMessage nvarchar(500) NULL
+    );
+
+    CREATE INDEX IX_JobRuns_JobName_StartedAt ON dbo.JobRuns (JobName, StartedAt DESC, Id DESC);
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0002')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0002', 'scheduled_jobs');
+GO
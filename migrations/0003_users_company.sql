@@ -0,0 +1,9 @@
+-- Empresa (CompanyId_BK do DW) vista pelos gestores. Enquanto a coluna não existe a API não grava a
+-- empresa dos usuários e os gestores não veem tickets nem métricas de nenhuma empresa.
+IF COL_LENGTH('dbo.tb_users', 'CompanyId') IS NULL
+    ALTER TABLE dbo.tb_users ADD CompanyId bigint NULL;
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0003')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0003', 'users_company');
+GO
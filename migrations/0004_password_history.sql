@@ -0,0 +1,17 @@
+-- Hashes das senhas anteriores, para impedir a reutilização (PASSWORD_HISTORY)
+IF OBJECT_ID('dbo.PasswordHistory', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.PasswordHistory (
+        Id           int IDENTITY(1,1) NOT NULL CONSTRAINT PK_PasswordHistory PRIMARY KEY,
+        UserId       int           NOT NULL,
+        PasswordHash nvarchar(500) NOT NULL,
+        CreatedAt    datetime2     NOT NULL CONSTRAINT DF_PasswordHistory_CreatedAt DEFAULT GETUTCDATE()
+    );
+
+    CREATE INDEX IX_PasswordHistory_UserId ON dbo.PasswordHistory (UserId, CreatedAt DESC);
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0004')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0004', 'password_history');
+GO
@@ -0,0 +1,17 @@
+-- Segredo TOTP e hashes dos códigos de recuperação do 2FA (/auth/2fa)
+IF OBJECT_ID('dbo.UserTwoFactor', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.UserTwoFactor (
+        UserId        int           NOT NULL CONSTRAINT PK_UserTwoFactor PRIMARY KEY,
+        Secret        nvarchar(100) NOT NULL,
+        Enabled       bit           NOT NULL CONSTRAINT DF_UserTwoFactor_Enabled DEFAULT 0,
+        RecoveryCodes nvarchar(max) NULL,
+        CreatedAt     datetime2     NOT NULL CONSTRAINT DF_UserTwoFactor_CreatedAt DEFAULT GETUTCDATE(),
+        EnabledAt     datetime2     NULL
+    );
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0005')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0005', 'user_two_factor');
+GO
@@ -0,0 +1,19 @@
+-- Buscas de tickets salvas pelos usuários (/users/me/saved-searches); o nome é único por usuário
+IF OBJECT_ID('dbo.SavedSearches', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.SavedSearches (
+        Id        int IDENTITY(1,1) NOT NULL CONSTRAINT PK_SavedSearches PRIMARY KEY,
+        UserId    int            NOT NULL,
+        Name      nvarchar(100)  NOT NULL,
+        Query     nvarchar(1000) NOT NULL,
+        Layout    nvarchar(max)  NULL,
+        CreatedAt datetime2      NOT NULL CONSTRAINT DF_SavedSearches_CreatedAt DEFAULT GETUTCDATE(),
+        UpdatedAt datetime2      NULL,
+        CONSTRAINT UQ_SavedSearches_UserId_Name UNIQUE (UserId, Name)
+    );
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0006')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0006', 'saved_searches');
+GO
@@ -0,0 +1,23 @@
+-- Tarefas enviadas à fila de processamento assíncrono (stream jobs:queue do Redis) e os seus resultados
+IF OBJECT_ID('dbo.QueuedJobs', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.QueuedJobs (
+        Id           nvarchar(36)  NOT NULL CONSTRAINT PK_QueuedJobs PRIMARY KEY,
+        Type         nvarchar(100) NOT NULL,
+        Payload      nvarchar(max) NULL,
+        Status       nvarchar(20)  NOT NULL,
+        Attempts     int           NOT NULL CONSTRAINT DF_QueuedJobs_Attempts DEFAULT 0,
+        MaxAttempts  int           NOT NULL,
+        Result       nvarchar(max) NULL,
+        ErrorMessage nvarchar(500) NULL,
+        RequestedBy  int           NULL,
+        CreatedAt    datetime2     NOT NULL,
+        StartedAt    datetime2     NULL,
+        FinishedAt   datetime2     NULL
+    );
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0007')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0007', 'queued_jobs');
+GO
@@ -0,0 +1,21 @@
+-- Dias em que a quantidade de tickets abertos fugiu da linha de base (/alerts); no máximo um alerta por dia
+IF OBJECT_ID('dbo.TicketVolumeAlerts', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.TicketVolumeAlerts (
+        Id             int IDENTITY(1,1) NOT NULL CONSTRAINT PK_TicketVolumeAlerts PRIMARY KEY,
+        Day            date         NOT NULL CONSTRAINT UQ_TicketVolumeAlerts_Day UNIQUE,
+        Tickets        bigint       NOT NULL,
+        BaselineMean   float        NOT NULL,
+        BaselineStdDev float        NOT NULL,
+        BaselineDays   int          NOT NULL,
+        ZScore         float        NOT NULL,
+        Severity       nvarchar(20) NOT NULL,
+        CreatedAt      datetime2    NOT NULL,
+        UpdatedAt      datetime2    NULL
+    );
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0008')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0008', 'ticket_volume_alerts');
+GO
@@ -0,0 +1,18 @@
+-- Grafias de tags apontadas para o nome canônico em dbo.Dim_Tags (/admin/tags)
+IF OBJECT_ID('dbo.TagAliases', 'U') IS NULL
+BEGIN
+    CREATE TABLE dbo.TagAliases (
+        Id        int IDENTITY(1,1) NOT NULL CONSTRAINT PK_TagAliases PRIMARY KEY,
+        Alias     nvarchar(60) NOT NULL CONSTRAINT UQ_TagAliases_Alias UNIQUE,
+        TagName   nvarchar(60) NOT NULL,
+        CreatedAt datetime2    NOT NULL CONSTRAINT DF_TagAliases_CreatedAt DEFAULT GETUTCDATE(),
+        CreatedBy int          NULL
+    );
+
+    CREATE INDEX IX_TagAliases_TagName ON dbo.TagAliases (TagName);
+END
+GO
+
+IF NOT EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0009')
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0009', 'tag_aliases');
+GO
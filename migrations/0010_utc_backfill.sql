@@ -0,0 +1,49 @@
+-- Converte para UTC as datas gravadas no horário local antes de a API passar a gravar em UTC: as de
+-- dbo.tb_users e dbo.UserAuthLogs, as únicas tabelas que já existiam. As tabelas criadas pelos scripts
+-- anteriores só recebem datas em UTC.
+--
+-- Antes de executar, preencha:
+--   @SourceTimeZone: fuso (nome do Windows, ver sys.time_zone_info) em que as datas antigas foram
+--                    gravadas, ex.: 'E. South America Standard Time'
+--   @CutoverUtc:     instante (UTC) em que a versão que grava em UTC entrou no ar; só as datas anteriores
+--                    são convertidas
+-- As colunas são comparadas com @CutoverUtc convertido para o horário local. As datas da própria hora do
+-- corte são ambíguas, pois não há como saber se foram gravadas no horário local ou em UTC; confira-as.
+-- Sem os dois valores o script não altera nada e não é registrado, para poder ser executado depois.
+-- Ao contrário dos demais, não pode ser repetido: a versão registrada impede uma segunda conversão.
+DECLARE @SourceTimeZone sysname = NULL;
+DECLARE @CutoverUtc datetime2 = NULL;
+
+IF EXISTS (SELECT 1 FROM dbo.SchemaMigrations WHERE Version = '0010')
+    PRINT 'Migration 0010 already applied';
+ELSE IF @SourceTimeZone IS NULL OR @CutoverUtc IS NULL
+    PRINT 'Migration 0010 skipped: set @SourceTimeZone and @CutoverUtc to convert the local-time rows';
+ELSE
+BEGIN
+    -- O corte no horário local é comparado com as colunas como foram gravadas
+    DECLARE @CutoverLocal datetime2 = CONVERT(datetime2, @CutoverUtc AT TIME ZONE 'UTC' AT TIME ZONE @SourceTimeZone);
+
+    SET XACT_ABORT ON;
+    BEGIN TRANSACTION;
+
+    UPDATE dbo.tb_users
+    SET CreatedAt = CONVERT(datetime2, CreatedAt AT TIME ZONE @SourceTimeZone AT TIME ZONE 'UTC')
+    WHERE CreatedAt < @CutoverLocal;
+
+    UPDATE dbo.tb_users
+    SET UpdatedAt = CONVERT(datetime2, UpdatedAt AT TIME ZONE @SourceTimeZone AT TIME ZONE 'UTC')
+    WHERE UpdatedAt < @CutoverLocal;
+
+    UPDATE dbo.tb_users
+    SET LastLoginAt = CONVERT(datetime2, LastLoginAt AT TIME ZONE @SourceTimeZone AT TIME ZONE 'UTC')
+    WHERE LastLoginAt < @CutoverLocal;
+
+    UPDATE dbo.UserAuthLogs
+    SET CreatedAt = CONVERT(datetime2, CreatedAt AT TIME ZONE @SourceTimeZone AT TIME ZONE 'UTC')
+    WHERE CreatedAt < @CutoverLocal;
+
+    INSERT INTO dbo.SchemaMigrations (Version, Name) VALUES ('0010', 'utc_backfill');
+
+    COMMIT TRANSACTION;
+END
+GO
@@ -6,12 +6,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v9"
@@ -29,6 +31,59 @@ const (
 	LevelFatal LogLevel = "FATAL"
 )
 
+// levelSeverity orders the levels from the most to the least verbose
+var levelSeverity = map[LogLevel]int{
+	LevelDebug: 0,
+	LevelInfo:  1,
+	LevelWarn:  2,
+	LevelError: 3,
+	LevelFatal: 4,
+}
+
+// ParseLevel converts a level name (case-insensitive, WARNING accepted for WARN) into a LogLevel
+func ParseLevel(name string) (LogLevel, error) {
+	level := LogLevel(strings.ToUpper(strings.TrimSpace(name)))
+	if level == "WARNING" {
+		level = LevelWarn
+	}
+	if _, ok := levelSeverity[level]; !ok {
+		return "", fmt.Errorf("unknown log level %q (expected DEBUG, INFO, WARN, ERROR or FATAL)", name)
+	}
+	return level, nil
+}
+
+// OverflowStrategy decides what happens to a new entry when the log channel is full
+type OverflowStrategy string
+
+const (
+	// OverflowDrop discards the new entry (default)
+	OverflowDrop OverflowStrategy = "drop"
+	// OverflowBlock waits up to OverflowTimeout for room in the channel, then discards the new entry
+	OverflowBlock OverflowStrategy = "block"
+	// OverflowDropOldest discards the oldest queued entry to make room for the new one
+	OverflowDropOldest OverflowStrategy = "drop_oldest"
+	// OverflowSample keeps one in SampleRate DEBUG/INFO entries while the channel is at least 3/4
+	// full; WARN and above are always queued while there is room
+	OverflowSample OverflowStrategy = "sample"
+)
+
+// ParseOverflowStrategy converts a strategy name (case-insensitive) into an OverflowStrategy
+func ParseOverflowStrategy(name string) (OverflowStrategy, error) {
+	strategy := OverflowStrategy(strings.ToLower(strings.TrimSpace(name)))
+	switch strategy {
+	case OverflowDrop, OverflowBlock, OverflowDropOldest, OverflowSample:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown overflow strategy %q (expected drop, block, drop_oldest or sample)", name)
+}
+
+const (
+	// maxRetryBackoff caps the exponential backoff between retries of a failed batch
+	maxRetryBackoff = 30 * time.Second
+	// closeFlushTimeout bounds the final flush done by Close
+	closeFlushTimeout = 5 * time.Second
+)
+
 // LogEntry represents the complete structure of a log record in Elasticsearch
 type LogEntry struct {
 	// Core fields
@@ -137,22 +192,32 @@ type Config struct {
 	Version         string        // Application version
 	Environment     string        // Environment (dev, staging, prod)
 	IndexName       string        // Elasticsearch index name
+	DailyIndex      bool          // Whether to append the date (YYYY.MM.DD) to the index name
 	FlushInterval   time.Duration // How often to flush logs to Elasticsearch
 	BatchSize       int           // Maximum number of logs to batch
 	BufferSize      int           // Channel buffer size
-	LogLevel        LogLevel      // Minimum log level to process
+	LogLevel        LogLevel      // Minimum log level to process (can be changed at runtime with SetLevel)
 	EnableCaller    bool          // Whether to capture caller information
 	EnableBody      bool          // Whether to log request/response bodies
 	MaxBodySize     int           // Maximum body size to log
 	SensitiveFields []string      // Fields to redact in logs
 	ExecutionID     string        // Unique ID for each request
+
+	Overflow        OverflowStrategy // What to do when the channel is full (default drop)
+	OverflowTimeout time.Duration    // How long OverflowBlock waits for room (default 100ms)
+	SampleRate      int              // One in SampleRate entries kept by OverflowSample (default 10)
+	MaxRetries      int              // Retries of a failed bulk request (default 3, negative disables)
+	RetryBackoff    time.Duration    // Wait before the first retry, doubled on each one (default 500ms)
 }
 
 // ElasticsearchLogger is the main logger instance
 type ElasticsearchLogger struct {
 	config      Config
 	es          *elasticsearch.Client
+	level       atomic.Value // current minimum LogLevel
 	logChannel  chan LogEntry
+	sampled     atomic.Uint64 // entries seen by OverflowSample under pressure
+	closed      atomic.Bool
 	wg          sync.WaitGroup
 	ctx         context.Context
 	cancel      context.CancelFunc
@@ -186,6 +251,22 @@ func NewLogger(es *elasticsearch.Client, config Config) *ElasticsearchLogger {
 		config.MaxBodySize = 1024 // 1KB default
 	}
 
+	if config.Overflow == "" {
+		config.Overflow = OverflowDrop
+	}
+	if config.OverflowTimeout == 0 {
+		config.OverflowTimeout = 100 * time.Millisecond
+	}
+	if config.SampleRate <= 0 {
+		config.SampleRate = 10
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = 500 * time.Millisecond
+	}
+
 	hostname, _ := os.Hostname()
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -199,6 +280,8 @@ func NewLogger(es *elasticsearch.Client, config Config) *ElasticsearchLogger {
 		pid:        os.Getpid(),
 	}
 
+	logger.level.Store(config.LogLevel)
+
 	// Start background goroutine for processing logs
 	logger.wg.Add(1)
 	go logger.processLogs()
@@ -215,14 +298,15 @@ func (l *ElasticsearchLogger) processLogs() {
 
 	batch := make([]LogEntry, 0, l.config.BatchSize)
 
-	flush := func() {
+	flush := func(ctx context.Context) {
 		if len(batch) == 0 {
 			return
 		}
 
-		if err := l.sendBatch(batch); err != nil {
-			// Fallback to stdout if Elasticsearch fails
-			fmt.Fprintf(os.Stderr, "Failed to send logs to Elasticsearch: %v\n", err)
+		if err := l.sendWithRetry(ctx, batch); err != nil {
+			// Fallback to stderr if Elasticsearch fails
+			fmt.Fprintf(os.Stderr, "Failed to send %d logs to Elasticsearch: %v\n", len(batch), err)
+			droppedEntries.WithLabelValues(DropSendFailed).Add(float64(len(batch)))
 		}
 		batch = batch[:0] // Reset batch
 	}
@@ -233,20 +317,70 @@ func (l *ElasticsearchLogger) processLogs() {
 			batch = append(batch, entry)
 
 			if len(batch) >= l.config.BatchSize {
-				flush()
+				flush(l.ctx)
 			}
 
 		case <-ticker.C:
-			flush()
+			flush(l.ctx)
 		case <-l.ctx.Done():
-			flush() // Final flush
+			// Final flush of what is still queued, with its own deadline since l.ctx is done
+			for drained := false; !drained; {
+				select {
+				case entry := <-l.logChannel:
+					batch = append(batch, entry)
+				default:
+					drained = true
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), closeFlushTimeout)
+			flush(ctx)
+			cancel()
 			return
 		}
 	}
 }
 
+// permanentError marks a batch that will fail again if retried (encoding or 4xx errors)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// sendWithRetry sends the batch, retrying connection errors, 429 and 5xx responses with
+// exponential backoff. While it waits, new entries pile up in the channel and the overflow
+// strategy applies.
+func (l *ElasticsearchLogger) sendWithRetry(ctx context.Context, entries []LogEntry) error {
+	backoff := l.config.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := l.sendBatch(ctx, entries)
+
+		var permanent *permanentError
+		if err == nil || errors.As(err, &permanent) || attempt >= l.config.MaxRetries {
+			return err
+		}
+
+		batchRetries.Inc()
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
 // sendBatch sends a batch of log entries to Elasticsearch
-func (l *ElasticsearchLogger) sendBatch(entries []LogEntry) error {
+func (l *ElasticsearchLogger) sendBatch(ctx context.Context, entries []LogEntry) error {
 	if len(entries) == 0 {
 		return nil
 	}
@@ -257,25 +391,25 @@ func (l *ElasticsearchLogger) sendBatch(entries []LogEntry) error {
 		// Create index action
 		indexAction := map[string]interface{}{
 			"index": map[string]interface{}{
-				"_index": l.getIndexName(),
+				"_index": l.getIndexName(entry.Timestamp),
 				"_id":    entry.ID,
 			},
 		}
 
 		if err := json.NewEncoder(&buf).Encode(indexAction); err != nil {
-			return fmt.Errorf("failed to encode index action: %w", err)
+			return &permanentError{fmt.Errorf("failed to encode index action: %w", err)}
 		}
 
 		// Add document
 		if err := json.NewEncoder(&buf).Encode(entry); err != nil {
-			return fmt.Errorf("failed to encode log entry: %w", err)
+			return &permanentError{fmt.Errorf("failed to encode log entry: %w", err)}
 		}
 	}
 
 	// Send bulk request
 	res, err := l.es.Bulk(
 		strings.NewReader(buf.String()),
-		l.es.Bulk.WithContext(l.ctx),
+		l.es.Bulk.WithContext(ctx),
 		l.es.Bulk.WithRefresh("false"),
 	)
 	if err != nil {
@@ -285,28 +419,52 @@ func (l *ElasticsearchLogger) sendBatch(entries []LogEntry) error {
 
 	if res.IsError() {
 		body, _ := io.ReadAll(res.Body)
-		return fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+		err := fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+		if res.StatusCode != 429 && res.StatusCode < 500 {
+			return &permanentError{err}
+		}
+		return err
 	}
 
 	return nil
 }
 
-// getIndexName generates index name with date suffix for daily rotation
-func (l *ElasticsearchLogger) getIndexName() string {
-	return fmt.Sprint(l.config.IndexName)
+// getIndexName generates index name with date suffix for daily rotation, based on the
+// entry timestamp so that entries flushed after midnight land in the day they were logged
+func (l *ElasticsearchLogger) getIndexName(timestamp time.Time) string {
+	if !l.config.DailyIndex {
+		return l.config.IndexName
+	}
+	return l.config.IndexName + "-" + timestamp.UTC().Format("2006.01.02")
 }
 
 // shouldLog checks if the log level should be processed
 func (l *ElasticsearchLogger) shouldLog(level LogLevel) bool {
-	levels := map[LogLevel]int{
-		LevelDebug: 0,
-		LevelInfo:  1,
-		LevelWarn:  2,
-		LevelError: 3,
-		LevelFatal: 4,
-	}
+	return levelSeverity[level] >= levelSeverity[l.Level()]
+}
+
+// BodyLogging reports whether request/response bodies are logged and up to which size
+func (l *ElasticsearchLogger) BodyLogging() (bool, int) {
+	return l.config.EnableBody, l.config.MaxBodySize
+}
+
+// Level returns the current minimum log level
+func (l *ElasticsearchLogger) Level() LogLevel {
+	return l.level.Load().(LogLevel)
+}
+
+// ConfiguredLevel returns the minimum log level the logger was created with
+func (l *ElasticsearchLogger) ConfiguredLevel() LogLevel {
+	return l.config.LogLevel
+}
 
-	return levels[level] >= levels[l.config.LogLevel]
+// SetLevel changes the minimum log level at runtime and returns the previous one. It affects
+// only this process.
+func (l *ElasticsearchLogger) SetLevel(level LogLevel) (LogLevel, error) {
+	if _, ok := levelSeverity[level]; !ok {
+		return "", fmt.Errorf("unknown log level %q", level)
+	}
+	return l.level.Swap(level).(LogLevel), nil
 }
 
 // createLogEntry creates a base log entry with common fields
@@ -345,12 +503,59 @@ func (l *ElasticsearchLogger) log(entry LogEntry) {
 		return
 	}
 
+	if l.closed.Load() {
+		droppedEntries.WithLabelValues(DropClosed).Inc()
+		return
+	}
+
+	if l.config.Overflow == OverflowSample && l.underPressure() && levelSeverity[entry.Level] < levelSeverity[LevelWarn] {
+		if l.sampled.Add(1)%uint64(l.config.SampleRate) != 0 {
+			droppedEntries.WithLabelValues(DropSampled).Inc()
+			return
+		}
+	}
+
 	select {
 	case l.logChannel <- entry:
+		return
 	default:
-		// Channel is full, log to stderr as fallback
-		fmt.Fprintf(os.Stderr, "Logger channel full, dropping log: %s\n", entry.Message)
 	}
+
+	switch l.config.Overflow {
+	case OverflowBlock:
+		timer := time.NewTimer(l.config.OverflowTimeout)
+		defer timer.Stop()
+
+		select {
+		case l.logChannel <- entry:
+			return
+		case <-timer.C:
+		case <-l.ctx.Done():
+		}
+
+	case OverflowDropOldest:
+		// The consumer may take the oldest entry first; either way there is room for one more
+		select {
+		case <-l.logChannel:
+			droppedEntries.WithLabelValues(DropOverwritten).Inc()
+		default:
+		}
+
+		select {
+		case l.logChannel <- entry:
+			return
+		default:
+		}
+	}
+
+	// Channel is full, log to stderr as fallback
+	droppedEntries.WithLabelValues(DropBufferFull).Inc()
+	fmt.Fprintf(os.Stderr, "Logger channel full, dropping log: %s\n", entry.Message)
+}
+
+// underPressure reports whether the channel is at least 3/4 full
+func (l *ElasticsearchLogger) underPressure() bool {
+	return len(l.logChannel)*4 >= cap(l.logChannel)*3
 }
 
 // Debug logs a debug message
@@ -439,9 +644,12 @@ type LogContext struct {
 
 // Close gracefully shuts down the logger
 func (l *ElasticsearchLogger) Close() error {
+	// The channel is left open: entries logged after Close are counted as dropped instead of panicking
+	if l.closed.Swap(true) {
+		return nil
+	}
 	l.cancel()
 	l.wg.Wait()
-	close(l.logChannel)
 
 	return nil
 }
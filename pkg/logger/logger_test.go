@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newQueueLogger creates a logger without the processing goroutine, so the channel only fills up
+func newQueueLogger(t *testing.T, config Config, capacity int) *ElasticsearchLogger {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	l := &ElasticsearchLogger{
+		config:     config,
+		logChannel: make(chan LogEntry, capacity),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	l.level.Store(LevelDebug)
+	return l
+}
+
+// queued returns the messages waiting in the channel, oldest first
+func queued(l *ElasticsearchLogger) []string {
+	var messages []string
+	for len(l.logChannel) > 0 {
+		messages = append(messages, (<-l.logChannel).Message)
+	}
+	return messages
+}
+
+func TestLogOverflow(t *testing.T) {
+	type logged struct {
+		level   LogLevel
+		message string
+	}
+	info := func(message string) logged { return logged{LevelInfo, message} }
+
+	tests := []struct {
+		name     string
+		config   Config
+		capacity int
+		entries  []logged
+		queued   []string
+		dropped  map[string]float64
+	}{
+		{
+			name:     "drop discards the new entry",
+			config:   Config{Overflow: OverflowDrop},
+			capacity: 2,
+			entries:  []logged{info("a"), info("b"), info("c")},
+			queued:   []string{"a", "b"},
+			dropped:  map[string]float64{DropBufferFull: 1},
+		},
+		{
+			name:     "block discards the new entry after the timeout",
+			config:   Config{Overflow: OverflowBlock, OverflowTimeout: 10 * time.Millisecond},
+			capacity: 2,
+			entries:  []logged{info("a"), info("b"), info("c")},
+			queued:   []string{"a", "b"},
+			dropped:  map[string]float64{DropBufferFull: 1},
+		},
+		{
+			name:     "drop_oldest makes room for the new entry",
+			config:   Config{Overflow: OverflowDropOldest},
+			capacity: 2,
+			entries:  []logged{info("a"), info("b"), info("c")},
+			queued:   []string{"b", "c"},
+			dropped:  map[string]float64{DropOverwritten: 1},
+		},
+		{
+			name:     "sample keeps one in SampleRate entries under pressure, and warnings while there is room",
+			config:   Config{Overflow: OverflowSample, SampleRate: 2},
+			capacity: 5,
+			entries:  []logged{info("a"), info("b"), info("c"), info("d"), info("e"), {LevelWarn, "f"}, {LevelError, "g"}},
+			queued:   []string{"a", "b", "c", "d", "f"},
+			dropped:  map[string]float64{DropSampled: 1, DropBufferFull: 1},
+		},
+	}
+
+	reasons := []string{DropBufferFull, DropOverwritten, DropSampled}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newQueueLogger(t, tt.config, tt.capacity)
+
+			before := make(map[string]float64, len(reasons))
+			for _, reason := range reasons {
+				before[reason] = testutil.ToFloat64(droppedEntries.WithLabelValues(reason))
+			}
+
+			for _, entry := range tt.entries {
+				l.log(LogEntry{Level: entry.level, Message: entry.message})
+			}
+
+			assert.Equal(t, tt.queued, queued(l))
+			for _, reason := range reasons {
+				assert.Equal(t, tt.dropped[reason], testutil.ToFloat64(droppedEntries.WithLabelValues(reason))-before[reason], reason)
+			}
+		})
+	}
+}
+
+func TestLogOverflow_BlockWaitsForRoom(t *testing.T) {
+	l := newQueueLogger(t, Config{Overflow: OverflowBlock, OverflowTimeout: time.Second}, 1)
+	l.log(LogEntry{Level: LevelInfo, Message: "a"})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-l.logChannel
+	}()
+
+	l.log(LogEntry{Level: LevelInfo, Message: "b"})
+	assert.Equal(t, []string{"b"}, queued(l))
+}
+
+func TestSendWithRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		maxRetries int
+		expectErr  bool
+		requests   int32
+		retries    float64
+	}{
+		{name: "success", statuses: []int{200}, maxRetries: 3, requests: 1},
+		{name: "retries a server error", statuses: []int{503, 200}, maxRetries: 3, requests: 2, retries: 1},
+		{name: "retries a rate limited request", statuses: []int{429, 429, 200}, maxRetries: 3, requests: 3, retries: 2},
+		{name: "gives up after the retries", statuses: []int{500}, maxRetries: 2, expectErr: true, requests: 3, retries: 2},
+		{name: "does not retry a client error", statuses: []int{400}, maxRetries: 3, expectErr: true, requests: 1},
+		{name: "retries disabled", statuses: []int{503}, maxRetries: -1, expectErr: true, requests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := int(requests.Add(1)) - 1
+				status := tt.statuses[min(attempt, len(tt.statuses)-1)]
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Elastic-Product", "Elasticsearch")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"errors": false, "items": []}`))
+			}))
+			t.Cleanup(server.Close)
+
+			es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}, DisableRetry: true})
+			require.NoError(t, err)
+
+			l := newQueueLogger(t, Config{IndexName: "logs", MaxRetries: tt.maxRetries, RetryBackoff: time.Millisecond}, 1)
+			l.es = es
+
+			retriesBefore := testutil.ToFloat64(batchRetries)
+			err = l.sendWithRetry(context.Background(), []LogEntry{{ID: "1", Level: LevelInfo, Message: "a"}})
+
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.requests, requests.Load())
+			assert.Equal(t, tt.retries, testutil.ToFloat64(batchRetries)-retriesBefore)
+		})
+	}
+}
+
+func TestLogAfterClose(t *testing.T) {
+	l := newQueueLogger(t, Config{}, 1)
+	l.closed.Store(true)
+
+	before := testutil.ToFloat64(droppedEntries.WithLabelValues(DropClosed))
+	assert.NotPanics(t, func() { l.log(LogEntry{Level: LevelInfo, Message: "late"}) })
+	assert.Empty(t, queued(l))
+	assert.Equal(t, 1.0, testutil.ToFloat64(droppedEntries.WithLabelValues(DropClosed))-before)
+}
@@ -0,0 +1,29 @@
+package logger
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Reasons for dropping a log entry, used as the reason label of logger_dropped_entries_total
+const (
+	DropBufferFull  = "buffer_full" // the channel was full (drop, or block after the timeout)
+	DropOverwritten = "overwritten" // an older entry was discarded to make room (drop_oldest)
+	DropSampled     = "sampled"     // left out by sampling while the channel was under pressure
+	DropSendFailed  = "send_failed" // the batch could not be sent after all retries
+	DropClosed      = "closed"      // logged after Close
+)
+
+var (
+	droppedEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logger_dropped_entries_total",
+		Help: "Log entries that never reached Elasticsearch, by reason.",
+	}, []string{"reason"})
+
+	batchRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "logger_batch_retries_total",
+		Help: "Retries of failed Elasticsearch bulk requests of the logger.",
+	})
+)
+
+// Collectors returns the logger metrics, to be registered on the Prometheus endpoint
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{droppedEntries, batchRetries}
+}